@@ -0,0 +1,40 @@
+// Package analyzer reports errors in annotations as a golang.org/x/tools/go/analysis Analyzer, so that go vet and
+// editors through gopls show them while editing instead of at generation time. It is a module of its own, so that
+// golangAnnotations itself does not depend on golang.org/x/tools.
+package analyzer
+
+import (
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/builtin"
+	"github.com/MarcGrol/golangAnnotations/lint"
+	"golang.org/x/tools/go/analysis"
+)
+
+// Analyzer checks the annotations of the built-in generators
+var Analyzer = New(builtin.NewRegistry())
+
+// New returns an analyzer for the annotations of the generators in registry, which reports what lint.CheckFiles finds
+// with its fixes as suggested fixes
+func New(registry *generator.Registry) *analysis.Analyzer {
+	return &analysis.Analyzer{
+		Name: "annotations",
+		Doc:  "report unknown, malformed and misplaced golangAnnotations-annotations and the errors generators find in them",
+		Run: func(pass *analysis.Pass) (interface{}, error) {
+			for _, finding := range lint.CheckFiles(pass.Fset, pass.Files, registry) {
+				pass.Report(diagnostic(finding))
+			}
+			return nil, nil
+		},
+	}
+}
+
+func diagnostic(finding lint.Finding) analysis.Diagnostic {
+	d := analysis.Diagnostic{Pos: finding.Pos, End: finding.End, Message: finding.Message}
+	for _, fix := range finding.Fixes {
+		d.SuggestedFixes = append(d.SuggestedFixes, analysis.SuggestedFix{
+			Message:   fix.Title,
+			TextEdits: []analysis.TextEdit{{Pos: fix.Pos, End: fix.End, NewText: []byte(fix.NewText)}},
+		})
+	}
+	return d
+}
@@ -0,0 +1,18 @@
+package tour
+
+// @RestService( path = "/api" )
+type TourService struct{}
+
+// @RestOperaton( method = "POST", path = "/tour" ) // want "Unknown annotation @RestOperaton"
+func (ts *TourService) createTour() error {
+	return nil
+}
+
+// @RestOperation( path = "/tour/{uid}" ) // want "Annotation @RestOperation is malformed or has invalid attributes"
+func (ts *TourService) getTour(uid string) (*Tour, error) {
+	return nil, nil
+}
+
+// @JsonStruct() // want "Annotation @JsonStruct is not in the doc-comment of a declaration, so no generator sees it"
+
+type Tour struct{}
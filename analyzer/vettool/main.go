@@ -0,0 +1,14 @@
+// Command vettool checks the annotations of packages, standalone or through go vet:
+//
+//	$ vettool ./...
+//	$ go vet -vettool=$(which vettool) ./...
+package main
+
+import (
+	"github.com/MarcGrol/golangAnnotations/analyzer"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(analyzer.Analyzer)
+}
@@ -0,0 +1,350 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/lint"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+// Subcommands of the cli
+const (
+	parseCommand    = "parse"
+	listCommand     = "list"
+	graphCommand    = "graph"
+	topologyCommand = "topology"
+	compatCommand   = "compat"
+	serveCommand    = "serve"
+	lspCommand      = "lsp"
+	generateCommand = "generate"
+	lintCommand     = "lint"
+	diffCommand     = "diff"
+	statsCommand    = "stats"
+	cleanCommand    = "clean"
+	verifyCommand   = "verify"
+	newCommand      = "new"
+	helpCommand     = "help"
+	versionCommand  = "version"
+)
+
+// command is a subcommand of the cli: all subcommands share the same flags
+type command struct {
+	name    string
+	summary string
+	// description is shown by help <command>, below the summary
+	description string
+	run         func()
+}
+
+func allCommands() []command {
+	return []command{
+		{
+			name:        parseCommand,
+			summary:     "parse the sources and print the model that the generators work on as json or yaml",
+			description: "Only the parser runs: use -model-format, -model-output and -filter to select what is written where.",
+			run:         runParse,
+		},
+		{
+			name:        listCommand,
+			summary:     "list the annotated services, operations, events, aggregates and enums",
+			description: "Use -list-format json for tooling.",
+			run:         runList,
+		},
+		{
+			name:        graphCommand,
+			summary:     "print which services publish and handle which events and which aggregates these apply to",
+			description: "Writes a Graphviz dot-graph, or a Mermaid flowchart with -graph-format mermaid, to stdout.",
+			run:         runGraph,
+		},
+		{
+			name:        topologyCommand,
+			summary:     "print the topics and subscriptions that the events and event-services need, as terraform or json",
+			description: "Writes google_pubsub-resources, or kafka_topic-resources with -topology-provider kafka, to stdout: -topology-format json for other tooling.",
+			run:         runTopology,
+		},
+		{
+			name:        compatCommand,
+			summary:     "classify the changes of the rest-operations and events since the model of -compat-baseline as breaking or compatible",
+			description: "Exits with status 1 when there are breaking changes without a next version: a version of the @RestService or the @Event.",
+			run:         runCompat,
+		},
+		{
+			name:        serveCommand,
+			summary:     "serve the model, the annotated elements, the report and the generated files over http, until interrupted",
+			description: "Open http://<listen> in a browser: the page reloads when the sources change. Nothing is written to disk.",
+			run:         runServe,
+		},
+		{
+			name:        lspCommand,
+			summary:     "serve the Language Server Protocol on stdio: errors in annotations as diagnostics, with fixes as code actions",
+			description: "Configure it in the editor as an extra language server for go-files, next to gopls.",
+			run:         runLSP,
+		},
+		{
+			name:        generateCommand,
+			summary:     "generate the code for the annotated sources (the default)",
+			description: "Only the generated files that changed are written.",
+			run:         runGenerate,
+		},
+		{
+			name:        lintCommand,
+			summary:     "report errors in annotations, unknown or malformed annotations and annotated methods of unknown receivers, without writing any file",
+			description: "Exits with status 1 when there are errors: unknown and malformed annotations and annotated methods of receivers that are no struct of the package are only warnings.",
+			run:         runLint,
+		},
+		{
+			name:        diffCommand,
+			summary:     "print unified diffs of the changes that generation would make, without writing any file",
+			description: "Same as generate -dry-run.",
+			run:         runGenerate,
+		},
+		{
+			name:        statsCommand,
+			summary:     "print what was parsed and what every generator renders, without writing any file",
+			description: "Lists the parsed declarations, the annotations in use and per generator the files, lines and time it takes.",
+			run:         runStats,
+		},
+		{
+			name:        cleanCommand,
+			summary:     "remove the generated files that are no longer generated",
+			description: "The files that are still generated are not written.",
+			run:         runGenerate,
+		},
+		{
+			name:        verifyCommand,
+			summary:     "fail when generated files on disk differ from a fresh generation, without writing any file",
+			description: "Same as generate -verify: exits with status 1 when files are not up to date.",
+			run:         runGenerate,
+		},
+		{
+			name:        newCommand,
+			summary:     "create an annotated skeleton to start from: new rest-service <Name> or new aggregate <Name>",
+			description: "The file is created in the input-dir, or in the working dir without -input-dir, and is ready for generate.",
+			run:         runNew,
+		},
+		{
+			name:    helpCommand,
+			summary: "show the usage of the cli or of a command: help <command>",
+			run:     runHelp,
+		},
+		{
+			name:    versionCommand,
+			summary: "print the version of golangAnnotations",
+			run:     printVersion,
+		},
+	}
+}
+
+func lookupCommand(name string) (command, bool) {
+	for _, cmd := range allCommands() {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintf(w, "\nUsage:\n")
+	fmt.Fprintf(w, "  golangAnnotations [command] [flags]\n\n")
+	fmt.Fprintf(w, "Commands:\n")
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	for _, cmd := range allCommands() {
+		fmt.Fprintf(tw, "  %s\t%s\n", cmd.name, cmd.summary)
+	}
+	tw.Flush()
+	fmt.Fprintf(w, "\nFlags:\n")
+	printFlags(w)
+	fmt.Fprintf(w, "\nRun 'golangAnnotations help <command>' for more about a command.\n\n")
+}
+
+func printCommandUsage(w io.Writer, cmd command) {
+	fmt.Fprintf(w, "\nUsage:\n")
+	fmt.Fprintf(w, "  golangAnnotations %s [flags]\n\n", cmd.name)
+	fmt.Fprintf(w, "%s.", strings.ToUpper(cmd.summary[:1])+cmd.summary[1:])
+	if cmd.description != "" {
+		fmt.Fprintf(w, " %s", cmd.description)
+	}
+	fmt.Fprintf(w, "\n\nFlags:\n")
+	printFlags(w)
+	fmt.Fprintf(w, "\n")
+}
+
+func printFlags(w io.Writer) {
+	flag.CommandLine.SetOutput(w)
+	flag.PrintDefaults()
+	flag.CommandLine.SetOutput(os.Stderr)
+}
+
+func runHelp() {
+	if len(commandArgs) == 0 {
+		printUsage(os.Stdout)
+		return
+	}
+	cmd, found := lookupCommand(commandArgs[0])
+	if !found {
+		fail(exitUsage, "Unknown command %s: run 'golangAnnotations help' for the commands", commandArgs[0])
+	}
+	printCommandUsage(os.Stdout, cmd)
+}
+
+// interruptibleContext stops parsing and generating on ctrl-c, before anything is written
+func interruptibleContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt)
+}
+
+// runGenerate writes, diffs, verifies or cleans the generated files, depending on the command
+func runGenerate() {
+	inputDirs, registry := prepare()
+
+	if *dumpTemplates != "" {
+		err := generator.Write(generator.DefaultTemplateFiles(*dumpTemplates, registry))
+		if err != nil {
+			fail(exitFailure, "Error dumping templates to %s: %s", *dumpTemplates, err)
+		}
+		return
+	}
+
+	c, stop := interruptibleContext()
+	defer stop()
+
+	parsedSources := parseInputDirs(c, inputDirs)
+	generators, tasks, results := renderInputDirs(c, registry, inputDirs, parsedSources)
+
+	summary := &runSummary{}
+	for idx, dir := range inputDirs {
+		from, to := idx*len(generators), (idx+1)*len(generators)
+		processRenderedFiles(dir, tasks[from:to], results[from:to], summary)
+	}
+	summary.report(inputDirs)
+}
+
+// runLint reports unknown annotations and unresolved receivers and renders all generators in memory, which reports the errors in annotations
+func runLint() {
+	inputDirs, registry := prepare()
+
+	c, stop := interruptibleContext()
+	defer stop()
+
+	parsedSources := parseInputDirs(c, inputDirs)
+	warnings := 0
+	for _, parsed := range parsedSources {
+		errs := lint.UnknownAnnotations(parsed, lint.KnownAnnotationNames(registry))
+		errs = append(errs, lint.MalformedAnnotations(parsed, registry)...)
+		for _, err := range append(errs, lint.UnresolvedReceivers(parsed)...) {
+			warnings++
+			logger.Warningf("%s", err)
+			runReport.AddDiagnostic(generator.NewDiagnostic(generator.SeverityWarning, err))
+		}
+	}
+	renderInputDirs(c, registry, inputDirs, parsedSources)
+	logger.Infof("golangAnnotations: no errors in annotations of %d input-dirs, %d warnings", len(inputDirs), warnings)
+}
+
+// runStats prints what was parsed and what the generators render, without writing anything
+func runStats() {
+	inputDirs, registry := prepare()
+
+	c, stop := interruptibleContext()
+	defer stop()
+
+	start := time.Now()
+	parsedSources := parseInputDirs(c, inputDirs)
+	parseDuration := time.Since(start)
+	_, tasks, results := renderInputDirs(c, registry, inputDirs, parsedSources)
+
+	printParseStats(os.Stdout, parsedSources, parseDuration)
+	printGeneratorStats(os.Stdout, tasks, results)
+}
+
+func printParseStats(w io.Writer, parsedSources []model.ParsedSources, duration time.Duration) {
+	var structs, operations, interfaces, typedefs, enums int
+	annotations := map[string]int{}
+	count := func(docLines []string) {
+		for _, name := range lint.AnnotationNames(docLines) {
+			annotations[name]++
+		}
+	}
+	for _, parsed := range parsedSources {
+		structs += len(parsed.Structs)
+		operations += len(parsed.Operations)
+		interfaces += len(parsed.Interfaces)
+		typedefs += len(parsed.Typedefs)
+		enums += len(parsed.Enums)
+		for _, s := range parsed.Structs {
+			count(s.DocLines)
+			for _, f := range s.Fields {
+				count(f.DocLines)
+			}
+		}
+		for _, o := range parsed.Operations {
+			count(o.DocLines)
+		}
+		for _, i := range parsed.Interfaces {
+			count(i.DocLines)
+			for _, m := range i.Methods {
+				count(m.DocLines)
+			}
+		}
+		for _, t := range parsed.Typedefs {
+			count(t.DocLines)
+		}
+		for _, e := range parsed.Enums {
+			count(e.DocLines)
+		}
+	}
+	fmt.Fprintf(w, "Parsed %d input-dirs in %s: %d structs, %d operations, %d interfaces, %d typedefs, %d enums\n\n",
+		len(parsedSources), duration.Round(time.Millisecond), structs, operations, interfaces, typedefs, enums)
+
+	names := []string{}
+	for name := range annotations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "Annotation\tCount\n")
+	for _, name := range names {
+		fmt.Fprintf(tw, "@%s\t%d\n", name, annotations[name])
+	}
+	tw.Flush()
+	fmt.Fprintf(w, "\n")
+}
+
+func printGeneratorStats(w io.Writer, tasks []generator.Task, results []generator.TaskResult) {
+	type generatorStats struct {
+		files    int
+		lines    int
+		duration time.Duration
+	}
+	stats := map[string]*generatorStats{}
+	names := []string{}
+	for idx, result := range results {
+		name := tasks[idx].Generator.Name()
+		if _, found := stats[name]; !found {
+			stats[name] = &generatorStats{}
+			names = append(names, name)
+		}
+		stats[name].files += len(result.Files)
+		stats[name].duration += result.Duration
+		for _, f := range result.Files {
+			stats[name].lines += strings.Count(string(f.Content), "\n")
+		}
+	}
+	sort.Strings(names)
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "Generator\tFiles\tLines\tDuration\n")
+	for _, name := range names {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%s\n", name, stats[name].files, stats[name].lines, stats[name].duration.Round(time.Millisecond))
+	}
+	tw.Flush()
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookupCommand(t *testing.T) {
+	cmd, found := lookupCommand("lint")
+	assert.True(t, found)
+	assert.Equal(t, lintCommand, cmd.name)
+
+	_, found = lookupCommand("unknown")
+	assert.False(t, found)
+}
+
+func TestPrintGeneratorStats(t *testing.T) {
+	var buf bytes.Buffer
+	printGeneratorStats(&buf, []generator.Task{
+		{Generator: statsGenerator{name: "rest"}},
+		{Generator: statsGenerator{name: "event"}},
+		{Generator: statsGenerator{name: "rest"}},
+	}, []generator.TaskResult{
+		{Files: []generator.OutputFile{{Content: []byte("package a\n\nfunc A() {}\n")}}, Duration: time.Millisecond},
+		{},
+		{Files: []generator.OutputFile{{Content: []byte("package b\n")}}, Duration: time.Millisecond},
+	})
+	assert.Equal(t, ""+
+		"Generator  Files  Lines  Duration\n"+
+		"event      0      0      0s\n"+
+		"rest       2      4      2ms\n", buf.String())
+}
+
+type statsGenerator struct {
+	generator.Generator
+	name string
+}
+
+func (g statsGenerator) Name() string {
+	return g.name
+}
+
+func TestCleanWithOnlyKeepsFilesOfOtherGenerators(t *testing.T) {
+	dir := t.TempDir()
+	restFile := filepath.Join(dir, "gen_httpTourService.go")
+	eventFile := filepath.Join(dir, "gen_wrappers.go")
+	for _, filename := range []string{restFile, eventFile} {
+		assert.NoError(t, ioutil.WriteFile(filename, []byte("package tour\n"), 0644))
+	}
+	assert.NoError(t, generator.WriteManifest(dir, []generator.ManifestEntry{
+		{Generator: "rest", Filename: restFile},
+		{Generator: "event", Filename: eventFile},
+	}))
+
+	previousCommand, previousOnly, previousSkip := commandName, only, skip
+	defer func() {
+		commandName, only, skip = previousCommand, previousOnly, previousSkip
+	}()
+	selected, none := "rest", ""
+	commandName, only, skip = cleanCommand, &selected, &none
+
+	// the rest-generator no longer generates anything, the event-generator did not run
+	processRenderedFiles(dir, []generator.Task{{Generator: statsGenerator{name: "rest"}}}, []generator.TaskResult{{}}, &runSummary{})
+
+	_, err := os.Stat(restFile)
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(eventFile)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,403 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/MarcGrol/golangAnnotations/generator/event"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/generator/rest"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+// Kinds of the elements that compat compares
+const (
+	compatOperation = "operation"
+	compatEvent     = "event"
+)
+
+// compatChange is a difference between the baseline-model and the model of the sources
+type compatChange struct {
+	kind    string
+	element string
+	message string
+	// breaking changes make clients of the api or readers of the event fail
+	breaking bool
+	// versioned tells that a breaking change comes with a next version of the api or event
+	versioned bool
+}
+
+func (c compatChange) level() string {
+	switch {
+	case c.breaking && c.versioned:
+		return "versioned"
+	case c.breaking:
+		return "breaking"
+	}
+	return "compatible"
+}
+
+// runCompat compares the sources of all input-dirs with the model of -compat-baseline and fails on breaking
+// changes without a next version
+func runCompat() {
+	if *compatBaseline == "" {
+		fail(exitUsage, "Missing -compat-baseline: the model to compare with, as written by parse")
+	}
+	inputDirs, err := applyProjectConfig()
+	if err != nil {
+		fail(exitUsage, "Error in config: %s", err)
+	}
+	baseline, err := model.Parse(*compatBaseline)
+	if err != nil {
+		fail(exitUsage, "Error reading baseline: %s", err)
+	}
+	baseline.EmbedOperations()
+
+	c, stop := interruptibleContext()
+	defer stop()
+
+	changes := compareModels(baseline, mergeParsed(parseInputDirs(c, inputDirs)))
+	printChanges(os.Stdout, changes)
+	unversioned := 0
+	for _, change := range changes {
+		if change.breaking && !change.versioned {
+			unversioned++
+		}
+	}
+	if unversioned > 0 {
+		fail(exitFailure, "%d breaking changes without a next version of the api or event", unversioned)
+	}
+}
+
+// compareModels classifies the changes of the rest-operations and the events between two models
+func compareModels(baseline model.ParsedSources, current model.ParsedSources) []compatChange {
+	changes := compareOperations(baseline, current)
+	return append(changes, compareEvents(baseline, current)...)
+}
+
+type restRoute struct {
+	service   model.Struct
+	operation model.Operation
+}
+
+func (r restRoute) String() string {
+	return rest.GetRestOperationMethod(r.operation) + " " + rest.GetRestServicePath(r.service) + rest.GetRestOperationPath(r.operation)
+}
+
+var pathParam = regexp.MustCompile(`\{[^}]*\}`)
+
+// restRoutes returns the rest-operations of a model by method and path: the names of path-parameters are left out,
+// because renaming them keeps the route
+func restRoutes(parsed model.ParsedSources) map[string]restRoute {
+	routes := map[string]restRoute{}
+	for _, s := range parsed.Structs {
+		if !rest.IsRestService(s) {
+			continue
+		}
+		for _, o := range s.Operations {
+			if rest.IsRestOperation(*o) {
+				route := restRoute{service: s, operation: *o}
+				routes[pathParam.ReplaceAllString(route.String(), "{}")] = route
+			}
+		}
+	}
+	return routes
+}
+
+// compareOperations finds the removed and added rest-operations and the changes of their parameters and bodies.
+// Removing an operation is allowed with a next version of its service.
+func compareOperations(baseline model.ParsedSources, current model.ParsedSources) []compatChange {
+	before, after := restRoutes(baseline), restRoutes(current)
+	changes := []compatChange{}
+	for _, key := range sortedRoutes(before) {
+		b := before[key]
+		c, found := after[key]
+		if !found {
+			changes = append(changes, compatChange{
+				kind:      compatOperation,
+				element:   b.String(),
+				message:   fmt.Sprintf("Operation %s.%s is removed", b.service.Name, b.operation.Name),
+				breaking:  true,
+				versioned: hasNextServiceVersion(b.service, current),
+			})
+			continue
+		}
+		changes = append(changes, compareOperation(b, c, baseline, current)...)
+	}
+	for _, key := range sortedRoutes(after) {
+		if _, found := before[key]; !found {
+			c := after[key]
+			changes = append(changes, compatChange{kind: compatOperation, element: c.String(), message: fmt.Sprintf("Operation %s.%s is added", c.service.Name, c.operation.Name)})
+		}
+	}
+	return changes
+}
+
+func compareOperation(b restRoute, c restRoute, baseline model.ParsedSources, current model.ParsedSources) []compatChange {
+	changes := []compatChange{}
+	add := func(breaking bool, format string, args ...interface{}) {
+		changes = append(changes, compatChange{kind: compatOperation, element: c.String(), message: fmt.Sprintf(format, args...), breaking: breaking})
+	}
+
+	beforeParams, afterParams := queryParams(b.operation), queryParams(c.operation)
+	for _, name := range sortedFields(beforeParams) {
+		if _, found := afterParams[name]; !found {
+			add(false, "Parameter %s is no longer read", name)
+		}
+	}
+	for _, name := range sortedFields(afterParams) {
+		after := afterParams[name]
+		before, found := beforeParams[name]
+		mandatory := rest.IsInputArgMandatory(c.operation, after)
+		switch {
+		case !found && mandatory:
+			add(true, "Mandatory parameter %s is added", name)
+		case !found:
+			add(false, "Optional parameter %s is added", name)
+		case before.TypeName != after.TypeName:
+			add(true, "Parameter %s changes from %s to %s", name, before.TypeName, after.TypeName)
+		case mandatory && !rest.IsInputArgMandatory(b.operation, before):
+			add(true, "Parameter %s becomes mandatory", name)
+		}
+	}
+
+	beforeInput, afterInput := bodyType(rest.HasInput(b.operation), rest.GetInputArgType(b.operation)), bodyType(rest.HasInput(c.operation), rest.GetInputArgType(c.operation))
+	switch {
+	case beforeInput == "" && afterInput != "":
+		add(true, "Request body of type %s is added", afterInput)
+	case beforeInput != "" && afterInput == "":
+		add(false, "Request body is no longer read")
+	case beforeInput != afterInput:
+		add(true, "Request body changes from %s to %s", beforeInput, afterInput)
+	case beforeInput != "":
+		for _, s := range compareSchema(baseline, current, afterInput, false) {
+			add(s.breaking, "Request body %s", s.message)
+		}
+	}
+
+	beforeOutput, afterOutput := bodyType(rest.HasOutput(b.operation), rest.GetOutputArgType(b.operation)), bodyType(rest.HasOutput(c.operation), rest.GetOutputArgType(c.operation))
+	switch {
+	case beforeOutput == "" && afterOutput != "":
+		add(false, "Response body of type %s is added", afterOutput)
+	case beforeOutput != "" && afterOutput == "":
+		add(true, "Response body is removed")
+	case beforeOutput != afterOutput:
+		add(true, "Response body changes from %s to %s", beforeOutput, afterOutput)
+	case beforeOutput != "":
+		for _, s := range compareSchema(baseline, current, afterOutput, true) {
+			add(s.breaking, "Response body %s", s.message)
+		}
+	}
+	return changes
+}
+
+func bodyType(hasBody bool, typeName string) string {
+	if !hasBody {
+		return ""
+	}
+	return strings.TrimPrefix(typeName, "*")
+}
+
+// queryParams returns the arguments of an operation that clients pass in the query, by name
+func queryParams(o model.Operation) map[string]model.Field {
+	params := map[string]model.Field{}
+	for _, arg := range o.InputArgs {
+		if rest.IsQueryParam(o, arg) && !rest.IsInputArg(arg) {
+			params[arg.Name] = arg
+		}
+	}
+	return params
+}
+
+// hasNextServiceVersion tells whether the current model has the service with a higher version than in the baseline
+func hasNextServiceVersion(s model.Struct, current model.ParsedSources) bool {
+	for _, other := range current.Structs {
+		if other.PackageName == s.PackageName && other.Name == s.Name && rest.IsRestService(other) {
+			return versionNumber(rest.GetRestServiceVersion(other)) > versionNumber(rest.GetRestServiceVersion(s))
+		}
+	}
+	return false
+}
+
+func versionNumber(version string) int {
+	number, _ := strconv.Atoi(strings.TrimPrefix(version, "v"))
+	return number
+}
+
+// compareEvents finds the removed and added events and the changes of their fields. Once stored or published, events
+// are read by other services, so a changed field is allowed only with a next version of the event.
+func compareEvents(baseline model.ParsedSources, current model.ParsedSources) []compatChange {
+	before, after := events(baseline), events(current)
+	changes := []compatChange{}
+	for _, key := range sortedStructs(before) {
+		b := before[key]
+		c, found := after[key]
+		if !found {
+			changes = append(changes, compatChange{kind: compatEvent, element: key, message: "Event is removed, so stored events of its type can no longer be read", breaking: true})
+			continue
+		}
+		versioned := event.GetEventVersion(c) > event.GetEventVersion(b)
+		if event.GetAggregateName(b) != event.GetAggregateName(c) {
+			changes = append(changes, compatChange{kind: compatEvent, element: key, message: fmt.Sprintf("Event moves from aggregate %s to %s", event.GetAggregateName(b), event.GetAggregateName(c)), breaking: true, versioned: versioned})
+		}
+		for _, s := range compareSchema(baseline, current, key, true) {
+			changes = append(changes, compatChange{kind: compatEvent, element: key, message: "Event " + s.message, breaking: s.breaking, versioned: versioned && s.breaking})
+		}
+	}
+	for _, key := range sortedStructs(after) {
+		if _, found := before[key]; !found {
+			changes = append(changes, compatChange{kind: compatEvent, element: key, message: "Event is added"})
+		}
+	}
+	return changes
+}
+
+// events returns the events of a model by their package-qualified name
+func events(parsed model.ParsedSources) map[string]model.Struct {
+	found := map[string]model.Struct{}
+	for _, s := range parsed.Structs {
+		if event.IsEvent(s) {
+			found[s.PackageName+"."+s.Name] = s
+		}
+	}
+	return found
+}
+
+type schemaChange struct {
+	message  string
+	breaking bool
+}
+
+// compareSchema compares the json-fields of a type in both models, and the fields of their types in turn. For types
+// that clients read, removing a field breaks them; for types that the service reads, only changed types do.
+func compareSchema(baseline model.ParsedSources, current model.ParsedSources, typeName string, read bool) []schemaChange {
+	changes := []schemaChange{}
+	visited := map[string]bool{}
+	var compare func(typeName string, prefix string)
+	compare = func(typeName string, prefix string) {
+		b, foundBefore := findStruct(baseline, typeName)
+		c, foundAfter := findStruct(current, typeName)
+		if !foundBefore || !foundAfter || visited[typeName] {
+			return
+		}
+		visited[typeName] = true
+		beforeFields, afterFields := jsonFields(baseline, b), jsonFields(current, c)
+		for _, name := range sortedFields(beforeFields) {
+			before := beforeFields[name]
+			after, found := afterFields[name]
+			switch {
+			case !found:
+				changes = append(changes, schemaChange{message: fmt.Sprintf("field %s%s is removed", prefix, name), breaking: read})
+			case before.TypeName != after.TypeName:
+				changes = append(changes, schemaChange{message: fmt.Sprintf("field %s%s changes from %s to %s", prefix, name, before.TypeName, after.TypeName), breaking: true})
+			default:
+				compare(elementType(after.TypeName), prefix+name+".")
+			}
+		}
+		for _, name := range sortedFields(afterFields) {
+			if _, found := beforeFields[name]; !found {
+				changes = append(changes, schemaChange{message: fmt.Sprintf("field %s%s is added", prefix, name)})
+			}
+		}
+	}
+	compare(elementType(typeName), "")
+	return changes
+}
+
+// jsonFields returns the fields of a struct by their name in json, with the fields of embedded structs
+func jsonFields(parsed model.ParsedSources, s model.Struct) map[string]model.Field {
+	fields := map[string]model.Field{}
+	for _, f := range s.Fields {
+		name := strings.Split(f.GetTagMap()["json"], ",")[0]
+		if name == "-" || (name == "" && f.Name != "" && !generationUtil.IsExported(f.Name)) {
+			continue
+		}
+		if name == "" && f.IsEmbedded {
+			if embedded, found := findStruct(parsed, elementType(f.TypeName)); found {
+				for embeddedName, embeddedField := range jsonFields(parsed, embedded) {
+					fields[embeddedName] = embeddedField
+				}
+				continue
+			}
+		}
+		if name == "" {
+			name = f.Name
+			if f.IsEmbedded {
+				name = f.EmbeddedName()
+			}
+		}
+		fields[name] = f
+	}
+	return fields
+}
+
+// findStruct returns the struct of a type, like Person or person.Person
+func findStruct(parsed model.ParsedSources, typeName string) (model.Struct, bool) {
+	packageName, name := "", typeName
+	if idx := strings.LastIndex(typeName, "."); idx >= 0 {
+		packageName, name = typeName[:idx], typeName[idx+1:]
+	}
+	for _, s := range parsed.Structs {
+		if s.Name == name && (packageName == "" || s.PackageName == packageName) {
+			return s, true
+		}
+	}
+	return model.Struct{}, false
+}
+
+// elementType returns the type without pointers and slices, like Person for []*Person
+func elementType(typeName string) string {
+	for {
+		trimmed := strings.TrimPrefix(strings.TrimPrefix(typeName, "*"), "[]")
+		if trimmed == typeName {
+			return typeName
+		}
+		typeName = trimmed
+	}
+}
+
+func sortedRoutes(routes map[string]restRoute) []string {
+	keys := []string{}
+	for key := range routes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedFields(fields map[string]model.Field) []string {
+	names := []string{}
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedStructs(structs map[string]model.Struct) []string {
+	names := []string{}
+	for name := range structs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func printChanges(w io.Writer, changes []compatChange) {
+	if len(changes) == 0 {
+		fmt.Fprintln(w, "No changes")
+		return
+	}
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "Level\tKind\tElement\tChange\n")
+	for _, change := range changes {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", change.level(), change.kind, change.element, change.message)
+	}
+	tw.Flush()
+}
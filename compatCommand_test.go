@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func compatSources(service string, getPerson string, personFields []model.Field, event string, eventFields []model.Field) model.ParsedSources {
+	parsed := model.ParsedSources{
+		Structs: []model.Struct{
+			{PackageName: "person", Name: "PersonService", DocLines: []string{service}},
+			{PackageName: "person", Name: "Person", Fields: personFields},
+			{PackageName: "person", Name: "PersonCreated", DocLines: []string{event}, Fields: eventFields},
+		},
+		Operations: []model.Operation{
+			{
+				PackageName:   "person",
+				Name:          "getPerson",
+				DocLines:      []string{getPerson},
+				RelatedStruct: &model.Field{TypeName: "*PersonService"},
+				InputArgs:     []model.Field{{Name: "uid", TypeName: "string"}, {Name: "verbose", TypeName: "bool"}},
+				OutputArgs:    []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+			},
+		},
+	}
+	parsed.EmbedOperations()
+	return parsed
+}
+
+func messages(changes []compatChange) []string {
+	found := []string{}
+	for _, change := range changes {
+		found = append(found, change.level()+" "+change.element+": "+change.message)
+	}
+	return found
+}
+
+func TestCompareModelsWithoutChanges(t *testing.T) {
+	parsed := compatSources(`// @RestService( path = "/api" )`, `// @RestOperation( method = "GET", path = "/person/{uid}" )`,
+		[]model.Field{{Name: "Name", TypeName: "string", Tag: "`json:\"name\"`"}},
+		`// @Event( aggregate = "Person" )`, []model.Field{{Name: "Name", TypeName: "string"}})
+	assert.Empty(t, compareModels(parsed, parsed))
+}
+
+func TestCompareModelsClassifiesChanges(t *testing.T) {
+	baseline := compatSources(`// @RestService( path = "/api" )`, `// @RestOperation( method = "GET", path = "/person/{uid}", optionalargs = "verbose" )`,
+		[]model.Field{{Name: "Name", TypeName: "string", Tag: "`json:\"name\"`"}, {Name: "Age", TypeName: "int", Tag: "`json:\"age\"`"}},
+		`// @Event( aggregate = "Person" )`, []model.Field{{Name: "Name", TypeName: "string"}})
+	current := compatSources(`// @RestService( path = "/api" )`, `// @RestOperation( method = "GET", path = "/person/{id}" )`,
+		[]model.Field{{Name: "Name", TypeName: "string", Tag: "`json:\"name\"`"}, {Name: "Email", TypeName: "string", Tag: "`json:\"email\"`"}},
+		`// @Event( aggregate = "Person", version = "1" )`, []model.Field{{Name: "Name", TypeName: "[]string"}})
+	current.Structs[0].Operations[0].InputArgs[0].Name = "id"
+
+	assert.Equal(t, []string{
+		"breaking GET /api/person/{id}: Parameter verbose becomes mandatory",
+		"breaking GET /api/person/{id}: Response body field age is removed",
+		"compatible GET /api/person/{id}: Response body field email is added",
+		"versioned person.PersonCreated: Event field Name changes from string to []string",
+	}, messages(compareModels(baseline, current)))
+}
+
+func TestCompareModelsRemovedOperations(t *testing.T) {
+	baseline := compatSources(`// @RestService( path = "/persons", version = "v1" )`, `// @RestOperation( method = "GET", path = "/{uid}" )`,
+		nil, `// @Event( aggregate = "Person" )`, nil)
+	current := compatSources(`// @RestService( path = "/persons", version = "v2" )`, `// @RestOperation( method = "GET", path = "/{uid}" )`,
+		nil, `// @Event( aggregate = "Tour" )`, nil)
+
+	assert.Equal(t, []string{
+		"versioned GET /v1/persons/{uid}: Operation PersonService.getPerson is removed",
+		"compatible GET /v2/persons/{uid}: Operation PersonService.getPerson is added",
+		"breaking person.PersonCreated: Event moves from aggregate Person to Tour",
+	}, messages(compareModels(baseline, current)))
+
+	current.Structs = current.Structs[1:2]
+	assert.Equal(t, []string{
+		"breaking GET /v1/persons/{uid}: Operation PersonService.getPerson is removed",
+		"breaking person.PersonCreated: Event is removed, so stored events of its type can no longer be read",
+	}, messages(compareModels(baseline, current)))
+}
+
+func TestPrintChanges(t *testing.T) {
+	var buf bytes.Buffer
+	printChanges(&buf, []compatChange{{kind: compatEvent, element: "person.PersonCreated", message: "Event is added"}})
+	assert.Equal(t, `Level       Kind   Element               Change
+compatible  event  person.PersonCreated  Event is added
+`, buf.String())
+
+	buf.Reset()
+	printChanges(&buf, nil)
+	assert.Equal(t, "No changes\n", buf.String())
+}
@@ -2,6 +2,10 @@ package annotation
 
 import "strings"
 
+// ParamBuildTags is the attribute that every annotation accepts for a build-constraint of the files generated for
+// it, e.g. buildtags = "!appengine"
+const ParamBuildTags = "buildtags"
+
 type AnnotationRegister interface {
 	ResolveAnnotations(annotationDocline []string) []Annotation
 	ResolveAnnotationByName(annotationDocline []string, name string) (Annotation, bool)
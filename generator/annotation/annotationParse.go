@@ -16,6 +16,11 @@ const (
 	done
 )
 
+// Parse returns the annotation of a doc-line, without validating it against a descriptor
+func Parse(line string) (Annotation, error) {
+	return parseAnnotation(line)
+}
+
 func parseAnnotation(line string) (Annotation, error) {
 	withoutComment := strings.TrimLeft(strings.TrimSpace(line), "/")
 
@@ -106,3 +106,12 @@ func validateOk(annot Annotation) bool {
 func validateError(annot Annotation) bool {
 	return false
 }
+
+func TestParse(t *testing.T) {
+	ann, err := Parse(`// @RestOperation( Method = "GET", path = "/tour" )`)
+	assert.NoError(t, err)
+	assert.Equal(t, Annotation{Name: "RestOperation", Attributes: map[string]string{"method": "GET", "path": "/tour"}}, ann)
+
+	_, err = Parse(`// @RestOperation( method = "GET"`)
+	assert.Error(t, err)
+}
@@ -3,8 +3,9 @@ package ast
 import (
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"os"
+	"path/filepath"
 
 	"github.com/MarcGrol/golangAnnotations/generator"
 	"github.com/MarcGrol/golangAnnotations/generator/annotation"
@@ -15,37 +16,106 @@ import (
 
 type Generator struct {
 	targetFilename string
+	out            io.Writer
 }
 
+// NewGenerator returns a generator that dumps the parsed sources into targetFilename in the input-dir,
+// or to stdout when targetFilename is empty
 func NewGenerator(targetFilename string) generator.Generator {
+	if targetFilename == "" {
+		return NewGeneratorForWriter(os.Stdout)
+	}
 	return &Generator{
 		targetFilename: targetFilename,
 	}
 }
 
+// NewGeneratorForWriter returns a generator that dumps the parsed sources to out instead of into a file
+func NewGeneratorForWriter(out io.Writer) generator.Generator {
+	return &Generator{
+		out: out,
+	}
+}
+
+func (eg *Generator) Name() string {
+	return "ast"
+}
+
 func (eg *Generator) GetAnnotations() []annotation.AnnotationDescriptor {
 	return eventAnnotation.Get()
 }
 
-func (eg *Generator) Generate(inputDir string, parsedSources model.ParsedSources) error {
+func (eg *Generator) Generate(parsedSources model.ParsedSources, config generator.Config) ([]generator.OutputFile, error) {
 
-	marshalled, err := json.MarshalIndent(parsedSources, "", "\t")
+	marshalled, err := json.MarshalIndent(withRelativeFilenames(parsedSources, config.InputDir), "", "\t")
 	if err != nil {
-		panic(err)
+		return nil, fmt.Errorf("Error marshalling json-ast:%w", err)
 	}
 
-	if eg.targetFilename != "" {
-		filenamePath := generationUtil.Prefixed(inputDir + "/" + eg.targetFilename)
-		err = ioutil.WriteFile(filenamePath, marshalled, 0644)
+	if eg.out != nil {
+		_, err = eg.out.Write(marshalled)
 		if err != nil {
-			return fmt.Errorf("Error writing json-ast to file:%s", err)
+			return nil, fmt.Errorf("Error writing json-ast:%w", err)
 		}
-	} else {
-		_, err = os.Stdout.Write(marshalled)
-		if err != nil {
-			return fmt.Errorf("Error writing json-ast to stdout:%s", err)
+		return nil, nil
+	}
+
+	return []generator.OutputFile{
+		{
+			Filename: generationUtil.Prefixed(config.InputDir + "/" + eg.targetFilename),
+			Src:      config.InputDir,
+			Content:  marshalled,
+		},
+	}, nil
+}
+
+// withRelativeFilenames returns a copy of parsedSources with filenames relative to the input-dir,
+// so that the dumped ast does not depend on where the sources are checked out
+func withRelativeFilenames(parsedSources model.ParsedSources, inputDir string) model.ParsedSources {
+	relative := func(filename string) string {
+		if filename == "" || inputDir == "" {
+			return filename
+		}
+		if rel, err := filepath.Rel(inputDir, filename); err == nil {
+			return filepath.ToSlash(rel)
 		}
+		return filename
+	}
+	relativeOperation := func(o model.Operation) model.Operation {
+		o.Filename = relative(o.Filename)
+		return o
 	}
 
-	return nil
+	result := model.ParsedSources{}
+	for _, s := range parsedSources.Structs {
+		s.Filename = relative(s.Filename)
+		operations := s.Operations
+		s.Operations = nil
+		for _, o := range operations {
+			relativeOper := relativeOperation(*o)
+			s.Operations = append(s.Operations, &relativeOper)
+		}
+		result.Structs = append(result.Structs, s)
+	}
+	for _, o := range parsedSources.Operations {
+		result.Operations = append(result.Operations, relativeOperation(o))
+	}
+	for _, i := range parsedSources.Interfaces {
+		i.Filename = relative(i.Filename)
+		methods := i.Methods
+		i.Methods = nil
+		for _, m := range methods {
+			i.Methods = append(i.Methods, relativeOperation(m))
+		}
+		result.Interfaces = append(result.Interfaces, i)
+	}
+	for _, t := range parsedSources.Typedefs {
+		t.Filename = relative(t.Filename)
+		result.Typedefs = append(result.Typedefs, t)
+	}
+	for _, e := range parsedSources.Enums {
+		e.Filename = relative(e.Filename)
+		result.Enums = append(result.Enums, e)
+	}
+	return result
 }
@@ -0,0 +1,153 @@
+package generator
+
+import (
+	"fmt"
+	"go/build/constraint"
+	"path/filepath"
+	"strings"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+// ApplyBuildTags puts a build-constraint in the generated go-files: the build-tags of the generator in config,
+// combined with the buildtags-attribute of the annotations of the struct or interface that a file is generated for.
+// Files that cover a package combine the attributes of all annotated structs and interfaces of the package.
+func ApplyBuildTags(files []OutputFile, g Generator, parsedSources model.ParsedSources, config Config) ([]OutputFile, error) {
+	var generatorExpr constraint.Expr
+	if config.BuildTags != "" {
+		var err error
+		generatorExpr, err = parseBuildTags(config.BuildTags)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid build-tags '%s' of generator %s:%w", config.BuildTags, g.Name(), err)
+		}
+	}
+	typeExprs, packageExprs, err := annotatedBuildTags(g.GetAnnotations(), parsedSources)
+	if err != nil {
+		return nil, err
+	}
+	for idx, f := range files {
+		if f.Fixed || filepath.Ext(f.Filename) != ".go" {
+			continue
+		}
+		exprs := []constraint.Expr{}
+		if generatorExpr != nil {
+			exprs = append(exprs, generatorExpr)
+		}
+		if f.TypeName != "" {
+			exprs = append(exprs, typeExprs[f.TypeName]...)
+		} else {
+			exprs = append(exprs, packageExprs...)
+		}
+		if len(exprs) == 0 {
+			continue
+		}
+		files[idx].Content, err = insertBuildConstraint(f.Content, exprs)
+		if err != nil {
+			return nil, fmt.Errorf("Error adding build-tags to %s:%w", f.Filename, err)
+		}
+	}
+	return files, nil
+}
+
+// annotatedBuildTags returns the build-tags that the annotations of the generator request per struct and interface,
+// and all distinct ones of the package
+func annotatedBuildTags(descriptors []annotation.AnnotationDescriptor, parsedSources model.ParsedSources) (map[string][]constraint.Expr, []constraint.Expr, error) {
+	registry := annotation.NewRegistry(descriptors)
+	typeExprs := map[string][]constraint.Expr{}
+	packageExprs := []constraint.Expr{}
+	seen := map[string]bool{}
+	collect := func(typeName string, docLines []string, sourceError func(annotationName string, format string, args ...interface{}) error) error {
+		for _, ann := range registry.ResolveAnnotations(docLines) {
+			tags, found := ann.Attributes[annotation.ParamBuildTags]
+			if !found || tags == "" {
+				continue
+			}
+			expr, err := parseBuildTags(tags)
+			if err != nil {
+				return sourceError(ann.Name, "Invalid build-tags '%s': %s", tags, err)
+			}
+			typeExprs[typeName] = append(typeExprs[typeName], expr)
+			if !seen[expr.String()] {
+				seen[expr.String()] = true
+				packageExprs = append(packageExprs, expr)
+			}
+		}
+		return nil
+	}
+	for _, s := range parsedSources.Structs {
+		err := collect(s.Name, s.DocLines, func(annotationName string, format string, args ...interface{}) error {
+			return StructError(s, annotationName, format, args...)
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	for _, i := range parsedSources.Interfaces {
+		err := collect(i.Name, i.DocLines, func(annotationName string, format string, args ...interface{}) error {
+			return InterfaceError(i, annotationName, format, args...)
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return typeExprs, packageExprs, nil
+}
+
+func parseBuildTags(tags string) (constraint.Expr, error) {
+	return constraint.Parse("//go:build " + tags)
+}
+
+// insertBuildConstraint puts the combination of exprs and an existing constraint of content just above the
+// package-clause, in both the //go:build and the // +build syntax
+func insertBuildConstraint(content []byte, exprs []constraint.Expr) ([]byte, error) {
+	lines := strings.Split(string(content), "\n")
+	preamble := []string{}
+	var expr constraint.Expr
+	packageIdx := -1
+	for idx, line := range lines {
+		if strings.HasPrefix(line, "package ") {
+			packageIdx = idx
+			break
+		}
+		if constraint.IsGoBuild(line) {
+			existing, err := constraint.Parse(line)
+			if err != nil {
+				return nil, err
+			}
+			expr = existing
+			continue
+		}
+		if constraint.IsPlusBuild(line) {
+			continue
+		}
+		preamble = append(preamble, line)
+	}
+	if packageIdx < 0 {
+		return nil, fmt.Errorf("No package-clause")
+	}
+	for _, e := range exprs {
+		if expr == nil {
+			expr = e
+		} else {
+			expr = &constraint.AndExpr{X: expr, Y: e}
+		}
+	}
+	plusBuildLines, err := constraint.PlusBuildLines(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	for len(preamble) > 0 && strings.TrimSpace(preamble[len(preamble)-1]) == "" {
+		preamble = preamble[:len(preamble)-1]
+	}
+	result := preamble
+	if len(result) > 0 {
+		result = append(result, "")
+	}
+	result = append(result, "//go:build "+expr.String())
+	result = append(result, plusBuildLines...)
+	result = append(result, "")
+	result = append(result, lines[packageIdx:]...)
+	return []byte(strings.Join(result, "\n")), nil
+}
@@ -0,0 +1,69 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+type annotatedGenerator struct {
+	namedGenerator
+}
+
+func (g annotatedGenerator) GetAnnotations() []annotation.AnnotationDescriptor {
+	return []annotation.AnnotationDescriptor{{
+		Name:       "Thing",
+		ParamNames: []string{annotation.ParamBuildTags},
+		Validator:  func(annot annotation.Annotation) bool { return true },
+	}}
+}
+
+func TestApplyBuildTagsOfAnnotation(t *testing.T) {
+	s := []model.Struct{
+		{Filename: "thing.go", Line: 4, Name: "Thing", DocLines: []string{`// @Thing( buildtags = "!appengine" )`}},
+		{Filename: "other.go", Line: 8, Name: "Other", DocLines: []string{`// @Thing()`}},
+	}
+	files, err := ApplyBuildTags([]OutputFile{
+		{Filename: "gen_thing.go", TypeName: "Thing", Content: []byte("// Generated\n\npackage x\n")},
+		{Filename: "gen_other.go", TypeName: "Other", Content: []byte("// Generated\n\npackage x\n")},
+		{Filename: "gen_package.go", Content: []byte("package x\n")},
+		{Filename: "gen_thing.json", TypeName: "Thing", Content: []byte("{}\n")},
+	}, annotatedGenerator{}, model.ParsedSources{Structs: s}, Config{})
+	assert.NoError(t, err)
+	assert.Equal(t, "// Generated\n\n//go:build !appengine\n// +build !appengine\n\npackage x\n", string(files[0].Content))
+	assert.Equal(t, "// Generated\n\npackage x\n", string(files[1].Content))
+	assert.Equal(t, "//go:build !appengine\n// +build !appengine\n\npackage x\n", string(files[2].Content))
+	assert.Equal(t, "{}\n", string(files[3].Content))
+}
+
+func TestApplyBuildTagsCombinesConstraints(t *testing.T) {
+	s := []model.Struct{
+		{Filename: "thing.go", Line: 4, Name: "Thing", DocLines: []string{`// @Thing( buildtags = "!appengine" )`}},
+		{Filename: "other.go", Line: 8, Name: "Other", DocLines: []string{`// @Thing()`}},
+	}
+	files, err := ApplyBuildTags([]OutputFile{
+		{Filename: "gen_thing.go", TypeName: "Thing", Content: []byte("//go:build go1.18\n// +build go1.18\n\npackage x\n")},
+	}, annotatedGenerator{}, model.ParsedSources{Structs: s}, Config{BuildTags: "linux || darwin"})
+	assert.NoError(t, err)
+	assert.Equal(t, "//go:build go1.18 && (linux || darwin) && !appengine\n// +build go1.18\n// +build linux darwin\n// +build !appengine\n\npackage x\n", string(files[0].Content))
+}
+
+func TestApplyBuildTagsRejectsInvalidAttribute(t *testing.T) {
+	s := []model.Struct{
+		{Filename: "thing.go", Line: 4, Name: "Thing", DocLines: []string{`// @Thing( buildtags = "!" )`}},
+		{Filename: "other.go", Line: 8, Name: "Other", DocLines: []string{`// @Thing()`}},
+	}
+	_, err := ApplyBuildTags(nil, annotatedGenerator{}, model.ParsedSources{Structs: s}, Config{})
+	assert.Error(t, err)
+	sourceError, ok := AsSourceError(err)
+	assert.True(t, ok)
+	assert.Equal(t, "thing.go", sourceError.Filename)
+	assert.Equal(t, 3, sourceError.Line)
+}
+
+func TestApplyBuildTagsRejectsInvalidConfig(t *testing.T) {
+	_, err := ApplyBuildTags(nil, annotatedGenerator{namedGenerator{name: "a"}}, model.ParsedSources{}, Config{BuildTags: "a &&"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,88 @@
+// Package builtin provides the registry with all generators that are part of golangAnnotations
+package builtin
+
+import (
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/ast"
+	"github.com/MarcGrol/golangAnnotations/generator/cache"
+	"github.com/MarcGrol/golangAnnotations/generator/cli"
+	"github.com/MarcGrol/golangAnnotations/generator/config"
+	"github.com/MarcGrol/golangAnnotations/generator/csharp"
+	"github.com/MarcGrol/golangAnnotations/generator/dart"
+	"github.com/MarcGrol/golangAnnotations/generator/docs"
+	"github.com/MarcGrol/golangAnnotations/generator/entity"
+	"github.com/MarcGrol/golangAnnotations/generator/event"
+	"github.com/MarcGrol/golangAnnotations/generator/eventService"
+	"github.com/MarcGrol/golangAnnotations/generator/export"
+	"github.com/MarcGrol/golangAnnotations/generator/flatbuffers"
+	"github.com/MarcGrol/golangAnnotations/generator/inject"
+	"github.com/MarcGrol/golangAnnotations/generator/jsonHelpers"
+	"github.com/MarcGrol/golangAnnotations/generator/kubernetes"
+	"github.com/MarcGrol/golangAnnotations/generator/messages"
+	"github.com/MarcGrol/golangAnnotations/generator/migration"
+	"github.com/MarcGrol/golangAnnotations/generator/notify"
+	"github.com/MarcGrol/golangAnnotations/generator/openapi"
+	"github.com/MarcGrol/golangAnnotations/generator/pact"
+	"github.com/MarcGrol/golangAnnotations/generator/pii"
+	"github.com/MarcGrol/golangAnnotations/generator/postman"
+	"github.com/MarcGrol/golangAnnotations/generator/python"
+	"github.com/MarcGrol/golangAnnotations/generator/recording"
+	"github.com/MarcGrol/golangAnnotations/generator/repository"
+	"github.com/MarcGrol/golangAnnotations/generator/rest"
+	"github.com/MarcGrol/golangAnnotations/generator/retrofit"
+	"github.com/MarcGrol/golangAnnotations/generator/scheduler"
+	"github.com/MarcGrol/golangAnnotations/generator/search"
+	"github.com/MarcGrol/golangAnnotations/generator/shadow"
+	"github.com/MarcGrol/golangAnnotations/generator/stateMachine"
+	"github.com/MarcGrol/golangAnnotations/generator/stub"
+	"github.com/MarcGrol/golangAnnotations/generator/tags"
+	"github.com/MarcGrol/golangAnnotations/generator/task"
+	"github.com/MarcGrol/golangAnnotations/generator/terraform"
+	"github.com/MarcGrol/golangAnnotations/generator/testFactory"
+	"github.com/MarcGrol/golangAnnotations/generator/warehouse"
+	"github.com/MarcGrol/golangAnnotations/generator/webhook"
+)
+
+// NewRegistry returns a registry with all built-in generators: callers can register their own generators on top
+func NewRegistry() *generator.Registry {
+	return generator.NewRegistry(
+		ast.NewGenerator("ast.json"),
+		cache.NewGenerator(),
+		cli.NewGenerator(),
+		config.NewGenerator(),
+		csharp.NewGenerator(),
+		dart.NewGenerator(),
+		docs.NewGenerator(),
+		entity.NewGenerator(),
+		event.NewGenerator(),
+		eventService.NewGenerator(),
+		export.NewGenerator(),
+		flatbuffers.NewGenerator(),
+		inject.NewGenerator(),
+		jsonHelpers.NewGenerator(),
+		kubernetes.NewGenerator(),
+		messages.NewGenerator(),
+		migration.NewGenerator(),
+		notify.NewGenerator(),
+		openapi.NewGenerator(),
+		pact.NewGenerator(),
+		pii.NewGenerator(),
+		postman.NewGenerator(),
+		python.NewGenerator(),
+		recording.NewGenerator(),
+		repository.NewGenerator(),
+		rest.NewGenerator(),
+		retrofit.NewGenerator(),
+		scheduler.NewGenerator(),
+		search.NewGenerator(),
+		shadow.NewGenerator(),
+		stateMachine.NewGenerator(),
+		stub.NewGenerator(),
+		tags.NewGenerator(),
+		task.NewGenerator(),
+		terraform.NewGenerator(),
+		testFactory.NewGenerator(),
+		warehouse.NewGenerator(),
+		webhook.NewGenerator(),
+	)
+}
@@ -0,0 +1,34 @@
+package builtin
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRegistry(t *testing.T) {
+	registry := NewRegistry()
+	assert.Equal(t, []string{"ast", "cache", "cli", "config", "csharp", "dart", "docs", "entity", "event", "event-service", "export",
+		"flatbuffers", "inject", "json-helpers", "kubernetes", "messages", "migration", "notify", "openapi", "pact", "pii", "postman",
+		"python", "recording", "repository", "rest", "retrofit", "scheduler", "search", "shadow", "state-machine", "stub", "tags", "task", "terraform", "test-factory", "warehouse", "webhook"}, registry.Names())
+
+	g, found := registry.Get("rest")
+	assert.True(t, found)
+	assert.Equal(t, "rest", g.Name())
+}
+
+func TestTemplateNamesAreUniquePerGenerator(t *testing.T) {
+	for _, g := range NewRegistry().All() {
+		provider, ok := g.(generator.TemplateProvider)
+		if !ok {
+			continue
+		}
+		names := map[string]bool{}
+		for _, tmpl := range provider.GetTemplates() {
+			assert.False(t, names[tmpl.Name], "Generator %s has duplicate template %s", g.Name(), tmpl.Name)
+			assert.NotEmpty(t, tmpl.Text, "Template %s of generator %s is empty", tmpl.Name, g.Name())
+			names[tmpl.Name] = true
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package cache
+
+const cacheTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+
+package {{.PackageName}}
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+{{ $interface := .Interface.Name -}}
+
+// Cached{{$interface}} decorates {{$interface}} with a cache-aside layer backed by redis
+type Cached{{$interface}} struct {
+	next   {{$interface}}
+	client *redis.Client
+}
+
+func NewCached{{$interface}}(next {{$interface}}, client *redis.Client) *Cached{{$interface}} {
+	return &Cached{{$interface}}{
+		next:   next,
+		client: client,
+	}
+}
+
+{{range .Methods -}}
+func (cache *Cached{{$interface}}) {{.Name}}({{Params .Args}}) {{Results .}} {
+{{- if .Cached }}
+	key := {{.Key}}
+	var result {{ZeroResult .}}
+	if data, err := cache.client.Get({{.ContextName}}, key).Bytes(); err == nil {
+		if err := json.Unmarshal(data, &result); err == nil {
+			return result, nil
+		}
+	}
+
+	result, err := cache.next.{{.Name}}({{CallArgs .Args}})
+	if err != nil {
+		return result, err
+	}
+	if data, err := json.Marshal(result); err == nil {
+		cache.client.Set({{.ContextName}}, key, data, {{.TTL}})
+	}
+	return result, nil
+{{- else if .EvictKeys }}
+	{{ResultVars .}} := cache.next.{{.Name}}({{CallArgs .Args}})
+	if err == nil {
+		cache.client.Del({{.ContextName}}{{range .EvictKeys}}, {{.}}{{end}})
+	}
+	return {{ResultVars .}}
+{{- else }}
+	return cache.next.{{.Name}}({{CallArgs .Args}})
+{{- end }}
+}
+
+{{if .Cached -}}
+// Invalidate{{.Name}} removes the cached result of {{.Name}} for the given arguments
+func (cache *Cached{{$interface}}) Invalidate{{.Name}}(c context.Context{{range .KeyArgs}}, {{.Name}} {{.TypeName}}{{end}}) error {
+	return cache.client.Del(c, {{.Key}}).Err()
+}
+
+{{end -}}
+{{end -}}
+`
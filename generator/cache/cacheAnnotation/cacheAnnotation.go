@@ -0,0 +1,49 @@
+package cacheAnnotation
+
+import (
+	"time"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+)
+
+const (
+	TypeCached     = "Cached"
+	TypeCacheEvict = "CacheEvict"
+	ParamTTL       = "ttl"
+	ParamKey       = "key"
+	ParamKeys      = "keys"
+)
+
+func Get() []annotation.AnnotationDescriptor {
+	return []annotation.AnnotationDescriptor{
+		{
+			Name:       TypeCached,
+			ParamNames: []string{ParamTTL, ParamKey},
+			Validator:  validateCachedAnnotation,
+		},
+		{
+			Name:       TypeCacheEvict,
+			ParamNames: []string{ParamKeys},
+			Validator:  validateCacheEvictAnnotation,
+		}}
+}
+
+func validateCachedAnnotation(annot annotation.Annotation) bool {
+	if annot.Name == TypeCached {
+		ttl, hasTTL := annot.Attributes[ParamTTL]
+		if !hasTTL {
+			return true
+		}
+		_, err := time.ParseDuration(ttl)
+		return err == nil
+	}
+	return false
+}
+
+func validateCacheEvictAnnotation(annot annotation.Annotation) bool {
+	if annot.Name == TypeCacheEvict {
+		keys, hasKeys := annot.Attributes[ParamKeys]
+		return hasKeys && keys != ""
+	}
+	return false
+}
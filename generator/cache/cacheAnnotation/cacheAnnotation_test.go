@@ -0,0 +1,32 @@
+package cacheAnnotation
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrectCachedAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	annotation, ok := registry.ResolveAnnotation(`// @Cached( ttl = "5m", key = "person:{uid}" )`)
+	assert.True(t, ok)
+	assert.Equal(t, TypeCached, annotation.Name)
+	assert.Equal(t, "5m", annotation.Attributes[ParamTTL])
+	assert.Equal(t, "person:{uid}", annotation.Attributes[ParamKey])
+}
+
+func TestCachedAnnotationWithInvalidTTL(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	_, ok := registry.ResolveAnnotation(`// @Cached( ttl = "often" )`)
+	assert.False(t, ok)
+}
+
+func TestCacheEvictAnnotationWithoutKeys(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	_, ok := registry.ResolveAnnotation(`// @CacheEvict()`)
+	assert.False(t, ok)
+}
@@ -0,0 +1,269 @@
+package cache
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/MarcGrol/golangAnnotations/generator/cache/cacheAnnotation"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+const defaultTTL = 10 * time.Minute
+
+var keyPlaceholderRegex = regexp.MustCompile(`\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+type cacheContext struct {
+	PackageName string
+	Interface   model.Interface
+	Methods     []cacheMethod
+}
+
+type cacheArg struct {
+	Name     string
+	TypeName string
+}
+
+type cacheMethod struct {
+	Name        string
+	Args        []cacheArg
+	KeyArgs     []cacheArg
+	Results     []string
+	ContextName string
+	Cached      bool
+	TTL         string
+	Key         string
+	EvictKeys   []string
+}
+
+type Generator struct {
+}
+
+func NewGenerator() generator.Generator {
+	return &Generator{}
+}
+
+func (eg *Generator) Name() string {
+	return "cache"
+}
+
+func (eg *Generator) GetTemplates() []generator.Template {
+	return []generator.Template{
+		{Name: "cache", APIVersion: 1, Text: cacheTemplate},
+	}
+}
+
+func (eg *Generator) GetAnnotations() []annotation.AnnotationDescriptor {
+	return cacheAnnotation.Get()
+}
+
+func (eg *Generator) Generate(parsedSource model.ParsedSources, config generator.Config) ([]generator.OutputFile, error) {
+	return generate(config, parsedSource.Interfaces)
+}
+
+func generate(config generator.Config, interfaces []model.Interface) ([]generator.OutputFile, error) {
+	packageName, err := generationUtil.GetPackageNameForInterfaces(interfaces)
+	if packageName == "" || err != nil {
+		return nil, err
+	}
+	targetDir, err := generationUtil.DetermineTargetPath(config.InputDir, packageName)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []generator.OutputFile{}
+	for _, i := range interfaces {
+		if !IsCachedInterface(i) {
+			continue
+		}
+		methods, err := getCacheMethods(i)
+		if err != nil {
+			return nil, err
+		}
+
+		file, err := generationUtil.Generate(generationUtil.Info{
+			Src:            fmt.Sprintf("%s.%s", i.PackageName, i.Name),
+			TypeName:       i.Name,
+			TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/cached%s.go", targetDir, i.Name)),
+			TemplateName:   "cache",
+			TemplateString: cacheTemplate,
+			Config:         config,
+			FuncMap:        customTemplateFuncs,
+			Data: cacheContext{
+				PackageName: packageName,
+				Interface:   i,
+				Methods:     methods,
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Error generating cache for interface %s:%w", i.Name, err)
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+var customTemplateFuncs = template.FuncMap{
+	"Params":     params,
+	"CallArgs":   callArgs,
+	"Results":    results,
+	"ResultVars": resultVars,
+	"ZeroResult": zeroResult,
+}
+
+// IsCachedInterface tells if any method of the interface is annotated with @Cached or @CacheEvict
+func IsCachedInterface(i model.Interface) bool {
+	annotations := annotation.NewRegistry(cacheAnnotation.Get())
+	for _, m := range i.Methods {
+		if len(annotations.ResolveAnnotations(m.DocLines)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func getCacheMethods(i model.Interface) ([]cacheMethod, error) {
+	annotations := annotation.NewRegistry(cacheAnnotation.Get())
+
+	methods := make([]cacheMethod, 0, len(i.Methods))
+	for _, o := range i.Methods {
+		method := cacheMethod{
+			Name:        o.Name,
+			ContextName: "context.Background()",
+		}
+		for idx, arg := range o.InputArgs {
+			name := arg.Name
+			if name == "" || name == "_" {
+				name = fmt.Sprintf("arg%d", idx)
+			}
+			a := cacheArg{Name: name, TypeName: arg.TypeName}
+			method.Args = append(method.Args, a)
+			if arg.TypeName == "context.Context" {
+				method.ContextName = name
+			} else {
+				method.KeyArgs = append(method.KeyArgs, a)
+			}
+		}
+		for _, arg := range o.OutputArgs {
+			method.Results = append(method.Results, arg.TypeName)
+		}
+
+		if ann, ok := annotations.ResolveAnnotationByName(o.DocLines, cacheAnnotation.TypeCached); ok {
+			if len(method.Results) != 2 || method.Results[1] != "error" {
+				return nil, generator.OperationError(o, i.Name, cacheAnnotation.TypeCached, "Cached method %s.%s must return a value and an error", i.Name, o.Name)
+			}
+			key, err := keyExpression(i, o, method, ann.Attributes[cacheAnnotation.ParamKey])
+			if err != nil {
+				return nil, err
+			}
+			ttl := defaultTTL
+			if value, found := ann.Attributes[cacheAnnotation.ParamTTL]; found {
+				ttl, _ = time.ParseDuration(value)
+			}
+			method.Cached = true
+			method.Key = key
+			method.TTL = fmt.Sprintf("%d * time.Millisecond", ttl.Milliseconds())
+		}
+
+		if ann, ok := annotations.ResolveAnnotationByName(o.DocLines, cacheAnnotation.TypeCacheEvict); ok {
+			if len(method.Results) == 0 || method.Results[len(method.Results)-1] != "error" {
+				return nil, generator.OperationError(o, i.Name, cacheAnnotation.TypeCacheEvict, "Evicting method %s.%s must return an error", i.Name, o.Name)
+			}
+			for _, template := range strings.Split(ann.Attributes[cacheAnnotation.ParamKeys], ",") {
+				key, err := keyExpression(i, o, method, strings.TrimSpace(template))
+				if err != nil {
+					return nil, err
+				}
+				method.EvictKeys = append(method.EvictKeys, key)
+			}
+		}
+		methods = append(methods, method)
+	}
+	return methods, nil
+}
+
+// keyExpression converts a key-template like "person:{uid}" into the go-expression that renders it;
+// without template the key consists of interface, method and all non-context arguments
+func keyExpression(i model.Interface, o model.Operation, m cacheMethod, keyTemplate string) (string, error) {
+	if keyTemplate == "" {
+		keyTemplate = fmt.Sprintf("%s.%s", i.Name, m.Name)
+		for _, arg := range m.KeyArgs {
+			keyTemplate += fmt.Sprintf(":{%s}", arg.Name)
+		}
+	}
+
+	args := []string{}
+	var err error
+	format := keyPlaceholderRegex.ReplaceAllStringFunc(keyTemplate, func(placeholder string) string {
+		name := keyPlaceholderRegex.FindStringSubmatch(placeholder)[1]
+		if !hasArg(m, name) {
+			err = generator.OperationError(o, i.Name, "", "Cache-key %s of method %s.%s refers to unknown argument %s", keyTemplate, i.Name, m.Name, name)
+		}
+		args = append(args, name)
+		return "%v"
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(args) == 0 {
+		return fmt.Sprintf("%q", format), nil
+	}
+	return fmt.Sprintf("fmt.Sprintf(%q, %s)", format, strings.Join(args, ", ")), nil
+}
+
+func hasArg(m cacheMethod, name string) bool {
+	for _, arg := range m.KeyArgs {
+		if arg.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func params(args []cacheArg) string {
+	parts := make([]string, 0, len(args))
+	for _, arg := range args {
+		parts = append(parts, fmt.Sprintf("%s %s", arg.Name, arg.TypeName))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func callArgs(args []cacheArg) string {
+	parts := make([]string, 0, len(args))
+	for _, arg := range args {
+		if strings.HasPrefix(arg.TypeName, "...") {
+			parts = append(parts, arg.Name+"...")
+		} else {
+			parts = append(parts, arg.Name)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func results(m cacheMethod) string {
+	if len(m.Results) <= 1 {
+		return strings.Join(m.Results, "")
+	}
+	return fmt.Sprintf("(%s)", strings.Join(m.Results, ", "))
+}
+
+func resultVars(m cacheMethod) string {
+	vars := make([]string, 0, len(m.Results))
+	for idx := range m.Results {
+		if idx == len(m.Results)-1 {
+			vars = append(vars, "err")
+		} else {
+			vars = append(vars, fmt.Sprintf("result%d", idx))
+		}
+	}
+	return strings.Join(vars, ", ")
+}
+
+func zeroResult(m cacheMethod) string {
+	return m.Results[0]
+}
@@ -0,0 +1,110 @@
+package cache
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func cleanup() {
+	os.Remove(generationUtil.Prefixed("./testData/cachedPersonRepository.go"))
+}
+
+func personRepository(getDocLines ...string) []model.Interface {
+	return []model.Interface{
+		{
+			PackageName: "testData",
+			Name:        "PersonRepository",
+			Methods: []model.Operation{
+				{
+					DocLines:   getDocLines,
+					Name:       "GetPerson",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}},
+					OutputArgs: []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+				},
+				{
+					DocLines:   []string{`// @CacheEvict( keys = "person:{uid}" )`},
+					Name:       "UpdatePerson",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}, {Name: "person", TypeName: "Person"}},
+					OutputArgs: []model.Field{{TypeName: "error"}},
+				},
+				{
+					Name:       "CountPersons",
+					InputArgs:  []model.Field{{TypeName: "context.Context"}},
+					OutputArgs: []model.Field{{TypeName: "int"}, {TypeName: "error"}},
+				},
+			},
+		},
+	}
+}
+
+func TestGenerateForCache(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Interfaces: personRepository(`// @Cached( ttl = "5m", key = "person:{uid}" )`)}, generator.Config{InputDir: "testData"})
+	assert.Nil(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/cachedPersonRepository.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `func NewCachedPersonRepository(next PersonRepository, client *redis.Client) *CachedPersonRepository {`)
+	assert.Contains(t, string(data), `func (cache *CachedPersonRepository) GetPerson(c context.Context, uid string) (*Person, error) {`)
+	assert.Contains(t, string(data), `key := fmt.Sprintf("person:%v", uid)`)
+	assert.Contains(t, string(data), `cache.client.Set(c, key, data, 300000*time.Millisecond)`)
+	assert.Contains(t, string(data), `func (cache *CachedPersonRepository) InvalidateGetPerson(c context.Context, uid string) error {`)
+	assert.Contains(t, string(data), `err := cache.next.UpdatePerson(c, uid, person)`)
+	assert.Contains(t, string(data), `cache.client.Del(c, fmt.Sprintf("person:%v", uid))`)
+	assert.Contains(t, string(data), `func (cache *CachedPersonRepository) CountPersons(arg0 context.Context) (int, error) {
+	return cache.next.CountPersons(arg0)
+}`)
+}
+
+func TestGenerateForCacheWithDefaultKey(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Interfaces: personRepository(`// @Cached()`)}, generator.Config{InputDir: "testData"})
+	assert.Nil(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/cachedPersonRepository.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `key := fmt.Sprintf("PersonRepository.GetPerson:%v", uid)`)
+	assert.Contains(t, string(data), `600000*time.Millisecond`)
+}
+
+func TestGenerateForCacheWithUnknownKeyArgument(t *testing.T) {
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Interfaces: personRepository(`// @Cached( key = "person:{id}" )`)}, generator.Config{InputDir: "testData"})
+	assert.Error(t, err)
+}
+
+func TestGenerateForCacheWithTemplateOverride(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Interfaces: personRepository(`// @Cached()`)}, generator.Config{
+		InputDir:          "testData",
+		TemplateOverrides: map[string]string{"cache": "// custom cache for {{.Interface.Name}}\n\npackage {{.PackageName}}\n"},
+	})
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/cachedPersonRepository.go"))
+	assert.NoError(t, err)
+	assert.Equal(t, "// custom cache for PersonRepository\n\npackage testData\n", string(data))
+}
+
+func TestGenerateForCacheWithBrokenTemplateOverrideFails(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Interfaces: personRepository(`// @Cached()`)}, generator.Config{
+		InputDir:          "testData",
+		TemplateOverrides: map[string]string{"cache": "{{.Unknown}}"},
+	})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Error generating cache for interface PersonRepository:")
+}
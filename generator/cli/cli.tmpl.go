@@ -0,0 +1,75 @@
+package cli
+
+const cliTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+
+package {{.PackageName}}
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/spf13/cobra"
+)
+
+{{ $interface := .Interface.Name -}}
+{{ $needsRequestContext := .NeedsRequestContext -}}
+
+// New{{$interface}}Command returns the cobra-command that exposes the @CliCommand-methods of {{$interface}}
+func New{{$interface}}Command(service {{$interface}}{{if $needsRequestContext}}, requestContext func(c context.Context) request.Context{{end}}) *cobra.Command {
+	cmd := &cobra.Command{
+		Use: "{{.Use}}",
+	}
+{{- range .Commands }}
+	cmd.AddCommand(new{{$interface}}{{.Name}}Command(service{{if $needsRequestContext}}, requestContext{{end}}))
+{{- end }}
+	return cmd
+}
+
+{{range .Commands -}}
+func new{{$interface}}{{.Name}}Command(service {{$interface}}{{if $needsRequestContext}}, requestContext func(c context.Context) request.Context{{end}}) *cobra.Command {
+{{- range .Vars }}
+	var {{.Name}} {{.TypeName}}
+{{- end }}
+
+	cmd := &cobra.Command{
+		Use:   {{Quoted .Use}},
+		Short: {{Quoted .Short}},
+		Long:  {{Quoted .Long}},
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := cmd.Context()
+			if c == nil {
+				c = context.Background()
+			}
+{{- if .HasResult }}
+			result, err := service.{{.Name}}({{range $idx, $arg := .CallArgs}}{{if $idx}}, {{end}}{{$arg}}{{end}})
+			if err != nil {
+				return err
+			}
+			return write{{$interface}}CliResult(cmd, result)
+{{- else }}
+			return service.{{.Name}}({{range $idx, $arg := .CallArgs}}{{if $idx}}, {{end}}{{$arg}}{{end}})
+{{- end }}
+		},
+	}
+{{- range .Flags }}
+{{- if .Shorthand }}
+	cmd.Flags().{{.Func}}VarP({{.Target}}, {{Quoted .Name}}, {{Quoted .Shorthand}}, {{.Default}}, {{Quoted .Usage}})
+{{- else }}
+	cmd.Flags().{{.Func}}Var({{.Target}}, {{Quoted .Name}}, {{.Default}}, {{Quoted .Usage}})
+{{- end }}
+{{- if .Required }}
+	cmd.MarkFlagRequired({{Quoted .Name}})
+{{- end }}
+{{- end }}
+	return cmd
+}
+
+{{end -}}
+
+func write{{$interface}}CliResult(cmd *cobra.Command, result interface{}) error {
+	encoder := json.NewEncoder(cmd.OutOrStdout())
+	encoder.SetIndent("", "\t")
+	return encoder.Encode(result)
+}
+`
@@ -0,0 +1,44 @@
+package cliAnnotation
+
+import "github.com/MarcGrol/golangAnnotations/generator/annotation"
+
+const (
+	TypeCliCommand = "CliCommand"
+	TypeCliFlag    = "CliFlag"
+	ParamName      = "name"
+	ParamShort     = "short"
+	ParamLong      = "long"
+	ParamUsage     = "usage"
+	ParamShorthand = "shorthand"
+	ParamRequired  = "required"
+	ParamSkip      = "skip"
+)
+
+func Get() []annotation.AnnotationDescriptor {
+	return []annotation.AnnotationDescriptor{
+		{
+			Name:       TypeCliCommand,
+			ParamNames: []string{ParamName, ParamShort, ParamLong},
+			Validator:  validateCliCommandAnnotation,
+		},
+		{
+			Name:       TypeCliFlag,
+			ParamNames: []string{ParamName, ParamUsage, ParamShorthand, ParamRequired, ParamSkip},
+			Validator:  validateCliFlagAnnotation,
+		}}
+}
+
+func validateCliCommandAnnotation(annot annotation.Annotation) bool {
+	if annot.Name == TypeCliCommand {
+		return true
+	}
+	return false
+}
+
+func validateCliFlagAnnotation(annot annotation.Annotation) bool {
+	if annot.Name == TypeCliFlag {
+		shorthand, hasShorthand := annot.Attributes[ParamShorthand]
+		return !hasShorthand || len(shorthand) == 1
+	}
+	return false
+}
@@ -0,0 +1,33 @@
+package cliAnnotation
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrectCliCommandAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	annotation, ok := registry.ResolveAnnotation(`// @CliCommand( name = "reset-password", short = "Resets the password of a user" )`)
+	assert.True(t, ok)
+	assert.Equal(t, TypeCliCommand, annotation.Name)
+	assert.Equal(t, "reset-password", annotation.Attributes[ParamName])
+	assert.Equal(t, "Resets the password of a user", annotation.Attributes[ParamShort])
+}
+
+func TestCorrectCliFlagAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	annotation, ok := registry.ResolveAnnotation(`// @CliFlag( shorthand = "u", required = "true" )`)
+	assert.True(t, ok)
+	assert.Equal(t, "u", annotation.Attributes[ParamShorthand])
+}
+
+func TestCliFlagAnnotationWithLongShorthand(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	_, ok := registry.ResolveAnnotation(`// @CliFlag( shorthand = "user" )`)
+	assert.False(t, ok)
+}
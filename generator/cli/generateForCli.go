@@ -0,0 +1,259 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/MarcGrol/golangAnnotations/generator/cli/cliAnnotation"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+type cliContext struct {
+	PackageName         string
+	Interface           model.Interface
+	Use                 string
+	Commands            []cliCommand
+	NeedsRequestContext bool
+}
+
+type cliCommand struct {
+	Name      string
+	Use       string
+	Short     string
+	Long      string
+	Vars      []cliVar
+	Flags     []cliFlag
+	CallArgs  []string
+	HasResult bool
+}
+
+type cliVar struct {
+	Name     string
+	TypeName string
+}
+
+type cliFlag struct {
+	Func      string
+	Target    string
+	Name      string
+	Shorthand string
+	Default   string
+	Usage     string
+	Required  bool
+}
+
+// flagTypes maps the supported go-types onto the corresponding pflag-function and zero-value
+var flagTypes = map[string]struct {
+	function string
+	zero     string
+}{
+	"string":        {"String", `""`},
+	"bool":          {"Bool", "false"},
+	"int":           {"Int", "0"},
+	"int32":         {"Int32", "0"},
+	"int64":         {"Int64", "0"},
+	"float64":       {"Float64", "0"},
+	"time.Duration": {"Duration", "0"},
+	"[]string":      {"StringSlice", "nil"},
+	"[]int":         {"IntSlice", "nil"},
+}
+
+type Generator struct {
+}
+
+func NewGenerator() generator.Generator {
+	return &Generator{}
+}
+
+func (eg *Generator) Name() string {
+	return "cli"
+}
+
+func (eg *Generator) GetTemplates() []generator.Template {
+	return []generator.Template{
+		{Name: "cli", APIVersion: 1, Text: cliTemplate},
+	}
+}
+
+func (eg *Generator) GetAnnotations() []annotation.AnnotationDescriptor {
+	return cliAnnotation.Get()
+}
+
+func (eg *Generator) Generate(parsedSource model.ParsedSources, config generator.Config) ([]generator.OutputFile, error) {
+	return generate(config, parsedSource.Interfaces, parsedSource.Structs)
+}
+
+func generate(config generator.Config, interfaces []model.Interface, structs []model.Struct) ([]generator.OutputFile, error) {
+	packageName, err := generationUtil.GetPackageNameForInterfaces(interfaces)
+	if packageName == "" || err != nil {
+		return nil, err
+	}
+	targetDir, err := generationUtil.DetermineTargetPath(config.InputDir, packageName)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []generator.OutputFile{}
+
+	for _, i := range interfaces {
+		commands, needsRequestContext, err := getCommands(i, structs)
+		if err != nil {
+			return nil, err
+		}
+		if len(commands) == 0 {
+			continue
+		}
+
+		file, err := generationUtil.Generate(generationUtil.Info{
+			Src:            fmt.Sprintf("%s.%s", i.PackageName, i.Name),
+			TypeName:       i.Name,
+			TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/cli%s.go", targetDir, i.Name)),
+			TemplateName:   "cli",
+			TemplateString: cliTemplate,
+			Config:         config,
+			FuncMap:        customTemplateFuncs,
+			Data: cliContext{
+				PackageName:         packageName,
+				Interface:           i,
+				Use:                 generationUtil.KebabCase(i.Name),
+				Commands:            commands,
+				NeedsRequestContext: needsRequestContext,
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Error generating cli for interface %s:%w", i.Name, err)
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+var customTemplateFuncs = template.FuncMap{
+	"Quoted": strconv.Quote,
+}
+
+func IsCliCommand(o model.Operation) bool {
+	annotations := annotation.NewRegistry(cliAnnotation.Get())
+	_, ok := annotations.ResolveAnnotationByName(o.DocLines, cliAnnotation.TypeCliCommand)
+	return ok
+}
+
+func getCommands(i model.Interface, structs []model.Struct) ([]cliCommand, bool, error) {
+	annotations := annotation.NewRegistry(cliAnnotation.Get())
+
+	needsRequestContext := false
+	commands := []cliCommand{}
+	for _, o := range i.Methods {
+		ann, ok := annotations.ResolveAnnotationByName(o.DocLines, cliAnnotation.TypeCliCommand)
+		if !ok {
+			continue
+		}
+		if len(o.OutputArgs) == 0 || o.OutputArgs[len(o.OutputArgs)-1].TypeName != "error" || len(o.OutputArgs) > 2 {
+			return nil, false, generator.OperationError(o, i.Name, cliAnnotation.TypeCliCommand, "Cli-command %s.%s must return an error, optionally preceded by a result", i.Name, o.Name)
+		}
+
+		command := cliCommand{
+			Name:      o.Name,
+			Use:       generationUtil.KebabCase(o.Name),
+			Short:     ann.Attributes[cliAnnotation.ParamShort],
+			Long:      ann.Attributes[cliAnnotation.ParamLong],
+			HasResult: len(o.OutputArgs) == 2,
+		}
+		if name := ann.Attributes[cliAnnotation.ParamName]; name != "" {
+			command.Use = name
+		}
+
+		for idx, arg := range o.InputArgs {
+			switch {
+			case arg.TypeName == "context.Context":
+				command.CallArgs = append(command.CallArgs, "c")
+			case arg.TypeName == "request.Context":
+				command.CallArgs = append(command.CallArgs, "requestContext(c)")
+				needsRequestContext = true
+			default:
+				name := arg.Name
+				if name == "" || name == "_" {
+					name = fmt.Sprintf("arg%d", idx)
+				}
+				if s, found := findStruct(structs, arg.DereferencedTypeName()); found {
+					command.Vars = append(command.Vars, cliVar{Name: name, TypeName: s.Name})
+					command.Flags = append(command.Flags, getStructFlags(name, s)...)
+					if arg.IsPointer() {
+						command.CallArgs = append(command.CallArgs, "&"+name)
+					} else {
+						command.CallArgs = append(command.CallArgs, name)
+					}
+					continue
+				}
+				flagType, supported := flagTypes[arg.TypeName]
+				if !supported {
+					return nil, false, generator.OperationError(o, i.Name, cliAnnotation.TypeCliCommand, "Cli-command %s.%s: argument %s of type %s cannot be mapped onto a flag", i.Name, o.Name, name, arg.TypeName)
+				}
+				command.Vars = append(command.Vars, cliVar{Name: name, TypeName: arg.TypeName})
+				command.Flags = append(command.Flags, cliFlag{
+					Func:     flagType.function,
+					Target:   "&" + name,
+					Name:     generationUtil.KebabCase(name),
+					Default:  flagType.zero,
+					Required: true,
+				})
+				command.CallArgs = append(command.CallArgs, name)
+			}
+		}
+		commands = append(commands, command)
+	}
+	return commands, needsRequestContext, nil
+}
+
+// getStructFlags maps the exported fields of an input-struct onto flags: fields of unsupported types are ignored
+func getStructFlags(varName string, s model.Struct) []cliFlag {
+	annotations := annotation.NewRegistry(cliAnnotation.Get())
+
+	flags := []cliFlag{}
+	for _, f := range s.Fields {
+		if f.Name == "" || !unicode.IsUpper([]rune(f.Name)[0]) {
+			continue
+		}
+		flagType, supported := flagTypes[f.TypeName]
+		if !supported {
+			continue
+		}
+		flag := cliFlag{
+			Func:    flagType.function,
+			Target:  fmt.Sprintf("&%s.%s", varName, f.Name),
+			Name:    generationUtil.KebabCase(f.Name),
+			Default: flagType.zero,
+			Usage:   strings.TrimSpace(strings.TrimPrefix(strings.Join(f.CommentLines, " "), "//")),
+		}
+		if ann, ok := annotations.ResolveAnnotationByName(f.DocLines, cliAnnotation.TypeCliFlag); ok {
+			if ann.Attributes[cliAnnotation.ParamSkip] == "true" {
+				continue
+			}
+			if name := ann.Attributes[cliAnnotation.ParamName]; name != "" {
+				flag.Name = name
+			}
+			if usage := ann.Attributes[cliAnnotation.ParamUsage]; usage != "" {
+				flag.Usage = usage
+			}
+			flag.Shorthand = ann.Attributes[cliAnnotation.ParamShorthand]
+			flag.Required = ann.Attributes[cliAnnotation.ParamRequired] == "true"
+		}
+		flags = append(flags, flag)
+	}
+	return flags
+}
+
+func findStruct(structs []model.Struct, name string) (model.Struct, bool) {
+	for _, s := range structs {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return model.Struct{}, false
+}
@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func cleanup() {
+	os.Remove(generationUtil.Prefixed("./testData/cliAdminService.go"))
+}
+
+func TestGenerateForCli(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	i := []model.Interface{
+		{
+			PackageName: "testData",
+			Name:        "AdminService",
+			Methods: []model.Operation{
+				{
+					DocLines:   []string{`// @CliCommand( short = "Resets the password of a user" )`},
+					Name:       "ResetPassword",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "rc", TypeName: "request.Context"}, {Name: "req", TypeName: "ResetPasswordRequest"}},
+					OutputArgs: []model.Field{{TypeName: "*ResetPasswordResult"}, {TypeName: "error"}},
+				},
+				{
+					DocLines:   []string{`// @CliCommand( name = "purge" )`},
+					Name:       "PurgeUser",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "userUID", TypeName: "string"}},
+					OutputArgs: []model.Field{{TypeName: "error"}},
+				},
+				{
+					Name:       "Internal",
+					OutputArgs: []model.Field{{TypeName: "error"}},
+				},
+			},
+		},
+	}
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			Name:        "ResetPasswordRequest",
+			Fields: []model.Field{
+				{Name: "UserUID", TypeName: "string", DocLines: []string{`// @CliFlag( shorthand = "u", required = "true" )`}},
+				{Name: "Notify", TypeName: "bool", CommentLines: []string{"// send an email"}},
+				{Name: "Address", TypeName: "Address"},
+			},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Interfaces: i, Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Nil(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/cliAdminService.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `func NewAdminServiceCommand(service AdminService, requestContext func(c context.Context) request.Context) *cobra.Command {`)
+	assert.Contains(t, string(data), `Use: "admin-service",`)
+	assert.Contains(t, string(data), `var req ResetPasswordRequest`)
+	assert.Contains(t, string(data), `result, err := service.ResetPassword(c, requestContext(c), req)`)
+	assert.Contains(t, string(data), `cmd.Flags().StringVarP(&req.UserUID, "user-uid", "u", "", "")`)
+	assert.Contains(t, string(data), `cmd.MarkFlagRequired("user-uid")`)
+	assert.Contains(t, string(data), `cmd.Flags().BoolVar(&req.Notify, "notify", false, "send an email")`)
+	assert.NotContains(t, string(data), `address`)
+	assert.Contains(t, string(data), `Use:   "purge",`)
+	assert.Contains(t, string(data), `return service.PurgeUser(c, userUID)`)
+	assert.NotContains(t, string(data), `Internal`)
+}
+
+func TestGenerateForCliWithUnsupportedArgument(t *testing.T) {
+	i := []model.Interface{
+		{
+			PackageName: "testData",
+			Name:        "AdminService",
+			Methods: []model.Operation{
+				{
+					DocLines:   []string{`// @CliCommand()`},
+					Name:       "Import",
+					InputArgs:  []model.Field{{Name: "data", TypeName: "map[string]string"}},
+					OutputArgs: []model.Field{{TypeName: "error"}},
+				},
+			},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Interfaces: i}, generator.Config{InputDir: "testData"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,219 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build/constraint"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+// producer is a rendered file with the generator that rendered it, or a parsed source when it has no generator
+type producer struct {
+	generatorName string
+	file          OutputFile
+	line          int
+}
+
+func (p producer) String() string {
+	if p.generatorName == "" {
+		return fmt.Sprintf("%s:%d", p.file.Filename, p.line)
+	}
+	return fmt.Sprintf("%s (generator %s)", p.file.Filename, describeProducer(p))
+}
+
+// source is the struct or interface, or otherwise the source, that the file is rendered for
+func (p producer) source() string {
+	if p.file.TypeName != "" {
+		return p.file.TypeName
+	}
+	return p.file.Src
+}
+
+// DetectCollisions checks the rendered files of all tasks before they are written: it returns an error for every
+// file that is rendered more than once and for every top-level symbol that more than one generated go-file of a
+// package declares, instead of letting one silently overwrite the other or breaking the build of the package. The
+// generated symbols are also compared with the top-level declarations of the parsed sources of the tasks. Generated
+// files with different build-constraints are not compared, because they need not be compiled together.
+func DetectCollisions(tasks []Task, results []TaskResult) error {
+	collisions := Errors{}
+	files := map[string]producer{}
+	symbols := map[string]producer{}
+	sourceSymbols := map[string]producer{}
+	for _, task := range tasks {
+		for _, symbol := range sourceDeclarations(task.ParsedSources) {
+			if _, found := sourceSymbols[symbol.key]; !found {
+				sourceSymbols[symbol.key] = symbol.source
+			}
+		}
+	}
+	for idx, result := range results {
+		for _, f := range result.Files {
+			p := producer{generatorName: tasks[idx].Generator.Name(), file: f}
+			filename := filepath.Clean(f.Filename)
+			if other, found := files[filename]; found {
+				collisions = append(collisions, fmt.Errorf("File %s is generated both by generator %s and by generator %s",
+					filename, describeProducer(other), describeProducer(p)))
+				continue
+			}
+			files[filename] = p
+			if f.Fixed || filepath.Ext(f.Filename) != ".go" {
+				continue
+			}
+			for _, symbol := range declaredSymbols(f) {
+				if source, found := sourceSymbols[symbol.sourceKey]; found {
+					collisions = append(collisions, fmt.Errorf("Symbol %s of package %s is declared both by %s and by %s",
+						symbol.name, symbol.packageName, source, p))
+					continue
+				}
+				if other, found := symbols[symbol.key]; found {
+					collisions = append(collisions, fmt.Errorf("Symbol %s of package %s is declared both by %s and by %s",
+						symbol.name, symbol.packageName, other, p))
+					continue
+				}
+				symbols[symbol.key] = p
+			}
+		}
+	}
+	if len(collisions) == 0 {
+		return nil
+	}
+	return collisions
+}
+
+// describeProducer tells which generator rendered a file and for what
+func describeProducer(p producer) string {
+	if source := p.source(); source != "" {
+		return p.generatorName + " for " + source
+	}
+	return p.generatorName
+}
+
+type declaredSymbol struct {
+	key         string
+	sourceKey   string
+	name        string
+	packageName string
+}
+
+// declaredSymbols returns the top-level functions, types, variables, constants and methods of a go-file, keyed by
+// its directory, package and build-constraint. Files that do not parse are left to the formatter to report.
+func declaredSymbols(f OutputFile) []declaredSymbol {
+	file, err := parser.ParseFile(token.NewFileSet(), f.Filename, f.Content, parser.ParseComments)
+	if err != nil {
+		return nil
+	}
+	packageScope := filepath.Dir(filepath.Clean(f.Filename)) + "|" + file.Name.Name
+	scope := packageScope + "|" + buildConstraint(file)
+	names := []string{}
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Recv == nil {
+				if d.Name.Name != "init" {
+					names = append(names, d.Name.Name)
+				}
+			} else if receiver := receiverTypeName(d.Recv.List[0].Type); receiver != "" {
+				names = append(names, receiver+"."+d.Name.Name)
+			}
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					names = append(names, s.Name.Name)
+				case *ast.ValueSpec:
+					for _, ident := range s.Names {
+						names = append(names, ident.Name)
+					}
+				}
+			}
+		}
+	}
+	symbols := []declaredSymbol{}
+	for _, name := range names {
+		if name == "_" {
+			continue
+		}
+		symbols = append(symbols, declaredSymbol{key: scope + "|" + name, sourceKey: packageScope + "|" + name, name: name,
+			packageName: file.Name.Name})
+	}
+	return symbols
+}
+
+type sourceDeclaration struct {
+	key    string
+	source producer
+}
+
+// sourceDeclarations returns the top-level types, functions, methods and enum-literals of the parsed sources, keyed by
+// their directory and package. The build-constraints of the sources are unknown, so they are compared with the
+// generated files of every build-constraint. Parsed files that are generated themselves, are skipped: they are about
+// to be replaced.
+func sourceDeclarations(parsedSources model.ParsedSources) []sourceDeclaration {
+	declarations := []sourceDeclaration{}
+	declare := func(filename string, line int, packageName string, name string) {
+		if filename == "" || strings.HasPrefix(filepath.Base(filename), GenfilePrefix) {
+			return
+		}
+		declarations = append(declarations, sourceDeclaration{
+			key:    filepath.Dir(filepath.Clean(filename)) + "|" + packageName + "|" + name,
+			source: producer{file: OutputFile{Filename: filename}, line: line},
+		})
+	}
+	for _, s := range parsedSources.Structs {
+		declare(s.Filename, s.Line, s.PackageName, s.Name)
+	}
+	for _, i := range parsedSources.Interfaces {
+		declare(i.Filename, i.Line, i.PackageName, i.Name)
+	}
+	for _, td := range parsedSources.Typedefs {
+		declare(td.Filename, td.Line, td.PackageName, td.Name)
+	}
+	for _, e := range parsedSources.Enums {
+		for _, literal := range e.EnumLiterals {
+			declare(e.Filename, e.Line, e.PackageName, literal.Name)
+		}
+	}
+	for _, o := range parsedSources.Operations {
+		name := o.Name
+		if o.RelatedStruct != nil {
+			name = strings.TrimPrefix(o.RelatedStruct.TypeName, "*") + "." + o.Name
+		}
+		declare(o.Filename, o.Line, o.PackageName, name)
+	}
+	return declarations
+}
+
+func receiverTypeName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		return receiverTypeName(e.X)
+	case *ast.ParenExpr:
+		return receiverTypeName(e.X)
+	case *ast.Ident:
+		return e.Name
+	}
+	return ""
+}
+
+// buildConstraint returns the normalized //go:build-constraint of a file, or "" when it has none
+func buildConstraint(file *ast.File) string {
+	for _, group := range file.Comments {
+		if group.Pos() > file.Package {
+			break
+		}
+		for _, comment := range group.List {
+			if constraint.IsGoBuild(comment.Text) {
+				expr, err := constraint.Parse(comment.Text)
+				if err == nil {
+					return expr.String()
+				}
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,72 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func collisionTasks(names ...string) []Task {
+	tasks := []Task{}
+	for _, name := range names {
+		tasks = append(tasks, Task{Generator: namedGenerator{name: name}})
+	}
+	return tasks
+}
+
+func TestDetectCollisionsWithoutCollisions(t *testing.T) {
+	err := DetectCollisions(collisionTasks("a", "b"), []TaskResult{
+		{Files: []OutputFile{{Filename: "x/gen_a.go", Content: []byte("package x\n\nfunc A() {}\n\nfunc (t *T) A() {}\n")}}},
+		{Files: []OutputFile{
+			{Filename: "x/gen_b.go", Content: []byte("package x\n\nfunc init() {}\n\nvar _ = 1\n\ntype T struct{}\n")},
+			{Filename: "y/gen_b.go", Content: []byte("package y\n\nfunc A() {}\n")},
+		}},
+	})
+	assert.NoError(t, err)
+}
+
+func TestDetectCollisionsOfFiles(t *testing.T) {
+	err := DetectCollisions(collisionTasks("a", "b"), []TaskResult{
+		{Files: []OutputFile{{Filename: "x/gen_a.json", TypeName: "Person"}}},
+		{Files: []OutputFile{{Filename: "./x/gen_a.json", Src: "x"}}},
+	})
+	assert.EqualError(t, err, "File x/gen_a.json is generated both by generator a for Person and by generator b for x")
+}
+
+func TestDetectCollisionsOfSymbols(t *testing.T) {
+	err := DetectCollisions(collisionTasks("a", "b"), []TaskResult{
+		{Files: []OutputFile{{Filename: "x/gen_a.go", TypeName: "Person", Content: []byte("package x\n\nconst A = 1\n\nfunc (p Person) Get() {}\n")}}},
+		{Files: []OutputFile{{Filename: "x/gen_b.go", Content: []byte("package x\n\nfunc A() {}\n\nfunc (p *Person) Get() {}\n")}}},
+	})
+	assert.EqualError(t, err, "Symbol A of package x is declared both by x/gen_a.go (generator a for Person) and by x/gen_b.go (generator b)\n"+
+		"Symbol Person.Get of package x is declared both by x/gen_a.go (generator a for Person) and by x/gen_b.go (generator b)")
+}
+
+func TestDetectCollisionsSkipsOtherBuildConstraints(t *testing.T) {
+	err := DetectCollisions(collisionTasks("a", "b"), []TaskResult{
+		{Files: []OutputFile{{Filename: "x/gen_a.go", Content: []byte("//go:build appengine\n\npackage x\n\nfunc A() {}\n")}}},
+		{Files: []OutputFile{{Filename: "x/gen_b.go", Content: []byte("//go:build !appengine\n\npackage x\n\nfunc A() {}\n")}}},
+	})
+	assert.NoError(t, err)
+}
+
+func TestDetectCollisionsWithSources(t *testing.T) {
+	tasks := collisionTasks("a")
+	tasks[0].ParsedSources = model.ParsedSources{
+		Structs: []model.Struct{
+			{PackageName: "x", Filename: "x/person.go", Line: 3, Name: "PersonRepository"},
+			{PackageName: "y", Filename: "y/person.go", Line: 3, Name: "Person"},
+		},
+		Operations: []model.Operation{
+			{PackageName: "x", Filename: "x/person.go", Line: 8, Name: "Find", RelatedStruct: &model.Field{TypeName: "*PersonRepository"}},
+			{PackageName: "x", Filename: "x/gen_old.go", Line: 3, Name: "Old"},
+		},
+	}
+	err := DetectCollisions(tasks, []TaskResult{
+		{Files: []OutputFile{{Filename: "x/gen_a.go", TypeName: "Person", Content: []byte("//go:build go1.19\n\npackage x\n\n" +
+			"type PersonRepository struct{}\n\nfunc (r PersonRepository) Find() {}\n\nfunc Old() {}\n\ntype Person struct{}\n")}}},
+	})
+	assert.EqualError(t, err, "Symbol PersonRepository of package x is declared both by x/person.go:3 and by x/gen_a.go (generator a for Person)\n"+
+		"Symbol PersonRepository.Find of package x is declared both by x/person.go:8 and by x/gen_a.go (generator a for Person)")
+}
@@ -0,0 +1,129 @@
+package config
+
+const configTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+
+package {{.PackageName}}
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+{{ $config := .Config.Name -}}
+
+// {{$config}}SampleEnv documents all settings of {{$config}} in .env format
+const {{$config}}SampleEnv = ` + "`" + `{{SampleEnv .Fields}}` + "`" + `
+
+func Write{{$config}}SampleEnv(w io.Writer) error {
+	_, err := io.WriteString(w, {{$config}}SampleEnv)
+	return err
+}
+
+// Load{{$config}} determines the settings of {{$config}} from (in increasing precedence):
+// defaults, the optional yaml-file, environment-variables and command-line flags
+func Load{{$config}}(args []string, yamlFilename string) (*{{$config}}, error) {
+	values := map[string]string{}
+{{- range .Fields }}
+{{- if .Default }}
+	values["{{.Name}}"] = {{Quoted .Default}}
+{{- end }}
+{{- end }}
+
+	if yamlFilename != "" {
+		yamlValues, err := read{{$config}}Yaml(yamlFilename)
+		if err != nil {
+			return nil, err
+		}
+{{- range .Fields }}
+		if value, found := yamlValues["{{.Yaml}}"]; found {
+			values["{{.Name}}"] = value
+		}
+{{- end }}
+	}
+
+{{- range .Fields }}
+	if value, found := os.LookupEnv("{{.Env}}"); found {
+		values["{{.Name}}"] = value
+	}
+{{- end }}
+
+	flagFields := map[string]string{
+{{- range .Fields }}
+		"{{.Flag}}": "{{.Name}}",
+{{- end }}
+	}
+	flags := flag.NewFlagSet("{{$config}}", flag.ContinueOnError)
+{{- range .Fields }}
+	flags.String({{Quoted .Flag}}, {{Quoted .Default}}, {{Quoted .Usage}})
+{{- end }}
+	err := flags.Parse(args)
+	if err != nil {
+		return nil, err
+	}
+	flags.Visit(func(f *flag.Flag) {
+		values[flagFields[f.Name]] = f.Value.String()
+	})
+
+	cfg := &{{$config}}{}
+	problems := []string{}
+{{- range .Fields }}
+	if value, found := values["{{.Name}}"]; found {
+		err := func() error {
+			{{ParseValue .}}
+			return nil
+		}()
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s has invalid value '%s': %s", {{Quoted (Describe .)}}, value, err))
+		}
+	}{{if .Required}} else {
+		problems = append(problems, {{Quoted (Describe .)}}+" is required")
+	}{{end}}
+{{- end }}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("Invalid configuration {{$config}}: %s", strings.Join(problems, ", "))
+	}
+	return cfg, nil
+}
+
+// read{{$config}}Yaml flattens the yaml-file into dotted keys with string-values
+func read{{$config}}Yaml(filename string) (map[string]string, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading config-file %s: %s", filename, err)
+	}
+	document := map[string]interface{}{}
+	err = yaml.Unmarshal(data, &document)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing config-file %s: %s", filename, err)
+	}
+
+	values := map[string]string{}
+	var flatten func(prefix string, node map[string]interface{})
+	flatten = func(prefix string, node map[string]interface{}) {
+		for key, value := range node {
+			switch v := value.(type) {
+			case map[string]interface{}:
+				flatten(prefix+key+".", v)
+			case []interface{}:
+				parts := []string{}
+				for _, part := range v {
+					parts = append(parts, fmt.Sprint(part))
+				}
+				values[prefix+key] = strings.Join(parts, ",")
+			default:
+				values[prefix+key] = fmt.Sprint(v)
+			}
+		}
+	}
+	flatten("", document)
+	return values, nil
+}
+`
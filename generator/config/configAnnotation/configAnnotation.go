@@ -0,0 +1,44 @@
+package configAnnotation
+
+import "github.com/MarcGrol/golangAnnotations/generator/annotation"
+
+const (
+	TypeConfig      = "Config"
+	TypeConfigField = "ConfigField"
+	ParamPrefix     = "prefix"
+	ParamEnv        = "env"
+	ParamFlag       = "flag"
+	ParamYaml       = "yaml"
+	ParamDefault    = "default"
+	ParamRequired   = "required"
+	ParamUsage      = "usage"
+)
+
+func Get() []annotation.AnnotationDescriptor {
+	return []annotation.AnnotationDescriptor{
+		{
+			Name:       TypeConfig,
+			ParamNames: []string{ParamPrefix},
+			Validator:  validateConfigAnnotation,
+		},
+		{
+			Name:       TypeConfigField,
+			ParamNames: []string{ParamEnv, ParamFlag, ParamYaml, ParamDefault, ParamRequired, ParamUsage},
+			Validator:  validateConfigFieldAnnotation,
+		}}
+}
+
+func validateConfigAnnotation(annot annotation.Annotation) bool {
+	if annot.Name == TypeConfig {
+		return true
+	}
+	return false
+}
+
+func validateConfigFieldAnnotation(annot annotation.Annotation) bool {
+	if annot.Name == TypeConfigField {
+		required, hasRequired := annot.Attributes[ParamRequired]
+		return !hasRequired || required == "true" || required == "false"
+	}
+	return false
+}
@@ -0,0 +1,33 @@
+package configAnnotation
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrectConfigAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	annotation, ok := registry.ResolveAnnotation(`// @Config( prefix = "APP" )`)
+	assert.True(t, ok)
+	assert.Equal(t, TypeConfig, annotation.Name)
+	assert.Equal(t, "APP", annotation.Attributes[ParamPrefix])
+}
+
+func TestCorrectConfigFieldAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	annotation, ok := registry.ResolveAnnotation(`// @ConfigField( env = "HTTP_PORT", default = "8080", required = "true" )`)
+	assert.True(t, ok)
+	assert.Equal(t, "HTTP_PORT", annotation.Attributes[ParamEnv])
+	assert.Equal(t, "8080", annotation.Attributes[ParamDefault])
+}
+
+func TestConfigFieldAnnotationWithInvalidRequired(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	_, ok := registry.ResolveAnnotation(`// @ConfigField( required = "yes" )`)
+	assert.False(t, ok)
+}
@@ -0,0 +1,218 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/MarcGrol/golangAnnotations/generator/config/configAnnotation"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+type configContext struct {
+	PackageName string
+	Config      model.Struct
+	Fields      []configField
+}
+
+// configField describes where the value of a single setting can come from
+type configField struct {
+	Name     string
+	TypeName string
+	Env      string
+	Flag     string
+	Yaml     string
+	Default  string
+	Usage    string
+	Required bool
+}
+
+type Generator struct {
+}
+
+func NewGenerator() generator.Generator {
+	return &Generator{}
+}
+
+func (eg *Generator) Name() string {
+	return "config"
+}
+
+func (eg *Generator) GetTemplates() []generator.Template {
+	return []generator.Template{
+		{Name: "config", APIVersion: 1, Text: configTemplate},
+	}
+}
+
+func (eg *Generator) GetAnnotations() []annotation.AnnotationDescriptor {
+	return configAnnotation.Get()
+}
+
+func (eg *Generator) Generate(parsedSource model.ParsedSources, config generator.Config) ([]generator.OutputFile, error) {
+	return generate(config, parsedSource.Structs)
+}
+
+func generate(config generator.Config, structs []model.Struct) ([]generator.OutputFile, error) {
+	packageName, err := generationUtil.GetPackageNameForStructs(structs)
+	if packageName == "" || err != nil {
+		return nil, err
+	}
+	targetDir, err := generationUtil.DetermineTargetPath(config.InputDir, packageName)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []generator.OutputFile{}
+
+	for _, s := range structs {
+		if !IsConfig(s) {
+			continue
+		}
+		fields, err := GetConfigFields(s)
+		if err != nil {
+			return nil, err
+		}
+
+		file, err := generationUtil.Generate(generationUtil.Info{
+			Src:            fmt.Sprintf("%s.%s", s.PackageName, s.Name),
+			TypeName:       s.Name,
+			TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/config%s.go", targetDir, s.Name)),
+			TemplateName:   "config",
+			TemplateString: configTemplate,
+			Config:         config,
+			FuncMap:        customTemplateFuncs,
+			Data: configContext{
+				PackageName: packageName,
+				Config:      s,
+				Fields:      fields,
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Error generating config-loader for %s:%w", s.Name, err)
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+var customTemplateFuncs = template.FuncMap{
+	"ParseValue": ParseValue,
+	"SampleEnv":  SampleEnv,
+	"Describe":   describe,
+	"Quoted":     strconv.Quote,
+}
+
+func IsConfig(s model.Struct) bool {
+	annotations := annotation.NewRegistry(configAnnotation.Get())
+	_, ok := annotations.ResolveAnnotationByName(s.DocLines, configAnnotation.TypeConfig)
+	return ok
+}
+
+func getPrefix(s model.Struct) string {
+	annotations := annotation.NewRegistry(configAnnotation.Get())
+	if ann, ok := annotations.ResolveAnnotationByName(s.DocLines, configAnnotation.TypeConfig); ok {
+		if prefix := ann.Attributes[configAnnotation.ParamPrefix]; prefix != "" {
+			return strings.ToUpper(prefix) + "_"
+		}
+	}
+	return ""
+}
+
+func isSupportedType(typeName string) bool {
+	switch typeName {
+	case "string", "bool", "int", "int64", "float64", "time.Duration", "[]string":
+		return true
+	}
+	return false
+}
+
+// GetConfigFields returns the settings of a @Config-struct: exported fields of unsupported types
+// are ignored unless explicitly annotated with @ConfigField
+func GetConfigFields(s model.Struct) ([]configField, error) {
+	annotations := annotation.NewRegistry(configAnnotation.Get())
+	prefix := getPrefix(s)
+
+	fields := []configField{}
+	for _, f := range s.Fields {
+		if f.Name == "" || !unicode.IsUpper([]rune(f.Name)[0]) {
+			continue
+		}
+		ann, annotated := annotations.ResolveAnnotationByName(f.DocLines, configAnnotation.TypeConfigField)
+		if !isSupportedType(f.TypeName) {
+			if annotated {
+				return nil, generator.FieldError(s, f, configAnnotation.TypeConfigField, "Config-field %s.%s has unsupported type %s", s.Name, f.Name, f.TypeName)
+			}
+			continue
+		}
+		field := configField{
+			Name:     f.Name,
+			TypeName: f.TypeName,
+			Env:      prefix + strings.ToUpper(generationUtil.SnakeCase(f.Name)),
+			Flag:     generationUtil.KebabCase(f.Name),
+			Yaml:     generationUtil.SnakeCase(f.Name),
+			Usage:    strings.TrimSpace(strings.TrimPrefix(strings.Join(f.CommentLines, " "), "//")),
+		}
+		if annotated {
+			if env := ann.Attributes[configAnnotation.ParamEnv]; env != "" {
+				field.Env = env
+			}
+			if flag := ann.Attributes[configAnnotation.ParamFlag]; flag != "" {
+				field.Flag = flag
+			}
+			if yaml := ann.Attributes[configAnnotation.ParamYaml]; yaml != "" {
+				field.Yaml = yaml
+			}
+			if usage := ann.Attributes[configAnnotation.ParamUsage]; usage != "" {
+				field.Usage = usage
+			}
+			field.Default = ann.Attributes[configAnnotation.ParamDefault]
+			field.Required = ann.Attributes[configAnnotation.ParamRequired] == "true"
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// ParseValue returns the go-statements that convert the string "value" and store it in the field of cfg
+func ParseValue(f configField) string {
+	switch f.TypeName {
+	case "bool":
+		return fmt.Sprintf("parsed, err := strconv.ParseBool(value)\nif err != nil {\nreturn err\n}\ncfg.%s = parsed", f.Name)
+	case "int":
+		return fmt.Sprintf("parsed, err := strconv.Atoi(value)\nif err != nil {\nreturn err\n}\ncfg.%s = parsed", f.Name)
+	case "int64":
+		return fmt.Sprintf("parsed, err := strconv.ParseInt(value, 10, 64)\nif err != nil {\nreturn err\n}\ncfg.%s = parsed", f.Name)
+	case "float64":
+		return fmt.Sprintf("parsed, err := strconv.ParseFloat(value, 64)\nif err != nil {\nreturn err\n}\ncfg.%s = parsed", f.Name)
+	case "time.Duration":
+		return fmt.Sprintf("parsed, err := time.ParseDuration(value)\nif err != nil {\nreturn err\n}\ncfg.%s = parsed", f.Name)
+	case "[]string":
+		return fmt.Sprintf("cfg.%s = []string{}\nfor _, part := range strings.Split(value, \",\") {\ncfg.%s = append(cfg.%s, strings.TrimSpace(part))\n}", f.Name, f.Name, f.Name)
+	}
+	return fmt.Sprintf("cfg.%s = value", f.Name)
+}
+
+func describe(f configField) string {
+	return fmt.Sprintf("%s (env %s, flag --%s, yaml %s)", f.Name, f.Env, f.Flag, f.Yaml)
+}
+
+// SampleEnv documents all settings in the format of a .env file
+func SampleEnv(fields []configField) string {
+	lines := []string{}
+	for _, f := range fields {
+		comment := f.Usage
+		if f.Required {
+			comment = strings.TrimSpace(comment + " (required)")
+		}
+		if comment != "" {
+			lines = append(lines, "# "+comment)
+		}
+		lines = append(lines, fmt.Sprintf("%s=%s", f.Env, f.Default))
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func cleanup() {
+	os.Remove(generationUtil.Prefixed("./testData/configServerConfig.go"))
+}
+
+func TestGenerateForConfig(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @Config( prefix = "app" )`},
+			Name:        "ServerConfig",
+			Fields: []model.Field{
+				{Name: "HTTPPort", TypeName: "int", DocLines: []string{`// @ConfigField( default = "8080", usage = "port to listen on" )`}},
+				{Name: "DatabaseURL", TypeName: "string", DocLines: []string{`// @ConfigField( env = "DATABASE_URL", required = "true" )`}},
+				{Name: "Timeout", TypeName: "time.Duration"},
+				{Name: "Origins", TypeName: "[]string"},
+				{Name: "Logger", TypeName: "*log.Logger"},
+			},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Nil(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/configServerConfig.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `func LoadServerConfig(args []string, yamlFilename string) (*ServerConfig, error) {`)
+	assert.Contains(t, string(data), `values["HTTPPort"] = "8080"`)
+	assert.Contains(t, string(data), `if value, found := yamlValues["http_port"]; found {`)
+	assert.Contains(t, string(data), `if value, found := os.LookupEnv("APP_HTTP_PORT"); found {`)
+	assert.Contains(t, string(data), `if value, found := os.LookupEnv("DATABASE_URL"); found {`)
+	assert.Contains(t, string(data), `flags.String("http-port", "8080", "port to listen on")`)
+	assert.Contains(t, string(data), `parsed, err := time.ParseDuration(value)`)
+	assert.Contains(t, string(data), `problems = append(problems, "DatabaseURL (env DATABASE_URL, flag --database-url, yaml database_url)"+" is required")`)
+	assert.Contains(t, string(data), "# port to listen on\nAPP_HTTP_PORT=8080\n# (required)\nDATABASE_URL=\nAPP_TIMEOUT=\n")
+	assert.NotContains(t, string(data), `Logger`)
+}
+
+func TestGenerateForConfigWithUnsupportedAnnotatedField(t *testing.T) {
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @Config()`},
+			Name:        "ServerConfig",
+			Fields: []model.Field{
+				{Name: "Limits", TypeName: "map[string]int", DocLines: []string{`// @ConfigField()`}},
+			},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Error(t, err)
+}
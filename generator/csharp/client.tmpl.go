@@ -0,0 +1,231 @@
+package csharp
+
+const apiClientTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+
+#nullable enable
+
+using System;
+using System.Collections.Generic;
+using System.Globalization;
+using System.Linq;
+using System.Net.Http;
+using System.Text.Json;
+using System.Text.Json.Serialization;
+using System.Threading;
+using System.Threading.Tasks;
+
+namespace {{.Namespace}};
+
+/// <summary>ApiException is the answer of a rest-operation that failed, with its http-status and body</summary>
+public sealed class ApiException : Exception
+{
+    public ApiException(int statusCode, string body)
+        : base($"http-status {statusCode}: {body}")
+    {
+        StatusCode = statusCode;
+        Body = body;
+    }
+
+    public int StatusCode { get; }
+
+    public string Body { get; }
+}
+
+/// <summary>ApiClient sends the requests of the clients, with their parameters in the format that the services parse</summary>
+internal static class ApiClient
+{
+    internal static JsonSerializerOptions DefaultJsonOptions { get; } = new JsonSerializerOptions
+    {
+        DefaultIgnoreCondition = JsonIgnoreCondition.WhenWritingNull,
+    };
+
+    internal static string Path(object value, JsonSerializerOptions options)
+    {
+        return Uri.EscapeDataString(Format(value, options) ?? "");
+    }
+
+    internal static string Query(JsonSerializerOptions options, params (string Key, object? Value)[] parameters)
+    {
+        var parts = Values(options, parameters).Select(p => Uri.EscapeDataString(p.Key) + "=" + Uri.EscapeDataString(p.Value)).ToList();
+        return parts.Count == 0 ? "" : "?" + string.Join("&", parts);
+    }
+
+    internal static FormUrlEncodedContent Form(JsonSerializerOptions options, params (string Key, object? Value)[] parameters)
+    {
+        return new FormUrlEncodedContent(Values(options, parameters).Select(p => new KeyValuePair<string?, string?>(p.Key, p.Value)));
+    }
+
+    internal static void Header(HttpRequestMessage request, string key, object? value, JsonSerializerOptions options)
+    {
+        var text = Format(value, options);
+        if (text != null)
+        {
+            // entity-tags are quoted, which the validation of if-match does not accept
+            request.Headers.TryAddWithoutValidation(key, text);
+        }
+    }
+
+    internal static async Task<HttpResponseMessage> SendAsync(HttpClient httpClient, HttpRequestMessage request, CancellationToken cancellationToken)
+    {
+        var response = await httpClient.SendAsync(request, cancellationToken).ConfigureAwait(false);
+        if (!response.IsSuccessStatusCode)
+        {
+            using (response)
+            {
+                var body = await response.Content.ReadAsStringAsync(cancellationToken).ConfigureAwait(false);
+                throw new ApiException((int)response.StatusCode, body);
+            }
+        }
+        return response;
+    }
+
+    private static IEnumerable<(string Key, string Value)> Values(JsonSerializerOptions options, (string Key, object? Value)[] parameters)
+    {
+        foreach (var (key, value) in parameters)
+        {
+            IEnumerable<string?> values = value is IEnumerable<string> list ? list : new[] { Format(value, options) };
+            foreach (var item in values)
+            {
+                if (item != null)
+                {
+                    yield return (key, item);
+                }
+            }
+        }
+    }
+
+    private static string? Format(object? value, JsonSerializerOptions options)
+    {
+        return value switch
+        {
+            null => null,
+            string text => text,
+            bool flag => flag ? "true" : "false",
+            DateTimeOffset time => time.ToString("o", CultureInfo.InvariantCulture),
+            Enum => JsonSerializer.Serialize(value, value.GetType(), options).Trim('"'),
+            IFormattable formattable => formattable.ToString(null, CultureInfo.InvariantCulture),
+            _ => value.ToString(),
+        };
+    }
+}
+`
+
+const modelsTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+
+#nullable enable
+
+using System;
+using System.Collections.Generic;
+using System.Text.Json;
+using System.Text.Json.Serialization;
+
+namespace {{.Namespace}};
+{{- range .Records}}
+{{with .Description}}
+/// <summary>{{Summary .}}</summary>
+{{- end}}
+public sealed record {{.Name}}
+{
+{{- range $idx, $property := .Properties}}
+{{- if $idx}}
+{{end}}
+    [JsonPropertyName("{{$property.JSONName}}")]
+    public {{$property.Type}}? {{$property.Name}} { get; init; }
+{{- end}}
+}
+{{- end}}
+{{- range $enum := .Enums}}
+{{with .Description}}
+/// <summary>{{Summary .}}</summary>
+{{- end}}
+[JsonConverter(typeof({{.Name}}JsonConverter))]
+public enum {{.Name}}
+{
+{{- range .Literals}}
+    {{.Name}},
+{{- end}}
+}
+
+/// <summary>{{.Name}}JsonConverter converts {{.Name}} from and to the names of its literals in json</summary>
+internal sealed class {{.Name}}JsonConverter : JsonConverter<{{.Name}}>
+{
+    public override {{.Name}} Read(ref Utf8JsonReader reader, Type typeToConvert, JsonSerializerOptions options)
+    {
+        return reader.GetString() switch
+        {
+{{- range .Literals}}
+            "{{.JSONName}}" => {{$enum.Name}}.{{.Name}},
+{{- end}}
+            var name => throw new JsonException($"Unknown {{.Name}} {name}"),
+        };
+    }
+
+    public override void Write(Utf8JsonWriter writer, {{.Name}} value, JsonSerializerOptions options)
+    {
+        writer.WriteStringValue(value switch
+        {
+{{- range .Literals}}
+            {{$enum.Name}}.{{.Name}} => "{{.JSONName}}",
+{{- end}}
+            _ => throw new JsonException($"Unknown {{.Name}} {value}"),
+        });
+    }
+}
+{{- end}}
+`
+
+const clientTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+
+#nullable enable
+
+using System;
+using System.Collections.Generic;
+using System.Net.Http;
+using System.Net.Http.Json;
+using System.Text.Json;
+using System.Threading;
+using System.Threading.Tasks;
+
+namespace {{.Namespace}};
+
+/// <summary>{{.Class}} calls the rest-operations of {{.Service}} at the base-address of its http-client</summary>
+public sealed class {{.Class}}
+{
+    private readonly HttpClient httpClient;
+    private readonly JsonSerializerOptions jsonOptions;
+
+    public {{.Class}}(HttpClient httpClient, JsonSerializerOptions? jsonOptions = null)
+    {
+        this.httpClient = httpClient;
+        this.jsonOptions = jsonOptions ?? ApiClient.DefaultJsonOptions;
+    }
+{{- range .Operations}}
+{{with .Description}}
+    /// <summary>{{Summary .}}</summary>
+{{- end}}
+    public async {{if eq .Returns "nothing"}}Task{{else if eq .Returns "content"}}Task<{{.Result}}>{{else}}Task<{{.Result}}?>{{end}} {{.Name}}(
+{{- range .Required}}{{.Type}} {{.Name}}, {{end}}
+{{- range .Optional}}{{.Type}}? {{.Name}} = null, {{end -}}
+CancellationToken cancellationToken = default)
+    {
+        using var httpRequest = new HttpRequestMessage(new HttpMethod("{{.Method}}"), {{.Path}}
+{{- with .Query}} + ApiClient.Query(this.jsonOptions{{range .}}, ("{{.Key}}", {{.Name}}){{end}}){{end}});
+{{- range .Headers}}
+        ApiClient.Header(httpRequest, "{{.Key}}", {{.Name}}, this.jsonOptions);
+{{- end}}
+{{- with .Body}}
+        httpRequest.Content = JsonContent.Create({{.Name}}, options: this.jsonOptions);
+{{- end}}
+{{- with .Fields}}
+        httpRequest.Content = ApiClient.Form(this.jsonOptions{{range .}}, ("{{.Key}}", {{.Name}}){{end}});
+{{- end}}
+        using var httpResponse = await ApiClient.SendAsync(this.httpClient, httpRequest, cancellationToken).ConfigureAwait(false);
+{{- if eq .Returns "json"}}
+        return await httpResponse.Content.ReadFromJsonAsync<{{.Result}}?>(this.jsonOptions, cancellationToken).ConfigureAwait(false);
+{{- else if eq .Returns "content"}}
+        return await httpResponse.Content.ReadAsByteArrayAsync(cancellationToken).ConfigureAwait(false);
+{{- end}}
+    }
+{{- end}}
+}
+`
@@ -0,0 +1,30 @@
+package csharpAnnotation
+
+import (
+	"regexp"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+)
+
+const (
+	TypeCSharpClient = "CSharpClient"
+	ParamNamespace   = "namespace"
+)
+
+var namespacePattern = regexp.MustCompile(`^[A-Z][A-Za-z0-9_]*(\.[A-Z][A-Za-z0-9_]*)*$`)
+
+func Get() []annotation.AnnotationDescriptor {
+	return []annotation.AnnotationDescriptor{
+		{
+			Name:       TypeCSharpClient,
+			ParamNames: []string{ParamNamespace},
+			Validator:  validateCSharpClientAnnotation,
+		}}
+}
+
+func validateCSharpClientAnnotation(annot annotation.Annotation) bool {
+	if annot.Name == TypeCSharpClient {
+		return namespacePattern.MatchString(annot.Attributes[ParamNamespace])
+	}
+	return false
+}
@@ -0,0 +1,25 @@
+package csharpAnnotation
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrectCSharpClientAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	annotation, ok := registry.ResolveAnnotation(`// @CSharpClient( namespace = "Example.Person" )`)
+	assert.True(t, ok)
+	assert.Equal(t, TypeCSharpClient, annotation.Name)
+	assert.Equal(t, "Example.Person", annotation.Attributes[ParamNamespace])
+}
+
+func TestInvalidCSharpClientAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @CSharpClient()`}))
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @CSharpClient( namespace = "example.person" )`}))
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @CSharpClient( namespace = "Example..Person" )`}))
+}
@@ -0,0 +1,276 @@
+package csharp
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/MarcGrol/golangAnnotations/generator/csharp/csharpAnnotation"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/generator/rest"
+	"github.com/MarcGrol/golangAnnotations/generator/tenant"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+var pathParamPattern = regexp.MustCompile(`\{(\w+)\}`)
+
+// namespaceContext holds the clients of the rest-services in a namespace, with the records and enums they use
+type namespaceContext struct {
+	Namespace string
+	Clients   []clientContext
+	Records   []*csRecord
+	Enums     []*csEnum
+	types     *csTypes
+}
+
+type clientContext struct {
+	Namespace  string
+	Service    string
+	Class      string
+	Operations []clientOperation
+}
+
+type clientOperation struct {
+	Name        string
+	Description string
+	Method      string
+	// Path is the c#-expression of the path of the operation, with its path-parameters filled in
+	Path string
+	// Required and Optional are the parameters of the method, the optional ones default to null
+	Required []clientParam
+	Optional []clientParam
+	Query    []clientParam
+	Headers  []clientParam
+	Fields   []clientParam
+	Body     *clientParam
+	Result   string
+	// Returns is how the response is returned: deserialized json, the raw content or nothing
+	Returns string
+}
+
+type clientParam struct {
+	Key  string
+	Name string
+	Type string
+}
+
+const (
+	returnsNothing = "nothing"
+	returnsJSON    = "json"
+	returnsContent = "content"
+)
+
+type Generator struct {
+}
+
+func NewGenerator() generator.Generator {
+	return &Generator{}
+}
+
+func (eg *Generator) Name() string {
+	return "csharp"
+}
+
+func (eg *Generator) GetTemplates() []generator.Template {
+	return []generator.Template{
+		{Name: "csharp-api-client", APIVersion: 1, Text: apiClientTemplate},
+		{Name: "csharp-models", APIVersion: 1, Text: modelsTemplate},
+		{Name: "csharp-client", APIVersion: 1, Text: clientTemplate},
+	}
+}
+
+func (eg *Generator) GetAnnotations() []annotation.AnnotationDescriptor {
+	return csharpAnnotation.Get()
+}
+
+func (eg *Generator) Generate(parsedSource model.ParsedSources, config generator.Config) ([]generator.OutputFile, error) {
+	return generate(config, parsedSource)
+}
+
+func generate(config generator.Config, parsedSources model.ParsedSources) ([]generator.OutputFile, error) {
+	packageName, err := generationUtil.GetPackageNameForStructs(parsedSources.Structs)
+	if packageName == "" || err != nil {
+		return nil, err
+	}
+	targetDir, err := generationUtil.DetermineTargetPath(config.InputDir, packageName)
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces := []*namespaceContext{}
+	for _, service := range parsedSources.Structs {
+		ann, ok := annotation.NewRegistry(csharpAnnotation.Get()).ResolveAnnotationByName(service.DocLines, csharpAnnotation.TypeCSharpClient)
+		if !ok {
+			continue
+		}
+		if !rest.IsRestService(service) {
+			return nil, generator.StructError(service, csharpAnnotation.TypeCSharpClient, "Struct %s with @CSharpClient must be a @RestService", service.Name)
+		}
+		namespace := findNamespace(&namespaces, ann.Attributes[csharpAnnotation.ParamNamespace], parsedSources)
+		namespace.Clients = append(namespace.Clients, newClient(service, namespace))
+	}
+
+	files := []generator.OutputFile{}
+	for _, namespace := range namespaces {
+		namespace.Records, namespace.Enums = namespace.types.records, namespace.types.enums
+		namespaceFiles, err := generateNamespace(targetDir, packageName, namespace, config)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, namespaceFiles...)
+	}
+	return files, nil
+}
+
+func findNamespace(namespaces *[]*namespaceContext, name string, parsedSources model.ParsedSources) *namespaceContext {
+	for _, namespace := range *namespaces {
+		if namespace.Namespace == name {
+			return namespace
+		}
+	}
+	namespace := &namespaceContext{Namespace: name, types: newCSTypes(parsedSources)}
+	*namespaces = append(*namespaces, namespace)
+	return namespace
+}
+
+// generateNamespace writes the sources of a namespace into its own directory below csharp/, named after the classes
+// in them like .net does, so they have no prefix
+func generateNamespace(targetDir string, packageName string, namespace *namespaceContext, config generator.Config) ([]generator.OutputFile, error) {
+	dir := fmt.Sprintf("%s/csharp/%s", targetDir, namespace.Namespace)
+	files := []generator.OutputFile{}
+	render := func(src string, typeName string, filename string, templateName string, templateString string, data interface{}) error {
+		file, err := generationUtil.Generate(generationUtil.Info{
+			Src:            src,
+			TypeName:       typeName,
+			TargetFilename: fmt.Sprintf("%s/%s", dir, filename),
+			TemplateName:   templateName,
+			TemplateString: templateString,
+			Config:         config,
+			FuncMap:        customTemplateFuncs,
+			Data:           data,
+		})
+		if err != nil {
+			return fmt.Errorf("Error generating c#-client %s for package %s:%w", filename, packageName, err)
+		}
+		files = append(files, file)
+		return nil
+	}
+
+	err := render(packageName, "", "ApiClient.cs", "csharp-api-client", apiClientTemplate, namespace)
+	if err != nil {
+		return nil, err
+	}
+	if len(namespace.Records) > 0 || len(namespace.Enums) > 0 {
+		err := render(packageName, "", "Models.cs", "csharp-models", modelsTemplate, namespace)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, client := range namespace.Clients {
+		err := render(fmt.Sprintf("%s.%s", packageName, client.Service), client.Service, client.Class+".cs", "csharp-client", clientTemplate, client)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// newClient describes the rest-operations of a service as methods of a client-class. Operations with an upload are
+// left out: their form is read by the service itself.
+func newClient(service model.Struct, namespace *namespaceContext) clientContext {
+	client := clientContext{Namespace: namespace.Namespace, Service: service.Name, Class: service.Name + "Client"}
+	for _, o := range service.Operations {
+		if !rest.IsRestOperation(*o) || rest.HasUpload(*o) {
+			continue
+		}
+		client.Operations = append(client.Operations, newOperation(service, *o, namespace.types))
+	}
+	return client
+}
+
+func newOperation(service model.Struct, o model.Operation, types *csTypes) clientOperation {
+	op := clientOperation{
+		Name:        rest.ToFirstUpper(o.Name) + "Async",
+		Description: generationUtil.Description(o.DocLines),
+		Method:      rest.GetRestOperationMethod(o),
+	}
+	pathParams := map[string]string{}
+	add := func(param clientParam, required bool) {
+		if required {
+			op.Required = append(op.Required, param)
+		} else {
+			op.Optional = append(op.Optional, param)
+		}
+	}
+	for _, arg := range o.InputArgs {
+		param := clientParam{Name: identifier(generationUtil.LowerCamelCase(arg.Name)), Type: types.of(arg.TypeName)}
+		switch {
+		case strings.Contains(rest.GetRestOperationPath(o), "{"+arg.Name+"}"):
+			pathParams[arg.Name] = param.Name
+			add(param, true)
+		case rest.IsRestServiceTenantScoped(service) && tenant.IsTenantArg(arg):
+			param.Key = rest.GetRestServiceTenantHeader(service)
+			op.Headers = append(op.Headers, param)
+			add(param, true)
+		case rest.IsContextArg(arg) || rest.IsRequestContextArg(arg) || rest.IsUploadArg(arg):
+			continue
+		case rest.IsInputArg(arg):
+			op.Body = &param
+			add(param, true)
+		case rest.IsRestOperationForm(o):
+			param.Key = rest.Uncapitalized(arg.Name)
+			op.Fields = append(op.Fields, param)
+			add(param, rest.IsInputArgMandatory(o, arg))
+		default:
+			param.Key = rest.Uncapitalized(arg.Name)
+			op.Query = append(op.Query, param)
+			add(param, rest.IsInputArgMandatory(o, arg))
+		}
+	}
+	if rest.IsRestOperationIdempotent(o) {
+		param := clientParam{Key: "Idempotency-Key", Name: "idempotencyKey", Type: "string"}
+		op.Headers = append(op.Headers, param)
+		add(param, rest.IsIdempotencyKeyRequired(o))
+	}
+	if rest.HasETagPrecondition(o) {
+		param := clientParam{Key: "If-Match", Name: "ifMatch", Type: "string"}
+		op.Headers = append(op.Headers, param)
+		add(param, rest.IsETagRequired(o))
+	}
+	// relative to the base-address of the http-client
+	op.Path = pathExpression(strings.TrimPrefix(rest.GetRestServicePath(service)+rest.GetRestOperationPath(o), "/"), pathParams)
+
+	switch {
+	case rest.IsRestOperationAsync(o):
+		op.Result, op.Returns = types.asyncJob(), returnsJSON
+	case rest.IsRestOperationNoContent(o) || (rest.IsRestOperationJSON(o) && !rest.HasOutput(o)):
+		op.Returns = returnsNothing
+	case rest.IsRestOperationJSON(o):
+		op.Result, op.Returns = types.of(rest.GetOutputArgType(o)), returnsJSON
+	default:
+		op.Result, op.Returns = "byte[]", returnsContent
+	}
+	return op
+}
+
+// pathExpression returns the c#-expression of a path: an interpolated string when there are path-parameters to fill in
+func pathExpression(path string, pathParams map[string]string) string {
+	if len(pathParams) == 0 {
+		return fmt.Sprintf("%q", path)
+	}
+	return "$" + fmt.Sprintf("%q", pathParamPattern.ReplaceAllStringFunc(path, func(match string) string {
+		name := match[1 : len(match)-1]
+		if param, found := pathParams[name]; found {
+			return "{ApiClient.Path(" + param + ", this.jsonOptions)}"
+		}
+		return "{{" + name + "}}"
+	}))
+}
+
+var customTemplateFuncs = template.FuncMap{
+	"Summary": html.EscapeString,
+}
@@ -0,0 +1,216 @@
+package csharp
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func cleanup() {
+	os.RemoveAll("./testData/csharp")
+}
+
+func TestGenerateRecords(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @RestService( path = "/api" )`, `// @CSharpClient( namespace = "Example.Person" )`},
+			Name:        "PersonService",
+			Operations: []*model.Operation{
+				{
+					DocLines:   []string{`// @RestOperation( method = "GET", path = "/person/{uid}", format = "JSON" )`},
+					Name:       "getPerson",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}},
+					OutputArgs: []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+				},
+			},
+		},
+		{
+			PackageName: "testData",
+			DocLines:    []string{"// Person is someone */ we know"},
+			Name:        "Person",
+			Fields: []model.Field{
+				{Name: "Age", TypeName: "int", Tag: "`json:\"age\"`"},
+				{Name: "Rank", TypeName: "int32", Tag: "`json:\"rank\"`"},
+				{Name: "Visits", TypeName: "uint64", Tag: "`json:\"visits\"`"},
+				{Name: "Score", TypeName: "float32", Tag: "`json:\"score\"`"},
+				{Name: "Photo", TypeName: "[]byte", Tag: "`json:\"photo\"`"},
+				{Name: "Born", TypeName: "*time.Time", Tag: "`json:\"born\"`"},
+				{Name: "Birthday", TypeName: "mydate.MyDate", Tag: "`json:\"birthday\"`"},
+				{Name: "Friends", TypeName: "[]*Person", Tag: "`json:\"friends\"`"},
+				{Name: "Tags", TypeName: "map[string]int", Tag: "`json:\"tags\"`"},
+				{Name: "Color", TypeName: "ColorType", Tag: "`json:\"color\"`"},
+				{Name: "Status", TypeName: "Status", Tag: "`json:\"status\"`"},
+				{Name: "Extra", TypeName: "interface{}", Tag: "`json:\"extra\"`"},
+				{Name: "Person", TypeName: "string", Tag: "`json:\"person\"`"},
+				{Name: "secret", TypeName: "string"},
+				{Name: "Internal", TypeName: "string", Tag: "`json:\"-\"`"},
+			},
+		},
+	}
+	e := []model.Enum{
+		{
+			PackageName:  "testData",
+			DocLines:     []string{"// @JsonEnum( )"},
+			Name:         "ColorType",
+			EnumLiterals: []model.EnumLiteral{{Name: "ColorRed"}, {Name: "ColorGreen"}},
+		},
+		{
+			PackageName:  "testData",
+			Name:         "Status",
+			EnumLiterals: []model.EnumLiteral{{Name: "StatusActive"}},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s, Enums: e}, generator.Config{InputDir: "testData"})
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile("./testData/csharp/Example.Person/Models.cs")
+	assert.NoError(t, err)
+	models := string(data)
+	assert.Contains(t, models, "/// <summary>Person is someone */ we know</summary>\npublic sealed record Person\n{")
+	for property, csType := range map[string]string{
+		"Age":      "long",
+		"Rank":     "int",
+		"Visits":   "ulong",
+		"Score":    "float",
+		"Photo":    "byte[]",
+		"Born":     "DateTimeOffset",
+		"Birthday": "string",
+		"Friends":  "List<Person>",
+		"Tags":     "Dictionary<string, long>",
+		"Color":    "ColorType",
+		"Status":   "int",
+		"Extra":    "JsonElement",
+	} {
+		assert.Contains(t, models, "    public "+csType+"? "+property+" { get; init; }")
+	}
+	// members cannot have the name of their type
+	assert.Contains(t, models, "    [JsonPropertyName(\"person\")]\n    public string? PersonValue { get; init; }")
+	assert.Contains(t, models, "[JsonConverter(typeof(ColorTypeJsonConverter))]\npublic enum ColorType\n{\n    ColorRed,\n    ColorGreen,\n}")
+	assert.Contains(t, models, `            "colorRed" => ColorType.ColorRed,`)
+	assert.NotContains(t, models, "enum Status")
+	assert.NotContains(t, models, "Secret")
+	assert.NotContains(t, models, "Internal")
+}
+
+func TestGenerateClient(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @RestService( path = "/api" )`, `// @CSharpClient( namespace = "Example.Person" )`},
+			Name:        "PersonService",
+			Operations: []*model.Operation{
+				{
+					DocLines:   []string{"// Returns a person", `// @RestOperation( method = "GET", path = "/person/{uid}", format = "JSON", optionalargs = "verbose" )`},
+					Name:       "getPerson",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}, {Name: "verbose", TypeName: "bool"}},
+					OutputArgs: []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+				},
+				{
+					DocLines:   []string{`// @RestOperation( method = "POST", path = "/person", format = "no_content" )`, `// @Idempotent()`},
+					Name:       "createPerson",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "person", TypeName: "Person"}},
+					OutputArgs: []model.Field{{TypeName: "error"}},
+				},
+				{
+					DocLines:   []string{`// @RestOperation( method = "DELETE", path = "/person/{uid}", format = "JSON" )`, `// @ETag( required = "true" )`},
+					Name:       "deletePerson",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}, {Name: "event", TypeName: "string"}},
+					OutputArgs: []model.Field{{TypeName: "[]Person"}, {TypeName: "error"}},
+				},
+				{
+					DocLines:   []string{`// @RestOperation( method = "POST", path = "/person/{uid}/name", form = "true", format = "no_content" )`},
+					Name:       "rename",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}, {Name: "newName", TypeName: "string"}},
+					OutputArgs: []model.Field{{TypeName: "error"}},
+				},
+				{
+					DocLines:   []string{`// @RestOperation( method = "POST", path = "/report", format = "JSON" )`, `// @Async()`},
+					Name:       "createReport",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}},
+					OutputArgs: []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+				},
+				{
+					DocLines:   []string{`// @RestOperation( method = "GET", path = "/export", format = "CSV" )`},
+					Name:       "export",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}},
+					OutputArgs: []model.Field{{TypeName: "string"}, {TypeName: "error"}},
+				},
+				{
+					DocLines:   []string{`// @RestOperation( method = "POST", path = "/upload", format = "no_content" )`},
+					Name:       "upload",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "upload", TypeName: "Upload"}},
+					OutputArgs: []model.Field{{TypeName: "error"}},
+				},
+			},
+		},
+		{
+			PackageName: "testData",
+			Name:        "Person",
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile("./testData/csharp/Example.Person/PersonServiceClient.cs")
+	assert.NoError(t, err)
+	client := string(data)
+	assert.Contains(t, client, "namespace Example.Person;")
+	assert.Contains(t, client, "public sealed class PersonServiceClient\n{")
+	assert.Contains(t, client, `    /// <summary>Returns a person</summary>
+    public async Task<Person?> GetPersonAsync(string uid, bool? verbose = null, CancellationToken cancellationToken = default)
+    {
+        using var httpRequest = new HttpRequestMessage(new HttpMethod("GET"), $"api/person/{ApiClient.Path(uid, this.jsonOptions)}" + ApiClient.Query(this.jsonOptions, ("verbose", verbose)));
+        using var httpResponse = await ApiClient.SendAsync(this.httpClient, httpRequest, cancellationToken).ConfigureAwait(false);
+        return await httpResponse.Content.ReadFromJsonAsync<Person?>(this.jsonOptions, cancellationToken).ConfigureAwait(false);
+    }`)
+	assert.Contains(t, client, `    public async Task CreatePersonAsync(Person person, string? idempotencyKey = null, CancellationToken cancellationToken = default)
+    {
+        using var httpRequest = new HttpRequestMessage(new HttpMethod("POST"), "api/person");
+        ApiClient.Header(httpRequest, "Idempotency-Key", idempotencyKey, this.jsonOptions);
+        httpRequest.Content = JsonContent.Create(person, options: this.jsonOptions);
+        using var httpResponse = await ApiClient.SendAsync(this.httpClient, httpRequest, cancellationToken).ConfigureAwait(false);
+    }`)
+	// keywords of c# are verbatim identifiers
+	assert.Contains(t, client, "public async Task<List<Person>?> DeletePersonAsync(string uid, string @event, string ifMatch, CancellationToken cancellationToken = default)")
+	assert.Contains(t, client, `        httpRequest.Content = ApiClient.Form(this.jsonOptions, ("newName", newName));`)
+	assert.Contains(t, client, "public async Task<AsyncJob?> CreateReportAsync(CancellationToken cancellationToken = default)")
+	assert.Contains(t, client, "public async Task<byte[]> ExportAsync(CancellationToken cancellationToken = default)")
+	assert.NotContains(t, client, "UploadAsync")
+
+	data, err = ioutil.ReadFile("./testData/csharp/Example.Person/Models.cs")
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "public sealed record Person\n{\n}")
+	assert.Contains(t, string(data), "public sealed record AsyncJob\n{")
+
+	_, err = os.Stat("./testData/csharp/Example.Person/ApiClient.cs")
+	assert.NoError(t, err)
+}
+
+func TestGenerateForNoRestService(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @CSharpClient( namespace = "Example.Person" )`},
+			Name:        "PersonService",
+		},
+	}
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Struct PersonService with @CSharpClient must be a @RestService")
+}
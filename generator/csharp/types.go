@@ -0,0 +1,203 @@
+package csharp
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/generator/jsonHelpers"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+// csRecord is a struct of the parsed sources as record with the json-fields of the struct as properties
+type csRecord struct {
+	Name        string
+	Description string
+	Properties  []csProperty
+}
+
+type csProperty struct {
+	JSONName string
+	Name     string
+	Type     string
+}
+
+// csEnum is a @JsonEnum of the parsed sources, with its literals by their name in json
+type csEnum struct {
+	Name        string
+	Description string
+	Literals    []csLiteral
+}
+
+type csLiteral struct {
+	JSONName string
+	Name     string
+}
+
+var primitives = map[string]string{
+	"string":        "string",
+	"bool":          "bool",
+	"int":           "long",
+	"int8":          "int",
+	"int16":         "int",
+	"int32":         "int",
+	"int64":         "long",
+	"uint":          "long",
+	"uint8":         "int",
+	"uint16":        "int",
+	"uint32":        "long",
+	"uint64":        "ulong",
+	"float32":       "float",
+	"float64":       "double",
+	"[]byte":        "byte[]",
+	"time.Time":     "DateTimeOffset",
+	"mydate.MyDate": "string",
+}
+
+var keywords = map[string]bool{
+	"abstract": true, "as": true, "base": true, "bool": true, "break": true, "byte": true, "case": true,
+	"catch": true, "char": true, "checked": true, "class": true, "const": true, "continue": true, "decimal": true,
+	"default": true, "delegate": true, "do": true, "double": true, "else": true, "enum": true, "event": true,
+	"explicit": true, "extern": true, "false": true, "finally": true, "fixed": true, "float": true, "for": true,
+	"foreach": true, "goto": true, "if": true, "implicit": true, "in": true, "int": true, "interface": true,
+	"internal": true, "is": true, "lock": true, "long": true, "namespace": true, "new": true, "null": true,
+	"object": true, "operator": true, "out": true, "override": true, "params": true, "private": true,
+	"protected": true, "public": true, "readonly": true, "ref": true, "return": true, "sbyte": true,
+	"sealed": true, "short": true, "sizeof": true, "stackalloc": true, "static": true, "string": true,
+	"struct": true, "switch": true, "this": true, "throw": true, "true": true, "try": true, "typeof": true,
+	"uint": true, "ulong": true, "unchecked": true, "unsafe": true, "ushort": true, "using": true, "virtual": true,
+	"void": true, "volatile": true, "while": true,
+}
+
+// locals are the names that the methods of a client use themselves
+var locals = map[string]bool{"httpRequest": true, "httpResponse": true, "cancellationToken": true}
+
+// csTypes maps go-types onto the types of c#, the same way as they are marshalled to json, and collects the
+// records and enums of the structs and enums that it meets
+type csTypes struct {
+	parsedSources model.ParsedSources
+	records       []*csRecord
+	enums         []*csEnum
+	known         map[string]bool
+}
+
+func newCSTypes(parsedSources model.ParsedSources) *csTypes {
+	return &csTypes{parsedSources: parsedSources, known: map[string]bool{}}
+}
+
+// of returns the c#-type of a go-type. Dates are strings in the format of their json, types that are not in the
+// parsed sources are any json-value.
+func (t *csTypes) of(typeName string) string {
+	if primitive, found := primitives[typeName]; found {
+		return primitive
+	}
+	if strings.HasPrefix(typeName, "*") {
+		return t.of(typeName[1:])
+	}
+	if strings.HasPrefix(typeName, "[]") {
+		return fmt.Sprintf("List<%s>", t.of(typeName[2:]))
+	}
+	if field := (model.Field{TypeName: typeName}); field.IsMap() {
+		keyType, valueType := field.SplitMapTypeNames()
+		return fmt.Sprintf("Dictionary<%s, %s>", t.of(keyType), t.of(valueType))
+	}
+	packageName, name := model.Field{TypeName: typeName}.SplitTypeName()
+	for _, e := range t.parsedSources.Enums {
+		if e.Name == name && (packageName == "" || e.PackageName == packageName) {
+			if !jsonHelpers.IsJSONEnum(e) {
+				// the literals of other enums are numbers
+				return "int"
+			}
+			if jsonHelpers.IsJSONEnumTolerant(e) {
+				// unknown names are accepted as well
+				return "string"
+			}
+			t.addEnum(e)
+			return e.Name
+		}
+	}
+	if s, found := generationUtil.FindStruct(t.parsedSources.Structs, typeName); found {
+		t.addRecord(s)
+		return s.Name
+	}
+	for _, td := range t.parsedSources.Typedefs {
+		// structs and interfaces are typedefs as well, without a type
+		if td.Name == name && (packageName == "" || td.PackageName == packageName) && td.Type != "" {
+			return t.of(td.Type)
+		}
+	}
+	return "JsonElement"
+}
+
+func (t *csTypes) addEnum(e model.Enum) {
+	if t.known[e.Name] {
+		return
+	}
+	t.known[e.Name] = true
+	enum := &csEnum{Name: e.Name, Description: generationUtil.Description(e.DocLines)}
+	for idx, name := range jsonHelpers.GetJSONEnumNames(e) {
+		enum.Literals = append(enum.Literals, csLiteral{JSONName: name, Name: identifier(e.EnumLiterals[idx].Name)})
+	}
+	t.enums = append(t.enums, enum)
+}
+
+func (t *csTypes) addRecord(s model.Struct) {
+	if t.known[s.Name] {
+		return
+	}
+	// registered before its properties, for structs that refer to themselves
+	t.known[s.Name] = true
+	record := &csRecord{Name: s.Name, Description: generationUtil.Description(s.DocLines)}
+	t.records = append(t.records, record)
+	for _, p := range t.propertiesOf(s) {
+		if p.Name == record.Name {
+			// members cannot have the name of their type
+			p.Name += "Value"
+		}
+		record.Properties = append(record.Properties, p)
+	}
+}
+
+// asyncJob returns the record of the job that an @Async rest-operation answers with
+func (t *csTypes) asyncJob() string {
+	const name = "AsyncJob"
+	if !t.known[name] {
+		t.known[name] = true
+		t.records = append(t.records, &csRecord{
+			Name:        name,
+			Description: "AsyncJob is the status of an asynchronous rest-operation, with its result once completed",
+			Properties: []csProperty{
+				{JSONName: "id", Name: "ID", Type: "string"},
+				{JSONName: "operation", Name: "Operation", Type: "string"},
+				{JSONName: "tenant", Name: "Tenant", Type: "string"},
+				{JSONName: "status", Name: "Status", Type: "string"},
+				{JSONName: "result", Name: "Result", Type: "JsonElement"},
+				{JSONName: "error", Name: "Error", Type: "string"},
+				{JSONName: "created", Name: "Created", Type: "DateTimeOffset"},
+				{JSONName: "completed", Name: "Completed", Type: "DateTimeOffset"},
+			},
+		})
+	}
+	return name
+}
+
+// propertiesOf returns the json-fields of a struct as the properties of its record
+func (t *csTypes) propertiesOf(s model.Struct) []csProperty {
+	properties := []csProperty{}
+	for _, f := range generationUtil.JSONFields(s, t.parsedSources.Structs) {
+		properties = append(properties, csProperty{JSONName: f.JSONName, Name: f.Field.Name, Type: t.of(f.Field.TypeName)})
+	}
+	return properties
+}
+
+// identifier returns a name that is a keyword in c# as verbatim identifier, and a name that the client uses itself
+// with a suffix
+func identifier(name string) string {
+	if keywords[name] {
+		return "@" + name
+	}
+	if locals[name] {
+		return name + "Value"
+	}
+	return name
+}
@@ -0,0 +1,115 @@
+package dart
+
+const clientTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+
+import 'package:dio/dio.dart';
+import 'package:json_annotation/json_annotation.dart';
+
+part '{{.Library}}.g.dart';
+
+/// ApiEnum is an enum with the names of its literals in json
+abstract class ApiEnum {
+  String get json;
+}
+{{- range .Enums}}
+{{with .Description}}
+/// {{DocComment .}}
+{{- end}}
+@JsonEnum(valueField: 'json')
+enum {{.Name}} implements ApiEnum {
+{{- range $idx, $literal := .Literals}}{{if $idx}},{{end}}
+  {{$literal.Name}}('{{$literal.JSONName}}')
+{{- end}};
+
+  const {{.Name}}(this.json);
+
+  @override
+  final String json;
+}
+{{- end}}
+{{- range .Classes}}
+{{with .Description}}
+/// {{DocComment .}}
+{{- end}}
+@JsonSerializable(explicitToJson: true, includeIfNull: false)
+class {{.Name}} {
+  const {{.Name}}({{with .Fields}}{ {{- range $idx, $field := .}}{{if $idx}}, {{end}}this.{{$field.Name}}{{end -}} }{{end}});
+
+  factory {{.Name}}.fromJson(Map<String, dynamic> json) => _${{.Name}}FromJson(json);
+{{range .Fields}}
+  @JsonKey(name: '{{.JSONName}}')
+  final {{.Type}}? {{.Name}};
+{{end}}
+  Map<String, dynamic> toJson() => _${{.Name}}ToJson(this);
+}
+{{- end}}
+{{- range .Clients}}
+
+/// {{.Class}} calls the rest-operations of {{.Service}} at the baseUrl of its dio
+class {{.Class}} {
+  {{.Class}}(this._dio);
+
+  final Dio _dio;
+{{- range .Operations}}
+{{with .Description}}
+  /// {{DocComment .}}
+{{- end}}
+  Future<{{if eq .Returns "nothing"}}void{{else if eq .Returns "content"}}List<int>{{else}}{{.Result}}?{{end}}> {{.Name}}(
+{{- range $idx, $param := .Required}}{{if $idx}}, {{end}}{{$param.Type}} {{$param.Name}}{{end}}
+{{- if and .Required .Optional}}, {{end}}
+{{- with .Optional}}{ {{- range $idx, $param := .}}{{if $idx}}, {{end}}{{$param.Type}}? {{$param.Name}}{{end -}} }{{end}}) async {
+    {{if ne .Returns "nothing"}}final response = {{end}}await _dio.request<{{if eq .Returns "content"}}List<int>{{else if eq .Returns "json"}}Object?{{else}}void{{end}}>(
+      {{.Path}},
+{{- with .Query}}
+      queryParameters: _params({ {{- range $idx, $param := .}}{{if $idx}}, {{end}}'{{$param.Key}}': {{$param.Name}}{{end -}} }),
+{{- end}}
+{{- with .Data}}
+      data: {{.}},
+{{- end}}
+{{- with .Fields}}
+      data: _params({ {{- range $idx, $param := .}}{{if $idx}}, {{end}}'{{$param.Key}}': {{$param.Name}}{{end -}} }),
+{{- end}}
+      options: Options(
+        method: '{{.Method}}',
+{{- with .Headers}}
+        headers: _params({ {{- range $idx, $param := .}}{{if $idx}}, {{end}}'{{$param.Key}}': {{$param.Name}}{{end -}} }),
+{{- end}}
+{{- if .Fields}}
+        contentType: Headers.formUrlEncodedContentType,
+{{- end}}
+{{- if eq .Returns "content"}}
+        responseType: ResponseType.bytes,
+{{- end}}
+      ),
+    );
+{{- if eq .Returns "json"}}
+    final data = response.data;
+    return data == null ? null : {{.Decode}};
+{{- else if eq .Returns "content"}}
+    return response.data ?? <int>[];
+{{- end}}
+  }
+{{- end}}
+}
+{{- end}}
+
+/// _path formats a path-parameter
+String _path(Object value) => Uri.encodeComponent(_param(value));
+
+/// _params formats the query-, header- or form-parameters that are given
+Map<String, dynamic> _params(Map<String, Object?> values) => {
+      for (final entry in values.entries)
+        if (entry.value != null) entry.key: entry.value is List ? entry.value : _param(entry.value!),
+    };
+
+/// _param formats a parameter the way the service parses it
+String _param(Object value) {
+  if (value is ApiEnum) {
+    return value.json;
+  }
+  if (value is DateTime) {
+    return value.toUtc().toIso8601String();
+  }
+  return value.toString();
+}
+`
@@ -0,0 +1,31 @@
+package dartAnnotation
+
+import (
+	"regexp"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+)
+
+const (
+	TypeDartClient = "DartClient"
+	ParamLibrary   = "library"
+)
+
+var libraryPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+func Get() []annotation.AnnotationDescriptor {
+	return []annotation.AnnotationDescriptor{
+		{
+			Name:       TypeDartClient,
+			ParamNames: []string{ParamLibrary},
+			Validator:  validateDartClientAnnotation,
+		}}
+}
+
+func validateDartClientAnnotation(annot annotation.Annotation) bool {
+	if annot.Name == TypeDartClient {
+		library, hasLibrary := annot.Attributes[ParamLibrary]
+		return !hasLibrary || libraryPattern.MatchString(library)
+	}
+	return false
+}
@@ -0,0 +1,27 @@
+package dartAnnotation
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrectDartClientAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	annotation, ok := registry.ResolveAnnotation(`// @DartClient( library = "person_client" )`)
+	assert.True(t, ok)
+	assert.Equal(t, TypeDartClient, annotation.Name)
+	assert.Equal(t, "person_client", annotation.Attributes[ParamLibrary])
+
+	_, ok = registry.ResolveAnnotation(`// @DartClient()`)
+	assert.True(t, ok)
+}
+
+func TestInvalidDartClientAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @DartClient( library = "PersonClient" )`}))
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @DartClient( library = "person-client" )`}))
+}
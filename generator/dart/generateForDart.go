@@ -0,0 +1,250 @@
+package dart
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/MarcGrol/golangAnnotations/generator/dart/dartAnnotation"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/generator/rest"
+	"github.com/MarcGrol/golangAnnotations/generator/tenant"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+var pathParamPattern = regexp.MustCompile(`\{(\w+)\}`)
+
+// libraryContext holds the clients of the rest-services that share a dart-library, with the models they use
+type libraryContext struct {
+	Library string
+	Clients []clientContext
+	Classes []*dartClass
+	Enums   []*dartEnum
+	types   *dartTypes
+}
+
+type clientContext struct {
+	Service    string
+	Class      string
+	Operations []clientOperation
+}
+
+type clientOperation struct {
+	Name        string
+	Description string
+	Method      string
+	// Path is the dart-string of the path of the operation, with its path-parameters filled in
+	Path string
+	// Required and Optional are the parameters of the method, the optional ones are named
+	Required []clientParam
+	Optional []clientParam
+	Query    []clientParam
+	Headers  []clientParam
+	Fields   []clientParam
+	// Data is the dart-expression of the json of the body
+	Data   string
+	Result string
+	// Decode is the dart-expression that converts the json of the response, in data, into the result
+	Decode string
+	// Returns is how the response is returned: decoded json, the raw content or nothing
+	Returns string
+}
+
+type clientParam struct {
+	Key  string
+	Name string
+	Type string
+}
+
+const (
+	returnsNothing = "nothing"
+	returnsJSON    = "json"
+	returnsContent = "content"
+)
+
+type Generator struct {
+}
+
+func NewGenerator() generator.Generator {
+	return &Generator{}
+}
+
+func (eg *Generator) Name() string {
+	return "dart"
+}
+
+func (eg *Generator) GetTemplates() []generator.Template {
+	return []generator.Template{
+		{Name: "dart-client", APIVersion: 1, Text: clientTemplate},
+	}
+}
+
+func (eg *Generator) GetAnnotations() []annotation.AnnotationDescriptor {
+	return dartAnnotation.Get()
+}
+
+func (eg *Generator) Generate(parsedSource model.ParsedSources, config generator.Config) ([]generator.OutputFile, error) {
+	return generate(config, parsedSource)
+}
+
+func generate(config generator.Config, parsedSources model.ParsedSources) ([]generator.OutputFile, error) {
+	packageName, err := generationUtil.GetPackageNameForStructs(parsedSources.Structs)
+	if packageName == "" || err != nil {
+		return nil, err
+	}
+	targetDir, err := generationUtil.DetermineTargetPath(config.InputDir, packageName)
+	if err != nil {
+		return nil, err
+	}
+
+	libraries := []*libraryContext{}
+	for _, service := range parsedSources.Structs {
+		ann, ok := annotation.NewRegistry(dartAnnotation.Get()).ResolveAnnotationByName(service.DocLines, dartAnnotation.TypeDartClient)
+		if !ok {
+			continue
+		}
+		if !rest.IsRestService(service) {
+			return nil, generator.StructError(service, dartAnnotation.TypeDartClient, "Struct %s with @DartClient must be a @RestService", service.Name)
+		}
+		name := ann.Attributes[dartAnnotation.ParamLibrary]
+		if name == "" {
+			name = generationUtil.SnakeCase(packageName) + "_client"
+		}
+		library := findLibrary(&libraries, name, parsedSources)
+		library.Clients = append(library.Clients, newClient(service, library.types))
+	}
+
+	files := []generator.OutputFile{}
+	for _, library := range libraries {
+		library.Classes, library.Enums = library.types.classes, library.types.enums
+		// the part that json_serializable generates is named after the library, so it has no prefix
+		file, err := generationUtil.Generate(generationUtil.Info{
+			Src:            packageName,
+			TargetFilename: fmt.Sprintf("%s/dart/%s.dart", targetDir, library.Library),
+			TemplateName:   "dart-client",
+			TemplateString: clientTemplate,
+			Config:         config,
+			FuncMap:        customTemplateFuncs,
+			Data:           library,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Error generating dart-client %s for package %s:%w", library.Library, packageName, err)
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+func findLibrary(libraries *[]*libraryContext, name string, parsedSources model.ParsedSources) *libraryContext {
+	for _, library := range *libraries {
+		if library.Library == name {
+			return library
+		}
+	}
+	library := &libraryContext{Library: name, types: newDartTypes(parsedSources)}
+	*libraries = append(*libraries, library)
+	return library
+}
+
+// newClient describes the rest-operations of a service as methods of a client-class. Operations with an upload are
+// left out: their form is read by the service itself.
+func newClient(service model.Struct, types *dartTypes) clientContext {
+	client := clientContext{Service: service.Name, Class: service.Name + "Client"}
+	for _, o := range service.Operations {
+		if !rest.IsRestOperation(*o) || rest.HasUpload(*o) {
+			continue
+		}
+		client.Operations = append(client.Operations, newOperation(service, *o, types))
+	}
+	return client
+}
+
+func newOperation(service model.Struct, o model.Operation, types *dartTypes) clientOperation {
+	op := clientOperation{
+		Name:        identifier(o.Name),
+		Description: generationUtil.Description(o.DocLines),
+		Method:      rest.GetRestOperationMethod(o),
+	}
+	pathParams := map[string]string{}
+	add := func(param clientParam, required bool) {
+		if required {
+			op.Required = append(op.Required, param)
+		} else {
+			op.Optional = append(op.Optional, param)
+		}
+	}
+	for _, arg := range o.InputArgs {
+		argType := types.of(arg.TypeName)
+		param := clientParam{Name: identifier(generationUtil.LowerCamelCase(arg.Name)), Type: argType.Name}
+		switch {
+		case strings.Contains(rest.GetRestOperationPath(o), "{"+arg.Name+"}"):
+			pathParams[arg.Name] = param.Name
+			add(param, true)
+		case rest.IsRestServiceTenantScoped(service) && tenant.IsTenantArg(arg):
+			param.Key = rest.GetRestServiceTenantHeader(service)
+			op.Headers = append(op.Headers, param)
+			add(param, true)
+		case rest.IsContextArg(arg) || rest.IsRequestContextArg(arg) || rest.IsUploadArg(arg):
+			continue
+		case rest.IsInputArg(arg):
+			op.Data = argType.encode(param.Name)
+			add(param, true)
+		case rest.IsRestOperationForm(o):
+			param.Key = rest.Uncapitalized(arg.Name)
+			op.Fields = append(op.Fields, param)
+			add(param, rest.IsInputArgMandatory(o, arg))
+		default:
+			param.Key = rest.Uncapitalized(arg.Name)
+			op.Query = append(op.Query, param)
+			add(param, rest.IsInputArgMandatory(o, arg))
+		}
+	}
+	if rest.IsRestOperationIdempotent(o) {
+		param := clientParam{Key: "Idempotency-Key", Name: "idempotencyKey", Type: "String"}
+		op.Headers = append(op.Headers, param)
+		add(param, rest.IsIdempotencyKeyRequired(o))
+	}
+	if rest.HasETagPrecondition(o) {
+		param := clientParam{Key: "If-Match", Name: "ifMatch", Type: "String"}
+		op.Headers = append(op.Headers, param)
+		add(param, rest.IsETagRequired(o))
+	}
+	op.Path = pathString(rest.GetRestServicePath(service)+rest.GetRestOperationPath(o), pathParams)
+
+	switch {
+	case rest.IsRestOperationAsync(o):
+		result := types.asyncJob()
+		op.Result, op.Decode, op.Returns = result.Name, result.decode("data"), returnsJSON
+	case rest.IsRestOperationNoContent(o) || (rest.IsRestOperationJSON(o) && !rest.HasOutput(o)):
+		op.Returns = returnsNothing
+	case rest.IsRestOperationJSON(o):
+		result := types.of(rest.GetOutputArgType(o))
+		op.Result, op.Decode, op.Returns = result.Name, result.decode("data"), returnsJSON
+	default:
+		op.Returns = returnsContent
+	}
+	return op
+}
+
+// pathString returns the dart-string of a path, with the path-parameters interpolated
+func pathString(path string, pathParams map[string]string) string {
+	return "'" + pathParamPattern.ReplaceAllStringFunc(strings.Replace(path, "$", `\$`, -1), func(match string) string {
+		name := match[1 : len(match)-1]
+		if param, found := pathParams[name]; found {
+			return "${_path(" + param + ")}"
+		}
+		return match
+	}) + "'"
+}
+
+var customTemplateFuncs = template.FuncMap{
+	"DocComment": docComment,
+}
+
+// docComment returns text that renders as text in dartdoc, which reads markdown
+func docComment(text string) string {
+	return strings.NewReplacer("[", `\[`, "]", `\]`).Replace(text)
+}
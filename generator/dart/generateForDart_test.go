@@ -0,0 +1,204 @@
+package dart
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func cleanup() {
+	os.RemoveAll("./testData/dart")
+}
+
+func TestGenerateClasses(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @RestService( path = "/api" )`, `// @DartClient()`},
+			Name:        "PersonService",
+			Operations: []*model.Operation{
+				{
+					DocLines:   []string{`// @RestOperation( method = "GET", path = "/person/{uid}", format = "JSON" )`},
+					Name:       "getPerson",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}},
+					OutputArgs: []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+				},
+			},
+		},
+		{
+			PackageName: "testData",
+			DocLines:    []string{"// Person is someone */ we know"},
+			Name:        "Person",
+			Fields: []model.Field{
+				{Name: "Name", TypeName: "string", Tag: "`json:\"name\"`"},
+				{Name: "Visits", TypeName: "uint64", Tag: "`json:\"visits\"`"},
+				{Name: "Score", TypeName: "float32", Tag: "`json:\"score\"`"},
+				{Name: "Born", TypeName: "*time.Time", Tag: "`json:\"born\"`"},
+				{Name: "Photo", TypeName: "[]byte", Tag: "`json:\"photo\"`"},
+				{Name: "Friends", TypeName: "[]*Person", Tag: "`json:\"friends\"`"},
+				{Name: "Tags", TypeName: "map[string]int", Tag: "`json:\"tags\"`"},
+				{Name: "Color", TypeName: "ColorType", Tag: "`json:\"color,omitempty\"`"},
+				{Name: "Extra", TypeName: "interface{}", Tag: "`json:\"extra\"`"},
+				{Name: "Default", TypeName: "bool", Tag: "`json:\"default\"`"},
+				{Name: "secret", TypeName: "string"},
+				{Name: "Internal", TypeName: "string", Tag: "`json:\"-\"`"},
+			},
+		},
+	}
+	e := []model.Enum{
+		{
+			PackageName:  "testData",
+			DocLines:     []string{"// @JsonEnum( )"},
+			Name:         "ColorType",
+			EnumLiterals: []model.EnumLiteral{{Name: "ColorRed"}, {Name: "ColorGreen"}},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s, Enums: e}, generator.Config{InputDir: "testData"})
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile("./testData/dart/test_data_client.dart")
+	assert.NoError(t, err)
+	client := string(data)
+	assert.Contains(t, client, "part 'test_data_client.g.dart';")
+	assert.Contains(t, client, "@JsonEnum(valueField: 'json')\nenum ColorType implements ApiEnum {\n  colorRed('colorRed'),\n  colorGreen('colorGreen');\n")
+	assert.Contains(t, client, "/// Person is someone */ we know\n@JsonSerializable(explicitToJson: true, includeIfNull: false)\nclass Person {")
+	assert.Contains(t, client, "  const Person({this.name, this.visits, this.score, this.born, this.photo, this.friends, this.tags, this.color, this.extra, this.default_});")
+	for field, dartType := range map[string]string{
+		"visits":  "int",
+		"score":   "double",
+		"born":    "DateTime",
+		"photo":   "String",
+		"friends": "List<Person>",
+		"tags":    "Map<String, int>",
+		"color":   "ColorType",
+		"extra":   "Object",
+	} {
+		assert.Contains(t, client, "  @JsonKey(name: '"+field+"')\n  final "+dartType+"? "+field+";")
+	}
+	// keywords of dart get a trailing underscore
+	assert.Contains(t, client, "  @JsonKey(name: 'default')\n  final bool? default_;")
+	assert.NotContains(t, client, "secret")
+	assert.NotContains(t, client, "internal")
+}
+
+func TestGenerateClient(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @RestService( path = "/api" )`, `// @DartClient()`},
+			Name:        "PersonService",
+			Operations: []*model.Operation{
+				{
+					DocLines:   []string{"// Returns a person", `// @RestOperation( method = "GET", path = "/person/{uid}", format = "JSON", optionalargs = "verbose" )`},
+					Name:       "getPerson",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}, {Name: "verbose", TypeName: "bool"}},
+					OutputArgs: []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+				},
+				{
+					DocLines:   []string{`// @RestOperation( method = "POST", path = "/person", format = "no_content" )`, `// @Idempotent()`},
+					Name:       "createPerson",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "person", TypeName: "Person"}},
+					OutputArgs: []model.Field{{TypeName: "error"}},
+				},
+				{
+					DocLines:   []string{`// @RestOperation( method = "DELETE", path = "/person/{uid}", format = "JSON" )`, `// @ETag( required = "true" )`},
+					Name:       "deletePerson",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}, {Name: "reason", TypeName: "Reason"}},
+					OutputArgs: []model.Field{{TypeName: "[]Person"}, {TypeName: "error"}},
+				},
+				{
+					DocLines:   []string{`// @RestOperation( method = "POST", path = "/person/{uid}/name", form = "true", format = "no_content" )`},
+					Name:       "rename",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}, {Name: "newName", TypeName: "string"}},
+					OutputArgs: []model.Field{{TypeName: "error"}},
+				},
+				{
+					DocLines:   []string{`// @RestOperation( method = "POST", path = "/report", format = "JSON" )`, `// @Async()`},
+					Name:       "createReport",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}},
+					OutputArgs: []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+				},
+				{
+					DocLines:   []string{`// @RestOperation( method = "GET", path = "/export", format = "CSV" )`},
+					Name:       "export",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}},
+					OutputArgs: []model.Field{{TypeName: "string"}, {TypeName: "error"}},
+				},
+				{
+					DocLines:   []string{`// @RestOperation( method = "POST", path = "/upload", format = "no_content" )`},
+					Name:       "upload",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "upload", TypeName: "Upload"}},
+					OutputArgs: []model.Field{{TypeName: "error"}},
+				},
+			},
+		},
+		{
+			PackageName: "testData",
+			Name:        "Person",
+		},
+		{
+			PackageName: "testData",
+			Name:        "Reason",
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile("./testData/dart/test_data_client.dart")
+	assert.NoError(t, err)
+	client := string(data)
+	assert.Contains(t, client, "  const Reason();")
+	assert.Contains(t, client, "class AsyncJob {")
+	assert.Contains(t, client, "class PersonServiceClient {")
+	assert.Contains(t, client, `  /// Returns a person
+  Future<Person?> getPerson(String uid, {bool? verbose}) async {
+    final response = await _dio.request<Object?>(
+      '/api/person/${_path(uid)}',
+      queryParameters: _params({'verbose': verbose}),
+      options: Options(
+        method: 'GET',
+      ),
+    );
+    final data = response.data;
+    return data == null ? null : Person.fromJson(data as Map<String, dynamic>);
+  }`)
+	assert.Contains(t, client, `  Future<void> createPerson(Person person, {String? idempotencyKey}) async {
+    await _dio.request<void>(
+      '/api/person',
+      data: person.toJson(),`)
+	assert.Contains(t, client, "  Future<List<Person>?> deletePerson(String uid, Reason reason, String ifMatch) async {")
+	assert.Contains(t, client, "return data == null ? null : (data as List<dynamic>).map((e) => Person.fromJson(e as Map<String, dynamic>)).toList();")
+	assert.Contains(t, client, "      data: _params({'newName': newName}),")
+	assert.Contains(t, client, "        contentType: Headers.formUrlEncodedContentType,")
+	assert.Contains(t, client, "  Future<AsyncJob?> createReport() async {")
+	assert.Contains(t, client, "  Future<List<int>> export_() async {")
+	assert.NotContains(t, client, "upload")
+}
+
+func TestGenerateForNoRestService(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @DartClient()`},
+			Name:        "PersonService",
+		},
+	}
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Struct PersonService with @DartClient must be a @RestService")
+}
@@ -0,0 +1,254 @@
+package dart
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/generator/jsonHelpers"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+// dartClass is a struct of the parsed sources as json_serializable class with the json-fields of the struct
+type dartClass struct {
+	Name        string
+	Description string
+	Fields      []dartField
+}
+
+type dartField struct {
+	JSONName string
+	Name     string
+	Type     string
+}
+
+// dartEnum is a @JsonEnum of the parsed sources, with its literals by their name in json
+type dartEnum struct {
+	Name        string
+	Description string
+	Literals    []dartLiteral
+}
+
+type dartLiteral struct {
+	JSONName string
+	Name     string
+}
+
+const (
+	kindValue  = "value"
+	kindDouble = "double"
+	kindTime   = "time"
+	kindList   = "list"
+	kindMap    = "map"
+	kindClass  = "class"
+	kindEnum   = "enum"
+	kindAny    = "any"
+)
+
+// dartType is a type in dart, with what it takes to convert it from and to json
+type dartType struct {
+	Name string
+	kind string
+	key  *dartType
+	elem *dartType
+}
+
+var primitives = map[string]dartType{
+	"string":        {Name: "String", kind: kindValue},
+	"bool":          {Name: "bool", kind: kindValue},
+	"int":           {Name: "int", kind: kindValue},
+	"int8":          {Name: "int", kind: kindValue},
+	"int16":         {Name: "int", kind: kindValue},
+	"int32":         {Name: "int", kind: kindValue},
+	"int64":         {Name: "int", kind: kindValue},
+	"uint":          {Name: "int", kind: kindValue},
+	"uint8":         {Name: "int", kind: kindValue},
+	"uint16":        {Name: "int", kind: kindValue},
+	"uint32":        {Name: "int", kind: kindValue},
+	"uint64":        {Name: "int", kind: kindValue},
+	"float32":       {Name: "double", kind: kindDouble},
+	"float64":       {Name: "double", kind: kindDouble},
+	"[]byte":        {Name: "String", kind: kindValue},
+	"time.Time":     {Name: "DateTime", kind: kindTime},
+	"mydate.MyDate": {Name: "String", kind: kindValue},
+}
+
+var keywords = map[string]bool{
+	"abstract": true, "as": true, "assert": true, "async": true, "await": true, "break": true, "case": true,
+	"catch": true, "class": true, "const": true, "continue": true, "covariant": true, "default": true,
+	"deferred": true, "do": true, "dynamic": true, "else": true, "enum": true, "export": true, "extends": true,
+	"extension": true, "external": true, "factory": true, "false": true, "final": true, "finally": true,
+	"for": true, "get": true, "hide": true, "if": true, "implements": true, "import": true, "in": true,
+	"interface": true, "is": true, "late": true, "library": true, "mixin": true, "new": true, "null": true,
+	"on": true, "operator": true, "part": true, "required": true, "rethrow": true, "return": true, "set": true,
+	"show": true, "static": true, "super": true, "switch": true, "sync": true, "this": true, "throw": true,
+	"true": true, "try": true, "typedef": true, "var": true, "void": true, "while": true, "with": true,
+	"yield": true,
+	// used by the methods of the clients themselves
+	"response": true,
+}
+
+// dartTypes maps go-types onto the types of dart, the same way as they are marshalled to json, and collects the
+// classes and enums of the structs and enums that it meets
+type dartTypes struct {
+	parsedSources model.ParsedSources
+	classes       []*dartClass
+	enums         []*dartEnum
+	known         map[string]bool
+}
+
+func newDartTypes(parsedSources model.ParsedSources) *dartTypes {
+	return &dartTypes{parsedSources: parsedSources, known: map[string]bool{}}
+}
+
+// of returns the dart-type of a go-type. Dates are strings in the format of their json, types that are not in the
+// parsed sources are any json-value.
+func (t *dartTypes) of(typeName string) *dartType {
+	if primitive, found := primitives[typeName]; found {
+		return &primitive
+	}
+	if strings.HasPrefix(typeName, "*") {
+		return t.of(typeName[1:])
+	}
+	if strings.HasPrefix(typeName, "[]") {
+		elem := t.of(typeName[2:])
+		return &dartType{Name: fmt.Sprintf("List<%s>", elem.Name), kind: kindList, elem: elem}
+	}
+	if field := (model.Field{TypeName: typeName}); field.IsMap() {
+		keyType, valueType := field.SplitMapTypeNames()
+		key, elem := t.of(keyType), t.of(valueType)
+		return &dartType{Name: fmt.Sprintf("Map<%s, %s>", key.Name, elem.Name), kind: kindMap, key: key, elem: elem}
+	}
+	packageName, name := model.Field{TypeName: typeName}.SplitTypeName()
+	for _, e := range t.parsedSources.Enums {
+		if e.Name == name && (packageName == "" || e.PackageName == packageName) {
+			if !jsonHelpers.IsJSONEnum(e) {
+				// the literals of other enums are numbers
+				return &dartType{Name: "int", kind: kindValue}
+			}
+			if jsonHelpers.IsJSONEnumTolerant(e) {
+				// unknown names are accepted as well
+				return &dartType{Name: "String", kind: kindValue}
+			}
+			t.addEnum(e)
+			return &dartType{Name: e.Name, kind: kindEnum}
+		}
+	}
+	if s, found := generationUtil.FindStruct(t.parsedSources.Structs, typeName); found {
+		t.addClass(s)
+		return &dartType{Name: s.Name, kind: kindClass}
+	}
+	for _, td := range t.parsedSources.Typedefs {
+		// structs and interfaces are typedefs as well, without a type
+		if td.Name == name && (packageName == "" || td.PackageName == packageName) && td.Type != "" {
+			return t.of(td.Type)
+		}
+	}
+	return &dartType{Name: "Object", kind: kindAny}
+}
+
+// decode returns the dart-expression that converts the json-value of expr into the type
+func (d *dartType) decode(expr string) string {
+	switch d.kind {
+	case kindDouble:
+		return fmt.Sprintf("(%s as num).toDouble()", expr)
+	case kindTime:
+		return fmt.Sprintf("DateTime.parse(%s as String)", expr)
+	case kindList:
+		return fmt.Sprintf("(%s as List<dynamic>).map((e) => %s).toList()", expr, d.elem.decode("e"))
+	case kindMap:
+		key := "k"
+		if d.key.Name == "int" {
+			// the keys of json-objects are strings, also for maps with numbers as key
+			key = "int.parse(k)"
+		}
+		return fmt.Sprintf("(%s as Map<String, dynamic>).map((k, v) => MapEntry(%s, %s))", expr, key, d.elem.decode("v"))
+	case kindClass:
+		return fmt.Sprintf("%s.fromJson(%s as Map<String, dynamic>)", d.Name, expr)
+	case kindEnum:
+		return fmt.Sprintf("%s.values.firstWhere((e) => e.json == %s)", d.Name, expr)
+	case kindAny:
+		return expr
+	}
+	return fmt.Sprintf("%s as %s", expr, d.Name)
+}
+
+// encode returns the dart-expression that converts the value of expr into json
+func (d *dartType) encode(expr string) string {
+	switch d.kind {
+	case kindTime:
+		return fmt.Sprintf("%s.toUtc().toIso8601String()", expr)
+	case kindList:
+		return fmt.Sprintf("%s.map((e) => %s).toList()", expr, d.elem.encode("e"))
+	case kindMap:
+		return fmt.Sprintf("%s.map((k, v) => MapEntry(k.toString(), %s))", expr, d.elem.encode("v"))
+	case kindClass:
+		return fmt.Sprintf("%s.toJson()", expr)
+	case kindEnum:
+		return fmt.Sprintf("%s.json", expr)
+	}
+	return expr
+}
+
+func (t *dartTypes) addEnum(e model.Enum) {
+	if t.known[e.Name] {
+		return
+	}
+	t.known[e.Name] = true
+	enum := &dartEnum{Name: e.Name, Description: generationUtil.Description(e.DocLines)}
+	for idx, name := range jsonHelpers.GetJSONEnumNames(e) {
+		enum.Literals = append(enum.Literals, dartLiteral{JSONName: name, Name: identifier(generationUtil.LowerCamelCase(e.EnumLiterals[idx].Name))})
+	}
+	t.enums = append(t.enums, enum)
+}
+
+func (t *dartTypes) addClass(s model.Struct) {
+	if t.known[s.Name] {
+		return
+	}
+	// registered before its fields, for structs that refer to themselves
+	t.known[s.Name] = true
+	class := &dartClass{Name: s.Name, Description: generationUtil.Description(s.DocLines)}
+	t.classes = append(t.classes, class)
+	class.Fields = t.fieldsOf(s)
+}
+
+// asyncJob returns the class of the job that an @Async rest-operation answers with
+func (t *dartTypes) asyncJob() *dartType {
+	const name = "AsyncJob"
+	if !t.known[name] {
+		t.known[name] = true
+		t.classes = append(t.classes, &dartClass{
+			Name:        name,
+			Description: "AsyncJob is the status of an asynchronous rest-operation, with its result once completed",
+			Fields: []dartField{
+				{JSONName: "id", Name: "id", Type: "String"},
+				{JSONName: "operation", Name: "operation", Type: "String"},
+				{JSONName: "tenant", Name: "tenant", Type: "String"},
+				{JSONName: "status", Name: "status", Type: "String"},
+				{JSONName: "result", Name: "result", Type: "Object"},
+				{JSONName: "error", Name: "error", Type: "String"},
+				{JSONName: "created", Name: "created", Type: "DateTime"},
+				{JSONName: "completed", Name: "completed", Type: "DateTime"},
+			},
+		})
+	}
+	return &dartType{Name: name, kind: kindClass}
+}
+
+// fieldsOf returns the json-fields of a struct as the final fields of its class
+func (t *dartTypes) fieldsOf(s model.Struct) []dartField {
+	fields := []dartField{}
+	for _, f := range generationUtil.JSONFields(s, t.parsedSources.Structs) {
+		fields = append(fields, dartField{JSONName: f.JSONName, Name: identifier(generationUtil.LowerCamelCase(f.Field.Name)), Type: t.of(f.Field.TypeName).Name})
+	}
+	return fields
+}
+
+// identifier returns a name that is a keyword in dart with a trailing underscore
+func identifier(name string) string {
+	if keywords[name] {
+		return name + "_"
+	}
+	return name
+}
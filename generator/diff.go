@@ -0,0 +1,185 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const diffContextLines = 3
+
+// Diff returns the unified diff from the current content of the file on disk to its rendered content,
+// or "" when regenerating would not change the file
+func Diff(f OutputFile) (string, error) {
+	current, err := ioutil.ReadFile(f.Filename)
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("Error reading file %s:%w", f.Filename, err)
+	}
+	if err == nil && bytes.Equal(current, f.Content) {
+		return "", nil
+	}
+	filename := filepath.ToSlash(filepath.Clean(f.Filename))
+	from := "a/" + filename
+	if os.IsNotExist(err) {
+		from = "/dev/null"
+	}
+	return UnifiedDiff(from, "b/"+filename, string(current), string(f.Content)), nil
+}
+
+// IsUpToDate tells if the file on disk already has the rendered content
+func IsUpToDate(f OutputFile) (bool, error) {
+	current, err := ioutil.ReadFile(f.Filename)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("Error reading file %s:%w", f.Filename, err)
+	}
+	return bytes.Equal(current, f.Content), nil
+}
+
+// UnifiedDiff returns the differences between two texts in the unified format of "diff -u"
+func UnifiedDiff(fromName string, toName string, from string, to string) string {
+	if from == to {
+		return ""
+	}
+	edits := diffLines(splitLines(from), splitLines(to))
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "--- %s\n+++ %s\n", fromName, toName)
+	for start := 0; start < len(edits); {
+		if edits[start].kind == ' ' {
+			start++
+			continue
+		}
+		// changes that are at most twice the context apart end up in the same hunk
+		end := start + 1
+		for idx := end; idx < len(edits); idx++ {
+			if edits[idx].kind != ' ' {
+				if idx-end > 2*diffContextLines {
+					break
+				}
+				end = idx + 1
+			}
+		}
+		first := start - diffContextLines
+		if first < 0 {
+			first = 0
+		}
+		last := end + diffContextLines
+		if last > len(edits) {
+			last = len(edits)
+		}
+		writeHunk(&buf, edits, first, last)
+		start = last
+	}
+	return buf.String()
+}
+
+type edit struct {
+	kind byte // ' ', '-' or '+'
+	line string
+	// fromLine and toLine are the 0-based line-numbers in the texts before this edit
+	fromLine int
+	toLine   int
+}
+
+func writeHunk(buf *strings.Builder, edits []edit, first int, last int) {
+	fromCount, toCount := 0, 0
+	for _, e := range edits[first:last] {
+		if e.kind != '+' {
+			fromCount++
+		}
+		if e.kind != '-' {
+			toCount++
+		}
+	}
+	fmt.Fprintf(buf, "@@ -%s +%s @@\n", hunkRange(edits[first].fromLine, fromCount), hunkRange(edits[first].toLine, toCount))
+	for _, e := range edits[first:last] {
+		buf.WriteByte(e.kind)
+		buf.WriteString(e.line)
+		if !strings.HasSuffix(e.line, "\n") {
+			buf.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+func hunkRange(start int, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// splitLines splits text into lines that keep their line-ending
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines computes a minimal edit-script with a longest common subsequence of the lines that remain
+// after stripping the common prefix and suffix, which keeps the usual small changes of regeneration cheap
+func diffLines(from []string, to []string) []edit {
+	prefix := 0
+	for prefix < len(from) && prefix < len(to) && from[prefix] == to[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(from)-prefix && suffix < len(to)-prefix && from[len(from)-1-suffix] == to[len(to)-1-suffix] {
+		suffix++
+	}
+	a, b := from[prefix:len(from)-suffix], to[prefix:len(to)-suffix]
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] > lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	edits := make([]edit, 0, len(from)+len(to))
+	i, j := 0, 0
+	for ; i < prefix; i, j = i+1, j+1 {
+		edits = append(edits, edit{kind: ' ', line: from[i], fromLine: i, toLine: j})
+	}
+	for ai, bj := 0, 0; ai < len(a) || bj < len(b); {
+		switch {
+		case ai < len(a) && bj < len(b) && a[ai] == b[bj]:
+			edits = append(edits, edit{kind: ' ', line: a[ai], fromLine: i, toLine: j})
+			ai, bj, i, j = ai+1, bj+1, i+1, j+1
+		case ai < len(a) && (bj == len(b) || lcs[ai+1][bj] >= lcs[ai][bj+1]):
+			edits = append(edits, edit{kind: '-', line: a[ai], fromLine: i, toLine: j})
+			ai, i = ai+1, i+1
+		default:
+			edits = append(edits, edit{kind: '+', line: b[bj], fromLine: i, toLine: j})
+			bj, j = bj+1, j+1
+		}
+	}
+	for k := len(from) - suffix; k < len(from); k++ {
+		edits = append(edits, edit{kind: ' ', line: from[k], fromLine: i, toLine: j})
+		i, j = i+1, j+1
+	}
+	return edits
+}
@@ -0,0 +1,95 @@
+package generator
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnifiedDiffEqual(t *testing.T) {
+	assert.Equal(t, "", UnifiedDiff("a", "b", "x\ny\n", "x\ny\n"))
+}
+
+func TestUnifiedDiffChangedLine(t *testing.T) {
+	from := "1\n2\n3\n4\n5\n6\n7\n8\n9\n"
+	to := "1\n2\n3\n4\nfive\n6\n7\n8\n9\n"
+	assert.Equal(t, `--- a
++++ b
+@@ -2,7 +2,7 @@
+ 2
+ 3
+ 4
+-5
++five
+ 6
+ 7
+ 8
+`, UnifiedDiff("a", "b", from, to))
+}
+
+func TestUnifiedDiffSeparateHunks(t *testing.T) {
+	from := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+	to := "one\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n"
+	assert.Equal(t, `--- a
++++ b
+@@ -1,4 +1,4 @@
+-1
++one
+ 2
+ 3
+ 4
+@@ -9,4 +9,3 @@
+ 9
+ 10
+ 11
+-12
+`, UnifiedDiff("a", "b", from, to))
+}
+
+func TestUnifiedDiffMissingNewline(t *testing.T) {
+	assert.Equal(t, `--- a
++++ b
+@@ -1 +1 @@
+-x
+\ No newline at end of file
++x
+`, UnifiedDiff("a", "b", "x", "x\n"))
+}
+
+func TestDiffNewFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "gen_new.go")
+	diff, err := Diff(OutputFile{Filename: filename, Content: []byte("package x\n")})
+	assert.NoError(t, err)
+	assert.Equal(t, "--- /dev/null\n+++ b/"+filename+"\n@@ -0,0 +1 @@\n+package x\n", diff)
+}
+
+func TestDiffUnchangedFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "gen_existing.go")
+	err := ioutil.WriteFile(filename, []byte("package x\n"), 0644)
+	assert.NoError(t, err)
+
+	diff, err := Diff(OutputFile{Filename: filename, Content: []byte("package x\n")})
+	assert.NoError(t, err)
+	assert.Equal(t, "", diff)
+}
+
+func TestIsUpToDate(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "gen_existing.go")
+
+	upToDate, err := IsUpToDate(OutputFile{Filename: filename, Content: []byte("package x\n")})
+	assert.NoError(t, err)
+	assert.False(t, upToDate)
+
+	err = ioutil.WriteFile(filename, []byte("package x\n"), 0644)
+	assert.NoError(t, err)
+
+	upToDate, err = IsUpToDate(OutputFile{Filename: filename, Content: []byte("package x\n")})
+	assert.NoError(t, err)
+	assert.True(t, upToDate)
+
+	upToDate, err = IsUpToDate(OutputFile{Filename: filename, Content: []byte("package y\n")})
+	assert.NoError(t, err)
+	assert.False(t, upToDate)
+}
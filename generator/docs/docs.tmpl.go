@@ -0,0 +1,232 @@
+package docs
+
+const serviceMarkdownTemplate = `<!-- Generated automatically by golangAnnotations: do not edit manually -->
+
+# {{.Name}}
+{{if .Description}}
+{{.Description}}
+{{end}}
+Base path: {{Code .Path}}
+
+## Operations
+
+| Method | Path | Operation | Roles | Feature flag |
+|--------|------|-----------|-------|--------------|
+{{range .Operations -}}
+| {{.Method}} | {{Code .Path}} | [{{.Name}}](#{{Anchor .Name}}) | {{Cell (Join .Roles ", ")}} | {{.FeatureFlag}} |
+{{end -}}
+{{if .FeatureFlags}}
+## Feature flags
+
+{{range .FeatureFlags -}}
+- {{Code .}}
+{{end -}}
+{{end -}}
+{{range .Operations}}
+### {{.Name}}
+{{if .Description}}
+{{.Description}}
+{{end}}
+{{Code (printf "%s %s" .Method .Path)}}
+{{if .FeatureFlag}}
+Only available when feature flag {{Code .FeatureFlag}} is enabled.
+{{end -}}
+{{if .PathParams}}
+Path parameters:
+
+| Name | Type |
+|------|------|
+{{range .PathParams -}}
+| {{.Name}} | {{Code .Type}} |
+{{end -}}
+{{end -}}
+{{if .QueryParams}}
+Query parameters:
+
+| Name | Type | Required |
+|------|------|----------|
+{{range .QueryParams -}}
+| {{.Name}} | {{Code .Type}} | {{if .Optional}}no{{else}}yes{{end}} |
+{{end -}}
+{{end -}}
+{{if .BodyType}}
+Request body: {{if .BodySchema}}[{{.BodyType}}](#{{Anchor .BodySchema}}){{else}}{{Code .BodyType}}{{end}}
+{{if .ExampleBody}}
+{{Fence "json"}}
+{{.ExampleBody}}
+{{Fence ""}}
+{{end -}}
+{{end}}
+Response: {{.Status}}{{if .ContentType}} ({{.ContentType}}){{end}}{{if .ResponseType}} {{if .ResponseSchema}}[{{.ResponseType}}](#{{Anchor .ResponseSchema}}){{else}}{{Code .ResponseType}}{{end}}{{end}}
+{{if .ExampleResponse}}
+{{Fence "json"}}
+{{.ExampleResponse}}
+{{Fence ""}}
+{{end -}}
+{{if .ProducesEvents}}
+Produces events: {{Join .ProducesEvents ", "}}
+{{end -}}
+{{end -}}
+{{if .Schemas}}
+## Schemas
+{{range .Schemas}}
+### {{.Name}}
+{{if .Description}}
+{{.Description}}
+{{end}}
+| Field | Type | Description |
+|-------|------|-------------|
+{{range .Fields -}}
+| {{.Name}} | {{if .Schema}}[{{Cell .Type}}](#{{Anchor .Schema}}){{else}}{{Code .Type}}{{end}} | {{Cell .Description}} |
+{{end -}}
+{{end -}}
+{{end -}}
+`
+
+const aggregateMarkdownTemplate = `<!-- Generated automatically by golangAnnotations: do not edit manually -->
+
+# Aggregate {{.Name}}
+
+## State transitions
+
+{{Fence "mermaid"}}
+stateDiagram-v2
+{{- $aggregate := .Name}}
+{{range .Events -}}
+{{if .IsRoot}}    [*] --> {{$aggregate}}: {{.Name}}{{else}}    {{$aggregate}} --> {{$aggregate}}: {{.Name}}{{end}}
+{{end -}}
+{{Fence ""}}
+
+## Events
+{{range .Events}}
+### {{.Name}}
+{{if .Description}}
+{{.Description}}
+{{end}}
+{{if .IsRoot}}Creates the {{$aggregate}}.
+
+{{end -}}
+| Field | Type | Description |
+|-------|------|-------------|
+{{range .Fields -}}
+| {{.Name}} | {{Code .Type}} | {{Cell .Description}} |
+{{end -}}
+{{end -}}
+`
+
+const htmlStyle = `
+	<style>
+		body { font-family: sans-serif; margin: 2em; }
+		table { border-collapse: collapse; margin-bottom: 1em; }
+		th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+		pre { background: #f5f5f5; padding: 8px; }
+	</style>`
+
+const serviceHTMLTemplate = `<!DOCTYPE html>
+<!-- Generated automatically by golangAnnotations: do not edit manually -->
+<html>
+<head>
+	<meta charset="UTF-8">
+	<title>{{.Name}}</title>` + htmlStyle + `
+</head>
+<body>
+<h1>{{.Name}}</h1>
+{{if .Description}}<p>{{.Description}}</p>{{end}}
+<p>Base path: <code>{{.Path}}</code></p>
+
+<h2>Operations</h2>
+<table>
+	<tr><th>Method</th><th>Path</th><th>Operation</th><th>Roles</th><th>Feature flag</th></tr>
+	{{range .Operations -}}
+	<tr><td>{{.Method}}</td><td><code>{{.Path}}</code></td><td><a href="#{{Anchor .Name}}">{{.Name}}</a></td><td>{{Join .Roles ", "}}</td><td>{{.FeatureFlag}}</td></tr>
+	{{end -}}
+</table>
+{{if .FeatureFlags}}
+<h2>Feature flags</h2>
+<ul>
+	{{range .FeatureFlags -}}
+	<li><code>{{.}}</code></li>
+	{{end -}}
+</ul>
+{{end -}}
+{{range .Operations}}
+<h3 id="{{Anchor .Name}}">{{.Name}}</h3>
+{{if .Description}}<p>{{.Description}}</p>{{end}}
+<p><code>{{.Method}} {{.Path}}</code></p>
+{{if .FeatureFlag}}<p>Only available when feature flag <code>{{.FeatureFlag}}</code> is enabled.</p>{{end}}
+{{if .PathParams -}}
+<p>Path parameters:</p>
+<table>
+	<tr><th>Name</th><th>Type</th></tr>
+	{{range .PathParams -}}
+	<tr><td>{{.Name}}</td><td><code>{{.Type}}</code></td></tr>
+	{{end -}}
+</table>
+{{end -}}
+{{if .QueryParams -}}
+<p>Query parameters:</p>
+<table>
+	<tr><th>Name</th><th>Type</th><th>Required</th></tr>
+	{{range .QueryParams -}}
+	<tr><td>{{.Name}}</td><td><code>{{.Type}}</code></td><td>{{if .Optional}}no{{else}}yes{{end}}</td></tr>
+	{{end -}}
+</table>
+{{end -}}
+{{if .BodyType -}}
+<p>Request body: {{if .BodySchema}}<a href="#{{Anchor .BodySchema}}">{{.BodyType}}</a>{{else}}<code>{{.BodyType}}</code>{{end}}</p>
+{{if .ExampleBody}}<pre>{{.ExampleBody}}</pre>{{end}}
+{{end -}}
+<p>Response: {{.Status}}{{if .ContentType}} ({{.ContentType}}){{end}}{{if .ResponseType}} {{if .ResponseSchema}}<a href="#{{Anchor .ResponseSchema}}">{{.ResponseType}}</a>{{else}}<code>{{.ResponseType}}</code>{{end}}{{end}}</p>
+{{if .ExampleResponse}}<pre>{{.ExampleResponse}}</pre>{{end}}
+{{if .ProducesEvents}}<p>Produces events: {{Join .ProducesEvents ", "}}</p>{{end}}
+{{end -}}
+{{if .Schemas}}
+<h2>Schemas</h2>
+{{range .Schemas}}
+<h3 id="{{Anchor .Name}}">{{.Name}}</h3>
+{{if .Description}}<p>{{.Description}}</p>{{end}}
+<table>
+	<tr><th>Field</th><th>Type</th><th>Description</th></tr>
+	{{range .Fields -}}
+	<tr><td>{{.Name}}</td><td>{{if .Schema}}<a href="#{{Anchor .Schema}}">{{.Type}}</a>{{else}}<code>{{.Type}}</code>{{end}}</td><td>{{.Description}}</td></tr>
+	{{end -}}
+</table>
+{{end -}}
+{{end}}
+</body>
+</html>
+`
+
+const aggregateHTMLTemplate = `<!DOCTYPE html>
+<!-- Generated automatically by golangAnnotations: do not edit manually -->
+<html>
+<head>
+	<meta charset="UTF-8">
+	<title>Aggregate {{.Name}}</title>` + htmlStyle + `
+</head>
+<body>
+<h1>Aggregate {{.Name}}</h1>
+{{$aggregate := .Name}}
+<h2>State transitions</h2>
+<table>
+	<tr><th>From</th><th>Event</th><th>To</th></tr>
+	{{range .Events -}}
+	<tr><td>{{if .IsRoot}}(new){{else}}{{$aggregate}}{{end}}</td><td><a href="#{{Anchor .Name}}">{{.Name}}</a></td><td>{{$aggregate}}</td></tr>
+	{{end -}}
+</table>
+
+<h2>Events</h2>
+{{range .Events}}
+<h3 id="{{Anchor .Name}}">{{.Name}}</h3>
+{{if .Description}}<p>{{.Description}}</p>{{end}}
+{{if .IsRoot}}<p>Creates the {{$aggregate}}.</p>{{end}}
+<table>
+	<tr><th>Field</th><th>Type</th><th>Description</th></tr>
+	{{range .Fields -}}
+	<tr><td>{{.Name}}</td><td><code>{{.Type}}</code></td><td>{{.Description}}</td></tr>
+	{{end -}}
+</table>
+{{end}}
+</body>
+</html>
+`
@@ -0,0 +1,369 @@
+package docs
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+	textTemplate "text/template"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/MarcGrol/golangAnnotations/generator/event"
+	"github.com/MarcGrol/golangAnnotations/generator/example"
+	"github.com/MarcGrol/golangAnnotations/generator/example/exampleAnnotation"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/generator/rest"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+type serviceDoc struct {
+	PackageName  string
+	Name         string
+	Path         string
+	Description  string
+	FeatureFlags []string
+	Operations   []operationDoc
+	Schemas      []schemaDoc
+}
+
+type operationDoc struct {
+	Name            string
+	Method          string
+	Path            string
+	Description     string
+	Roles           []string
+	FeatureFlag     string
+	ProducesEvents  []string
+	PathParams      []paramDoc
+	QueryParams     []paramDoc
+	BodyType        string
+	BodySchema      string
+	ExampleBody     string
+	Status          int
+	ContentType     string
+	ResponseType    string
+	ResponseSchema  string
+	ExampleResponse string
+}
+
+type paramDoc struct {
+	Name     string
+	Type     string
+	Optional bool
+}
+
+type schemaDoc struct {
+	Name        string
+	Description string
+	Fields      []fieldDoc
+}
+
+type fieldDoc struct {
+	Name        string
+	Type        string
+	Schema      string
+	Description string
+}
+
+type aggregateDoc struct {
+	PackageName string
+	Name        string
+	Events      []eventDoc
+}
+
+type eventDoc struct {
+	Name        string
+	Description string
+	IsRoot      bool
+	Fields      []fieldDoc
+}
+
+type Generator struct {
+}
+
+func NewGenerator() generator.Generator {
+	return &Generator{}
+}
+
+func (eg *Generator) Name() string {
+	return "docs"
+}
+
+func (eg *Generator) GetTemplates() []generator.Template {
+	return []generator.Template{
+		{Name: "service-markdown", APIVersion: 1, Text: serviceMarkdownTemplate},
+		{Name: "service-html", APIVersion: 1, Text: serviceHTMLTemplate},
+		{Name: "aggregate-markdown", APIVersion: 1, Text: aggregateMarkdownTemplate},
+		{Name: "aggregate-html", APIVersion: 1, Text: aggregateHTMLTemplate},
+	}
+}
+
+func (eg *Generator) GetAnnotations() []annotation.AnnotationDescriptor {
+	return exampleAnnotation.Get()
+}
+
+func (eg *Generator) Generate(parsedSource model.ParsedSources, config generator.Config) ([]generator.OutputFile, error) {
+	return generate(config, parsedSource)
+}
+
+func generate(config generator.Config, parsedSources model.ParsedSources) ([]generator.OutputFile, error) {
+	packageName, err := generationUtil.GetPackageNameForStructs(parsedSources.Structs)
+	if packageName == "" || err != nil {
+		return nil, err
+	}
+	targetDir, err := generationUtil.DetermineTargetPath(config.InputDir, packageName)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []generator.OutputFile{}
+	for _, service := range parsedSources.Structs {
+		if !rest.IsRestService(service) {
+			continue
+		}
+		data := getServiceDoc(packageName, service, parsedSources)
+		docs, err := generateDocs(config, targetDir, fmt.Sprintf("api%s", rest.ToFirstUpper(service.Name)), service.Name, data, "service", serviceMarkdownTemplate, serviceHTMLTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("Error generating docs for service %s:%w", service.Name, err)
+		}
+		files = append(files, docs...)
+	}
+
+	for _, aggregate := range getAggregateDocs(packageName, parsedSources.Structs) {
+		docs, err := generateDocs(config, targetDir, fmt.Sprintf("aggregate%s", rest.ToFirstUpper(aggregate.Name)), aggregate.Name, aggregate, "aggregate", aggregateMarkdownTemplate, aggregateHTMLTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("Error generating docs for aggregate %s:%w", aggregate.Name, err)
+		}
+		files = append(files, docs...)
+	}
+	return files, nil
+}
+
+// generateDocs renders the markdown and html docs of a service or aggregate: kind makes the template-names unique
+func generateDocs(config generator.Config, targetDir string, baseName string, typeName string, data interface{}, kind string, markdownTemplate string, htmlTemplate string) ([]generator.OutputFile, error) {
+	markdown, err := generationUtil.Generate(generationUtil.Info{
+		Src:            baseName,
+		TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/docs/%s.md", targetDir, baseName)),
+		TemplateName:   kind + "-markdown",
+		TemplateString: markdownTemplate,
+		Config:         config,
+		FuncMap:        customTemplateFuncs,
+		Data:           data,
+	})
+	if err != nil {
+		return nil, err
+	}
+	htmlName := kind + "-html"
+	html, err := generateHTML(config, generationUtil.Prefixed(fmt.Sprintf("%s/docs/%s.html", targetDir, baseName)), htmlName, generationUtil.TemplateText(config.TemplateOverrides, htmlName, htmlTemplate), data)
+	if err != nil {
+		return nil, err
+	}
+	html.TypeName = typeName
+	return []generator.OutputFile{markdown, html}, nil
+}
+
+// generateHTML uses html/template instead of generationUtil.Generate so that all doc-text gets escaped
+func generateHTML(config generator.Config, targetFilename string, templateName string, templateString string, data interface{}) (generator.OutputFile, error) {
+	t, err := template.New(templateName).Funcs(generationUtil.TemplateFuncs(customTemplateFuncs, config)).Parse(templateString)
+	if err != nil {
+		return generator.OutputFile{}, err
+	}
+	var buf bytes.Buffer
+	err = t.Execute(&buf, data)
+	if err != nil {
+		return generator.OutputFile{}, err
+	}
+	return generator.OutputFile{
+		Filename: targetFilename,
+		Src:      targetFilename,
+		Content:  buf.Bytes(),
+	}, nil
+}
+
+var customTemplateFuncs = textTemplate.FuncMap{
+	"Join":   strings.Join,
+	"Anchor": strings.ToLower,
+	"Cell":   cell,
+	"Code":   code,
+	"Fence":  fence,
+}
+
+// cell makes text safe for use within a cell of a markdown-table
+func cell(in string) string {
+	return strings.Replace(in, "|", "\\|", -1)
+}
+
+func code(in string) string {
+	return "`" + in + "`"
+}
+
+func fence(language string) string {
+	return "```" + language
+}
+
+func getServiceDoc(packageName string, service model.Struct, parsedSources model.ParsedSources) serviceDoc {
+	data := serviceDoc{
+		PackageName:  packageName,
+		Name:         service.Name,
+		Path:         rest.GetRestServicePath(service),
+		Description:  generationUtil.Description(service.DocLines),
+		FeatureFlags: rest.GetFeatureFlagNames([]model.Struct{service}),
+	}
+	schemaNames := []string{}
+	for _, o := range service.Operations {
+		if !rest.IsRestOperation(*o) {
+			continue
+		}
+		doc := getOperationDoc(service, *o, parsedSources)
+		schemaNames = append(schemaNames, doc.BodyType, doc.ResponseType)
+		data.Operations = append(data.Operations, doc)
+	}
+	data.Schemas = getSchemaDocs(schemaNames, parsedSources)
+	for idx := range data.Operations {
+		data.Operations[idx].BodySchema = getSchemaName(data.Operations[idx].BodyType, parsedSources.Structs)
+		data.Operations[idx].ResponseSchema = getSchemaName(data.Operations[idx].ResponseType, parsedSources.Structs)
+	}
+	return data
+}
+
+// getSchemaName returns the name of the documented struct that a type refers to, if any
+func getSchemaName(typeName string, structs []model.Struct) string {
+	if _, found := findStruct(structs, baseTypeName(typeName)); found {
+		return baseTypeName(typeName)
+	}
+	return ""
+}
+
+func getOperationDoc(service model.Struct, o model.Operation, parsedSources model.ParsedSources) operationDoc {
+	doc := operationDoc{
+		Name:           o.Name,
+		Method:         rest.GetRestOperationMethod(o),
+		Path:           rest.GetRestServicePath(service) + rest.GetRestOperationPath(o),
+		Description:    generationUtil.Description(o.DocLines),
+		Roles:          rest.GetRestOperationRoles(o),
+		ProducesEvents: rest.GetRestOperationProducesEventsAsSlice(o),
+		Status:         200,
+		ContentType:    rest.GetContentType(o),
+	}
+	if rest.HasFeatureFlag(o) {
+		doc.FeatureFlag = rest.GetFeatureFlagName(o)
+	}
+	for _, arg := range o.InputArgs {
+		if rest.IsContextArg(arg) || rest.IsRequestContextArg(arg) || rest.IsUploadArg(arg) {
+			continue
+		}
+		if rest.IsInputArg(arg) {
+			if rest.HasInput(o) {
+				doc.BodyType = arg.TypeName
+				doc.ExampleBody = example.JSON(arg.TypeName, parsedSources)
+			}
+			continue
+		}
+		param := paramDoc{Name: arg.Name, Type: arg.TypeName}
+		if rest.IsQueryParam(o, arg) {
+			param.Optional = !rest.IsInputArgMandatory(o, arg)
+			doc.QueryParams = append(doc.QueryParams, param)
+		} else {
+			doc.PathParams = append(doc.PathParams, param)
+		}
+	}
+	if rest.IsRestOperationNoContent(o) {
+		doc.Status = 204
+	} else if rest.HasOutput(o) {
+		doc.ResponseType = rest.GetOutputArgType(o)
+		if rest.IsRestOperationJSON(o) {
+			doc.ExampleResponse = example.JSON(doc.ResponseType, parsedSources)
+		}
+	}
+	return doc
+}
+
+// getSchemaDocs describes the named structs and all structs they refer to, in order of first appearance
+func getSchemaDocs(typeNames []string, parsedSources model.ParsedSources) []schemaDoc {
+	schemas := []schemaDoc{}
+	seen := map[string]bool{}
+	for len(typeNames) > 0 {
+		typeName := baseTypeName(typeNames[0])
+		typeNames = typeNames[1:]
+		if typeName == "" || seen[typeName] {
+			continue
+		}
+		seen[typeName] = true
+		s, found := findStruct(parsedSources.Structs, typeName)
+		if !found {
+			continue
+		}
+		schema := schemaDoc{
+			Name:        s.Name,
+			Description: generationUtil.Description(s.DocLines),
+			Fields:      getFieldDocs(s, parsedSources.Structs),
+		}
+		for _, f := range s.Fields {
+			typeNames = append(typeNames, f.TypeName)
+		}
+		schemas = append(schemas, schema)
+	}
+	return schemas
+}
+
+func getFieldDocs(s model.Struct, structs []model.Struct) []fieldDoc {
+	fields := []fieldDoc{}
+	for _, f := range s.Fields {
+		name, ok := example.FieldName(f)
+		if !ok {
+			continue
+		}
+		fields = append(fields, fieldDoc{
+			Name:        name,
+			Type:        f.TypeName,
+			Schema:      getSchemaName(f.TypeName, structs),
+			Description: generationUtil.Description(append(append([]string{}, f.DocLines...), f.CommentLines...)),
+		})
+	}
+	return fields
+}
+
+func baseTypeName(typeName string) string {
+	for {
+		trimmed := strings.TrimPrefix(strings.TrimPrefix(typeName, "*"), "[]")
+		if trimmed == typeName {
+			return typeName
+		}
+		typeName = trimmed
+	}
+}
+
+func findStruct(structs []model.Struct, name string) (model.Struct, bool) {
+	for _, s := range structs {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return model.Struct{}, false
+}
+
+// getAggregateDocs groups all events per aggregate, in order of appearance
+func getAggregateDocs(packageName string, structs []model.Struct) []aggregateDoc {
+	aggregates := []aggregateDoc{}
+	indexes := map[string]int{}
+	for _, s := range structs {
+		if !event.IsEvent(s) {
+			continue
+		}
+		name := event.GetAggregateName(s)
+		idx, found := indexes[name]
+		if !found {
+			idx = len(aggregates)
+			indexes[name] = idx
+			aggregates = append(aggregates, aggregateDoc{PackageName: packageName, Name: name})
+		}
+		aggregates[idx].Events = append(aggregates[idx].Events, eventDoc{
+			Name:        s.Name,
+			Description: generationUtil.Description(s.DocLines),
+			IsRoot:      event.IsRootEvent(s),
+			Fields:      getFieldDocs(s, structs),
+		})
+	}
+	return aggregates
+}
@@ -0,0 +1,159 @@
+package docs
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func cleanup() {
+	os.Remove(generationUtil.Prefixed("./testData/docs/apiPersonService.md"))
+	os.Remove(generationUtil.Prefixed("./testData/docs/apiPersonService.html"))
+	os.Remove(generationUtil.Prefixed("./testData/docs/aggregatePerson.md"))
+	os.Remove(generationUtil.Prefixed("./testData/docs/aggregatePerson.html"))
+}
+
+func TestGenerateForDocs(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			DocLines: []string{
+				"// PersonService manages persons",
+				`// @RestService( path = "/api" )`,
+			},
+			PackageName: "testData",
+			Name:        "PersonService",
+			Operations: []*model.Operation{
+				{
+					DocLines: []string{
+						"// getPerson returns a single person",
+						`// @RestOperation( method = "GET", path = "/person/{uid}", format = "JSON", roles = "admin,user" )`,
+					},
+					Name:          "getPerson",
+					RelatedStruct: &model.Field{TypeName: "PersonService"},
+					InputArgs: []model.Field{
+						{Name: "c", TypeName: "context.Context"},
+						{Name: "uid", TypeName: "string"},
+						{Name: "verbose", TypeName: "bool"},
+					},
+					OutputArgs: []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+				},
+				{
+					DocLines: []string{
+						`// @RestOperation( method = "POST", path = "/person", format = "JSON" )`,
+						`// @FeatureFlag( name = "person-creation" )`,
+					},
+					Name:          "createPerson",
+					RelatedStruct: &model.Field{TypeName: "PersonService"},
+					InputArgs: []model.Field{
+						{Name: "c", TypeName: "context.Context"},
+						{Name: "person", TypeName: "Person"},
+					},
+					OutputArgs: []model.Field{{TypeName: "error"}},
+				},
+			},
+		},
+		{
+			DocLines:    []string{"// Person is someone that can place orders | or not"},
+			PackageName: "testData",
+			Name:        "Person",
+			Fields: []model.Field{
+				{Name: "Name", TypeName: "string", Tag: "`json:\"name\"`", CommentLines: []string{"// full name"}},
+				{Name: "Address", TypeName: "*Address", Tag: "`json:\"address\"`"},
+			},
+		},
+		{
+			PackageName: "testData",
+			Name:        "Address",
+			Fields: []model.Field{
+				{Name: "City", TypeName: "string", Tag: "`json:\"city\"`"},
+			},
+		},
+		{
+			DocLines:    []string{"// PersonCreated is emitted when a person registered", `// @Event( aggregate = "Person", isrootevent = "true" )`},
+			PackageName: "testData",
+			Name:        "PersonCreated",
+			Fields:      []model.Field{{Name: "PersonUID", TypeName: "string", Tag: "`json:\"personUID\"`"}},
+		},
+		{
+			DocLines:    []string{`// @Event( aggregate = "Person" )`},
+			PackageName: "testData",
+			Name:        "PersonRemoved",
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Nil(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/docs/apiPersonService.md"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "PersonService manages persons")
+	assert.Contains(t, string(data), "| GET | `/api/person/{uid}` | [getPerson](#getperson) | admin, user |  |")
+	assert.Contains(t, string(data), "| POST | `/api/person` | [createPerson](#createperson) |  | person-creation |")
+	assert.Contains(t, string(data), "## Feature flags\n\n- `person-creation`")
+	assert.Contains(t, string(data), "| verbose | `bool` | yes |")
+	assert.Contains(t, string(data), "Request body: [Person](#person)")
+	assert.Contains(t, string(data), "Response: 200 (application/json) [*Person](#person)")
+	assert.Contains(t, string(data), `{
+  "name": "string",
+  "address": {
+    "city": "string"
+  }
+}`)
+	assert.Contains(t, string(data), "Person is someone that can place orders | or not")
+	assert.Contains(t, string(data), "| name | `string` | full name |")
+	assert.Contains(t, string(data), "| address | [*Address](#address) |  |")
+	assert.Contains(t, string(data), "### Address")
+
+	data, err = ioutil.ReadFile(generationUtil.Prefixed("./testData/docs/apiPersonService.html"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `<h3 id="getperson">getPerson</h3>`)
+	assert.Contains(t, string(data), "<p>Person is someone that can place orders | or not</p>")
+
+	data, err = ioutil.ReadFile(generationUtil.Prefixed("./testData/docs/aggregatePerson.md"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "    [*] --> Person: PersonCreated\n    Person --> Person: PersonRemoved")
+	assert.Contains(t, string(data), "| personUID | `string` |  |")
+
+	_, err = os.Stat(generationUtil.Prefixed("./testData/docs/aggregatePerson.html"))
+	assert.NoError(t, err)
+}
+
+func TestGenerateForDocsEscapesHTML(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			DocLines: []string{
+				"// PersonService manages <persons>",
+				`// @RestService( path = "/api" )`,
+			},
+			PackageName: "testData",
+			Name:        "PersonService",
+			Operations: []*model.Operation{
+				{
+					DocLines:      []string{`// @RestOperation( method = "GET", path = "/person/{uid}", format = "JSON" )`},
+					Name:          "getPerson",
+					RelatedStruct: &model.Field{TypeName: "PersonService"},
+					InputArgs:     []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}},
+					OutputArgs:    []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+				},
+			},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Nil(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/docs/apiPersonService.html"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "<p>PersonService manages &lt;persons&gt;</p>")
+}
@@ -0,0 +1,185 @@
+package entity
+
+const entityRepositoryTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+
+package {{.PackageName}}
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+{{ $entity := .Entity.Name -}}
+{{ $data := . -}}
+
+// {{$entity}}SQLRepository stores {{$entity}} in table {{.Table}} using prepared statements
+type {{$entity}}SQLRepository struct {
+	db          *sql.DB
+	insertStmt  *sql.Stmt
+	updateStmt  *sql.Stmt
+	deleteStmt  *sql.Stmt
+	getByIDStmt *sql.Stmt
+}
+
+func New{{$entity}}SQLRepository(c context.Context, db *sql.DB) (*{{$entity}}SQLRepository, error) {
+	var err error
+	repo := &{{$entity}}SQLRepository{db: db}
+
+	repo.insertStmt, err = db.PrepareContext(c, "{{InsertSQL .}}")
+	if err != nil {
+		repo.Close()
+		return nil, fmt.Errorf("Error preparing insert for {{$entity}}: %s", err)
+	}
+	repo.updateStmt, err = db.PrepareContext(c, "{{UpdateSQL .}}")
+	if err != nil {
+		repo.Close()
+		return nil, fmt.Errorf("Error preparing update for {{$entity}}: %s", err)
+	}
+	repo.deleteStmt, err = db.PrepareContext(c, "{{DeleteSQL .}}")
+	if err != nil {
+		repo.Close()
+		return nil, fmt.Errorf("Error preparing delete for {{$entity}}: %s", err)
+	}
+	repo.getByIDStmt, err = db.PrepareContext(c, "{{SelectByIDSQL .}}")
+	if err != nil {
+		repo.Close()
+		return nil, fmt.Errorf("Error preparing get-by-id for {{$entity}}: %s", err)
+	}
+	return repo, nil
+}
+
+func (repo *{{$entity}}SQLRepository) Close() error {
+	for _, stmt := range []*sql.Stmt{repo.insertStmt, repo.updateStmt, repo.deleteStmt, repo.getByIDStmt} {
+		if stmt != nil {
+			stmt.Close()
+		}
+	}
+	return nil
+}
+
+func (repo *{{$entity}}SQLRepository) Insert(c context.Context, entity *{{$entity}}) error {
+{{- if .ID.IsAuto }}
+{{- if IsPostgres .Dialect }}
+	err := repo.insertStmt.QueryRowContext(c{{range InsertColumns .}}, entity.{{.Field.Name}}{{end}}).Scan(&entity.{{.ID.Field.Name}})
+	if err != nil {
+		return fmt.Errorf("Error inserting {{$entity}}: %s", err)
+	}
+{{- else }}
+	result, err := repo.insertStmt.ExecContext(c{{range InsertColumns .}}, entity.{{.Field.Name}}{{end}})
+	if err != nil {
+		return fmt.Errorf("Error inserting {{$entity}}: %s", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("Error determining id of inserted {{$entity}}: %s", err)
+	}
+	entity.{{.ID.Field.Name}} = {{Dereferenced .ID.Field}}(id)
+{{- end }}
+{{- else }}
+	_, err := repo.insertStmt.ExecContext(c{{range InsertColumns .}}, entity.{{.Field.Name}}{{end}})
+	if err != nil {
+		return fmt.Errorf("Error inserting {{$entity}}: %s", err)
+	}
+{{- end }}
+	return nil
+}
+
+func (repo *{{$entity}}SQLRepository) Update(c context.Context, entity {{$entity}}) error {
+	result, err := repo.updateStmt.ExecContext(c{{range UpdateColumns .}}, entity.{{.Field.Name}}{{end}}, entity.{{.ID.Field.Name}})
+	if err != nil {
+		return fmt.Errorf("Error updating {{$entity}} with id %v: %s", entity.{{.ID.Field.Name}}, err)
+	}
+	return expectAffected{{$entity}}(result, entity.{{.ID.Field.Name}})
+}
+
+func (repo *{{$entity}}SQLRepository) Delete(c context.Context, id {{.ID.Field.TypeName}}) error {
+	result, err := repo.deleteStmt.ExecContext(c, id)
+	if err != nil {
+		return fmt.Errorf("Error deleting {{$entity}} with id %v: %s", id, err)
+	}
+	return expectAffected{{$entity}}(result, id)
+}
+
+func (repo *{{$entity}}SQLRepository) GetByID(c context.Context, id {{.ID.Field.TypeName}}) (*{{$entity}}, error) {
+	entity, err := scan{{$entity}}(repo.getByIDStmt.QueryRowContext(c, id))
+	if err == sql.ErrNoRows {
+		return nil, errorh.NewNotFoundErrorf(0, "{{$entity}} with id %v not found", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Error fetching {{$entity}} with id %v: %s", id, err)
+	}
+	return entity, nil
+}
+
+// {{$entity}}Filter restricts the result of List: nil-fields are ignored
+type {{$entity}}Filter struct {
+{{- range FilterColumns . }}
+	{{.Field.Name}} *{{Dereferenced .Field}}
+{{- end }}
+	Limit  int
+	Offset int
+}
+
+func (repo *{{$entity}}SQLRepository) List(c context.Context, filter {{$entity}}Filter) ([]{{$entity}}, error) {
+	conditions := []string{}
+	args := []interface{}{}
+{{- range FilterColumns . }}
+	if filter.{{.Field.Name}} != nil {
+		args = append(args, *filter.{{.Field.Name}})
+		conditions = append(conditions, {{FilterCondition $data .}})
+	}
+{{- end }}
+
+	query := "{{SelectSQL .}}"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY {{.ID.Name}}"
+	if filter.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query += fmt.Sprintf(" OFFSET %d", filter.Offset)
+	}
+
+	rows, err := repo.db.QueryContext(c, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Error listing {{$entity}}: %s", err)
+	}
+	defer rows.Close()
+
+	entities := []{{$entity}}{}
+	for rows.Next() {
+		entity, err := scan{{$entity}}(rows)
+		if err != nil {
+			return nil, fmt.Errorf("Error scanning {{$entity}}: %s", err)
+		}
+		entities = append(entities, *entity)
+	}
+	return entities, rows.Err()
+}
+
+func scan{{$entity}}(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*{{$entity}}, error) {
+	entity := {{$entity}}{}
+	err := scanner.Scan({{range $idx, $column := .Columns}}{{if $idx}}, {{end}}&entity.{{$column.Field.Name}}{{end}})
+	if err != nil {
+		return nil, err
+	}
+	return &entity, nil
+}
+
+func expectAffected{{$entity}}(result sql.Result, id {{.ID.Field.TypeName}}) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("Error determining affected rows for {{$entity}} with id %v: %s", id, err)
+	}
+	if affected == 0 {
+		return errorh.NewNotFoundErrorf(0, "{{$entity}} with id %v not found", id)
+	}
+	return nil
+}
+`
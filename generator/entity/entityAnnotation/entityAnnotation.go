@@ -0,0 +1,59 @@
+package entityAnnotation
+
+import "github.com/MarcGrol/golangAnnotations/generator/annotation"
+
+const (
+	TypeEntity      = "Entity"
+	TypeID          = "Id"
+	TypeColumn      = "Column"
+	ParamTable      = "table"
+	ParamDialect    = "dialect"
+	ParamAuto       = "auto"
+	ParamName       = "name"
+	ParamType       = "type"
+	ParamFilter     = "filter"
+	ParamNullable   = "nullable"
+	DialectPostgres = "postgres"
+	DialectMySQL    = "mysql"
+)
+
+func Get() []annotation.AnnotationDescriptor {
+	return []annotation.AnnotationDescriptor{
+		{
+			Name:       TypeEntity,
+			ParamNames: []string{ParamTable, ParamDialect},
+			Validator:  validateEntityAnnotation,
+		},
+		{
+			Name:       TypeID,
+			ParamNames: []string{ParamAuto, ParamName, ParamType},
+			Validator:  validateIDAnnotation,
+		},
+		{
+			Name:       TypeColumn,
+			ParamNames: []string{ParamName, ParamType, ParamFilter, ParamNullable},
+			Validator:  validateColumnAnnotation,
+		}}
+}
+
+func validateEntityAnnotation(annot annotation.Annotation) bool {
+	if annot.Name == TypeEntity {
+		dialect, hasDialect := annot.Attributes[ParamDialect]
+		return !hasDialect || dialect == DialectPostgres || dialect == DialectMySQL
+	}
+	return false
+}
+
+func validateIDAnnotation(annot annotation.Annotation) bool {
+	if annot.Name == TypeID {
+		return true
+	}
+	return false
+}
+
+func validateColumnAnnotation(annot annotation.Annotation) bool {
+	if annot.Name == TypeColumn {
+		return true
+	}
+	return false
+}
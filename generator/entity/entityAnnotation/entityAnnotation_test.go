@@ -0,0 +1,40 @@
+package entityAnnotation
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrectEntityAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	ann, ok := registry.ResolveAnnotationByName([]string{`// @Entity( table = "persons", dialect = "mysql" )`}, TypeEntity)
+	assert.True(t, ok)
+	assert.Equal(t, "persons", ann.Attributes["table"])
+	assert.Equal(t, "mysql", ann.Attributes["dialect"])
+}
+
+func TestInvalidDialectEntityAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @Entity( dialect = "oracle" )`}))
+}
+
+func TestCorrectIDAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	ann, ok := registry.ResolveAnnotationByName([]string{`// @Id( auto = "true" )`}, TypeID)
+	assert.True(t, ok)
+	assert.Equal(t, "true", ann.Attributes["auto"])
+}
+
+func TestCorrectColumnAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	ann, ok := registry.ResolveAnnotationByName([]string{`// @Column( name = "last_name", filter = "true" )`}, TypeColumn)
+	assert.True(t, ok)
+	assert.Equal(t, "last_name", ann.Attributes["name"])
+	assert.Equal(t, "true", ann.Attributes["filter"])
+}
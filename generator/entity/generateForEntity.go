@@ -0,0 +1,304 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/MarcGrol/golangAnnotations/generator/entity/entityAnnotation"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+// Column describes how a single struct-field is mapped onto a table-column
+type Column struct {
+	Name       string
+	Field      model.Field
+	SQLType    string
+	IsID       bool
+	IsAuto     bool
+	IsFilter   bool
+	IsNullable bool
+}
+
+type entityContext struct {
+	PackageName string
+	Entity      model.Struct
+	Table       string
+	Dialect     string
+	ID          Column
+	Columns     []Column
+}
+
+type Generator struct {
+}
+
+func NewGenerator() generator.Generator {
+	return &Generator{}
+}
+
+func (eg *Generator) Name() string {
+	return "entity"
+}
+
+func (eg *Generator) GetTemplates() []generator.Template {
+	return []generator.Template{
+		{Name: "entity-repository", APIVersion: 1, Text: entityRepositoryTemplate},
+	}
+}
+
+func (eg *Generator) GetAnnotations() []annotation.AnnotationDescriptor {
+	return entityAnnotation.Get()
+}
+
+func (eg *Generator) Generate(parsedSource model.ParsedSources, config generator.Config) ([]generator.OutputFile, error) {
+	return generate(config, parsedSource.Structs)
+}
+
+func generate(config generator.Config, structs []model.Struct) ([]generator.OutputFile, error) {
+	packageName, err := generationUtil.GetPackageNameForStructs(structs)
+	if packageName == "" || err != nil {
+		return nil, err
+	}
+	targetDir, err := generationUtil.DetermineTargetPath(config.InputDir, packageName)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []generator.OutputFile{}
+
+	for _, s := range structs {
+		if !IsEntity(s) {
+			continue
+		}
+		idColumn, found := GetIDColumn(s)
+		if !found {
+			return nil, generator.StructError(s, entityAnnotation.TypeEntity, "Entity %s has no field annotated with @Id", s.Name)
+		}
+
+		file, err := generationUtil.Generate(generationUtil.Info{
+			Src:            fmt.Sprintf("%s.%s", s.PackageName, s.Name),
+			TypeName:       s.Name,
+			TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/%sSQLRepository.go", targetDir, toFirstLower(s.Name))),
+			TemplateName:   "entity-repository",
+			TemplateString: entityRepositoryTemplate,
+			Config:         config,
+			FuncMap:        customTemplateFuncs,
+			Data: entityContext{
+				PackageName: packageName,
+				Entity:      s,
+				Table:       GetTableName(s),
+				Dialect:     GetDialect(s),
+				ID:          idColumn,
+				Columns:     GetColumns(s),
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Error generating sql-repository for entity %s:%w", s.Name, err)
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+var customTemplateFuncs = template.FuncMap{
+	"InsertSQL":       InsertSQL,
+	"UpdateSQL":       UpdateSQL,
+	"DeleteSQL":       DeleteSQL,
+	"SelectSQL":       SelectSQL,
+	"SelectByIDSQL":   SelectByIDSQL,
+	"InsertColumns":   insertColumns,
+	"UpdateColumns":   updateColumns,
+	"FilterColumns":   filterColumns,
+	"FilterCondition": FilterCondition,
+	"IsPostgres":      isPostgres,
+	"ToFirstLower":    toFirstLower,
+	"Dereferenced":    dereferenced,
+}
+
+func IsEntity(s model.Struct) bool {
+	annotations := annotation.NewRegistry(entityAnnotation.Get())
+	_, ok := annotations.ResolveAnnotationByName(s.DocLines, entityAnnotation.TypeEntity)
+	return ok
+}
+
+func GetTableName(s model.Struct) string {
+	annotations := annotation.NewRegistry(entityAnnotation.Get())
+	if ann, ok := annotations.ResolveAnnotationByName(s.DocLines, entityAnnotation.TypeEntity); ok {
+		if table := ann.Attributes[entityAnnotation.ParamTable]; table != "" {
+			return table
+		}
+	}
+	return generationUtil.SnakeCase(s.Name)
+}
+
+func GetDialect(s model.Struct) string {
+	annotations := annotation.NewRegistry(entityAnnotation.Get())
+	if ann, ok := annotations.ResolveAnnotationByName(s.DocLines, entityAnnotation.TypeEntity); ok {
+		if dialect := ann.Attributes[entityAnnotation.ParamDialect]; dialect != "" {
+			return dialect
+		}
+	}
+	return entityAnnotation.DialectPostgres
+}
+
+func GetIDColumn(s model.Struct) (Column, bool) {
+	for _, c := range GetColumns(s) {
+		if c.IsID {
+			return c, true
+		}
+	}
+	return Column{}, false
+}
+
+// GetColumns returns all fields that are stored: the @Id-field, fields annotated with @Column and all exported scalar fields
+func GetColumns(s model.Struct) []Column {
+	annotations := annotation.NewRegistry(entityAnnotation.Get())
+
+	columns := make([]Column, 0, len(s.Fields))
+	for _, f := range s.Fields {
+		if f.Name == "" || !unicode.IsUpper([]rune(f.Name)[0]) {
+			continue
+		}
+		column := Column{
+			Name:       generationUtil.SnakeCase(f.Name),
+			Field:      f,
+			IsNullable: f.IsPointer(),
+		}
+		if ann, ok := annotations.ResolveAnnotationByName(f.DocLines, entityAnnotation.TypeID); ok {
+			column.IsID = true
+			column.IsAuto = ann.Attributes[entityAnnotation.ParamAuto] == "true"
+			applyColumnAttributes(&column, ann)
+		} else if ann, ok := annotations.ResolveAnnotationByName(f.DocLines, entityAnnotation.TypeColumn); ok {
+			column.IsFilter = ann.Attributes[entityAnnotation.ParamFilter] == "true"
+			if nullable, ok := ann.Attributes[entityAnnotation.ParamNullable]; ok {
+				column.IsNullable = nullable == "true"
+			}
+			applyColumnAttributes(&column, ann)
+		} else if !IsScalar(f) {
+			continue
+		}
+		columns = append(columns, column)
+	}
+	return columns
+}
+
+func applyColumnAttributes(column *Column, ann annotation.Annotation) {
+	if name := ann.Attributes[entityAnnotation.ParamName]; name != "" {
+		column.Name = name
+	}
+	column.SQLType = ann.Attributes[entityAnnotation.ParamType]
+}
+
+// IsScalar tells if a field can be stored in a single column without explicit mapping
+func IsScalar(f model.Field) bool {
+	switch f.DereferencedTypeName() {
+	case "string", "bool", "int", "int32", "int64", "float32", "float64", "time.Time":
+		return true
+	}
+	return false
+}
+
+func isPostgres(dialect string) bool {
+	return dialect == entityAnnotation.DialectPostgres
+}
+
+func placeholder(dialect string, idx int) string {
+	if isPostgres(dialect) {
+		return fmt.Sprintf("$%d", idx)
+	}
+	return "?"
+}
+
+func columnNames(columns []Column) string {
+	names := make([]string, 0, len(columns))
+	for _, c := range columns {
+		names = append(names, c.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+func insertColumns(data entityContext) []Column {
+	columns := make([]Column, 0, len(data.Columns))
+	for _, c := range data.Columns {
+		if !c.IsAuto {
+			columns = append(columns, c)
+		}
+	}
+	return columns
+}
+
+func updateColumns(data entityContext) []Column {
+	columns := make([]Column, 0, len(data.Columns))
+	for _, c := range data.Columns {
+		if !c.IsID {
+			columns = append(columns, c)
+		}
+	}
+	return columns
+}
+
+func filterColumns(data entityContext) []Column {
+	columns := make([]Column, 0, len(data.Columns))
+	for _, c := range data.Columns {
+		if c.IsFilter || c.IsID {
+			columns = append(columns, c)
+		}
+	}
+	return columns
+}
+
+func InsertSQL(data entityContext) string {
+	columns := insertColumns(data)
+	placeholders := make([]string, 0, len(columns))
+	for idx := range columns {
+		placeholders = append(placeholders, placeholder(data.Dialect, idx+1))
+	}
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", data.Table, columnNames(columns), strings.Join(placeholders, ", "))
+	if data.ID.IsAuto && isPostgres(data.Dialect) {
+		query += fmt.Sprintf(" RETURNING %s", data.ID.Name)
+	}
+	return query
+}
+
+func UpdateSQL(data entityContext) string {
+	columns := updateColumns(data)
+	assignments := make([]string, 0, len(columns))
+	for idx, c := range columns {
+		assignments = append(assignments, fmt.Sprintf("%s = %s", c.Name, placeholder(data.Dialect, idx+1)))
+	}
+	return fmt.Sprintf("UPDATE %s SET %s WHERE %s = %s", data.Table, strings.Join(assignments, ", "), data.ID.Name, placeholder(data.Dialect, len(columns)+1))
+}
+
+func DeleteSQL(data entityContext) string {
+	return fmt.Sprintf("DELETE FROM %s WHERE %s = %s", data.Table, data.ID.Name, placeholder(data.Dialect, 1))
+}
+
+func SelectSQL(data entityContext) string {
+	return fmt.Sprintf("SELECT %s FROM %s", columnNames(data.Columns), data.Table)
+}
+
+func SelectByIDSQL(data entityContext) string {
+	return fmt.Sprintf("%s WHERE %s = %s", SelectSQL(data), data.ID.Name, placeholder(data.Dialect, 1))
+}
+
+// FilterCondition returns the go-expression that renders the where-condition for a filter-column
+func FilterCondition(data entityContext, c Column) string {
+	if isPostgres(data.Dialect) {
+		return fmt.Sprintf("fmt.Sprintf(\"%s = $%%d\", len(args))", c.Name)
+	}
+	return fmt.Sprintf("\"%s = ?\"", c.Name)
+}
+
+func dereferenced(f model.Field) string {
+	return f.DereferencedTypeName()
+}
+
+func toFirstLower(in string) string {
+	a := []rune(in)
+	a[0] = unicode.ToLower(a[0])
+	return string(a)
+}
@@ -0,0 +1,93 @@
+package entity
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func cleanup() {
+	os.Remove(generationUtil.Prefixed("./testData/personSQLRepository.go"))
+	os.Remove(generationUtil.Prefixed("./testData/invoiceSQLRepository.go"))
+}
+
+func TestGenerateForEntity(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @Entity( table = "persons" )`},
+			Name:        "Person",
+			Fields: []model.Field{
+				{Name: "ID", TypeName: "int64", DocLines: []string{`// @Id( auto = "true" )`}},
+				{Name: "FirstName", TypeName: "string", DocLines: []string{`// @Column( filter = "true" )`}},
+				{Name: "Email", TypeName: "*string", DocLines: []string{`// @Column( name = "email_address" )`}},
+				{Name: "CreatedAt", TypeName: "time.Time"},
+				{Name: "Tags", TypeName: "[]string"},
+				{Name: "internal", TypeName: "string"},
+			},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Nil(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/personSQLRepository.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `func NewPersonSQLRepository(c context.Context, db *sql.DB) (*PersonSQLRepository, error) {`)
+	assert.Contains(t, string(data), `"INSERT INTO persons (first_name, email_address, created_at) VALUES ($1, $2, $3) RETURNING id"`)
+	assert.Contains(t, string(data), `"UPDATE persons SET first_name = $1, email_address = $2, created_at = $3 WHERE id = $4"`)
+	assert.Contains(t, string(data), `"DELETE FROM persons WHERE id = $1"`)
+	assert.Contains(t, string(data), `"SELECT id, first_name, email_address, created_at FROM persons WHERE id = $1"`)
+	assert.Contains(t, string(data), `.Scan(&entity.ID)`)
+	assert.Contains(t, string(data), `FirstName *string`)
+	assert.Contains(t, string(data), `conditions = append(conditions, fmt.Sprintf("first_name = $%d", len(args)))`)
+	assert.Contains(t, string(data), `err := scanner.Scan(&entity.ID, &entity.FirstName, &entity.Email, &entity.CreatedAt)`)
+	assert.NotContains(t, string(data), `tags`)
+}
+
+func TestGenerateForEntityMySQL(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @Entity( dialect = "mysql" )`},
+			Name:        "Invoice",
+			Fields: []model.Field{
+				{Name: "InvoiceID", TypeName: "int", DocLines: []string{`// @Id( auto = "true" )`}},
+				{Name: "Amount", TypeName: "float64"},
+			},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Nil(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/invoiceSQLRepository.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"INSERT INTO invoice (amount) VALUES (?)"`)
+	assert.Contains(t, string(data), `entity.InvoiceID = int(id)`)
+	assert.Contains(t, string(data), `"SELECT invoice_id, amount FROM invoice WHERE invoice_id = ?"`)
+}
+
+func TestGenerateForEntityWithoutID(t *testing.T) {
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @Entity()`},
+			Name:        "Person",
+			Fields:      []model.Field{{Name: "Name", TypeName: "string"}},
+		},
+	}
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Error(t, err)
+}
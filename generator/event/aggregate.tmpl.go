@@ -28,6 +28,12 @@ var AggregateEvents = map[string][]string{
 {{end -}}
 }
 
+// VersionedAggregate is implemented by aggregates that keep their version: the sequence-number of their last event,
+// for optimistic concurrency
+type VersionedAggregate interface {
+	SetAggregateVersion(version int64)
+}
+
 {{range $aggr, $events := .AggregateMap}}
 
 // {{$aggr}}Aggregate provides an interface that forces all events related to an aggregate are handled
@@ -73,6 +79,9 @@ func Apply{{$aggr}}Event(c context.Context, rc request.Context, envlp envelope.E
 		AggregateUID:  envlp.AggregateUID,
 		EventTypeName: envlp.EventTypeName,
 	})
+	if versioned, ok := aggregateRoot.(VersionedAggregate); ok {
+		versioned.SetAggregateVersion(envlp.SequenceNumber)
+	}
 
 	return nil
 }
@@ -107,6 +116,49 @@ func UnWrap{{$aggr}}Event(envlp *envelope.Envelope) (envelope.Event, error) {
 	}
 }
 
+{{if $events.IsTenantScoped -}}
+// {{$aggr}}TenantFilter keeps the envelopes with the events of a tenant, before filtering them further with Next
+type {{$aggr}}TenantFilter struct {
+	TenantID string
+	Next     envelope.EnvelopeFilter
+}
+
+// FilteredEnvelopes keeps the envelopes of the tenant of the filter
+func (f {{$aggr}}TenantFilter) FilteredEnvelopes(envelopes []envelope.Envelope) ([]envelope.Envelope, error) {
+	filtered := make([]envelope.Envelope, 0, len(envelopes))
+	for _, envlp := range envelopes {
+		tenantID, err := Get{{$aggr}}TenantID(&envlp)
+		if err != nil {
+			return nil, err
+		}
+		if tenantID == f.TenantID {
+			filtered = append(filtered, envlp)
+		}
+	}
+	if f.Next == nil {
+		return filtered, nil
+	}
+	return f.Next.FilteredEnvelopes(filtered)
+}
+
+// Get{{$aggr}}TenantID returns the tenant of the event in an envelope
+func Get{{$aggr}}TenantID(envlp *envelope.Envelope) (string, error) {
+	switch envlp.EventTypeName {
+		{{range $aggregName, $event := $events.Events -}}
+			case {{$event.Name}}EventName:
+				evt, err := UnWrap{{$event.Name}}(envlp)
+				if err != nil {
+					return "", err
+				}
+				return evt.{{$event.TenantField}}, nil
+		{{end -}}
+		default:
+		return "", fmt.Errorf("Get{{$aggr}}TenantID: Unexpected event %s", envlp.EventTypeName)
+	}
+}
+
+{{end -}}
+
 {{if $events.IsAnySensitive -}}
 // Anonymize{{$aggr}}Envelopes anonymizes the events wrapped by the envelopes
 func Anonymize{{$aggr}}Envelopes(envelopes []envelope.Envelope) ([]envelope.Envelope, error) {
@@ -1,6 +1,10 @@
 package eventAnnotation
 
-import "github.com/MarcGrol/golangAnnotations/generator/annotation"
+import (
+	"strconv"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+)
 
 const (
 	TypeEvent         = "Event"
@@ -9,6 +13,7 @@ const (
 	ParamIsRootEvent  = "isrootevent"
 	ParamIsTransient  = "istransient"
 	ParamIsSensitive  = "issensitive"
+	ParamVersion      = "version"
 	FieldTagSensitive = "sensitive"
 )
 
@@ -17,7 +22,7 @@ func Get() []annotation.AnnotationDescriptor {
 	return []annotation.AnnotationDescriptor{
 		{
 			Name:       TypeEvent,
-			ParamNames: []string{ParamAggregate, ParamIsRootEvent, ParamIsTransient, ParamIsSensitive},
+			ParamNames: []string{ParamAggregate, ParamIsRootEvent, ParamIsTransient, ParamIsSensitive, ParamVersion},
 			Validator:  validateEventAnnotation,
 		},
 		{
@@ -32,6 +37,11 @@ func validateEventAnnotation(annot annotation.Annotation) bool {
 	switch annot.Name {
 	case TypeEvent:
 		val, hasAggr := annot.Attributes[ParamAggregate]
+		if version, found := annot.Attributes[ParamVersion]; found {
+			if number, err := strconv.Atoi(version); err != nil || number < 0 {
+				return false
+			}
+		}
 		return hasAggr && val != ""
 	case TypeEventPart:
 		return true
@@ -27,3 +27,12 @@ func TestEmptyEventAnnotation(t *testing.T) {
 
 	assert.Empty(t, registry.ResolveAnnotations([]string{`// @Event( aggregate = "")`}))
 }
+
+func TestEventAnnotationWithVersion(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	ann, ok := registry.ResolveAnnotationByName([]string{`// @Event( aggregate = "test", version = "2" )`}, "Event")
+	assert.True(t, ok)
+	assert.Equal(t, "2", ann.Attributes[ParamVersion])
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @Event( aggregate = "test", version = "v2" )`}))
+}
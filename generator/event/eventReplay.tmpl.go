@@ -0,0 +1,234 @@
+package event
+
+const eventReplayTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+
+package {{.PackageName}}
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+)
+
+// EventStreamsDir is the directory of the recorded event-streams that replay-tests load
+const EventStreamsDir = "testdata/events"
+
+// RecordedEvent is an event of a recorded event-stream: a json-fixture holds a list of them, in the order in which
+// they happened. UUID and Timestamp are optional.
+type RecordedEvent struct {
+	UUID          string          ` + "`" + `json:"uuid,omitempty"` + "`" + `
+	Timestamp     *time.Time      ` + "`" + `json:"timestamp,omitempty"` + "`" + `
+	AggregateUID  string          ` + "`" + `json:"aggregateUID"` + "`" + `
+	EventTypeName string          ` + "`" + `json:"eventTypeName"` + "`" + `
+	Event         json.RawMessage ` + "`" + `json:"event"` + "`" + `
+}
+
+// LoadEventStream reads a recorded event-stream from a json-fixture and returns its events as envelopes, numbered in
+// the order of the fixture
+func LoadEventStream(filename string) ([]envelope.Envelope, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	recorded := []RecordedEvent{}
+	err = json.Unmarshal(data, &recorded)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading event-stream %s: %w", filename, err)
+	}
+	envelopes := []envelope.Envelope{}
+	for idx, evt := range recorded {
+		envlp := envelope.Envelope{
+			UUID:           evt.UUID,
+			SequenceNumber: int64(idx + 1),
+			AggregateUID:   evt.AggregateUID,
+			EventTypeName:  evt.EventTypeName,
+			EventData:      string(evt.Event),
+		}
+		switch evt.EventTypeName {
+{{- range .Events}}
+		case {{.Name}}EventName:
+			envlp.AggregateName, envlp.EventTypeVersion = {{GetAggregateName .}}AggregateName, {{GetEventVersion .}}
+{{- end}}
+		default:
+			return nil, fmt.Errorf("Event %d of event-stream %s has unknown event-type '%s'", idx+1, filename, evt.EventTypeName)
+		}
+		if envlp.UUID == "" {
+			// events are applied once per uuid
+			envlp.UUID = fmt.Sprintf("replayed-%d", idx+1)
+		}
+		if evt.Timestamp != nil {
+			envlp.Timestamp = *evt.Timestamp
+		}
+		envelopes = append(envelopes, envlp)
+	}
+	return envelopes, nil
+}
+
+// SampleEventStream writes envelopes, like the events of an aggregate that are read from a store, as json-fixture
+// that LoadEventStream reads. The sensitive data of sensitive events is wiped.
+func SampleEventStream(filename string, envelopes []envelope.Envelope) error {
+	recorded := []RecordedEvent{}
+	for _, envlp := range envelopes {
+		evt, err := sampledEvent(envlp)
+		if err != nil {
+			return fmt.Errorf("Error sampling event %s: %w", envlp.UUID, err)
+		}
+		timestamp := envlp.Timestamp
+		recorded = append(recorded, RecordedEvent{
+			UUID:          envlp.UUID,
+			Timestamp:     &timestamp,
+			AggregateUID:  envlp.AggregateUID,
+			EventTypeName: envlp.EventTypeName,
+			Event:         evt,
+		})
+	}
+	return writeReplayFixture(filename, recorded)
+}
+
+// sampledEvent returns the json of the event in an envelope, anonymized when the event is sensitive
+func sampledEvent(envlp envelope.Envelope) (json.RawMessage, error) {
+	switch envlp.EventTypeName {
+{{- range .Events}}{{if IsSensitiveEvent .}}
+	case {{.Name}}EventName:
+		evt, err := UnWrap{{.Name}}(&envlp)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(evt.Anonymized())
+{{- end}}{{end}}
+	}
+	return json.RawMessage(envlp.EventData), nil
+}
+
+func writeReplayFixture(filename string, value interface{}) error {
+	data, err := json.MarshalIndent(value, "", "\t")
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(filename), 0755)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, append(data, '\n'), 0644)
+}
+
+// ReplayT is the part of *testing.T that an EventReplay reports its failures to
+type ReplayT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+	FailNow()
+}
+
+// EventReplay replays a recorded event-stream through aggregates and projections in a test, and asserts their
+// final state:
+//
+//	NewEventReplay(t, c, rc, filepath.Join(EventStreamsDir, "order-shipped.json")).
+//		ReplayOrder("1234", order).
+//		AssertState(filepath.Join(EventStreamsDir, "order-shipped.state.json"), order)
+type EventReplay struct {
+	t         ReplayT
+	c         context.Context
+	rc        request.Context
+	Envelopes []envelope.Envelope
+}
+
+// NewEventReplay loads a recorded event-stream, failing the test when it cannot be read
+func NewEventReplay(t ReplayT, c context.Context, rc request.Context, filename string) *EventReplay {
+	t.Helper()
+	envelopes, err := LoadEventStream(filename)
+	if err != nil {
+		t.Errorf("Error loading event-stream: %s", err)
+		t.FailNow()
+	}
+	return &EventReplay{t: t, c: c, rc: rc, Envelopes: envelopes}
+}
+{{range .Aggregates}}{{$aggr := .Name}}
+// Replay{{$aggr}} applies the events of aggregate {{$aggr}} with aggregateUID to aggregateRoot, or the events of all
+// {{$aggr}}s when aggregateUID is empty
+func (r *EventReplay) Replay{{$aggr}}(aggregateUID string, aggregateRoot {{$aggr}}Aggregate) *EventReplay {
+	r.t.Helper()
+	for _, envlp := range r.Envelopes {
+		if envlp.AggregateName != {{$aggr}}AggregateName || (aggregateUID != "" && envlp.AggregateUID != aggregateUID) {
+			continue
+		}
+		switch envlp.EventTypeName {
+		case {{range $idx, $name := .PersistentEvents}}{{if $idx}}, {{end}}{{$name}}EventName{{end}}:
+			err := Apply{{$aggr}}Event(r.c, r.rc, envlp, aggregateRoot)
+			if err != nil {
+				r.t.Errorf("Error applying event %d (%s) to {{$aggr}} %s: %s", envlp.SequenceNumber, envlp.EventTypeName, envlp.AggregateUID, err)
+				r.t.FailNow()
+			}
+		}
+	}
+	return r
+}
+{{end}}
+// Project passes all events of the stream to a projection, failing the test when it returns an error
+func (r *EventReplay) Project(handler Handler) *EventReplay {
+	r.t.Helper()
+	for _, envlp := range r.Envelopes {
+		err := projectEvent(r.c, r.rc, envlp, handler)
+		if err != nil {
+			r.t.Errorf("Error projecting event %d (%s): %s", envlp.SequenceNumber, envlp.EventTypeName, err)
+			r.t.FailNow()
+		}
+	}
+	return r
+}
+
+func projectEvent(c context.Context, rc request.Context, envlp envelope.Envelope, handler Handler) error {
+	switch envlp.EventTypeName {
+{{- range .Events}}
+	case {{.Name}}EventName:
+		evt, err := UnWrap{{.Name}}(&envlp)
+		if err != nil {
+			return err
+		}
+		return handler.On{{.Name}}{{if IsTransientEvent .}}Transient{{end}}(c, rc, *evt)
+{{- end}}
+	}
+	return fmt.Errorf("Unexpected event %s", envlp.EventTypeName)
+}
+
+// AssertState compares the final state of an aggregate or projection, as json, with the expected state in a
+// json-fixture. A missing fixture is written with the state, to be reviewed and committed: remove it to record the
+// state again after an intended change.
+func (r *EventReplay) AssertState(filename string, state interface{}) *EventReplay {
+	r.t.Helper()
+	actual, err := json.MarshalIndent(state, "", "\t")
+	if err != nil {
+		r.t.Errorf("Error marshalling state: %s", err)
+		r.t.FailNow()
+	}
+	expected, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		err = writeReplayFixture(filename, state)
+		if err != nil {
+			r.t.Errorf("Error recording state %s: %s", filename, err)
+			r.t.FailNow()
+		}
+		return r
+	}
+	if err != nil {
+		r.t.Errorf("Error reading state %s: %s", filename, err)
+		r.t.FailNow()
+	}
+	var expectedValue, actualValue interface{}
+	err = json.Unmarshal(expected, &expectedValue)
+	if err != nil {
+		r.t.Errorf("Error reading state %s: %s", filename, err)
+		r.t.FailNow()
+	}
+	_ = json.Unmarshal(actual, &actualValue)
+	if !reflect.DeepEqual(expectedValue, actualValue) {
+		r.t.Errorf("State differs from %s:\nexpected: %s\nactual:   %s", filename, bytes.TrimSpace(expected), actual)
+	}
+	return r
+}
+`
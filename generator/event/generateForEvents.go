@@ -2,7 +2,8 @@ package event
 
 import (
 	"fmt"
-	"log"
+	"sort"
+	"strconv"
 	"strings"
 	"text/template"
 	"unicode"
@@ -11,6 +12,8 @@ import (
 	"github.com/MarcGrol/golangAnnotations/generator/annotation"
 	"github.com/MarcGrol/golangAnnotations/generator/event/eventAnnotation"
 	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/generator/tenant"
+	"github.com/MarcGrol/golangAnnotations/generator/tenant/tenantAnnotation"
 	"github.com/MarcGrol/golangAnnotations/model"
 )
 
@@ -18,12 +21,14 @@ type eventMap struct {
 	Events          map[string]event
 	IsAnyPersistent bool
 	IsAnySensitive  bool
+	IsTenantScoped  bool
 }
 
 type event struct {
 	Name         string
 	IsPersistent bool
 	IsSensitive  bool
+	TenantField  string
 }
 
 type aggregateMap struct {
@@ -36,6 +41,18 @@ type structures struct {
 	Structs     []model.Struct
 }
 
+type replayContext struct {
+	PackageName string
+	Events      []model.Struct
+	// Aggregates are the aggregates with persistent events, that can be replayed
+	Aggregates []replayedAggregate
+}
+
+type replayedAggregate struct {
+	Name             string
+	PersistentEvents []string
+}
+
 type Generator struct {
 }
 
@@ -43,87 +60,94 @@ func NewGenerator() generator.Generator {
 	return &Generator{}
 }
 
+func (eg *Generator) Name() string {
+	return "event"
+}
+
+func (eg *Generator) GetTemplates() []generator.Template {
+	return []generator.Template{
+		{Name: "aggregates", APIVersion: 1, Text: aggregateTemplate},
+		{Name: "wrappers", APIVersion: 1, Text: wrappersTemplate},
+		{Name: "anonymized", APIVersion: 1, Text: anonymizedTemplate},
+		{Name: "event-store", APIVersion: 1, Text: eventStoreTemplate},
+		{Name: "event-publisher", APIVersion: 1, Text: eventPublisherTemplate},
+		{Name: "wrappers-test", APIVersion: 1, Text: wrappersTestTemplate},
+		{Name: "interface", APIVersion: 1, Text: interfaceTemplate},
+		{Name: "event-replay", APIVersion: 1, Text: eventReplayTemplate},
+	}
+}
+
 func (eg *Generator) GetAnnotations() []annotation.AnnotationDescriptor {
-	return eventAnnotation.Get()
+	return append(eventAnnotation.Get(), tenantAnnotation.Get()...)
 }
 
-func (eg *Generator) Generate(inputDir string, parsedSource model.ParsedSources) error {
-	return generate(inputDir, parsedSource.Structs)
+func (eg *Generator) Generate(parsedSource model.ParsedSources, config generator.Config) ([]generator.OutputFile, error) {
+	return generate(config, parsedSource.Structs)
 }
 
 type generateContext struct {
 	targetDir   string
 	packageName string
 	structs     []model.Struct
+	config      generator.Config
 }
 
-func generate(inputDir string, structs []model.Struct) error {
+func generate(config generator.Config, structs []model.Struct) ([]generator.OutputFile, error) {
 	packageName, err := generationUtil.GetPackageNameForStructs(structs)
 	if packageName == "" || err != nil {
-		return err
+		return nil, err
+	}
+
+	targetDir, err := generationUtil.DetermineTargetPath(config.InputDir, packageName)
+	if err != nil {
+		return nil, err
 	}
 
-	targetDir, err := generationUtil.DetermineTargetPath(inputDir, packageName)
+	err = validateTenants(structs)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	ctx := generateContext{
 		targetDir:   targetDir,
 		packageName: packageName,
 		structs:     structs,
+		config:      config,
+	}
+
+	files := []generator.OutputFile{}
+	for _, generateFunc := range []func(generateContext) ([]generator.OutputFile, error){
+		generateAggregates,
+		generateWrappers,
+		generateAnonymized,
+		generateEventStore,
+		generateEventPublisher,
+		generateWrappersTest,
+		generateHandlerInterface,
+		generateEventReplay,
+	} {
+		generated, err := generateFunc(ctx)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, generated...)
 	}
-
-	err = generateAggregates(ctx)
-	if err != nil {
-		return err
-	}
-
-	err = generateWrappers(ctx)
-	if err != nil {
-		return err
-	}
-
-	err = generateAnonymized(ctx)
-	if err != nil {
-		return err
-	}
-
-	err = generateEventStore(ctx)
-	if err != nil {
-		return err
-	}
-
-	err = generateEventPublisher(ctx)
-	if err != nil {
-		return err
-	}
-
-	err = generateWrappersTest(ctx)
-	if err != nil {
-		return err
-	}
-
-	err = generateHandlerInterface(ctx)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return files, nil
 }
 
-func generateAggregates(ctx generateContext) error {
+func generateAggregates(ctx generateContext) ([]generator.OutputFile, error) {
 
 	aggregates := getAggregates(ctx.structs)
 	if len(aggregates) == 0 {
-		return nil
+		return nil, nil
 	}
 
-	err := generationUtil.Generate(generationUtil.Info{
+	file, err := generationUtil.Generate(generationUtil.Info{
 		Src:            ctx.packageName,
 		TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/aggregates.go", ctx.targetDir)),
 		TemplateName:   "aggregates",
 		TemplateString: aggregateTemplate,
+		Config:         ctx.config,
 		FuncMap:        customTemplateFuncs,
 		Data: aggregateMap{
 			PackageName:  ctx.packageName,
@@ -131,10 +155,9 @@ func generateAggregates(ctx generateContext) error {
 		},
 	})
 	if err != nil {
-		log.Fatalf("Error generating aggregates (%s)", err)
-		return err
+		return nil, fmt.Errorf("Error generating aggregates:%w", err)
 	}
-	return nil
+	return []generator.OutputFile{file}, nil
 }
 
 func getAggregates(structs []model.Struct) map[string]eventMap {
@@ -161,6 +184,11 @@ func getAggregates(structs []model.Struct) map[string]eventMap {
 			if evt.IsSensitive {
 				events.IsAnySensitive = true
 			}
+			if IsTenantScopedEvent(s) {
+				// the events of an aggregate are either all tenant-scoped or none are
+				evt.TenantField = tenant.GetField(s)
+				events.IsTenantScoped = true
+			}
 			events.Events[s.Name] = evt
 			aggregates[GetAggregateName(s)] = events
 			eventCount++
@@ -169,17 +197,48 @@ func getAggregates(structs []model.Struct) map[string]eventMap {
 	return aggregates
 }
 
-func generateWrappers(ctx generateContext) error {
+// validateTenants checks that tenant-scoped events have a string-field for their tenant, and that the other events
+// of their aggregate are tenant-scoped as well: the aggregate would be accessed without a tenant otherwise
+func validateTenants(structs []model.Struct) error {
+	scopedAggregates := map[string]string{}
+	for _, s := range structs {
+		if !IsTenantScopedEvent(s) {
+			continue
+		}
+		scopedAggregates[GetAggregateName(s)] = s.Name
+		if !hasField(s, tenant.GetField(s), "string") {
+			return generator.StructError(s, tenantAnnotation.TypeTenantScoped, "Tenant-scoped event %s has no field '%s string' for its tenant", s.Name, tenant.GetField(s))
+		}
+	}
+	for _, s := range structs {
+		if scopedEvent, found := scopedAggregates[GetAggregateName(s)]; found && IsEvent(s) && !IsTenantScopedEvent(s) {
+			return generator.StructError(s, eventAnnotation.TypeEvent, "Event %s of aggregate %s must be @TenantScoped, like event %s", s.Name, GetAggregateName(s), scopedEvent)
+		}
+	}
+	return nil
+}
+
+func hasField(s model.Struct, name string, typeName string) bool {
+	for _, f := range s.Fields {
+		if f.Name == name && f.TypeName == typeName {
+			return true
+		}
+	}
+	return false
+}
+
+func generateWrappers(ctx generateContext) ([]generator.OutputFile, error) {
 
 	if !containsAny(ctx.structs, IsEvent) {
-		return nil
+		return nil, nil
 	}
 
-	err := generationUtil.Generate(generationUtil.Info{
+	file, err := generationUtil.Generate(generationUtil.Info{
 		Src:            ctx.packageName,
 		TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/wrappers.go", ctx.targetDir)),
 		TemplateName:   "wrappers",
 		TemplateString: wrappersTemplate,
+		Config:         ctx.config,
 		FuncMap:        customTemplateFuncs,
 		Data: structures{
 			PackageName: ctx.packageName,
@@ -187,23 +246,23 @@ func generateWrappers(ctx generateContext) error {
 		},
 	})
 	if err != nil {
-		log.Fatalf("Error generating wrappers for structures (%s)", err)
-		return err
+		return nil, fmt.Errorf("Error generating wrappers for structures:%w", err)
 	}
-	return nil
+	return []generator.OutputFile{file}, nil
 }
 
-func generateAnonymized(ctx generateContext) error {
+func generateAnonymized(ctx generateContext) ([]generator.OutputFile, error) {
 
 	if !containsAny(ctx.structs, IsSensitiveEventOrEventPart) {
-		return nil
+		return nil, nil
 	}
 
-	err := generationUtil.Generate(generationUtil.Info{
+	file, err := generationUtil.Generate(generationUtil.Info{
 		Src:            ctx.packageName,
 		TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/anonymized.go", ctx.targetDir)),
 		TemplateName:   "anonymized",
 		TemplateString: anonymizedTemplate,
+		Config:         ctx.config,
 		FuncMap:        customTemplateFuncs,
 		Data: structures{
 			PackageName: ctx.packageName,
@@ -211,10 +270,9 @@ func generateAnonymized(ctx generateContext) error {
 		},
 	})
 	if err != nil {
-		log.Fatalf("Error generating anonymized for structures (%s)", err)
-		return err
+		return nil, fmt.Errorf("Error generating anonymized for structures:%w", err)
 	}
-	return nil
+	return []generator.OutputFile{file}, nil
 }
 
 func containsAny(structs []model.Struct, predicate func(_ model.Struct) bool) bool {
@@ -226,17 +284,18 @@ func containsAny(structs []model.Struct, predicate func(_ model.Struct) bool) bo
 	return false
 }
 
-func generateEventStore(ctx generateContext) error {
+func generateEventStore(ctx generateContext) ([]generator.OutputFile, error) {
 
 	if !containsAny(ctx.structs, IsPersistentEvent) {
-		return nil
+		return nil, nil
 	}
 
-	err := generationUtil.Generate(generationUtil.Info{
+	file, err := generationUtil.Generate(generationUtil.Info{
 		Src:            ctx.packageName,
 		TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/../%sStore/%sStore.go", ctx.targetDir, ctx.packageName, ctx.packageName)),
 		TemplateName:   "event-store",
 		TemplateString: eventStoreTemplate,
+		Config:         ctx.config,
 		FuncMap:        customTemplateFuncs,
 		Data: structures{
 			PackageName: ctx.packageName,
@@ -244,23 +303,23 @@ func generateEventStore(ctx generateContext) error {
 		},
 	})
 	if err != nil {
-		log.Fatalf("Error generating event-store for structures (%s)", err)
-		return err
+		return nil, fmt.Errorf("Error generating event-store for structures:%w", err)
 	}
-	return nil
+	return []generator.OutputFile{file}, nil
 }
 
-func generateEventPublisher(ctx generateContext) error {
+func generateEventPublisher(ctx generateContext) ([]generator.OutputFile, error) {
 
 	if !containsAny(ctx.structs, isTransient) {
-		return nil
+		return nil, nil
 	}
 
-	err := generationUtil.Generate(generationUtil.Info{
+	file, err := generationUtil.Generate(generationUtil.Info{
 		Src:            ctx.packageName,
 		TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/../%sPublisher/%sPublisher.go", ctx.targetDir, ctx.packageName, ctx.packageName)),
 		TemplateName:   "event-publisher",
 		TemplateString: eventPublisherTemplate,
+		Config:         ctx.config,
 		FuncMap:        customTemplateFuncs,
 		Data: structures{
 			PackageName: ctx.packageName,
@@ -268,23 +327,23 @@ func generateEventPublisher(ctx generateContext) error {
 		},
 	})
 	if err != nil {
-		log.Fatalf("Error generating event-publisher for structures (%s)", err)
-		return err
+		return nil, fmt.Errorf("Error generating event-publisher for structures:%w", err)
 	}
-	return nil
+	return []generator.OutputFile{file}, nil
 }
 
-func generateWrappersTest(ctx generateContext) error {
+func generateWrappersTest(ctx generateContext) ([]generator.OutputFile, error) {
 
 	if !containsAny(ctx.structs, IsEvent) {
-		return nil
+		return nil, nil
 	}
 
-	err := generationUtil.Generate(generationUtil.Info{
+	file, err := generationUtil.Generate(generationUtil.Info{
 		Src:            ctx.packageName,
 		TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/wrappers_test.go", ctx.targetDir)),
 		TemplateName:   "wrappers-test",
 		TemplateString: wrappersTestTemplate,
+		Config:         ctx.config,
 		FuncMap:        customTemplateFuncs,
 		Data: structures{
 			PackageName: ctx.packageName,
@@ -292,23 +351,23 @@ func generateWrappersTest(ctx generateContext) error {
 		},
 	})
 	if err != nil {
-		log.Fatalf("Error generating wrappers-test for structures (%s)", err)
-		return err
+		return nil, fmt.Errorf("Error generating wrappers-test for structures:%w", err)
 	}
-	return nil
+	return []generator.OutputFile{file}, nil
 }
 
-func generateHandlerInterface(ctx generateContext) error {
+func generateHandlerInterface(ctx generateContext) ([]generator.OutputFile, error) {
 
 	if !containsAny(ctx.structs, IsEvent) {
-		return nil
+		return nil, nil
 	}
 
-	err := generationUtil.Generate(generationUtil.Info{
+	file, err := generationUtil.Generate(generationUtil.Info{
 		Src:            ctx.packageName,
 		TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/interface.go", ctx.targetDir)),
 		TemplateName:   "interface",
 		TemplateString: interfaceTemplate,
+		Config:         ctx.config,
 		FuncMap:        customTemplateFuncs,
 		Data: structures{
 			PackageName: ctx.packageName,
@@ -316,10 +375,51 @@ func generateHandlerInterface(ctx generateContext) error {
 		},
 	})
 	if err != nil {
-		log.Fatalf("Error generating interface for event-handlers (%s)", err)
-		return err
+		return nil, fmt.Errorf("Error generating interface for event-handlers:%w", err)
 	}
-	return nil
+	return []generator.OutputFile{file}, nil
+}
+
+func generateEventReplay(ctx generateContext) ([]generator.OutputFile, error) {
+
+	events := GetEvents(structures{Structs: ctx.structs})
+	if len(events) == 0 {
+		return nil, nil
+	}
+
+	data := replayContext{PackageName: ctx.packageName, Events: events}
+	aggregates := getAggregates(ctx.structs)
+	names := []string{}
+	for name := range aggregates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		aggregate := replayedAggregate{Name: name}
+		for _, evt := range aggregates[name].Events {
+			if evt.IsPersistent {
+				aggregate.PersistentEvents = append(aggregate.PersistentEvents, evt.Name)
+			}
+		}
+		if len(aggregate.PersistentEvents) > 0 {
+			sort.Strings(aggregate.PersistentEvents)
+			data.Aggregates = append(data.Aggregates, aggregate)
+		}
+	}
+
+	file, err := generationUtil.Generate(generationUtil.Info{
+		Src:            ctx.packageName,
+		TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/eventReplay.go", ctx.targetDir)),
+		TemplateName:   "event-replay",
+		TemplateString: eventReplayTemplate,
+		Config:         ctx.config,
+		FuncMap:        customTemplateFuncs,
+		Data:           data,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Error generating event-replay for structures:%w", err)
+	}
+	return []generator.OutputFile{file}, nil
 }
 
 var customTemplateFuncs = template.FuncMap{
@@ -334,7 +434,10 @@ var customTemplateFuncs = template.FuncMap{
 	"IsDeepSensitiveField":        IsDeepSensitiveField,
 	"IsCustomSensitiveField":      IsCustomSensitiveField,
 	"GetAggregateName":            GetAggregateName,
+	"IsTenantScopedEvent":         IsTenantScopedEvent,
+	"GetTenantField":              tenant.GetField,
 	"GetAggregateNameLowerCase":   GetAggregateNameLowerCase,
+	"GetEventVersion":             GetEventVersion,
 	"EventIdentifier":             EventIdentifier,
 	"FieldIdentifier":             FieldIdentifier,
 	"SliceFieldIdentifier":        SliceFieldIdentifier,
@@ -385,6 +488,17 @@ func GetAggregateNameLowerCase(s model.Struct) string {
 	return toFirstLower(GetAggregateName(s))
 }
 
+// GetEventVersion returns the version of the schema of an event, that is raised on changes that readers of stored
+// events cannot handle: 0 when the event has no version
+func GetEventVersion(s model.Struct) int {
+	annotations := annotation.NewRegistry(eventAnnotation.Get())
+	if ann, ok := annotations.ResolveAnnotationByName(s.DocLines, eventAnnotation.TypeEvent); ok {
+		version, _ := strconv.Atoi(ann.Attributes[eventAnnotation.ParamVersion])
+		return version
+	}
+	return 0
+}
+
 func IsRootEvent(s model.Struct) bool {
 	if IsEvent(s) {
 		annotations := annotation.NewRegistry(eventAnnotation.Get())
@@ -415,6 +529,10 @@ func IsSensitiveEventOrEventPart(s model.Struct) bool {
 	return IsSensitiveEvent(s) || IsSensitiveEventPart(s)
 }
 
+func IsTenantScopedEvent(s model.Struct) bool {
+	return IsEvent(s) && tenant.IsTenantScoped(s.DocLines)
+}
+
 func IsSensitiveEvent(s model.Struct) bool {
 	if IsEvent(s) {
 		annotations := annotation.NewRegistry(eventAnnotation.Get())
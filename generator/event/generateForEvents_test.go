@@ -5,6 +5,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/MarcGrol/golangAnnotations/generator"
 	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
 	"github.com/MarcGrol/golangAnnotations/model"
 	"github.com/stretchr/testify/assert"
@@ -12,10 +13,14 @@ import (
 
 func cleanup() {
 	os.Remove(generationUtil.Prefixed("./testData/aggregates.go"))
+	os.Remove(generationUtil.Prefixed("./testData/anonymized.go"))
+	os.Remove(generationUtil.Prefixed("./testData/eventReplay.go"))
 	os.Remove(generationUtil.Prefixed("./testData/interface.go"))
 	os.Remove(generationUtil.Prefixed("./testData/wrappers.go"))
 	os.Remove(generationUtil.Prefixed("./testData/wrappers_test.go"))
 	os.Remove(generationUtil.Prefixed("./testDataStore/testDataStore.go"))
+	os.Remove(generationUtil.Prefixed("./testDataPublisher/testDataPublisher.go"))
+	os.Remove("./testDataPublisher")
 }
 
 func TestGenerateForEvents(t *testing.T) {
@@ -35,7 +40,7 @@ func TestGenerateForEvents(t *testing.T) {
 			},
 		},
 	}
-	err := NewGenerator().Generate("testData", model.ParsedSources{Structs: s})
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
 	assert.Nil(t, err)
 
 	// check that generated files exisst
@@ -49,6 +54,7 @@ func TestGenerateForEvents(t *testing.T) {
 	assert.Contains(t, string(data), "func ApplyTestEvent(c context.Context, rc request.Context, envlp envelope.Envelope, aggregateRoot TestAggregate) error {")
 	assert.Contains(t, string(data), "func ApplyTestEvents(c context.Context, rc request.Context, envelopes []envelope.Envelope, aggregateRoot TestAggregate) error {")
 	assert.Contains(t, string(data), "func UnWrapTestEvent(envlp *envelope.Envelope) (envelope.Event, error) {")
+	assert.Contains(t, string(data), "versioned.SetAggregateVersion(envlp.SequenceNumber)")
 	//assert.Contains(t, string(data), "func AnonymizeTestEnvelopes(envelopes []envelope.Envelope) ([]envelope.Envelope, error) {")
 
 	// check that generate code has 4 helper functions for MyStruct
@@ -69,6 +75,106 @@ func TestGenerateForEvents(t *testing.T) {
 	cleanup()
 }
 
+func TestGenerateForTenantScopedEvents(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`//@Event(aggregate = "Test")`, `//@TenantScoped( field = "Customer" )`},
+			Name:        "MyStruct",
+			Fields: []model.Field{
+				{Name: "Customer", TypeName: "string"},
+				{Name: "StringField", TypeName: "string"},
+			},
+		},
+	}
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Nil(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/aggregates.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "type TestTenantFilter struct {")
+	assert.Contains(t, string(data), "func GetTestTenantID(envlp *envelope.Envelope) (string, error) {")
+	assert.Contains(t, string(data), "return evt.Customer, nil")
+
+	data, err = ioutil.ReadFile(generationUtil.Prefixed("./testData/wrappers.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `if s.Customer == "" {`)
+	assert.Contains(t, string(data), "func (s *MyStruct) GetTenantID() string {")
+}
+
+func TestGenerateForEventReplay(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`//@Event(aggregate = "Order", issensitive = "true")`},
+			Name:        "OrderCreated",
+			Fields: []model.Field{
+				{Name: "Email", TypeName: "string", Tag: "`json:\"email\" sensitive:\"true\"`"},
+			},
+		},
+		{
+			PackageName: "testData",
+			DocLines:    []string{`//@Event(aggregate = "Order", version = "2")`},
+			Name:        "OrderShipped",
+		},
+		{
+			PackageName: "testData",
+			DocLines:    []string{`//@Event(aggregate = "Order", istransient = "true")`},
+			Name:        "OrderViewed",
+		},
+		{
+			PackageName: "testData",
+			DocLines:    []string{`//@Event(aggregate = "Viewer", istransient = "true")`},
+			Name:        "ViewerJoined",
+		},
+	}
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Nil(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/eventReplay.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "func LoadEventStream(filename string) ([]envelope.Envelope, error) {")
+	assert.Contains(t, string(data), "envlp.AggregateName, envlp.EventTypeVersion = OrderAggregateName, 2")
+	assert.Contains(t, string(data), "func SampleEventStream(filename string, envelopes []envelope.Envelope) error {")
+	assert.Contains(t, string(data), "return json.Marshal(evt.Anonymized())")
+	assert.Contains(t, string(data), "func (r *EventReplay) ReplayOrder(aggregateUID string, aggregateRoot OrderAggregate) *EventReplay {")
+	assert.Contains(t, string(data), "case OrderCreatedEventName, OrderShippedEventName:")
+	assert.NotContains(t, string(data), "ReplayViewer")
+	assert.Contains(t, string(data), "return handler.OnOrderViewedTransient(c, rc, *evt)")
+	assert.Contains(t, string(data), "func (r *EventReplay) AssertState(filename string, state interface{}) *EventReplay {")
+}
+
+func TestTenantScopedEventErrors(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	scoped := model.Struct{
+		PackageName: "testData",
+		DocLines:    []string{`//@Event(aggregate = "Test")`, `//@TenantScoped()`},
+		Name:        "MyStruct",
+		Fields:      []model.Field{{Name: "TenantID", TypeName: "int"}},
+	}
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: []model.Struct{scoped}}, generator.Config{InputDir: "testData"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Tenant-scoped event MyStruct has no field 'TenantID string' for its tenant")
+
+	scoped.Fields = []model.Field{{Name: "TenantID", TypeName: "string"}}
+	unscoped := model.Struct{
+		PackageName: "testData",
+		DocLines:    []string{`//@Event(aggregate = "Test")`},
+		Name:        "MyOtherStruct",
+	}
+	err = generator.Generate(NewGenerator(), model.ParsedSources{Structs: []model.Struct{scoped, unscoped}}, generator.Config{InputDir: "testData"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Event MyOtherStruct of aggregate Test must be @TenantScoped, like event MyStruct")
+}
+
 func TestIsEvent(t *testing.T) {
 	s := model.Struct{
 		DocLines: []string{
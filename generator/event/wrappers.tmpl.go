@@ -24,6 +24,11 @@ const (
 
 // Wrap wraps event {{.Name}} into an envelope
 func (s *{{.Name}}) Wrap(rc request.Context) (*envelope.Envelope, error) {
+	{{if IsTenantScopedEvent . -}}
+	if s.{{GetTenantField .}} == "" {
+		return nil, fmt.Errorf("Event {{.Name}} of tenant-scoped aggregate {{GetAggregateName .}} has no tenant")
+	}
+	{{end -}}
 	blob, err := json.Marshal(s)
 	if err != nil {
 		log.Printf("Error marshalling {{.Name}} payload %+v", err)
@@ -37,7 +42,7 @@ func (s *{{.Name}}) Wrap(rc request.Context) (*envelope.Envelope, error) {
 		AggregateName:    {{GetAggregateName . }}AggregateName, // from annotation!
 		AggregateUID:     s.GetUID(),
 		EventTypeName:    {{.Name}}EventName,
-		EventTypeVersion: 0,
+		EventTypeVersion: {{GetEventVersion .}},
 		EventData:        string(blob),
 	}
 
@@ -55,6 +60,13 @@ func (s *{{.Name}}) GetAggregateName() string {
 	return "{{GetAggregateName . }}"
 }
 
+{{if IsTenantScopedEvent . -}}
+// GetTenantID returns the tenant of the event
+func (s *{{.Name}}) GetTenantID() string {
+	return s.{{GetTenantField .}}
+}
+
+{{end -}}
 // GetEventTypeName return the name of the event
 func (s *{{.Name}}) GetEventTypeName() string {
 	return "{{.Name}}"
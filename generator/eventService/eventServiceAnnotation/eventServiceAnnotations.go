@@ -11,13 +11,15 @@ const (
 	ParamDelayed        = "delayed"
 	ParamNoTest         = "notest"
 	ParamProducesEvents = "producesevents"
+	ParamPackage        = "package"
+	ParamAppEngine      = "appengine"
 )
 
 func Get() []annotation.AnnotationDescriptor {
 	return []annotation.AnnotationDescriptor{
 		{
 			Name:       TypeEventService,
-			ParamNames: []string{ParamSelf, ParamNoTest},
+			ParamNames: []string{ParamSelf, ParamNoTest, ParamPackage, ParamAppEngine},
 			Validator:  validateEventServiceAnnotation,
 		},
 		{
@@ -2,7 +2,6 @@ package eventService
 
 import (
 	"fmt"
-	"log"
 	"sort"
 	"strings"
 	"text/template"
@@ -22,90 +21,160 @@ func NewGenerator() generator.Generator {
 	return &Generator{}
 }
 
+func (eg *Generator) Name() string {
+	return "event-service"
+}
+
+// GetDependencies returns the generators of the code that the generated code refers to: the event-names and unwrap-functions of the events
+func (eg *Generator) GetDependencies() []string {
+	return []string{"event"}
+}
+
+func (eg *Generator) GetTemplates() []generator.Template {
+	return []generator.Template{
+		{Name: "event-handlers", APIVersion: 1, Text: handlersTemplate},
+		{Name: "test-handlers", APIVersion: 1, Text: testHandlersTemplate},
+	}
+}
+
 func (eg *Generator) GetAnnotations() []annotation.AnnotationDescriptor {
 	return eventServiceAnnotation.Get()
 }
 
-func (eg *Generator) Generate(inputDir string, parsedSource model.ParsedSources) error {
-	return generate(inputDir, parsedSource.Structs)
+func (eg *Generator) Generate(parsedSource model.ParsedSources, config generator.Config) ([]generator.OutputFile, error) {
+	return generate(config, parsedSource.Structs)
 }
 
 type templateData struct {
 	PackageName string
 	Services    []model.Struct
+	// DomainPackage and DomainImportPath refer to the annotated package when generating into a separate package
+	DomainPackage    string
+	DomainImportPath string
 }
 
-func generate(inputDir string, structs []model.Struct) error {
+func generate(config generator.Config, structs []model.Struct) ([]generator.OutputFile, error) {
 
 	packageName, err := generationUtil.GetPackageNameForStructs(structs)
 	if packageName == "" || err != nil {
-		return err
+		return nil, err
 	}
-	targetDir, err := generationUtil.DetermineTargetPath(inputDir, packageName)
+	targetDir, err := generationUtil.DetermineTargetPath(config.InputDir, packageName)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	eventServices := make([]model.Struct, 0)
+	eventServicesPerPackage := map[string][]model.Struct{}
 	for _, service := range structs {
 		if IsEventService(service) {
-			eventServices = append(eventServices, service)
+			separatePackage := GetEventServicePackage(service)
+			eventServicesPerPackage[separatePackage] = append(eventServicesPerPackage[separatePackage], service)
+		}
+	}
+
+	files := []generator.OutputFile{}
+	for _, separatePackage := range getPackageNames(eventServicesPerPackage) {
+		data := templateData{
+			PackageName: packageName,
+			Services:    eventServicesPerPackage[separatePackage],
+		}
+		dir := targetDir
+		if separatePackage != "" {
+			dir = fmt.Sprintf("%s/../%s", targetDir, separatePackage)
+			data, err = inSeparatePackage(data, targetDir, separatePackage)
+			if err != nil {
+				return nil, err
+			}
+		}
+		packageFiles, err := doGenerate(dir, data.PackageName, data, config)
+		if err != nil {
+			return nil, err
 		}
+		files = append(files, packageFiles...)
 	}
+	return files, nil
+}
 
-	if len(eventServices) == 0 {
-		return nil
+func getPackageNames(eventServicesPerPackage map[string][]model.Struct) []string {
+	packageNames := []string{}
+	for packageName := range eventServicesPerPackage {
+		packageNames = append(packageNames, packageName)
 	}
+	sort.Strings(packageNames)
+	return packageNames
+}
 
-	data := templateData{
-		PackageName: packageName,
-		Services:    eventServices,
+// inSeparatePackage prepares data for generating the event-handling of its services into the sibling package
+// separatePackage, that imports the annotated package and can only call its exported operations
+func inSeparatePackage(data templateData, targetDir string, separatePackage string) (templateData, error) {
+	services := []model.Struct{}
+	for _, service := range data.Services {
+		for _, o := range service.Operations {
+			if IsEventOperation(*o) && !generationUtil.IsExported(o.Name) {
+				return data, generator.OperationError(*o, service.Name, eventServiceAnnotation.TypeEventOperation, "Operation %s.%s must be exported to be called from package %s", service.Name, o.Name, separatePackage)
+			}
+		}
+		services = append(services, generationUtil.QualifyStruct(service, data.PackageName))
 	}
-	return doGenerate(targetDir, packageName, data)
+	domainImportPath, err := generationUtil.GetImportPath(targetDir)
+	if err != nil {
+		return data, err
+	}
+	return templateData{
+		PackageName:      separatePackage,
+		Services:         services,
+		DomainPackage:    data.PackageName,
+		DomainImportPath: domainImportPath,
+	}, nil
 }
 
-func doGenerate(targetDir, packageName string, data templateData) error {
-	err := generationUtil.Generate(generationUtil.Info{
+func doGenerate(targetDir, packageName string, data templateData, config generator.Config) ([]generator.OutputFile, error) {
+	files := []generator.OutputFile{}
+	file, err := generationUtil.Generate(generationUtil.Info{
 		Src:            packageName,
 		TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/eventHandler.go", targetDir)),
 		TemplateName:   "event-handlers",
 		TemplateString: handlersTemplate,
+		Config:         config,
 		FuncMap:        customTemplateFuncs,
 		Data:           data,
 	})
 	if err != nil {
-		log.Fatalf("Error generating handlers for event-services in package %s: %s", packageName, err)
-		return err
+		return nil, fmt.Errorf("Error generating handlers for event-services in package %s:%w", packageName, err)
 	}
+	files = append(files, file)
 
 	for _, eventService := range data.Services {
 		if !IsEventServiceNoTest(eventService) {
-			err = generationUtil.Generate(generationUtil.Info{
+			file, err := generationUtil.Generate(generationUtil.Info{
 				Src:            packageName,
 				TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/eventHandlerHelpers_test.go", targetDir)),
 				TemplateName:   "test-handlers",
 				TemplateString: testHandlersTemplate,
+				Config:         config,
 				FuncMap:        customTemplateFuncs,
 				Data:           data,
 			})
 			if err != nil {
-				log.Fatalf("Error generating test-handlers for event-services in package %s: %s", packageName, err)
-				return err
+				return nil, fmt.Errorf("Error generating test-handlers for event-services in package %s:%w", packageName, err)
 			}
+			files = append(files, file)
 			break
 		}
 	}
 
-	return nil
+	return files, nil
 }
 
 var customTemplateFuncs = template.FuncMap{
 	"IsEventService":                  IsEventService,
 	"IsEventServiceNoTest":            IsEventServiceNoTest,
+	"IsEventServiceOnAppEngine":       IsEventServiceOnAppEngine,
 	"IsEventOperation":                IsEventOperation,
 	"GetInputArgType":                 GetInputArgType,
 	"GetFullEventNames":               GetFullEventNames,
 	"GetInputArgPackage":              GetInputArgPackage,
+	"GetInputArgPackagePrefix":        GetInputArgPackagePrefix,
 	"GetEventServiceSelfName":         GetEventServiceSelfName,
 	"GetEventServiceTopics":           GetEventServiceTopics,
 	"GetEventOperationTopic":          GetEventOperationTopic,
@@ -117,6 +186,16 @@ var customTemplateFuncs = template.FuncMap{
 	"ToFirstUpper":                    ToFirstUpper,
 }
 
+// GetEventServicePackage returns the name of the separate package that the event-handling of the service is
+// generated into, or "" when it is generated into the package of the service itself
+func GetEventServicePackage(s model.Struct) string {
+	annotations := annotation.NewRegistry(eventServiceAnnotation.Get())
+	if ann, ok := annotations.ResolveAnnotationByName(s.DocLines, eventServiceAnnotation.TypeEventService); ok {
+		return ann.Attributes[eventServiceAnnotation.ParamPackage]
+	}
+	return ""
+}
+
 func IsEventService(s model.Struct) bool {
 	annotations := annotation.NewRegistry(eventServiceAnnotation.Get())
 	_, ok := annotations.ResolveAnnotationByName(s.DocLines, eventServiceAnnotation.TypeEventService)
@@ -141,6 +220,16 @@ func IsEventServiceNoTest(s model.Struct) bool {
 	return false
 }
 
+// IsEventServiceOnAppEngine tells whether the event-handling of the service relies on App Engine: its task-headers
+// and handling events immediately on the development server. Services say appengine = "false" to run elsewhere.
+func IsEventServiceOnAppEngine(s model.Struct) bool {
+	annotations := annotation.NewRegistry(eventServiceAnnotation.Get())
+	if ann, ok := annotations.ResolveAnnotationByName(s.DocLines, eventServiceAnnotation.TypeEventService); ok {
+		return ann.Attributes[eventServiceAnnotation.ParamAppEngine] != "false"
+	}
+	return true
+}
+
 func GetEventServiceSelfName(s model.Struct) string {
 	annotations := annotation.NewRegistry(eventServiceAnnotation.Get())
 	if ann, ok := annotations.ResolveAnnotationByName(s.DocLines, eventServiceAnnotation.TypeEventService); ok {
@@ -237,9 +326,7 @@ operations:
 		if IsEventOperation(*o) {
 			process := GetEventOperationProcess(*o)
 			if process != "" {
-				aggregate := GetInputArgPackage(*o)
-				eventType := GetInputArgType(*o)
-				event := fmt.Sprintf("%s.%s", aggregate, eventType)
+				event := GetInputArgPackagePrefix(*o) + GetInputArgType(*o)
 				for i, group := range queueGroups {
 					if group.Process == process {
 						queueGroups[i].Events = append(group.Events, event)
@@ -300,6 +387,16 @@ func GetInputArgPackage(o model.Operation) string {
 	}
 	return ""
 }
+
+// GetInputArgPackagePrefix returns the qualifier of the event-type, like "personEvents.", or "" for an event of the
+// package itself
+func GetInputArgPackagePrefix(o model.Operation) string {
+	if argPackage := GetInputArgPackage(o); argPackage != "" {
+		return argPackage + "."
+	}
+	return ""
+}
+
 func isContextArg(f model.Field) bool {
 	return f.TypeName == "context.Context"
 }
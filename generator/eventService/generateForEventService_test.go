@@ -6,6 +6,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/MarcGrol/golangAnnotations/generator"
 	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
 	"github.com/MarcGrol/golangAnnotations/model"
 	"github.com/stretchr/testify/assert"
@@ -44,7 +45,7 @@ func TestGenerateForWeb(t *testing.T) {
 		},
 	}
 
-	err := NewGenerator().Generate("testData", model.ParsedSources{Structs: s})
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
 	assert.Nil(t, err)
 
 	// check that generated files exisst
@@ -101,3 +102,72 @@ func createOper() model.Operation {
 	}
 	return o
 }
+
+func TestGenerateInSeparatePackage(t *testing.T) {
+	defer os.RemoveAll("./testDataevents")
+
+	s := []model.Struct{
+		{
+			DocLines:    []string{`// @EventService( self = "self", package = "testDataevents" )`},
+			PackageName: "testData",
+			Name:        "MyEventService",
+			Operations: []*model.Operation{
+				{
+					DocLines:      []string{`// @EventOperation( topic = "other" )`},
+					Name:          "OnOrderCreated",
+					RelatedStruct: &model.Field{TypeName: "MyEventService"},
+					InputArgs: []model.Field{
+						{Name: "c", TypeName: "context.Context"},
+						{Name: "evt", TypeName: "OrderCreated"},
+					},
+					OutputArgs: []model.Field{{TypeName: "error"}},
+				},
+			},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Nil(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testDataevents/eventHandler.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "package testDataevents")
+	assert.Contains(t, string(data), `"github.com/MarcGrol/golangAnnotations/generator/eventService/testData"`)
+	assert.Contains(t, string(data), "type MyEventService struct {\n\t*testData.MyEventService\n}")
+	assert.Contains(t, string(data), "evt, found := testData.GetIfIsOrderCreated(&envlp)")
+	assert.Contains(t, string(data), "err := es.OnOrderCreated(c, rc, *evt)")
+}
+
+func TestGenerateOutsideAppEngine(t *testing.T) {
+	s := []model.Struct{
+		{
+			DocLines:    []string{`// @EventService( self = "self", appengine = "false", buildtags = "!appengine" )`},
+			PackageName: "testData",
+			Name:        "MyEventService",
+			Operations: []*model.Operation{
+				{
+					DocLines:      []string{`// @EventOperation( topic = "other" )`},
+					Name:          "OnOrderCreated",
+					RelatedStruct: &model.Field{TypeName: "MyEventService"},
+					InputArgs: []model.Field{
+						{Name: "c", TypeName: "context.Context"},
+						{Name: "evt", TypeName: "OrderCreated"},
+					},
+					OutputArgs: []model.Field{{TypeName: "error"}},
+				},
+			},
+		},
+	}
+
+	files, err := generator.Render(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, files)
+	for _, f := range files {
+		assert.Contains(t, string(f.Content), "//go:build !appengine\n// +build !appengine\n\npackage testData")
+	}
+
+	data := string(files[0].Content)
+	assert.NotContains(t, data, "devmode")
+	assert.NotContains(t, data, "X-AppEngine-TaskRetryCount")
+	assert.Contains(t, data, `r.Header.Get("X-CloudTasks-TaskRetryCount")`)
+}
@@ -12,11 +12,22 @@ import (
 	"strconv"
 
 	"github.com/gorilla/mux"
+{{- if .DomainImportPath}}
+
+	"{{.DomainImportPath}}"
+{{- end}}
 )
 
 {{range $idxService, $service := .Services -}}
 
 {{ $eventServiceName := .Name -}}
+{{if $.DomainPackage -}}
+// {{$eventServiceName}} adds event-handling to the business logic of {{$.DomainPackage}}.{{$eventServiceName}}
+type {{$eventServiceName}} struct {
+	*{{$.DomainPackage}}.{{$eventServiceName}}
+}
+
+{{end -}}
 
 func (es *{{$eventServiceName}}) SubscribeToEvents(router *mux.Router) {
 	const subscriber = "{{GetEventServiceSelfName .}}"
@@ -34,10 +45,12 @@ func (es *{{$eventServiceName}}) handleOrEnqueueEvent(c context.Context, rc requ
 	switch envlp.EventTypeName {
 		case {{range $idxOper, $evtName := GetFullEventNames .}}{{if $idxOper}}, {{end -}}{{$evtName}}{{end -}}:
 
+			{{if IsEventServiceOnAppEngine $service -}}
 			// Cloud Tasks emulator not available for local development server, handle event immediately 
 			if devmode.New().IsDevMode() {
 				return es.handleEvent(c, rc, topic, envlp)
 			}
+			{{end -}}
 			return es.enqueueEventToBackground(c, rc, topic, envlp, subscriber)
 	}
 	return nil
@@ -64,7 +77,7 @@ func (es *{{$eventServiceName}}) enqueueEventToBackground(c context.Context, rc
 	var eta time.Time
 	switch envlp.EventTypeName {
 	{{range $oper := .Operations -}}{{if IsEventOperationDelayed $oper -}}
-	case {{GetInputArgPackage $oper}}.{{GetInputArgType $oper}}EventName:
+	case {{GetInputArgPackagePrefix $oper}}{{GetInputArgType $oper}}EventName:
 		delay, eta = get{{GetInputArgType $oper}}DelayOrETA(c, rc, envlp)
 	{{end -}}{{end -}}
 	}
@@ -101,9 +114,10 @@ func (es *{{$eventServiceName}}) handleHTTPBackgroundEvent() http.HandlerFunc {
 		c := ctx.New().CreateContext(r)
 		rc := request.NewMinimalContext(c, r)
 
-		retryCount, err := strconv.Atoi(r.Header.Get("X-AppEngine-TaskRetryCount"))
+		{{$retryCountHeader := "X-CloudTasks-TaskRetryCount"}}{{if IsEventServiceOnAppEngine $service}}{{$retryCountHeader = "X-AppEngine-TaskRetryCount"}}{{end -}}
+		retryCount, err := strconv.Atoi(r.Header.Get("{{$retryCountHeader}}"))
 		if err != nil {
-			mylog.New().Error(c, rc, "Error parsing 'X-AppEngine-TaskRetryCount': %s", err)
+			mylog.New().Error(c, rc, "Error parsing '{{$retryCountHeader}}': %s", err)
 		}
 
 		if retryCount > 0 && !environ.GetEnvironment(c).RetryFailedEvents(c) {
@@ -143,7 +157,7 @@ func (es *{{$eventServiceName}}) handleEvent(c context.Context, rc request.Conte
 	{{range $idxOper, $oper := .Operations -}}
 		{{if IsEventOperation $oper -}}
 		{
-			evt, found := {{GetInputArgPackage $oper}}.GetIfIs{{GetInputArgType $oper}}(&envlp)
+			evt, found := {{GetInputArgPackagePrefix $oper}}GetIfIs{{GetInputArgType $oper}}(&envlp)
 			if found {
 				err := es.{{$oper.Name}}(c, rc, *evt)
 				if err != nil {
@@ -8,6 +8,10 @@ import (
 	"context"
 	"fmt"
 	"testing"
+{{- if .DomainImportPath}}
+
+	"{{.DomainImportPath}}"
+{{- end}}
 )
 
 {{range $idxService, $service := .Services -}}
@@ -20,16 +24,16 @@ import (
 		{{range $idxOper, $oper := .Operations -}}
 			{{if IsEventOperation $oper -}}
 
-func {{$oper.Name}}In{{ToFirstUpper $service.Name}}TestHelper(t *testing.T, c context.Context, rc request.Context, es *{{$eventServiceName}}, evt {{GetInputArgPackage $oper}}.{{GetInputArgType $oper}}) []envelope.Envelope {
+func {{$oper.Name}}In{{ToFirstUpper $service.Name}}TestHelper(t *testing.T, c context.Context, rc request.Context, es *{{$eventServiceName}}, evt {{GetInputArgPackagePrefix $oper}}{{GetInputArgType $oper}}) []envelope.Envelope {
 	{{if IsEventNotTransient $oper -}}
 		envlp, err := store.StoreEvent(c, rc, &evt)
 		if err != nil {
-		t.Fatalf("Error storing event %s: %s", "{{GetInputArgPackage $oper}}.{{GetInputArgType $oper}}", err)
+		t.Fatalf("Error storing event %s: %s", "{{GetInputArgPackagePrefix $oper}}{{GetInputArgType $oper}}", err)
 		}
 	{{else -}}
 		envlp, err := evt.Wrap(rc)
 		if err != nil {
-			t.Fatalf("Error wrapping event %s: %s", "{{GetInputArgPackage $oper}}.{{GetInputArgType $oper}}", err)
+			t.Fatalf("Error wrapping event %s: %s", "{{GetInputArgPackagePrefix $oper}}{{GetInputArgType $oper}}", err)
 		}
 	{{end -}}
 
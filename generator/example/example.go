@@ -0,0 +1,179 @@
+package example
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/MarcGrol/golangAnnotations/generator/example/exampleAnnotation"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+// Member is a single named value of an Object
+type Member struct {
+	Name  string
+	Value interface{}
+}
+
+// Object is a json-object that keeps its members in the order of the fields of the originating struct
+type Object []Member
+
+func (o Object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("{")
+	for idx, member := range o {
+		if idx > 0 {
+			buf.WriteString(",")
+		}
+		name, err := json.Marshal(member.Name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(member.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteString(":")
+		buf.Write(value)
+	}
+	buf.WriteString("}")
+	return buf.Bytes(), nil
+}
+
+// Value returns a plausible value for a type, using the structs and enums of the parsed sources to
+// describe custom types. Literal values of @Example annotations on structs and fields take precedence
+// over synthesized values. The returned value can be marshalled into an example json-payload.
+func Value(typeName string, parsedSources model.ParsedSources) interface{} {
+	return value(typeName, parsedSources, map[string]bool{})
+}
+
+// JSON returns the indented example json-payload of a type
+func JSON(typeName string, parsedSources model.ParsedSources) string {
+	data, err := json.MarshalIndent(Value(typeName, parsedSources), "", "  ")
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// value keeps track of the structs being visited to stop on self-referencing structs
+func value(typeName string, parsedSources model.ParsedSources, visiting map[string]bool) interface{} {
+	typeName = strings.TrimPrefix(typeName, "*")
+	if strings.HasPrefix(typeName, "[]") {
+		element := value(strings.TrimPrefix(typeName, "[]"), parsedSources, visiting)
+		if element == nil {
+			return []interface{}{}
+		}
+		return []interface{}{element}
+	}
+	if field := (model.Field{TypeName: typeName}); field.IsMap() {
+		_, valueType := field.SplitMapTypeNames()
+		return Object{{Name: "key", Value: value(valueType, parsedSources, visiting)}}
+	}
+	switch typeName {
+	case "string":
+		return "string"
+	case "int", "int32", "int64":
+		return 1
+	case "float32", "float64":
+		return 1.5
+	case "bool":
+		return true
+	case "time.Time":
+		return "2020-01-01T12:00:00Z"
+	case "mydate.MyDate":
+		return "2020-01-01"
+	}
+	for _, e := range parsedSources.Enums {
+		if e.Name == typeName && len(e.EnumLiterals) > 0 {
+			return lowerInitial(e.EnumLiterals[0].Name)
+		}
+	}
+	if visiting[typeName] {
+		return nil
+	}
+	for _, s := range parsedSources.Structs {
+		if s.Name == typeName {
+			if literal, ok := GetExample(s.DocLines); ok {
+				return literalValue(typeName, literal)
+			}
+			visiting[typeName] = true
+			defer delete(visiting, typeName)
+			return structValue(s, parsedSources, visiting)
+		}
+	}
+	return nil
+}
+
+func structValue(s model.Struct, parsedSources model.ParsedSources, visiting map[string]bool) Object {
+	object := Object{}
+	for _, f := range s.Fields {
+		name, ok := FieldName(f)
+		if !ok {
+			continue
+		}
+		if literal, ok := GetExample(f.DocLines); ok {
+			object = append(object, Member{Name: name, Value: literalValue(f.TypeName, literal)})
+			continue
+		}
+		object = append(object, Member{Name: name, Value: value(f.TypeName, parsedSources, visiting)})
+	}
+	return object
+}
+
+// GetExample returns the literal value of the @Example annotation within doc-lines
+func GetExample(docLines []string) (string, bool) {
+	annotations := annotation.NewRegistry(exampleAnnotation.Get())
+	if ann, ok := annotations.ResolveAnnotationByName(docLines, exampleAnnotation.TypeExample); ok {
+		return strings.Trim(ann.Attributes[exampleAnnotation.ParamValue], "`"), true
+	}
+	return "", false
+}
+
+// literalValue interprets an example-literal: for strings as is, for all other types as json,
+// falling back to a string when the literal is no valid json
+func literalValue(typeName string, literal string) interface{} {
+	if strings.TrimPrefix(typeName, "*") != "string" && json.Valid([]byte(literal)) {
+		return json.RawMessage(literal)
+	}
+	return literal
+}
+
+// ParamValue returns a plausible value for a path- or query-parameter
+func ParamValue(arg model.Field) string {
+	switch {
+	case arg.IsInt():
+		return "1"
+	case arg.IsBool():
+		return "true"
+	case arg.IsDate():
+		return "2020-01-01"
+	}
+	return arg.Name + "-1"
+}
+
+// FieldName returns the name of a field in json, or false when the field is not marshalled
+func FieldName(f model.Field) (string, bool) {
+	if f.Name == "" || !unicode.IsUpper([]rune(f.Name)[0]) {
+		return "", false
+	}
+	tag := reflect.StructTag(strings.Trim(f.Tag, "`")).Get("json")
+	name := strings.Split(tag, ",")[0]
+	if name == "-" {
+		return "", false
+	}
+	if name == "" {
+		return f.Name, true
+	}
+	return name, true
+}
+
+func lowerInitial(in string) string {
+	a := []rune(in)
+	a[0] = unicode.ToLower(a[0])
+	return string(a)
+}
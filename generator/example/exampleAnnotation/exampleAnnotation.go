@@ -0,0 +1,25 @@
+package exampleAnnotation
+
+import "github.com/MarcGrol/golangAnnotations/generator/annotation"
+
+const (
+	TypeExample = "Example"
+	ParamValue  = "value"
+)
+
+func Get() []annotation.AnnotationDescriptor {
+	return []annotation.AnnotationDescriptor{
+		{
+			Name:       TypeExample,
+			ParamNames: []string{ParamValue},
+			Validator:  validateExampleAnnotation,
+		}}
+}
+
+func validateExampleAnnotation(annot annotation.Annotation) bool {
+	if annot.Name == TypeExample {
+		_, hasValue := annot.Attributes[ParamValue]
+		return hasValue
+	}
+	return false
+}
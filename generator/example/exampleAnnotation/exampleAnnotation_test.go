@@ -0,0 +1,32 @@
+package exampleAnnotation
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrectExampleAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	annotation, ok := registry.ResolveAnnotation(`// @Example( value = "john@example.com" )`)
+	assert.True(t, ok)
+	assert.Equal(t, TypeExample, annotation.Name)
+	assert.Equal(t, "john@example.com", annotation.Attributes[ParamValue])
+}
+
+func TestExampleAnnotationWithRawJSON(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	annotation, ok := registry.ResolveAnnotation("// @Example( value = `{\"name\": \"john\"}` )")
+	assert.True(t, ok)
+	assert.Equal(t, "`{\"name\": \"john\"}`", annotation.Attributes[ParamValue])
+}
+
+func TestExampleAnnotationWithoutValue(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	_, ok := registry.ResolveAnnotation(`// @Example()`)
+	assert.False(t, ok)
+}
@@ -0,0 +1,85 @@
+package example
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+var parsedSources = model.ParsedSources{
+	Structs: []model.Struct{
+		{
+			Name: "Person",
+			Fields: []model.Field{
+				{Name: "Name", TypeName: "string", Tag: "`json:\"name\"`"},
+				{Name: "Age", TypeName: "int"},
+				{Name: "Color", TypeName: "ColorType", Tag: "`json:\"color,omitempty\"`"},
+				{Name: "Children", TypeName: "[]*Person", Tag: "`json:\"children\"`"},
+				{Name: "Secret", TypeName: "string", Tag: "`json:\"-\"`"},
+				{Name: "internal", TypeName: "string"},
+			},
+		},
+	},
+	Enums: []model.Enum{
+		{Name: "ColorType", EnumLiterals: []model.EnumLiteral{{Name: "ColorTypeRed"}}},
+	},
+}
+
+func TestJSONKeepsFieldOrder(t *testing.T) {
+	assert.Equal(t, `{
+  "name": "string",
+  "Age": 1,
+  "color": "colorTypeRed",
+  "children": []
+}`, JSON("Person", parsedSources))
+}
+
+func TestValueOfPrimitives(t *testing.T) {
+	assert.Equal(t, "string", Value("*string", parsedSources))
+	assert.Equal(t, []interface{}{1}, Value("[]int", parsedSources))
+	assert.Equal(t, Object{{Name: "key", Value: true}}, Value("map[string]bool", parsedSources))
+	assert.Nil(t, Value("json.RawMessage", parsedSources))
+}
+
+func TestFieldName(t *testing.T) {
+	name, ok := FieldName(model.Field{Name: "Name", Tag: "`json:\"name,omitempty\"`"})
+	assert.True(t, ok)
+	assert.Equal(t, "name", name)
+
+	_, ok = FieldName(model.Field{Name: "Secret", Tag: "`json:\"-\"`"})
+	assert.False(t, ok)
+}
+
+func TestParamValue(t *testing.T) {
+	assert.Equal(t, "1", ParamValue(model.Field{Name: "year", TypeName: "int"}))
+	assert.Equal(t, "uid-1", ParamValue(model.Field{Name: "uid", TypeName: "string"}))
+}
+
+func TestJSONUsesExamples(t *testing.T) {
+	sources := model.ParsedSources{
+		Structs: []model.Struct{
+			{
+				Name: "Person",
+				Fields: []model.Field{
+					{Name: "Email", TypeName: "string", Tag: "`json:\"email\"`", DocLines: []string{`// @Example( value = "john@example.com" )`}},
+					{Name: "Age", TypeName: "int", Tag: "`json:\"age\"`", DocLines: []string{`// @Example( value = "42" )`}},
+					{Name: "Born", TypeName: "mydate.MyDate", Tag: "`json:\"born\"`", DocLines: []string{`// @Example( value = "1980-05-01" )`}},
+					{Name: "Address", TypeName: "Address", Tag: "`json:\"address\"`"},
+				},
+			},
+			{
+				Name:     "Address",
+				DocLines: []string{"// @Example( value = `{\"city\": \"Amsterdam\"}` )"},
+			},
+		},
+	}
+	assert.Equal(t, `{
+  "email": "john@example.com",
+  "age": 42,
+  "born": "1980-05-01",
+  "address": {
+    "city": "Amsterdam"
+  }
+}`, JSON("Person", sources))
+}
@@ -0,0 +1,88 @@
+package export
+
+const exportTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+
+package {{.PackageName}}
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"time"
+)
+
+{{ $service := .Service }}
+
+{{range .Exports -}}
+{{ $oper := .Operation }}
+{{if .CSV -}}
+// {{$oper.Name}}WriteCSV writes the result of {{$oper.Name}} as csv
+func (service *{{$service.Name}}) {{$oper.Name}}WriteCSV(w io.Writer, rows {{.RowType}}) error {
+	writer := csv.NewWriter(w)
+
+	err := writer.Write([]string{
+		{{range .Columns -}}
+			{{QuotedColumnLabel .}},
+		{{end -}}
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		err = writer.Write([]string{
+			{{range .Columns -}}
+				{{CSVValue .}},
+			{{end -}}
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+{{end -}}
+
+{{if .XLSX -}}
+// {{$oper.Name}}WriteXLSX writes the result of {{$oper.Name}} as excel-sheet
+func (service *{{$service.Name}}) {{$oper.Name}}WriteXLSX(w io.Writer, rows {{.RowType}}) error {
+	const sheet = "Sheet1"
+	f := excelize.NewFile()
+
+	labels := []string{
+		{{range .Columns -}}
+			{{QuotedColumnLabel .}},
+		{{end -}}
+	}
+	for col, label := range labels {
+		cell, err := excelize.CoordinatesToCellName(col+1, 1)
+		if err != nil {
+			return err
+		}
+		f.SetCellValue(sheet, cell, label)
+	}
+
+	for idx, row := range rows {
+		values := []interface{}{
+			{{range .Columns -}}
+				{{XLSXValue .}},
+			{{end -}}
+		}
+		for col, value := range values {
+			cell, err := excelize.CoordinatesToCellName(col+1, idx+2)
+			if err != nil {
+				return err
+			}
+			f.SetCellValue(sheet, cell, value)
+		}
+	}
+
+	return f.Write(w)
+}
+
+{{end -}}
+{{end -}}
+`
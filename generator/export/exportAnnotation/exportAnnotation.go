@@ -0,0 +1,47 @@
+package exportAnnotation
+
+import "github.com/MarcGrol/golangAnnotations/generator/annotation"
+
+const (
+	TypeExport       = "Export"
+	TypeExportColumn = "ExportColumn"
+	ParamFormat      = "format"
+	ParamLabel       = "label"
+	ParamOrder       = "order"
+	ParamSkip        = "skip"
+
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+func Get() []annotation.AnnotationDescriptor {
+	return []annotation.AnnotationDescriptor{
+		{
+			Name:       TypeExport,
+			ParamNames: []string{ParamFormat},
+			Validator:  validateExportAnnotation,
+		},
+		{
+			Name:       TypeExportColumn,
+			ParamNames: []string{ParamLabel, ParamOrder, ParamSkip},
+			Validator:  validateExportColumnAnnotation,
+		}}
+}
+
+func validateExportAnnotation(annot annotation.Annotation) bool {
+	if annot.Name == TypeExport {
+		format, hasFormat := annot.Attributes[ParamFormat]
+		if !hasFormat {
+			return true
+		}
+		return format == FormatCSV || format == FormatXLSX || format == FormatCSV+","+FormatXLSX
+	}
+	return false
+}
+
+func validateExportColumnAnnotation(annot annotation.Annotation) bool {
+	if annot.Name == TypeExportColumn {
+		return true
+	}
+	return false
+}
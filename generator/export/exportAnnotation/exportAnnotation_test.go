@@ -0,0 +1,37 @@
+package exportAnnotation
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrectExportAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	ann, ok := registry.ResolveAnnotationByName([]string{`// @Export( format = "csv,xlsx" )`}, TypeExport)
+	assert.True(t, ok)
+	assert.Equal(t, "csv,xlsx", ann.Attributes["format"])
+}
+
+func TestDefaultExportAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	assert.NotEmpty(t, registry.ResolveAnnotations([]string{`// @Export()`}))
+}
+
+func TestInvalidFormatExportAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @Export( format = "pdf" )`}))
+}
+
+func TestCorrectExportColumnAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	ann, ok := registry.ResolveAnnotationByName([]string{`// @ExportColumn( label = "Last name", order = "2" )`}, TypeExportColumn)
+	assert.True(t, ok)
+	assert.Equal(t, "Last name", ann.Attributes["label"])
+	assert.Equal(t, "2", ann.Attributes["order"])
+}
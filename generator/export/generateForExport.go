@@ -0,0 +1,272 @@
+package export
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/MarcGrol/golangAnnotations/generator/export/exportAnnotation"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+type exportContext struct {
+	PackageName string
+	Service     model.Struct
+	Exports     []exportOperation
+}
+
+type exportOperation struct {
+	Operation model.Operation
+	RowType   string
+	Columns   []exportColumn
+	CSV       bool
+	XLSX      bool
+}
+
+type exportColumn struct {
+	Label string
+	Field model.Field
+	order int
+}
+
+type Generator struct {
+}
+
+func NewGenerator() generator.Generator {
+	return &Generator{}
+}
+
+func (eg *Generator) Name() string {
+	return "export"
+}
+
+func (eg *Generator) GetTemplates() []generator.Template {
+	return []generator.Template{
+		{Name: "export", APIVersion: 1, Text: exportTemplate},
+	}
+}
+
+func (eg *Generator) GetAnnotations() []annotation.AnnotationDescriptor {
+	return exportAnnotation.Get()
+}
+
+func (eg *Generator) Generate(parsedSource model.ParsedSources, config generator.Config) ([]generator.OutputFile, error) {
+	return generate(config, parsedSource.Structs)
+}
+
+func generate(config generator.Config, structs []model.Struct) ([]generator.OutputFile, error) {
+	packageName, err := generationUtil.GetPackageNameForStructs(structs)
+	if packageName == "" || err != nil {
+		return nil, err
+	}
+	targetDir, err := generationUtil.DetermineTargetPath(config.InputDir, packageName)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []generator.OutputFile{}
+
+	for _, service := range structs {
+		exports, err := getExportOperations(service, structs)
+		if err != nil {
+			return nil, err
+		}
+		if len(exports) == 0 {
+			continue
+		}
+
+		file, err := generationUtil.Generate(generationUtil.Info{
+			Src:            fmt.Sprintf("%s.%s", service.PackageName, service.Name),
+			TypeName:       service.Name,
+			TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/export%s.go", targetDir, toFirstUpper(service.Name))),
+			TemplateName:   "export",
+			TemplateString: exportTemplate,
+			Config:         config,
+			FuncMap:        customTemplateFuncs,
+			Data: exportContext{
+				PackageName: packageName,
+				Service:     service,
+				Exports:     exports,
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Error generating export for service %s:%w", service.Name, err)
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+func getExportOperations(service model.Struct, structs []model.Struct) ([]exportOperation, error) {
+	exports := make([]exportOperation, 0)
+	for _, o := range service.Operations {
+		if !IsExportOperation(*o) {
+			continue
+		}
+		rowType := GetExportRowType(*o)
+		if rowType == "" {
+			return nil, generator.OperationError(*o, service.Name, exportAnnotation.TypeExport, "Export-operation %s.%s must return a slice", service.Name, o.Name)
+		}
+		rowStruct, found := findStruct(structs, rowType)
+		if !found {
+			return nil, generator.OperationError(*o, service.Name, exportAnnotation.TypeExport, "Export-operation %s.%s: row-struct %s not found", service.Name, o.Name, rowType)
+		}
+		exports = append(exports, exportOperation{
+			Operation: *o,
+			RowType:   GetOutputArgType(*o),
+			Columns:   GetExportColumns(rowStruct),
+			CSV:       IsExportFormat(*o, exportAnnotation.FormatCSV),
+			XLSX:      IsExportFormat(*o, exportAnnotation.FormatXLSX),
+		})
+	}
+	return exports, nil
+}
+
+func findStruct(structs []model.Struct, name string) (model.Struct, bool) {
+	for _, s := range structs {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return model.Struct{}, false
+}
+
+var customTemplateFuncs = template.FuncMap{
+	"ToFirstUpper":      toFirstUpper,
+	"CSVValue":          CSVValue,
+	"XLSXValue":         XLSXValue,
+	"QuotedColumnLabel": QuotedColumnLabel,
+}
+
+func IsExportOperation(o model.Operation) bool {
+	annotations := annotation.NewRegistry(exportAnnotation.Get())
+	_, ok := annotations.ResolveAnnotationByName(o.DocLines, exportAnnotation.TypeExport)
+	return ok
+}
+
+func IsExportFormat(o model.Operation, format string) bool {
+	annotations := annotation.NewRegistry(exportAnnotation.Get())
+	if ann, ok := annotations.ResolveAnnotationByName(o.DocLines, exportAnnotation.TypeExport); ok {
+		formats, hasFormats := ann.Attributes[exportAnnotation.ParamFormat]
+		if !hasFormats {
+			return format == exportAnnotation.FormatCSV
+		}
+		for _, f := range strings.Split(formats, ",") {
+			if strings.TrimSpace(f) == format {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func GetOutputArgType(o model.Operation) string {
+	for _, arg := range o.OutputArgs {
+		if arg.TypeName != "error" {
+			return arg.TypeName
+		}
+	}
+	return ""
+}
+
+// GetExportRowType returns the name of the struct that represents a single row of the exported slice
+func GetExportRowType(o model.Operation) string {
+	for _, arg := range o.OutputArgs {
+		if arg.IsSlice() {
+			return strings.TrimPrefix(arg.SliceElementTypeName(), "*")
+		}
+	}
+	return ""
+}
+
+// GetExportColumns returns the ordered columns of a row-struct: when any field is annotated with
+// @ExportColumn only the annotated fields are exported, otherwise all exported fields are.
+func GetExportColumns(s model.Struct) []exportColumn {
+	annotations := annotation.NewRegistry(exportAnnotation.Get())
+
+	explicit := false
+	for _, f := range s.Fields {
+		if _, ok := annotations.ResolveAnnotationByName(f.DocLines, exportAnnotation.TypeExportColumn); ok {
+			explicit = true
+			break
+		}
+	}
+
+	columns := make([]exportColumn, 0, len(s.Fields))
+	for idx, f := range s.Fields {
+		if f.Name == "" || !unicode.IsUpper([]rune(f.Name)[0]) {
+			continue
+		}
+		column := exportColumn{
+			Label: f.Name,
+			Field: f,
+			order: len(s.Fields) + idx,
+		}
+		ann, ok := annotations.ResolveAnnotationByName(f.DocLines, exportAnnotation.TypeExportColumn)
+		if explicit && !ok {
+			continue
+		}
+		if ok {
+			if ann.Attributes[exportAnnotation.ParamSkip] == "true" {
+				continue
+			}
+			if label := ann.Attributes[exportAnnotation.ParamLabel]; label != "" {
+				column.Label = label
+			}
+			if order, err := strconv.Atoi(ann.Attributes[exportAnnotation.ParamOrder]); err == nil {
+				column.order = order
+			}
+		}
+		columns = append(columns, column)
+	}
+	sort.SliceStable(columns, func(i, j int) bool {
+		return columns[i].order < columns[j].order
+	})
+	return columns
+}
+
+func QuotedColumnLabel(c exportColumn) string {
+	return strconv.Quote(c.Label)
+}
+
+// CSVValue returns the go-expression that renders the column of a row as string
+func CSVValue(c exportColumn) string {
+	f := c.Field
+	value := fmt.Sprintf("row.%s", f.Name)
+	if f.IsPointer() {
+		return fmt.Sprintf("func() string { if %s == nil { return \"\" }; return %s }()", value, formatValue(f.DereferencedTypeName(), "*"+value))
+	}
+	return formatValue(f.TypeName, value)
+}
+
+func formatValue(typeName string, value string) string {
+	switch typeName {
+	case "string":
+		return value
+	case "time.Time":
+		return fmt.Sprintf("%s.Format(time.RFC3339)", value)
+	default:
+		return fmt.Sprintf("fmt.Sprint(%s)", value)
+	}
+}
+
+// XLSXValue returns the go-expression that provides the raw cell-value of the column of a row
+func XLSXValue(c exportColumn) string {
+	f := c.Field
+	if !f.IsPointer() && (f.IsPrimitive() || f.TypeName == "time.Time") {
+		return fmt.Sprintf("row.%s", f.Name)
+	}
+	return CSVValue(c)
+}
+
+func toFirstUpper(in string) string {
+	a := []rune(in)
+	a[0] = unicode.ToUpper(a[0])
+	return string(a)
+}
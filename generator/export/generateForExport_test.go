@@ -0,0 +1,104 @@
+package export
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func cleanup() {
+	os.Remove(generationUtil.Prefixed("./testData/exportMyService.go"))
+}
+
+func TestGenerateForExport(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			Name:        "MyService",
+			Operations: []*model.Operation{
+				{
+					DocLines:      []string{`// @Export( format = "csv,xlsx" )`},
+					Name:          "listPersons",
+					RelatedStruct: &model.Field{TypeName: "*MyService"},
+					OutputArgs: []model.Field{
+						{TypeName: "[]Person"},
+						{TypeName: "error"},
+					},
+				},
+			},
+		},
+		{
+			PackageName: "testData",
+			Name:        "Person",
+			Fields: []model.Field{
+				{Name: "FirstName", TypeName: "string", DocLines: []string{`// @ExportColumn( label = "First name", order = "2" )`}},
+				{Name: "LastName", TypeName: "string", DocLines: []string{`// @ExportColumn( label = "Last name", order = "1" )`}},
+				{Name: "Age", TypeName: "*int", DocLines: []string{`// @ExportColumn()`}},
+				{Name: "Secret", TypeName: "string"},
+			},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Nil(t, err)
+
+	// check that generated files exists
+	_, err = os.Stat(generationUtil.Prefixed("./testData/exportMyService.go"))
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/exportMyService.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `func (service *MyService) listPersonsWriteCSV(w io.Writer, rows []Person) error {`)
+	assert.Contains(t, string(data), `func (service *MyService) listPersonsWriteXLSX(w io.Writer, rows []Person) error {`)
+	assert.Contains(t, string(data), `"Last name",
+		"First name",
+		"Age",`)
+	assert.Contains(t, string(data), `func() string {
+				if row.Age == nil {
+					return ""
+				}
+				return fmt.Sprint(*row.Age)
+			}()`)
+	assert.NotContains(t, string(data), `"Secret"`)
+}
+
+func TestGenerateForExportWithoutSlice(t *testing.T) {
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			Name:        "MyService",
+			Operations: []*model.Operation{
+				{
+					DocLines:   []string{`// @Export()`},
+					Name:       "getPerson",
+					OutputArgs: []model.Field{{TypeName: "Person"}, {TypeName: "error"}},
+				},
+			},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Error(t, err)
+}
+
+func TestGetExportColumnsWithoutAnnotations(t *testing.T) {
+	columns := GetExportColumns(model.Struct{
+		Fields: []model.Field{
+			{Name: "Name", TypeName: "string"},
+			{Name: "hidden", TypeName: "string"},
+			{Name: "Day", TypeName: "time.Time"},
+		},
+	})
+	assert.Len(t, columns, 2)
+	assert.Equal(t, "Name", columns[0].Label)
+	assert.Equal(t, "row.Day.Format(time.RFC3339)", CSVValue(columns[1]))
+	assert.Equal(t, "row.Day", XLSXValue(columns[1]))
+}
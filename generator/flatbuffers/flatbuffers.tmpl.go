@@ -0,0 +1,129 @@
+package flatbuffers
+
+const flatbuffersSchemaTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+
+namespace {{.PackageName}};
+{{range .Events}}
+table {{.Event.Name}} {
+{{- range .Fields}}
+	{{.Name}}:{{.Type}};
+{{- end}}
+}
+{{end -}}
+`
+
+const flatbuffersGlueTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+
+package {{.PackageName}}
+
+import (
+	"fmt"
+	"time"
+
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+{{range .Events -}}
+{{$event := .Event.Name -}}
+// {{$event}}Flat provides zero-copy access to a {{$event}} that is encoded as flatbuffer
+type {{$event}}Flat struct {
+	tab flatbuffers.Table
+}
+
+// GetRootAs{{$event}}Flat accesses the {{$event}} within buf without decoding it
+func GetRootAs{{$event}}Flat(buf []byte) *{{$event}}Flat {
+	n := flatbuffers.GetUOffsetT(buf)
+	return &{{$event}}Flat{tab: flatbuffers.Table{Bytes: buf, Pos: n}}
+}
+
+{{range .Fields -}}
+{{if IsStrings . -}}
+// {{.Field.Name}}Length returns the number of elements of {{.Field.Name}}
+func (f *{{$event}}Flat) {{.Field.Name}}Length() int {
+	if o := flatbuffers.UOffsetT(f.tab.Offset({{VtableOffset .Slot}})); o != 0 {
+		return f.tab.VectorLen(o)
+	}
+	return 0
+}
+
+// {{.Field.Name}} returns element j of {{.Field.Name}} without copying it
+func (f *{{$event}}Flat) {{.Field.Name}}(j int) []byte {
+	if o := flatbuffers.UOffsetT(f.tab.Offset({{VtableOffset .Slot}})); o != 0 {
+		a := f.tab.Vector(o)
+		return f.tab.ByteVector(a + flatbuffers.UOffsetT(j*4))
+	}
+	return nil
+}
+
+{{else if IsString . -}}
+// {{.Field.Name}} returns {{.Field.Name}} without copying it
+func (f *{{$event}}Flat) {{.Field.Name}}() []byte {
+	if o := flatbuffers.UOffsetT(f.tab.Offset({{VtableOffset .Slot}})); o != 0 {
+		return f.tab.ByteVector(o + f.tab.Pos)
+	}
+	return nil
+}
+
+{{else -}}
+// {{.Field.Name}} returns the raw value of {{.Field.Name}}
+func (f *{{$event}}Flat) {{.Field.Name}}() {{.GoType}} {
+	if o := flatbuffers.UOffsetT(f.tab.Offset({{VtableOffset .Slot}})); o != 0 {
+		return f.tab.Get{{.Scalar}}(o + f.tab.Pos)
+	}
+	return {{.Zero}}
+}
+
+{{end -}}
+{{end -}}
+
+// MarshalFlatBuffer encodes the {{$event}} as flatbuffer
+func (e {{$event}}) MarshalFlatBuffer() []byte {
+	b := flatbuffers.NewBuilder(0)
+	{{range .Fields -}}
+	{{if IsString . -}}
+	{{ToFirstLower .Field.Name}}Offset := b.CreateString({{.Encode}})
+	{{else if IsStrings . -}}
+	{{ToFirstLower .Field.Name}}Offsets := make([]flatbuffers.UOffsetT, len({{.Encode}}))
+	for i, s := range {{.Encode}} {
+		{{ToFirstLower .Field.Name}}Offsets[i] = b.CreateString(s)
+	}
+	b.StartVector(4, len({{ToFirstLower .Field.Name}}Offsets), 4)
+	for i := len({{ToFirstLower .Field.Name}}Offsets) - 1; i >= 0; i-- {
+		b.PrependUOffsetT({{ToFirstLower .Field.Name}}Offsets[i])
+	}
+	{{ToFirstLower .Field.Name}}Offset := b.EndVector(len({{ToFirstLower .Field.Name}}Offsets))
+	{{end -}}
+	{{end -}}
+	b.StartObject({{len .Fields}})
+	{{range .Fields -}}
+	{{if IsScalar . -}}
+	b.Prepend{{.Scalar}}Slot({{.Slot}}, {{.Encode}}, {{.Zero}})
+	{{else -}}
+	b.PrependUOffsetTSlot({{.Slot}}, {{ToFirstLower .Field.Name}}Offset, 0)
+	{{end -}}
+	{{end -}}
+	b.Finish(b.EndObject())
+	return b.FinishedBytes()
+}
+
+// UnmarshalFlatBuffer decodes a {{$event}} from a flatbuffer
+func (e *{{$event}}) UnmarshalFlatBuffer(buf []byte) error {
+	if len(buf) < flatbuffers.SizeUOffsetT {
+		return fmt.Errorf("Flatbuffer of %d bytes is too small for a {{$event}}", len(buf))
+	}
+	f := GetRootAs{{$event}}Flat(buf)
+	{{range .Fields -}}
+	{{if IsStrings . -}}
+	e.{{.Field.Name}} = make([]string, f.{{.Field.Name}}Length())
+	for j := range e.{{.Field.Name}} {
+		e.{{.Field.Name}}[j] = string(f.{{.Field.Name}}(j))
+	}
+	{{else -}}
+	e.{{.Field.Name}} = {{.DecodeExpression (printf "f.%s()" .Field.Name)}}
+	{{end -}}
+	{{end -}}
+	return nil
+}
+
+{{end -}}
+`
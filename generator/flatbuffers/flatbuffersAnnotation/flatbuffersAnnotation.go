@@ -0,0 +1,23 @@
+package flatbuffersAnnotation
+
+import "github.com/MarcGrol/golangAnnotations/generator/annotation"
+
+const (
+	TypeZeroCopy = "ZeroCopy"
+)
+
+func Get() []annotation.AnnotationDescriptor {
+	return []annotation.AnnotationDescriptor{
+		{
+			Name:       TypeZeroCopy,
+			ParamNames: []string{},
+			Validator:  validateZeroCopyAnnotation,
+		}}
+}
+
+func validateZeroCopyAnnotation(annot annotation.Annotation) bool {
+	if annot.Name == TypeZeroCopy {
+		return true
+	}
+	return false
+}
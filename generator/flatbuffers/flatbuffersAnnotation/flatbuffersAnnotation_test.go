@@ -0,0 +1,16 @@
+package flatbuffersAnnotation
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrectZeroCopyAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	annotation, ok := registry.ResolveAnnotation(`// @ZeroCopy()`)
+	assert.True(t, ok)
+	assert.Equal(t, TypeZeroCopy, annotation.Name)
+}
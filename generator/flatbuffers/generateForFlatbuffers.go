@@ -0,0 +1,243 @@
+package flatbuffers
+
+import (
+	"fmt"
+	"text/template"
+	"unicode"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/MarcGrol/golangAnnotations/generator/event"
+	"github.com/MarcGrol/golangAnnotations/generator/flatbuffers/flatbuffersAnnotation"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+const (
+	kindScalar  = "scalar"
+	kindString  = "string"
+	kindStrings = "strings"
+)
+
+// Field describes how a field of an event is stored in a flatbuffers-table
+type Field struct {
+	Field model.Field
+	Slot  int
+	// Name of the field within the .fbs schema
+	Name string
+	// Type of the field within the .fbs schema
+	Type string
+	Kind string
+	// Scalar is the suffix of the flatbuffers-methods for scalars, like Int64 in PrependInt64Slot and GetInt64
+	Scalar string
+	// GoType is the go-type of the zero-copy accessor
+	GoType string
+	// Encode and Decode convert between the type of the struct-field and the type of the accessor
+	Encode string
+	Decode string
+	// Zero is the default value of a scalar that flatbuffers leaves out of the buffer
+	Zero string
+}
+
+type flatbuffersContext struct {
+	PackageName string
+	Events      []flatbuffersEvent
+}
+
+type flatbuffersEvent struct {
+	Event  model.Struct
+	Fields []Field
+}
+
+type scalarType struct {
+	fbsType string
+	scalar  string
+	goType  string
+}
+
+var scalarTypes = map[string]scalarType{
+	"bool":    {fbsType: "bool", scalar: "Bool", goType: "bool"},
+	"int":     {fbsType: "long", scalar: "Int64", goType: "int64"},
+	"int32":   {fbsType: "int", scalar: "Int32", goType: "int32"},
+	"int64":   {fbsType: "long", scalar: "Int64", goType: "int64"},
+	"float32": {fbsType: "float", scalar: "Float32", goType: "float32"},
+	"float64": {fbsType: "double", scalar: "Float64", goType: "float64"},
+}
+
+type Generator struct {
+}
+
+func NewGenerator() generator.Generator {
+	return &Generator{}
+}
+
+func (eg *Generator) Name() string {
+	return "flatbuffers"
+}
+
+func (eg *Generator) GetTemplates() []generator.Template {
+	return []generator.Template{
+		{Name: "flatbuffers-schema", APIVersion: 1, Text: flatbuffersSchemaTemplate},
+		{Name: "flatbuffers-glue", APIVersion: 1, Text: flatbuffersGlueTemplate},
+	}
+}
+
+func (eg *Generator) GetAnnotations() []annotation.AnnotationDescriptor {
+	return flatbuffersAnnotation.Get()
+}
+
+func (eg *Generator) Generate(parsedSource model.ParsedSources, config generator.Config) ([]generator.OutputFile, error) {
+	return generate(config, parsedSource.Structs, parsedSource.Enums)
+}
+
+func generate(config generator.Config, structs []model.Struct, enums []model.Enum) ([]generator.OutputFile, error) {
+	packageName, err := generationUtil.GetPackageNameForStructs(structs)
+	if packageName == "" || err != nil {
+		return nil, err
+	}
+	targetDir, err := generationUtil.DetermineTargetPath(config.InputDir, packageName)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []generator.OutputFile{}
+
+	events := []flatbuffersEvent{}
+	for _, s := range structs {
+		if !IsZeroCopy(s) {
+			continue
+		}
+		if !event.IsEvent(s) {
+			return nil, generator.StructError(s, flatbuffersAnnotation.TypeZeroCopy, "ZeroCopy-struct %s must be annotated with @Event", s.Name)
+		}
+		fields, err := GetFields(s, enums)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, flatbuffersEvent{
+			Event:  s,
+			Fields: fields,
+		})
+	}
+	if len(events) == 0 {
+		return files, nil
+	}
+
+	data := flatbuffersContext{
+		PackageName: packageName,
+		Events:      events,
+	}
+	file, err := generationUtil.Generate(generationUtil.Info{
+		Src:            fmt.Sprintf("%s.%s", packageName, "events"),
+		TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/zeroCopyEvents.fbs", targetDir)),
+		TemplateName:   "flatbuffers-schema",
+		TemplateString: flatbuffersSchemaTemplate,
+		Config:         config,
+		FuncMap:        customTemplateFuncs,
+		Data:           data,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Error generating flatbuffers-schema for package %s:%w", packageName, err)
+	}
+	files = append(files, file)
+
+	file, err = generationUtil.Generate(generationUtil.Info{
+		Src:            fmt.Sprintf("%s.%s", packageName, "events"),
+		TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/zeroCopyEvents.go", targetDir)),
+		TemplateName:   "flatbuffers-glue",
+		TemplateString: flatbuffersGlueTemplate,
+		Config:         config,
+		FuncMap:        customTemplateFuncs,
+		Data:           data,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Error generating flatbuffers-glue for package %s:%w", packageName, err)
+	}
+	files = append(files, file)
+	return files, nil
+}
+
+var customTemplateFuncs = template.FuncMap{
+	"VtableOffset": vtableOffset,
+	"IsScalar":     func(f Field) bool { return f.Kind == kindScalar },
+	"IsString":     func(f Field) bool { return f.Kind == kindString },
+	"IsStrings":    func(f Field) bool { return f.Kind == kindStrings },
+	"ToFirstLower": toFirstLower,
+}
+
+func IsZeroCopy(s model.Struct) bool {
+	annotations := annotation.NewRegistry(flatbuffersAnnotation.Get())
+	_, ok := annotations.ResolveAnnotationByName(s.DocLines, flatbuffersAnnotation.TypeZeroCopy)
+	return ok
+}
+
+// GetFields maps all exported fields of an event onto flatbuffers-slots, in order of declaration
+func GetFields(s model.Struct, enums []model.Enum) ([]Field, error) {
+	fields := make([]Field, 0, len(s.Fields))
+	for _, f := range s.Fields {
+		if f.Name == "" || !unicode.IsUpper([]rune(f.Name)[0]) {
+			continue
+		}
+		field := Field{
+			Field: f,
+			Slot:  len(fields),
+			Name:  generationUtil.SnakeCase(f.Name),
+		}
+		if !describeField(&field, f, enums) {
+			return nil, generator.FieldError(s, f, "", "ZeroCopy-event %s: field %s of type %s is not supported", s.Name, f.Name, f.TypeName)
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+func describeField(field *Field, f model.Field, enums []model.Enum) bool {
+	value := "e." + f.Name
+	switch f.TypeName {
+	case "string":
+		field.Type, field.Kind, field.GoType = "string", kindString, "[]byte"
+		field.Encode, field.Decode = value, "string(%s)"
+		return true
+	case "[]string":
+		field.Type, field.Kind, field.GoType = "[string]", kindStrings, "[]byte"
+		field.Encode, field.Decode = value, "string(%s)"
+		return true
+	case "time.Time":
+		// stored as unix-nanos in UTC
+		field.Type, field.Kind, field.Scalar, field.GoType = "long", kindScalar, "Int64", "int64"
+		field.Encode, field.Decode, field.Zero = fmt.Sprintf("%s.UnixNano()", value), "time.Unix(0, %s).UTC()", "0"
+		return true
+	}
+	if scalar, found := scalarTypes[f.TypeName]; found {
+		field.Type, field.Kind, field.Scalar, field.GoType = scalar.fbsType, kindScalar, scalar.scalar, scalar.goType
+		field.Encode, field.Decode, field.Zero = fmt.Sprintf("%s(%s)", scalar.goType, value), f.TypeName+"(%s)", "0"
+		if scalar.goType == "bool" {
+			field.Encode, field.Decode, field.Zero = value, "%s", "false"
+		}
+		return true
+	}
+	for _, e := range enums {
+		if e.Name == f.TypeName {
+			field.Type, field.Kind, field.Scalar, field.GoType = "int", kindScalar, "Int32", "int32"
+			field.Encode, field.Decode, field.Zero = fmt.Sprintf("int32(%s)", value), f.TypeName+"(%s)", "0"
+			return true
+		}
+	}
+	return false
+}
+
+// vtableOffset returns the position of a slot within the vtable of a table
+func vtableOffset(slot int) int {
+	return 4 + 2*slot
+}
+
+// DecodeExpression returns the go-expression that converts the accessor-value of a field into the type of the struct-field
+func (f Field) DecodeExpression(accessor string) string {
+	return fmt.Sprintf(f.Decode, accessor)
+}
+
+func toFirstLower(in string) string {
+	a := []rune(in)
+	a[0] = unicode.ToLower(a[0])
+	return string(a)
+}
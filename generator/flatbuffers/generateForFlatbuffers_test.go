@@ -0,0 +1,93 @@
+package flatbuffers
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func cleanup() {
+	os.Remove(generationUtil.Prefixed("./testData/zeroCopyEvents.fbs"))
+	os.Remove(generationUtil.Prefixed("./testData/zeroCopyEvents.go"))
+}
+
+func TestGenerateForFlatbuffers(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			DocLines:    []string{`// @Event( aggregate = "Order" )`, `// @ZeroCopy()`},
+			PackageName: "testData",
+			Name:        "OrderPlaced",
+			Fields: []model.Field{
+				{Name: "OrderUID", TypeName: "string"},
+				{Name: "Amount", TypeName: "int"},
+				{Name: "Express", TypeName: "bool"},
+				{Name: "Items", TypeName: "[]string"},
+				{Name: "PlacedAt", TypeName: "time.Time"},
+				{Name: "Status", TypeName: "OrderStatus"},
+				{Name: "internal", TypeName: "string"},
+			},
+		},
+		{
+			DocLines:    []string{`// @Event( aggregate = "Order" )`},
+			PackageName: "testData",
+			Name:        "OrderCancelled",
+		},
+	}
+	enums := []model.Enum{{Name: "OrderStatus", EnumLiterals: []model.EnumLiteral{{Name: "OrderStatusNew"}}}}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s, Enums: enums}, generator.Config{InputDir: "testData"})
+	assert.Nil(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/zeroCopyEvents.fbs"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "namespace testData;")
+	assert.Contains(t, string(data), `table OrderPlaced {
+	order_uid:string;
+	amount:long;
+	express:bool;
+	items:[string];
+	placed_at:long;
+	status:int;
+}`)
+	assert.NotContains(t, string(data), "OrderCancelled")
+
+	data, err = ioutil.ReadFile(generationUtil.Prefixed("./testData/zeroCopyEvents.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "func GetRootAsOrderPlacedFlat(buf []byte) *OrderPlacedFlat {")
+	assert.Contains(t, string(data), "func (f *OrderPlacedFlat) OrderUID() []byte {")
+	assert.Contains(t, string(data), "return f.tab.GetInt64(o + f.tab.Pos)")
+	assert.Contains(t, string(data), "b.PrependInt64Slot(1, int64(e.Amount), 0)")
+	assert.Contains(t, string(data), "b.PrependBoolSlot(2, e.Express, false)")
+	assert.Contains(t, string(data), "b.PrependUOffsetTSlot(3, itemsOffset, 0)")
+	assert.Contains(t, string(data), "e.PlacedAt = time.Unix(0, f.PlacedAt()).UTC()")
+	assert.Contains(t, string(data), "e.Status = OrderStatus(f.Status())")
+}
+
+func TestZeroCopyRequiresEvent(t *testing.T) {
+	s := []model.Struct{
+		{
+			DocLines:    []string{`// @ZeroCopy()`},
+			PackageName: "testData",
+			Name:        "OrderPlaced",
+		},
+	}
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Error(t, err)
+}
+
+func TestGetFieldsRejectsUnsupportedTypes(t *testing.T) {
+	s := model.Struct{
+		Name:   "OrderPlaced",
+		Fields: []model.Field{{Name: "Lines", TypeName: "[]OrderLine"}},
+	}
+	_, err := GetFields(s, nil)
+	assert.EqualError(t, err, "ZeroCopy-event OrderPlaced: field Lines of type []OrderLine is not supported")
+}
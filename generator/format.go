@@ -0,0 +1,223 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// standardImports are the packages of the standard library that are added to a generated go file that refers to
+// them. Other packages are left to the user, because resolving them would depend on the environment and make
+// the generated output irreproducible.
+var standardImports = map[string]string{
+	"bufio":     "bufio",
+	"bytes":     "bytes",
+	"context":   "context",
+	"base64":    "encoding/base64",
+	"binary":    "encoding/binary",
+	"csv":       "encoding/csv",
+	"hex":       "encoding/hex",
+	"json":      "encoding/json",
+	"xml":       "encoding/xml",
+	"errors":    "errors",
+	"flag":      "flag",
+	"fmt":       "fmt",
+	"io":        "io",
+	"ioutil":    "io/ioutil",
+	"log":       "log",
+	"math":      "math",
+	"mime":      "mime",
+	"multipart": "mime/multipart",
+	"http":      "net/http",
+	"httptest":  "net/http/httptest",
+	"url":       "net/url",
+	"os":        "os",
+	"filepath":  "path/filepath",
+	"reflect":   "reflect",
+	"regexp":    "regexp",
+	"sort":      "sort",
+	"strconv":   "strconv",
+	"strings":   "strings",
+	"sync":      "sync",
+	"testing":   "testing",
+	"time":      "time",
+	"unicode":   "unicode",
+}
+
+var versionSuffix = regexp.MustCompile(`^v[0-9]+$`)
+
+// FormatGoFiles fixes the imports of the generated go files and formats them like gofmt. Files that are marked
+// as fixed are rewritten sources that the generator formats itself.
+func FormatGoFiles(files []OutputFile) ([]OutputFile, error) {
+	for idx, f := range files {
+		if f.Fixed || filepath.Ext(f.Filename) != ".go" {
+			continue
+		}
+		formatted, err := FormatGoSource(f.Filename, f.Content)
+		if err != nil {
+			return nil, err
+		}
+		files[idx].Content = formatted
+	}
+	return files, nil
+}
+
+// FormatGoSource removes unused imports, adds missing imports of the standard library and formats src.
+// It fails with the numbered source attached when src is not valid go.
+func FormatGoSource(filename string, src []byte) ([]byte, error) {
+	fileSet := token.NewFileSet()
+	file, err := parser.ParseFile(fileSet, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing generated file %s:%s\n%s", filename, err, numberedLines(src))
+	}
+	fixed := fixImports(fileSet, file, src)
+	formatted, err := format.Source(fixed)
+	if err != nil {
+		return nil, fmt.Errorf("Error formatting generated file %s:%s\n%s", filename, err, numberedLines(fixed))
+	}
+	return formatted, nil
+}
+
+// fixImports replaces the import-declarations of file by a single declaration with the standard library and
+// the other packages in separate groups, just like goimports does
+func fixImports(fileSet *token.FileSet, file *ast.File, src []byte) []byte {
+	used := referencedPackageNames(file)
+
+	imports := map[string]string{}
+	for _, spec := range file.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := ""
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		if name == "_" || name == "." || used[importName(name, path)] || !isImportNameKnown(name, path) {
+			imports[path] = name
+		}
+	}
+	for name := range used {
+		if path, found := standardImports[name]; found && !isImported(imports, name) {
+			imports[path] = ""
+		}
+	}
+
+	var buf bytes.Buffer
+	start := fileSet.Position(file.Name.End()).Offset
+	buf.Write(src[:start])
+	buf.WriteString("\n\n")
+	writeImportDecl(&buf, imports)
+
+	// skip the original import-declarations, keeping everything in between
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.IMPORT {
+			continue
+		}
+		declStart := fileSet.Position(genDecl.Pos()).Offset
+		buf.Write(src[start:declStart])
+		start = fileSet.Position(genDecl.End()).Offset
+	}
+	buf.Write(src[start:])
+	return buf.Bytes()
+}
+
+func writeImportDecl(buf *bytes.Buffer, imports map[string]string) {
+	if len(imports) == 0 {
+		return
+	}
+	standard, other := []string{}, []string{}
+	for path := range imports {
+		if isStandardLibrary(path) {
+			standard = append(standard, path)
+		} else {
+			other = append(other, path)
+		}
+	}
+	sort.Strings(standard)
+	sort.Strings(other)
+
+	buf.WriteString("import (\n")
+	for idx, group := range [][]string{standard, other} {
+		if idx > 0 && len(standard) > 0 && len(other) > 0 {
+			buf.WriteString("\n")
+		}
+		for _, path := range group {
+			buf.WriteString("\t")
+			if name := imports[path]; name != "" {
+				buf.WriteString(name + " ")
+			}
+			buf.WriteString(strconv.Quote(path) + "\n")
+		}
+	}
+	buf.WriteString(")\n")
+}
+
+// referencedPackageNames returns the identifiers that are used as package in a qualified identifier: the
+// parser leaves them unresolved because they are not declared in the file
+func referencedPackageNames(file *ast.File) map[string]bool {
+	used := map[string]bool{}
+	ast.Inspect(file, func(node ast.Node) bool {
+		if sel, ok := node.(*ast.SelectorExpr); ok {
+			if ident, ok := sel.X.(*ast.Ident); ok && ident.Obj == nil {
+				used[ident.Name] = true
+			}
+		}
+		return true
+	})
+	return used
+}
+
+// importName guesses the name of an imported package from the last element of its path, skipping a major version
+func importName(name string, path string) string {
+	if name != "" {
+		return name
+	}
+	elements := strings.Split(path, "/")
+	last := elements[len(elements)-1]
+	if versionSuffix.MatchString(last) && len(elements) > 1 {
+		last = elements[len(elements)-2]
+	}
+	return last
+}
+
+// isImportNameKnown tells if the name of the imported package can be derived from its path with certainty:
+// other imports are never removed
+func isImportNameKnown(name string, path string) bool {
+	if name != "" || isStandardLibrary(path) {
+		return true
+	}
+	elements := strings.Split(path, "/")
+	return token.IsIdentifier(elements[len(elements)-1])
+}
+
+func isImported(imports map[string]string, name string) bool {
+	for path, importedName := range imports {
+		if importName(importedName, path) == name {
+			return true
+		}
+	}
+	return false
+}
+
+func isStandardLibrary(path string) bool {
+	firstElement := strings.SplitN(path, "/", 2)[0]
+	return !strings.Contains(firstElement, ".")
+}
+
+func numberedLines(src []byte) string {
+	var buf strings.Builder
+	for idx, line := range strings.Split(string(src), "\n") {
+		fmt.Fprintf(&buf, "%4d  %s\n", idx+1, line)
+	}
+	return buf.String()
+}
@@ -0,0 +1,70 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatGoSourceFixesImports(t *testing.T) {
+	src := `package example
+
+import (
+	"github.com/gorilla/mux"
+	"net/http"
+	"encoding/json"
+	"github.com/go-redis/redis/v8"
+	"github.com/MarcGrol/golangAnnotations/generator/rest/errorh"
+)
+
+func handle(w http.ResponseWriter, client *redis.Client) {
+	fmt.Fprintf(w, "%s", strings.ToUpper("ok"))
+}
+`
+	formatted, err := FormatGoSource("gen_example.go", []byte(src))
+	assert.NoError(t, err)
+	assert.Equal(t, `package example
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-redis/redis/v8"
+)
+
+func handle(w http.ResponseWriter, client *redis.Client) {
+	fmt.Fprintf(w, "%s", strings.ToUpper("ok"))
+}
+`, string(formatted))
+}
+
+func TestFormatGoSourceKeepsImportsWithUnknownName(t *testing.T) {
+	src := `package example
+
+import "github.com/example/go-lib"
+
+var x = lib.Value
+`
+	formatted, err := FormatGoSource("gen_example.go", []byte(src))
+	assert.NoError(t, err)
+	assert.Contains(t, string(formatted), `"github.com/example/go-lib"`)
+}
+
+func TestFormatGoSourceInvalid(t *testing.T) {
+	_, err := FormatGoSource("gen_example.go", []byte("package example\n\nfunc {\n"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "gen_example.go")
+	assert.Contains(t, err.Error(), "   3  func {")
+}
+
+func TestFormatGoFilesSkipsOtherFiles(t *testing.T) {
+	files := []OutputFile{
+		{Filename: "gen_example.json", Content: []byte("{ }")},
+		{Filename: "example.go", Fixed: true, Content: []byte("package  example")},
+	}
+	formatted, err := FormatGoFiles(files)
+	assert.NoError(t, err)
+	assert.Equal(t, "{ }", string(formatted[0].Content))
+	assert.Equal(t, "package  example", string(formatted[1].Content))
+}
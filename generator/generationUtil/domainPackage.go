@@ -0,0 +1,125 @@
+package generationUtil
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+// GetImportPath determines the import path of the package in dir, based on the go.mod of the module it belongs to
+// or, for code that still lives in the GOPATH, on its location below GOPATH/src
+func GetImportPath(dir string) (string, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("Error determining absolute path of %s:%w", dir, err)
+	}
+	for moduleDir := absDir; ; moduleDir = filepath.Dir(moduleDir) {
+		modulePath, found, err := readModulePath(filepath.Join(moduleDir, "go.mod"))
+		if err != nil {
+			return "", err
+		}
+		if found {
+			return joinImportPath(modulePath, moduleDir, absDir)
+		}
+		if filepath.Dir(moduleDir) == moduleDir {
+			break
+		}
+	}
+	goPath := os.Getenv("GOPATH")
+	if goPath != "" {
+		srcDir := filepath.Join(goPath, "src")
+		if strings.HasPrefix(absDir, srcDir+string(filepath.Separator)) {
+			return joinImportPath("", srcDir, absDir)
+		}
+	}
+	return "", fmt.Errorf("Cannot determine import path of %s: no go.mod found", dir)
+}
+
+func readModulePath(filename string) (string, bool, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("Error opening %s:%w", filename, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "module ") {
+			return strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "module ")), `"`), true, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", false, fmt.Errorf("Error reading %s:%w", filename, err)
+	}
+	return "", false, fmt.Errorf("No module declaration in %s", filename)
+}
+
+func joinImportPath(rootPath string, rootDir string, dir string) (string, error) {
+	relativeDir, err := filepath.Rel(rootDir, dir)
+	if err != nil {
+		return "", fmt.Errorf("Error determining path of %s relative to %s:%w", dir, rootDir, err)
+	}
+	if relativeDir == "." {
+		return rootPath, nil
+	}
+	if rootPath == "" {
+		return filepath.ToSlash(relativeDir), nil
+	}
+	return rootPath + "/" + filepath.ToSlash(relativeDir), nil
+}
+
+// QualifyTypeName prefixes the exported types of the annotated package in typeName with its package-name,
+// so that generated code in another package can refer to them: "[]*Person" becomes "[]*domain.Person"
+func QualifyTypeName(typeName string, packageName string) string {
+	switch {
+	case strings.HasPrefix(typeName, "*"):
+		return "*" + QualifyTypeName(typeName[1:], packageName)
+	case strings.HasPrefix(typeName, "[]"):
+		return "[]" + QualifyTypeName(typeName[2:], packageName)
+	case strings.HasPrefix(typeName, "map["):
+		keyType, valueType := model.Field{TypeName: typeName}.SplitMapTypeNames()
+		return fmt.Sprintf("map[%s]%s", QualifyTypeName(keyType, packageName), QualifyTypeName(valueType, packageName))
+	case typeName == "" || strings.Contains(typeName, "."):
+		return typeName
+	case unicode.IsUpper([]rune(typeName)[0]):
+		return packageName + "." + typeName
+	}
+	return typeName
+}
+
+// QualifyStruct returns a copy of s in which the arguments of all operations refer to the types of the annotated
+// package by their qualified name
+func QualifyStruct(s model.Struct, packageName string) model.Struct {
+	operations := make([]*model.Operation, 0, len(s.Operations))
+	for _, o := range s.Operations {
+		qualified := *o
+		qualified.InputArgs = qualifyFields(o.InputArgs, packageName)
+		qualified.OutputArgs = qualifyFields(o.OutputArgs, packageName)
+		operations = append(operations, &qualified)
+	}
+	s.Operations = operations
+	return s
+}
+
+func qualifyFields(fields []model.Field, packageName string) []model.Field {
+	qualified := make([]model.Field, 0, len(fields))
+	for _, f := range fields {
+		f.TypeName = QualifyTypeName(f.TypeName, packageName)
+		qualified = append(qualified, f)
+	}
+	return qualified
+}
+
+// IsExported tells if generated code in another package can call the function or method with this name
+func IsExported(name string) bool {
+	return name != "" && unicode.IsUpper([]rune(name)[0])
+}
@@ -0,0 +1,54 @@
+package generationUtil
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetImportPathFromGoMod(t *testing.T) {
+	moduleDir := t.TempDir()
+	err := ioutil.WriteFile(filepath.Join(moduleDir, "go.mod"), []byte("module example.com/shop\n\ngo 1.16\n"), 0644)
+	assert.NoError(t, err)
+	err = os.MkdirAll(filepath.Join(moduleDir, "order", "domain"), 0777)
+	assert.NoError(t, err)
+
+	importPath, err := GetImportPath(filepath.Join(moduleDir, "order", "domain"))
+	assert.NoError(t, err)
+	assert.Equal(t, "example.com/shop/order/domain", importPath)
+
+	importPath, err = GetImportPath(moduleDir)
+	assert.NoError(t, err)
+	assert.Equal(t, "example.com/shop", importPath)
+}
+
+func TestGetImportPathOfThisPackage(t *testing.T) {
+	importPath, err := GetImportPath(".")
+	assert.NoError(t, err)
+	assert.Equal(t, "github.com/MarcGrol/golangAnnotations/generator/generationUtil", importPath)
+}
+
+func TestQualifyTypeName(t *testing.T) {
+	assert.Equal(t, "domain.Person", QualifyTypeName("Person", "domain"))
+	assert.Equal(t, "[]*domain.Person", QualifyTypeName("[]*Person", "domain"))
+	assert.Equal(t, "map[string]domain.Person", QualifyTypeName("map[string]Person", "domain"))
+	assert.Equal(t, "string", QualifyTypeName("string", "domain"))
+	assert.Equal(t, "context.Context", QualifyTypeName("context.Context", "domain"))
+	assert.Equal(t, "error", QualifyTypeName("error", "domain"))
+}
+
+func TestQualifyStructLeavesOriginalUntouched(t *testing.T) {
+	s := model.Struct{
+		Name: "Service",
+		Operations: []*model.Operation{
+			{Name: "GetPerson", OutputArgs: []model.Field{{TypeName: "*Person"}, {TypeName: "error"}}},
+		},
+	}
+	qualified := QualifyStruct(s, "domain")
+	assert.Equal(t, "*domain.Person", qualified.Operations[0].OutputArgs[0].TypeName)
+	assert.Equal(t, "*Person", s.Operations[0].OutputArgs[0].TypeName)
+}
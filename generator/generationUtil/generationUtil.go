@@ -1,14 +1,16 @@
 package generationUtil
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"path"
-	"path/filepath"
 	"strings"
 	"text/template"
 
+	"github.com/MarcGrol/golangAnnotations/generator"
 	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/Masterminds/sprig/v3"
 )
 
 func GetPackageNameForStructs(structs []model.Struct) (string, error) {
@@ -24,6 +26,19 @@ func GetPackageNameForStructs(structs []model.Struct) (string, error) {
 	return packageName, nil
 }
 
+func GetPackageNameForInterfaces(interfaces []model.Interface) (string, error) {
+	if len(interfaces) == 0 {
+		return "", nil // Need at least one interface to determine package-name
+	}
+	packageName := interfaces[0].PackageName
+	for _, i := range interfaces {
+		if i.PackageName != packageName {
+			return "", fmt.Errorf("List of interfaces has multiple package-names")
+		}
+	}
+	return packageName, nil
+}
+
 func getPackageNameForEnums(enums []model.Enum) (string, error) {
 	if len(enums) == 0 {
 		return "", nil // Need at least one enum to determine package-name
@@ -74,7 +89,7 @@ func DetermineTargetPath(inputDir string, packageName string) (string, error) {
 		// Perform some additional check when still using GOPATH
 		workDir, err := os.Getwd()
 		if err != nil {
-			return "", fmt.Errorf("Error getting working dir:%s", err)
+			return "", fmt.Errorf("Error getting working dir:%w", err)
 		}
 
 		if !strings.Contains(workDir, goPath) {
@@ -90,40 +105,59 @@ func DetermineTargetPath(inputDir string, packageName string) (string, error) {
 }
 
 type Info struct {
-	Src            string
+	Src string
+	// TypeName is the struct or interface that the file is generated for: empty when the file covers a package
+	TypeName       string
 	TargetFilename string
 	TemplateName   string
 	TemplateString string
 	FuncMap        template.FuncMap
 	Data           interface{}
+	// Config provides the user-provided template-overrides and template-functions
+	Config generator.Config
 }
 
-func Generate(twd Info) error {
-	fmt.Fprintf(os.Stderr, "%s: Generated go file '%s' based on source '%s'\n", "golangAnnotations", twd.TargetFilename, twd.Src)
-
-	w, err := createFile(twd.TargetFilename)
-	if err != nil {
-		return err
+// TemplateText returns the user-provided override of a template, or its default text when there is none
+func TemplateText(overrides map[string]string, name string, defaultText string) string {
+	if text, found := overrides[name]; found {
+		return text
 	}
-	defer w.Close()
+	return defaultText
+}
 
-	t := template.New(twd.TemplateName).Funcs(twd.FuncMap)
-	t, err = t.Parse(twd.TemplateString)
-	if err != nil {
-		return err
+// TemplateFuncs combines the functions available to templates: the sprig-functions, overruled by the functions
+// of the generator, overruled by the functions that the user registered
+func TemplateFuncs(generatorFuncs map[string]interface{}, config generator.Config) map[string]interface{} {
+	funcs := map[string]interface{}{}
+	for name, f := range sprig.TxtFuncMap() {
+		funcs[name] = f
 	}
-
-	return t.Execute(w, twd.Data)
+	for name, f := range generatorFuncs {
+		funcs[name] = f
+	}
+	for name, f := range config.TemplateFuncs {
+		funcs[name] = f
+	}
+	return funcs
 }
 
-func createFile(filename string) (*os.File, error) {
-	err := os.MkdirAll(filepath.Dir(filename), 0777)
+// Generate renders the template of twd into an output-file
+func Generate(twd Info) (generator.OutputFile, error) {
+	t := template.New(twd.TemplateName).Funcs(TemplateFuncs(twd.FuncMap, twd.Config))
+	t, err := t.Parse(TemplateText(twd.Config.TemplateOverrides, twd.TemplateName, twd.TemplateString))
 	if err != nil {
-		return nil, err
+		return generator.OutputFile{}, err
 	}
-	w, err := os.Create(filename)
+
+	var buf bytes.Buffer
+	err = t.Execute(&buf, twd.Data)
 	if err != nil {
-		return nil, err
-	}
-	return w, nil
+		return generator.OutputFile{}, err
+	}
+	return generator.OutputFile{
+		Filename: twd.TargetFilename,
+		Src:      twd.Src,
+		TypeName: twd.TypeName,
+		Content:  buf.Bytes(),
+	}, nil
 }
@@ -1,11 +1,10 @@
 package generationUtil
 
 import (
-	"io/ioutil"
-	"os"
 	"testing"
 	"text/template"
 
+	"github.com/MarcGrol/golangAnnotations/generator"
 	"github.com/MarcGrol/golangAnnotations/model"
 	"github.com/stretchr/testify/assert"
 )
@@ -36,6 +35,19 @@ func TestGetPackageNameNoStructs(t *testing.T) {
 
 }
 
+func TestGetPackageNameForInterfaces(t *testing.T) {
+	i := []model.Interface{
+		{PackageName: "mypack"},
+		{PackageName: "mypack"},
+	}
+	packName, err := GetPackageNameForInterfaces(i)
+	assert.Equal(t, "mypack", packName)
+	assert.Nil(t, err)
+
+	_, err = GetPackageNameForInterfaces(append(i, model.Interface{PackageName: "otherPack"}))
+	assert.Error(t, err)
+}
+
 func TestDetermineTargetPathEmptyInput(t *testing.T) {
 	inputDir := ""
 	packageName := ""
@@ -67,7 +79,7 @@ func TestGenerateFileFromTemplate(t *testing.T) {
 		"CommentedPackageName": CommentedPackageName,
 	}
 
-	err := Generate(Info{
+	file, err := Generate(Info{
 		Src:            "testsrc",
 		TargetFilename: "test/doit.txt",
 		TemplateName:   "testtemplate",
@@ -77,11 +89,41 @@ func TestGenerateFileFromTemplate(t *testing.T) {
 	})
 	assert.Nil(t, err)
 
-	data, err := ioutil.ReadFile("test/doit.txt")
-	assert.NoError(t, err)
+	assert.Equal(t, "test/doit.txt", file.Filename)
+	assert.Equal(t, "testsrc", file.Src)
+	assert.Equal(t, "testit\n// commented testit", string(file.Content))
+}
 
-	assert.Equal(t, "testit\n// commented testit", string(data))
+func TestGenerateFileFromOverriddenTemplate(t *testing.T) {
+	file, err := Generate(Info{
+		Src:            "testsrc",
+		TargetFilename: "test/doit.txt",
+		TemplateName:   "testtemplate",
+		TemplateString: "{{.PackageName}}",
+		Data:           model.Struct{PackageName: "testit"},
+		Config: generator.Config{
+			TemplateOverrides: map[string]string{"testtemplate": "custom {{.PackageName}}", "other": "other"},
+		},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "custom testit", string(file.Content))
+}
 
-	os.Remove("./test/doit.txt")
-	os.Remove("./test")
+func TestGenerateFileWithTemplateFuncs(t *testing.T) {
+	file, err := Generate(Info{
+		TargetFilename: "test/doit.txt",
+		TemplateName:   "testtemplate",
+		TemplateString: "{{.PackageName | upper}} {{CommentedPackageName .}} {{Shout .PackageName}}",
+		FuncMap:        template.FuncMap{"CommentedPackageName": CommentedPackageName},
+		Data:           model.Struct{PackageName: "testit"},
+		Config: generator.Config{
+			TemplateFuncs: template.FuncMap{
+				"Shout":                func(s string) string { return s + "!" },
+				"CommentedPackageName": func(s model.Struct) string { return "/* " + s.PackageName + " */" },
+			},
+		},
+	})
+	assert.Nil(t, err)
+	// sprig is available by default and user-registered functions overrule those of the generator
+	assert.Equal(t, "TESTIT /* testit */ testit!", string(file.Content))
 }
@@ -0,0 +1,52 @@
+package generationUtil
+
+import (
+	"strings"
+
+	"github.com/MarcGrol/golangAnnotations/generator/example"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+// JSONField is a field of a struct in json, with the go-field it is marshalled from
+type JSONField struct {
+	JSONName string
+	Field    model.Field
+}
+
+// JSONFields returns the fields of a struct that are marshalled to json, with the fields of embedded structs in
+// their place. A struct that embeds itself, directly or through other structs, is expanded once.
+func JSONFields(s model.Struct, structs []model.Struct) []JSONField {
+	return jsonFields(s, structs, map[string]bool{})
+}
+
+func jsonFields(s model.Struct, structs []model.Struct, expanding map[string]bool) []JSONField {
+	expanding[s.PackageName+"."+s.Name] = true
+	defer delete(expanding, s.PackageName+"."+s.Name)
+
+	fields := []JSONField{}
+	for _, f := range s.Fields {
+		if f.IsEmbedded && strings.Split(f.GetTagMap()["json"], ",")[0] == "" {
+			if embedded, found := FindStruct(structs, f.TypeName); found {
+				if !expanding[embedded.PackageName+"."+embedded.Name] {
+					fields = append(fields, jsonFields(embedded, structs, expanding)...)
+				}
+				continue
+			}
+		}
+		if jsonName, ok := example.FieldName(f); ok {
+			fields = append(fields, JSONField{JSONName: jsonName, Field: f})
+		}
+	}
+	return fields
+}
+
+// FindStruct returns the struct of a type like Person, *Person or person.Person
+func FindStruct(structs []model.Struct, typeName string) (model.Struct, bool) {
+	packageName, name := model.Field{TypeName: typeName}.SplitTypeName()
+	for _, s := range structs {
+		if s.Name == name && (packageName == "" || s.PackageName == packageName) {
+			return s, true
+		}
+	}
+	return model.Struct{}, false
+}
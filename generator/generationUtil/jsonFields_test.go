@@ -0,0 +1,59 @@
+package generationUtil
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONFields(t *testing.T) {
+	structs := []model.Struct{
+		{
+			PackageName: "person",
+			Name:        "Person",
+			Fields: []model.Field{
+				{Name: "UID", TypeName: "string", Tag: "`json:\"uid\"`"},
+				{TypeName: "*Audit", IsEmbedded: true},
+				{Name: "Secret", TypeName: "string", Tag: "`json:\"-\"`"},
+				{Name: "age", TypeName: "int"},
+			},
+		},
+		{
+			PackageName: "person",
+			Name:        "Audit",
+			Fields:      []model.Field{{Name: "Created", TypeName: "time.Time"}},
+		},
+	}
+	fields := JSONFields(structs[0], structs)
+	assert.Equal(t, []JSONField{
+		{JSONName: "uid", Field: structs[0].Fields[0]},
+		{JSONName: "Created", Field: structs[1].Fields[0]},
+	}, fields)
+}
+
+func TestJSONFieldsOfStructThatEmbedsItself(t *testing.T) {
+	structs := []model.Struct{
+		{
+			PackageName: "tree",
+			Name:        "Node",
+			Fields: []model.Field{
+				{TypeName: "*Node", IsEmbedded: true},
+				{Name: "Name", TypeName: "string"},
+			},
+		},
+	}
+	fields := JSONFields(structs[0], structs)
+	assert.Equal(t, []JSONField{{JSONName: "Name", Field: structs[0].Fields[1]}}, fields)
+}
+
+func TestFindStruct(t *testing.T) {
+	structs := []model.Struct{{PackageName: "person", Name: "Person"}}
+
+	for _, typeName := range []string{"Person", "*Person", "person.Person", "*person.Person"} {
+		_, found := FindStruct(structs, typeName)
+		assert.True(t, found, typeName)
+	}
+	_, found := FindStruct(structs, "other.Person")
+	assert.False(t, found)
+}
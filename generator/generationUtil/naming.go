@@ -0,0 +1,82 @@
+package generationUtil
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// SnakeCase converts an identifier like "EtappeUID" into "etappe_uid"
+func SnakeCase(in string) string {
+	runes := []rune(in)
+	var sb strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				sb.WriteRune('_')
+			}
+			sb.WriteRune(unicode.ToLower(r))
+		} else {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// LowerCamelCase converts an identifier like "HTTPServerPort" into "httpServerPort"
+func LowerCamelCase(in string) string {
+	runes := []rune(in)
+	for i := 0; i < len(runes) && unicode.IsUpper(runes[i]); i++ {
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
+
+// KebabCase converts an identifier like "ResetPassword" into "reset-password"
+func KebabCase(in string) string {
+	return strings.Replace(SnakeCase(in), "_", "-", -1)
+}
+
+// Description returns the human readable text of doc- or comment-lines, leaving out the annotations
+func Description(lines []string) string {
+	parts := make([]string, 0, len(lines))
+	for _, line := range lines {
+		text := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "//"))
+		if text == "" || strings.HasPrefix(text, "@") || strings.HasPrefix(text, "go:") {
+			continue
+		}
+		parts = append(parts, text)
+	}
+	return strings.Join(parts, " ")
+}
+
+// GoStringLiteral returns a go-literal of s that keeps its lines readable: a raw string, unless s contains a backquote
+func GoStringLiteral(s string) string {
+	if strings.Contains(s, "`") {
+		return strconv.Quote(s)
+	}
+	return "`" + s + "`"
+}
+
+// DurationLiteral returns the go-expression of a duration in its largest whole unit, like 5 * time.Minute
+func DurationLiteral(d time.Duration) string {
+	for _, unit := range []struct {
+		duration time.Duration
+		name     string
+	}{
+		{time.Hour, "time.Hour"},
+		{time.Minute, "time.Minute"},
+		{time.Second, "time.Second"},
+		{time.Millisecond, "time.Millisecond"},
+	} {
+		if d%unit.duration == 0 {
+			return fmt.Sprintf("%d * %s", d/unit.duration, unit.name)
+		}
+	}
+	return fmt.Sprintf("%d", d)
+}
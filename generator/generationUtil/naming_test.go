@@ -0,0 +1,52 @@
+package generationUtil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnakeCase(t *testing.T) {
+	assert.Equal(t, "first_name", SnakeCase("FirstName"))
+	assert.Equal(t, "etappe_uid", SnakeCase("EtappeUID"))
+	assert.Equal(t, "uid", SnakeCase("UID"))
+	assert.Equal(t, "http_server_port", SnakeCase("HTTPServerPort"))
+	assert.Equal(t, "year", SnakeCase("year"))
+}
+
+func TestLowerCamelCase(t *testing.T) {
+	assert.Equal(t, "firstName", LowerCamelCase("FirstName"))
+	assert.Equal(t, "etappeUID", LowerCamelCase("EtappeUID"))
+	assert.Equal(t, "uid", LowerCamelCase("UID"))
+	assert.Equal(t, "httpServerPort", LowerCamelCase("HTTPServerPort"))
+	assert.Equal(t, "year", LowerCamelCase("year"))
+}
+
+func TestKebabCase(t *testing.T) {
+	assert.Equal(t, "reset-password", KebabCase("ResetPassword"))
+	assert.Equal(t, "person-uid", KebabCase("PersonUID"))
+}
+
+func TestDescription(t *testing.T) {
+	lines := []string{
+		"// Person is someone that",
+		"// @JsonStruct()",
+		"//   can place orders",
+		"//go:generate golangAnnotations -input-dir .",
+	}
+	assert.Equal(t, "Person is someone that can place orders", Description(lines))
+	assert.Equal(t, "", Description(nil))
+}
+
+func TestGoStringLiteral(t *testing.T) {
+	assert.Equal(t, "`{\"a\": 1}`", GoStringLiteral(`{"a": 1}`))
+	assert.Equal(t, "\"{\\\"a\\\": \\\"`\\\"}\"", GoStringLiteral("{\"a\": \"`\"}"))
+}
+
+func TestDurationLiteral(t *testing.T) {
+	assert.Equal(t, "2 * time.Hour", DurationLiteral(2*time.Hour))
+	assert.Equal(t, "90 * time.Second", DurationLiteral(90*time.Second))
+	assert.Equal(t, "1500 * time.Millisecond", DurationLiteral(1500*time.Millisecond))
+	assert.Equal(t, "10", DurationLiteral(10))
+}
@@ -0,0 +1,202 @@
+package golden
+
+import (
+	"context"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/builtin"
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/MarcGrol/golangAnnotations/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	fixtureDir    = "testdata/fixture"
+	goldenDir     = "testdata/golden"
+	goldenSuffix  = ".golden"
+	fixturePkgDir = "fixture"
+)
+
+// fixturePackages are the package-dirs of the fixture, relative to the fixture-dir
+var fixturePackages = []string{".", "teams"}
+
+var update = flag.Bool("update", false, "rewrite the golden files with the current output of the generators")
+
+// TestGoldenFiles renders every generator against the fixture packages and compares the output with the
+// committed golden files. Run "go test ./generator/golden -update" to accept intended changes. A generator that
+// renders nothing for the fixture fails: add the annotations that it needs to the fixture.
+func TestGoldenFiles(t *testing.T) {
+	for _, g := range builtin.NewRegistry().All() {
+		name := g.Name()
+		t.Run(name, func(t *testing.T) {
+			actual := render(t, g, false)
+			if len(actual) == 0 {
+				t.Fatalf("Generator %s renders nothing for the fixture: add its annotations to %s", name, fixtureDir)
+			}
+			expectedDir := filepath.Join(goldenDir, name)
+
+			if *update {
+				writeGoldenFiles(t, expectedDir, actual)
+				return
+			}
+
+			expected := readTree(t, expectedDir)
+			for filename, content := range actual {
+				golden, found := expected[filename+goldenSuffix]
+				if !assert.True(t, found, "Unexpected generated file %s: run with -update to accept", filename) {
+					continue
+				}
+				assert.Equal(t, golden, content, "Generated file %s differs from golden file: run with -update to accept", filename)
+			}
+			for goldenFilename := range expected {
+				filename := strings.TrimSuffix(goldenFilename, goldenSuffix)
+				_, found := actual[filename]
+				assert.True(t, found, "Golden file %s is no longer generated: run with -update to accept", goldenFilename)
+			}
+		})
+	}
+}
+
+// TestGeneratedFilesAreReproducible verifies that the output of every generator is byte-identical across runs,
+// and does not depend on the location of the sources or on the input-dir being given as an absolute path
+func TestGeneratedFilesAreReproducible(t *testing.T) {
+	for _, g := range builtin.NewRegistry().All() {
+		t.Run(g.Name(), func(t *testing.T) {
+			first := render(t, g, false)
+			for run := 0; run < 3; run++ {
+				assert.Equal(t, first, render(t, g, false), "Output differs between runs")
+			}
+			assert.Equal(t, first, render(t, g, true), "Output differs for an absolute input-dir")
+		})
+	}
+}
+
+// TestGeneratedFilesDoNotCollide renders all generators together, like a single run of golangAnnotations does, and
+// verifies that no file or symbol of a fixture package is generated twice
+func TestGeneratedFilesDoNotCollide(t *testing.T) {
+	inputDir := copyFixture(t)
+	tasks := []generator.Task{}
+	for _, pkg := range fixturePackages {
+		packageDir := filepath.Join(inputDir, pkg)
+		parsedSources := parse(t, packageDir)
+		for _, g := range builtin.NewRegistry().All() {
+			tasks = append(tasks, generator.Task{Generator: g, ParsedSources: parsedSources, Config: generator.Config{InputDir: packageDir}})
+		}
+	}
+	results, err := generator.RenderAll(context.Background(), tasks, 1)
+	if err != nil {
+		t.Fatalf("Error rendering for fixture: %s", err)
+	}
+	assert.NoError(t, generator.DetectCollisions(tasks, results))
+}
+
+// render runs a single generator on a fresh copy of the fixture and returns all files that it created or changed
+func render(t *testing.T, g generator.Generator, absoluteInputDir bool) map[string]string {
+	inputDir := copyFixture(t)
+	fixture := readTree(t, inputDir)
+
+	workDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Error getting working dir: %s", err)
+	}
+	defer os.Chdir(workDir)
+
+	for _, pkg := range fixturePackages {
+		// generate from within the package, just like "//go:generate golangAnnotations -input-dir ." does
+		packageDir := filepath.Join(inputDir, pkg)
+		err = os.Chdir(packageDir)
+		if err != nil {
+			t.Fatalf("Error changing to %s: %s", packageDir, err)
+		}
+
+		inputDirArg := "."
+		if absoluteInputDir {
+			inputDirArg = packageDir
+		}
+		err = generator.Generate(g, parse(t, inputDirArg), generator.Config{InputDir: inputDirArg})
+		if err != nil {
+			t.Fatalf("Error generating for fixture package %s: %s", pkg, err)
+		}
+	}
+
+	output := readTree(t, inputDir)
+	for filename, content := range output {
+		if original, found := fixture[filename]; found && original == content {
+			delete(output, filename)
+		}
+	}
+	return output
+}
+
+// copyFixture writes a fresh copy of the fixture to a temporary dir and returns that dir
+func copyFixture(t *testing.T) string {
+	inputDir := filepath.Join(t.TempDir(), fixturePkgDir)
+	for filename, content := range readTree(t, fixtureDir) {
+		writeFile(t, filepath.Join(inputDir, filename), content)
+	}
+	return inputDir
+}
+
+func parse(t *testing.T, packageDir string) model.ParsedSources {
+	parsedSources, err := parser.New().ParseSourceDir(packageDir, "^.*.go$", "^"+generator.GenfilePrefix+".*.go$")
+	if err != nil {
+		t.Fatalf("Error parsing fixture package %s: %s", packageDir, err)
+	}
+	return parsedSources
+}
+
+func writeGoldenFiles(t *testing.T, dir string, files map[string]string) {
+	err := os.RemoveAll(dir)
+	if err != nil {
+		t.Fatalf("Error removing golden files in %s: %s", dir, err)
+	}
+	for filename, content := range files {
+		writeFile(t, filepath.Join(dir, filename+goldenSuffix), content)
+	}
+}
+
+// readTree returns the content of all files below dir on their slash-separated path relative to dir
+func readTree(t *testing.T, dir string) map[string]string {
+	files := map[string]string{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == dir {
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		relative, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(relative)] = string(content)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Error reading files in %s: %s", dir, err)
+	}
+	return files
+}
+
+func writeFile(t *testing.T, filename string, content string) {
+	err := os.MkdirAll(filepath.Dir(filename), 0777)
+	if err == nil {
+		err = ioutil.WriteFile(filename, []byte(content), 0666)
+	}
+	if err != nil {
+		t.Fatalf("Error writing %s: %s", filename, err)
+	}
+}
@@ -0,0 +1,173 @@
+package fixture
+
+import (
+	"context"
+	"time"
+)
+
+// @JsonEnum()
+type ColorType int
+
+const (
+	ColorTypeRed ColorType = iota
+	ColorTypeGreen
+	ColorTypeBlue
+)
+
+// @MessageCatalog( languages = "nl" )
+type ErrorCode int
+
+const (
+	// @Message( text = "Person {{.UID}} was not found" )
+	ErrorCodePersonNotFound ErrorCode = iota + 1
+	// @Message( text = "Invalid input", description = "Validation of a request failed" )
+	ErrorCodeInvalidInput
+)
+
+// @JsonStruct()
+// @TestFactory()
+// @Entity( table = "persons" )
+// @Tags( keys = "db" )
+type Person struct {
+	// @Id( auto = "true" )
+	ID int `json:"id"`
+	// @Validate( format = "email" )
+	// @Column( filter = "true" )
+	// @Example( value = "john@example.com" )
+	// @Audited()
+	// @PII( category = "contact" )
+	Email string `json:"email"`
+	// @Example( value = "John Doe" )
+	Name      string    `json:"name"`
+	Color     ColorType `json:"color"`
+	Tags      []string  `json:"tags"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// @Event( aggregate = "Person" )
+// @Warehouse()
+// @ZeroCopy()
+// @Notify( channel = "sms", to = "{{.PersonUID}}", body = "Welcome {{.Name}}" )
+// @WebhookPublishable( name = "person.created" )
+type PersonCreated struct {
+	PersonUID string `json:"personUID"`
+	Name      string `json:"name"`
+}
+
+// @Repository( aggregate = "Person", methods = "find,exists" )
+type PersonRepository struct {
+}
+
+// @Searchable( index = "persons", aggregate = "Person" )
+type PersonDocument struct {
+	// @SearchField( id = "true" )
+	PersonUID string `json:"personUID"`
+	// @SearchField( type = "text" )
+	Name string `json:"name"`
+}
+
+// @EventService( self = "fixture" )
+type PersonEventService struct {
+}
+
+// @EventOperation( topic = "person" )
+func (es *PersonEventService) onPersonCreated(c context.Context, event PersonCreated) error {
+	return nil
+}
+
+// @Application()
+// @Kubernetes( image = "registry.example.com/fixture:1.0", cpu = "100m", memory = "128Mi" )
+type Application struct {
+	// @Inject()
+	Service *PersonService
+}
+
+// @Provides()
+func NewPersonService() *PersonService {
+	return &PersonService{}
+}
+
+// @Config( prefix = "fixture" )
+type Settings struct {
+	// @ConfigField( default = "8080", usage = "port to listen on" )
+	HTTPPort int
+	// @ConfigField( env = "DATABASE_URL", required = "true" )
+	DatabaseURL string
+}
+
+// @RestService( path = "/api" )
+// @Pact( consumer = "web-shop" )
+// @OpenAPI( validate = "true" )
+// @Stub( latency = "100ms" )
+// @Recording()
+// @Shadow()
+// @Retrofit( package = "com.example.fixture" )
+// @PythonClient()
+// @CSharpClient( namespace = "Fixture" )
+// @DartClient()
+type PersonService struct {
+}
+
+// @RestOperation( method = "GET", path = "/person/{uid}", format = "JSON" )
+func (ps *PersonService) getPerson(c context.Context, uid int) (*Person, error) {
+	return &Person{ID: uid}, nil
+}
+
+// @RestOperation( method = "POST", path = "/person", format = "JSON" )
+// @FeatureFlag( name = "person-creation" )
+// @Audited( action = "person.create" )
+func (ps *PersonService) createPerson(c context.Context, person Person) (*Person, error) {
+	return &person, nil
+}
+
+// @RestOperation( method = "GET", path = "/person.csv", format = "CSV" )
+// @Export( format = "csv" )
+func (ps *PersonService) exportPersons(c context.Context) ([]Person, error) {
+	return []Person{}, nil
+}
+
+// @StateMachine( aggregate = "Membership" )
+type MembershipStatus int
+
+const (
+	MembershipStatusRequested MembershipStatus = iota
+	MembershipStatusActive
+	MembershipStatusEnded
+)
+
+type Membership struct {
+	PersonUID string
+	TeamUID   string
+	Status    MembershipStatus
+}
+
+// @Transition( from = "MembershipStatusRequested", to = "MembershipStatusActive" )
+func (m *Membership) Accept() {
+}
+
+// @Transition( from = "MembershipStatusRequested, MembershipStatusActive", to = "MembershipStatusEnded" )
+func (m *Membership) End() {
+}
+
+// @Scheduled( cron = "0 3 * * *" )
+func purgeEndedMemberships(c context.Context) error {
+	return nil
+}
+
+// @Task( attempts = "3" )
+func sendWelcome(c context.Context, personUID string, teamUID string) error {
+	return nil
+}
+
+// @CliCommand( name = "persons" )
+type PersonAdmin interface {
+	// @CliCommand( short = "Removes a person" )
+	RemovePerson(c context.Context, uid string) error
+}
+
+type PersonStore interface {
+	// @Cached( ttl = "5m", key = "person:{uid}" )
+	GetPerson(c context.Context, uid string) (*Person, error)
+	// @CacheEvict( keys = "person:{uid}" )
+	RemovePerson(c context.Context, uid string) error
+}
@@ -0,0 +1,33 @@
+// Package teams is the second package of the fixture: the rest-generator renders test-helpers with a TestMain for
+// every rest-service, so a package of the fixture has room for only one rest-service.
+package teams
+
+import (
+	"context"
+)
+
+// Team is a group of persons
+type Team struct {
+	UID  string `json:"uid"`
+	Name string `json:"name"`
+}
+
+// @RestService( path = "/api" )
+// @TerraformResource( provider = "fixture", name = "team", create = "createTeam", read = "getTeam", delete = "deleteTeam" )
+type TeamService struct {
+}
+
+// @RestOperation( method = "POST", path = "/team", format = "JSON" )
+func (ts *TeamService) createTeam(c context.Context, team Team) (*Team, error) {
+	return &team, nil
+}
+
+// @RestOperation( method = "GET", path = "/team/{uid}", format = "JSON" )
+func (ts *TeamService) getTeam(c context.Context, uid string) (*Team, error) {
+	return &Team{UID: uid}, nil
+}
+
+// @RestOperation( method = "DELETE", path = "/team/{uid}", format = "no_content" )
+func (ts *TeamService) deleteTeam(c context.Context, uid string) error {
+	return nil
+}
@@ -0,0 +1,110 @@
+package generator
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultMarker is the first line of every generated file, in the comment-syntax of the file
+const DefaultMarker = "Generated automatically by golangAnnotations: do not edit manually"
+
+// Header replaces the default marker-line at the top of generated files, for instance to add a license
+type Header struct {
+	// License is put above the marker, line by line
+	License string
+	// Marker replaces DefaultMarker, e.g. "Code generated by golangAnnotations. DO NOT EDIT."
+	Marker string
+	// Version of golangAnnotations that generated the file: omitted when empty
+	Version string
+	// WithSource adds the generator and the source that the file is based on
+	WithSource bool
+}
+
+func (h Header) isDefault() bool {
+	return h.License == "" && h.Marker == "" && h.Version == "" && !h.WithSource
+}
+
+type commentSyntax struct {
+	prefix string
+	suffix string
+}
+
+// commentSyntaxes are the kinds of files that start with a marker-line: json and html cannot have one
+var commentSyntaxes = map[string]commentSyntax{
+	".go":   {prefix: "//"},
+	".fbs":  {prefix: "//"},
+	".cs":   {prefix: "//"},
+	".dart": {prefix: "//"},
+	".java": {prefix: "//"},
+	".kt":   {prefix: "//"},
+	".py":   {prefix: "#"},
+	".sql":  {prefix: "--"},
+	".yaml": {prefix: "#"},
+	".md":   {prefix: "<!--", suffix: "-->"},
+	".dot":  {prefix: "//"},
+}
+
+// ApplyHeader replaces the default marker-line of the generated files by the header of config. Files that do not
+// start with the default marker, like the output of customized templates, are left alone.
+func ApplyHeader(files []OutputFile, generatorName string, config Config) []OutputFile {
+	if config.Header.isDefault() {
+		return files
+	}
+	for idx, f := range files {
+		syntax, found := commentSyntaxes[filepath.Ext(f.Filename)]
+		if f.Fixed || !found {
+			continue
+		}
+		markerLine := []byte(syntax.comment(DefaultMarker) + "\n")
+		if !bytes.HasPrefix(f.Content, markerLine) {
+			continue
+		}
+		header := syntax.commentBlock(config.Header.lines(f, generatorName))
+		files[idx].Content = append([]byte(header), f.Content[len(markerLine):]...)
+	}
+	return files
+}
+
+func (h Header) lines(f OutputFile, generatorName string) []string {
+	lines := []string{}
+	if h.License != "" {
+		lines = append(lines, strings.Split(strings.TrimRight(h.License, "\n"), "\n")...)
+		lines = append(lines, "")
+	}
+	marker := h.Marker
+	if marker == "" {
+		marker = DefaultMarker
+	}
+	lines = append(lines, marker)
+	if h.Version != "" {
+		lines = append(lines, versionLinePrefix+h.Version)
+	}
+	if h.WithSource {
+		lines = append(lines, "Generated by generator "+generatorName+" from "+f.Src)
+	}
+	return lines
+}
+
+func (s commentSyntax) comment(line string) string {
+	if s.suffix == "" {
+		return s.prefix + " " + line
+	}
+	return s.prefix + " " + line + " " + s.suffix
+}
+
+func (s commentSyntax) commentBlock(lines []string) string {
+	var buf strings.Builder
+	for _, line := range lines {
+		if line == "" {
+			// keep a block of line-comments together
+			if s.suffix == "" {
+				buf.WriteString(s.prefix)
+			}
+			buf.WriteString("\n")
+			continue
+		}
+		buf.WriteString(s.comment(line) + "\n")
+	}
+	return buf.String()
+}
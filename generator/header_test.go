@@ -0,0 +1,51 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyHeaderDefault(t *testing.T) {
+	files := ApplyHeader([]OutputFile{{Filename: "gen_x.go", Content: []byte("// " + DefaultMarker + "\n\npackage x\n")}}, "rest", Config{})
+	assert.Equal(t, "// "+DefaultMarker+"\n\npackage x\n", string(files[0].Content))
+}
+
+func TestApplyHeader(t *testing.T) {
+	config := Config{Header: Header{
+		License:    "Copyright Example\nAll rights reserved\n",
+		Marker:     "Code generated by golangAnnotations. DO NOT EDIT.",
+		Version:    "1.2",
+		WithSource: true,
+	}}
+	files := ApplyHeader([]OutputFile{
+		{Filename: "gen_x.go", Src: "x.Service", Content: []byte("// " + DefaultMarker + "\n\npackage x\n")},
+		{Filename: "gen_x.md", Src: "x", Content: []byte("<!-- " + DefaultMarker + " -->\n# x\n")},
+		{Filename: "gen_x.json", Content: []byte("{}")},
+		{Filename: "000001_x.up.sql", Fixed: true, Content: []byte("-- " + DefaultMarker + "\n")},
+		{Filename: "gen_custom.go", Content: []byte("// custom\n\npackage x\n")},
+		{Filename: "gen_kubernetesApp.yaml", Src: "x.App", Content: []byte("# " + DefaultMarker + "\n\nkind: Service\n")},
+	}, "rest", config)
+
+	assert.Equal(t, `// Copyright Example
+// All rights reserved
+//
+// Code generated by golangAnnotations. DO NOT EDIT.
+// golangAnnotations version: 1.2
+// Generated by generator rest from x.Service
+
+package x
+`, string(files[0].Content))
+	assert.Equal(t, `<!-- Copyright Example -->
+<!-- All rights reserved -->
+
+<!-- Code generated by golangAnnotations. DO NOT EDIT. -->
+<!-- golangAnnotations version: 1.2 -->
+<!-- Generated by generator rest from x -->
+# x
+`, string(files[1].Content))
+	assert.Equal(t, "{}", string(files[2].Content))
+	assert.Equal(t, "-- "+DefaultMarker+"\n", string(files[3].Content))
+	assert.Equal(t, "// custom\n\npackage x\n", string(files[4].Content))
+	assert.Contains(t, string(files[5].Content), "# Code generated by golangAnnotations. DO NOT EDIT.\n# golangAnnotations version: 1.2\n")
+}
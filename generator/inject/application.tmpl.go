@@ -0,0 +1,103 @@
+package inject
+
+const applicationTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+
+package {{.PackageName}}
+
+import (
+	"fmt"
+{{- if .Health}}
+
+	"github.com/gorilla/mux"
+{{- end}}
+)
+
+{{ $app := .Application.Name -}}
+
+// New{{$app}} assembles {{$app}} by calling the @Provides-functions in dependency-order
+func New{{$app}}({{range $idx, $input := .Inputs}}{{if $idx}}, {{end}}{{$input.Name}} {{$input.TypeName}}{{end}}) (*{{$app}}, error) {
+{{- range .Steps }}
+{{- if .ReturnsError }}
+	{{.Var.Name}}, err := {{.Provider}}({{range $idx, $arg := .Args}}{{if $idx}}, {{end}}{{$arg}}{{end}})
+	if err != nil {
+		return nil, fmt.Errorf("Error providing {{.Var.TypeName}}: %s", err)
+	}
+{{- else }}
+	{{.Var.Name}} := {{.Provider}}({{range $idx, $arg := .Args}}{{if $idx}}, {{end}}{{$arg}}{{end}})
+{{- end }}
+{{- end }}
+
+	app := &{{$app}}{
+{{- range .Fields }}
+		{{.Name}}: {{.Var}},
+{{- end }}
+	}
+	return app, nil
+}
+{{- if .Health}}
+
+// {{$app}}HealthCheck checks a component of {{$app}}: readiness-checks, like of the connection to a database, only
+// run on /readyz, other checks run on /healthz and /readyz
+type {{$app}}HealthCheck struct {
+	Name      string
+	Readiness bool
+	Check     func(c context.Context) error
+}
+
+// {{$app}}HealthChecks is the registry of the checks of the /healthz and /readyz endpoints of {{$app}}
+type {{$app}}HealthChecks struct {
+	mutex  sync.RWMutex
+	checks []{{$app}}HealthCheck
+}
+
+// HealthChecks returns a registry with the @HealthCheck-functions{{if .HealthChecks}}, that are called with the injected fields of app{{end}}
+func (app *{{$app}}) HealthChecks() *{{$app}}HealthChecks {
+	checks := &{{$app}}HealthChecks{}
+{{- range .HealthChecks }}
+	checks.Register("{{.Name}}", {{.Readiness}}, func(c context.Context) error {
+		return {{.Function}}({{range $idx, $arg := .Args}}{{if $idx}}, {{end}}{{$arg}}{{end}})
+	})
+{{- end }}
+	return checks
+}
+
+// Register adds a check, like of a component that is generated or constructed at runtime
+func (h *{{$app}}HealthChecks) Register(name string, readiness bool, check func(c context.Context) error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.checks = append(h.checks, {{$app}}HealthCheck{Name: name, Readiness: readiness, Check: check})
+}
+
+// HTTPHandlerWithRouter registers /healthz and /readyz in existing router
+func (h *{{$app}}HealthChecks) HTTPHandlerWithRouter(router *mux.Router) *mux.Router {
+	router.HandleFunc("/healthz", h.handle(false)).Methods("GET")
+	router.HandleFunc("/readyz", h.handle(true)).Methods("GET")
+	return router
+}
+
+// handle runs the checks and responds with the result of each of them: 503 when any of them fails
+func (h *{{$app}}HealthChecks) handle(readiness bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		h.mutex.RLock()
+		checks := append([]{{$app}}HealthCheck{}, h.checks...)
+		h.mutex.RUnlock()
+
+		status := http.StatusOK
+		results := map[string]string{}
+		for _, check := range checks {
+			if check.Readiness && !readiness {
+				continue
+			}
+			results[check.Name] = "ok"
+			if err := check.Check(r.Context()); err != nil {
+				status = http.StatusServiceUnavailable
+				results[check.Name] = err.Error()
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(results)
+	}
+}
+{{- end}}
+`
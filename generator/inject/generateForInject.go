@@ -0,0 +1,365 @@
+package inject
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/generator/inject/injectAnnotation"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+type applicationContext struct {
+	PackageName  string
+	Application  model.Struct
+	Inputs       []injectVar
+	Steps        []injectStep
+	Fields       []injectField
+	Health       bool
+	HealthChecks []healthCheck
+}
+
+type injectVar struct {
+	Name     string
+	TypeName string
+}
+
+type injectStep struct {
+	Var          injectVar
+	Provider     string
+	Args         []string
+	ReturnsError bool
+}
+
+type injectField struct {
+	Name string
+	Var  string
+}
+
+// healthCheck is a @HealthCheck-function that the application calls with the context of the request and its
+// injected fields
+type healthCheck struct {
+	Name      string
+	Readiness bool
+	Function  string
+	Args      []string
+}
+
+type provider struct {
+	operation    model.Operation
+	provides     string
+	returnsError bool
+}
+
+type Generator struct {
+}
+
+func NewGenerator() generator.Generator {
+	return &Generator{}
+}
+
+func (eg *Generator) Name() string {
+	return "inject"
+}
+
+func (eg *Generator) GetTemplates() []generator.Template {
+	return []generator.Template{
+		{Name: "application", APIVersion: 1, Text: applicationTemplate},
+	}
+}
+
+func (eg *Generator) GetAnnotations() []annotation.AnnotationDescriptor {
+	return injectAnnotation.Get()
+}
+
+func (eg *Generator) Generate(parsedSource model.ParsedSources, config generator.Config) ([]generator.OutputFile, error) {
+	return generate(config, parsedSource.Structs, parsedSource.Operations)
+}
+
+func generate(config generator.Config, structs []model.Struct, operations []model.Operation) ([]generator.OutputFile, error) {
+	packageName, err := generationUtil.GetPackageNameForStructs(structs)
+	if packageName == "" || err != nil {
+		return nil, err
+	}
+	targetDir, err := generationUtil.DetermineTargetPath(config.InputDir, packageName)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []generator.OutputFile{}
+
+	providers, err := getProviders(operations)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range structs {
+		if !IsApplication(s) {
+			continue
+		}
+		ctx, err := resolveApplication(packageName, s, providers)
+		if err != nil {
+			return nil, err
+		}
+		ctx.HealthChecks, err = getHealthChecks(s, operations)
+		if err != nil {
+			return nil, err
+		}
+		ctx.Health = HasHealth(s) || len(ctx.HealthChecks) > 0
+
+		file, err := generationUtil.Generate(generationUtil.Info{
+			Src:            fmt.Sprintf("%s.%s", s.PackageName, s.Name),
+			TypeName:       s.Name,
+			TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/application%s.go", targetDir, s.Name)),
+			TemplateName:   "application",
+			TemplateString: applicationTemplate,
+			Config:         config,
+			FuncMap:        customTemplateFuncs,
+			Data:           ctx,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Error generating initializer for application %s:%w", s.Name, err)
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+var customTemplateFuncs = template.FuncMap{}
+
+func IsApplication(s model.Struct) bool {
+	annotations := annotation.NewRegistry(injectAnnotation.Get())
+	_, ok := annotations.ResolveAnnotationByName(s.DocLines, injectAnnotation.TypeApplication)
+	return ok
+}
+
+// HasHealth tells if the application has health-endpoints without @HealthCheck-functions, for checks that are
+// registered at runtime, like by generated components
+func HasHealth(s model.Struct) bool {
+	annotations := annotation.NewRegistry(injectAnnotation.Get())
+	ann, ok := annotations.ResolveAnnotationByName(s.DocLines, injectAnnotation.TypeApplication)
+	return ok && ann.Attributes[injectAnnotation.ParamHealth] == "true"
+}
+
+// HasHealthEndpoints tells if the generated initializer of application s gives it /healthz and /readyz endpoints
+func HasHealthEndpoints(s model.Struct, operations []model.Operation) bool {
+	if HasHealth(s) {
+		return true
+	}
+	checks, err := getHealthChecks(s, operations)
+	return err == nil && len(checks) > 0
+}
+
+func IsInjected(f model.Field) bool {
+	annotations := annotation.NewRegistry(injectAnnotation.Get())
+	_, ok := annotations.ResolveAnnotationByName(f.DocLines, injectAnnotation.TypeInject)
+	return ok
+}
+
+func IsProvider(o model.Operation) bool {
+	annotations := annotation.NewRegistry(injectAnnotation.Get())
+	_, ok := annotations.ResolveAnnotationByName(o.DocLines, injectAnnotation.TypeProvides)
+	return ok
+}
+
+// getProviders indexes the @Provides functions on the type they provide; a provider returns
+// the provided value optionally followed by an error
+func getProviders(operations []model.Operation) (map[string]provider, error) {
+	providers := map[string]provider{}
+	for _, o := range operations {
+		if !IsProvider(o) {
+			continue
+		}
+		if o.RelatedStruct != nil {
+			return nil, generator.OperationError(o, "", injectAnnotation.TypeProvides, "Provider %s must be a function, not a method", o.Name)
+		}
+		returnsError := len(o.OutputArgs) == 2 && o.OutputArgs[1].TypeName == "error"
+		if len(o.OutputArgs) != 1 && !returnsError {
+			return nil, generator.OperationError(o, "", injectAnnotation.TypeProvides, "Provider %s must return a value and optionally an error", o.Name)
+		}
+		provides := o.OutputArgs[0].TypeName
+		if other, exists := providers[provides]; exists {
+			return nil, generator.OperationError(o, "", injectAnnotation.TypeProvides, "Type %s is provided by both %s and %s", provides, other.operation.Name, o.Name)
+		}
+		providers[provides] = provider{
+			operation:    o,
+			provides:     provides,
+			returnsError: returnsError,
+		}
+	}
+	return providers, nil
+}
+
+// getHealthChecks returns the @HealthCheck-functions of the package as checks of application s: a check takes a
+// context.Context, followed by injected fields of s by type, and returns an error
+func getHealthChecks(s model.Struct, operations []model.Operation) ([]healthCheck, error) {
+	injected := map[string]string{}
+	for _, f := range s.Fields {
+		if IsInjected(f) {
+			injected[f.TypeName] = f.Name
+		}
+	}
+	annotations := annotation.NewRegistry(injectAnnotation.Get())
+	checks := []healthCheck{}
+	names := map[string]bool{}
+	for _, o := range operations {
+		ann, ok := annotations.ResolveAnnotationByName(o.DocLines, injectAnnotation.TypeHealthCheck)
+		if !ok {
+			continue
+		}
+		if o.RelatedStruct != nil {
+			return nil, generator.OperationError(o, "", injectAnnotation.TypeHealthCheck, "Health check %s must be a function, not a method", o.Name)
+		}
+		if len(o.InputArgs) == 0 || o.InputArgs[0].TypeName != "context.Context" || len(o.OutputArgs) != 1 || o.OutputArgs[0].TypeName != "error" {
+			return nil, generator.OperationError(o, "", injectAnnotation.TypeHealthCheck, "Health check %s must take a context.Context and return an error", o.Name)
+		}
+		check := healthCheck{
+			Name:      ann.Attributes[injectAnnotation.ParamName],
+			Readiness: ann.Attributes[injectAnnotation.ParamReadiness] == "true",
+			Function:  o.Name,
+			Args:      []string{"c"},
+		}
+		if check.Name == "" {
+			check.Name = o.Name
+		}
+		if names[check.Name] {
+			return nil, generator.OperationError(o, "", injectAnnotation.TypeHealthCheck, "Health check %s is not the only one named %s", o.Name, check.Name)
+		}
+		names[check.Name] = true
+		for _, arg := range o.InputArgs[1:] {
+			field, found := injected[arg.TypeName]
+			if !found {
+				return nil, generator.OperationError(o, "", injectAnnotation.TypeHealthCheck, "Argument %s of health check %s is of type %s, which no @Inject-field of %s has", arg.Name, o.Name, arg.TypeName, s.Name)
+			}
+			check.Args = append(check.Args, "app."+field)
+		}
+		checks = append(checks, check)
+	}
+	return checks, nil
+}
+
+type resolver struct {
+	providers map[string]provider
+	vars      map[string]string
+	names     map[string]bool
+	inputs    []injectVar
+	steps     []injectStep
+}
+
+// resolveApplication orders the providers so that every dependency is constructed before it is used:
+// types without provider become parameters of the generated initializer
+func resolveApplication(packageName string, s model.Struct, providers map[string]provider) (applicationContext, error) {
+	r := &resolver{
+		providers: providers,
+		vars:      map[string]string{},
+		names:     map[string]bool{},
+	}
+
+	fields := []injectField{}
+	for _, f := range s.Fields {
+		if !IsInjected(f) {
+			continue
+		}
+		v, err := r.resolve(f.TypeName, []string{s.Name + "." + f.Name})
+		if err != nil {
+			return applicationContext{}, err
+		}
+		fields = append(fields, injectField{Name: f.Name, Var: v})
+	}
+	if len(fields) == 0 {
+		return applicationContext{}, generator.StructError(s, injectAnnotation.TypeApplication, "Application %s has no fields annotated with @Inject", s.Name)
+	}
+
+	return applicationContext{
+		PackageName: packageName,
+		Application: s,
+		Inputs:      r.inputs,
+		Steps:       r.steps,
+		Fields:      fields,
+	}, nil
+}
+
+func (r *resolver) resolve(typeName string, path []string) (string, error) {
+	if v, found := r.vars[typeName]; found {
+		return v, nil
+	}
+
+	p, found := r.providers[typeName]
+	if !found {
+		v := r.newVar(typeName)
+		r.inputs = append(r.inputs, injectVar{Name: v, TypeName: typeName})
+		return v, nil
+	}
+
+	for _, step := range path {
+		if step == p.operation.Name {
+			return "", generator.OperationError(p.operation, "", injectAnnotation.TypeProvides, "Dependency cycle: %s -> %s", strings.Join(path, " -> "), p.operation.Name)
+		}
+	}
+
+	args := []string{}
+	for _, arg := range p.operation.InputArgs {
+		v, err := r.resolve(arg.TypeName, append(path, p.operation.Name))
+		if err != nil {
+			return "", err
+		}
+		if strings.HasPrefix(arg.TypeName, "...") {
+			v += "..."
+		}
+		args = append(args, v)
+	}
+
+	v := r.newVar(typeName)
+	r.steps = append(r.steps, injectStep{
+		Var:          injectVar{Name: v, TypeName: typeName},
+		Provider:     p.operation.Name,
+		Args:         args,
+		ReturnsError: p.returnsError,
+	})
+	return v, nil
+}
+
+func (r *resolver) newVar(typeName string) string {
+	name := varName(typeName)
+	candidate := name
+	for idx := 2; r.names[candidate]; idx++ {
+		candidate = fmt.Sprintf("%s%d", name, idx)
+	}
+	r.names[candidate] = true
+	r.vars[typeName] = candidate
+	return candidate
+}
+
+// varName derives a variable-name from a type: "*mux.Router" becomes "router"
+func varName(typeName string) string {
+	if typeName == "context.Context" {
+		return "c"
+	}
+	name := strings.TrimLeft(typeName, "*[].")
+	packageName := ""
+	if idx := strings.LastIndex(name, "."); idx >= 0 {
+		packageName = name[:idx]
+		name = name[idx+1:]
+	}
+	if name == "" {
+		return "dep"
+	}
+	name = generationUtil.LowerCamelCase(name)
+	if name == packageName || isReserved(name) {
+		name += "Dep"
+	}
+	return name
+}
+
+func isReserved(name string) bool {
+	switch name {
+	case "break", "case", "chan", "const", "continue", "default", "defer", "else", "fallthrough", "for", "func",
+		"go", "goto", "if", "import", "interface", "map", "package", "range", "return", "select", "struct",
+		"switch", "type", "var", "err", "app":
+		return true
+	}
+	return false
+}
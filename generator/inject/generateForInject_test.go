@@ -0,0 +1,163 @@
+package inject
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func cleanup() {
+	os.Remove(generationUtil.Prefixed("./testData/applicationApplication.go"))
+}
+
+func application() []model.Struct {
+	return []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @Application()`},
+			Name:        "Application",
+			Fields: []model.Field{
+				{Name: "Router", TypeName: "*mux.Router", DocLines: []string{`// @Inject()`}},
+				{Name: "Service", TypeName: "*PersonService", DocLines: []string{`// @Inject()`}},
+				{Name: "Unrelated", TypeName: "string"},
+			},
+		},
+	}
+}
+
+func TestGenerateForInject(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	operations := []model.Operation{
+		{
+			DocLines:   []string{`// @Provides()`},
+			Name:       "NewRouter",
+			InputArgs:  []model.Field{{Name: "service", TypeName: "*PersonService"}},
+			OutputArgs: []model.Field{{TypeName: "*mux.Router"}},
+		},
+		{
+			DocLines:   []string{`// @Provides()`},
+			Name:       "NewPersonService",
+			InputArgs:  []model.Field{{Name: "repo", TypeName: "PersonRepository"}, {Name: "b", TypeName: "bus.Bus"}},
+			OutputArgs: []model.Field{{TypeName: "*PersonService"}},
+		},
+		{
+			DocLines:   []string{`// @Provides()`},
+			Name:       "NewPersonRepository",
+			InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "db", TypeName: "*sql.DB"}},
+			OutputArgs: []model.Field{{TypeName: "PersonRepository"}, {TypeName: "error"}},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: application(), Operations: operations}, generator.Config{InputDir: "testData"})
+	assert.Nil(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/applicationApplication.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `func NewApplication(c context.Context, db *sql.DB, busDep bus.Bus) (*Application, error) {
+	personRepository, err := NewPersonRepository(c, db)
+	if err != nil {
+		return nil, fmt.Errorf("Error providing PersonRepository: %s", err)
+	}
+	personService := NewPersonService(personRepository, busDep)
+	router := NewRouter(personService)
+
+	app := &Application{
+		Router:  router,
+		Service: personService,
+	}`)
+}
+
+func TestGenerateForInjectWithCycle(t *testing.T) {
+	operations := []model.Operation{
+		{
+			DocLines:   []string{`// @Provides()`},
+			Name:       "NewRouter",
+			InputArgs:  []model.Field{{TypeName: "*PersonService"}},
+			OutputArgs: []model.Field{{TypeName: "*mux.Router"}},
+		},
+		{
+			DocLines:   []string{`// @Provides()`},
+			Name:       "NewPersonService",
+			InputArgs:  []model.Field{{TypeName: "*mux.Router"}},
+			OutputArgs: []model.Field{{TypeName: "*PersonService"}},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: application(), Operations: operations}, generator.Config{InputDir: "testData"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Dependency cycle")
+}
+
+func TestGenerateForInjectWithDuplicateProvider(t *testing.T) {
+	operations := []model.Operation{
+		{DocLines: []string{`// @Provides()`}, Name: "NewRouter", OutputArgs: []model.Field{{TypeName: "*mux.Router"}}},
+		{DocLines: []string{`// @Provides()`}, Name: "NewOtherRouter", OutputArgs: []model.Field{{TypeName: "*mux.Router"}}},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: application(), Operations: operations}, generator.Config{InputDir: "testData"})
+	assert.Error(t, err)
+}
+
+func TestGenerateForInjectWithHealthChecks(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	operations := []model.Operation{
+		{DocLines: []string{`// @Provides()`}, Name: "NewRouter", OutputArgs: []model.Field{{TypeName: "*mux.Router"}}},
+		{DocLines: []string{`// @Provides()`}, Name: "NewPersonService", OutputArgs: []model.Field{{TypeName: "*PersonService"}}},
+		{
+			DocLines:   []string{`// @HealthCheck( name = "persons", readiness = "true" )`},
+			Name:       "checkPersons",
+			InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "service", TypeName: "*PersonService"}},
+			OutputArgs: []model.Field{{TypeName: "error"}},
+		},
+		{
+			DocLines:   []string{`// @HealthCheck()`},
+			Name:       "checkAlive",
+			InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}},
+			OutputArgs: []model.Field{{TypeName: "error"}},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: application(), Operations: operations}, generator.Config{InputDir: "testData"})
+	assert.Nil(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/applicationApplication.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `func (app *Application) HealthChecks() *ApplicationHealthChecks {
+	checks := &ApplicationHealthChecks{}
+	checks.Register("persons", true, func(c context.Context) error {
+		return checkPersons(c, app.Service)
+	})
+	checks.Register("checkAlive", false, func(c context.Context) error {
+		return checkAlive(c)
+	})
+	return checks
+}`)
+	assert.Contains(t, string(data), `router.HandleFunc("/readyz", h.handle(true)).Methods("GET")`)
+	assert.Contains(t, string(data), `"github.com/gorilla/mux"`)
+	assert.Contains(t, string(data), `"sync"`)
+}
+
+func TestGenerateForInjectWithHealthCheckOfUnknownType(t *testing.T) {
+	operations := []model.Operation{
+		{DocLines: []string{`// @Provides()`}, Name: "NewRouter", OutputArgs: []model.Field{{TypeName: "*mux.Router"}}},
+		{DocLines: []string{`// @Provides()`}, Name: "NewPersonService", OutputArgs: []model.Field{{TypeName: "*PersonService"}}},
+		{
+			DocLines:   []string{`// @HealthCheck()`},
+			Name:       "checkDatabase",
+			InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "db", TypeName: "*sql.DB"}},
+			OutputArgs: []model.Field{{TypeName: "error"}},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: application(), Operations: operations}, generator.Config{InputDir: "testData"})
+	assert.EqualError(t, err, "Argument db of health check checkDatabase is of type *sql.DB, which no @Inject-field of Application has")
+}
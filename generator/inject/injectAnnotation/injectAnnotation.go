@@ -0,0 +1,66 @@
+package injectAnnotation
+
+import "github.com/MarcGrol/golangAnnotations/generator/annotation"
+
+const (
+	TypeApplication = "Application"
+	TypeInject      = "Inject"
+	TypeProvides    = "Provides"
+	TypeHealthCheck = "HealthCheck"
+	ParamHealth     = "health"
+	ParamName       = "name"
+	ParamReadiness  = "readiness"
+)
+
+func Get() []annotation.AnnotationDescriptor {
+	return []annotation.AnnotationDescriptor{
+		{
+			Name:       TypeApplication,
+			ParamNames: []string{ParamHealth},
+			Validator:  validateApplicationAnnotation,
+		},
+		{
+			Name:       TypeInject,
+			ParamNames: []string{},
+			Validator:  validateInjectAnnotation,
+		},
+		{
+			Name:       TypeProvides,
+			ParamNames: []string{},
+			Validator:  validateProvidesAnnotation,
+		},
+		{
+			Name:       TypeHealthCheck,
+			ParamNames: []string{ParamName, ParamReadiness},
+			Validator:  validateHealthCheckAnnotation,
+		}}
+}
+
+func validateApplicationAnnotation(annot annotation.Annotation) bool {
+	if annot.Name == TypeApplication {
+		return true
+	}
+	return false
+}
+
+func validateInjectAnnotation(annot annotation.Annotation) bool {
+	if annot.Name == TypeInject {
+		return true
+	}
+	return false
+}
+
+func validateProvidesAnnotation(annot annotation.Annotation) bool {
+	if annot.Name == TypeProvides {
+		return true
+	}
+	return false
+}
+
+func validateHealthCheckAnnotation(annot annotation.Annotation) bool {
+	if annot.Name == TypeHealthCheck {
+		readiness, hasReadiness := annot.Attributes[ParamReadiness]
+		return !hasReadiness || readiness == "true" || readiness == "false"
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+package injectAnnotation
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrectApplicationAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	annotation, ok := registry.ResolveAnnotation(`// @Application()`)
+	assert.True(t, ok)
+	assert.Equal(t, TypeApplication, annotation.Name)
+}
+
+func TestCorrectInjectAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	annotation, ok := registry.ResolveAnnotation(`// @Inject()`)
+	assert.True(t, ok)
+	assert.Equal(t, TypeInject, annotation.Name)
+}
+
+func TestCorrectProvidesAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	annotation, ok := registry.ResolveAnnotation(`// @Provides()`)
+	assert.True(t, ok)
+	assert.Equal(t, TypeProvides, annotation.Name)
+}
+
+func TestHealthCheckAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	annotation, ok := registry.ResolveAnnotation(`// @HealthCheck( name = "eventstore", readiness = "true" )`)
+	assert.True(t, ok)
+	assert.Equal(t, TypeHealthCheck, annotation.Name)
+	assert.Equal(t, "eventstore", annotation.Attributes[ParamName])
+
+	_, ok = registry.ResolveAnnotation(`// @HealthCheck( readiness = "yes" )`)
+	assert.False(t, ok)
+}
@@ -1,7 +1,11 @@
 package generator
 
 import (
+	"context"
+	"text/template"
+
 	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/MarcGrol/golangAnnotations/logging"
 	"github.com/MarcGrol/golangAnnotations/model"
 )
 
@@ -10,7 +14,44 @@ const (
 	GenfileExcludeRegex = GenfilePrefix + ".*"
 )
 
+// Config holds the settings of a single generation run
+type Config struct {
+	InputDir string
+	// OutputDir replaces the input-dir as root of the generated files: their location relative to it is kept
+	OutputDir string
+	// FilenamePattern is a template that renames every generated file, for instance "{{.Struct.Name | snake}}_gen.go"
+	FilenamePattern string
+	// TemplateOverrides replaces the default text of templates of the generator by name
+	TemplateOverrides map[string]string
+	// TemplateFuncs are made available to all templates, in addition to the sprig-functions and the functions of the generator
+	TemplateFuncs template.FuncMap
+	// BuildTags is a build-constraint expression that all generated go-files get, e.g. "!appengine"
+	BuildTags string
+	// Header replaces the default marker-line at the top of the generated files
+	Header Header
+	// Logger receives the messages of generation: nil reports to stderr
+	Logger *logging.Logger
+}
+
+// OutputFile is a file rendered by a generator: it is up to the caller to write it
+type OutputFile struct {
+	Filename string
+	Src      string
+	// TypeName is the struct or interface that the file is generated for: empty when the file covers a package
+	TypeName string
+	// Fixed marks files that must stay where the generator put them: rewritten sources and state
+	// that the generator reads back on a next run
+	Fixed   bool
+	Content []byte
+}
+
 type Generator interface {
+	Name() string
 	GetAnnotations() []annotation.AnnotationDescriptor
-	Generate(inputDir string, parsedSources model.ParsedSources) error
+	Generate(parsedSources model.ParsedSources, config Config) ([]OutputFile, error)
+}
+
+// ContextGenerator is implemented by generators that can be cancelled while generating, like external plugins
+type ContextGenerator interface {
+	GenerateContext(c context.Context, parsedSources model.ParsedSources, config Config) ([]OutputFile, error)
 }
@@ -2,7 +2,8 @@ package jsonHelpers
 
 import (
 	"fmt"
-	"log"
+	"path/filepath"
+	"sort"
 	"strings"
 	"text/template"
 	"unicode"
@@ -21,6 +22,18 @@ func NewGenerator() generator.Generator {
 	return &Generator{}
 }
 
+func (eg *Generator) Name() string {
+	return "json-helpers"
+}
+
+func (eg *Generator) GetTemplates() []generator.Template {
+	return []generator.Template{
+		{Name: "json-enums", APIVersion: 1, Text: jsonHelpersTemplate},
+		{Name: "json-fuzz", APIVersion: 1, Text: jsonFuzzTemplate},
+		{Name: "json-encryption", APIVersion: 1, Text: jsonEncryptionTemplate},
+	}
+}
+
 func (eg *Generator) GetAnnotations() []annotation.AnnotationDescriptor {
 	return jsonAnnotation.Get()
 }
@@ -31,17 +44,17 @@ type jsonContext struct {
 	Structs     []model.Struct
 }
 
-func (eg *Generator) Generate(inputDir string, parsedSource model.ParsedSources) error {
+func (eg *Generator) Generate(parsedSource model.ParsedSources, config generator.Config) ([]generator.OutputFile, error) {
 	enums := parsedSource.Enums
 	structs := parsedSource.Structs
 
 	packageName, err := generationUtil.GetPackageNameForEnumsOrStructs(enums, structs)
 	if packageName == "" || err != nil {
-		return err
+		return nil, err
 	}
-	targetDir, err := generationUtil.DetermineTargetPath(inputDir, packageName)
+	targetDir, err := generationUtil.DetermineTargetPath(config.InputDir, packageName)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	jsonEnums := make([]model.Enum, 0, len(enums))
@@ -52,28 +65,76 @@ func (eg *Generator) Generate(inputDir string, parsedSource model.ParsedSources)
 	}
 	jsonStructs := make([]model.Struct, 0, len(structs))
 	for _, aStruct := range structs {
-		if IsJSONStruct(aStruct) {
+		// encrypted fields are only encrypted by the generated marshalers
+		if IsJSONStruct(aStruct) || HasEncryptedFields(aStruct) {
+			err = validateEncryptedFields(aStruct)
+			if err != nil {
+				return nil, err
+			}
 			jsonStructs = append(jsonStructs, aStruct)
 		}
 	}
 	if len(jsonEnums) == 0 && len(jsonStructs) == 0 {
-		return nil
+		return nil, nil
 	}
 
-	err = doGenerate(packageName, jsonEnums, jsonStructs, targetDir)
+	files, err := doGenerate(packageName, jsonEnums, jsonStructs, targetDir, config)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	for _, s := range jsonStructs {
+		if HasEncryptedFields(s) {
+			file, err := generationUtil.Generate(generationUtil.Info{
+				Src:            packageName,
+				TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/encryption.go", targetDir)),
+				TemplateName:   "json-encryption",
+				TemplateString: jsonEncryptionTemplate,
+				Config:         config,
+				FuncMap:        customTemplateFuncs,
+				Data:           jsonContext{PackageName: packageName},
+			})
+			if err != nil {
+				return nil, fmt.Errorf("Error generating encryption for package %s:%w", packageName, err)
+			}
+			return append(files, file), nil
+		}
 	}
+	return files, nil
+}
 
+// validateEncryptedFields checks that encrypted fields have a json-name of their own to hold their ciphertext
+func validateEncryptedFields(s model.Struct) error {
+	for _, f := range s.Fields {
+		if !IsEncryptedField(f) {
+			continue
+		}
+		if f.Name == "" || !generationUtil.IsExported(f.Name) {
+			return generator.FieldError(s, f, jsonAnnotation.TypeEncrypted, "Encrypted field %s.%s must be an exported field that is not embedded", s.Name, f.DereferencedTypeName())
+		}
+		if GetJSONName(f) == "-" {
+			return generator.FieldError(s, f, jsonAnnotation.TypeEncrypted, "Encrypted field %s.%s is not in json", s.Name, f.Name)
+		}
+	}
 	return nil
 }
 
-func doGenerate(packageName string, jsonEnums []model.Enum, jsonStructs []model.Struct, targetDir string) error {
+func doGenerate(packageName string, jsonEnums []model.Enum, jsonStructs []model.Struct, targetDir string, config generator.Config) ([]generator.OutputFile, error) {
 	filenameMap := getFilenamesWithTypeNames(jsonEnums, jsonStructs)
 
+	filenames := make([]string, 0, len(filenameMap))
 	for fn := range filenameMap {
-		targetFilename := strings.Replace(fn, ".", "_json.", 1)
+		filenames = append(filenames, fn)
+	}
+	sort.Strings(filenames)
+
+	files := []generator.OutputFile{}
+	for _, fn := range filenames {
+		// the source-filename includes the input-dir
+		baseFilename := filepath.Base(fn)
+		targetFilename := strings.Replace(baseFilename, ".", "_json.", 1)
 		target := generationUtil.Prefixed(fmt.Sprintf("%s/%s", targetDir, targetFilename))
+		fuzzFilename := strings.Replace(baseFilename, ".go", "_json_fuzz_test.go", 1)
+		fuzzTarget := generationUtil.Prefixed(fmt.Sprintf("%s/%s", targetDir, fuzzFilename))
 
 		data := jsonContext{
 			PackageName: packageName,
@@ -92,22 +153,51 @@ func doGenerate(packageName string, jsonEnums []model.Enum, jsonStructs []model.
 		}
 
 		if len(data.Enums) > 0 || len(data.Structs) > 0 {
-			err := generationUtil.Generate(generationUtil.Info{
+			file, err := generationUtil.Generate(generationUtil.Info{
 				Src:            packageName,
 				TargetFilename: target,
 				TemplateName:   "json-enums",
 				TemplateString: jsonHelpersTemplate,
+				Config:         config,
 				FuncMap:        customTemplateFuncs,
 				Data:           data,
 			})
 			if err != nil {
-				log.Fatalf("Error generating wrappers for enums (%s)", err)
-				return err
+				return nil, fmt.Errorf("Error generating wrappers for enums:%w", err)
 			}
+			files = append(files, file)
+
+			if !isFuzzable(data) {
+				continue
+			}
+			// round-trip fuzz-tests guard the generated (un)marshalers against regressions
+			file, err = generationUtil.Generate(generationUtil.Info{
+				Src:            packageName,
+				TargetFilename: fuzzTarget,
+				TemplateName:   "json-fuzz",
+				TemplateString: jsonFuzzTemplate,
+				Config:         config,
+				FuncMap:        customTemplateFuncs,
+				Data:           data,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("Error generating fuzz-tests for enums and structs:%w", err)
+			}
+			files = append(files, file)
 		}
 	}
 
-	return nil
+	return files, nil
+}
+
+// isFuzzable tells if a file has enums or structs to fuzz: structs with encrypted fields encrypt differently every time
+func isFuzzable(data jsonContext) bool {
+	for _, s := range data.Structs {
+		if !HasEncryptedFields(s) {
+			return true
+		}
+	}
+	return len(data.Enums) > 0
 }
 
 func getFilenamesWithTypeNames(jsonEnums []model.Enum, jsonStructs []model.Struct) map[string][]string {
@@ -138,6 +228,10 @@ var customTemplateFuncs = template.FuncMap{
 	"HasDefaultValue":    hasDefaultValue,
 	"GetDefaultValue":    getDefaultValue,
 	"HasSlices":          hasSlices,
+	"HasEncryptedFields": HasEncryptedFields,
+	"IsEncryptedField":   IsEncryptedField,
+	"GetEncryptionKey":   GetEncryptionKey,
+	"GetJSONName":        GetJSONName,
 }
 
 func IsJSONEnum(e model.Enum) bool {
@@ -190,6 +284,15 @@ func GetJSONEnumDefault(e model.Enum) string {
 	return ""
 }
 
+// GetJSONEnumNames returns the names that the literals of an enum have in json
+func GetJSONEnumNames(e model.Enum) []string {
+	names := []string{}
+	for _, lit := range e.EnumLiterals {
+		names = append(names, getPreferredName(e, lit))
+	}
+	return names
+}
+
 func hasDefaultValue(e model.Enum) bool {
 	return GetJSONEnumDefault(e) != ""
 }
@@ -248,3 +351,37 @@ func hasSlices(s model.Struct) bool {
 	}
 	return false
 }
+
+func IsEncryptedField(f model.Field) bool {
+	annotations := annotation.NewRegistry(jsonAnnotation.Get())
+	_, ok := annotations.ResolveAnnotationByName(f.DocLines, jsonAnnotation.TypeEncrypted)
+	return ok
+}
+
+func HasEncryptedFields(s model.Struct) bool {
+	for _, f := range s.Fields {
+		if IsEncryptedField(f) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetEncryptionKey returns the name of the master-key in the key management service that wraps the data-keys of a field
+func GetEncryptionKey(f model.Field) string {
+	annotations := annotation.NewRegistry(jsonAnnotation.Get())
+	if ann, ok := annotations.ResolveAnnotationByName(f.DocLines, jsonAnnotation.TypeEncrypted); ok {
+		if key := ann.Attributes[jsonAnnotation.ParamKey]; key != "" {
+			return key
+		}
+	}
+	return "default"
+}
+
+// GetJSONName returns the name of a field in json
+func GetJSONName(f model.Field) string {
+	if name := strings.Split(f.GetTagMap()["json"], ",")[0]; name != "" {
+		return name
+	}
+	return f.Name
+}
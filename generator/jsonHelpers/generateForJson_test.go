@@ -5,6 +5,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/MarcGrol/golangAnnotations/generator"
 	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
 	"github.com/MarcGrol/golangAnnotations/model"
 	"github.com/stretchr/testify/assert"
@@ -13,6 +14,10 @@ import (
 func cleanup() {
 	os.Remove(generationUtil.Prefixed("./testData/ast.json"))
 	os.Remove(generationUtil.Prefixed("./testData/example_json.go"))
+	os.Remove(generationUtil.Prefixed("./testData/example_json_fuzz_test.go"))
+	os.Remove(generationUtil.Prefixed("./testData/customer_json.go"))
+	os.Remove(generationUtil.Prefixed("./testData/customer_json_fuzz_test.go"))
+	os.Remove(generationUtil.Prefixed("./testData/encryption.go"))
 }
 
 func TestGenerateForJson(t *testing.T) {
@@ -64,7 +69,7 @@ func TestGenerateForJson(t *testing.T) {
 		Enums:   e,
 		Structs: s,
 	}
-	err := NewGenerator().Generate("./testData/", ps)
+	err := generator.Generate(NewGenerator(), ps, generator.Config{InputDir: "./testData/"})
 	assert.Nil(t, err)
 
 	// check that generated files exists
@@ -80,6 +85,73 @@ func TestGenerateForJson(t *testing.T) {
 	assert.Contains(t, string(data), `func (data *ColoredThing) UnmarshalJSON(b []byte) error {`)
 	assert.Contains(t, string(data), `func (data ColoredThing) MarshalJSON() ([]byte, error) {`)
 
+	// check that round-trip fuzz-tests are generated for enum and struct
+	data, err = ioutil.ReadFile(generationUtil.Prefixed("./testData/example_json_fuzz_test.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "//go:build go1.18")
+	assert.Contains(t, string(data), `func FuzzColorTypeJSON(f *testing.F) {
+	f.Add("colorTypeRed")`)
+	assert.Contains(t, string(data), `func FuzzColoredThingJSON(f *testing.F) {`)
+}
+
+func TestGenerateForEncryptedFields(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			Filename:    "customer.go",
+			Name:        "Customer",
+			Fields: []model.Field{
+				{Name: "UID", TypeName: "string", Tag: "`json:\"uid\"`"},
+				{Name: "Iban", TypeName: "string", Tag: "`json:\"iban,omitempty\"`", DocLines: []string{`// @Encrypted( key = "payments" )`}},
+				{Name: "Phones", TypeName: "[]string", Tag: "`json:\"phones\"`", DocLines: []string{`// @Encrypted()`}},
+				{Name: "Tags", TypeName: "[]string"},
+			},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Nil(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/customer_json.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "Iban   *EncryptedValue `json:\"iban,omitempty\"`")
+	assert.Contains(t, string(data), `raw.Iban, err = encryptField("payments", data.Iban)`)
+	assert.Contains(t, string(data), `raw.Phones, err = encryptField("default", data.Phones)`)
+	assert.Contains(t, string(data), "err = decryptField(raw.Iban, &raw.alias.Iban)")
+	assert.Contains(t, string(data), `if data.Tags == nil {`)
+	assert.NotContains(t, string(data), `if data.Phones == nil {`)
+
+	// encrypted fields encrypt differently every time
+	_, err = os.Stat(generationUtil.Prefixed("./testData/customer_json_fuzz_test.go"))
+	assert.True(t, os.IsNotExist(err))
+
+	data, err = ioutil.ReadFile(generationUtil.Prefixed("./testData/encryption.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "type KeyManagementService interface {")
+	assert.Contains(t, string(data), "func SetKeyManagementService(kms KeyManagementService) {")
+}
+
+func TestEncryptedFieldNotInJson(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			Filename:    "customer.go",
+			Name:        "Customer",
+			Fields: []model.Field{
+				{Name: "Iban", TypeName: "string", Tag: "`json:\"-\"`", DocLines: []string{`// @Encrypted()`}},
+			},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Encrypted field Customer.Iban is not in json")
 }
 
 func TestIsJsonEnum(t *testing.T) {
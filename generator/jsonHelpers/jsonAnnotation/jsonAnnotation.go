@@ -5,11 +5,13 @@ import "github.com/MarcGrol/golangAnnotations/generator/annotation"
 const (
 	TypeEnum      = "JsonEnum"
 	TypeStruct    = "JsonStruct"
+	TypeEncrypted = "Encrypted"
 	ParamStripped = "stripped"
 	ParamLiteral  = "literal"
 	ParamTolerant = "tolerant"
 	ParamBase     = "base"
 	ParamDefault  = "default"
+	ParamKey      = "key"
 )
 
 func Get() []annotation.AnnotationDescriptor {
@@ -23,6 +25,11 @@ func Get() []annotation.AnnotationDescriptor {
 			Name:       TypeStruct,
 			ParamNames: []string{},
 			Validator:  validateStructAnnotation,
+		},
+		{
+			Name:       TypeEncrypted,
+			ParamNames: []string{ParamKey},
+			Validator:  validateEncryptedAnnotation,
 		}}
 }
 
@@ -39,3 +46,11 @@ func validateStructAnnotation(annot annotation.Annotation) bool {
 	}
 	return false
 }
+
+func validateEncryptedAnnotation(annot annotation.Annotation) bool {
+	if annot.Name == TypeEncrypted {
+		key, hasKey := annot.Attributes[ParamKey]
+		return !hasKey || key != ""
+	}
+	return false
+}
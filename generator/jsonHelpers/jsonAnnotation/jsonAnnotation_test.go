@@ -30,3 +30,18 @@ func TestEmptyStructAnnotation(t *testing.T) {
 
 	assert.Empty(t, registry.ResolveAnnotations([]string{``}))
 }
+
+func TestCorrectEncryptedAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	annotation, ok := registry.ResolveAnnotation(`// @Encrypted( key = "customer-data" )`)
+	assert.True(t, ok)
+	assert.Equal(t, "customer-data", annotation.Attributes[ParamKey])
+	assert.NotEmpty(t, registry.ResolveAnnotations([]string{`// @Encrypted()`}))
+}
+
+func TestInvalidEncryptedAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @Encrypted( key = "" )`}))
+}
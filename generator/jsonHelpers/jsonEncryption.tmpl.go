@@ -0,0 +1,102 @@
+package jsonHelpers
+
+const jsonEncryptionTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+
+package {{.PackageName}}
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// KeyManagementService wraps the data-keys that encrypt the fields with @Encrypted with its master-keys. WrapKey
+// returns the id of the version of the master-key that it used, so that master-keys can rotate.
+type KeyManagementService interface {
+	WrapKey(c context.Context, keyName string, dataKey []byte) (string, []byte, error)
+	UnwrapKey(c context.Context, keyID string, wrappedKey []byte) ([]byte, error)
+}
+
+var keyManagementService KeyManagementService
+
+// SetKeyManagementService sets the key management service of the encrypted fields of the package
+func SetKeyManagementService(kms KeyManagementService) {
+	keyManagementService = kms
+}
+
+// EncryptedValue is the json of a field with @Encrypted: its json encrypted with a data-key of its own, that is
+// stored wrapped by the master-key with id KeyID
+type EncryptedValue struct {
+	KeyID      string ` + "`" + `json:"keyId"` + "`" + `
+	DataKey    []byte ` + "`" + `json:"dataKey"` + "`" + `
+	Ciphertext []byte ` + "`" + `json:"ciphertext"` + "`" + `
+}
+
+func encryptField(keyName string, value interface{}) (*EncryptedValue, error) {
+	if keyManagementService == nil {
+		return nil, fmt.Errorf("No key management service to encrypt with key %s", keyName)
+	}
+	plaintext, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	dataKey := make([]byte, 32)
+	_, err = io.ReadFull(rand.Reader, dataKey)
+	if err != nil {
+		return nil, err
+	}
+	keyID, wrappedKey, err := keyManagementService.WrapKey(context.Background(), keyName, dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("Error wrapping data-key with key %s: %s", keyName, err)
+	}
+	aead, err := fieldCipher(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	_, err = io.ReadFull(rand.Reader, nonce)
+	if err != nil {
+		return nil, err
+	}
+	return &EncryptedValue{
+		KeyID:      keyID,
+		DataKey:    wrappedKey,
+		Ciphertext: aead.Seal(nonce, nonce, plaintext, []byte(keyID)),
+	}, nil
+}
+
+func decryptField(encrypted *EncryptedValue, value interface{}) error {
+	if keyManagementService == nil {
+		return fmt.Errorf("No key management service to decrypt with key %s", encrypted.KeyID)
+	}
+	dataKey, err := keyManagementService.UnwrapKey(context.Background(), encrypted.KeyID, encrypted.DataKey)
+	if err != nil {
+		return fmt.Errorf("Error unwrapping data-key with key %s: %s", encrypted.KeyID, err)
+	}
+	aead, err := fieldCipher(dataKey)
+	if err != nil {
+		return err
+	}
+	if len(encrypted.Ciphertext) < aead.NonceSize() {
+		return fmt.Errorf("Ciphertext is too short")
+	}
+	nonce, ciphertext := encrypted.Ciphertext[:aead.NonceSize()], encrypted.Ciphertext[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(encrypted.KeyID))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(plaintext, value)
+}
+
+func fieldCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+`
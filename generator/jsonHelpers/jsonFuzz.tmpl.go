@@ -0,0 +1,86 @@
+package jsonHelpers
+
+const jsonFuzzTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+
+//go:build go1.18
+// +build go1.18
+
+package {{.PackageName}}
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+{{range .Enums -}}
+{{$enum := . -}}
+// Fuzz{{.Name}}JSON verifies that every {{.Name}} that can be decoded survives an encode-decode round-trip
+func Fuzz{{.Name}}JSON(f *testing.F) {
+	{{range .EnumLiterals -}}
+	f.Add("{{GetPreferredName $enum .}}")
+	{{end -}}
+	f.Fuzz(func(t *testing.T, name string) {
+		input, err := json.Marshal(name)
+		if err != nil {
+			return
+		}
+		var value {{.Name}}
+		if err := json.Unmarshal(input, &value); err != nil {
+			return
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			t.Fatalf("Error encoding {{.Name}} %d decoded from %q: %s", value, name, err)
+		}
+		var decoded {{.Name}}
+		if err := json.Unmarshal(encoded, &decoded); err != nil {
+			t.Fatalf("Error decoding {{.Name}} from %s: %s", encoded, err)
+		}
+		if decoded != value {
+			t.Fatalf("{{.Name}} changed during round-trip: %d became %d", value, decoded)
+		}
+		{{if HasDefaultValue . -}}
+		if byName := {{.Name}}ByName(value.String()); byName != value {
+			t.Fatalf("{{.Name}}ByName(%q) returned %d instead of %d", value.String(), byName, value)
+		}
+		{{end -}}
+	})
+}
+
+{{end -}}
+
+{{range .Structs -}}
+{{if not (HasEncryptedFields .) -}}
+// Fuzz{{.Name}}JSON verifies that every {{.Name}} that can be decoded encodes identically after an encode-decode round-trip
+func Fuzz{{.Name}}JSON(f *testing.F) {
+	f.Add([]byte("{}"))
+	if seed, err := json.Marshal({{.Name}}{}); err == nil {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var value {{.Name}}
+		if err := json.Unmarshal(data, &value); err != nil {
+			return
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			t.Fatalf("Error encoding {{.Name}} decoded from %s: %s", data, err)
+		}
+		var decoded {{.Name}}
+		if err := json.Unmarshal(encoded, &decoded); err != nil {
+			t.Fatalf("Error decoding {{.Name}} from %s: %s", encoded, err)
+		}
+		reencoded, err := json.Marshal(decoded)
+		if err != nil {
+			t.Fatalf("Error re-encoding {{.Name}}: %s", err)
+		}
+		if !bytes.Equal(encoded, reencoded) {
+			t.Fatalf("{{.Name}} changed during round-trip: %s became %s", encoded, reencoded)
+		}
+	})
+}
+
+{{end -}}
+{{end -}}
+`
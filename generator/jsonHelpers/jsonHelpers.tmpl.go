@@ -96,7 +96,77 @@ func (t *{{.Name}}) UnmarshalJSON(data []byte) error {
 {{range .Structs -}}
 
 // Helpers for json-struct {{.Name}}
-{{if HasSlices . -}}
+{{if HasEncryptedFields . -}}
+{{$struct := .Name -}}
+
+// MarshalJSON encrypts the fields with @Encrypted{{if HasSlices .}} and prevents nil slices in json{{end}}
+func (data {{.Name}}) MarshalJSON() ([]byte, error) {
+	{{range .Fields -}}
+		{{if and .IsSlice (not (IsEncryptedField .)) -}}
+			if data.{{.Name}} == nil {
+				data.{{.Name}} = {{.TypeName}}{}
+			}
+		{{end -}}
+	{{end -}}
+	type alias {{.Name}}
+	var raw = struct {
+		alias
+		{{range .Fields -}}
+			{{if IsEncryptedField . -}}
+				{{.Name}} *EncryptedValue ` + "`" + `json:"{{GetJSONName .}},omitempty"` + "`" + `
+			{{end -}}
+		{{end -}}
+	}{alias: alias(data)}
+
+	var err error
+	{{range .Fields -}}
+		{{if IsEncryptedField . -}}
+			raw.{{.Name}}, err = encryptField("{{GetEncryptionKey .}}", data.{{.Name}})
+			if err != nil {
+				return nil, fmt.Errorf("Error encrypting {{$struct}}.{{.Name}}: %s", err)
+			}
+		{{end -}}
+	{{end -}}
+	return json.Marshal(raw)
+}
+
+// UnmarshalJSON decrypts the fields with @Encrypted{{if HasSlices .}} and prevents nil slices from json{{end}}
+func (data *{{.Name}}) UnmarshalJSON(b []byte) error {
+	type alias {{.Name}}
+	var raw struct {
+		alias
+		{{range .Fields -}}
+			{{if IsEncryptedField . -}}
+				{{.Name}} *EncryptedValue ` + "`" + `json:"{{GetJSONName .}},omitempty"` + "`" + `
+			{{end -}}
+		{{end -}}
+	}
+	err := json.Unmarshal(b, &raw)
+	if err != nil {
+		return err
+	}
+
+	{{range .Fields -}}
+		{{if IsEncryptedField . -}}
+			if raw.{{.Name}} != nil {
+				err = decryptField(raw.{{.Name}}, &raw.alias.{{.Name}})
+				if err != nil {
+					return fmt.Errorf("Error decrypting {{$struct}}.{{.Name}}: %s", err)
+				}
+			}
+		{{end -}}
+		{{if .IsSlice -}}
+			if raw.alias.{{.Name}} == nil {
+				raw.alias.{{.Name}} = {{.TypeName}}{}
+			}
+		{{end -}}
+	{{end -}}
+
+	*data = {{.Name}}(raw.alias)
+	return nil
+}
+
+{{else if HasSlices . -}}
 
 // MarshalJSON prevents nil slices in json
 func (data {{.Name}}) MarshalJSON() ([]byte, error) {
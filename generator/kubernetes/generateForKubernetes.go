@@ -0,0 +1,184 @@
+package kubernetes
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/generator/inject"
+	"github.com/MarcGrol/golangAnnotations/generator/kubernetes/kubernetesAnnotation"
+	"github.com/MarcGrol/golangAnnotations/generator/rest"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+const (
+	defaultPort     = 8080
+	defaultReplicas = 1
+	// servicePort is the port of the kubernetes service in front of the containers
+	servicePort = 80
+)
+
+// validName is the name of kubernetes-objects: lowercase letters, digits and dashes
+var validName = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+type deploymentContext struct {
+	Name        string
+	Image       string
+	Repository  string
+	Tag         string
+	Port        int
+	ServicePort int
+	Replicas    int
+	CPU         string
+	Memory      string
+	Health      bool
+	Paths       []string
+}
+
+type Generator struct {
+}
+
+func NewGenerator() generator.Generator {
+	return &Generator{}
+}
+
+func (eg *Generator) Name() string {
+	return "kubernetes"
+}
+
+func (eg *Generator) GetTemplates() []generator.Template {
+	return []generator.Template{
+		{Name: "manifest", APIVersion: 1, Text: manifestTemplate},
+		{Name: "helm-values", APIVersion: 1, Text: helmValuesTemplate},
+	}
+}
+
+func (eg *Generator) GetAnnotations() []annotation.AnnotationDescriptor {
+	return kubernetesAnnotation.Get()
+}
+
+func (eg *Generator) Generate(parsedSource model.ParsedSources, config generator.Config) ([]generator.OutputFile, error) {
+	return generate(config, parsedSource.Structs, parsedSource.Operations)
+}
+
+func generate(config generator.Config, structs []model.Struct, operations []model.Operation) ([]generator.OutputFile, error) {
+	packageName, err := generationUtil.GetPackageNameForStructs(structs)
+	if packageName == "" || err != nil {
+		return nil, err
+	}
+	targetDir, err := generationUtil.DetermineTargetPath(config.InputDir, packageName)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []generator.OutputFile{}
+	for _, s := range structs {
+		ann, ok := annotation.NewRegistry(kubernetesAnnotation.Get()).ResolveAnnotationByName(s.DocLines, kubernetesAnnotation.TypeKubernetes)
+		if !ok {
+			continue
+		}
+		if !inject.IsApplication(s) {
+			return nil, generator.StructError(s, kubernetesAnnotation.TypeKubernetes, "Struct %s with @Kubernetes must be an @Application", s.Name)
+		}
+		ctx, err := newDeploymentContext(packageName, s, ann, structs, operations)
+		if err != nil {
+			return nil, err
+		}
+
+		templateName, filename := "manifest", fmt.Sprintf("%s/kubernetes%s.yaml", targetDir, s.Name)
+		if ann.Attributes[kubernetesAnnotation.ParamFormat] == kubernetesAnnotation.FormatHelm {
+			templateName, filename = "helm-values", fmt.Sprintf("%s/helmValues%s.yaml", targetDir, s.Name)
+		}
+		file, err := generationUtil.Generate(generationUtil.Info{
+			Src:            fmt.Sprintf("%s.%s", s.PackageName, s.Name),
+			TypeName:       s.Name,
+			TargetFilename: generationUtil.Prefixed(filename),
+			TemplateName:   templateName,
+			TemplateString: templates[templateName],
+			Config:         config,
+			FuncMap:        customTemplateFuncs,
+			Data:           ctx,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Error generating kubernetes-%s for application %s:%w", templateName, s.Name, err)
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+var templates = map[string]string{
+	"manifest":    manifestTemplate,
+	"helm-values": helmValuesTemplate,
+}
+
+var customTemplateFuncs = template.FuncMap{
+	"Quote": strconv.Quote,
+}
+
+// newDeploymentContext combines the attributes of @Kubernetes with the paths of the rest-services of the package
+// and the health-endpoints of the application
+func newDeploymentContext(packageName string, s model.Struct, ann annotation.Annotation, structs []model.Struct, operations []model.Operation) (deploymentContext, error) {
+	ctx := deploymentContext{
+		Name:        ann.Attributes[kubernetesAnnotation.ParamName],
+		Image:       ann.Attributes[kubernetesAnnotation.ParamImage],
+		Port:        defaultPort,
+		ServicePort: servicePort,
+		Replicas:    defaultReplicas,
+		CPU:         ann.Attributes[kubernetesAnnotation.ParamCPU],
+		Memory:      ann.Attributes[kubernetesAnnotation.ParamMemory],
+		Health:      inject.HasHealthEndpoints(s, operations),
+		Paths:       getRestServicePaths(structs),
+	}
+	if ctx.Name == "" {
+		ctx.Name = strings.ToLower(packageName)
+	}
+	if !validName.MatchString(ctx.Name) {
+		return deploymentContext{}, generator.StructError(s, kubernetesAnnotation.TypeKubernetes, "Name '%s' of application %s is no valid kubernetes-name: use lowercase letters, digits and dashes", ctx.Name, s.Name)
+	}
+	if ctx.Image == "" {
+		ctx.Image = ctx.Name + ":latest"
+	}
+	ctx.Repository, ctx.Tag = splitImage(ctx.Image)
+	if port, found := ann.Attributes[kubernetesAnnotation.ParamPort]; found {
+		ctx.Port, _ = strconv.Atoi(port)
+	}
+	if replicas, found := ann.Attributes[kubernetesAnnotation.ParamReplicas]; found {
+		ctx.Replicas, _ = strconv.Atoi(replicas)
+	}
+	return ctx, nil
+}
+
+// getRestServicePaths returns the paths of the rest-services, that the ingress routes to the application
+func getRestServicePaths(structs []model.Struct) []string {
+	unique := map[string]bool{}
+	for _, s := range structs {
+		if rest.IsRestService(s) {
+			path := rest.GetRestServicePath(s)
+			if path == "" {
+				path = "/"
+			}
+			unique[path] = true
+		}
+	}
+	paths := []string{}
+	for path := range unique {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// splitImage splits an image into its repository and tag: "registry:5000/persons:1.2" has tag "1.2"
+func splitImage(image string) (string, string) {
+	if idx := strings.LastIndex(image, ":"); idx > strings.LastIndex(image, "/") {
+		return image[:idx], image[idx+1:]
+	}
+	return image, "latest"
+}
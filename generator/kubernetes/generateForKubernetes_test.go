@@ -0,0 +1,122 @@
+package kubernetes
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func cleanup() {
+	os.Remove(generationUtil.Prefixed("./testData/kubernetesApplication.yaml"))
+	os.Remove(generationUtil.Prefixed("./testData/helmValuesApplication.yaml"))
+}
+
+func TestGenerateManifest(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @Application( health = "true" )`, `// @Kubernetes( name = "persons", port = "9090", replicas = "3" )`},
+			Name:        "Application",
+			Fields:      []model.Field{{Name: "Service", TypeName: "*PersonService", DocLines: []string{`// @Inject()`}}},
+		},
+		{PackageName: "testData", DocLines: []string{`// @RestService( path = "/persons" )`}, Name: "PersonService"},
+		{PackageName: "testData", DocLines: []string{`// @RestService( path = "/api" )`}, Name: "AdminService"},
+	}
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/kubernetesApplication.yaml"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "  replicas: 3\n")
+	assert.Contains(t, string(data), `          image: "persons:latest"
+          ports:
+            - containerPort: 9090
+          livenessProbe:
+            httpGet:
+              path: /healthz
+              port: 9090
+          readinessProbe:
+            httpGet:
+              path: /readyz
+              port: 9090
+`)
+	assert.Contains(t, string(data), `          - path: "/api"`)
+	assert.Contains(t, string(data), `          - path: "/persons"`)
+	assert.NotContains(t, string(data), "resources:")
+}
+
+func TestGenerateHelmValues(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @Application( health = "true" )`, `// @Kubernetes( image = "registry:5000/persons:1.2", memory = "256Mi", format = "helm" )`},
+			Name:        "Application",
+			Fields:      []model.Field{{Name: "Service", TypeName: "*PersonService", DocLines: []string{`// @Inject()`}}},
+		},
+		{PackageName: "testData", DocLines: []string{`// @RestService( path = "/persons" )`}, Name: "PersonService"},
+		{PackageName: "testData", DocLines: []string{`// @RestService( path = "/api" )`}, Name: "AdminService"},
+	}
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/helmValuesApplication.yaml"))
+	assert.NoError(t, err)
+	assert.Equal(t, `# Generated automatically by golangAnnotations: do not edit manually
+
+nameOverride: testdata
+replicaCount: 1
+image:
+  repository: "registry:5000/persons"
+  tag: "1.2"
+service:
+  port: 80
+  targetPort: 8080
+resources:
+  requests:
+    memory: "256Mi"
+probes:
+  liveness: /healthz
+  readiness: /readyz
+ingress:
+  paths:
+    - "/api"
+    - "/persons"
+`, string(data))
+}
+
+func TestInvalidKubernetesName(t *testing.T) {
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			Filename:    "app.go",
+			Line:        12,
+			DocLines:    []string{`// @Application()`, `// @Kubernetes( name = "Persons_App" )`},
+			Name:        "Application",
+		},
+	}
+	_, err := NewGenerator().Generate(model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.EqualError(t, err, "app.go:11: Name 'Persons_App' of application Application is no valid kubernetes-name: use lowercase letters, digits and dashes")
+}
+
+func TestKubernetesRequiresApplication(t *testing.T) {
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @Kubernetes()`},
+			Name:        "Application",
+		},
+	}
+	_, err := NewGenerator().Generate(model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.EqualError(t, err, "Struct Application with @Kubernetes must be an @Application")
+}
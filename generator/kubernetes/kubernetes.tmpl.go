@@ -0,0 +1,111 @@
+package kubernetes
+
+const manifestTemplate = `# Generated automatically by golangAnnotations: do not edit manually
+
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{.Name}}
+  labels:
+    app: {{.Name}}
+spec:
+  replicas: {{.Replicas}}
+  selector:
+    matchLabels:
+      app: {{.Name}}
+  template:
+    metadata:
+      labels:
+        app: {{.Name}}
+    spec:
+      containers:
+        - name: {{.Name}}
+          image: {{Quote .Image}}
+          ports:
+            - containerPort: {{.Port}}
+{{- if or .CPU .Memory}}
+          resources:
+            requests:
+{{- if .CPU}}
+              cpu: {{Quote .CPU}}
+{{- end}}
+{{- if .Memory}}
+              memory: {{Quote .Memory}}
+{{- end}}
+{{- end}}
+{{- if .Health}}
+          livenessProbe:
+            httpGet:
+              path: /healthz
+              port: {{.Port}}
+          readinessProbe:
+            httpGet:
+              path: /readyz
+              port: {{.Port}}
+{{- end}}
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: {{.Name}}
+spec:
+  selector:
+    app: {{.Name}}
+  ports:
+    - port: {{.ServicePort}}
+      targetPort: {{.Port}}
+{{- if .Paths}}
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: {{.Name}}
+spec:
+  rules:
+    - http:
+        paths:
+{{- range .Paths}}
+          - path: {{Quote .}}
+            pathType: Prefix
+            backend:
+              service:
+                name: {{$.Name}}
+                port:
+                  number: {{$.ServicePort}}
+{{- end}}
+{{- end}}
+`
+
+const helmValuesTemplate = `# Generated automatically by golangAnnotations: do not edit manually
+
+nameOverride: {{.Name}}
+replicaCount: {{.Replicas}}
+image:
+  repository: {{Quote .Repository}}
+  tag: {{Quote .Tag}}
+service:
+  port: {{.ServicePort}}
+  targetPort: {{.Port}}
+{{- if or .CPU .Memory}}
+resources:
+  requests:
+{{- if .CPU}}
+    cpu: {{Quote .CPU}}
+{{- end}}
+{{- if .Memory}}
+    memory: {{Quote .Memory}}
+{{- end}}
+{{- end}}
+{{- if .Health}}
+probes:
+  liveness: /healthz
+  readiness: /readyz
+{{- end}}
+{{- if .Paths}}
+ingress:
+  paths:
+{{- range .Paths}}
+    - {{Quote .}}
+{{- end}}
+{{- end}}
+`
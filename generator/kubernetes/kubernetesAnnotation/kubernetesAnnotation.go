@@ -0,0 +1,44 @@
+package kubernetesAnnotation
+
+import (
+	"strconv"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+)
+
+const (
+	TypeKubernetes = "Kubernetes"
+	ParamName      = "name"
+	ParamImage     = "image"
+	ParamPort      = "port"
+	ParamReplicas  = "replicas"
+	ParamCPU       = "cpu"
+	ParamMemory    = "memory"
+	ParamFormat    = "format"
+	FormatManifest = "manifest"
+	FormatHelm     = "helm"
+)
+
+func Get() []annotation.AnnotationDescriptor {
+	return []annotation.AnnotationDescriptor{
+		{
+			Name:       TypeKubernetes,
+			ParamNames: []string{ParamName, ParamImage, ParamPort, ParamReplicas, ParamCPU, ParamMemory, ParamFormat},
+			Validator:  validateKubernetesAnnotation,
+		}}
+}
+
+func validateKubernetesAnnotation(annot annotation.Annotation) bool {
+	if annot.Name == TypeKubernetes {
+		for _, name := range []string{ParamPort, ParamReplicas} {
+			if value, found := annot.Attributes[name]; found {
+				if number, err := strconv.Atoi(value); err != nil || number <= 0 {
+					return false
+				}
+			}
+		}
+		format, hasFormat := annot.Attributes[ParamFormat]
+		return !hasFormat || format == FormatManifest || format == FormatHelm
+	}
+	return false
+}
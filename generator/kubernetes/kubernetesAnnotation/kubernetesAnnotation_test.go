@@ -0,0 +1,25 @@
+package kubernetesAnnotation
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrectKubernetesAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	annotation, ok := registry.ResolveAnnotation(`// @Kubernetes( name = "persons", port = "8080", replicas = "2", format = "helm" )`)
+	assert.True(t, ok)
+	assert.Equal(t, TypeKubernetes, annotation.Name)
+	assert.Equal(t, "persons", annotation.Attributes[ParamName])
+}
+
+func TestInvalidKubernetesAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @Kubernetes( port = "http" )`}))
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @Kubernetes( replicas = "0" )`}))
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @Kubernetes( format = "kustomize" )`}))
+}
@@ -0,0 +1,165 @@
+package generator
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ManifestFilename is the file in the input-dir that lists the files that golangAnnotations generated
+const ManifestFilename = ".golangannotations.manifest"
+
+const manifestHeader = "# " + DefaultMarker + "\n# Files generated by golangAnnotations, per generator and relative to this directory: 'golangAnnotations clean' removes the ones that are no longer generated\n"
+
+// ManifestEntry is a generated file in the manifest, with the generator that generated it
+type ManifestEntry struct {
+	Generator string
+	Filename  string
+}
+
+// ManifestEntries returns the entries of the files of a generator that belong in the manifest: rewritten sources
+// and migrations are not listed, because they must never be removed
+func ManifestEntries(generatorName string, files []OutputFile) []ManifestEntry {
+	entries := []ManifestEntry{}
+	for _, f := range files {
+		if !f.Fixed {
+			entries = append(entries, ManifestEntry{Generator: generatorName, Filename: f.Filename})
+		}
+	}
+	return entries
+}
+
+// ReadManifest returns the generated files that are listed in the manifest of inputDir, relative to the working dir.
+// Every line holds a generator and a file, separated by a tab: the files in manifests of earlier versions have no
+// generator.
+func ReadManifest(inputDir string) ([]ManifestEntry, error) {
+	filename := filepath.Join(inputDir, ManifestFilename)
+	data, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return []ManifestEntry{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Error reading manifest %s:%w", filename, err)
+	}
+	entries := []ManifestEntry{}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		entry := ManifestEntry{Filename: line}
+		if idx := strings.Index(line, "\t"); idx >= 0 {
+			entry = ManifestEntry{Generator: line[:idx], Filename: line[idx+1:]}
+		}
+		entry.Filename = filepath.Join(inputDir, filepath.FromSlash(entry.Filename))
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// WriteManifest lists the generated files in the manifest of inputDir. Files of a previous run that are no longer
+// generated stay listed as long as they exist, so that a later clean still finds them.
+func WriteManifest(inputDir string, generated []ManifestEntry) error {
+	previous, err := ReadManifest(inputDir)
+	if err != nil {
+		return err
+	}
+	listed := map[string]string{}
+	for _, entry := range previous {
+		if _, err := os.Stat(entry.Filename); err == nil {
+			listed[filepath.Clean(entry.Filename)] = entry.Generator
+		}
+	}
+	for _, entry := range generated {
+		listed[filepath.Clean(entry.Filename)] = entry.Generator
+	}
+	return writeManifest(inputDir, listed)
+}
+
+// RemoveStaleFiles removes the files in the manifest of inputDir that the given generators no longer generate and
+// returns them. The files of other generators are left alone, so that cleaning with some generators keeps the files
+// of the others: without generators all of them ran, which also removes the stale files without generator.
+func RemoveStaleFiles(inputDir string, generators []string, generated []ManifestEntry) ([]ManifestEntry, error) {
+	previous, err := ReadManifest(inputDir)
+	if err != nil {
+		return nil, err
+	}
+	ran := map[string]bool{}
+	for _, name := range generators {
+		ran[name] = true
+	}
+	listed := map[string]string{}
+	for _, entry := range generated {
+		listed[filepath.Clean(entry.Filename)] = entry.Generator
+	}
+	removed := []ManifestEntry{}
+	for _, entry := range previous {
+		filename := filepath.Clean(entry.Filename)
+		if _, found := listed[filename]; found {
+			continue
+		}
+		if len(generators) > 0 && !ran[entry.Generator] {
+			listed[filename] = entry.Generator
+			continue
+		}
+		err = os.Remove(entry.Filename)
+		if err != nil && !os.IsNotExist(err) {
+			return removed, fmt.Errorf("Error removing stale file %s:%w", entry.Filename, err)
+		}
+		if err == nil {
+			removed = append(removed, entry)
+		}
+	}
+	return removed, writeManifest(inputDir, listed)
+}
+
+func writeManifest(inputDir string, listed map[string]string) error {
+	lines := []string{}
+	for filename, generatorName := range listed {
+		relative, err := relativeFilename(inputDir, filename)
+		if err != nil {
+			return fmt.Errorf("Error listing %s in manifest:%w", filename, err)
+		}
+		line := filepath.ToSlash(relative)
+		if generatorName != "" {
+			line = generatorName + "\t" + line
+		}
+		lines = append(lines, line)
+	}
+	sort.Strings(lines)
+
+	f := OutputFile{
+		Filename: filepath.Join(inputDir, ManifestFilename),
+		Content:  []byte(manifestHeader + strings.Join(lines, "\n") + "\n"),
+	}
+	if len(lines) == 0 {
+		f.Content = []byte(manifestHeader)
+	}
+	upToDate, err := IsUpToDate(f)
+	if err != nil || upToDate {
+		return err
+	}
+	err = ioutil.WriteFile(f.Filename, f.Content, 0644)
+	if err != nil {
+		return fmt.Errorf("Error writing manifest %s:%w", f.Filename, err)
+	}
+	return nil
+}
+
+func relativeFilename(dir string, filename string) (string, error) {
+	if filepath.IsAbs(dir) != filepath.IsAbs(filename) {
+		var err error
+		dir, err = filepath.Abs(dir)
+		if err != nil {
+			return "", err
+		}
+		filename, err = filepath.Abs(filename)
+		if err != nil {
+			return "", err
+		}
+	}
+	return filepath.Rel(dir, filename)
+}
@@ -0,0 +1,122 @@
+package generator
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManifestEntriesSkipsFixedFiles(t *testing.T) {
+	assert.Equal(t, []ManifestEntry{{Generator: "sql", Filename: "gen_x.go"}}, ManifestEntries("sql", []OutputFile{
+		{Filename: "gen_x.go"},
+		{Filename: "migrations/000001_x.up.sql", Fixed: true},
+	}))
+}
+
+func TestWriteAndReadManifest(t *testing.T) {
+	dir := t.TempDir()
+	err := WriteManifest(dir, []ManifestEntry{
+		{Generator: "rest", Filename: filepath.Join(dir, "sub", "gen_b.go")},
+		{Generator: "event", Filename: filepath.Join(dir, "gen_a.go")},
+	})
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, ManifestFilename))
+	assert.NoError(t, err)
+	assert.Equal(t, manifestHeader+"event\tgen_a.go\nrest\tsub/gen_b.go\n", string(data))
+
+	entries, err := ReadManifest(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, []ManifestEntry{
+		{Generator: "event", Filename: filepath.Join(dir, "gen_a.go")},
+		{Generator: "rest", Filename: filepath.Join(dir, "sub", "gen_b.go")},
+	}, entries)
+}
+
+func TestReadManifestWithoutGenerators(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, ManifestFilename), []byte("# header\ngen_a.go\n"), 0644))
+
+	entries, err := ReadManifest(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, []ManifestEntry{{Filename: filepath.Join(dir, "gen_a.go")}}, entries)
+}
+
+func TestReadMissingManifest(t *testing.T) {
+	entries, err := ReadManifest(t.TempDir())
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestWriteManifestKeepsExistingStaleFiles(t *testing.T) {
+	dir := t.TempDir()
+	stale := filepath.Join(dir, "gen_stale.go")
+	assert.NoError(t, ioutil.WriteFile(stale, []byte("package x\n"), 0644))
+	assert.NoError(t, WriteManifest(dir, []ManifestEntry{
+		{Generator: "rest", Filename: stale},
+		{Generator: "rest", Filename: filepath.Join(dir, "gen_gone.go")},
+	}))
+
+	assert.NoError(t, WriteManifest(dir, []ManifestEntry{{Generator: "event", Filename: filepath.Join(dir, "gen_a.go")}}))
+
+	entries, err := ReadManifest(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, []ManifestEntry{
+		{Generator: "event", Filename: filepath.Join(dir, "gen_a.go")},
+		{Generator: "rest", Filename: stale},
+	}, entries)
+}
+
+func TestRemoveStaleFiles(t *testing.T) {
+	dir := t.TempDir()
+	current := filepath.Join(dir, "gen_current.go")
+	stale := filepath.Join(dir, "gen_stale.go")
+	for _, filename := range []string{current, stale} {
+		assert.NoError(t, ioutil.WriteFile(filename, []byte("package x\n"), 0644))
+	}
+	assert.NoError(t, WriteManifest(dir, []ManifestEntry{{Generator: "rest", Filename: current}, {Generator: "rest", Filename: stale}}))
+
+	removed, err := RemoveStaleFiles(dir, nil, []ManifestEntry{{Generator: "rest", Filename: current}})
+	assert.NoError(t, err)
+	assert.Equal(t, []ManifestEntry{{Generator: "rest", Filename: stale}}, removed)
+
+	_, err = os.Stat(stale)
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(current)
+	assert.NoError(t, err)
+
+	entries, err := ReadManifest(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, []ManifestEntry{{Generator: "rest", Filename: current}}, entries)
+}
+
+func TestRemoveStaleFilesOfGenerators(t *testing.T) {
+	dir := t.TempDir()
+	stale := filepath.Join(dir, "gen_stale.go")
+	other := filepath.Join(dir, "gen_other.go")
+	unowned := filepath.Join(dir, "gen_unowned.go")
+	for _, filename := range []string{stale, other, unowned} {
+		assert.NoError(t, ioutil.WriteFile(filename, []byte("package x\n"), 0644))
+	}
+	manifest := "# header\nrest\tgen_stale.go\nevent\tgen_other.go\ngen_unowned.go\n"
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, ManifestFilename), []byte(manifest), 0644))
+
+	removed, err := RemoveStaleFiles(dir, []string{"rest"}, []ManifestEntry{})
+	assert.NoError(t, err)
+	assert.Equal(t, []ManifestEntry{{Generator: "rest", Filename: stale}}, removed)
+
+	// the generators that did not run may still generate their files
+	for _, filename := range []string{other, unowned} {
+		_, err = os.Stat(filename)
+		assert.NoError(t, err)
+	}
+	entries, err := ReadManifest(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, []ManifestEntry{
+		{Generator: "event", Filename: other},
+		{Filename: unowned},
+	}, entries)
+}
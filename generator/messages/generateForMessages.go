@@ -0,0 +1,243 @@
+package messages
+
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/generator/messages/messageAnnotation"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+const (
+	defaultSource = "en"
+	defaultDir    = "i18n"
+)
+
+type messagesContext struct {
+	PackageName string
+	Source      string
+	Dir         string
+	Enums       []messageEnum
+}
+
+type messageEnum struct {
+	Name     string
+	Messages []message
+}
+
+// message is an entry of the message-files of go-i18n
+type message struct {
+	ID          string `json:"-"`
+	Hash        string `json:"hash,omitempty"`
+	Description string `json:"description,omitempty"`
+	Other       string `json:"other"`
+}
+
+// catalog holds the languages of the messages of a package, of which the texts of the annotations are in source
+type catalog struct {
+	source    string
+	dir       string
+	languages []string
+}
+
+type Generator struct {
+}
+
+func NewGenerator() generator.Generator {
+	return &Generator{}
+}
+
+func (eg *Generator) Name() string {
+	return "messages"
+}
+
+func (eg *Generator) GetTemplates() []generator.Template {
+	return []generator.Template{
+		{Name: "messages", APIVersion: 1, Text: messagesTemplate},
+	}
+}
+
+func (eg *Generator) GetAnnotations() []annotation.AnnotationDescriptor {
+	return messageAnnotation.Get()
+}
+
+func (eg *Generator) Generate(parsedSource model.ParsedSources, config generator.Config) ([]generator.OutputFile, error) {
+	return generate(config, parsedSource.Enums)
+}
+
+func generate(config generator.Config, enums []model.Enum) ([]generator.OutputFile, error) {
+	cat, messageEnums, err := getCatalog(enums)
+	if err != nil || len(messageEnums) == 0 {
+		return nil, err
+	}
+
+	packageName, err := generationUtil.GetPackageNameForEnumsOrStructs(enums, nil)
+	if packageName == "" || err != nil {
+		return nil, err
+	}
+	targetDir, err := generationUtil.DetermineTargetPath(config.InputDir, packageName)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []generator.OutputFile{}
+	file, err := generationUtil.Generate(generationUtil.Info{
+		Src:            packageName,
+		TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/messages.go", targetDir)),
+		TemplateName:   "messages",
+		TemplateString: messagesTemplate,
+		Config:         config,
+		FuncMap:        customTemplateFuncs,
+		Data: messagesContext{
+			PackageName: packageName,
+			Source:      cat.source,
+			Dir:         cat.dir,
+			Enums:       messageEnums,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Error generating messages:%w", err)
+	}
+	files = append(files, file)
+
+	dir := fmt.Sprintf("%s/%s", targetDir, cat.dir)
+	sources := map[string]message{}
+	for _, e := range messageEnums {
+		for _, m := range e.Messages {
+			sources[m.ID] = m
+		}
+	}
+	file, err = renderMessageFile(generationUtil.Prefixed(fmt.Sprintf("%s/active.%s.json", dir, cat.source)), packageName, sources)
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, file)
+
+	for _, lang := range cat.languages {
+		translated, err := readTranslations(fmt.Sprintf("%s/active.%s.json", dir, lang))
+		if err != nil {
+			return nil, err
+		}
+		untranslated := map[string]message{}
+		for id, m := range sources {
+			m.Hash = hash(m.Other)
+			if translatedHash, found := translated[id]; !found || (translatedHash != "" && translatedHash != m.Hash) {
+				untranslated[id] = m
+			}
+		}
+		file, err = renderMessageFile(generationUtil.Prefixed(fmt.Sprintf("%s/translate.%s.json", dir, lang)), packageName, untranslated)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+var customTemplateFuncs = template.FuncMap{
+	"Quoted": strconv.Quote,
+}
+
+// getCatalog returns the enums with messages and the languages of their catalog. The enums of a package share
+// a catalog, so their @MessageCatalog-annotations must agree on its source and dir.
+func getCatalog(enums []model.Enum) (catalog, []messageEnum, error) {
+	annotations := annotation.NewRegistry(messageAnnotation.Get())
+	cat := catalog{}
+	messageEnums := []messageEnum{}
+	languages := map[string]bool{}
+	for _, e := range enums {
+		messageEnum := messageEnum{Name: e.Name}
+		for _, literal := range e.EnumLiterals {
+			if ann, ok := annotations.ResolveAnnotationByName(literal.DocLines, messageAnnotation.TypeMessage); ok {
+				messageEnum.Messages = append(messageEnum.Messages, message{
+					ID:          literal.Name,
+					Description: ann.Attributes[messageAnnotation.ParamDescription],
+					Other:       ann.Attributes[messageAnnotation.ParamText],
+				})
+			}
+		}
+		ann, hasCatalog := annotations.ResolveAnnotationByName(e.DocLines, messageAnnotation.TypeMessageCatalog)
+		if len(messageEnum.Messages) == 0 {
+			if hasCatalog {
+				return catalog{}, nil, generator.EnumError(e, messageAnnotation.TypeMessageCatalog, "Enum %s with @MessageCatalog has no literals with @Message", e.Name)
+			}
+			continue
+		}
+		messageEnums = append(messageEnums, messageEnum)
+
+		source, dir := ann.Attributes[messageAnnotation.ParamSource], ann.Attributes[messageAnnotation.ParamDir]
+		if source == "" {
+			source = defaultSource
+		}
+		if dir == "" {
+			dir = defaultDir
+		}
+		if cat.source == "" {
+			cat.source, cat.dir = source, dir
+		} else if source != cat.source || dir != cat.dir {
+			return catalog{}, nil, generator.EnumError(e, messageAnnotation.TypeMessageCatalog,
+				"Enum %s has messages in %s in dir %s, where other enums of the package have them in %s in dir %s", e.Name, source, dir, cat.source, cat.dir)
+		}
+		for _, lang := range strings.Split(ann.Attributes[messageAnnotation.ParamLanguages], ",") {
+			lang = strings.TrimSpace(lang)
+			if lang != "" && lang != source && !languages[lang] {
+				languages[lang] = true
+				cat.languages = append(cat.languages, lang)
+			}
+		}
+	}
+	return cat, messageEnums, nil
+}
+
+// readTranslations returns the hashes of the source-texts of the messages in a message-file, by id: empty for the
+// messages that were translated without one
+func readTranslations(filename string) (map[string]string, error) {
+	data, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Error reading translations %s:%w", filename, err)
+	}
+	entries := map[string]json.RawMessage{}
+	err = json.Unmarshal(data, &entries)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing translations %s:%w", filename, err)
+	}
+	hashes := map[string]string{}
+	for id, entry := range entries {
+		translation := message{}
+		// a message is either an object or just its text
+		if json.Unmarshal(entry, &translation) != nil {
+			translation = message{}
+		}
+		hashes[id] = translation.Hash
+	}
+	return hashes, nil
+}
+
+func renderMessageFile(filename string, packageName string, messages map[string]message) (generator.OutputFile, error) {
+	marshalled, err := json.MarshalIndent(messages, "", "\t")
+	if err != nil {
+		return generator.OutputFile{}, fmt.Errorf("Error marshalling messages %s:%w", filename, err)
+	}
+	return generator.OutputFile{
+		Filename: filename,
+		Src:      packageName,
+		Content:  marshalled,
+	}, nil
+}
+
+// hash identifies the source-text that a message was translated from, so that a changed text is translated again
+func hash(text string) string {
+	return fmt.Sprintf("sha1-%x", sha1.Sum([]byte(text)))
+}
@@ -0,0 +1,171 @@
+package messages
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func cleanup() {
+	os.Remove(generationUtil.Prefixed("./testData/messages.go"))
+	os.RemoveAll("./testData/i18n")
+}
+
+func TestGenerateMessages(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	e := []model.Enum{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @MessageCatalog( languages = "nl,de" )`},
+			Name:        "ErrorCode",
+			EnumLiterals: []model.EnumLiteral{
+				{Name: "ErrorNone"},
+				{
+					DocLines: []string{`// @Message( text = "Person {{.UID}} was not found", description = "Lookup of a person" )`},
+					Name:     "ErrorPersonNotFound",
+				},
+				{
+					DocLines: []string{`// @Message( text = "Invalid input" )`},
+					Name:     "ErrorInvalidInput",
+				},
+			},
+		},
+	}
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Enums: e}, generator.Config{InputDir: "testData"})
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/messages.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "bundle := i18n.NewBundle(language.MustParse(\"en\"))\n")
+	assert.Contains(t, string(data), "var ErrorCodeMessages = map[ErrorCode]*i18n.Message{\n")
+	assert.Contains(t, string(data), `ErrorPersonNotFound: {ID: "ErrorPersonNotFound", Description: "Lookup of a person", Other: "Person {{.UID}} was not found"},`)
+	assert.Contains(t, string(data), `ErrorInvalidInput:   {ID: "ErrorInvalidInput", Other: "Invalid input"},`)
+	assert.NotContains(t, string(data), `"ErrorNone"`)
+	assert.Contains(t, string(data), "func (e ErrorCode) Localize(data interface{}, languages ...string) string {")
+	assert.Contains(t, string(data), "func (e ErrorCode) LocalizeForRequest(r *http.Request, data interface{}) string {")
+
+	data, err = ioutil.ReadFile(generationUtil.Prefixed("./testData/i18n/active.en.json"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "\"ErrorPersonNotFound\": {\n\t\t\"description\": \"Lookup of a person\",\n\t\t\"other\": \"Person {{.UID}} was not found\"\n\t}")
+
+	for _, lang := range []string{"nl", "de"} {
+		data, err = ioutil.ReadFile(generationUtil.Prefixed("./testData/i18n/translate." + lang + ".json"))
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), `"hash": "`+hash("Invalid input")+`"`)
+		assert.Contains(t, string(data), `"ErrorPersonNotFound": {`)
+	}
+}
+
+func TestGenerateMessagesLeavesTranslatedOut(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	os.MkdirAll("./testData/i18n", 0755)
+	translations := `{
+	"ErrorPersonNotFound": {"hash": "` + hash("Person {{.UID}} was not found") + `", "other": "Persoon {{.UID}} is niet gevonden"},
+	"ErrorInvalidInput": {"hash": "` + hash("Wrong input") + `", "other": "Verkeerde invoer"}
+}`
+	err := ioutil.WriteFile("./testData/i18n/active.nl.json", []byte(translations), 0644)
+	assert.NoError(t, err)
+
+	e := []model.Enum{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @MessageCatalog( languages = "nl" )`},
+			Name:        "ErrorCode",
+			EnumLiterals: []model.EnumLiteral{
+				{Name: "ErrorNone"},
+				{
+					DocLines: []string{`// @Message( text = "Person {{.UID}} was not found", description = "Lookup of a person" )`},
+					Name:     "ErrorPersonNotFound",
+				},
+				{
+					DocLines: []string{`// @Message( text = "Invalid input" )`},
+					Name:     "ErrorInvalidInput",
+				},
+			},
+		},
+	}
+	err = generator.Generate(NewGenerator(), model.ParsedSources{Enums: e}, generator.Config{InputDir: "testData"})
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/i18n/translate.nl.json"))
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "ErrorPersonNotFound")
+	// its source-text changed since it was translated
+	assert.Contains(t, string(data), `"ErrorInvalidInput": {`)
+}
+
+func TestGenerateMessagesWithoutCatalog(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	e := []model.Enum{
+		{
+			PackageName: "testData",
+			Name:        "ErrorCode",
+			EnumLiterals: []model.EnumLiteral{
+				{Name: "ErrorNone"},
+				{
+					DocLines: []string{`// @Message( text = "Person {{.UID}} was not found", description = "Lookup of a person" )`},
+					Name:     "ErrorPersonNotFound",
+				},
+				{
+					DocLines: []string{`// @Message( text = "Invalid input" )`},
+					Name:     "ErrorInvalidInput",
+				},
+			},
+		},
+	}
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Enums: e}, generator.Config{InputDir: "testData"})
+	assert.NoError(t, err)
+
+	_, err = os.Stat(generationUtil.Prefixed("./testData/i18n/active.en.json"))
+	assert.NoError(t, err)
+	_, err = os.Stat(generationUtil.Prefixed("./testData/i18n/translate.nl.json"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestGenerateMessagesForCatalogWithoutMessages(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	e := []model.Enum{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @MessageCatalog( languages = "nl" )`},
+			Name:        "ErrorCode",
+			EnumLiterals: []model.EnumLiteral{
+				{Name: "ErrorNone"},
+			},
+		},
+	}
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Enums: e}, generator.Config{InputDir: "testData"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Enum ErrorCode with @MessageCatalog has no literals with @Message")
+}
+
+func TestGenerateMessagesForPackageWithoutEnums(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			Name:        "Person",
+			Fields:      []model.Field{{Name: "UID", TypeName: "string"}},
+		},
+	}
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.NoError(t, err)
+
+	_, err = os.Stat(generationUtil.Prefixed("./testData/messages.go"))
+	assert.True(t, os.IsNotExist(err))
+}
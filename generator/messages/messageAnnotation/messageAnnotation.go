@@ -0,0 +1,59 @@
+package messageAnnotation
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+)
+
+const (
+	TypeMessage        = "Message"
+	TypeMessageCatalog = "MessageCatalog"
+	ParamText          = "text"
+	ParamDescription   = "description"
+	ParamLanguages     = "languages"
+	ParamSource        = "source"
+	ParamDir           = "dir"
+)
+
+func Get() []annotation.AnnotationDescriptor {
+	return []annotation.AnnotationDescriptor{
+		{
+			Name:       TypeMessage,
+			ParamNames: []string{ParamText, ParamDescription},
+			Validator:  validateMessageAnnotation,
+		},
+		{
+			Name:       TypeMessageCatalog,
+			ParamNames: []string{ParamLanguages, ParamSource, ParamDir},
+			Validator:  validateMessageAnnotation,
+		}}
+}
+
+func validateMessageAnnotation(annot annotation.Annotation) bool {
+	switch annot.Name {
+	case TypeMessage:
+		return annot.Attributes[ParamText] != ""
+	case TypeMessageCatalog:
+		if source, found := annot.Attributes[ParamSource]; found && !isLanguage(source) {
+			return false
+		}
+		if languages, found := annot.Attributes[ParamLanguages]; found {
+			for _, lang := range strings.Split(languages, ",") {
+				if !isLanguage(strings.TrimSpace(lang)) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// languageRegex matches BCP 47 language-tags like nl, en-US and zh-Hant
+var languageRegex = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{2,8})*$`)
+
+func isLanguage(tag string) bool {
+	return languageRegex.MatchString(tag)
+}
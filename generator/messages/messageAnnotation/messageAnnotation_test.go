@@ -0,0 +1,33 @@
+package messageAnnotation
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrectMessageAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	annotation, ok := registry.ResolveAnnotation(`// @Message( text = "Person {{.UID}} was not found", description = "Lookup of a person" )`)
+	assert.True(t, ok)
+	assert.Equal(t, TypeMessage, annotation.Name)
+	assert.Equal(t, "Person {{.UID}} was not found", annotation.Attributes[ParamText])
+	assert.Equal(t, "Lookup of a person", annotation.Attributes[ParamDescription])
+
+	annotation, ok = registry.ResolveAnnotation(`// @MessageCatalog( languages = "nl,de-CH", source = "en", dir = "locales" )`)
+	assert.True(t, ok)
+	assert.Equal(t, TypeMessageCatalog, annotation.Name)
+	assert.Equal(t, "nl,de-CH", annotation.Attributes[ParamLanguages])
+	assert.Equal(t, "en", annotation.Attributes[ParamSource])
+	assert.Equal(t, "locales", annotation.Attributes[ParamDir])
+}
+
+func TestInvalidMessageAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @Message( description = "Lookup of a person" )`}))
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @MessageCatalog( languages = "nl,dutch language" )`}))
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @MessageCatalog( source = "" )`}))
+}
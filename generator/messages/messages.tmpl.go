@@ -0,0 +1,91 @@
+package messages
+
+const messagesTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+
+package {{.PackageName}}
+
+import (
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"golang.org/x/text/language"
+)
+
+// MessageBundle holds the messages of the package in {{.Source}}, the language of their annotations, and the
+// translations that are loaded into it
+var MessageBundle = newMessageBundle()
+
+func newMessageBundle() *i18n.Bundle {
+	bundle := i18n.NewBundle(language.MustParse({{Quoted .Source}}))
+	bundle.RegisterUnmarshalFunc("json", json.Unmarshal)
+{{- range .Enums}}
+	for _, message := range {{.Name}}Messages {
+		bundle.MustAddMessages(language.MustParse({{Quoted $.Source}}), message)
+	}
+{{- end}}
+	return bundle
+}
+
+// LoadMessageFiles loads the translated messages of the active.<language>.json-files in dir, like {{Quoted .Dir}}, into
+// MessageBundle
+func LoadMessageFiles(dir string) error {
+	filenames, err := filepath.Glob(filepath.Join(dir, "active.*.json"))
+	if err != nil {
+		return err
+	}
+	for _, filename := range filenames {
+		_, err = MessageBundle.LoadMessageFile(filename)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// localizeMessage returns message in the first of the languages that it is translated in, falling back to {{.Source}}
+func localizeMessage(message *i18n.Message, data interface{}, languages ...string) string {
+	text, err := i18n.NewLocalizer(MessageBundle, languages...).Localize(&i18n.LocalizeConfig{
+		DefaultMessage: message,
+		TemplateData:   data,
+	})
+	if err != nil && text == "" {
+		return message.Other
+	}
+	return text
+}
+{{range .Enums}}
+{{$enum := .Name -}}
+// {{$enum}}Messages are the messages of the {{$enum}}-constants in {{$.Source}}
+var {{$enum}}Messages = map[{{$enum}}]*i18n.Message{
+{{- range .Messages}}
+	{{.ID}}: {ID: {{Quoted .ID}}{{if .Description}}, Description: {{Quoted .Description}}{{end}}, Other: {{Quoted .Other}}},
+{{- end}}
+}
+
+// MessageID returns the id of the message of the constant in the message-files: empty when it has no message
+func (e {{$enum}}) MessageID() string {
+	if message, found := {{$enum}}Messages[e]; found {
+		return message.ID
+	}
+	return ""
+}
+
+// Localize returns the message of the constant in the first of the languages that it is translated in, like the
+// value of an Accept-Language header, falling back to {{$.Source}}. Data fills in the fields of the message.
+func (e {{$enum}}) Localize(data interface{}, languages ...string) string {
+	message, found := {{$enum}}Messages[e]
+	if !found {
+		return ""
+	}
+	return localizeMessage(message, data, languages...)
+}
+
+// LocalizeForRequest returns the message of the constant in the language that the request accepts, for the
+// error-response to it
+func (e {{$enum}}) LocalizeForRequest(r *http.Request, data interface{}) string {
+	return e.Localize(data, r.Header.Get("Accept-Language"))
+}
+{{end -}}
+`
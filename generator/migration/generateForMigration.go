@@ -0,0 +1,189 @@
+package migration
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/MarcGrol/golangAnnotations/generator/entity/entityAnnotation"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+const (
+	migrationsDir          = "migrations"
+	schemaSnapshotFilename = "schema.json"
+)
+
+var migrationFilenameRegex = regexp.MustCompile(`^([0-9]+)_.*\.(up|down)\.sql$`)
+
+type migrationContext struct {
+	Statements []string
+}
+
+type Generator struct {
+}
+
+func NewGenerator() generator.Generator {
+	return &Generator{}
+}
+
+func (eg *Generator) Name() string {
+	return "migration"
+}
+
+func (eg *Generator) GetTemplates() []generator.Template {
+	return []generator.Template{
+		{Name: "migration", APIVersion: 1, Text: migrationTemplate},
+	}
+}
+
+func (eg *Generator) GetAnnotations() []annotation.AnnotationDescriptor {
+	return entityAnnotation.Get()
+}
+
+func (eg *Generator) Generate(parsedSource model.ParsedSources, config generator.Config) ([]generator.OutputFile, error) {
+	return generate(config, parsedSource.Structs)
+}
+
+func generate(config generator.Config, structs []model.Struct) ([]generator.OutputFile, error) {
+	packageName, err := generationUtil.GetPackageNameForStructs(structs)
+	if packageName == "" || err != nil {
+		return nil, err
+	}
+	targetDir, err := generationUtil.DetermineTargetPath(config.InputDir, packageName)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []generator.OutputFile{}
+
+	current, err := GetSchema(structs)
+	if err != nil {
+		return nil, err
+	}
+	if len(current.Tables) == 0 {
+		return files, nil
+	}
+
+	dir := fmt.Sprintf("%s/%s", targetDir, migrationsDir)
+	snapshotFilename := generationUtil.Prefixed(fmt.Sprintf("%s/%s", dir, schemaSnapshotFilename))
+	previous, err := readSnapshot(snapshotFilename)
+	if err != nil {
+		return nil, err
+	}
+
+	up, down := Diff(previous, current)
+	if len(up) == 0 {
+		return files, nil
+	}
+
+	version, err := nextVersion(dir)
+	if err != nil {
+		return nil, err
+	}
+	baseFilename := fmt.Sprintf("%s/%06d_%s", dir, version, migrationTitle(previous, current))
+
+	for _, m := range []struct {
+		filename   string
+		statements []string
+	}{
+		{filename: baseFilename + ".up.sql", statements: up},
+		{filename: baseFilename + ".down.sql", statements: down},
+	} {
+		file, err := generationUtil.Generate(generationUtil.Info{
+			Src:            fmt.Sprintf("%s.entities", packageName),
+			TargetFilename: m.filename,
+			TemplateName:   "migration",
+			TemplateString: migrationTemplate,
+			Config:         config,
+			FuncMap:        template.FuncMap{},
+			Data: migrationContext{
+				Statements: m.statements,
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Error generating migration %s:%w", m.filename, err)
+		}
+		// the next run derives its version from the existing migrations
+		file.Fixed = true
+		files = append(files, file)
+	}
+
+	snapshot, err := renderSnapshot(snapshotFilename, packageName, current)
+	if err != nil {
+		return nil, err
+	}
+	return append(files, snapshot), nil
+}
+
+func readSnapshot(filename string) (Schema, error) {
+	data, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return Schema{}, nil
+	}
+	if err != nil {
+		return Schema{}, fmt.Errorf("Error reading schema-snapshot %s:%w", filename, err)
+	}
+	schema := Schema{}
+	err = json.Unmarshal(data, &schema)
+	if err != nil {
+		return Schema{}, fmt.Errorf("Error parsing schema-snapshot %s:%w", filename, err)
+	}
+	return schema, nil
+}
+
+func renderSnapshot(filename string, packageName string, schema Schema) (generator.OutputFile, error) {
+	marshalled, err := json.MarshalIndent(schema, "", "\t")
+	if err != nil {
+		return generator.OutputFile{}, fmt.Errorf("Error marshalling schema-snapshot:%w", err)
+	}
+	return generator.OutputFile{
+		Filename: filename,
+		Src:      fmt.Sprintf("%s.entities", packageName),
+		Fixed:    true,
+		Content:  marshalled,
+	}, nil
+}
+
+// nextVersion returns the sequence-number that follows the highest existing migration in dir
+func nextVersion(dir string) (int, error) {
+	files, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return 1, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("Error reading migrations-dir %s:%w", dir, err)
+	}
+	highest := 0
+	for _, f := range files {
+		match := migrationFilenameRegex.FindStringSubmatch(f.Name())
+		if match == nil {
+			continue
+		}
+		if version, err := strconv.Atoi(match[1]); err == nil && version > highest {
+			highest = version
+		}
+	}
+	return highest + 1, nil
+}
+
+func migrationTitle(previous Schema, current Schema) string {
+	created := []string{}
+	for _, t := range current.Tables {
+		if _, exists := previous.findTable(t.Name); !exists {
+			created = append(created, t.Name)
+		}
+	}
+	if len(previous.Tables) == 0 {
+		return "create_" + strings.Join(created, "_")
+	}
+	return "alter_schema"
+}
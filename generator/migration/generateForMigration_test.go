@@ -0,0 +1,98 @@
+package migration
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/entity/entityAnnotation"
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func cleanup() {
+	os.RemoveAll("./testData/migrations")
+}
+
+func personEntity(fields ...model.Field) []model.Struct {
+	return []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @Entity( table = "persons" )`},
+			Name:        "Person",
+			Fields: append([]model.Field{
+				{Name: "ID", TypeName: "int64", DocLines: []string{`// @Id( auto = "true" )`}},
+				{Name: "FirstName", TypeName: "string"},
+			}, fields...),
+		},
+	}
+}
+
+func TestGenerateForMigration(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: personEntity()}, generator.Config{InputDir: "testData"})
+	assert.Nil(t, err)
+
+	data, err := ioutil.ReadFile("./testData/migrations/000001_create_persons.up.sql")
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `CREATE TABLE persons (
+	id BIGSERIAL NOT NULL,
+	first_name TEXT NOT NULL,
+	PRIMARY KEY (id)
+);`)
+
+	data, err = ioutil.ReadFile("./testData/migrations/000001_create_persons.down.sql")
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `DROP TABLE persons;`)
+
+	_, err = os.Stat("./testData/migrations/gen_schema.json")
+	assert.NoError(t, err)
+
+	// unchanged entities: no new migration
+	err = generator.Generate(NewGenerator(), model.ParsedSources{Structs: personEntity()}, generator.Config{InputDir: "testData"})
+	assert.Nil(t, err)
+	files, err := ioutil.ReadDir("./testData/migrations")
+	assert.NoError(t, err)
+	assert.Len(t, files, 3)
+
+	// drift: a column was added
+	err = generator.Generate(NewGenerator(), model.ParsedSources{Structs: personEntity(model.Field{Name: "Email", TypeName: "*string"})}, generator.Config{InputDir: "testData"})
+	assert.Nil(t, err)
+
+	data, err = ioutil.ReadFile("./testData/migrations/000002_alter_schema.up.sql")
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `ALTER TABLE persons ADD COLUMN email TEXT;`)
+
+	data, err = ioutil.ReadFile("./testData/migrations/000002_alter_schema.down.sql")
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `ALTER TABLE persons DROP COLUMN email;`)
+}
+
+func TestDiffMySQL(t *testing.T) {
+	previous := Schema{
+		Dialect: entityAnnotation.DialectMySQL,
+		Tables: []Table{
+			{Name: "persons", Columns: []SchemaColumn{{Name: "id", Type: "BIGINT AUTO_INCREMENT", PrimaryKey: true}, {Name: "age", Type: "INT"}}},
+			{Name: "obsolete", Columns: []SchemaColumn{{Name: "id", Type: "BIGINT", PrimaryKey: true}}},
+		},
+	}
+	current := Schema{
+		Dialect: entityAnnotation.DialectMySQL,
+		Tables: []Table{
+			{Name: "persons", Columns: []SchemaColumn{{Name: "id", Type: "BIGINT AUTO_INCREMENT", PrimaryKey: true}, {Name: "age", Type: "BIGINT", Nullable: true}}},
+		},
+	}
+
+	up, down := Diff(previous, current)
+	assert.Equal(t, []string{
+		"ALTER TABLE persons MODIFY COLUMN age BIGINT",
+		"DROP TABLE obsolete",
+	}, up)
+	assert.Equal(t, []string{
+		"CREATE TABLE obsolete (\n\tid BIGINT NOT NULL,\n\tPRIMARY KEY (id)\n)",
+		"ALTER TABLE persons MODIFY COLUMN age INT NOT NULL",
+	}, down)
+}
@@ -0,0 +1,7 @@
+package migration
+
+const migrationTemplate = `-- Generated automatically by golangAnnotations: do not edit manually
+{{range .Statements}}
+{{.}};
+{{end -}}
+`
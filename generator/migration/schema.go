@@ -0,0 +1,223 @@
+package migration
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/entity"
+	"github.com/MarcGrol/golangAnnotations/generator/entity/entityAnnotation"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+// Schema is the snapshot of all tables of a package: it is stored alongside the migrations
+// so that the next run can detect drift between the entities and the generated migrations
+type Schema struct {
+	Dialect string  `json:"dialect"`
+	Tables  []Table `json:"tables"`
+}
+
+type Table struct {
+	Name    string         `json:"name"`
+	Columns []SchemaColumn `json:"columns"`
+}
+
+type SchemaColumn struct {
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	Nullable   bool   `json:"nullable,omitempty"`
+	PrimaryKey bool   `json:"primaryKey,omitempty"`
+}
+
+func (t Table) findColumn(name string) (SchemaColumn, bool) {
+	for _, c := range t.Columns {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return SchemaColumn{}, false
+}
+
+func (s Schema) findTable(name string) (Table, bool) {
+	for _, t := range s.Tables {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return Table{}, false
+}
+
+// GetSchema derives the schema of all @Entity structs
+func GetSchema(structs []model.Struct) (Schema, error) {
+	schema := Schema{
+		Tables: []Table{},
+	}
+	for _, s := range structs {
+		if !entity.IsEntity(s) {
+			continue
+		}
+		dialect := entity.GetDialect(s)
+		if schema.Dialect == "" {
+			schema.Dialect = dialect
+		} else if schema.Dialect != dialect {
+			return Schema{}, generator.StructError(s, entityAnnotation.TypeEntity, "Entity %s uses dialect %s where other entities use %s", s.Name, dialect, schema.Dialect)
+		}
+		if _, found := entity.GetIDColumn(s); !found {
+			return Schema{}, generator.StructError(s, entityAnnotation.TypeEntity, "Entity %s has no field annotated with @Id", s.Name)
+		}
+
+		table := Table{
+			Name:    entity.GetTableName(s),
+			Columns: []SchemaColumn{},
+		}
+		for _, c := range entity.GetColumns(s) {
+			table.Columns = append(table.Columns, SchemaColumn{
+				Name:       c.Name,
+				Type:       SQLType(dialect, c),
+				Nullable:   c.IsNullable && !c.IsID,
+				PrimaryKey: c.IsID,
+			})
+		}
+		schema.Tables = append(schema.Tables, table)
+	}
+	return schema, nil
+}
+
+// SQLType maps the go-type of a column onto the column-type of the dialect, unless explicitly specified
+func SQLType(dialect string, c entity.Column) string {
+	if c.SQLType != "" {
+		return c.SQLType
+	}
+	typeName := c.Field.DereferencedTypeName()
+	if dialect == entityAnnotation.DialectMySQL {
+		sqlType := map[string]string{
+			"string":    "VARCHAR(255)",
+			"bool":      "BOOLEAN",
+			"int":       "BIGINT",
+			"int32":     "INT",
+			"int64":     "BIGINT",
+			"float32":   "FLOAT",
+			"float64":   "DOUBLE",
+			"time.Time": "DATETIME(6)",
+		}[typeName]
+		if sqlType == "" {
+			sqlType = "JSON"
+		}
+		if c.IsAuto {
+			sqlType += " AUTO_INCREMENT"
+		}
+		return sqlType
+	}
+
+	if c.IsAuto {
+		if typeName == "int32" {
+			return "SERIAL"
+		}
+		return "BIGSERIAL"
+	}
+	sqlType := map[string]string{
+		"string":    "TEXT",
+		"bool":      "BOOLEAN",
+		"int":       "BIGINT",
+		"int32":     "INTEGER",
+		"int64":     "BIGINT",
+		"float32":   "REAL",
+		"float64":   "DOUBLE PRECISION",
+		"time.Time": "TIMESTAMP WITH TIME ZONE",
+	}[typeName]
+	if sqlType == "" {
+		sqlType = "JSONB"
+	}
+	return sqlType
+}
+
+// Diff returns the up- and down-statements needed to migrate from the previous to the current schema
+func Diff(previous Schema, current Schema) ([]string, []string) {
+	up := []string{}
+	down := []string{}
+
+	for _, table := range current.Tables {
+		old, exists := previous.findTable(table.Name)
+		if !exists {
+			up = append(up, createTable(table))
+			down = append([]string{dropTable(table)}, down...)
+			continue
+		}
+		for _, column := range table.Columns {
+			oldColumn, exists := old.findColumn(column.Name)
+			if !exists {
+				up = append(up, addColumn(table, column))
+				down = append([]string{dropColumn(table, column)}, down...)
+			} else if oldColumn != column {
+				up = append(up, alterColumn(current.Dialect, table, oldColumn, column)...)
+				down = append(alterColumn(current.Dialect, table, column, oldColumn), down...)
+			}
+		}
+		for _, oldColumn := range old.Columns {
+			if _, exists := table.findColumn(oldColumn.Name); !exists {
+				up = append(up, dropColumn(table, oldColumn))
+				down = append([]string{addColumn(table, oldColumn)}, down...)
+			}
+		}
+	}
+
+	for _, old := range previous.Tables {
+		if _, exists := current.findTable(old.Name); !exists {
+			up = append(up, dropTable(old))
+			down = append([]string{createTable(old)}, down...)
+		}
+	}
+	return up, down
+}
+
+func columnDefinition(c SchemaColumn) string {
+	if c.Nullable {
+		return fmt.Sprintf("%s %s", c.Name, c.Type)
+	}
+	return fmt.Sprintf("%s %s NOT NULL", c.Name, c.Type)
+}
+
+func createTable(t Table) string {
+	lines := []string{}
+	primaryKeys := []string{}
+	for _, c := range t.Columns {
+		lines = append(lines, "\t"+columnDefinition(c))
+		if c.PrimaryKey {
+			primaryKeys = append(primaryKeys, c.Name)
+		}
+	}
+	if len(primaryKeys) > 0 {
+		lines = append(lines, fmt.Sprintf("\tPRIMARY KEY (%s)", strings.Join(primaryKeys, ", ")))
+	}
+	return fmt.Sprintf("CREATE TABLE %s (\n%s\n)", t.Name, strings.Join(lines, ",\n"))
+}
+
+func dropTable(t Table) string {
+	return fmt.Sprintf("DROP TABLE %s", t.Name)
+}
+
+func addColumn(t Table, c SchemaColumn) string {
+	return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", t.Name, columnDefinition(c))
+}
+
+func dropColumn(t Table, c SchemaColumn) string {
+	return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", t.Name, c.Name)
+}
+
+func alterColumn(dialect string, t Table, from SchemaColumn, to SchemaColumn) []string {
+	if dialect == entityAnnotation.DialectMySQL {
+		return []string{fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s", t.Name, columnDefinition(to))}
+	}
+	statements := []string{}
+	if from.Type != to.Type {
+		statements = append(statements, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE %s", t.Name, to.Name, to.Type))
+	}
+	if from.Nullable != to.Nullable {
+		if to.Nullable {
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP NOT NULL", t.Name, to.Name))
+		} else {
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET NOT NULL", t.Name, to.Name))
+		}
+	}
+	return statements
+}
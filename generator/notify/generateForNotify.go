@@ -0,0 +1,190 @@
+package notify
+
+import (
+	"fmt"
+	"strconv"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/MarcGrol/golangAnnotations/generator/event"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/generator/notify/notifyAnnotation"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+type notificationsContext struct {
+	PackageName string
+	Events      []eventNotifications
+}
+
+// eventNotifications are the notifications of an event, in the order of their annotations
+type eventNotifications struct {
+	Name          string
+	Notifications []notification
+}
+
+// notification is a @Notify of an event, with the templates of its payload
+type notification struct {
+	Channel string
+	To      string
+	Subject string
+	Body    string
+	URL     string
+}
+
+type Generator struct {
+}
+
+func NewGenerator() generator.Generator {
+	return &Generator{}
+}
+
+func (eg *Generator) Name() string {
+	return "notify"
+}
+
+func (eg *Generator) GetTemplates() []generator.Template {
+	return []generator.Template{
+		{Name: "notifications", APIVersion: 1, Text: notificationsTemplate},
+	}
+}
+
+func (eg *Generator) GetAnnotations() []annotation.AnnotationDescriptor {
+	return notifyAnnotation.Get()
+}
+
+func (eg *Generator) Generate(parsedSource model.ParsedSources, config generator.Config) ([]generator.OutputFile, error) {
+	return generate(config, parsedSource.Structs)
+}
+
+func generate(config generator.Config, structs []model.Struct) ([]generator.OutputFile, error) {
+	packageName, err := generationUtil.GetPackageNameForStructs(structs)
+	if packageName == "" || err != nil {
+		return nil, err
+	}
+	targetDir, err := generationUtil.DetermineTargetPath(config.InputDir, packageName)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := notificationsContext{PackageName: packageName}
+	registry := annotation.NewRegistry(notifyAnnotation.Get())
+	for _, s := range structs {
+		// an event can be notified on several channels
+		e := eventNotifications{Name: s.Name}
+		for _, ann := range registry.ResolveAnnotations(s.DocLines) {
+			if ann.Name != notifyAnnotation.TypeNotify {
+				continue
+			}
+			if !event.IsEvent(s) {
+				return nil, generator.StructError(s, notifyAnnotation.TypeNotify, "Struct %s with @Notify must be an @Event", s.Name)
+			}
+			n := notification{
+				Channel: ann.Attributes[notifyAnnotation.ParamChannel],
+				To:      ann.Attributes[notifyAnnotation.ParamTo],
+				Subject: ann.Attributes[notifyAnnotation.ParamSubject],
+				Body:    ann.Attributes[notifyAnnotation.ParamBody],
+				URL:     ann.Attributes[notifyAnnotation.ParamURL],
+			}
+			for _, param := range []string{notifyAnnotation.ParamTo, notifyAnnotation.ParamSubject, notifyAnnotation.ParamBody, notifyAnnotation.ParamURL} {
+				err := validatePayloadTemplate(s, ann.Attributes[param])
+				if err != nil {
+					return nil, generator.StructError(s, notifyAnnotation.TypeNotify, "The %s of the %s-notification of event %s is an invalid template: %s", param, n.Channel, s.Name, err)
+				}
+			}
+			e.Notifications = append(e.Notifications, n)
+		}
+		if len(e.Notifications) > 0 {
+			ctx.Events = append(ctx.Events, e)
+		}
+	}
+	if len(ctx.Events) == 0 {
+		return nil, nil
+	}
+
+	file, err := generationUtil.Generate(generationUtil.Info{
+		Src:            packageName,
+		TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/notifications.go", targetDir)),
+		TemplateName:   "notifications",
+		TemplateString: notificationsTemplate,
+		Config:         config,
+		FuncMap:        customTemplateFuncs,
+		Data:           ctx,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Error generating notifications for package %s:%w", packageName, err)
+	}
+	return []generator.OutputFile{file}, nil
+}
+
+var customTemplateFuncs = template.FuncMap{
+	"Quote":          strconv.Quote,
+	"LowerCamelCase": generationUtil.LowerCamelCase,
+}
+
+// validatePayloadTemplate parses a template of a notification, and checks that the fields it uses are fields of the
+// event. Fields in range- and with-blocks have another dot and are left alone, like the fields of events with
+// embedded structs.
+func validatePayloadTemplate(s model.Struct, text string) error {
+	tmpl, err := template.New(s.Name).Parse(text)
+	if err != nil {
+		return err
+	}
+	fields := map[string]bool{}
+	for _, f := range s.Fields {
+		if f.IsEmbedded {
+			return nil
+		}
+		fields[f.Name] = true
+	}
+	if tmpl.Tree == nil {
+		return nil
+	}
+	return validateFields(tmpl.Tree.Root, fields)
+}
+
+func validateFields(node parse.Node, fields map[string]bool) error {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, child := range n.Nodes {
+			if err := validateFields(child, fields); err != nil {
+				return err
+			}
+		}
+	case *parse.ActionNode:
+		return validateFields(n.Pipe, fields)
+	case *parse.PipeNode:
+		if n == nil {
+			return nil
+		}
+		for _, cmd := range n.Cmds {
+			for _, arg := range cmd.Args {
+				if err := validateFields(arg, fields); err != nil {
+					return err
+				}
+			}
+		}
+	case *parse.FieldNode:
+		if !fields[n.Ident[0]] {
+			return fmt.Errorf("%s is no field of the event", n)
+		}
+	case *parse.IfNode:
+		if err := validateFields(n.Pipe, fields); err != nil {
+			return err
+		}
+		if err := validateFields(n.List, fields); err != nil {
+			return err
+		}
+		return validateFields(n.ElseList, fields)
+	case *parse.RangeNode:
+		return validateFields(n.Pipe, fields)
+	case *parse.WithNode:
+		return validateFields(n.Pipe, fields)
+	}
+	return nil
+}
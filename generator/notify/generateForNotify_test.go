@@ -0,0 +1,161 @@
+package notify
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func cleanup() {
+	os.Remove(generationUtil.Prefixed("./testData/notifications.go"))
+	os.Remove("./testData")
+}
+
+func TestGenerateForNotify(t *testing.T) {
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines: []string{
+				`// @Event( aggregate = "Order" )`,
+				`// @Notify( channel = "email", to = "{{.CustomerEmail}}", subject = "Order {{.OrderUID}} shipped", body = "Your order is on its way" )`,
+				`// @Notify( channel = "sms", to = "{{.CustomerPhone}}", body = "Order {{.OrderUID}} shipped" )`,
+				`// @Notify( channel = "webhook", url = "https://hooks.example.com/orders" )`,
+			},
+			Name: "OrderShipped",
+			Fields: []model.Field{
+				{Name: "OrderUID", TypeName: "string"},
+				{Name: "CustomerEmail", TypeName: "string"},
+				{Name: "CustomerPhone", TypeName: "string"},
+			},
+		},
+		{
+			PackageName: "testData",
+			DocLines: []string{
+				`// @Event( aggregate = "Order" )`,
+			},
+			Name: "OrderCreated",
+		},
+	}
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Nil(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/notifications.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "type EmailChannel interface {")
+	assert.Contains(t, string(data), "type SMSChannel interface {")
+	assert.Contains(t, string(data), "type WebhookChannel interface {")
+	assert.Contains(t, string(data), `newNotificationTemplate("OrderShipped", "email", "{{.CustomerEmail}}", "Order {{.OrderUID}} shipped", "Your order is on its way", ""),`)
+	assert.Contains(t, string(data), `newNotificationTemplate("OrderShipped", "sms", "{{.CustomerPhone}}", "", "Order {{.OrderUID}} shipped", ""),`)
+	assert.Contains(t, string(data), `newNotificationTemplate("OrderShipped", "webhook", "", "", "", "https://hooks.example.com/orders"),`)
+	assert.Contains(t, string(data), "func NotifyOrderShipped(c context.Context, channels NotificationChannels, evt OrderShipped) error {")
+	assert.Contains(t, string(data), "case *OrderShipped:")
+	assert.Contains(t, string(data), "func NewHTTPWebhookChannel(client *http.Client) WebhookChannel {")
+	assert.NotContains(t, string(data), "NotifyOrderCreated")
+}
+
+func TestGenerateForNoNotify(t *testing.T) {
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines: []string{
+				`// @Event( aggregate = "Order" )`,
+			},
+			Name: "OrderShipped",
+			Fields: []model.Field{
+				{Name: "OrderUID", TypeName: "string"},
+				{Name: "CustomerEmail", TypeName: "string"},
+				{Name: "CustomerPhone", TypeName: "string"},
+			},
+		},
+	}
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Nil(t, err)
+
+	_, err = os.Stat(generationUtil.Prefixed("./testData/notifications.go"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestGenerateForNotifyWithEmbeddedEvent(t *testing.T) {
+	defer cleanup()
+
+	// the fields of an embedded struct are unknown
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines: []string{
+				`// @Event( aggregate = "Order" )`,
+				`// @Notify( channel = "sms", to = "{{.Phone}}", body = "Shipped" )`,
+			},
+			Name: "OrderShipped",
+			Fields: []model.Field{
+				{Name: "OrderUID", TypeName: "string"},
+				{TypeName: "Customer", IsEmbedded: true},
+			},
+		},
+	}
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Nil(t, err)
+}
+
+func TestGenerateForInvalidNotify(t *testing.T) {
+	for notify, message := range map[string]string{
+		`// @Notify( channel = "sms", to = "{{.CustomerPhone", body = "Shipped" )`:                        "The to of the sms-notification of event OrderShipped is an invalid template",
+		`// @Notify( channel = "sms", to = "{{.Phone}}", body = "Shipped" )`:                              "The to of the sms-notification of event OrderShipped is an invalid template: .Phone is no field of the event",
+		`// @Notify( channel = "webhook", url = "https://example.com", body = "{{if .Amount}}x{{end}}" )`: "The body of the webhook-notification of event OrderShipped is an invalid template: .Amount is no field of the event",
+	} {
+		t.Run(notify, func(t *testing.T) {
+			defer cleanup()
+
+			s := []model.Struct{
+				{
+					PackageName: "testData",
+					DocLines: []string{
+						`// @Event( aggregate = "Order" )`,
+						notify,
+					},
+					Name: "OrderShipped",
+					Fields: []model.Field{
+						{Name: "OrderUID", TypeName: "string"},
+						{Name: "CustomerPhone", TypeName: "string"},
+					},
+				},
+			}
+			err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), message)
+			}
+		})
+	}
+}
+
+func TestGenerateForNotifyOnNoEvent(t *testing.T) {
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines: []string{
+				`// @Notify( channel = "email", to = "{{.CustomerEmail}}", subject = "Shipped", body = "Shipped" )`,
+			},
+			Name: "OrderShipped",
+			Fields: []model.Field{
+				{Name: "OrderUID", TypeName: "string"},
+				{Name: "CustomerEmail", TypeName: "string"},
+				{Name: "CustomerPhone", TypeName: "string"},
+			},
+		},
+	}
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "Struct OrderShipped with @Notify must be an @Event")
+	}
+}
@@ -0,0 +1,238 @@
+package notify
+
+const notificationsTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+
+package {{.PackageName}}
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"text/template"
+)
+
+// EmailNotification is an email about an event
+type EmailNotification struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// SMSNotification is a text-message about an event
+type SMSNotification struct {
+	To   string
+	Body string
+}
+
+// WebhookNotification is a json-body about an event, that is posted to a url
+type WebhookNotification struct {
+	URL  string
+	Body string
+}
+
+// EmailChannel sends email-notifications, like with smtp or the api of a mail-provider
+type EmailChannel interface {
+	SendEmail(c context.Context, notification EmailNotification) error
+}
+
+// SMSChannel sends sms-notifications, like with the api of an sms-provider
+type SMSChannel interface {
+	SendSMS(c context.Context, notification SMSNotification) error
+}
+
+// WebhookChannel sends webhook-notifications: NewHTTPWebhookChannel posts them over http
+type WebhookChannel interface {
+	SendWebhook(c context.Context, notification WebhookNotification) error
+}
+
+// NotificationChannels are the channels that notifications are sent on: the notifications of a channel that is nil
+// are skipped
+type NotificationChannels struct {
+	Email   EmailChannel
+	SMS     SMSChannel
+	Webhook WebhookChannel
+}
+
+// NotificationError tells which notifications of an event could not be sent: the other ones were sent
+type NotificationError struct {
+	Event  string
+	Errors []error
+}
+
+func (err *NotificationError) Error() string {
+	messages := []string{}
+	for _, e := range err.Errors {
+		messages = append(messages, e.Error())
+	}
+	return fmt.Sprintf("Error notifying event %s: %s", err.Event, strings.Join(messages, "; "))
+}
+
+// notificationTemplate is a @Notify of an event, with the templates that fill its notifications with the event
+type notificationTemplate struct {
+	channel string
+	to      *template.Template
+	subject *template.Template
+	body    *template.Template
+	url     *template.Template
+}
+
+func newNotificationTemplate(event, channel, to, subject, body, url string) notificationTemplate {
+	return notificationTemplate{
+		channel: channel,
+		to:      parseNotificationTemplate(event, "to", to),
+		subject: parseNotificationTemplate(event, "subject", subject),
+		body:    parseNotificationTemplate(event, "body", body),
+		url:     parseNotificationTemplate(event, "url", url),
+	}
+}
+
+func parseNotificationTemplate(event, param, text string) *template.Template {
+	if text == "" {
+		return nil
+	}
+	return template.Must(template.New(event + "." + param).Option("missingkey=error").Parse(text))
+}
+{{range $event := .Events}}
+var {{LowerCamelCase .Name}}Notifications = []notificationTemplate{
+{{- range .Notifications}}
+	newNotificationTemplate("{{$event.Name}}", "{{.Channel}}", {{Quote .To}}, {{Quote .Subject}}, {{Quote .Body}}, {{Quote .URL}}),
+{{- end}}
+}
+
+// Notify{{.Name}} sends the notifications of event {{.Name}} on their channels
+func Notify{{.Name}}(c context.Context, channels NotificationChannels, evt {{.Name}}) error {
+	return notify(c, channels, "{{.Name}}", {{LowerCamelCase .Name}}Notifications, evt)
+}
+{{end}}
+// NotifyEvent sends the notifications of an event of package {{.PackageName}}, given as value or pointer: events
+// without notifications are ignored
+func NotifyEvent(c context.Context, channels NotificationChannels, evt interface{}) error {
+	switch e := evt.(type) {
+{{- range .Events}}
+	case {{.Name}}:
+		return Notify{{.Name}}(c, channels, e)
+	case *{{.Name}}:
+		return Notify{{.Name}}(c, channels, *e)
+{{- end}}
+	}
+	return nil
+}
+
+// notify sends all notifications of an event, also when some of them fail
+func notify(c context.Context, channels NotificationChannels, event string, templates []notificationTemplate, evt interface{}) error {
+	errs := []error{}
+	for _, t := range templates {
+		err := t.send(c, channels, evt)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return &NotificationError{Event: event, Errors: errs}
+	}
+	return nil
+}
+
+func (t notificationTemplate) send(c context.Context, channels NotificationChannels, evt interface{}) error {
+	var err error
+	switch t.channel {
+	case "email":
+		if channels.Email == nil {
+			return nil
+		}
+		n := EmailNotification{}
+		if n.To, err = execute(t.to, evt); err != nil {
+			return err
+		}
+		if n.Subject, err = execute(t.subject, evt); err != nil {
+			return err
+		}
+		if n.Body, err = execute(t.body, evt); err != nil {
+			return err
+		}
+		err = channels.Email.SendEmail(c, n)
+	case "sms":
+		if channels.SMS == nil {
+			return nil
+		}
+		n := SMSNotification{}
+		if n.To, err = execute(t.to, evt); err != nil {
+			return err
+		}
+		if n.Body, err = execute(t.body, evt); err != nil {
+			return err
+		}
+		err = channels.SMS.SendSMS(c, n)
+	case "webhook":
+		if channels.Webhook == nil {
+			return nil
+		}
+		n := WebhookNotification{}
+		if n.URL, err = execute(t.url, evt); err != nil {
+			return err
+		}
+		if n.Body, err = execute(t.body, evt); err != nil {
+			return err
+		}
+		if t.body == nil {
+			// without a body the event itself is posted
+			body, err := json.Marshal(evt)
+			if err != nil {
+				return fmt.Errorf("Error marshalling body of webhook-notification: %w", err)
+			}
+			n.Body = string(body)
+		}
+		err = channels.Webhook.SendWebhook(c, n)
+	}
+	if err != nil {
+		return fmt.Errorf("Error sending %s-notification: %w", t.channel, err)
+	}
+	return nil
+}
+
+// execute fills a template with an event: a template that is nil is empty
+func execute(tmpl *template.Template, evt interface{}) (string, error) {
+	if tmpl == nil {
+		return "", nil
+	}
+	var buf bytes.Buffer
+	err := tmpl.Execute(&buf, evt)
+	if err != nil {
+		return "", fmt.Errorf("Error filling template %s: %w", tmpl.Name(), err)
+	}
+	return buf.String(), nil
+}
+
+type httpWebhookChannel struct {
+	client *http.Client
+}
+
+// NewHTTPWebhookChannel returns a channel that posts webhook-notifications as json, with the default client when
+// client is nil
+func NewHTTPWebhookChannel(client *http.Client) WebhookChannel {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &httpWebhookChannel{client: client}
+}
+
+func (w *httpWebhookChannel) SendWebhook(c context.Context, notification WebhookNotification) error {
+	req, err := http.NewRequestWithContext(c, http.MethodPost, notification.URL, strings.NewReader(notification.Body))
+	if err != nil {
+		return fmt.Errorf("Error creating request to webhook %s: %w", notification.URL, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("Error posting to webhook %s: %w", notification.URL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("Webhook %s answered with status %d", notification.URL, resp.StatusCode)
+	}
+	return nil
+}
+`
@@ -0,0 +1,47 @@
+package notifyAnnotation
+
+import "github.com/MarcGrol/golangAnnotations/generator/annotation"
+
+const (
+	TypeNotify     = "Notify"
+	ParamChannel   = "channel"
+	ParamTo        = "to"
+	ParamSubject   = "subject"
+	ParamBody      = "body"
+	ParamURL       = "url"
+	ChannelEmail   = "email"
+	ChannelSMS     = "sms"
+	ChannelWebhook = "webhook"
+)
+
+// requiredParams are the attributes that every channel needs to address and fill its notifications
+var requiredParams = map[string][]string{
+	ChannelEmail:   {ParamTo, ParamSubject, ParamBody},
+	ChannelSMS:     {ParamTo, ParamBody},
+	ChannelWebhook: {ParamURL},
+}
+
+func Get() []annotation.AnnotationDescriptor {
+	return []annotation.AnnotationDescriptor{
+		{
+			Name:       TypeNotify,
+			ParamNames: []string{ParamChannel, ParamTo, ParamSubject, ParamBody, ParamURL},
+			Validator:  validateNotifyAnnotation,
+		}}
+}
+
+func validateNotifyAnnotation(annot annotation.Annotation) bool {
+	if annot.Name == TypeNotify {
+		params, found := requiredParams[annot.Attributes[ParamChannel]]
+		if !found {
+			return false
+		}
+		for _, param := range params {
+			if annot.Attributes[param] == "" {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,33 @@
+package notifyAnnotation
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrectNotifyAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	annotation, ok := registry.ResolveAnnotation(`// @Notify( channel = "email", to = "{{.Email}}", subject = "Order {{.OrderUID}} shipped", body = "Dear {{.Name}}, your order is on its way" )`)
+	assert.True(t, ok)
+	assert.Equal(t, TypeNotify, annotation.Name)
+	assert.Equal(t, ChannelEmail, annotation.Attributes[ParamChannel])
+	assert.Equal(t, "{{.Email}}", annotation.Attributes[ParamTo])
+	assert.Equal(t, "Order {{.OrderUID}} shipped", annotation.Attributes[ParamSubject])
+
+	_, ok = registry.ResolveAnnotation(`// @Notify( channel = "sms", to = "{{.Phone}}", body = "Shipped: {{.OrderUID}}" )`)
+	assert.True(t, ok)
+	_, ok = registry.ResolveAnnotation(`// @Notify( channel = "webhook", url = "https://hooks.example.com/orders" )`)
+	assert.True(t, ok)
+}
+
+func TestInvalidNotifyAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @Notify( channel = "pigeon", to = "{{.Email}}", body = "Hi" )`}))
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @Notify( channel = "email", to = "{{.Email}}", body = "Hi" )`}))
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @Notify( channel = "sms", body = "Hi" )`}))
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @Notify( channel = "webhook" )`}))
+}
@@ -0,0 +1,60 @@
+package openapi
+
+const docsTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+
+package {{.PackageName}}
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+{{- if not .Validated}}
+
+// {{.Name}}OpenAPIDocument is the OpenAPI-document of {{.Name}}
+const {{.Name}}OpenAPIDocument = {{GoStringLiteral .Document}}
+{{- end}}
+
+// {{.Name}}OpenAPIDocsPath is the path of the browsable documentation of {{.Name}}
+const {{.Name}}OpenAPIDocsPath = "{{.DocsPath}}"
+
+// {{.Name}}OpenAPIDocsPage shows the OpenAPI-document of {{.Name}} with {{if eq .UI "redoc"}}Redoc{{else}}Swagger UI{{end}}, which is loaded from a CDN
+const {{.Name}}OpenAPIDocsPage = ` + "`" + `<!DOCTYPE html>
+<html>
+<head>
+	<meta charset="utf-8">
+	<title>{{.Name}}</title>
+{{- if eq .UI "redoc"}}
+</head>
+<body>
+	<redoc spec-url="{{.DocsPath}}/openapi.json"></redoc>
+	<script src="https://cdn.jsdelivr.net/npm/redoc@2/bundles/redoc.standalone.js"></script>
+</body>
+{{- else}}
+	<link rel="stylesheet" href="https://cdn.jsdelivr.net/npm/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+	<div id="swagger-ui"></div>
+	<script src="https://cdn.jsdelivr.net/npm/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+	<script>
+		SwaggerUIBundle({url: "{{.DocsPath}}/openapi.json", dom_id: "#swagger-ui"});
+	</script>
+</body>
+{{- end}}
+</html>
+` + "`" + `
+
+// {{.Name}}OpenAPIDocs registers the browsable documentation of {{.Name}} in a router: the page at
+// {{.Name}}OpenAPIDocsPath and the OpenAPI-document below it, at openapi.json
+func {{.Name}}OpenAPIDocs(router *mux.Router) {
+	router.HandleFunc({{.Name}}OpenAPIDocsPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		io.WriteString(w, {{.Name}}OpenAPIDocsPage)
+	}).Methods("GET")
+	router.HandleFunc({{.Name}}OpenAPIDocsPath+"/openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, {{.Name}}OpenAPIDocument)
+	}).Methods("GET")
+}
+`
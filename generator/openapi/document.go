@@ -0,0 +1,325 @@
+package openapi
+
+import (
+	"strings"
+
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/generator/jsonHelpers"
+	"github.com/MarcGrol/golangAnnotations/generator/rest"
+	"github.com/MarcGrol/golangAnnotations/generator/tenant"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+const openAPIVersion = "3.0.3"
+
+// document is the OpenAPI-document of a rest-service
+type document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       info                `json:"info"`
+	Servers    []server            `json:"servers"`
+	Paths      map[string]pathItem `json:"paths"`
+	Components components          `json:"components"`
+}
+
+type info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type server struct {
+	URL string `json:"url"`
+}
+
+// pathItem holds the operations of a path by their lowercase method
+type pathItem map[string]*operation
+
+type operation struct {
+	OperationID string              `json:"operationId"`
+	Description string              `json:"description,omitempty"`
+	Parameters  []parameter         `json:"parameters,omitempty"`
+	RequestBody *requestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]response `json:"responses"`
+}
+
+type parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required"`
+	Schema   *schema `json:"schema"`
+}
+
+type requestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]mediaType `json:"content"`
+}
+
+type mediaType struct {
+	Schema *schema `json:"schema,omitempty"`
+}
+
+type response struct {
+	Description string               `json:"description"`
+	Content     map[string]mediaType `json:"content,omitempty"`
+}
+
+type components struct {
+	Schemas map[string]*schema `json:"schemas,omitempty"`
+}
+
+type schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	AllOf                []*schema          `json:"allOf,omitempty"`
+	Items                *schema            `json:"items,omitempty"`
+	Properties           map[string]*schema `json:"properties,omitempty"`
+	AdditionalProperties *schema            `json:"additionalProperties,omitempty"`
+}
+
+var primitiveSchemas = map[string]schema{
+	"string":        {Type: "string"},
+	"bool":          {Type: "boolean"},
+	"int":           {Type: "integer"},
+	"int8":          {Type: "integer"},
+	"int16":         {Type: "integer"},
+	"int32":         {Type: "integer", Format: "int32"},
+	"int64":         {Type: "integer", Format: "int64"},
+	"uint":          {Type: "integer"},
+	"uint8":         {Type: "integer"},
+	"uint16":        {Type: "integer"},
+	"uint32":        {Type: "integer"},
+	"uint64":        {Type: "integer"},
+	"float32":       {Type: "number", Format: "float"},
+	"float64":       {Type: "number", Format: "double"},
+	"time.Time":     {Type: "string", Format: "date-time"},
+	"mydate.MyDate": {Type: "string", Format: "date"},
+}
+
+// newDocument describes the rest-operations of a service, with the structs of their bodies as components
+func newDocument(service model.Struct, parsedSources model.ParsedSources) document {
+	doc := document{
+		OpenAPI: openAPIVersion,
+		Info: info{
+			Title:       service.Name,
+			Description: generationUtil.Description(service.DocLines),
+			Version:     rest.GetRestServiceVersion(service),
+		},
+		// paths are relative to the host that serves the document
+		Servers:    []server{{URL: "/"}},
+		Paths:      map[string]pathItem{},
+		Components: components{Schemas: map[string]*schema{}},
+	}
+	if doc.Info.Version == "" {
+		doc.Info.Version = "1"
+	}
+	schemas := schemaBuilder{parsedSources: parsedSources, components: doc.Components.Schemas}
+	for _, o := range service.Operations {
+		if !rest.IsRestOperation(*o) {
+			continue
+		}
+		path := rest.GetRestServicePath(service) + rest.GetRestOperationPath(*o)
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = pathItem{}
+		}
+		doc.Paths[path][strings.ToLower(rest.GetRestOperationMethod(*o))] = newOperation(service, *o, schemas)
+	}
+	if rest.IsRestServiceAsync(service) {
+		doc.Components.Schemas[asyncJobSchemaName] = asyncJobSchema()
+		doc.Paths[rest.GetAsyncJobsPath(service)+"/{jobID}"] = pathItem{"get": newAsyncJobOperation(service)}
+	}
+	return doc
+}
+
+func newOperation(service model.Struct, o model.Operation, schemas schemaBuilder) *operation {
+	op := &operation{
+		OperationID: o.Name,
+		Description: generationUtil.Description(o.DocLines),
+		Responses:   map[string]response{"default": {Description: "Error"}},
+	}
+	pathParams := map[string]bool{}
+	for _, name := range pathParamPattern.FindAllStringSubmatch(rest.GetRestOperationPath(o), -1) {
+		pathParams[name[1]] = true
+	}
+	for _, arg := range o.InputArgs {
+		switch {
+		case pathParams[arg.Name]:
+			op.Parameters = append(op.Parameters, parameter{Name: arg.Name, In: "path", Required: true, Schema: schemas.of(arg.TypeName)})
+		case rest.IsRestServiceTenantScoped(service) && tenant.IsTenantArg(arg):
+			op.Parameters = append(op.Parameters, parameter{Name: rest.GetRestServiceTenantHeader(service), In: "header", Required: true, Schema: schemas.of(arg.TypeName)})
+		case rest.IsContextArg(arg) || rest.IsRequestContextArg(arg) || rest.IsUploadArg(arg) || rest.IsInputArg(arg):
+		case !rest.IsRestOperationForm(o):
+			// form-values are in the body, that is not described
+			op.Parameters = append(op.Parameters, parameter{Name: rest.Uncapitalized(arg.Name), In: "query", Required: rest.IsInputArgMandatory(o, arg), Schema: schemas.of(arg.TypeName)})
+		}
+	}
+	if rest.IsRestOperationIdempotent(o) {
+		op.Parameters = append(op.Parameters, parameter{Name: "Idempotency-Key", In: "header", Required: rest.IsIdempotencyKeyRequired(o), Schema: &schema{Type: "string"}})
+		op.Responses["422"] = response{Description: "Idempotency-Key was used for another request"}
+	}
+	if rest.HasETagPrecondition(o) {
+		op.Parameters = append(op.Parameters, parameter{Name: "If-Match", In: "header", Required: rest.IsETagRequired(o), Schema: &schema{Type: "string"}})
+		op.Responses["412"] = response{Description: "Version of resource has changed"}
+		if rest.IsETagRequired(o) {
+			op.Responses["428"] = response{Description: "Missing If-Match header"}
+		}
+	}
+	if rest.HasInput(o) && !rest.HasUpload(o) {
+		mediaTypes := rest.GetRestOperationConsumes(service, o)
+		if len(mediaTypes) == 0 {
+			mediaTypes = []string{"application/json"}
+		}
+		op.RequestBody = &requestBody{Required: true, Content: map[string]mediaType{}}
+		for _, name := range mediaTypes {
+			op.RequestBody.Content[name] = mediaType{Schema: schemas.of(rest.GetInputArgType(o))}
+		}
+	}
+
+	if rest.IsRestOperationNoContent(o) {
+		op.Responses["204"] = response{Description: "No content"}
+		return op
+	}
+	if rest.IsRestOperationAsync(o) {
+		op.Responses["202"] = response{
+			Description: "Accepted: the job is at the Location header",
+			Content:     map[string]mediaType{"application/json": {Schema: &schema{Ref: "#/components/schemas/" + asyncJobSchemaName}}},
+		}
+		return op
+	}
+	ok := response{Description: "OK"}
+	if contentType := rest.GetContentType(o); contentType != "" {
+		ok.Content = map[string]mediaType{contentType: {}}
+		if rest.IsRestOperationJSON(o) && rest.HasOutput(o) {
+			ok.Content[contentType] = mediaType{Schema: schemas.of(rest.GetOutputArgType(o))}
+		}
+	}
+	op.Responses["200"] = ok
+	return op
+}
+
+const asyncJobSchemaName = "AsyncJob"
+
+// asyncJobSchema describes the jobs of asynchronous operations, with the result of the operation once completed
+func asyncJobSchema() *schema {
+	return &schema{
+		Type: "object",
+		Properties: map[string]*schema{
+			"id":        {Type: "string"},
+			"operation": {Type: "string"},
+			"tenant":    {Type: "string"},
+			"status":    {Type: "string", Enum: []string{"pending", "completed", "failed"}},
+			"result":    {},
+			"error":     {Type: "string"},
+			"created":   {Type: "string", Format: "date-time"},
+			"completed": {Type: "string", Format: "date-time", Nullable: true},
+		},
+	}
+}
+
+func newAsyncJobOperation(service model.Struct) *operation {
+	op := &operation{
+		OperationID: "getAsyncJob",
+		Description: "Returns the status of a job of an asynchronous operation",
+		Parameters:  []parameter{{Name: "jobID", In: "path", Required: true, Schema: &schema{Type: "string"}}},
+		Responses: map[string]response{
+			"200":     {Description: "OK", Content: map[string]mediaType{"application/json": {Schema: &schema{Ref: "#/components/schemas/" + asyncJobSchemaName}}}},
+			"404":     {Description: "Unknown job"},
+			"default": {Description: "Error"},
+		},
+	}
+	if rest.IsRestServiceTenantScoped(service) {
+		op.Parameters = append(op.Parameters, parameter{Name: rest.GetRestServiceTenantHeader(service), In: "header", Required: true, Schema: &schema{Type: "string"}})
+	}
+	return op
+}
+
+// schemaBuilder returns the schemas of types: structs of the parsed sources become components that are referred to
+type schemaBuilder struct {
+	parsedSources model.ParsedSources
+	components    map[string]*schema
+}
+
+func (b schemaBuilder) of(typeName string) *schema {
+	if strings.HasPrefix(typeName, "*") {
+		s := b.of(typeName[1:])
+		if s.Ref != "" {
+			// siblings of a reference are ignored
+			return &schema{Nullable: true, AllOf: []*schema{s}}
+		}
+		s.Nullable = true
+		return s
+	}
+	if strings.HasPrefix(typeName, "[]") {
+		return &schema{Type: "array", Nullable: true, Items: b.of(typeName[2:])}
+	}
+	if field := (model.Field{TypeName: typeName}); field.IsMap() {
+		_, valueType := field.SplitMapTypeNames()
+		return &schema{Type: "object", Nullable: true, AdditionalProperties: b.of(valueType)}
+	}
+	if primitive, found := primitiveSchemas[typeName]; found {
+		return &primitive
+	}
+	packageName, name := splitTypeName(typeName)
+	for _, e := range b.parsedSources.Enums {
+		if e.Name == name && (packageName == "" || e.PackageName == packageName) && jsonHelpers.IsJSONEnum(e) {
+			if jsonHelpers.IsJSONEnumTolerant(e) {
+				return &schema{Type: "string"}
+			}
+			return &schema{Type: "string", Enum: jsonHelpers.GetJSONEnumNames(e)}
+		}
+	}
+	for _, s := range b.parsedSources.Structs {
+		if s.Name == name && (packageName == "" || s.PackageName == packageName) {
+			ref := &schema{Ref: "#/components/schemas/" + s.Name}
+			if _, found := b.components[s.Name]; !found {
+				// registered before its fields, for structs that refer to themselves
+				b.components[s.Name] = &schema{}
+				*b.components[s.Name] = *b.structSchema(s)
+			}
+			return ref
+		}
+	}
+	for _, t := range b.parsedSources.Typedefs {
+		// structs and interfaces are typedefs as well, without a type
+		if t.Name == name && (packageName == "" || t.PackageName == packageName) && t.Type != "" {
+			return b.of(t.Type)
+		}
+	}
+	// any value
+	return &schema{}
+}
+
+// structSchema returns the object with the json-fields of a struct: fields are not required, as decoding json into
+// a struct leaves missing fields empty
+func (b schemaBuilder) structSchema(s model.Struct) *schema {
+	object := &schema{Type: "object", Properties: map[string]*schema{}}
+	for _, f := range s.Fields {
+		name := strings.Split(f.GetTagMap()["json"], ",")[0]
+		if name == "-" || (name == "" && f.Name != "" && !generationUtil.IsExported(f.Name)) {
+			continue
+		}
+		if name == "" && f.IsEmbedded {
+			if embedded := b.of(f.DereferencedTypeName()); embedded.Ref != "" {
+				for embeddedName, property := range b.components[f.EmbeddedName()].Properties {
+					object.Properties[embeddedName] = property
+				}
+				continue
+			}
+		}
+		if name == "" {
+			name = f.Name
+		}
+		object.Properties[name] = b.of(f.TypeName)
+	}
+	return object
+}
+
+// splitTypeName splits a type into its package and name, like person and Person for person.Person
+func splitTypeName(typeName string) (string, string) {
+	if idx := strings.LastIndex(typeName, "."); idx >= 0 {
+		return typeName[:idx], typeName[idx+1:]
+	}
+	return "", typeName
+}
@@ -0,0 +1,153 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/generator/openapi/openapiAnnotation"
+	"github.com/MarcGrol/golangAnnotations/generator/rest"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+var pathParamPattern = regexp.MustCompile(`\{(\w+)\}`)
+
+// defaultDocsPath is where the browsable documentation is served when @OpenAPI has a ui but no docspath
+const defaultDocsPath = "/docs"
+
+type validationContext struct {
+	PackageName string
+	Name        string
+	Document    string
+}
+
+type docsContext struct {
+	PackageName string
+	Name        string
+	Document    string
+	// Validated tells that the validation declares the document already
+	Validated bool
+	DocsPath  string
+	UI        string
+}
+
+type Generator struct {
+}
+
+func NewGenerator() generator.Generator {
+	return &Generator{}
+}
+
+func (eg *Generator) Name() string {
+	return "openapi"
+}
+
+func (eg *Generator) GetTemplates() []generator.Template {
+	return []generator.Template{
+		{Name: "openapi-validation", APIVersion: 1, Text: validationTemplate},
+		{Name: "openapi-docs", APIVersion: 1, Text: docsTemplate},
+	}
+}
+
+func (eg *Generator) GetAnnotations() []annotation.AnnotationDescriptor {
+	return openapiAnnotation.Get()
+}
+
+func (eg *Generator) Generate(parsedSource model.ParsedSources, config generator.Config) ([]generator.OutputFile, error) {
+	return generate(config, parsedSource)
+}
+
+func generate(config generator.Config, parsedSources model.ParsedSources) ([]generator.OutputFile, error) {
+	packageName, err := generationUtil.GetPackageNameForStructs(parsedSources.Structs)
+	if packageName == "" || err != nil {
+		return nil, err
+	}
+	targetDir, err := generationUtil.DetermineTargetPath(config.InputDir, packageName)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []generator.OutputFile{}
+	for _, service := range parsedSources.Structs {
+		ann, ok := annotation.NewRegistry(openapiAnnotation.Get()).ResolveAnnotationByName(service.DocLines, openapiAnnotation.TypeOpenAPI)
+		if !ok {
+			continue
+		}
+		if !rest.IsRestService(service) {
+			return nil, generator.StructError(service, openapiAnnotation.TypeOpenAPI, "Struct %s with @OpenAPI must be a @RestService", service.Name)
+		}
+		src := fmt.Sprintf("%s.%s", service.PackageName, service.Name)
+
+		marshalled, err := json.MarshalIndent(newDocument(service, parsedSources), "", "\t")
+		if err != nil {
+			return nil, fmt.Errorf("Error marshalling OpenAPI-document of service %s:%w", service.Name, err)
+		}
+		files = append(files, generator.OutputFile{
+			Filename: generationUtil.Prefixed(fmt.Sprintf("%s/openapi%s.json", targetDir, rest.ToFirstUpper(service.Name))),
+			Src:      src,
+			TypeName: service.Name,
+			Content:  marshalled,
+		})
+		validated := ann.Attributes[openapiAnnotation.ParamValidate] == "true"
+		if docsPath, ui, found := getDocs(ann); found {
+			file, err := generationUtil.Generate(generationUtil.Info{
+				Src:            src,
+				TypeName:       service.Name,
+				TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/openapiDocs%s.go", targetDir, rest.ToFirstUpper(service.Name))),
+				TemplateName:   "openapi-docs",
+				TemplateString: docsTemplate,
+				Config:         config,
+				FuncMap:        customTemplateFuncs,
+				Data:           docsContext{PackageName: service.PackageName, Name: service.Name, Document: string(marshalled), Validated: validated, DocsPath: docsPath, UI: ui},
+			})
+			if err != nil {
+				return nil, fmt.Errorf("Error generating OpenAPI-docs for service %s:%w", service.Name, err)
+			}
+			files = append(files, file)
+		}
+		if !validated {
+			continue
+		}
+
+		file, err := generationUtil.Generate(generationUtil.Info{
+			Src:            src,
+			TypeName:       service.Name,
+			TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/openapiValidation%s.go", targetDir, rest.ToFirstUpper(service.Name))),
+			TemplateName:   "openapi-validation",
+			TemplateString: validationTemplate,
+			Config:         config,
+			FuncMap:        customTemplateFuncs,
+			Data:           validationContext{PackageName: service.PackageName, Name: service.Name, Document: string(marshalled)},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Error generating OpenAPI-validation for service %s:%w", service.Name, err)
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// getDocs returns the path and ui of the browsable documentation of a service, when it has any
+func getDocs(ann annotation.Annotation) (string, string, bool) {
+	docsPath, hasDocsPath := ann.Attributes[openapiAnnotation.ParamDocsPath]
+	ui, hasUI := ann.Attributes[openapiAnnotation.ParamUI]
+	if !hasDocsPath && !hasUI {
+		return "", "", false
+	}
+	if docsPath = strings.TrimSuffix(docsPath, "/"); docsPath == "" {
+		docsPath = defaultDocsPath
+	}
+	if ui == "" {
+		ui = openapiAnnotation.UISwagger
+	}
+	return docsPath, ui, true
+}
+
+var customTemplateFuncs = template.FuncMap{
+	"GoStringLiteral": generationUtil.GoStringLiteral,
+}
@@ -0,0 +1,269 @@
+package openapi
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func cleanup() {
+	os.Remove(generationUtil.Prefixed("./testData/openapiPersonService.json"))
+	os.Remove(generationUtil.Prefixed("./testData/openapiValidationPersonService.go"))
+	os.Remove(generationUtil.Prefixed("./testData/openapiDocsPersonService.go"))
+}
+
+func TestGenerateDocument(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @RestService( path = "/api", version = "v2" )`, `// @OpenAPI()`},
+			Name:        "PersonService",
+			Operations: []*model.Operation{
+				{
+					DocLines:   []string{"// Returns a person", `// @RestOperation( method = "GET", path = "/person/{uid}", format = "JSON", optionalargs = "verbose" )`},
+					Name:       "getPerson",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}, {Name: "verbose", TypeName: "bool"}},
+					OutputArgs: []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+				},
+				{
+					DocLines:   []string{`// @RestOperation( method = "POST", path = "/person", format = "no_content" )`, `// @Idempotent()`},
+					Name:       "createPerson",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "person", TypeName: "Person"}},
+					OutputArgs: []model.Field{{TypeName: "error"}},
+				},
+				{
+					DocLines:   []string{`// @RestOperation( method = "PUT", path = "/person/{uid}", format = "no_content" )`, `// @ETag( required = "true" )`},
+					Name:       "updatePerson",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}, {Name: "person", TypeName: "Person"}},
+					OutputArgs: []model.Field{{TypeName: "error"}},
+				},
+				{
+					DocLines:   []string{`// @RestOperation( method = "POST", path = "/report", format = "JSON" )`, `// @Async()`},
+					Name:       "createReport",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}},
+					OutputArgs: []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+				},
+			},
+		},
+		{
+			PackageName: "testData",
+			Name:        "Person",
+			Fields: []model.Field{
+				{Name: "Name", TypeName: "string", Tag: "`json:\"name\"`"},
+				{Name: "Color", TypeName: "ColorType", Tag: "`json:\"color,omitempty\"`"},
+				{Name: "Born", TypeName: "*time.Time", Tag: "`json:\"born\"`"},
+				{Name: "Parent", TypeName: "*Person", Tag: "`json:\"parent\"`"},
+				{Name: "Tags", TypeName: "map[string]int", Tag: "`json:\"tags\"`"},
+				{Name: "secret", TypeName: "string"},
+				{Name: "Internal", TypeName: "string", Tag: "`json:\"-\"`"},
+			},
+		},
+	}
+	e := []model.Enum{
+		{
+			PackageName: "testData",
+			DocLines:    []string{"// @JsonEnum( )"},
+			Name:        "ColorType",
+			EnumLiterals: []model.EnumLiteral{
+				{Name: "ColorRed"},
+				{Name: "ColorGreen"},
+			},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s, Enums: e}, generator.Config{InputDir: "testData"})
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/openapiPersonService.json"))
+	assert.NoError(t, err)
+	doc := document{}
+	assert.NoError(t, json.Unmarshal(data, &doc))
+	assert.Equal(t, "3.0.3", doc.OpenAPI)
+	assert.Equal(t, info{Title: "PersonService", Version: "v2"}, doc.Info)
+	assert.Len(t, doc.Paths, 4)
+
+	get := doc.Paths["/v2/api/person/{uid}"]["get"]
+	assert.Equal(t, "getPerson", get.OperationID)
+	assert.Equal(t, "Returns a person", get.Description)
+	assert.Equal(t, []parameter{
+		{Name: "uid", In: "path", Required: true, Schema: &schema{Type: "string"}},
+		{Name: "verbose", In: "query", Required: false, Schema: &schema{Type: "boolean"}},
+	}, get.Parameters)
+	assert.Nil(t, get.RequestBody)
+	assert.Equal(t, &schema{Nullable: true, AllOf: []*schema{{Ref: "#/components/schemas/Person"}}}, get.Responses["200"].Content["application/json"].Schema)
+	assert.Contains(t, get.Responses, "default")
+
+	post := doc.Paths["/v2/api/person"]["post"]
+	assert.Equal(t, []parameter{{Name: "Idempotency-Key", In: "header", Required: false, Schema: &schema{Type: "string"}}}, post.Parameters)
+	assert.Contains(t, post.Responses, "422")
+	assert.Equal(t, &schema{Ref: "#/components/schemas/Person"}, post.RequestBody.Content["application/json"].Schema)
+	assert.Contains(t, post.Responses, "204")
+	assert.NotContains(t, post.Responses, "200")
+
+	put := doc.Paths["/v2/api/person/{uid}"]["put"]
+	assert.Contains(t, put.Parameters, parameter{Name: "If-Match", In: "header", Required: true, Schema: &schema{Type: "string"}})
+	assert.Contains(t, put.Responses, "412")
+	assert.Contains(t, put.Responses, "428")
+
+	async := doc.Paths["/v2/api/report"]["post"]
+	assert.Equal(t, &schema{Ref: "#/components/schemas/AsyncJob"}, async.Responses["202"].Content["application/json"].Schema)
+	assert.NotContains(t, async.Responses, "200")
+	job := doc.Paths["/v2/api/jobs/{jobID}"]["get"]
+	assert.Equal(t, []parameter{{Name: "jobID", In: "path", Required: true, Schema: &schema{Type: "string"}}}, job.Parameters)
+	assert.Contains(t, doc.Components.Schemas, "AsyncJob")
+
+	assert.Equal(t, &schema{
+		Type: "object",
+		Properties: map[string]*schema{
+			"name":   {Type: "string"},
+			"color":  {Type: "string", Enum: []string{"colorRed", "colorGreen"}},
+			"born":   {Type: "string", Format: "date-time", Nullable: true},
+			"parent": {Nullable: true, AllOf: []*schema{{Ref: "#/components/schemas/Person"}}},
+			"tags":   {Type: "object", Nullable: true, AdditionalProperties: &schema{Type: "integer"}},
+		},
+	}, doc.Components.Schemas["Person"])
+
+	_, err = os.Stat(generationUtil.Prefixed("./testData/openapiValidationPersonService.go"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestGenerateValidation(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @RestService( path = "/api", version = "v2" )`, `// @OpenAPI( validate = "true" )`},
+			Name:        "PersonService",
+			Operations: []*model.Operation{
+				{
+					DocLines:   []string{`// @RestOperation( method = "GET", path = "/person/{uid}", format = "JSON" )`},
+					Name:       "getPerson",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}},
+					OutputArgs: []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+				},
+			},
+		},
+		{
+			PackageName: "testData",
+			Name:        "Person",
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/openapiValidationPersonService.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "const PersonServiceOpenAPIDocument = `{\n\t\"openapi\": \"3.0.3\",")
+	assert.Contains(t, string(data), "func PersonServiceOpenAPIValidation(onResponseError func(r *http.Request, err error)) (func(http.Handler) http.Handler, error) {")
+	assert.Contains(t, string(data), "openapi3filter.ValidateRequest(r.Context(), requestInput)")
+}
+
+func TestGenerateDocs(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @RestService( path = "/api", version = "v2" )`, `// @OpenAPI( docspath = "/api/docs/" )`},
+			Name:        "PersonService",
+			Operations: []*model.Operation{
+				{
+					DocLines:   []string{`// @RestOperation( method = "GET", path = "/person/{uid}", format = "JSON" )`},
+					Name:       "getPerson",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}},
+					OutputArgs: []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+				},
+			},
+		},
+		{
+			PackageName: "testData",
+			Name:        "Person",
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/openapiDocsPersonService.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "const PersonServiceOpenAPIDocument = `{\n\t\"openapi\": \"3.0.3\",")
+	assert.Contains(t, string(data), `const PersonServiceOpenAPIDocsPath = "/api/docs"`)
+	assert.Contains(t, string(data), `SwaggerUIBundle({url: "/api/docs/openapi.json", dom_id: "#swagger-ui"});`)
+	assert.Contains(t, string(data), "func PersonServiceOpenAPIDocs(router *mux.Router) {")
+}
+
+func TestGenerateDocsWithValidation(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @RestService( path = "/api", version = "v2" )`, `// @OpenAPI( validate = "true", ui = "redoc" )`},
+			Name:        "PersonService",
+			Operations: []*model.Operation{
+				{
+					DocLines:   []string{`// @RestOperation( method = "GET", path = "/person/{uid}", format = "JSON" )`},
+					Name:       "getPerson",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}},
+					OutputArgs: []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+				},
+			},
+		},
+		{
+			PackageName: "testData",
+			Name:        "Person",
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/openapiDocsPersonService.go"))
+	assert.NoError(t, err)
+	// declared by the validation
+	assert.NotContains(t, string(data), "const PersonServiceOpenAPIDocument =")
+	assert.Contains(t, string(data), `const PersonServiceOpenAPIDocsPath = "/docs"`)
+	assert.Contains(t, string(data), `<redoc spec-url="/docs/openapi.json"></redoc>`)
+}
+
+func TestGenerateForNoRestService(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @OpenAPI()`},
+			Name:        "PersonService",
+			Operations: []*model.Operation{
+				{
+					DocLines:   []string{`// @RestOperation( method = "GET", path = "/person/{uid}", format = "JSON" )`},
+					Name:       "getPerson",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}},
+					OutputArgs: []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+				},
+			},
+		},
+		{
+			PackageName: "testData",
+			Name:        "Person",
+		},
+	}
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Struct PersonService with @OpenAPI must be a @RestService")
+}
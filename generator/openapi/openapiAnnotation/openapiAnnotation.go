@@ -0,0 +1,38 @@
+package openapiAnnotation
+
+import (
+	"strings"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+)
+
+const (
+	TypeOpenAPI   = "OpenAPI"
+	ParamValidate = "validate"
+	ParamDocsPath = "docspath"
+	ParamUI       = "ui"
+	UISwagger     = "swagger"
+	UIRedoc       = "redoc"
+)
+
+func Get() []annotation.AnnotationDescriptor {
+	return []annotation.AnnotationDescriptor{
+		{
+			Name:       TypeOpenAPI,
+			ParamNames: []string{ParamValidate, ParamDocsPath, ParamUI},
+			Validator:  validateOpenAPIAnnotation,
+		}}
+}
+
+func validateOpenAPIAnnotation(annot annotation.Annotation) bool {
+	if annot.Name == TypeOpenAPI {
+		validate, hasValidate := annot.Attributes[ParamValidate]
+		docsPath, hasDocsPath := annot.Attributes[ParamDocsPath]
+		ui, hasUI := annot.Attributes[ParamUI]
+		return (!hasValidate || validate == "true" || validate == "false") &&
+			// the path ends up in a javascript-string of the page
+			(!hasDocsPath || strings.HasPrefix(docsPath, "/") && !strings.ContainsAny(docsPath, "\"'<>\\ ")) &&
+			(!hasUI || ui == UISwagger || ui == UIRedoc)
+	}
+	return false
+}
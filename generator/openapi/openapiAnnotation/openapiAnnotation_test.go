@@ -0,0 +1,34 @@
+package openapiAnnotation
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrectOpenAPIAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	annotation, ok := registry.ResolveAnnotation(`// @OpenAPI( validate = "true" )`)
+	assert.True(t, ok)
+	assert.Equal(t, TypeOpenAPI, annotation.Name)
+	assert.Equal(t, "true", annotation.Attributes[ParamValidate])
+}
+
+func TestCorrectOpenAPIDocsAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	annotation, ok := registry.ResolveAnnotation(`// @OpenAPI( docspath = "/docs", ui = "redoc" )`)
+	assert.True(t, ok)
+	assert.Equal(t, "/docs", annotation.Attributes[ParamDocsPath])
+	assert.Equal(t, UIRedoc, annotation.Attributes[ParamUI])
+}
+
+func TestInvalidOpenAPIAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @OpenAPI( validate = "always" )`}))
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @OpenAPI( docspath = "docs" )`}))
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @OpenAPI( docspath = "/docs", ui = "rapidoc" )`}))
+}
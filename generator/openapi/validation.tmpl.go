@@ -0,0 +1,81 @@
+package openapi
+
+const validationTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+
+package {{.PackageName}}
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+// {{.Name}}OpenAPIDocument is the OpenAPI-document of {{.Name}}
+const {{.Name}}OpenAPIDocument = {{GoStringLiteral .Document}}
+
+// {{.Name}}OpenAPIValidation returns a middleware that validates requests against the OpenAPI-document of {{.Name}}:
+// invalid requests are answered with 400 Bad Request, requests for unknown routes are passed on. With an
+// onResponseError, responses are validated as well: it receives the responses that do not match the document, which
+// are still sent.
+func {{.Name}}OpenAPIValidation(onResponseError func(r *http.Request, err error)) (func(http.Handler) http.Handler, error) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData([]byte({{.Name}}OpenAPIDocument))
+	if err != nil {
+		return nil, err
+	}
+	err = doc.Validate(loader.Context)
+	if err != nil {
+		return nil, err
+	}
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, err
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route, pathParams, err := router.FindRoute(r)
+			if err != nil {
+				if err == routers.ErrPathNotFound || err == routers.ErrMethodNotAllowed {
+					next.ServeHTTP(w, r)
+					return
+				}
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			requestInput := &openapi3filter.RequestValidationInput{Request: r, PathParams: pathParams, Route: route}
+			err = openapi3filter.ValidateRequest(r.Context(), requestInput)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if onResponseError == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			recorder := httptest.NewRecorder()
+			next.ServeHTTP(recorder, r)
+			responseInput := &openapi3filter.ResponseValidationInput{
+				RequestValidationInput: requestInput,
+				Status:                 recorder.Code,
+				Header:                 recorder.Header(),
+				Options:                &openapi3filter.Options{IncludeResponseStatus: true},
+			}
+			responseInput.SetBodyBytes(recorder.Body.Bytes())
+			err = openapi3filter.ValidateResponse(r.Context(), responseInput)
+			if err != nil {
+				onResponseError(r, err)
+			}
+			for key, values := range recorder.Header() {
+				w.Header()[key] = values
+			}
+			w.WriteHeader(recorder.Code)
+			_, _ = w.Write(recorder.Body.Bytes())
+		})
+	}, nil
+}
+`
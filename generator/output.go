@@ -0,0 +1,83 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+)
+
+// FilenameData is available to a filename-pattern
+type FilenameData struct {
+	Generator string
+	// Filename is the name that the generator chose, without directory
+	Filename string
+	// Base is the name that the generator chose, without directory, generated-file prefix and extension
+	Base   string
+	Ext    string
+	Struct struct {
+		Name string
+	}
+}
+
+// Relocate moves a generated file to the output-dir and renames it according to the filename-pattern of config
+func Relocate(f OutputFile, generatorName string, config Config) (OutputFile, error) {
+	if f.Fixed || (config.OutputDir == "" && config.FilenamePattern == "") {
+		return f, nil
+	}
+	dir, filename := filepath.Split(f.Filename)
+	if config.OutputDir != "" {
+		relativeDir, err := filepath.Rel(config.InputDir, filepath.Clean(dir))
+		if err != nil {
+			return f, fmt.Errorf("Error relocating %s to output-dir %s:%w", f.Filename, config.OutputDir, err)
+		}
+		dir = filepath.Join(config.OutputDir, relativeDir)
+	}
+	if config.FilenamePattern != "" {
+		var err error
+		filename, err = renderFilename(config, filenameData(f, generatorName, filename))
+		if err != nil {
+			return f, err
+		}
+	}
+	f.Filename = filepath.Join(dir, filename)
+	return f, nil
+}
+
+func filenameData(f OutputFile, generatorName string, filename string) FilenameData {
+	data := FilenameData{
+		Generator: generatorName,
+		Filename:  filename,
+		Ext:       filepath.Ext(filename),
+	}
+	data.Base = strings.TrimSuffix(strings.TrimPrefix(filename, GenfilePrefix), data.Ext)
+	data.Struct.Name = f.TypeName
+	return data
+}
+
+func renderFilename(config Config, data FilenameData) (string, error) {
+	funcs := sprig.TxtFuncMap()
+	funcs["snake"] = funcs["snakecase"]
+	funcs["kebab"] = funcs["kebabcase"]
+	funcs["camel"] = funcs["camelcase"]
+	for name, f := range config.TemplateFuncs {
+		funcs[name] = f
+	}
+	t, err := template.New("filename").Funcs(funcs).Parse(config.FilenamePattern)
+	if err != nil {
+		return "", fmt.Errorf("Error parsing filename-pattern '%s':%w", config.FilenamePattern, err)
+	}
+	var buf bytes.Buffer
+	err = t.Execute(&buf, data)
+	if err != nil {
+		return "", fmt.Errorf("Error applying filename-pattern '%s' to %s:%w", config.FilenamePattern, data.Filename, err)
+	}
+	filename := strings.TrimSpace(buf.String())
+	if filename == "" || strings.ContainsAny(filename, `/\`) {
+		return "", fmt.Errorf("Filename-pattern '%s' results in invalid filename '%s' for %s", config.FilenamePattern, filename, data.Filename)
+	}
+	return filename, nil
+}
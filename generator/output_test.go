@@ -0,0 +1,58 @@
+package generator
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRelocateWithoutConfig(t *testing.T) {
+	f := OutputFile{Filename: filepath.Join("pkg", "gen_httpMyService.go")}
+	relocated, err := Relocate(f, "rest", Config{InputDir: "pkg"})
+	assert.NoError(t, err)
+	assert.Equal(t, f, relocated)
+}
+
+func TestRelocateToOutputDir(t *testing.T) {
+	f := OutputFile{Filename: filepath.Join("pkg", "sub", "gen_httpMyService.go")}
+	relocated, err := Relocate(f, "rest", Config{InputDir: "pkg", OutputDir: "out"})
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join("out", "sub", "gen_httpMyService.go"), relocated.Filename)
+}
+
+func TestRelocateWithFilenamePattern(t *testing.T) {
+	f := OutputFile{Filename: filepath.Join("pkg", "gen_httpMyService.go"), TypeName: "MyService"}
+	relocated, err := Relocate(f, "rest", Config{InputDir: "pkg", FilenamePattern: "gen_{{.Struct.Name | snake}}_{{.Generator}}{{.Ext}}"})
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join("pkg", "gen_my_service_rest.go"), relocated.Filename)
+}
+
+func TestRelocateWithFilenamePatternOnBase(t *testing.T) {
+	f := OutputFile{Filename: filepath.Join("pkg", "gen_httpMyService.go")}
+	relocated, err := Relocate(f, "rest", Config{
+		InputDir:        "pkg",
+		FilenamePattern: "{{.Base | shout}}.go",
+		TemplateFuncs:   template.FuncMap{"shout": strings.ToUpper},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join("pkg", "HTTPMYSERVICE.go"), relocated.Filename)
+}
+
+func TestRelocateSkipsFixedFiles(t *testing.T) {
+	f := OutputFile{Filename: filepath.Join("pkg", "migrations", "snapshot.json"), Fixed: true}
+	relocated, err := Relocate(f, "migration", Config{InputDir: "pkg", OutputDir: "out", FilenamePattern: "x.go"})
+	assert.NoError(t, err)
+	assert.Equal(t, f, relocated)
+}
+
+func TestRelocateWithInvalidFilenamePattern(t *testing.T) {
+	f := OutputFile{Filename: filepath.Join("pkg", "gen_httpMyService.go")}
+	_, err := Relocate(f, "rest", Config{InputDir: "pkg", FilenamePattern: "{{.Unknown}}"})
+	assert.Error(t, err)
+
+	_, err = Relocate(f, "rest", Config{InputDir: "pkg", FilenamePattern: "sub/{{.Filename}}"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,325 @@
+package pact
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/MarcGrol/golangAnnotations/generator/example"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/generator/pact/pactAnnotation"
+	"github.com/MarcGrol/golangAnnotations/generator/rest"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+// maxExampleDepth prevents endless recursion on self-referencing structs
+const maxExampleDepth = 4
+
+type pactContext struct {
+	PackageName string
+	// PactPackageName is the package of the consumer-pacts: every version of an api gets a package of its own
+	PactPackageName string
+	Service         model.Struct
+	Consumer        string
+	Provider        string
+	Interactions    []interaction
+}
+
+type interaction struct {
+	Name         string
+	Description  string
+	State        string
+	Method       string
+	Path         string
+	Query        []queryParam
+	RequestBody  string
+	Status       int
+	ContentType  string
+	ResponseBody string
+}
+
+type queryParam struct {
+	Name  string
+	Value string
+}
+
+type Generator struct {
+}
+
+func NewGenerator() generator.Generator {
+	return &Generator{}
+}
+
+func (eg *Generator) Name() string {
+	return "pact"
+}
+
+// GetDependencies returns the generators of the code that the generated code refers to: the http-handler of the service
+func (eg *Generator) GetDependencies() []string {
+	return []string{"rest"}
+}
+
+func (eg *Generator) GetTemplates() []generator.Template {
+	return []generator.Template{
+		{Name: "pact-consumer", APIVersion: 1, Text: pactConsumerTemplate},
+		{Name: "pact-provider", APIVersion: 1, Text: pactProviderTemplate},
+	}
+}
+
+func (eg *Generator) GetAnnotations() []annotation.AnnotationDescriptor {
+	return pactAnnotation.Get()
+}
+
+func (eg *Generator) Generate(parsedSource model.ParsedSources, config generator.Config) ([]generator.OutputFile, error) {
+	return generate(config, parsedSource.Structs)
+}
+
+func generate(config generator.Config, structs []model.Struct) ([]generator.OutputFile, error) {
+	packageName, err := generationUtil.GetPackageNameForStructs(structs)
+	if packageName == "" || err != nil {
+		return nil, err
+	}
+	targetDir, err := generationUtil.DetermineTargetPath(config.InputDir, packageName)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []generator.OutputFile{}
+
+	for _, service := range structs {
+		if !rest.IsRestService(service) || !IsPact(service) {
+			continue
+		}
+		data := pactContext{
+			PackageName:     packageName,
+			PactPackageName: packageName + "Pact" + strings.ToUpper(rest.GetRestServiceVersion(service)),
+			Service:         service,
+			Consumer:        GetPactConsumer(service),
+			Provider:        GetPactProvider(service),
+			Interactions:    getInteractions(service, structs),
+		}
+
+		file, err := generationUtil.Generate(generationUtil.Info{
+			Src:            fmt.Sprintf("%s.%s", service.PackageName, service.Name),
+			TypeName:       service.Name,
+			TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/%s/pact%s.go", targetDir, data.PactPackageName, rest.ToFirstUpper(service.Name))),
+			TemplateName:   "pact-consumer",
+			TemplateString: pactConsumerTemplate,
+			Config:         config,
+			FuncMap:        customTemplateFuncs,
+			Data:           data,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Error generating pact-consumer for service %s:%w", service.Name, err)
+		}
+		files = append(files, file)
+
+		file, err = generationUtil.Generate(generationUtil.Info{
+			Src:            fmt.Sprintf("%s.%s", service.PackageName, service.Name),
+			TypeName:       service.Name,
+			TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/pactProvider%s_test.go", targetDir, rest.ToFirstUpper(service.Name))),
+			TemplateName:   "pact-provider",
+			TemplateString: pactProviderTemplate,
+			Config:         config,
+			FuncMap:        customTemplateFuncs,
+			Data:           data,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Error generating pact-provider for service %s:%w", service.Name, err)
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+var customTemplateFuncs = template.FuncMap{
+	"ToFirstUpper": rest.ToFirstUpper,
+	"Quoted":       strconv.Quote,
+}
+
+func IsPact(s model.Struct) bool {
+	annotations := annotation.NewRegistry(pactAnnotation.Get())
+	_, ok := annotations.ResolveAnnotationByName(s.DocLines, pactAnnotation.TypePact)
+	return ok
+}
+
+func GetPactConsumer(s model.Struct) string {
+	annotations := annotation.NewRegistry(pactAnnotation.Get())
+	if ann, ok := annotations.ResolveAnnotationByName(s.DocLines, pactAnnotation.TypePact); ok {
+		return ann.Attributes[pactAnnotation.ParamConsumer]
+	}
+	return ""
+}
+
+func GetPactProvider(s model.Struct) string {
+	annotations := annotation.NewRegistry(pactAnnotation.Get())
+	if ann, ok := annotations.ResolveAnnotationByName(s.DocLines, pactAnnotation.TypePact); ok {
+		if provider := ann.Attributes[pactAnnotation.ParamProvider]; provider != "" {
+			return provider
+		}
+	}
+	return generationUtil.KebabCase(s.Name)
+}
+
+// IsPactInteraction tells if an operation takes part in the contract: only json and no-content operations do
+func IsPactInteraction(o model.Operation) bool {
+	if !rest.IsRestOperation(o) {
+		return false
+	}
+	annotations := annotation.NewRegistry(pactAnnotation.Get())
+	if ann, ok := annotations.ResolveAnnotationByName(o.DocLines, pactAnnotation.TypePactInteraction); ok {
+		if ann.Attributes[pactAnnotation.ParamSkip] == "true" {
+			return false
+		}
+	}
+	return rest.IsRestOperationJSON(o) || rest.IsRestOperationNoContent(o)
+}
+
+func getInteractionAttribute(o model.Operation, name string) string {
+	annotations := annotation.NewRegistry(pactAnnotation.Get())
+	if ann, ok := annotations.ResolveAnnotationByName(o.DocLines, pactAnnotation.TypePactInteraction); ok {
+		return ann.Attributes[name]
+	}
+	return ""
+}
+
+func getInteractions(service model.Struct, structs []model.Struct) []interaction {
+	interactions := make([]interaction, 0, len(service.Operations))
+	for _, o := range service.Operations {
+		if !IsPactInteraction(*o) {
+			continue
+		}
+		description := getInteractionAttribute(*o, pactAnnotation.ParamDescription)
+		if description == "" {
+			description = fmt.Sprintf("a %s request to %s", rest.GetRestOperationMethod(*o), o.Name)
+		}
+		i := interaction{
+			Name:        rest.ToFirstUpper(o.Name),
+			Description: description,
+			State:       getInteractionAttribute(*o, pactAnnotation.ParamState),
+			Method:      rest.GetRestOperationMethod(*o),
+			Path:        GetExamplePath(service, *o),
+			Query:       getExampleQuery(*o),
+			Status:      200,
+		}
+		if rest.HasInput(*o) {
+			if arg, found := getBodyArg(*o); found {
+				i.RequestBody = GetExampleBody(arg, structs)
+			}
+		}
+		if rest.IsRestOperationNoContent(*o) {
+			i.Status = 204
+		} else {
+			i.ContentType = rest.GetContentType(*o)
+			if rest.HasOutput(*o) {
+				i.ResponseBody = GetExampleBody(getOutputArg(*o), structs)
+			}
+		}
+		interactions = append(interactions, i)
+	}
+	return interactions
+}
+
+var pathParamPattern = regexp.MustCompile(`\{(\w+)\}`)
+
+// GetExamplePath returns the full path of an operation with all path-params replaced by example values
+func GetExamplePath(service model.Struct, o model.Operation) string {
+	path := rest.GetRestServicePath(service) + rest.GetRestOperationPath(o)
+	return pathParamPattern.ReplaceAllStringFunc(path, func(param string) string {
+		name := param[1 : len(param)-1]
+		for _, arg := range o.InputArgs {
+			if arg.Name == name {
+				return example.ParamValue(arg)
+			}
+		}
+		return name + "-1"
+	})
+}
+
+func getExampleQuery(o model.Operation) []queryParam {
+	params := make([]queryParam, 0)
+	if rest.HasInput(o) {
+		return params
+	}
+	for _, arg := range o.InputArgs {
+		if !rest.IsQueryParam(o, arg) || !isPrimitiveArg(arg) {
+			continue
+		}
+		params = append(params, queryParam{Name: arg.Name, Value: example.ParamValue(arg)})
+	}
+	return params
+}
+
+func isPrimitiveArg(arg model.Field) bool {
+	return rest.IsBoolArg(arg) || rest.IsIntArg(arg) || rest.IsStringArg(arg) || rest.IsStringSliceArg(arg) || rest.IsDateArg(arg)
+}
+
+func getBodyArg(o model.Operation) (model.Field, bool) {
+	for _, arg := range o.InputArgs {
+		if rest.IsInputArg(arg) {
+			return arg, true
+		}
+	}
+	return model.Field{}, false
+}
+
+func getOutputArg(o model.Operation) model.Field {
+	for _, arg := range o.OutputArgs {
+		if !rest.IsErrorArg(arg) && !rest.IsMetaCallbackArg(arg) {
+			return arg
+		}
+	}
+	return model.Field{}
+}
+
+// GetExampleBody returns the go-expression of a pact-go matcher that describes the shape of a json-body
+func GetExampleBody(f model.Field, structs []model.Struct) string {
+	return exampleMatcher(f.TypeName, structs, 0)
+}
+
+func exampleMatcher(typeName string, structs []model.Struct, depth int) string {
+	typeName = strings.TrimPrefix(typeName, "*")
+	if strings.HasPrefix(typeName, "[]") {
+		return fmt.Sprintf("dsl.EachLike(%s, 1)", exampleMatcher(strings.TrimPrefix(typeName, "[]"), structs, depth))
+	}
+	switch typeName {
+	case "string":
+		return `dsl.Like("string")`
+	case "int", "int32", "int64":
+		return "dsl.Like(1)"
+	case "float32", "float64":
+		return "dsl.Like(1.5)"
+	case "bool":
+		return "dsl.Like(true)"
+	case "time.Time":
+		return `dsl.Timestamp()`
+	case "mydate.MyDate":
+		return `dsl.Date()`
+	}
+	if depth < maxExampleDepth {
+		for _, s := range structs {
+			if s.Name == typeName {
+				return exampleStructMatcher(s, structs, depth+1)
+			}
+		}
+	}
+	return `dsl.Like("string")`
+}
+
+func exampleStructMatcher(s model.Struct, structs []model.Struct, depth int) string {
+	entries := make([]string, 0, len(s.Fields))
+	for _, f := range s.Fields {
+		name, ok := example.FieldName(f)
+		if !ok {
+			continue
+		}
+		entries = append(entries, fmt.Sprintf("%s: %s", strconv.Quote(name), exampleMatcher(f.TypeName, structs, depth)))
+	}
+	sort.Strings(entries)
+	return fmt.Sprintf("map[string]interface{}{%s}", strings.Join(entries, ", "))
+}
@@ -0,0 +1,144 @@
+package pact
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func cleanup() {
+	os.Remove(generationUtil.Prefixed("./testData/testDataPact/pactMyService.go"))
+	os.Remove(generationUtil.Prefixed("./testData/pactProviderMyService_test.go"))
+	os.Remove(generationUtil.Prefixed("./testData/testDataPactV2/pactMyService.go"))
+}
+
+func TestGenerateForPact(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			DocLines: []string{
+				`// @RestService( path = "/api")`,
+				`// @Pact( consumer = "web-shop" )`,
+			},
+			PackageName: "testData",
+			Name:        "MyService",
+			Operations: []*model.Operation{
+				{
+					DocLines: []string{
+						`// @RestOperation( path = "/person/{uid}", method = "GET", format = "JSON" )`,
+						`// @PactInteraction( state = "person 1 exists" )`,
+					},
+					Name:          "getPerson",
+					RelatedStruct: &model.Field{TypeName: "MyService"},
+					InputArgs: []model.Field{
+						{Name: "c", TypeName: "context.Context"},
+						{Name: "uid", TypeName: "int"},
+						{Name: "verbose", TypeName: "bool"},
+					},
+					OutputArgs: []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+				},
+				{
+					DocLines:      []string{`// @RestOperation( path = "/person", method = "POST", format = "JSON" )`},
+					Name:          "createPerson",
+					RelatedStruct: &model.Field{TypeName: "MyService"},
+					InputArgs: []model.Field{
+						{Name: "c", TypeName: "context.Context"},
+						{Name: "person", TypeName: "Person"},
+					},
+					OutputArgs: []model.Field{{TypeName: "error"}},
+				},
+				{
+					DocLines:      []string{`// @RestOperation( path = "/person.csv", method = "GET", format = "CSV" )`},
+					Name:          "exportPersons",
+					RelatedStruct: &model.Field{TypeName: "MyService"},
+					OutputArgs:    []model.Field{{TypeName: "[]Person"}, {TypeName: "error"}},
+				},
+			},
+		},
+		{
+			PackageName: "testData",
+			Name:        "Person",
+			Fields: []model.Field{
+				{Name: "Name", TypeName: "string", Tag: "`json:\"name\"`"},
+				{Name: "Tags", TypeName: "[]string", Tag: "`json:\"tags,omitempty\"`"},
+				{Name: "Secret", TypeName: "string", Tag: "`json:\"-\"`"},
+			},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Nil(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/testDataPact/pactMyService.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "package testDataPact")
+	assert.Contains(t, string(data), `MyServiceProvider = "my-service"`)
+	assert.Contains(t, string(data), `Given("person 1 exists").`)
+	assert.Contains(t, string(data), `Path:   dsl.String("/api/person/1"),`)
+	assert.Contains(t, string(data), `"verbose": dsl.String("true"),`)
+	assert.Contains(t, string(data), `Body:    map[string]interface{}{"name": dsl.Like("string"), "tags": dsl.EachLike(dsl.Like("string"), 1)},`)
+	assert.Contains(t, string(data), `Body:    map[string]interface{}{"name": dsl.Like("string"), "tags": dsl.EachLike(dsl.Like("string"), 1)},`)
+	assert.NotContains(t, string(data), "ExportPersons")
+
+	data, err = ioutil.ReadFile(generationUtil.Prefixed("./testData/pactProviderMyService_test.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "func TestPactProviderMyService(t *testing.T) {")
+	assert.Contains(t, string(data), "service, stateHandlers := pactSetupMyService(t)")
+}
+
+func TestGenerateForPactOfVersion(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			DocLines: []string{
+				`// @RestService( path = "/api", version = "v2" )`,
+				`// @Pact( consumer = "web-shop" )`,
+			},
+			PackageName: "testData",
+			Name:        "MyService",
+			Operations: []*model.Operation{
+				{
+					DocLines:      []string{`// @RestOperation( path = "/person", method = "GET", format = "JSON" )`},
+					Name:          "getPerson",
+					RelatedStruct: &model.Field{TypeName: "MyService"},
+					OutputArgs:    []model.Field{{TypeName: "string"}, {TypeName: "error"}},
+				},
+			},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Nil(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/testDataPactV2/pactMyService.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "package testDataPactV2")
+	assert.Contains(t, string(data), `Path:   dsl.String("/v2/api/person"),`)
+}
+
+func TestGetExamplePathWithStringParam(t *testing.T) {
+	s := model.Struct{DocLines: []string{`// @RestService( path = "/api")`}}
+	o := model.Operation{
+		DocLines:  []string{`// @RestOperation( path = "/order/{orderUID}", method = "GET" )`},
+		InputArgs: []model.Field{{Name: "orderUID", TypeName: "string"}},
+	}
+	assert.Equal(t, "/api/order/orderUID-1", GetExamplePath(s, o))
+}
+
+func TestGetPactProviderDefaultsToServiceName(t *testing.T) {
+	s := model.Struct{
+		Name:     "CustomerService",
+		DocLines: []string{`// @Pact( consumer = "web-shop" )`},
+	}
+	assert.Equal(t, "web-shop", GetPactConsumer(s))
+	assert.Equal(t, "customer-service", GetPactProvider(s))
+}
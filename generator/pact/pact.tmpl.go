@@ -0,0 +1,118 @@
+package pact
+
+const pactConsumerTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+
+package {{.PactPackageName}}
+
+import (
+	"github.com/pact-foundation/pact-go/dsl"
+)
+
+{{$service := .Service -}}
+const (
+	{{$service.Name}}Consumer = {{Quoted .Consumer}}
+	{{$service.Name}}Provider = {{Quoted .Provider}}
+)
+
+// New{{$service.Name}}Pact creates the pact between consumer {{.Consumer}} and provider {{.Provider}}
+func New{{$service.Name}}Pact() *dsl.Pact {
+	return &dsl.Pact{
+		Consumer: {{$service.Name}}Consumer,
+		Provider: {{$service.Name}}Provider,
+	}
+}
+
+// Add{{$service.Name}}Interactions registers all interactions that {{.Provider}} offers to its consumers
+func Add{{$service.Name}}Interactions(pact *dsl.Pact) {
+	{{range .Interactions -}}
+	Add{{$service.Name}}{{.Name}}Interaction(pact)
+	{{end -}}
+}
+
+{{range .Interactions -}}
+// Add{{$service.Name}}{{.Name}}Interaction registers the expected request and response of {{.Name}}
+func Add{{$service.Name}}{{.Name}}Interaction(pact *dsl.Pact) *dsl.Interaction {
+	return pact.AddInteraction().
+		{{if .State -}}
+		Given({{Quoted .State}}).
+		{{end -}}
+		UponReceiving({{Quoted .Description}}).
+		WithRequest(dsl.Request{
+			Method: "{{.Method}}",
+			Path:   dsl.String({{Quoted .Path}}),
+			{{if .Query -}}
+			Query: dsl.MapMatcher{
+				{{range .Query -}}
+				{{Quoted .Name}}: dsl.String({{Quoted .Value}}),
+				{{end -}}
+			},
+			{{end -}}
+			{{if .RequestBody -}}
+			Headers: dsl.MapMatcher{"Content-Type": dsl.String("application/json")},
+			Body:    {{.RequestBody}},
+			{{end -}}
+		}).
+		WillRespondWith(dsl.Response{
+			Status: {{.Status}},
+			{{if .ContentType -}}
+			Headers: dsl.MapMatcher{"Content-Type": dsl.String({{Quoted .ContentType}})},
+			{{end -}}
+			{{if .ResponseBody -}}
+			Body: {{.ResponseBody}},
+			{{end -}}
+		})
+}
+
+{{end -}}
+`
+
+const pactProviderTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+
+package {{.PackageName}}
+
+import (
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/pact-foundation/pact-go/dsl"
+	"github.com/pact-foundation/pact-go/types"
+)
+
+{{$service := .Service -}}
+// TestPactProvider{{$service.Name}} verifies the pacts that consumers published for {{.Provider}} against the real http-handler.
+// It expects a hand-written pactSetup{{$service.Name}}(t) that returns the service under test and its provider-state handlers,
+// and is skipped unless PACT_URLS (comma separated) or PACT_BROKER_URL is set.
+func TestPactProvider{{$service.Name}}(t *testing.T) {
+	pactURLs := os.Getenv("PACT_URLS")
+	brokerURL := os.Getenv("PACT_BROKER_URL")
+	if pactURLs == "" && brokerURL == "" {
+		t.Skip("Set PACT_URLS or PACT_BROKER_URL to verify the pacts of {{.Provider}}")
+	}
+
+	service, stateHandlers := pactSetup{{$service.Name}}(t)
+	server := httptest.NewServer(service.HTTPHandler())
+	defer server.Close()
+
+	request := types.VerifyRequest{
+		ProviderBaseURL:            server.URL,
+		BrokerURL:                  brokerURL,
+		BrokerToken:                os.Getenv("PACT_BROKER_TOKEN"),
+		ProviderVersion:            os.Getenv("PACT_PROVIDER_VERSION"),
+		PublishVerificationResults: os.Getenv("PACT_PUBLISH_VERIFICATION_RESULTS") == "true",
+		StateHandlers:              stateHandlers,
+	}
+	if pactURLs != "" {
+		request.PactURLs = strings.Split(pactURLs, ",")
+	}
+
+	pact := dsl.Pact{
+		Provider: {{Quoted .Provider}},
+	}
+	_, err := pact.VerifyProvider(t, request)
+	if err != nil {
+		t.Fatalf("Error verifying pacts of {{.Provider}}: %s", err)
+	}
+}
+`
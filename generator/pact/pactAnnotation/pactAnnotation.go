@@ -0,0 +1,42 @@
+package pactAnnotation
+
+import "github.com/MarcGrol/golangAnnotations/generator/annotation"
+
+const (
+	TypePact            = "Pact"
+	TypePactInteraction = "PactInteraction"
+	ParamConsumer       = "consumer"
+	ParamProvider       = "provider"
+	ParamState          = "state"
+	ParamDescription    = "description"
+	ParamSkip           = "skip"
+)
+
+func Get() []annotation.AnnotationDescriptor {
+	return []annotation.AnnotationDescriptor{
+		{
+			Name:       TypePact,
+			ParamNames: []string{ParamConsumer, ParamProvider},
+			Validator:  validatePactAnnotation,
+		},
+		{
+			Name:       TypePactInteraction,
+			ParamNames: []string{ParamState, ParamDescription, ParamSkip},
+			Validator:  validatePactInteractionAnnotation,
+		}}
+}
+
+func validatePactAnnotation(annot annotation.Annotation) bool {
+	if annot.Name == TypePact {
+		consumer, hasConsumer := annot.Attributes[ParamConsumer]
+		return hasConsumer && consumer != ""
+	}
+	return false
+}
+
+func validatePactInteractionAnnotation(annot annotation.Annotation) bool {
+	if annot.Name == TypePactInteraction {
+		return true
+	}
+	return false
+}
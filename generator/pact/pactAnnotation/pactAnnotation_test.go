@@ -0,0 +1,34 @@
+package pactAnnotation
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrectPactAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	annotation, ok := registry.ResolveAnnotation(`// @Pact( consumer = "web-shop", provider = "customer-service" )`)
+	assert.True(t, ok)
+	assert.Equal(t, TypePact, annotation.Name)
+	assert.Equal(t, "web-shop", annotation.Attributes[ParamConsumer])
+	assert.Equal(t, "customer-service", annotation.Attributes[ParamProvider])
+}
+
+func TestPactAnnotationWithoutConsumer(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	_, ok := registry.ResolveAnnotation(`// @Pact( provider = "customer-service" )`)
+	assert.False(t, ok)
+}
+
+func TestCorrectPactInteractionAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	annotation, ok := registry.ResolveAnnotation(`// @PactInteraction( state = "customer 1 exists" )`)
+	assert.True(t, ok)
+	assert.Equal(t, TypePactInteraction, annotation.Name)
+	assert.Equal(t, "customer 1 exists", annotation.Attributes[ParamState])
+}
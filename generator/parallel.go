@@ -0,0 +1,113 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+// Errors combines the errors of work that continued after the first failure
+type Errors []error
+
+func (errs Errors) Error() string {
+	messages := []string{}
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Task is a generator to render for the parsed sources of one input-dir
+type Task struct {
+	Generator     Generator
+	ParsedSources model.ParsedSources
+	Config        Config
+}
+
+// TaskError is the error of a task. Like a compiler-error, it reads "<file>:<line>: <generator>: <message>" when
+// it is in an annotated source and "<input-dir>: <generator>: <error>" otherwise.
+type TaskError struct {
+	Generator string
+	InputDir  string
+	Err       error
+}
+
+func (e TaskError) Error() string {
+	if sourceError, ok := AsSourceError(e.Err); ok && sourceError.Filename != "" {
+		return fmt.Sprintf("%s: %s: %s", sourceError.Position(), e.Generator, sourceError.Message)
+	}
+	return fmt.Sprintf("%s: %s: %v", e.InputDir, e.Generator, e.Err)
+}
+
+func (e TaskError) Unwrap() error {
+	return e.Err
+}
+
+// TaskResult is the outcome of rendering a task
+type TaskResult struct {
+	Files    []OutputFile
+	Duration time.Duration
+	Err      error
+}
+
+// RenderAll renders the tasks concurrently, with at most workers at a time, and returns the results in the order
+// of the tasks. A failing task does not stop the others: the errors of all failing tasks are returned, as
+// TaskErrors.
+func RenderAll(c context.Context, tasks []Task, workers int) ([]TaskResult, error) {
+	results := make([]TaskResult, len(tasks))
+	err := RunConcurrently(c, len(tasks), workers, func(idx int) error {
+		task := tasks[idx]
+		start := time.Now()
+		files, err := RenderContext(c, task.Generator, task.ParsedSources, task.Config)
+		if err != nil && c.Err() == nil {
+			err = TaskError{Generator: task.Generator.Name(), InputDir: task.Config.InputDir, Err: err}
+		}
+		results[idx] = TaskResult{Files: files, Duration: time.Since(start), Err: err}
+		return err
+	})
+	return results, err
+}
+
+// RunConcurrently calls work for every index below count, with at most workers calls at a time. It returns the
+// errors of all failing calls in the order of their index, or nil when none failed. Once c is cancelled, no more
+// calls are started and only the error of c is returned.
+func RunConcurrently(c context.Context, count int, workers int, work func(idx int) error) error {
+	if workers < 1 {
+		workers = 1
+	}
+	errs := make([]error, count)
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers && w < count; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range indexes {
+				errs[idx] = work(idx)
+			}
+		}()
+	}
+	for idx := 0; idx < count && c.Err() == nil; idx++ {
+		indexes <- idx
+	}
+	close(indexes)
+	wg.Wait()
+	if c.Err() != nil {
+		return c.Err()
+	}
+
+	failed := Errors{}
+	for _, err := range errs {
+		if err != nil {
+			failed = append(failed, err)
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	return failed
+}
@@ -0,0 +1,92 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunConcurrentlyLimitsWorkers(t *testing.T) {
+	var running, maxRunning int32
+	calls := make([]bool, 20)
+	err := RunConcurrently(context.Background(), len(calls), 3, func(idx int) error {
+		current := atomic.AddInt32(&running, 1)
+		for {
+			observed := atomic.LoadInt32(&maxRunning)
+			if current <= observed || atomic.CompareAndSwapInt32(&maxRunning, observed, current) {
+				break
+			}
+		}
+		calls[idx] = true
+		atomic.AddInt32(&running, -1)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.True(t, maxRunning <= 3)
+	for _, called := range calls {
+		assert.True(t, called)
+	}
+}
+
+func TestRunConcurrentlyCombinesErrors(t *testing.T) {
+	err := RunConcurrently(context.Background(), 5, 2, func(idx int) error {
+		if idx%2 == 1 {
+			return fmt.Errorf("Error %d", idx)
+		}
+		return nil
+	})
+	assert.EqualError(t, err, "Error 1\nError 3")
+}
+
+type fileGenerator struct {
+	namedGenerator
+	err error
+}
+
+func (g fileGenerator) Generate(parsedSources model.ParsedSources, config Config) ([]OutputFile, error) {
+	return []OutputFile{{Filename: g.name + ".json", Content: []byte(g.name)}}, g.err
+}
+
+func TestRenderAll(t *testing.T) {
+	tasks := []Task{
+		{Generator: fileGenerator{namedGenerator: namedGenerator{name: "a"}}, Config: Config{InputDir: "x"}},
+		{Generator: fileGenerator{namedGenerator: namedGenerator{name: "b"}, err: fmt.Errorf("Broken")}, Config: Config{InputDir: "x"}},
+		{Generator: fileGenerator{namedGenerator: namedGenerator{name: "c"}}, Config: Config{InputDir: "y"}},
+	}
+	results, err := RenderAll(context.Background(), tasks, 2)
+	assert.EqualError(t, err, "x: b: Broken")
+	assert.Equal(t, "a", string(results[0].Files[0].Content))
+	assert.NoError(t, results[0].Err)
+	assert.Empty(t, results[1].Files)
+	assert.EqualError(t, results[1].Err, "x: b: Broken")
+	assert.Equal(t, "c", string(results[2].Files[0].Content))
+}
+
+func TestRenderAllLocatesSourceErrors(t *testing.T) {
+	s := model.Struct{Filename: "person.go", Line: 9, DocLines: []string{"// @Entity()"}, Name: "Person"}
+	tasks := []Task{
+		{Generator: fileGenerator{namedGenerator: namedGenerator{name: "entity"}, err: StructError(s, "Entity", "No id")}, Config: Config{InputDir: "x"}},
+	}
+	results, err := RenderAll(context.Background(), tasks, 1)
+	assert.EqualError(t, err, "person.go:8: entity: No id")
+	sourceError, ok := AsSourceError(results[0].Err)
+	assert.True(t, ok)
+	assert.Equal(t, "Person", sourceError.Declaration)
+}
+
+func TestRunConcurrentlyStopsWhenCancelled(t *testing.T) {
+	c, cancel := context.WithCancel(context.Background())
+	var calls int32
+	err := RunConcurrently(c, 10, 1, func(idx int) error {
+		if atomic.AddInt32(&calls, 1) == 2 {
+			cancel()
+		}
+		return nil
+	})
+	assert.Equal(t, context.Canceled, err)
+	assert.True(t, calls < 10)
+}
@@ -0,0 +1,34 @@
+package pii
+
+const dataMapTemplate = `<!-- Generated automatically by golangAnnotations: do not edit manually -->
+
+# Personal data of package {{.PackageName}}
+
+Categories: {{Join .Categories ", "}}
+
+## Structs and events
+
+| Struct | Kind | Aggregate | Categories |
+|--------|------|-----------|------------|
+{{range .Structs -}}
+| [{{.Name}}](#{{Anchor .Name}}) | {{.Kind}} | {{.Aggregate}} | {{Join .Categories ", "}} |
+{{end -}}
+{{range .Structs}}
+### {{.Name}}
+
+| Field | Type | Categories | Shredded |
+|-------|------|------------|----------|
+{{range .Fields -}}
+| {{.Name}} | ` + "`{{.Type}}`" + ` | {{Join .Categories ", "}} | {{if .Shredded}}yes{{end}} |
+{{end -}}
+{{end -}}
+{{if .Endpoints}}
+## Endpoints
+
+| Method | Path | Operation | Categories |
+|--------|------|-----------|------------|
+{{range .Endpoints -}}
+| {{.Method}} | ` + "`{{.Path}}`" + ` | {{.Service}}.{{.Operation}} | {{Join .Categories ", "}} |
+{{end -}}
+{{end -}}
+`
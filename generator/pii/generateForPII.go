@@ -0,0 +1,341 @@
+package pii
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/MarcGrol/golangAnnotations/generator/event"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/generator/pii/piiAnnotation"
+	"github.com/MarcGrol/golangAnnotations/generator/rest"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+// categorySensitive is the category of the fields that are only marked with the sensitive-tag of events
+const categorySensitive = "sensitive"
+
+// dataMap is the inventory of the personal data of a package: which structs, events and endpoints carry which
+// categories of it
+type dataMap struct {
+	PackageName string        `json:"package"`
+	Categories  []string      `json:"categories"`
+	Structs     []structEntry `json:"structs"`
+	Endpoints   []endpoint    `json:"endpoints"`
+}
+
+type structEntry struct {
+	Name       string       `json:"name"`
+	Kind       string       `json:"kind"`
+	Aggregate  string       `json:"aggregate,omitempty"`
+	Categories []string     `json:"categories"`
+	Fields     []fieldEntry `json:"fields"`
+}
+
+type fieldEntry struct {
+	Name       string   `json:"name"`
+	Type       string   `json:"type"`
+	Categories []string `json:"categories"`
+	Shredded   bool     `json:"shredded,omitempty"`
+}
+
+type endpoint struct {
+	Service    string   `json:"service"`
+	Operation  string   `json:"operation"`
+	Method     string   `json:"method"`
+	Path       string   `json:"path"`
+	Categories []string `json:"categories"`
+}
+
+type shreddingContext struct {
+	PackageName string
+	Structs     []model.Struct
+}
+
+type Generator struct {
+}
+
+func NewGenerator() generator.Generator {
+	return &Generator{}
+}
+
+func (eg *Generator) Name() string {
+	return "pii"
+}
+
+func (eg *Generator) GetTemplates() []generator.Template {
+	return []generator.Template{
+		{Name: "data-map", APIVersion: 1, Text: dataMapTemplate},
+		{Name: "shredding", APIVersion: 1, Text: shreddingTemplate},
+	}
+}
+
+func (eg *Generator) GetAnnotations() []annotation.AnnotationDescriptor {
+	return piiAnnotation.Get()
+}
+
+func (eg *Generator) Generate(parsedSource model.ParsedSources, config generator.Config) ([]generator.OutputFile, error) {
+	return generate(config, parsedSource.Structs)
+}
+
+func generate(config generator.Config, structs []model.Struct) ([]generator.OutputFile, error) {
+	packageName, err := generationUtil.GetPackageNameForStructs(structs)
+	if packageName == "" || err != nil {
+		return nil, err
+	}
+	targetDir, err := generationUtil.DetermineTargetPath(config.InputDir, packageName)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateShredding(structs)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []generator.OutputFile{}
+	data := newDataMap(packageName, structs)
+	if len(data.Structs) == 0 {
+		return files, nil
+	}
+
+	marshalled, err := json.MarshalIndent(data, "", "\t")
+	if err != nil {
+		return nil, fmt.Errorf("Error marshalling data-map of package %s:%w", packageName, err)
+	}
+	files = append(files, generator.OutputFile{
+		Filename: generationUtil.Prefixed(fmt.Sprintf("%s/dataMap.json", targetDir)),
+		Src:      fmt.Sprintf("%s.%s", packageName, "pii"),
+		Content:  marshalled,
+	})
+
+	file, err := generationUtil.Generate(generationUtil.Info{
+		Src:            fmt.Sprintf("%s.%s", packageName, "pii"),
+		TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/dataMap.md", targetDir)),
+		TemplateName:   "data-map",
+		TemplateString: dataMapTemplate,
+		Config:         config,
+		FuncMap:        customTemplateFuncs,
+		Data:           data,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Error generating data-map for package %s:%w", packageName, err)
+	}
+	files = append(files, file)
+
+	shredded := []model.Struct{}
+	for _, s := range structs {
+		if HasShreddedFields(s) {
+			shredded = append(shredded, s)
+		}
+	}
+	if len(shredded) == 0 {
+		return files, nil
+	}
+	file, err = generationUtil.Generate(generationUtil.Info{
+		Src:            fmt.Sprintf("%s.%s", packageName, "pii"),
+		TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/shredding.go", targetDir)),
+		TemplateName:   "shredding",
+		TemplateString: shreddingTemplate,
+		Config:         config,
+		FuncMap:        customTemplateFuncs,
+		Data:           shreddingContext{PackageName: packageName, Structs: shredded},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Error generating shredding for package %s:%w", packageName, err)
+	}
+	files = append(files, file)
+	return files, nil
+}
+
+// validateShredding checks that shredded fields are strings, that hold their encrypted value
+func validateShredding(structs []model.Struct) error {
+	for _, s := range structs {
+		for _, f := range s.Fields {
+			if IsShreddedField(f) && f.TypeName != "string" {
+				return generator.FieldError(s, f, piiAnnotation.TypePII, "Shredded field %s.%s must be a string, not %s", s.Name, f.Name, f.TypeName)
+			}
+		}
+	}
+	return nil
+}
+
+// newDataMap describes the structs with personal data, also in their nested structs, and the rest-operations that
+// take or return them
+func newDataMap(packageName string, structs []model.Struct) dataMap {
+	data := dataMap{PackageName: packageName, Categories: []string{}, Structs: []structEntry{}, Endpoints: []endpoint{}}
+	byName := map[string]model.Struct{}
+	for _, s := range structs {
+		byName[s.Name] = s
+	}
+	resolver := categoryResolver{structs: byName, resolved: map[string][]string{}}
+
+	all := map[string]bool{}
+	for _, s := range structs {
+		entry := structEntry{Name: s.Name, Kind: "struct", Fields: []fieldEntry{}}
+		if event.IsEvent(s) {
+			entry.Kind = "event"
+			entry.Aggregate = event.GetAggregateName(s)
+		}
+		for _, f := range s.Fields {
+			categories := resolver.ofField(f)
+			if len(categories) == 0 {
+				continue
+			}
+			entry.Fields = append(entry.Fields, fieldEntry{Name: fieldName(f), Type: f.TypeName, Categories: categories, Shredded: IsShreddedField(f)})
+		}
+		if len(entry.Fields) == 0 {
+			continue
+		}
+		entry.Categories = resolver.ofStruct(s.Name)
+		for _, category := range entry.Categories {
+			all[category] = true
+		}
+		data.Structs = append(data.Structs, entry)
+	}
+
+	for _, service := range structs {
+		if !rest.IsRestService(service) {
+			continue
+		}
+		for _, o := range service.Operations {
+			if !rest.IsRestOperation(*o) {
+				continue
+			}
+			types := []string{}
+			if rest.HasInput(*o) {
+				types = append(types, rest.GetInputArgType(*o))
+			}
+			if rest.HasOutput(*o) {
+				types = append(types, rest.GetOutputArgType(*o))
+			}
+			categories := []string{}
+			for _, typeName := range types {
+				categories = union(categories, resolver.ofStruct(baseTypeName(typeName)))
+			}
+			if len(categories) == 0 {
+				continue
+			}
+			data.Endpoints = append(data.Endpoints, endpoint{
+				Service:    service.Name,
+				Operation:  o.Name,
+				Method:     rest.GetRestOperationMethod(*o),
+				Path:       rest.GetRestServicePath(service) + rest.GetRestOperationPath(*o),
+				Categories: categories,
+			})
+		}
+	}
+
+	for category := range all {
+		data.Categories = append(data.Categories, category)
+	}
+	sort.Strings(data.Categories)
+	return data
+}
+
+// categoryResolver returns the categories of personal data of structs, including those of the structs they nest
+type categoryResolver struct {
+	structs  map[string]model.Struct
+	resolved map[string][]string
+}
+
+func (r categoryResolver) ofStruct(name string) []string {
+	if categories, found := r.resolved[name]; found {
+		return categories
+	}
+	s, found := r.structs[name]
+	if !found {
+		return nil
+	}
+	// registered before its fields, for structs that refer to themselves
+	r.resolved[name] = nil
+	categories := []string{}
+	for _, f := range s.Fields {
+		categories = union(categories, r.ofField(f))
+	}
+	r.resolved[name] = categories
+	return categories
+}
+
+func (r categoryResolver) ofField(f model.Field) []string {
+	if category := GetCategory(f); category != "" {
+		return []string{category}
+	}
+	if f.IsMap() {
+		_, valueType := f.SplitMapTypeNames()
+		return r.ofStruct(baseTypeName(valueType))
+	}
+	return r.ofStruct(baseTypeName(f.TypeName))
+}
+
+// baseTypeName returns the struct in a type of the package, like Person for *Person and []Person
+func baseTypeName(typeName string) string {
+	typeName = strings.TrimLeft(typeName, "*[]")
+	if strings.Contains(typeName, ".") {
+		return ""
+	}
+	return typeName
+}
+
+func fieldName(f model.Field) string {
+	if f.Name == "" {
+		return f.TypeName
+	}
+	return f.Name
+}
+
+func union(a []string, b []string) []string {
+	for _, value := range b {
+		found := false
+		for _, existing := range a {
+			if existing == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			a = append(a, value)
+		}
+	}
+	sort.Strings(a)
+	return a
+}
+
+var customTemplateFuncs = template.FuncMap{
+	"Anchor":          strings.ToLower,
+	"IsShreddedField": IsShreddedField,
+	"Join":            strings.Join,
+}
+
+// GetCategory returns the category of the personal data in a field: sensitive fields of events are personal data
+// of an unspecified category
+func GetCategory(f model.Field) string {
+	if ann, ok := annotation.NewRegistry(piiAnnotation.Get()).ResolveAnnotationByName(f.DocLines, piiAnnotation.TypePII); ok {
+		return ann.Attributes[piiAnnotation.ParamCategory]
+	}
+	if event.IsSensitiveField(f) || event.IsCustomSensitiveField(f) {
+		// deep-sensitive fields have the categories of the struct they nest
+		return categorySensitive
+	}
+	return ""
+}
+
+func IsShreddedField(f model.Field) bool {
+	if ann, ok := annotation.NewRegistry(piiAnnotation.Get()).ResolveAnnotationByName(f.DocLines, piiAnnotation.TypePII); ok {
+		return ann.Attributes[piiAnnotation.ParamShredded] == "true"
+	}
+	return false
+}
+
+func HasShreddedFields(s model.Struct) bool {
+	for _, f := range s.Fields {
+		if IsShreddedField(f) {
+			return true
+		}
+	}
+	return false
+}
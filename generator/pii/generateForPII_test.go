@@ -0,0 +1,137 @@
+package pii
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func cleanup() {
+	os.Remove(generationUtil.Prefixed("./testData/dataMap.json"))
+	os.Remove(generationUtil.Prefixed("./testData/dataMap.md"))
+	os.Remove(generationUtil.Prefixed("./testData/shredding.go"))
+}
+
+func TestGenerateForPII(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			Name:        "Address",
+			Fields: []model.Field{
+				{Name: "Street", TypeName: "string", DocLines: []string{`// @PII( category = "address" )`}},
+			},
+		},
+		{
+			PackageName: "testData",
+			Name:        "Person",
+			Fields: []model.Field{
+				{Name: "UID", TypeName: "string"},
+				{Name: "Email", TypeName: "string", DocLines: []string{`// @PII( category = "contact", shredded = "true" )`}},
+				{Name: "Addresses", TypeName: "[]Address"},
+			},
+		},
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @Event( aggregate = "Person" )`},
+			Name:        "PersonCreated",
+			Fields: []model.Field{
+				{Name: "PersonUID", TypeName: "string"},
+				{Name: "Name", TypeName: "string", Tag: "`sensitive:\"true\"`"},
+			},
+		},
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @RestService( path = "/api" )`},
+			Name:        "Service",
+			Operations: []*model.Operation{
+				{
+					DocLines:      []string{`// @RestOperation( method = "GET", path = "/person/{uid}", format = "JSON" )`},
+					Name:          "getPerson",
+					RelatedStruct: &model.Field{TypeName: "Service"},
+					InputArgs:     []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}},
+					OutputArgs:    []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+				},
+				{
+					DocLines:      []string{`// @RestOperation( method = "GET", path = "/status", format = "JSON" )`},
+					Name:          "getStatus",
+					RelatedStruct: &model.Field{TypeName: "Service"},
+					InputArgs:     []model.Field{{Name: "c", TypeName: "context.Context"}},
+					OutputArgs:    []model.Field{{TypeName: "string"}, {TypeName: "error"}},
+				},
+			},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Nil(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/dataMap.md"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "Categories: address, contact, sensitive")
+	assert.Contains(t, string(data), "| [Person](#person) | struct |  | address, contact |")
+	assert.Contains(t, string(data), "| [PersonCreated](#personcreated) | event | Person | sensitive |")
+	assert.Contains(t, string(data), "| Email | `string` | contact | yes |")
+	assert.Contains(t, string(data), "| GET | `/api/person/{uid}` | Service.getPerson | address, contact |")
+	assert.NotContains(t, string(data), "getStatus")
+
+	data, err = ioutil.ReadFile(generationUtil.Prefixed("./testData/dataMap.json"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"categories": [
+		"address",
+		"contact",
+		"sensitive"
+	]`)
+
+	data, err = ioutil.ReadFile(generationUtil.Prefixed("./testData/shredding.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "type PIIKeyStore interface {")
+	assert.Contains(t, string(data), "func (s *Person) EncryptPII(key []byte) error {")
+	assert.Contains(t, string(data), "s.Email, err = decryptPII(key, s.Email)")
+	assert.NotContains(t, string(data), "func (s *Address) EncryptPII")
+}
+
+func TestShreddedFieldThatIsNoString(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			Name:        "Person",
+			Fields: []model.Field{
+				{Name: "BirthYear", TypeName: "int", DocLines: []string{`// @PII( category = "birth", shredded = "true" )`}},
+			},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Shredded field Person.BirthYear must be a string, not int")
+}
+
+func TestNoPersonalData(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			Name:        "Status",
+			Fields:      []model.Field{{Name: "Healthy", TypeName: "bool"}},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Nil(t, err)
+
+	_, err = os.Stat(generationUtil.Prefixed("./testData/dataMap.md"))
+	assert.True(t, os.IsNotExist(err))
+}
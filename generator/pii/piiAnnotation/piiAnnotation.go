@@ -0,0 +1,28 @@
+package piiAnnotation
+
+import "github.com/MarcGrol/golangAnnotations/generator/annotation"
+
+const (
+	TypePII       = "PII"
+	ParamCategory = "category"
+	ParamShredded = "shredded"
+)
+
+func Get() []annotation.AnnotationDescriptor {
+	return []annotation.AnnotationDescriptor{
+		{
+			Name:       TypePII,
+			ParamNames: []string{ParamCategory, ParamShredded},
+			Validator:  validatePIIAnnotation,
+		}}
+}
+
+func validatePIIAnnotation(annot annotation.Annotation) bool {
+	if annot.Name != TypePII || annot.Attributes[ParamCategory] == "" {
+		return false
+	}
+	if shredded, found := annot.Attributes[ParamShredded]; found && shredded != "true" && shredded != "false" {
+		return false
+	}
+	return true
+}
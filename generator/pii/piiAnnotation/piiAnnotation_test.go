@@ -0,0 +1,25 @@
+package piiAnnotation
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrectPIIAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	annotation, ok := registry.ResolveAnnotation(`// @PII( category = "contact", shredded = "true" )`)
+	assert.True(t, ok)
+	assert.Equal(t, TypePII, annotation.Name)
+	assert.Equal(t, "contact", annotation.Attributes[ParamCategory])
+	assert.Equal(t, "true", annotation.Attributes[ParamShredded])
+}
+
+func TestInvalidPIIAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @PII()`}))
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @PII( category = "contact", shredded = "yes" )`}))
+}
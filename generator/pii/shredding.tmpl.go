@@ -0,0 +1,120 @@
+package pii
+
+const shreddingTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+
+package {{.PackageName}}
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// PIIKeyStore keeps the keys that encrypt the shredded fields of each data-subject: deleting the key of a subject
+// shreds its personal data everywhere, also in stored events that never change. GetKey returns no key for a subject
+// of which the key was deleted.
+type PIIKeyStore interface {
+	GetKey(c context.Context, rc request.Context, subjectUID string) ([]byte, error)
+	DeleteKey(c context.Context, rc request.Context, subjectUID string) error
+}
+
+// NewPIIKey returns a new key for the shredded fields of a data-subject
+func NewPIIKey() ([]byte, error) {
+	key := make([]byte, 32)
+	_, err := io.ReadFull(rand.Reader, key)
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// piiPrefix marks encrypted values, so that values are encrypted once and values from before shredding are kept
+const piiPrefix = "pii:"
+
+func encryptPII(key []byte, value string) (string, error) {
+	if value == "" || strings.HasPrefix(value, piiPrefix) {
+		return value, nil
+	}
+	aead, err := piiCipher(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	_, err = io.ReadFull(rand.Reader, nonce)
+	if err != nil {
+		return "", err
+	}
+	return piiPrefix + base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, []byte(value), nil)), nil
+}
+
+func decryptPII(key []byte, value string) (string, error) {
+	if !strings.HasPrefix(value, piiPrefix) {
+		return value, nil
+	}
+	if key == nil {
+		// shredded
+		return "", nil
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, piiPrefix))
+	if err != nil {
+		return "", err
+	}
+	aead, err := piiCipher(key)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return "", fmt.Errorf("Encrypted value is too short")
+	}
+	plain, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
+
+func piiCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+{{range .Structs -}}
+{{$struct := .Name -}}
+// EncryptPII encrypts the shredded fields of {{.Name}} with the key of its data-subject
+func (s *{{.Name}}) EncryptPII(key []byte) error {
+	var err error
+	{{range .Fields -}}
+	{{if IsShreddedField . -}}
+	s.{{.Name}}, err = encryptPII(key, s.{{.Name}})
+	if err != nil {
+		return fmt.Errorf("Error encrypting {{$struct}}.{{.Name}}:%w", err)
+	}
+	{{end -}}
+	{{end -}}
+	return nil
+}
+
+// DecryptPII decrypts the shredded fields of {{.Name}} with the key of its data-subject: without a key they are empty
+func (s *{{.Name}}) DecryptPII(key []byte) error {
+	var err error
+	{{range .Fields -}}
+	{{if IsShreddedField . -}}
+	s.{{.Name}}, err = decryptPII(key, s.{{.Name}})
+	if err != nil {
+		return fmt.Errorf("Error decrypting {{$struct}}.{{.Name}}:%w", err)
+	}
+	{{end -}}
+	{{end -}}
+	return nil
+}
+
+{{end -}}
+`
@@ -0,0 +1,144 @@
+// Package plugin runs generators that live in external executables, in the way protoc runs its plugins.
+//
+// An executable named golangAnnotations-gen-<name> receives a Request as json on stdin and writes a Response as
+// json on stdout. Filenames in the response are relative to the input-dir. A plugin reports failure by either
+// filling the error-field of the response or by exiting with a non-zero status.
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+const ExecutablePrefix = "golangAnnotations-gen-"
+
+// Request is what a plugin receives on stdin
+type Request struct {
+	InputDir      string              `json:"inputDir"`
+	ParsedSources model.ParsedSources `json:"parsedSources"`
+}
+
+// Response is what a plugin writes on stdout
+type Response struct {
+	Files []File `json:"files,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+type File struct {
+	Filename string `json:"filename"`
+	Content  string `json:"content"`
+}
+
+type Generator struct {
+	name       string
+	executable string
+}
+
+// NewGenerator returns a generator that runs the given executable
+func NewGenerator(name string, executable string) generator.Generator {
+	return &Generator{
+		name:       name,
+		executable: executable,
+	}
+}
+
+// Lookup finds the executable of the plugin with the given name on the PATH
+func Lookup(name string) (generator.Generator, error) {
+	executable, err := exec.LookPath(ExecutablePrefix + name)
+	if err != nil {
+		return nil, fmt.Errorf("Plugin %s not found:%w", name, err)
+	}
+	return NewGenerator(name, executable), nil
+}
+
+func (eg *Generator) Name() string {
+	return eg.name
+}
+
+// GetAnnotations returns nothing: plugins resolve their own annotations from the doc-lines in the model
+func (eg *Generator) GetAnnotations() []annotation.AnnotationDescriptor {
+	return nil
+}
+
+func (eg *Generator) Generate(parsedSources model.ParsedSources, config generator.Config) ([]generator.OutputFile, error) {
+	return eg.GenerateContext(context.Background(), parsedSources, config)
+}
+
+// GenerateContext runs the plugin, which is killed when c is cancelled
+func (eg *Generator) GenerateContext(c context.Context, parsedSources model.ParsedSources, config generator.Config) ([]generator.OutputFile, error) {
+	request, err := json.Marshal(Request{InputDir: config.InputDir, ParsedSources: parsedSources})
+	if err != nil {
+		return nil, fmt.Errorf("Error marshalling request for plugin %s:%w", eg.name, err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(c, eg.executable)
+	cmd.Stdin = bytes.NewReader(request)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err = cmd.Run()
+	if err != nil {
+		return nil, fmt.Errorf("Error running plugin %s:%s %s", eg.name, err, strings.TrimSpace(stderr.String()))
+	}
+
+	response := Response{}
+	err = json.Unmarshal(stdout.Bytes(), &response)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing response of plugin %s:%w", eg.name, err)
+	}
+	if response.Error != "" {
+		return nil, fmt.Errorf("Plugin %s failed:%s", eg.name, response.Error)
+	}
+
+	files := []generator.OutputFile{}
+	for _, f := range response.Files {
+		filename, err := targetFilename(config.InputDir, f.Filename)
+		if err != nil {
+			return nil, fmt.Errorf("Plugin %s %w", eg.name, err)
+		}
+		files = append(files, generator.OutputFile{
+			Filename: filename,
+			Src:      ExecutablePrefix + eg.name,
+			Content:  []byte(f.Content),
+		})
+	}
+	return files, nil
+}
+
+// targetFilename keeps the files of a plugin within the input-dir
+func targetFilename(inputDir string, filename string) (string, error) {
+	cleaned := filepath.Clean(filepath.FromSlash(filename))
+	if filename == "" || filepath.IsAbs(cleaned) || cleaned == ".." || strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("returned filename '%s' outside input-dir", filename)
+	}
+	return filepath.Join(inputDir, cleaned), nil
+}
+
+// Serve implements the plugin-side of the protocol for a generator written in go
+func Serve(g generator.Generator, stdin io.Reader, stdout io.Writer) error {
+	request := Request{}
+	err := json.NewDecoder(stdin).Decode(&request)
+	if err != nil {
+		return fmt.Errorf("Error decoding plugin-request:%w", err)
+	}
+
+	response := Response{}
+	files, err := g.Generate(request.ParsedSources, generator.Config{InputDir: "."})
+	if err != nil {
+		response.Error = err.Error()
+	}
+	for _, f := range files {
+		response.Files = append(response.Files, File{Filename: filepath.ToSlash(f.Filename), Content: string(f.Content)})
+	}
+	return json.NewEncoder(stdout).Encode(response)
+}
@@ -0,0 +1,90 @@
+package plugin
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func writePlugin(t *testing.T, script string) string {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin-scripts require a posix shell")
+	}
+	executable := filepath.Join(t.TempDir(), ExecutablePrefix+"test")
+	err := ioutil.WriteFile(executable, []byte("#!/bin/sh\n"+script), 0755)
+	assert.NoError(t, err)
+	return executable
+}
+
+func TestGenerate(t *testing.T) {
+	// echoes the package-name of the first struct, proving that the model was received on stdin
+	executable := writePlugin(t, `pkg=$(sed 's/.*"packageName":"\([^"]*\)".*/\1/')
+echo "{\"files\":[{\"filename\":\"gen_plugin.txt\",\"content\":\"package $pkg\"}]}"
+`)
+	g := NewGenerator("test", executable)
+	assert.Equal(t, "test", g.Name())
+
+	files, err := g.Generate(model.ParsedSources{Structs: []model.Struct{{PackageName: "testData", Name: "Person"}}}, generator.Config{InputDir: "testData"})
+	assert.NoError(t, err)
+	assert.Equal(t, []generator.OutputFile{
+		{
+			Filename: filepath.Join("testData", "gen_plugin.txt"),
+			Src:      ExecutablePrefix + "test",
+			Content:  []byte("package testData"),
+		},
+	}, files)
+}
+
+func TestGeneratePluginReportsError(t *testing.T) {
+	executable := writePlugin(t, `echo '{"error":"no annotated structs"}'`)
+	_, err := NewGenerator("test", executable).Generate(model.ParsedSources{}, generator.Config{InputDir: "."})
+	assert.EqualError(t, err, "Plugin test failed:no annotated structs")
+}
+
+func TestGeneratePluginExitsWithFailure(t *testing.T) {
+	executable := writePlugin(t, `echo "out of coffee" >&2
+exit 3`)
+	_, err := NewGenerator("test", executable).Generate(model.ParsedSources{}, generator.Config{InputDir: "."})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "out of coffee")
+}
+
+func TestGenerateRejectsFilesOutsideInputDir(t *testing.T) {
+	executable := writePlugin(t, `echo '{"files":[{"filename":"../main.go","content":""}]}'`)
+	_, err := NewGenerator("test", executable).Generate(model.ParsedSources{}, generator.Config{InputDir: "testData"})
+	assert.EqualError(t, err, "Plugin test returned filename '../main.go' outside input-dir")
+}
+
+func TestLookupUnknownPlugin(t *testing.T) {
+	_, err := Lookup("does-not-exist")
+	assert.Error(t, err)
+}
+
+type personGenerator struct{}
+
+func (g personGenerator) Name() string {
+	return "person"
+}
+
+func (g personGenerator) GetAnnotations() []annotation.AnnotationDescriptor {
+	return nil
+}
+
+func (g personGenerator) Generate(parsedSources model.ParsedSources, config generator.Config) ([]generator.OutputFile, error) {
+	return []generator.OutputFile{{Filename: filepath.Join(config.InputDir, "gen_person.txt"), Content: []byte(parsedSources.Structs[0].Name)}}, nil
+}
+
+func TestServe(t *testing.T) {
+	var stdout bytes.Buffer
+	err := Serve(personGenerator{}, strings.NewReader(`{"inputDir":"x","parsedSources":{"structs":[{"name":"Person"}]}}`), &stdout)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"files":[{"filename":"gen_person.txt","content":"Person"}]}`+"\n", stdout.String())
+}
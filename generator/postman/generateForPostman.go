@@ -0,0 +1,170 @@
+package postman
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/MarcGrol/golangAnnotations/generator/example"
+	"github.com/MarcGrol/golangAnnotations/generator/example/exampleAnnotation"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/generator/rest"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+const (
+	baseURLVariable = "baseUrl"
+	defaultBaseURL  = "http://localhost:8080"
+	postmanSchema   = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+)
+
+// request is the tool-independent description of a single rest-operation
+type request struct {
+	Name        string
+	Description string
+	Method      string
+	Path        string
+	PathParams  []keyValue
+	QueryParams []keyValue
+	ContentType string
+	Body        string
+}
+
+type keyValue struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type Generator struct {
+}
+
+func NewGenerator() generator.Generator {
+	return &Generator{}
+}
+
+func (eg *Generator) Name() string {
+	return "postman"
+}
+
+func (eg *Generator) GetAnnotations() []annotation.AnnotationDescriptor {
+	return exampleAnnotation.Get()
+}
+
+func (eg *Generator) Generate(parsedSource model.ParsedSources, config generator.Config) ([]generator.OutputFile, error) {
+	return generate(config.InputDir, parsedSource)
+}
+
+func generate(inputDir string, parsedSources model.ParsedSources) ([]generator.OutputFile, error) {
+	packageName, err := generationUtil.GetPackageNameForStructs(parsedSources.Structs)
+	if packageName == "" || err != nil {
+		return nil, err
+	}
+	targetDir, err := generationUtil.DetermineTargetPath(inputDir, packageName)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []generator.OutputFile{}
+	for _, service := range parsedSources.Structs {
+		if !rest.IsRestService(service) {
+			continue
+		}
+		requests := getRequests(service, parsedSources)
+		src := fmt.Sprintf("%s.%s", service.PackageName, service.Name)
+
+		collection, err := renderJSON(generationUtil.Prefixed(fmt.Sprintf("%s/postman%s.json", targetDir, rest.ToFirstUpper(service.Name))), src, newPostmanCollection(service, requests))
+		if err != nil {
+			return nil, err
+		}
+		workspace, err := renderJSON(generationUtil.Prefixed(fmt.Sprintf("%s/insomnia%s.json", targetDir, rest.ToFirstUpper(service.Name))), src, newInsomniaExport(service, requests))
+		if err != nil {
+			return nil, err
+		}
+		collection.TypeName, workspace.TypeName = service.Name, service.Name
+		files = append(files, collection, workspace)
+	}
+	return files, nil
+}
+
+func renderJSON(filename string, src string, data interface{}) (generator.OutputFile, error) {
+	marshalled, err := json.MarshalIndent(data, "", "\t")
+	if err != nil {
+		return generator.OutputFile{}, fmt.Errorf("Error marshalling %s:%w", filename, err)
+	}
+	return generator.OutputFile{
+		Filename: filename,
+		Src:      src,
+		Content:  marshalled,
+	}, nil
+}
+
+var pathParamPattern = regexp.MustCompile(`\{(\w+)\}`)
+
+func getRequests(service model.Struct, parsedSources model.ParsedSources) []request {
+	requests := make([]request, 0, len(service.Operations))
+	for _, o := range service.Operations {
+		if !rest.IsRestOperation(*o) {
+			continue
+		}
+		r := request{
+			Name:        o.Name,
+			Description: generationUtil.Description(o.DocLines),
+			Method:      rest.GetRestOperationMethod(*o),
+			Path:        rest.GetRestServicePath(service) + rest.GetRestOperationPath(*o),
+		}
+		for _, arg := range o.InputArgs {
+			if rest.IsContextArg(arg) || rest.IsRequestContextArg(arg) || rest.IsUploadArg(arg) {
+				continue
+			}
+			if rest.IsInputArg(arg) {
+				if rest.HasInput(*o) {
+					r.ContentType = "application/json"
+					r.Body = example.JSON(arg.TypeName, parsedSources)
+				}
+				continue
+			}
+			param := keyValue{Key: arg.Name, Value: example.ParamValue(arg)}
+			if rest.IsQueryParam(*o, arg) {
+				r.QueryParams = append(r.QueryParams, param)
+			} else {
+				r.PathParams = append(r.PathParams, param)
+			}
+		}
+		requests = append(requests, r)
+	}
+	return requests
+}
+
+// pathSegments splits a path into its segments, using the ":name" notation of postman for path-params
+func pathSegments(path string) []string {
+	segments := []string{}
+	for _, segment := range strings.Split(strings.Trim(path, "/"), "/") {
+		if segment != "" {
+			segments = append(segments, pathParamPattern.ReplaceAllString(segment, ":$1"))
+		}
+	}
+	return segments
+}
+
+// examplePath replaces all path-params by their example values
+func examplePath(r request) string {
+	path := r.Path
+	for _, param := range r.PathParams {
+		path = strings.Replace(path, "{"+param.Key+"}", param.Value, -1)
+	}
+	return path
+}
+
+func queryString(params []keyValue) string {
+	if len(params) == 0 {
+		return ""
+	}
+	pairs := make([]string, 0, len(params))
+	for _, param := range params {
+		pairs = append(pairs, param.Key+"="+param.Value)
+	}
+	return "?" + strings.Join(pairs, "&")
+}
@@ -0,0 +1,157 @@
+package postman
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func cleanup() {
+	os.Remove(generationUtil.Prefixed("./testData/postmanPersonService.json"))
+	os.Remove(generationUtil.Prefixed("./testData/insomniaPersonService.json"))
+}
+
+func TestGeneratePostmanCollection(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			DocLines:    []string{`// @RestService( path = "/api" )`},
+			PackageName: "testData",
+			Name:        "PersonService",
+			Operations: []*model.Operation{
+				{
+					DocLines:      []string{`// @RestOperation( method = "GET", path = "/person/{uid}", format = "JSON" )`},
+					Name:          "getPerson",
+					RelatedStruct: &model.Field{TypeName: "PersonService"},
+					InputArgs: []model.Field{
+						{Name: "c", TypeName: "context.Context"},
+						{Name: "uid", TypeName: "string"},
+						{Name: "verbose", TypeName: "bool"},
+					},
+					OutputArgs: []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+				},
+				{
+					DocLines:      []string{"// createPerson registers a new person", `// @RestOperation( method = "POST", path = "/person", format = "JSON" )`},
+					Name:          "createPerson",
+					RelatedStruct: &model.Field{TypeName: "PersonService"},
+					InputArgs: []model.Field{
+						{Name: "c", TypeName: "context.Context"},
+						{Name: "person", TypeName: "Person"},
+					},
+					OutputArgs: []model.Field{{TypeName: "error"}},
+				},
+			},
+		},
+		{
+			PackageName: "testData",
+			Name:        "Person",
+			Fields: []model.Field{
+				{Name: "Name", TypeName: "string", Tag: "`json:\"name\"`"},
+				{Name: "Age", TypeName: "int", Tag: "`json:\"age\"`"},
+			},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Nil(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/postmanPersonService.json"))
+	assert.NoError(t, err)
+
+	collection := postmanCollection{}
+	err = json.Unmarshal(data, &collection)
+	assert.NoError(t, err)
+	assert.Equal(t, "PersonService", collection.Info.Name)
+	assert.Equal(t, postmanSchema, collection.Info.Schema)
+	assert.Equal(t, []keyValue{{Key: "baseUrl", Value: "http://localhost:8080"}}, collection.Variable)
+	assert.Len(t, collection.Item, 2)
+
+	get := collection.Item[0].Request
+	assert.Equal(t, "GET", get.Method)
+	assert.Equal(t, "{{baseUrl}}/api/person/:uid?verbose=true", get.URL.Raw)
+	assert.Equal(t, []string{"api", "person", ":uid"}, get.URL.Path)
+	assert.Equal(t, []keyValue{{Key: "uid", Value: "uid-1"}}, get.URL.Variable)
+	assert.Nil(t, get.Body)
+
+	create := collection.Item[1].Request
+	assert.Equal(t, "createPerson registers a new person", create.Description)
+	assert.Equal(t, []keyValue{{Key: "Content-Type", Value: "application/json"}}, create.Header)
+	assert.Equal(t, "{\n  \"name\": \"string\",\n  \"age\": 1\n}", create.Body.Raw)
+	assert.Equal(t, "json", create.Body.Options.Raw.Language)
+}
+
+func TestGenerateInsomniaExport(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			DocLines:    []string{`// @RestService( path = "/api" )`},
+			PackageName: "testData",
+			Name:        "PersonService",
+			Operations: []*model.Operation{
+				{
+					DocLines:      []string{`// @RestOperation( method = "GET", path = "/person/{uid}", format = "JSON" )`},
+					Name:          "getPerson",
+					RelatedStruct: &model.Field{TypeName: "PersonService"},
+					InputArgs: []model.Field{
+						{Name: "c", TypeName: "context.Context"},
+						{Name: "uid", TypeName: "string"},
+						{Name: "verbose", TypeName: "bool"},
+					},
+					OutputArgs: []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+				},
+				{
+					DocLines:      []string{"// createPerson registers a new person", `// @RestOperation( method = "POST", path = "/person", format = "JSON" )`},
+					Name:          "createPerson",
+					RelatedStruct: &model.Field{TypeName: "PersonService"},
+					InputArgs: []model.Field{
+						{Name: "c", TypeName: "context.Context"},
+						{Name: "person", TypeName: "Person"},
+					},
+					OutputArgs: []model.Field{{TypeName: "error"}},
+				},
+			},
+		},
+		{
+			PackageName: "testData",
+			Name:        "Person",
+			Fields: []model.Field{
+				{Name: "Name", TypeName: "string", Tag: "`json:\"name\"`"},
+				{Name: "Age", TypeName: "int", Tag: "`json:\"age\"`"},
+			},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Nil(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/insomniaPersonService.json"))
+	assert.NoError(t, err)
+
+	export := insomniaExport{}
+	err = json.Unmarshal(data, &export)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, export.ExportFormat)
+	assert.Len(t, export.Resources, 4)
+	assert.Equal(t, "workspace", export.Resources[0].Type)
+	assert.Nil(t, export.Resources[0].ParentID)
+	assert.Equal(t, map[string]string{"baseUrl": "http://localhost:8080"}, export.Resources[1].Data)
+
+	get := export.Resources[2]
+	assert.Equal(t, "{{ _.baseUrl }}/api/person/uid-1", get.URL)
+	assert.Equal(t, []insomniaPair{{Name: "verbose", Value: "true"}}, get.Parameters)
+	assert.Equal(t, "wrk_PersonService", *get.ParentID)
+
+	create := export.Resources[3]
+	assert.Equal(t, "application/json", create.Body.MimeType)
+	assert.Contains(t, create.Body.Text, `"name": "string"`)
+}
@@ -0,0 +1,82 @@
+package postman
+
+import (
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+// insomniaExport follows the v4 export-format of Insomnia
+type insomniaExport struct {
+	Type         string             `json:"_type"`
+	ExportFormat int                `json:"__export_format"`
+	ExportSource string             `json:"__export_source"`
+	Resources    []insomniaResource `json:"resources"`
+}
+
+type insomniaResource struct {
+	ID          string            `json:"_id"`
+	Type        string            `json:"_type"`
+	ParentID    *string           `json:"parentId"`
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Data        map[string]string `json:"data,omitempty"`
+	Method      string            `json:"method,omitempty"`
+	URL         string            `json:"url,omitempty"`
+	Parameters  []insomniaPair    `json:"parameters,omitempty"`
+	Headers     []insomniaPair    `json:"headers,omitempty"`
+	Body        *insomniaBody     `json:"body,omitempty"`
+}
+
+type insomniaPair struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type insomniaBody struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+func newInsomniaExport(service model.Struct, requests []request) insomniaExport {
+	workspaceID := "wrk_" + service.Name
+	export := insomniaExport{
+		Type:         "export",
+		ExportFormat: 4,
+		ExportSource: "golangAnnotations",
+		Resources: []insomniaResource{
+			{
+				ID:          workspaceID,
+				Type:        "workspace",
+				Name:        service.Name,
+				Description: generationUtil.Description(service.DocLines),
+			},
+			{
+				ID:       "env_" + service.Name,
+				Type:     "environment",
+				ParentID: &workspaceID,
+				Name:     "Base Environment",
+				Data:     map[string]string{baseURLVariable: defaultBaseURL},
+			},
+		},
+	}
+	for _, r := range requests {
+		resource := insomniaResource{
+			ID:          "req_" + service.Name + "_" + r.Name,
+			Type:        "request",
+			ParentID:    &workspaceID,
+			Name:        r.Name,
+			Description: r.Description,
+			Method:      r.Method,
+			URL:         "{{ _." + baseURLVariable + " }}" + examplePath(r),
+		}
+		for _, param := range r.QueryParams {
+			resource.Parameters = append(resource.Parameters, insomniaPair{Name: param.Key, Value: param.Value})
+		}
+		if r.Body != "" {
+			resource.Headers = []insomniaPair{{Name: "Content-Type", Value: r.ContentType}}
+			resource.Body = &insomniaBody{MimeType: r.ContentType, Text: r.Body}
+		}
+		export.Resources = append(export.Resources, resource)
+	}
+	return export
+}
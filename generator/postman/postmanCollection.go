@@ -0,0 +1,93 @@
+package postman
+
+import (
+	"strings"
+
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+// postmanCollection follows the v2.1 collection-format of Postman
+type postmanCollection struct {
+	Info     postmanInfo   `json:"info"`
+	Variable []keyValue    `json:"variable"`
+	Item     []postmanItem `json:"item"`
+}
+
+type postmanInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Schema      string `json:"schema"`
+}
+
+type postmanItem struct {
+	Name    string         `json:"name"`
+	Request postmanRequest `json:"request"`
+}
+
+type postmanRequest struct {
+	Method      string       `json:"method"`
+	Description string       `json:"description,omitempty"`
+	Header      []keyValue   `json:"header"`
+	URL         postmanURL   `json:"url"`
+	Body        *postmanBody `json:"body,omitempty"`
+}
+
+type postmanURL struct {
+	Raw      string     `json:"raw"`
+	Host     []string   `json:"host"`
+	Path     []string   `json:"path"`
+	Query    []keyValue `json:"query,omitempty"`
+	Variable []keyValue `json:"variable,omitempty"`
+}
+
+type postmanBody struct {
+	Mode    string             `json:"mode"`
+	Raw     string             `json:"raw"`
+	Options postmanBodyOptions `json:"options"`
+}
+
+type postmanBodyOptions struct {
+	Raw struct {
+		Language string `json:"language"`
+	} `json:"raw"`
+}
+
+func newPostmanCollection(service model.Struct, requests []request) postmanCollection {
+	collection := postmanCollection{
+		Info: postmanInfo{
+			Name:        service.Name,
+			Description: generationUtil.Description(service.DocLines),
+			Schema:      postmanSchema,
+		},
+		Variable: []keyValue{{Key: baseURLVariable, Value: defaultBaseURL}},
+		Item:     []postmanItem{},
+	}
+	for _, r := range requests {
+		host := "{{" + baseURLVariable + "}}"
+		segments := pathSegments(r.Path)
+		item := postmanItem{
+			Name: r.Name,
+			Request: postmanRequest{
+				Method:      r.Method,
+				Description: r.Description,
+				Header:      []keyValue{},
+				URL: postmanURL{
+					Raw:      host + "/" + strings.Join(segments, "/") + queryString(r.QueryParams),
+					Host:     []string{host},
+					Path:     segments,
+					Query:    r.QueryParams,
+					Variable: r.PathParams,
+				},
+			},
+		}
+		if r.Body != "" {
+			item.Request.Header = append(item.Request.Header, keyValue{Key: "Content-Type", Value: r.ContentType})
+			body := &postmanBody{Mode: "raw", Raw: r.Body}
+			body.Options.Raw.Language = "json"
+			item.Request.Body = body
+		}
+		collection.Item = append(collection.Item, item)
+	}
+	return collection
+}
@@ -0,0 +1,203 @@
+package python
+
+const clientTemplate = `# Generated automatically by golangAnnotations: do not edit manually
+
+"""Clients of the rest-services {{range $idx, $client := .Clients}}{{if $idx}}, {{end}}{{$client.Service}}{{end}}"""
+
+from __future__ import annotations
+
+{{if .Pydantic -}}
+import enum
+from typing import Any, Dict, List, Optional
+from urllib.parse import quote
+
+import pydantic
+import requests
+{{- else -}}
+import dataclasses
+import enum
+import typing
+from typing import Any, Dict, List, Optional, Union
+from urllib.parse import quote
+
+import requests
+{{- end}}
+
+
+class ApiError(Exception):
+    """ApiError is the answer of a rest-operation that failed, with its http-status and body"""
+
+    def __init__(self, status_code: int, body: str):
+        super().__init__(f"http-status {status_code}: {body}")
+        self.status_code = status_code
+        self.body = body
+{{range .Enums}}
+
+class {{.Name}}(str, enum.Enum):
+{{- with .Description}}
+    """{{Docstring .}}"""
+{{end}}
+{{- range .Literals}}
+    {{.Name}} = "{{.JSONName}}"
+{{- end}}
+{{end}}
+{{- range $class := .Classes}}
+
+{{if $.Pydantic -}}
+class {{$class.Name}}(pydantic.BaseModel):
+{{- with $class.Description}}
+    """{{Docstring .}}"""
+{{end}}
+    model_config = pydantic.ConfigDict(populate_by_name=True)
+{{- if $class.Fields}}
+{{end}}
+{{- range $class.Fields}}
+    {{.Name}}: Optional[{{.Type}}] = pydantic.Field(default=None, alias="{{.JSONName}}")
+{{- end}}
+{{- else -}}
+@dataclasses.dataclass
+class {{$class.Name}}:
+{{- with $class.Description}}
+    """{{Docstring .}}"""
+{{- if $class.Fields}}
+{{end}}
+{{- else}}
+{{- if not $class.Fields}}
+    pass
+{{- end}}
+{{- end}}
+{{- range $class.Fields}}
+    {{.Name}}: Optional[{{.Type}}] = dataclasses.field(default=None, metadata={"json": "{{.JSONName}}"})
+{{- end}}
+{{- end}}
+{{end}}
+
+class _Client:
+    """_Client sends the requests of the clients to a service at base_url"""
+
+    def __init__(self, base_url: str, session: Optional[requests.Session] = None, timeout: float = 30.0):
+        self.base_url = base_url.rstrip("/")
+        self.session = session or requests.Session()
+        self.timeout = timeout
+
+    def _request(
+        self,
+        method: str,
+        path: str,
+        params: Optional[Dict[str, Any]] = None,
+        headers: Optional[Dict[str, Any]] = None,
+        json: Any = None,
+        data: Optional[Dict[str, Any]] = None,
+    ) -> requests.Response:
+        response = self.session.request(
+            method,
+            self.base_url + path,
+            params=_params(params),
+            headers={key: str(value) for key, value in _params(headers).items()},
+            json=json,
+            data=_params(data),
+            timeout=self.timeout,
+        )
+        if response.status_code >= 400:
+            raise ApiError(response.status_code, response.text)
+        return response
+{{range .Clients}}
+
+class {{.Class}}(_Client):
+    """{{.Class}} calls the rest-operations of {{.Service}}"""
+{{range .Operations}}
+    def {{.Name}}(self{{range .Required}}, {{.Name}}: {{.Type}}{{end}}{{with .Optional}}, *{{range .}}, {{.Name}}: Optional[{{.Type}}] = None{{end}}{{end}}) -> {{.Result}}:
+{{- with .Description}}
+        """{{Docstring .}}"""
+{{- end}}
+        {{if ne .Returns "nothing"}}response = {{end}}self._request(
+            "{{.Method}}",
+            {{.Path}},
+{{- with .Query}}
+            params={ {{- range $idx, $param := .}}{{if $idx}}, {{end}}"{{$param.Key}}": {{$param.Name}}{{end -}} },
+{{- end}}
+{{- with .Headers}}
+            headers={ {{- range $idx, $param := .}}{{if $idx}}, {{end}}"{{$param.Key}}": {{$param.Name}}{{end -}} },
+{{- end}}
+{{- with .Body}}
+            json=_encode({{.Type}}, {{.Name}}),
+{{- end}}
+{{- with .Fields}}
+            data={ {{- range $idx, $param := .}}{{if $idx}}, {{end}}"{{$param.Key}}": {{$param.Name}}{{end -}} },
+{{- end}}
+        )
+{{- if eq .Returns "json"}}
+        return _decode({{.Result}}, response.json())
+{{- else if eq .Returns "content"}}
+        return response.content
+{{- end}}
+{{end}}
+{{- end}}
+
+def _path(value: Any) -> str:
+    """_path formats a path-parameter"""
+    return quote(str(_param(value)), safe="")
+
+
+def _params(values: Optional[Dict[str, Any]]) -> Dict[str, Any]:
+    """_params formats the query-, header- or form-parameters that are given"""
+    return {key: _param(value) for key, value in (values or {}).items() if value is not None}
+
+
+def _param(value: Any) -> Any:
+    """_param formats a parameter the way the service parses it"""
+    if isinstance(value, bool):
+        return "true" if value else "false"
+    if isinstance(value, enum.Enum):
+        return value.value
+    return value
+{{if .Pydantic}}
+
+def _encode(hint: Any, value: Any) -> Any:
+    """_encode converts a value of a type-hint into json-data"""
+    return pydantic.TypeAdapter(hint).dump_python(value, mode="json", by_alias=True, exclude_none=True)
+
+
+def _decode(hint: Any, value: Any) -> Any:
+    """_decode converts json-data into a value of a type-hint"""
+    return pydantic.TypeAdapter(hint).validate_python(value)
+{{- else}}
+
+def _encode(hint: Any, value: Any) -> Any:
+    """_encode converts a value of a type-hint into json-data: dataclasses know their fields, so the hint is not needed"""
+    if value is None:
+        return None
+    if dataclasses.is_dataclass(value):
+        fields = ((field, getattr(value, field.name)) for field in dataclasses.fields(value))
+        return {field.metadata["json"]: _encode(None, item) for field, item in fields if item is not None}
+    if isinstance(value, enum.Enum):
+        return value.value
+    if isinstance(value, (list, tuple)):
+        return [_encode(None, item) for item in value]
+    if isinstance(value, dict):
+        return {str(key): _encode(None, item) for key, item in value.items()}
+    return value
+
+
+def _decode(hint: Any, value: Any) -> Any:
+    """_decode converts json-data into a value of a type-hint"""
+    if value is None:
+        return None
+    origin = getattr(hint, "__origin__", None)
+    args = getattr(hint, "__args__", ())
+    if origin is Union:
+        return _decode(next(arg for arg in args if arg is not type(None)), value)
+    if origin is list:
+        return [_decode(args[0], item) for item in value]
+    if origin is dict:
+        # the keys of json-objects are strings, also for maps with numbers as key
+        return {(int(key) if args[0] is int else key): _decode(args[1], item) for key, item in value.items()}
+    if dataclasses.is_dataclass(hint):
+        hints = typing.get_type_hints(hint)
+        fields = dataclasses.fields(hint)
+        return hint(**{field.name: _decode(hints[field.name], value.get(field.metadata["json"])) for field in fields})
+    if isinstance(hint, type) and issubclass(hint, enum.Enum):
+        return hint(value)
+    return value
+{{- end}}
+`
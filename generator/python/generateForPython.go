@@ -0,0 +1,253 @@
+package python
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/generator/python/pythonAnnotation"
+	"github.com/MarcGrol/golangAnnotations/generator/rest"
+	"github.com/MarcGrol/golangAnnotations/generator/tenant"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+var pathParamPattern = regexp.MustCompile(`\{(\w+)\}`)
+
+// moduleContext holds the clients of the rest-services that share a python-module, with the models they use
+type moduleContext struct {
+	Module   string
+	Pydantic bool
+	Clients  []clientContext
+	Classes  []*pyClass
+	Enums    []*pyEnum
+	types    *pyTypes
+}
+
+type clientContext struct {
+	Service    string
+	Class      string
+	Operations []clientOperation
+}
+
+type clientOperation struct {
+	Name        string
+	Description string
+	Method      string
+	// Path is the python-expression of the path of the operation, with its path-parameters filled in
+	Path string
+	// Required and Optional are the parameters of the method, the optional ones are keyword-only
+	Required []clientParam
+	Optional []clientParam
+	Query    []clientParam
+	Headers  []clientParam
+	Fields   []clientParam
+	Body     *clientParam
+	Result   string
+	// Returns is how the response is returned: decoded json, the raw content or nothing
+	Returns string
+}
+
+type clientParam struct {
+	Key  string
+	Name string
+	Type string
+}
+
+const (
+	returnsNothing = "nothing"
+	returnsJSON    = "json"
+	returnsContent = "content"
+)
+
+type Generator struct {
+}
+
+func NewGenerator() generator.Generator {
+	return &Generator{}
+}
+
+func (eg *Generator) Name() string {
+	return "python"
+}
+
+func (eg *Generator) GetTemplates() []generator.Template {
+	return []generator.Template{
+		{Name: "python-client", APIVersion: 1, Text: clientTemplate},
+	}
+}
+
+func (eg *Generator) GetAnnotations() []annotation.AnnotationDescriptor {
+	return pythonAnnotation.Get()
+}
+
+func (eg *Generator) Generate(parsedSource model.ParsedSources, config generator.Config) ([]generator.OutputFile, error) {
+	return generate(config, parsedSource)
+}
+
+func generate(config generator.Config, parsedSources model.ParsedSources) ([]generator.OutputFile, error) {
+	packageName, err := generationUtil.GetPackageNameForStructs(parsedSources.Structs)
+	if packageName == "" || err != nil {
+		return nil, err
+	}
+	targetDir, err := generationUtil.DetermineTargetPath(config.InputDir, packageName)
+	if err != nil {
+		return nil, err
+	}
+
+	modules := []*moduleContext{}
+	for _, service := range parsedSources.Structs {
+		ann, ok := annotation.NewRegistry(pythonAnnotation.Get()).ResolveAnnotationByName(service.DocLines, pythonAnnotation.TypePythonClient)
+		if !ok {
+			continue
+		}
+		if !rest.IsRestService(service) {
+			return nil, generator.StructError(service, pythonAnnotation.TypePythonClient, "Struct %s with @PythonClient must be a @RestService", service.Name)
+		}
+		name := ann.Attributes[pythonAnnotation.ParamModule]
+		if name == "" {
+			name = generationUtil.SnakeCase(packageName) + "_client"
+		}
+		pydantic := ann.Attributes[pythonAnnotation.ParamModels] == pythonAnnotation.ModelsPydantic
+		module := findModule(&modules, name, pydantic, parsedSources)
+		if module.Pydantic != pydantic {
+			return nil, generator.StructError(service, pythonAnnotation.TypePythonClient, "Struct %s must use the same models as the other clients in module %s", service.Name, name)
+		}
+		module.Clients = append(module.Clients, newClient(service, module.types))
+	}
+
+	files := []generator.OutputFile{}
+	for _, module := range modules {
+		module.Classes, module.Enums = module.types.classes, module.types.enums
+		// python-modules are imported by their filename, so it has no prefix
+		file, err := generationUtil.Generate(generationUtil.Info{
+			Src:            packageName,
+			TargetFilename: fmt.Sprintf("%s/%s.py", targetDir, module.Module),
+			TemplateName:   "python-client",
+			TemplateString: clientTemplate,
+			Config:         config,
+			FuncMap:        customTemplateFuncs,
+			Data:           module,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Error generating python-client %s for package %s:%w", module.Module, packageName, err)
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+func findModule(modules *[]*moduleContext, name string, pydantic bool, parsedSources model.ParsedSources) *moduleContext {
+	for _, module := range *modules {
+		if module.Module == name {
+			return module
+		}
+	}
+	module := &moduleContext{Module: name, Pydantic: pydantic, types: newPyTypes(parsedSources)}
+	*modules = append(*modules, module)
+	return module
+}
+
+// newClient describes the rest-operations of a service as methods of a client-class. Operations with an upload are
+// left out: their form is read by the service itself.
+func newClient(service model.Struct, types *pyTypes) clientContext {
+	client := clientContext{Service: service.Name, Class: service.Name + "Client"}
+	for _, o := range service.Operations {
+		if !rest.IsRestOperation(*o) || rest.HasUpload(*o) {
+			continue
+		}
+		client.Operations = append(client.Operations, newOperation(service, *o, types))
+	}
+	return client
+}
+
+func newOperation(service model.Struct, o model.Operation, types *pyTypes) clientOperation {
+	op := clientOperation{
+		Name:        identifier(generationUtil.SnakeCase(o.Name)),
+		Description: generationUtil.Description(o.DocLines),
+		Method:      rest.GetRestOperationMethod(o),
+	}
+	pathParams := map[string]string{}
+	add := func(param clientParam, required bool) {
+		if required {
+			op.Required = append(op.Required, param)
+		} else {
+			op.Optional = append(op.Optional, param)
+		}
+	}
+	for _, arg := range o.InputArgs {
+		param := clientParam{Name: identifier(generationUtil.SnakeCase(arg.Name)), Type: types.of(arg.TypeName)}
+		switch {
+		case strings.Contains(rest.GetRestOperationPath(o), "{"+arg.Name+"}"):
+			pathParams[arg.Name] = param.Name
+			add(param, true)
+		case rest.IsRestServiceTenantScoped(service) && tenant.IsTenantArg(arg):
+			param.Key = rest.GetRestServiceTenantHeader(service)
+			op.Headers = append(op.Headers, param)
+			add(param, true)
+		case rest.IsContextArg(arg) || rest.IsRequestContextArg(arg) || rest.IsUploadArg(arg):
+			continue
+		case rest.IsInputArg(arg):
+			param.Type = types.nullableOf(arg.TypeName)
+			op.Body = &param
+			add(param, true)
+		case rest.IsRestOperationForm(o):
+			param.Key = rest.Uncapitalized(arg.Name)
+			op.Fields = append(op.Fields, param)
+			add(param, rest.IsInputArgMandatory(o, arg))
+		default:
+			param.Key = rest.Uncapitalized(arg.Name)
+			op.Query = append(op.Query, param)
+			add(param, rest.IsInputArgMandatory(o, arg))
+		}
+	}
+	if rest.IsRestOperationIdempotent(o) {
+		param := clientParam{Key: "Idempotency-Key", Name: "idempotency_key", Type: "str"}
+		op.Headers = append(op.Headers, param)
+		add(param, rest.IsIdempotencyKeyRequired(o))
+	}
+	if rest.HasETagPrecondition(o) {
+		param := clientParam{Key: "If-Match", Name: "if_match", Type: "str"}
+		op.Headers = append(op.Headers, param)
+		add(param, rest.IsETagRequired(o))
+	}
+	op.Path = pathExpression(rest.GetRestServicePath(service)+rest.GetRestOperationPath(o), pathParams)
+
+	switch {
+	case rest.IsRestOperationAsync(o):
+		op.Result, op.Returns = types.asyncJob(), returnsJSON
+	case rest.IsRestOperationNoContent(o) || (rest.IsRestOperationJSON(o) && !rest.HasOutput(o)):
+		op.Result, op.Returns = "None", returnsNothing
+	case rest.IsRestOperationJSON(o):
+		op.Result, op.Returns = types.nullableOf(rest.GetOutputArgType(o)), returnsJSON
+	default:
+		op.Result, op.Returns = "bytes", returnsContent
+	}
+	return op
+}
+
+// pathExpression returns the python-expression of a path: an f-string when there are path-parameters to fill in
+func pathExpression(path string, pathParams map[string]string) string {
+	if len(pathParams) == 0 {
+		return fmt.Sprintf("%q", path)
+	}
+	return "f" + fmt.Sprintf("%q", pathParamPattern.ReplaceAllStringFunc(path, func(match string) string {
+		name := match[1 : len(match)-1]
+		if param, found := pathParams[name]; found {
+			return "{_path(" + param + ")}"
+		}
+		return "{{" + name + "}}"
+	}))
+}
+
+var customTemplateFuncs = template.FuncMap{
+	"Docstring": docstring,
+}
+
+// docstring returns text that cannot end the docstring that it is in
+func docstring(text string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(text)
+}
@@ -0,0 +1,231 @@
+package python
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func cleanup() {
+	os.Remove("./testData/test_data_client.py")
+	os.Remove("./testData/person_client.py")
+}
+
+func TestGenerateDataclasses(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @RestService( path = "/api" )`, `// @PythonClient()`},
+			Name:        "PersonService",
+			Operations: []*model.Operation{
+				{
+					DocLines:   []string{`// @RestOperation( method = "GET", path = "/person/{uid}", format = "JSON" )`},
+					Name:       "getPerson",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}},
+					OutputArgs: []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+				},
+			},
+		},
+		{
+			PackageName: "testData",
+			DocLines:    []string{"// Person is someone */ we know"},
+			Name:        "Person",
+			Fields: []model.Field{
+				{Name: "FirstName", TypeName: "string", Tag: "`json:\"firstName\"`"},
+				{Name: "Color", TypeName: "ColorType", Tag: "`json:\"color,omitempty\"`"},
+				{Name: "Born", TypeName: "*time.Time", Tag: "`json:\"born\"`"},
+				{Name: "Scores", TypeName: "[]float64", Tag: "`json:\"scores\"`"},
+				{Name: "Tags", TypeName: "map[string]int", Tag: "`json:\"tags\"`"},
+				{Name: "From", TypeName: "bool", Tag: "`json:\"from\"`"},
+				{Name: "Reason", TypeName: "Reason", Tag: "`json:\"reason\"`"},
+				{Name: "Extra", TypeName: "interface{}", Tag: "`json:\"extra\"`"},
+				{Name: "secret", TypeName: "string"},
+				{Name: "Internal", TypeName: "string", Tag: "`json:\"-\"`"},
+			},
+		},
+		{
+			PackageName: "testData",
+			Name:        "Reason",
+		},
+	}
+	e := []model.Enum{
+		{
+			PackageName:  "testData",
+			DocLines:     []string{"// @JsonEnum( )"},
+			Name:         "ColorType",
+			EnumLiterals: []model.EnumLiteral{{Name: "ColorRed"}, {Name: "ColorGreen"}},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s, Enums: e}, generator.Config{InputDir: "testData"})
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile("./testData/test_data_client.py")
+	assert.NoError(t, err)
+	client := string(data)
+	assert.Contains(t, client, "import dataclasses\n")
+	assert.Contains(t, client, "class ColorType(str, enum.Enum):\n    COLOR_RED = \"colorRed\"\n    COLOR_GREEN = \"colorGreen\"\n")
+	assert.Contains(t, client, "@dataclasses.dataclass\nclass Person:\n    \"\"\"Person is someone */ we know\"\"\"\n\n")
+	assert.Contains(t, client, `    first_name: Optional[str] = dataclasses.field(default=None, metadata={"json": "firstName"})`)
+	assert.Contains(t, client, `    color: Optional[ColorType] = dataclasses.field(default=None, metadata={"json": "color"})`)
+	assert.Contains(t, client, `    born: Optional[str] = dataclasses.field(default=None, metadata={"json": "born"})`)
+	assert.Contains(t, client, `    scores: Optional[List[float]] = dataclasses.field(default=None, metadata={"json": "scores"})`)
+	assert.Contains(t, client, `    tags: Optional[Dict[str, int]] = dataclasses.field(default=None, metadata={"json": "tags"})`)
+	assert.Contains(t, client, `    from_: Optional[bool] = dataclasses.field(default=None, metadata={"json": "from"})`)
+	assert.Contains(t, client, `    extra: Optional[Any] = dataclasses.field(default=None, metadata={"json": "extra"})`)
+	assert.Contains(t, client, "@dataclasses.dataclass\nclass Reason:\n    pass\n")
+	assert.NotContains(t, client, "secret")
+	assert.NotContains(t, client, "internal")
+}
+
+func TestGenerateOperations(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @RestService( path = "/api" )`, `// @PythonClient()`},
+			Name:        "PersonService",
+			Operations: []*model.Operation{
+				{
+					DocLines:   []string{"// Returns a person", `// @RestOperation( method = "GET", path = "/person/{uid}", format = "JSON", optionalargs = "verbose" )`},
+					Name:       "getPerson",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}, {Name: "verbose", TypeName: "bool"}},
+					OutputArgs: []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+				},
+				{
+					DocLines:   []string{`// @RestOperation( method = "POST", path = "/person", format = "no_content" )`, `// @Idempotent()`},
+					Name:       "createPerson",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "person", TypeName: "Person"}},
+					OutputArgs: []model.Field{{TypeName: "error"}},
+				},
+				{
+					DocLines:   []string{`// @RestOperation( method = "DELETE", path = "/person/{uid}", format = "JSON" )`, `// @ETag( required = "true" )`},
+					Name:       "deletePerson",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}},
+					OutputArgs: []model.Field{{TypeName: "[]Person"}, {TypeName: "error"}},
+				},
+				{
+					DocLines:   []string{`// @RestOperation( method = "POST", path = "/person/{uid}/name", form = "true", format = "no_content" )`},
+					Name:       "rename",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}, {Name: "newName", TypeName: "string"}},
+					OutputArgs: []model.Field{{TypeName: "error"}},
+				},
+				{
+					DocLines:   []string{`// @RestOperation( method = "POST", path = "/report", format = "JSON" )`, `// @Async()`},
+					Name:       "createReport",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}},
+					OutputArgs: []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+				},
+				{
+					DocLines:   []string{`// @RestOperation( method = "GET", path = "/export", format = "CSV" )`},
+					Name:       "export",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}},
+					OutputArgs: []model.Field{{TypeName: "string"}, {TypeName: "error"}},
+				},
+				{
+					DocLines:   []string{`// @RestOperation( method = "POST", path = "/upload", format = "no_content" )`},
+					Name:       "upload",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "upload", TypeName: "Upload"}},
+					OutputArgs: []model.Field{{TypeName: "error"}},
+				},
+			},
+		},
+		{
+			PackageName: "testData",
+			Name:        "Person",
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile("./testData/test_data_client.py")
+	assert.NoError(t, err)
+	client := string(data)
+	assert.Contains(t, client, "class PersonServiceClient(_Client):")
+	assert.Contains(t, client, `    def get_person(self, uid: str, *, verbose: Optional[bool] = None) -> Optional[Person]:
+        """Returns a person"""
+        response = self._request(
+            "GET",
+            f"/api/person/{_path(uid)}",
+            params={"verbose": verbose},
+        )
+        return _decode(Optional[Person], response.json())
+`)
+	assert.Contains(t, client, `    def create_person(self, person: Person, *, idempotency_key: Optional[str] = None) -> None:
+        self._request(
+            "POST",
+            "/api/person",
+            headers={"Idempotency-Key": idempotency_key},
+            json=_encode(Person, person),
+        )
+`)
+	assert.Contains(t, client, `    def delete_person(self, uid: str, if_match: str) -> Optional[List[Person]]:`)
+	assert.Contains(t, client, `            data={"newName": new_name},`)
+	assert.Contains(t, client, `    def create_report(self) -> AsyncJob:`)
+	assert.Contains(t, client, "    def export(self) -> bytes:")
+	assert.NotContains(t, client, "def upload")
+}
+
+func TestGeneratePydantic(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @RestService( path = "/api" )`, `// @PythonClient( module = "person_client", models = "pydantic" )`},
+			Name:        "PersonService",
+			Operations: []*model.Operation{
+				{
+					DocLines:   []string{`// @RestOperation( method = "GET", path = "/person/{uid}", format = "JSON" )`},
+					Name:       "getPerson",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}},
+					OutputArgs: []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+				},
+			},
+		},
+		{
+			PackageName: "testData",
+			Name:        "Person",
+			Fields:      []model.Field{{Name: "Born", TypeName: "*time.Time", Tag: "`json:\"born\"`"}},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile("./testData/person_client.py")
+	assert.NoError(t, err)
+	client := string(data)
+	assert.Contains(t, client, "import pydantic\n")
+	assert.NotContains(t, client, "dataclasses")
+	assert.Contains(t, client, "class Person(pydantic.BaseModel):")
+	assert.Contains(t, client, `    born: Optional[str] = pydantic.Field(default=None, alias="born")`)
+	assert.Contains(t, client, "return pydantic.TypeAdapter(hint).validate_python(value)")
+}
+
+func TestGenerateForNoRestService(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @PythonClient()`},
+			Name:        "PersonService",
+		},
+	}
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Struct PersonService with @PythonClient must be a @RestService")
+}
@@ -0,0 +1,36 @@
+package pythonAnnotation
+
+import (
+	"regexp"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+)
+
+const (
+	TypePythonClient = "PythonClient"
+	ParamModule      = "module"
+	ParamModels      = "models"
+	ModelsDataclass  = "dataclasses"
+	ModelsPydantic   = "pydantic"
+)
+
+var modulePattern = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+func Get() []annotation.AnnotationDescriptor {
+	return []annotation.AnnotationDescriptor{
+		{
+			Name:       TypePythonClient,
+			ParamNames: []string{ParamModule, ParamModels},
+			Validator:  validatePythonClientAnnotation,
+		}}
+}
+
+func validatePythonClientAnnotation(annot annotation.Annotation) bool {
+	if annot.Name == TypePythonClient {
+		module, hasModule := annot.Attributes[ParamModule]
+		models, hasModels := annot.Attributes[ParamModels]
+		return (!hasModule || modulePattern.MatchString(module)) &&
+			(!hasModels || models == ModelsDataclass || models == ModelsPydantic)
+	}
+	return false
+}
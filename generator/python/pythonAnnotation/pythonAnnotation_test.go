@@ -0,0 +1,29 @@
+package pythonAnnotation
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrectPythonClientAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	annotation, ok := registry.ResolveAnnotation(`// @PythonClient( module = "person_client", models = "pydantic" )`)
+	assert.True(t, ok)
+	assert.Equal(t, TypePythonClient, annotation.Name)
+	assert.Equal(t, "person_client", annotation.Attributes[ParamModule])
+	assert.Equal(t, ModelsPydantic, annotation.Attributes[ParamModels])
+
+	_, ok = registry.ResolveAnnotation(`// @PythonClient()`)
+	assert.True(t, ok)
+}
+
+func TestInvalidPythonClientAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @PythonClient( module = "person-client" )`}))
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @PythonClient( module = "1client" )`}))
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @PythonClient( models = "attrs" )`}))
+}
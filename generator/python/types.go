@@ -0,0 +1,190 @@
+package python
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/generator/jsonHelpers"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+// pyClass is a struct of the parsed sources as model with the json-fields of the struct
+type pyClass struct {
+	Name        string
+	Description string
+	Fields      []pyField
+}
+
+type pyField struct {
+	JSONName string
+	Name     string
+	Type     string
+}
+
+// pyEnum is a @JsonEnum of the parsed sources, with its literals by their name in json
+type pyEnum struct {
+	Name        string
+	Description string
+	Literals    []pyLiteral
+}
+
+type pyLiteral struct {
+	JSONName string
+	Name     string
+}
+
+var primitives = map[string]string{
+	"string":        "str",
+	"bool":          "bool",
+	"int":           "int",
+	"int8":          "int",
+	"int16":         "int",
+	"int32":         "int",
+	"int64":         "int",
+	"uint":          "int",
+	"uint8":         "int",
+	"uint16":        "int",
+	"uint32":        "int",
+	"uint64":        "int",
+	"float32":       "float",
+	"float64":       "float",
+	"[]byte":        "str",
+	"time.Time":     "str",
+	"mydate.MyDate": "str",
+}
+
+var keywords = map[string]bool{
+	"False": true, "None": true, "True": true, "and": true, "as": true, "assert": true, "async": true, "await": true,
+	"break": true, "class": true, "continue": true, "def": true, "del": true, "elif": true, "else": true,
+	"except": true, "finally": true, "for": true, "from": true, "global": true, "if": true, "import": true,
+	"in": true, "is": true, "lambda": true, "nonlocal": true, "not": true, "or": true, "pass": true, "raise": true,
+	"return": true, "try": true, "while": true, "with": true, "yield": true, "self": true,
+}
+
+// pyTypes maps go-types onto type-hints of python, the same way as they are marshalled to json, and collects the
+// models and enums of the structs and enums that it meets
+type pyTypes struct {
+	parsedSources model.ParsedSources
+	classes       []*pyClass
+	enums         []*pyEnum
+	known         map[string]bool
+}
+
+func newPyTypes(parsedSources model.ParsedSources) *pyTypes {
+	return &pyTypes{parsedSources: parsedSources, known: map[string]bool{}}
+}
+
+// of returns the type-hint of a go-type. Times and dates are strings in the format of their json, types that are not
+// in the parsed sources are Any.
+func (t *pyTypes) of(typeName string) string {
+	if primitive, found := primitives[typeName]; found {
+		return primitive
+	}
+	if strings.HasPrefix(typeName, "*") {
+		return t.of(typeName[1:])
+	}
+	if strings.HasPrefix(typeName, "[]") {
+		return fmt.Sprintf("List[%s]", t.of(typeName[2:]))
+	}
+	if field := (model.Field{TypeName: typeName}); field.IsMap() {
+		keyType, valueType := field.SplitMapTypeNames()
+		return fmt.Sprintf("Dict[%s, %s]", t.of(keyType), t.of(valueType))
+	}
+	packageName, name := model.Field{TypeName: typeName}.SplitTypeName()
+	for _, e := range t.parsedSources.Enums {
+		if e.Name == name && (packageName == "" || e.PackageName == packageName) {
+			if !jsonHelpers.IsJSONEnum(e) {
+				// the literals of other enums are numbers
+				return "int"
+			}
+			if jsonHelpers.IsJSONEnumTolerant(e) {
+				// unknown names are accepted as well
+				return "str"
+			}
+			t.addEnum(e)
+			return e.Name
+		}
+	}
+	if s, found := generationUtil.FindStruct(t.parsedSources.Structs, typeName); found {
+		t.addClass(s)
+		return s.Name
+	}
+	for _, td := range t.parsedSources.Typedefs {
+		// structs and interfaces are typedefs as well, without a type
+		if td.Name == name && (packageName == "" || td.PackageName == packageName) && td.Type != "" {
+			return t.of(td.Type)
+		}
+	}
+	return "Any"
+}
+
+// nullableOf returns the type-hint of a go-type that can be null in json, like a pointer or a slice
+func (t *pyTypes) nullableOf(typeName string) string {
+	hint := t.of(typeName)
+	if strings.HasPrefix(typeName, "*") || strings.HasPrefix(typeName, "[]") || strings.HasPrefix(typeName, "map[") {
+		return fmt.Sprintf("Optional[%s]", hint)
+	}
+	return hint
+}
+
+func (t *pyTypes) addEnum(e model.Enum) {
+	if t.known[e.Name] {
+		return
+	}
+	t.known[e.Name] = true
+	enum := &pyEnum{Name: e.Name, Description: generationUtil.Description(e.DocLines)}
+	for idx, name := range jsonHelpers.GetJSONEnumNames(e) {
+		enum.Literals = append(enum.Literals, pyLiteral{JSONName: name, Name: strings.ToUpper(generationUtil.SnakeCase(e.EnumLiterals[idx].Name))})
+	}
+	t.enums = append(t.enums, enum)
+}
+
+func (t *pyTypes) addClass(s model.Struct) {
+	if t.known[s.Name] {
+		return
+	}
+	// registered before its fields, for structs that refer to themselves
+	t.known[s.Name] = true
+	class := &pyClass{Name: s.Name, Description: generationUtil.Description(s.DocLines)}
+	t.classes = append(t.classes, class)
+	class.Fields = t.fieldsOf(s)
+}
+
+// asyncJob returns the model of the job that an @Async rest-operation answers with
+func (t *pyTypes) asyncJob() string {
+	const name = "AsyncJob"
+	if !t.known[name] {
+		t.known[name] = true
+		class := &pyClass{
+			Name:        name,
+			Description: "AsyncJob is the status of an asynchronous rest-operation, with its result once completed",
+		}
+		for _, field := range []string{"id", "operation", "tenant", "status", "result", "error", "created", "completed"} {
+			hint := "str"
+			if field == "result" {
+				hint = "Any"
+			}
+			class.Fields = append(class.Fields, pyField{JSONName: field, Name: field, Type: hint})
+		}
+		t.classes = append(t.classes, class)
+	}
+	return name
+}
+
+// fieldsOf returns the json-fields of a struct as the snake-cased fields of its dataclass or pydantic-model
+func (t *pyTypes) fieldsOf(s model.Struct) []pyField {
+	fields := []pyField{}
+	for _, f := range generationUtil.JSONFields(s, t.parsedSources.Structs) {
+		fields = append(fields, pyField{JSONName: f.JSONName, Name: identifier(generationUtil.SnakeCase(f.Field.Name)), Type: t.of(f.Field.TypeName)})
+	}
+	return fields
+}
+
+// identifier returns a name that is a keyword in python with a trailing underscore, as pep 8 suggests
+func identifier(name string) string {
+	if keywords[name] {
+		return name + "_"
+	}
+	return name
+}
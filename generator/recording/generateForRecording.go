@@ -0,0 +1,114 @@
+package recording
+
+import (
+	"fmt"
+	"strconv"
+	"text/template"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/generator/recording/recordingAnnotation"
+	"github.com/MarcGrol/golangAnnotations/generator/rest"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+// defaultDir is below testdata, that the go-tool ignores
+const defaultDir = "testdata/fixtures"
+
+type recordingContext struct {
+	PackageName string
+	Name        string
+	Dir         string
+	Routes      []route
+}
+
+// route matches the requests of an operation
+type route struct {
+	Operation string
+	Method    string
+	Pattern   string
+}
+
+type Generator struct {
+}
+
+func NewGenerator() generator.Generator {
+	return &Generator{}
+}
+
+func (eg *Generator) Name() string {
+	return "recording"
+}
+
+func (eg *Generator) GetTemplates() []generator.Template {
+	return []generator.Template{
+		{Name: "recording", APIVersion: 1, Text: recordingTemplate},
+	}
+}
+
+func (eg *Generator) GetAnnotations() []annotation.AnnotationDescriptor {
+	return recordingAnnotation.Get()
+}
+
+func (eg *Generator) Generate(parsedSource model.ParsedSources, config generator.Config) ([]generator.OutputFile, error) {
+	return generate(config, parsedSource.Structs)
+}
+
+func generate(config generator.Config, structs []model.Struct) ([]generator.OutputFile, error) {
+	packageName, err := generationUtil.GetPackageNameForStructs(structs)
+	if packageName == "" || err != nil {
+		return nil, err
+	}
+	targetDir, err := generationUtil.DetermineTargetPath(config.InputDir, packageName)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []generator.OutputFile{}
+	for _, service := range structs {
+		if !rest.IsRestServiceRecorded(service) {
+			continue
+		}
+		if !rest.IsRestService(service) {
+			return nil, generator.StructError(service, recordingAnnotation.TypeRecording, "Struct %s with @Recording must be a @RestService", service.Name)
+		}
+		file, err := generationUtil.Generate(generationUtil.Info{
+			Src:            fmt.Sprintf("%s.%s", service.PackageName, service.Name),
+			TypeName:       service.Name,
+			TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/recording%s.go", targetDir, rest.ToFirstUpper(service.Name))),
+			TemplateName:   "recording",
+			TemplateString: recordingTemplate,
+			Config:         config,
+			FuncMap:        customTemplateFuncs,
+			Data:           newRecordingContext(service),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Error generating recording for service %s:%w", service.Name, err)
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+var customTemplateFuncs = template.FuncMap{
+	"Quoted":         strconv.Quote,
+	"LowerCamelCase": generationUtil.LowerCamelCase,
+}
+
+func newRecordingContext(service model.Struct) recordingContext {
+	ctx := recordingContext{PackageName: service.PackageName, Name: service.Name, Dir: defaultDir}
+	if ann, ok := annotation.NewRegistry(recordingAnnotation.Get()).ResolveAnnotationByName(service.DocLines, recordingAnnotation.TypeRecording); ok && ann.Attributes[recordingAnnotation.ParamDir] != "" {
+		ctx.Dir = ann.Attributes[recordingAnnotation.ParamDir]
+	}
+	for _, o := range service.Operations {
+		if rest.IsRestOperation(*o) {
+			ctx.Routes = append(ctx.Routes, route{
+				Operation: o.Name,
+				Method:    rest.GetRestOperationMethod(*o),
+				Pattern:   rest.GetRestOperationPathPattern(service, *o),
+			})
+		}
+	}
+	return ctx
+}
@@ -0,0 +1,105 @@
+package recording
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func cleanup() {
+	os.Remove(generationUtil.Prefixed("./testData/recordingPersonService.go"))
+}
+
+func TestGenerateRecording(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @RestService( path = "/api" )`, `// @Recording( dir = "testdata/persons" )`},
+			Name:        "PersonService",
+			Operations: []*model.Operation{
+				{
+					DocLines:   []string{`// @RestOperation( method = "GET", path = "/person/{uid}", format = "JSON" )`},
+					Name:       "getPerson",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}},
+					OutputArgs: []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+				},
+				{
+					DocLines:   []string{`// @RestOperation( method = "GET", path = "/person.csv", format = "CSV" )`},
+					Name:       "exportPersons",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}},
+					OutputArgs: []model.Field{{TypeName: "[]Person"}, {TypeName: "error"}},
+				},
+			},
+		},
+	}
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/recordingPersonService.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `const PersonServiceFixturesDir = "testdata/persons"`)
+	assert.Contains(t, string(data), `{"getPerson", "GET", regexp.MustCompile("^/api/person/[^/]+$")},`)
+	assert.Contains(t, string(data), `{"exportPersons", "GET", regexp.MustCompile("^/api/person\\.csv$")},`)
+	assert.Contains(t, string(data), "func NewPersonServiceRecorder(dir string, next http.RoundTripper) http.RoundTripper {")
+	assert.Contains(t, string(data), "func NewPersonServiceReplayer(dir string) http.RoundTripper {")
+	assert.Contains(t, string(data), "func NewPersonServiceProxy(dir string, upstream *url.URL) http.Handler {")
+}
+
+func TestGenerateRecordingInDefaultDir(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @RestService( path = "/api" )`, `// @Recording()`},
+			Name:        "PersonService",
+			Operations: []*model.Operation{
+				{
+					DocLines:   []string{`// @RestOperation( method = "GET", path = "/person/{uid}", format = "JSON" )`},
+					Name:       "getPerson",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}},
+					OutputArgs: []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+				},
+			},
+		},
+	}
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/recordingPersonService.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `const PersonServiceFixturesDir = "testdata/fixtures"`)
+}
+
+func TestGenerateRecordingForNoRestService(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @Recording()`},
+			Name:        "PersonService",
+			Operations: []*model.Operation{
+				{
+					DocLines:   []string{`// @RestOperation( method = "GET", path = "/person/{uid}", format = "JSON" )`},
+					Name:       "getPerson",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}},
+					OutputArgs: []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+				},
+			},
+		},
+	}
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Struct PersonService with @Recording must be a @RestService")
+}
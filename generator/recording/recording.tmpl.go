@@ -0,0 +1,199 @@
+package recording
+
+const recordingTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+
+package {{.PackageName}}
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+{{$service := .Name -}}
+// {{$service}}FixturesDir is the directory of the recorded responses of {{$service}}
+const {{$service}}FixturesDir = {{Quoted .Dir}}
+
+// {{$service}}Fixture is a recorded response to a request of {{$service}}
+type {{$service}}Fixture struct {
+	Operation   string      ` + "`" + `json:"operation"` + "`" + `
+	Method      string      ` + "`" + `json:"method"` + "`" + `
+	URL         string      ` + "`" + `json:"url"` + "`" + `
+	RequestBody string      ` + "`" + `json:"requestBody,omitempty"` + "`" + `
+	Status      int         ` + "`" + `json:"status"` + "`" + `
+	Header      http.Header ` + "`" + `json:"header,omitempty"` + "`" + `
+	Body        string      ` + "`" + `json:"body"` + "`" + `
+}
+
+var {{LowerCamelCase $service}}Routes = []struct {
+	operation string
+	method    string
+	path      *regexp.Regexp
+}{
+{{- range .Routes}}
+	{"{{.Operation}}", "{{.Method}}", regexp.MustCompile({{Quoted .Pattern}})},
+{{- end}}
+}
+
+// {{$service}}Operation returns the operation of {{$service}} that serves a request, or "" when there is none
+func {{$service}}Operation(r *http.Request) string {
+	for _, route := range {{LowerCamelCase $service}}Routes {
+		if route.method == r.Method && route.path.MatchString(r.URL.Path) {
+			return route.operation
+		}
+	}
+	return ""
+}
+
+// {{$service}}FixtureFilename returns the file in dir with the fixture of a request: requests with the same method,
+// url and body share their fixture
+func {{$service}}FixtureFilename(dir string, r *http.Request, requestBody []byte) string {
+	operation := {{$service}}Operation(r)
+	if operation == "" {
+		operation = "unknown"
+	}
+	hash := sha256.Sum256([]byte(r.Method + " " + r.URL.RequestURI() + "\n" + string(requestBody)))
+	return filepath.Join(dir, fmt.Sprintf("%s-%s.json", operation, hex.EncodeToString(hash[:6])))
+}
+
+// Record{{$service}}Fixture writes the response to a request as fixture into dir
+func Record{{$service}}Fixture(dir string, r *http.Request, requestBody []byte, status int, header http.Header, body []byte) error {
+	fixture := {{$service}}Fixture{
+		Operation:   {{$service}}Operation(r),
+		Method:      r.Method,
+		URL:         r.URL.RequestURI(),
+		RequestBody: string(requestBody),
+		Status:      status,
+		Header:      header,
+		Body:        string(body),
+	}
+	data, err := json.MarshalIndent(fixture, "", "\t")
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile({{$service}}FixtureFilename(dir, r, requestBody), data, 0644)
+}
+
+// Load{{$service}}Fixture reads the fixture of a request from dir: the error satisfies os.IsNotExist when the request
+// was not recorded
+func Load{{$service}}Fixture(dir string, r *http.Request, requestBody []byte) ({{$service}}Fixture, error) {
+	fixture := {{$service}}Fixture{}
+	data, err := ioutil.ReadFile({{$service}}FixtureFilename(dir, r, requestBody))
+	if err != nil {
+		return fixture, err
+	}
+	err = json.Unmarshal(data, &fixture)
+	return fixture, err
+}
+
+type {{LowerCamelCase $service}}Transport func(r *http.Request) (*http.Response, error)
+
+func (t {{LowerCamelCase $service}}Transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	return t(r)
+}
+
+// New{{$service}}Recorder returns a transport for clients of {{$service}} that records the responses of next as
+// fixtures into dir
+func New{{$service}}Recorder(dir string, next http.RoundTripper) http.RoundTripper {
+	return {{LowerCamelCase $service}}Transport(func(r *http.Request) (*http.Response, error) {
+		requestBody, err := read{{$service}}Body(&r.Body)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := next.RoundTrip(r)
+		if err != nil {
+			return nil, err
+		}
+		body, err := read{{$service}}Body(&resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		err = Record{{$service}}Fixture(dir, r, requestBody, resp.StatusCode, resp.Header, body)
+		if err != nil {
+			return nil, fmt.Errorf("Error recording fixture of %s %s: %s", r.Method, r.URL, err)
+		}
+		return resp, nil
+	})
+}
+
+// New{{$service}}Replayer returns a transport for clients of {{$service}} that responds with the fixtures in dir,
+// without calling the service: requests that were not recorded fail
+func New{{$service}}Replayer(dir string) http.RoundTripper {
+	return {{LowerCamelCase $service}}Transport(func(r *http.Request) (*http.Response, error) {
+		requestBody, err := read{{$service}}Body(&r.Body)
+		if err != nil {
+			return nil, err
+		}
+		fixture, err := Load{{$service}}Fixture(dir, r, requestBody)
+		if err != nil {
+			return nil, fmt.Errorf("Error replaying %s %s: %s", r.Method, r.URL, err)
+		}
+		header := fixture.Header
+		if header == nil {
+			header = http.Header{}
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", fixture.Status, http.StatusText(fixture.Status)),
+			StatusCode:    fixture.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          ioutil.NopCloser(bytes.NewBufferString(fixture.Body)),
+			ContentLength: int64(len(fixture.Body)),
+			Request:       r,
+		}, nil
+	})
+}
+
+// New{{$service}}Proxy returns a handler for clients that cannot change their transport, like web-applications: it
+// forwards requests to upstream and records the responses into dir or, without upstream, replays them from dir
+func New{{$service}}Proxy(dir string, upstream *url.URL) http.Handler {
+	if upstream == nil {
+		replayer := New{{$service}}Replayer(dir)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			resp, err := replayer.RoundTrip(r)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadGateway)
+				return
+			}
+			for key, values := range resp.Header {
+				w.Header()[key] = values
+			}
+			w.WriteHeader(resp.StatusCode)
+			_, _ = io.Copy(w, resp.Body)
+		})
+	}
+	proxy := httputil.NewSingleHostReverseProxy(upstream)
+	proxy.Transport = New{{$service}}Recorder(dir, http.DefaultTransport)
+	return proxy
+}
+
+// read{{$service}}Body reads a body and replaces it by a copy, so that it can be read again
+func read{{$service}}Body(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	data, err := ioutil.ReadAll(*body)
+	if err != nil {
+		return nil, err
+	}
+	(*body).Close()
+	*body = ioutil.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+`
@@ -0,0 +1,25 @@
+package recordingAnnotation
+
+import "github.com/MarcGrol/golangAnnotations/generator/annotation"
+
+const (
+	TypeRecording = "Recording"
+	ParamDir      = "dir"
+)
+
+func Get() []annotation.AnnotationDescriptor {
+	return []annotation.AnnotationDescriptor{
+		{
+			Name:       TypeRecording,
+			ParamNames: []string{ParamDir},
+			Validator:  validateRecordingAnnotation,
+		}}
+}
+
+func validateRecordingAnnotation(annot annotation.Annotation) bool {
+	if annot.Name == TypeRecording {
+		dir, hasDir := annot.Attributes[ParamDir]
+		return !hasDir || dir != ""
+	}
+	return false
+}
@@ -0,0 +1,23 @@
+package recordingAnnotation
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrectRecordingAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	annotation, ok := registry.ResolveAnnotation(`// @Recording( dir = "testdata/persons" )`)
+	assert.True(t, ok)
+	assert.Equal(t, TypeRecording, annotation.Name)
+	assert.Equal(t, "testdata/persons", annotation.Attributes[ParamDir])
+}
+
+func TestInvalidRecordingAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @Recording( dir = "" )`}))
+}
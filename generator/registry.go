@@ -0,0 +1,102 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DependencyProvider is implemented by generators whose output refers to code that other generators generate
+type DependencyProvider interface {
+	GetDependencies() []string
+}
+
+// Registry holds the generators of a run by name
+type Registry struct {
+	generators map[string]Generator
+}
+
+func NewRegistry(generators ...Generator) *Registry {
+	r := &Registry{generators: map[string]Generator{}}
+	for _, g := range generators {
+		r.MustRegister(g)
+	}
+	return r
+}
+
+// Register adds a generator: names must be unique
+func (r *Registry) Register(g Generator) error {
+	if _, exists := r.generators[g.Name()]; exists {
+		return fmt.Errorf("Generator %s is already registered", g.Name())
+	}
+	r.generators[g.Name()] = g
+	return nil
+}
+
+func (r *Registry) MustRegister(g Generator) {
+	err := r.Register(g)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func (r *Registry) Get(name string) (Generator, bool) {
+	g, found := r.generators[name]
+	return g, found
+}
+
+// Names returns the names of all registered generators in alphabetical order
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.generators))
+	for name := range r.generators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// All returns all registered generators ordered by name
+func (r *Registry) All() []Generator {
+	generators := []Generator{}
+	for _, name := range r.Names() {
+		generators = append(generators, r.generators[name])
+	}
+	return generators
+}
+
+// Select returns the generators in only, or all generators when only is empty, except the ones in skip. It fails
+// on unknown names and when a selected generator depends on the output of a generator that is not selected.
+func (r *Registry) Select(only []string, skip []string) ([]Generator, error) {
+	for _, name := range append(append([]string{}, only...), skip...) {
+		if _, found := r.Get(name); !found {
+			return nil, fmt.Errorf("Unknown generator %s: choose from %s", name, strings.Join(r.Names(), ", "))
+		}
+	}
+	isSelected := func(name string) bool {
+		return (len(only) == 0 || contains(only, name)) && !contains(skip, name)
+	}
+	selected := []Generator{}
+	for _, g := range r.All() {
+		if !isSelected(g.Name()) {
+			continue
+		}
+		if provider, ok := g.(DependencyProvider); ok {
+			for _, dependency := range provider.GetDependencies() {
+				if _, found := r.Get(dependency); found && !isSelected(dependency) {
+					return nil, fmt.Errorf("Generator %s needs the output of generator %s: select %s as well", g.Name(), dependency, dependency)
+				}
+			}
+		}
+		selected = append(selected, g)
+	}
+	return selected, nil
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
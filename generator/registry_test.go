@@ -0,0 +1,99 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+type namedGenerator struct {
+	name string
+}
+
+func (g namedGenerator) Name() string {
+	return g.name
+}
+
+func (g namedGenerator) GetAnnotations() []annotation.AnnotationDescriptor {
+	return nil
+}
+
+func (g namedGenerator) Generate(parsedSources model.ParsedSources, config Config) ([]OutputFile, error) {
+	return nil, nil
+}
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry(namedGenerator{name: "b"}, namedGenerator{name: "a"})
+	assert.Equal(t, []string{"a", "b"}, registry.Names())
+	assert.Equal(t, "a", registry.All()[0].Name())
+
+	_, found := registry.Get("c")
+	assert.False(t, found)
+
+	err := registry.Register(namedGenerator{name: "c"})
+	assert.NoError(t, err)
+	_, found = registry.Get("c")
+	assert.True(t, found)
+}
+
+func TestRegistryRejectsDuplicateNames(t *testing.T) {
+	registry := NewRegistry(namedGenerator{name: "a"})
+	err := registry.Register(namedGenerator{name: "a"})
+	assert.EqualError(t, err, "Generator a is already registered")
+}
+
+type dependentGenerator struct {
+	namedGenerator
+	dependencies []string
+}
+
+func (g dependentGenerator) GetDependencies() []string {
+	return g.dependencies
+}
+
+func names(generators []Generator) []string {
+	names := []string{}
+	for _, g := range generators {
+		names = append(names, g.Name())
+	}
+	return names
+}
+
+func TestRegistrySelect(t *testing.T) {
+	registry := NewRegistry(namedGenerator{name: "a"}, namedGenerator{name: "b"}, namedGenerator{name: "c"})
+
+	selected, err := registry.Select(nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, names(selected))
+
+	selected, err = registry.Select([]string{"c", "a"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "c"}, names(selected))
+
+	selected, err = registry.Select(nil, []string{"b"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "c"}, names(selected))
+
+	_, err = registry.Select([]string{"d"}, nil)
+	assert.EqualError(t, err, "Unknown generator d: choose from a, b, c")
+}
+
+func TestRegistrySelectWithDependencies(t *testing.T) {
+	registry := NewRegistry(namedGenerator{name: "event"}, dependentGenerator{namedGenerator: namedGenerator{name: "event-service"}, dependencies: []string{"event"}})
+
+	selected, err := registry.Select([]string{"event-service", "event"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"event", "event-service"}, names(selected))
+
+	_, err = registry.Select([]string{"event-service"}, nil)
+	assert.EqualError(t, err, "Generator event-service needs the output of generator event: select event as well")
+
+	_, err = registry.Select(nil, []string{"event"})
+	assert.Error(t, err)
+
+	selected, err = registry.Select([]string{"event"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"event"}, names(selected))
+}
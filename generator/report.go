@@ -0,0 +1,142 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+)
+
+// Statuses of the files in a report
+const (
+	FileWritten   = "written"
+	FileUnchanged = "unchanged"
+	FileStale     = "stale"
+	FileRemoved   = "removed"
+)
+
+// Severities of diagnostics in a report
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Report is the machine-readable outcome of a run, meant for tooling that acts on the results
+type Report struct {
+	Files       []ReportFile      `json:"files"`
+	Diagnostics []Diagnostic      `json:"diagnostics"`
+	Generators  []GeneratorReport `json:"generators"`
+	DurationMs  int64             `json:"durationMs"`
+}
+
+// ReportFile is a generated file with what the run did with it
+type ReportFile struct {
+	Filename  string `json:"filename"`
+	Generator string `json:"generator,omitempty"`
+	Src       string `json:"src,omitempty"`
+	Status    string `json:"status"`
+}
+
+// Diagnostic is an error or warning of the run
+type Diagnostic struct {
+	Severity  string `json:"severity"`
+	Generator string `json:"generator,omitempty"`
+	InputDir  string `json:"inputDir,omitempty"`
+	// Filename, Line and Declaration locate errors in annotated sources
+	Filename    string `json:"filename,omitempty"`
+	Line        int    `json:"line,omitempty"`
+	Declaration string `json:"declaration,omitempty"`
+	Message     string `json:"message"`
+}
+
+// GeneratorReport tells how long a generator took for an input-dir and how many files it rendered
+type GeneratorReport struct {
+	Generator  string `json:"generator"`
+	InputDir   string `json:"inputDir"`
+	FileCount  int    `json:"fileCount"`
+	DurationMs int64  `json:"durationMs"`
+}
+
+// NewReport returns an empty report: its lists are empty instead of null when nothing is added
+func NewReport() *Report {
+	return &Report{
+		Files:       []ReportFile{},
+		Diagnostics: []Diagnostic{},
+		Generators:  []GeneratorReport{},
+	}
+}
+
+// AddTask reports the timing and outcome of a rendered task
+func (r *Report) AddTask(task Task, result TaskResult) {
+	r.Generators = append(r.Generators, GeneratorReport{
+		Generator:  task.Generator.Name(),
+		InputDir:   task.Config.InputDir,
+		FileCount:  len(result.Files),
+		DurationMs: milliseconds(result.Duration),
+	})
+	if result.Err != nil {
+		err := result.Err
+		// the diagnostic has the generator and input-dir of the task already
+		if taskError, ok := err.(TaskError); ok {
+			err = taskError.Err
+		}
+		diagnostic := NewDiagnostic(SeverityError, err)
+		diagnostic.Generator = task.Generator.Name()
+		diagnostic.InputDir = task.Config.InputDir
+		r.AddDiagnostic(diagnostic)
+	}
+}
+
+// NewDiagnostic returns the diagnostic for err, located in the annotated source when err is a SourceError
+func NewDiagnostic(severity string, err error) Diagnostic {
+	diagnostic := Diagnostic{Severity: severity, Message: err.Error()}
+	if sourceError, ok := AsSourceError(err); ok {
+		diagnostic.Filename = sourceError.Filename
+		diagnostic.Line = sourceError.Line
+		diagnostic.Declaration = sourceError.Declaration
+		diagnostic.Message = sourceError.Message
+	}
+	return diagnostic
+}
+
+// AddFile reports what happened to a rendered file
+func (r *Report) AddFile(generatorName string, f OutputFile, status string) {
+	r.Files = append(r.Files, ReportFile{
+		Filename:  f.Filename,
+		Generator: generatorName,
+		Src:       f.Src,
+		Status:    status,
+	})
+}
+
+func (r *Report) AddDiagnostic(d Diagnostic) {
+	r.Diagnostics = append(r.Diagnostics, d)
+}
+
+// Finish records the duration of the run since start
+func (r *Report) Finish(start time.Time) {
+	r.DurationMs = milliseconds(time.Since(start))
+}
+
+// Write stores the report as json in filename, or prints it on stdout when filename is "-"
+func (r *Report) Write(filename string) error {
+	data, err := json.MarshalIndent(r, "", "\t")
+	if err != nil {
+		return fmt.Errorf("Error marshalling report:%w", err)
+	}
+	data = append(data, '\n')
+	if filename == "-" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	err = ioutil.WriteFile(filename, data, 0644)
+	if err != nil {
+		return fmt.Errorf("Error writing report %s:%w", filename, err)
+	}
+	return nil
+}
+
+func milliseconds(d time.Duration) int64 {
+	return int64(d / time.Millisecond)
+}
@@ -0,0 +1,40 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReport(t *testing.T) {
+	report := NewReport()
+	task := Task{Generator: namedGenerator{name: "rest"}, Config: Config{InputDir: "."}}
+	report.AddTask(task, TaskResult{Files: []OutputFile{{Filename: "gen_a.go"}}, Duration: 1500 * time.Microsecond})
+	report.AddTask(task, TaskResult{Err: fmt.Errorf("Broken")})
+	report.AddFile("rest", OutputFile{Filename: "gen_a.go", Src: "a.Service"}, FileWritten)
+
+	filename := filepath.Join(t.TempDir(), "report.json")
+	assert.NoError(t, report.Write(filename))
+	data, err := ioutil.ReadFile(filename)
+	assert.NoError(t, err)
+
+	written := Report{}
+	assert.NoError(t, json.Unmarshal(data, &written))
+	assert.Equal(t, []ReportFile{{Filename: "gen_a.go", Generator: "rest", Src: "a.Service", Status: FileWritten}}, written.Files)
+	assert.Equal(t, []GeneratorReport{
+		{Generator: "rest", InputDir: ".", FileCount: 1, DurationMs: 1},
+		{Generator: "rest", InputDir: ".", FileCount: 0, DurationMs: 0},
+	}, written.Generators)
+	assert.Equal(t, []Diagnostic{{Severity: SeverityError, Generator: "rest", InputDir: ".", Message: "Broken"}}, written.Diagnostics)
+}
+
+func TestEmptyReportHasEmptyLists(t *testing.T) {
+	data, err := json.Marshal(NewReport())
+	assert.NoError(t, err)
+	assert.Equal(t, `{"files":[],"diagnostics":[],"generators":[],"durationMs":0}`, string(data))
+}
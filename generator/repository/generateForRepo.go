@@ -2,7 +2,6 @@ package repository
 
 import (
 	"fmt"
-	"log"
 	"strings"
 	"text/template"
 	"unicode"
@@ -11,6 +10,8 @@ import (
 	"github.com/MarcGrol/golangAnnotations/generator/annotation"
 	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
 	"github.com/MarcGrol/golangAnnotations/generator/repository/repositoryAnnotation"
+	"github.com/MarcGrol/golangAnnotations/generator/tenant"
+	"github.com/MarcGrol/golangAnnotations/generator/tenant/tenantAnnotation"
 	"github.com/MarcGrol/golangAnnotations/model"
 )
 
@@ -21,35 +22,66 @@ func NewGenerator() generator.Generator {
 	return &Generator{}
 }
 
+func (eg *Generator) Name() string {
+	return "repository"
+}
+
+func (eg *Generator) GetTemplates() []generator.Template {
+	return []generator.Template{
+		{Name: "repository", APIVersion: 1, Text: repositoryTemplate},
+	}
+}
+
 func (eg *Generator) GetAnnotations() []annotation.AnnotationDescriptor {
-	return repositoryAnnotation.Get()
+	return append(repositoryAnnotation.Get(), tenantAnnotation.Get()...)
 }
 
-func (eg *Generator) Generate(inputDir string, parsedSource model.ParsedSources) error {
+func (eg *Generator) Generate(parsedSource model.ParsedSources, config generator.Config) ([]generator.OutputFile, error) {
 	structs := parsedSource.Structs
 
 	packageName, err := generationUtil.GetPackageNameForStructs(structs)
 	if packageName == "" || err != nil {
-		return err
+		return nil, err
 	}
-	targetDir, err := generationUtil.DetermineTargetPath(inputDir, packageName)
+	targetDir, err := generationUtil.DetermineTargetPath(config.InputDir, packageName)
 	if err != nil {
-		return err
+		return nil, err
 	}
+
+	files := []generator.OutputFile{}
 	for _, repository := range structs {
 		if IsRepository(repository) {
-			err = generationUtil.Generate(generationUtil.Info{
+			err = validateTenant(repository)
+			if err != nil {
+				return nil, err
+			}
+			file, err := generationUtil.Generate(generationUtil.Info{
 				Src:            fmt.Sprintf("%s.%s", repository.PackageName, repository.Name),
+				TypeName:       repository.Name,
 				TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/%s.go", targetDir, toFirstLower(repository.Name))),
 				TemplateName:   "repository",
 				TemplateString: repositoryTemplate,
+				Config:         config,
 				FuncMap:        customTemplateFuncs,
 				Data:           repository,
 			})
 			if err != nil {
-				log.Fatalf("Error generating repository %s: %s", repository.Name, err)
-				return err
+				return nil, fmt.Errorf("Error generating repository %s:%w", repository.Name, err)
 			}
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+// validateTenant rejects the methods of a tenant-scoped repository that access the aggregates of all tenants
+func validateTenant(repository model.Struct) error {
+	if !IsTenantScoped(repository) {
+		return nil
+	}
+	for _, method := range []string{"exists", "allAggregateUIDs"} {
+		if HasMethod(repository, method) {
+			return generator.StructError(repository, repositoryAnnotation.TypeRepository, "Method '%s' of tenant-scoped repository %s accesses aggregates without a tenant", method, repository.Name)
 		}
 	}
 	return nil
@@ -57,6 +89,9 @@ func (eg *Generator) Generate(inputDir string, parsedSource model.ParsedSources)
 
 var customTemplateFuncs = template.FuncMap{
 	"IsRepository":              IsRepository,
+	"IsTenantScoped":            IsTenantScoped,
+	"TenantParam":               TenantParam,
+	"TenantArg":                 TenantArg,
 	"AggregateNameConst":        AggregateNameConst,
 	"LowerAggregateName":        LowerAggregateName,
 	"UpperAggregateName":        UpperAggregateName,
@@ -82,6 +117,25 @@ func IsRepository(s model.Struct) bool {
 	return ok
 }
 
+func IsTenantScoped(s model.Struct) bool {
+	return IsRepository(s) && tenant.IsTenantScoped(s.DocLines)
+}
+
+// TenantParam returns the parameter for the tenant of the methods of a tenant-scoped repository
+func TenantParam(s model.Struct) string {
+	if !IsTenantScoped(s) {
+		return ""
+	}
+	return tenant.ArgName + " string, "
+}
+
+func TenantArg(s model.Struct) string {
+	if !IsTenantScoped(s) {
+		return ""
+	}
+	return tenant.ArgName + ", "
+}
+
 func AggregateNameConst(s model.Struct) string {
 	return fmt.Sprintf("%sAggregateName", UpperAggregateName(s))
 }
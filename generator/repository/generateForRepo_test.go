@@ -5,6 +5,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/MarcGrol/golangAnnotations/generator"
 	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
 	"github.com/MarcGrol/golangAnnotations/model"
 	"github.com/stretchr/testify/assert"
@@ -27,7 +28,7 @@ func TestGenerateForRepo(t *testing.T) {
 		},
 	}
 
-	err := NewGenerator().Generate("testData", model.ParsedSources{Structs: s})
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
 	assert.Nil(t, err)
 
 	// check that generated files exisst
@@ -39,4 +40,53 @@ func TestGenerateForRepo(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Contains(t, string(data), `func DefaultFindEndUserOnUID(c context.Context, rc request.Context, tx *datastore.Transaction, endUserUID string) (*endUserModel.EndUser, error) {
 `)
+	assert.Contains(t, string(data), `func GetEndUserVersionOnUID(c context.Context, rc request.Context, tx *datastore.Transaction, endUserUID string) (int64, error) {
+`)
+}
+
+func TestGenerateForTenantScopedRepo(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			DocLines: []string{
+				`// @Repository( aggregate = "User", model="EndUser", package="testEvents", methods="find,findStates,allAggregates" )`,
+				`// @TenantScoped()`,
+			},
+			PackageName: "testData",
+			Name:        "UserRepo",
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Nil(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/userRepo.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `func DefaultFindEndUserOnUID(c context.Context, rc request.Context, tx *datastore.Transaction, tenantID string, endUserUID string) (*endUserModel.EndUser, error) {
+`)
+	assert.Contains(t, string(data), "envelopeFilter = testEvents.UserTenantFilter{TenantID: tenantID, Next: envelopeFilter}")
+	assert.Contains(t, string(data), "envelopes, err = testEvents.UserTenantFilter{TenantID: tenantID}.FilteredEnvelopes(envelopes)")
+	assert.Contains(t, string(data), "envlpTenantID, err := testEvents.GetUserTenantID(&envlp)")
+}
+
+func TestTenantScopedRepoWithoutTenant(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			DocLines: []string{
+				`// @Repository( aggregate = "User", methods="find,exists" )`,
+				`// @TenantScoped()`,
+			},
+			PackageName: "testData",
+			Name:        "UserRepo",
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Method 'exists' of tenant-scoped repository UserRepo accesses aggregates without a tenant")
 }
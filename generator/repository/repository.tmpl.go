@@ -13,33 +13,38 @@ import (
 {{if HasMethodFind . -}}
 var Find{{UpperModelName .}}OnUID = DefaultFind{{UpperModelName .}}OnUID
 
-func DefaultFind{{UpperModelName .}}OnUID(c context.Context, rc request.Context, tx *datastore.Transaction, {{LowerModelName .}}UID string) (*{{ModelPackageName .}}.{{UpperModelName .}}, error) {
-	{{LowerModelName .}}, _, err := DoFind{{UpperModelName .}}OnUID(c, rc, tx, {{LowerModelName .}}UID, envelope.AcceptAll)
+func DefaultFind{{UpperModelName .}}OnUID(c context.Context, rc request.Context, tx *datastore.Transaction, {{TenantParam .}}{{LowerModelName .}}UID string) (*{{ModelPackageName .}}.{{UpperModelName .}}, error) {
+	{{LowerModelName .}}, _, err := DoFind{{UpperModelName .}}OnUID(c, rc, tx, {{TenantArg .}}{{LowerModelName .}}UID, envelope.AcceptAll)
 	return {{LowerModelName .}}, err
 }
 
 {{if HasMethodFilterByEvent . -}}
-func Find{{UpperModelName .}}OnUIDAndEvent(c context.Context, rc request.Context, tx *datastore.Transaction, {{LowerModelName .}}UID string, metadata eventMetaData.Metadata) (*{{ModelPackageName .}}.{{UpperModelName .}}, error) {
-	{{LowerModelName .}}, _, err := DoFind{{UpperModelName .}}OnUID(c, rc, tx, {{LowerModelName .}}UID, envelope.FilterByEventUID{EventUID: metadata.UUID})
+func Find{{UpperModelName .}}OnUIDAndEvent(c context.Context, rc request.Context, tx *datastore.Transaction, {{TenantParam .}}{{LowerModelName .}}UID string, metadata eventMetaData.Metadata) (*{{ModelPackageName .}}.{{UpperModelName .}}, error) {
+	{{LowerModelName .}}, _, err := DoFind{{UpperModelName .}}OnUID(c, rc, tx, {{TenantArg .}}{{LowerModelName .}}UID, envelope.FilterByEventUID{EventUID: metadata.UUID})
 	return {{LowerModelName .}}, err
 }
 
 {{end -}}
 
 {{if HasMethodFilterByMoment . -}}
-func Find{{UpperModelName .}}OnUIDAndMoment(c context.Context, rc request.Context, tx *datastore.Transaction, {{LowerModelName .}}UID string, moment time.Time) (*{{ModelPackageName .}}.{{UpperModelName .}}, error) {
-	{{LowerModelName .}}, _, err := DoFind{{UpperModelName .}}OnUID(c, rc, tx, {{LowerModelName .}}UID, envelope.FilterByMoment{Moment: moment})
+func Find{{UpperModelName .}}OnUIDAndMoment(c context.Context, rc request.Context, tx *datastore.Transaction, {{TenantParam .}}{{LowerModelName .}}UID string, moment time.Time) (*{{ModelPackageName .}}.{{UpperModelName .}}, error) {
+	{{LowerModelName .}}, _, err := DoFind{{UpperModelName .}}OnUID(c, rc, tx, {{TenantArg .}}{{LowerModelName .}}UID, envelope.FilterByMoment{Moment: moment})
 	return {{LowerModelName .}}, err
 }
 
 {{end -}}
 
-func DoFind{{UpperModelName .}}OnUID(c context.Context, rc request.Context, tx *datastore.Transaction, {{LowerModelName .}}UID string, envelopeFilter envelope.EnvelopeFilter) (*{{ModelPackageName .}}.{{UpperModelName .}}, []envelope.Envelope, error) {
+func DoFind{{UpperModelName .}}OnUID(c context.Context, rc request.Context, tx *datastore.Transaction, {{TenantParam .}}{{LowerModelName .}}UID string, envelopeFilter envelope.EnvelopeFilter) (*{{ModelPackageName .}}.{{UpperModelName .}}, []envelope.Envelope, error) {
 	envelopes, err := doFind{{UpperModelName .}}EnvelopesOnUID(c, rc, tx, {{LowerModelName .}}UID)
 	if err != nil {
 		return nil, nil, err
 	}
 
+	{{if IsTenantScoped . -}}
+	// the aggregate of another tenant is not found
+	envelopeFilter = {{GetPackageName .}}.{{UpperAggregateName .}}TenantFilter{TenantID: tenantID, Next: envelopeFilter}
+	{{end -}}
+
 	envelopes, err = envelopeFilter.FilteredEnvelopes(envelopes)
 	if err != nil {
 		return nil, nil, errorh.NewInternalErrorf(0, "Failed to filter events for {{LowerModelName .}} with uid %s: %s", {{LowerModelName .}}UID, err)
@@ -57,6 +62,15 @@ func DoFind{{UpperModelName .}}OnUID(c context.Context, rc request.Context, tx *
 	return {{LowerModelName .}}, envelopes, nil
 }
 
+// Get{{UpperModelName .}}VersionOnUID returns the version of a {{LowerModelName .}}: the sequence-number of its last event
+func Get{{UpperModelName .}}VersionOnUID(c context.Context, rc request.Context, tx *datastore.Transaction, {{TenantParam .}}{{LowerModelName .}}UID string) (int64, error) {
+	_, envelopes, err := DoFind{{UpperModelName .}}OnUID(c, rc, tx, {{TenantArg .}}{{LowerModelName .}}UID, envelope.AcceptAll)
+	if err != nil {
+		return 0, err
+	}
+	return envelopes[len(envelopes)-1].SequenceNumber, nil
+}
+
 func doFind{{UpperModelName .}}EnvelopesOnUID(c context.Context, rc request.Context, tx *datastore.Transaction, {{LowerModelName .}}UID string) ([]envelope.Envelope, error) {
 	envelopes, err := eventStoreInstance.Search(c, rc, tx, {{GetPackageName .}}.{{AggregateNameConst .}}, {{LowerModelName .}}UID)
 	if err != nil {
@@ -73,12 +87,23 @@ func doFind{{UpperModelName .}}EnvelopesOnUID(c context.Context, rc request.Cont
 {{end -}}
 
 {{if HasMethodFindStates . -}}
-	func Find{{UpperModelName .}}StatesOnUID(c context.Context, rc request.Context, tx *datastore.Transaction, {{LowerModelName .}}UID string) ([]{{ModelPackageName .}}.{{UpperModelName .}}, error) {
+	func Find{{UpperModelName .}}StatesOnUID(c context.Context, rc request.Context, tx *datastore.Transaction, {{TenantParam .}}{{LowerModelName .}}UID string) ([]{{ModelPackageName .}}.{{UpperModelName .}}, error) {
 	envelopes, err := doFind{{UpperModelName .}}EnvelopesOnUID(c, rc, tx, {{LowerModelName .}}UID)
 	if err != nil {
 		return nil, err
 	}
 
+	{{if IsTenantScoped . -}}
+	envelopes, err = {{GetPackageName .}}.{{UpperAggregateName .}}TenantFilter{TenantID: tenantID}.FilteredEnvelopes(envelopes)
+	if err != nil {
+		return nil, errorh.NewInternalErrorf(0, "Failed to filter events for {{LowerModelName .}} with uid %s: %s", {{LowerModelName .}}UID, err)
+	}
+	if len(envelopes) == 0 {
+		return nil, errorh.NewNotFoundErrorf(0, "{{UpperModelName .}} with uid %s not found", {{LowerModelName .}}UID)
+	}
+
+	{{end -}}
+
 	states := make([]{{ModelPackageName .}}.{{UpperModelName .}}, 0, len(envelopes))
 	{{LowerModelName .}} := {{ModelPackageName .}}.New{{UpperModelName .}}()
 	for _, envlp := range envelopes {
@@ -116,14 +141,23 @@ func GetAll{{UpperModelName .}}UIDs(c context.Context, rc request.Context) ([]st
 {{end -}}
 
 {{if HasMethodGetAllAggregates . -}}
-func GetAllRecent{{UpperModelName .}}s(c context.Context, rc request.Context, optOffset time.Time) ([]{{ModelPackageName .}}.{{UpperModelName .}}, error) {
-			{{LowerModelName .}}, _, err := DoGetAllRecent{{UpperModelName .}}s(c, rc, optOffset)
+func GetAllRecent{{UpperModelName .}}s(c context.Context, rc request.Context, {{TenantParam .}}optOffset time.Time) ([]{{ModelPackageName .}}.{{UpperModelName .}}, error) {
+			{{LowerModelName .}}, _, err := DoGetAllRecent{{UpperModelName .}}s(c, rc, {{TenantArg .}}optOffset)
 	return {{LowerModelName .}}, err
 }
 
-func DoGetAllRecent{{UpperModelName .}}s(c context.Context, rc request.Context, optOffset time.Time) ([]{{ModelPackageName .}}.{{UpperModelName .}}, map[string][]envelope.Envelope, error) {
+func DoGetAllRecent{{UpperModelName .}}s(c context.Context, rc request.Context, {{TenantParam .}}optOffset time.Time) ([]{{ModelPackageName .}}.{{UpperModelName .}}, map[string][]envelope.Envelope, error) {
 			{{LowerModelName .}}Map := map[string][]envelope.Envelope{}
 	err := eventStoreInstance.IterateWithOffset(c, rc, {{GetPackageName .}}.{{AggregateNameConst .}}, optOffset, func(envlp envelope.Envelope) error {
+		{{if IsTenantScoped . -}}
+		envlpTenantID, err := {{GetPackageName .}}.Get{{UpperAggregateName .}}TenantID(&envlp)
+		if err != nil {
+			return err
+		}
+		if envlpTenantID != tenantID {
+			return nil
+		}
+		{{end -}}
 		envelopes, exists := {{LowerModelName .}}Map[envlp.AggregateUID]
 		if !exists {
 			envelopes = []envelope.Envelope{}
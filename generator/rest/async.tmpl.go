@@ -0,0 +1,218 @@
+package rest
+
+const asyncTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+
+package {{.PackageName}}
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// AsyncJobAggregateName is the aggregate of the completion-events of the asynchronous operations of this package
+	AsyncJobAggregateName = "AsyncJob"
+	// AsyncJobCompletedEventName is the event-type of the completion-events on the event-bus
+	AsyncJobCompletedEventName = "AsyncJobCompleted"
+)
+
+// Statuses of asynchronous jobs
+const (
+	AsyncJobPending   = "pending"
+	AsyncJobCompleted = "completed"
+	AsyncJobFailed    = "failed"
+)
+
+// AsyncJob is a call of an operation with @Async, that clients poll until it is completed or failed
+type AsyncJob struct {
+	ID        string          ` + "`" + `json:"id"` + "`" + `
+	Operation string          ` + "`" + `json:"operation"` + "`" + `
+	Tenant    string          ` + "`" + `json:"tenant,omitempty"` + "`" + `
+	Status    string          ` + "`" + `json:"status"` + "`" + `
+	Result    json.RawMessage ` + "`" + `json:"result,omitempty"` + "`" + `
+	Error     string          ` + "`" + `json:"error,omitempty"` + "`" + `
+	Created   time.Time       ` + "`" + `json:"created"` + "`" + `
+	Completed *time.Time      ` + "`" + `json:"completed,omitempty"` + "`" + `
+}
+
+// AsyncJobStore keeps asynchronous jobs by their ID: Get returns none for an unknown ID
+type AsyncJobStore interface {
+	Get(c context.Context, rc request.Context, id string) (*AsyncJob, error)
+	Put(c context.Context, rc request.Context, job AsyncJob) error
+}
+
+// asyncJobTTL is how long the in-memory store keeps jobs
+const asyncJobTTL = 24 * time.Hour
+
+type memoryAsyncJobStore struct {
+	sync.Mutex
+	jobs map[string]AsyncJob
+}
+
+func (s *memoryAsyncJobStore) Get(c context.Context, rc request.Context, id string) (*AsyncJob, error) {
+	s.Lock()
+	defer s.Unlock()
+	job, found := s.jobs[id]
+	if !found || time.Since(job.Created) > asyncJobTTL {
+		delete(s.jobs, id)
+		return nil, nil
+	}
+	return &job, nil
+}
+
+func (s *memoryAsyncJobStore) Put(c context.Context, rc request.Context, job AsyncJob) error {
+	s.Lock()
+	defer s.Unlock()
+	s.jobs[job.ID] = job
+	return nil
+}
+
+var asyncJobStore AsyncJobStore = &memoryAsyncJobStore{jobs: map[string]AsyncJob{}}
+
+// SetAsyncJobStore replaces the in-memory store of asynchronous jobs, like with one that all instances of the
+// service share
+func SetAsyncJobStore(store AsyncJobStore) {
+	asyncJobStore = store
+}
+
+// detachedContext has the values of the context of a request, but not its deadline and cancellation: jobs outlive
+// the request that started them
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+func (c detachedContext) Value(key interface{}) interface{} {
+	return c.parent.Value(key)
+}
+
+// startAsyncJob stores a pending job for a call of an operation, with an ID that cannot be guessed
+func startAsyncJob(c context.Context, rc request.Context, operation string, tenant string) (AsyncJob, error) {
+	id := make([]byte, 16)
+	_, err := rand.Read(id)
+	if err != nil {
+		return AsyncJob{}, errorh.NewInternalErrorf(0, "Error creating job of %s: %s", operation, err)
+	}
+	job := AsyncJob{
+		ID:        hex.EncodeToString(id),
+		Operation: operation,
+		Tenant:    tenant,
+		Status:    AsyncJobPending,
+		Created:   mytime.Now(),
+	}
+	err = asyncJobStore.Put(c, rc, job)
+	if err != nil {
+		return AsyncJob{}, errorh.NewInternalErrorf(0, "Error storing job of %s: %s", operation, err)
+	}
+	return job, nil
+}
+
+// writeAsyncJob answers that a job is accepted, with the location of its status
+func writeAsyncJob(c context.Context, rc request.Context, w http.ResponseWriter, jobsPath string, job AsyncJob) {
+	w.Header().Set("Location", jobsPath+"/"+job.ID)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	err := json.NewEncoder(w).Encode(job)
+	if err != nil {
+		mylog.New().Warning(c, rc, "Error writing json-response: %s", err)
+	}
+}
+
+// completeAsyncJob stores the outcome of a job, after publishing the events of the operation, and publishes the
+// completion of the job on the event-bus
+func completeAsyncJob(c context.Context, rc request.Context, job AsyncJob, result interface{}, err error) {
+	if err == nil {
+		for _, envlp := range rc.GetEnvelopes() {
+			err = bus.New().Publish(c, rc, envlp)
+			if err != nil {
+				break
+			}
+		}
+	}
+	if err == nil && result != nil {
+		job.Result, err = json.Marshal(result)
+	}
+	job.Status = AsyncJobCompleted
+	if err != nil {
+		job.Status = AsyncJobFailed
+		job.Error = err.Error()
+	}
+	completed := mytime.Now()
+	job.Completed = &completed
+
+	err = asyncJobStore.Put(c, rc, job)
+	if err != nil {
+		mylog.New().Error(c, rc, "Error storing job %s of %s: %s", job.ID, job.Operation, err)
+	}
+	err = publishAsyncJobCompleted(c, rc, job)
+	if err != nil {
+		mylog.New().Error(c, rc, "Error publishing completion of job %s of %s: %s", job.ID, job.Operation, err)
+	}
+}
+
+// recoverAsyncJob fails a job whose operation panics, instead of leaving it pending and crashing the service: the
+// goroutine of the job defers it
+func recoverAsyncJob(c context.Context, rc request.Context, job AsyncJob) {
+	if p := recover(); p != nil {
+		mylog.New().Error(c, rc, "Panic in job %s of %s: %v\n%s", job.ID, job.Operation, p, debug.Stack())
+		completeAsyncJob(c, rc, job, nil, errorh.NewInternalErrorf(0, "Job %s of %s failed unexpectedly", job.ID, job.Operation))
+	}
+}
+
+func publishAsyncJobCompleted(c context.Context, rc request.Context, job AsyncJob) error {
+	blob, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	envlp := envelope.Envelope{
+		IsRootEvent:      true,
+		SequenceNumber:   int64(0),
+		SessionUID:       rc.GetSessionUID(),
+		Timestamp:        *job.Completed,
+		AggregateName:    AsyncJobAggregateName,
+		AggregateUID:     job.ID,
+		EventTypeName:    AsyncJobCompletedEventName,
+		EventTypeVersion: 1,
+		EventData:        string(blob),
+	}
+	envlp.UUID = envlp.CreateRequestUID(job.ID)
+	return bus.New().Publish(c, rc, &envlp)
+}
+
+// writeAsyncJobStatus answers the status of the job in the path of the request, when it is a job of the service
+// and tenant
+func writeAsyncJobStatus(c context.Context, rc request.Context, w http.ResponseWriter, r *http.Request, service string, tenant string) {
+	job, err := asyncJobStore.Get(c, rc, mux.Vars(r)["jobID"])
+	if err != nil {
+		errorh.HandleHTTPError(c, rc, err, w, r)
+		return
+	}
+	if job == nil || !strings.HasPrefix(job.Operation, service+".") || job.Tenant != tenant {
+		http.Error(w, "Unknown job", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(job)
+	if err != nil {
+		mylog.New().Warning(c, rc, "Error writing json-response: %s", err)
+	}
+}
+`
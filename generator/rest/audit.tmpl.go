@@ -0,0 +1,107 @@
+package rest
+
+const auditTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+
+package {{.PackageName}}
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+const (
+	// AuditAggregateName is the aggregate of the audit-events of the rest-operations of this package
+	AuditAggregateName = "Audit"
+	// AuditEventName is the event-type of the audit-events on the event-bus
+	AuditEventName = "AuditEvent"
+)
+
+// AuditEvent tells who called an audited rest-operation and when, with the audited fields before and after
+type AuditEvent struct {
+	Service   string                 ` + "`" + `json:"service"` + "`" + `
+	Operation string                 ` + "`" + `json:"operation"` + "`" + `
+	Action    string                 ` + "`" + `json:"action"` + "`" + `
+	Actor     string                 ` + "`" + `json:"actor"` + "`" + `
+	Method    string                 ` + "`" + `json:"method"` + "`" + `
+	Path      string                 ` + "`" + `json:"path"` + "`" + `
+	Timestamp time.Time              ` + "`" + `json:"timestamp"` + "`" + `
+	Before    map[string]interface{} ` + "`" + `json:"before,omitempty"` + "`" + `
+	After     map[string]interface{} ` + "`" + `json:"after,omitempty"` + "`" + `
+}
+
+// AuditActorProvider tells who makes a request
+type AuditActorProvider interface {
+	GetActor(c context.Context, rc request.Context, r *http.Request) string
+}
+
+type sessionActor struct{}
+
+func (sessionActor) GetActor(c context.Context, rc request.Context, r *http.Request) string {
+	return rc.GetSessionUID()
+}
+
+var auditActorProvider AuditActorProvider = sessionActor{}
+
+// SetAuditActorProvider replaces the default provider that identifies the actor by the session of the request
+func SetAuditActorProvider(provider AuditActorProvider) {
+	auditActorProvider = provider
+}
+
+// publishAuditEvent publishes the audit-event of a successful call on the event-bus
+func publishAuditEvent(c context.Context, rc request.Context, r *http.Request, evt AuditEvent) error {
+	evt.Actor = auditActorProvider.GetActor(c, rc, r)
+	evt.Method = r.Method
+	evt.Path = r.URL.Path
+	evt.Timestamp = mytime.Now()
+
+	blob, err := json.Marshal(evt)
+	if err != nil {
+		return errorh.NewInternalErrorf(0, "Error marshalling audit-event of %s: %s", evt.Operation, err)
+	}
+	requestUID := rc.GetRequestUID()
+	if requestUID == "" {
+		requestUID, _ = myuuid.NewV1(AuditAggregateName)
+	}
+	envlp := envelope.Envelope{
+		IsRootEvent:      true,
+		SequenceNumber:   int64(0),
+		SessionUID:       rc.GetSessionUID(),
+		Timestamp:        evt.Timestamp,
+		AggregateName:    AuditAggregateName,
+		AggregateUID:     requestUID,
+		EventTypeName:    AuditEventName,
+		EventTypeVersion: 1,
+		EventData:        string(blob),
+	}
+	envlp.UUID = envlp.CreateRequestUID(requestUID)
+
+	err = bus.New().Publish(c, rc, &envlp)
+	if err != nil {
+		return errorh.NewInternalErrorf(0, "Error publishing audit-event of %s: %s", evt.Operation, err)
+	}
+	return nil
+}
+
+// auditSnapshot returns the audited fields of a value by their json-name: snapshots leave out the other fields,
+// which may hold personal data
+func auditSnapshot(value interface{}, fields ...string) map[string]interface{} {
+	blob, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	all := map[string]interface{}{}
+	err = json.Unmarshal(blob, &all)
+	if err != nil {
+		return nil
+	}
+	snapshot := map[string]interface{}{}
+	for _, field := range fields {
+		if fieldValue, found := all[field]; found {
+			snapshot[field] = fieldValue
+		}
+	}
+	return snapshot
+}
+`
@@ -0,0 +1,52 @@
+package rest
+
+const etagTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+
+package {{.PackageName}}
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrVersionChanged is the error of an operation with @ETag that finds that its resource no longer has the
+// ExpectedVersion: the handler answers it with 412 Precondition Failed
+var ErrVersionChanged = errors.New("Version of resource has changed")
+
+type expectedVersionKey struct{}
+
+// ExpectedVersion returns the version that the resource of an operation with @ETag had when its handler matched it
+// with the If-Match header. The operation compares it with the version of the resource within the transaction that
+// changes the resource, and returns ErrVersionChanged when they differ, so that no concurrent change gets lost.
+func ExpectedVersion(c context.Context) (int64, bool) {
+	version, ok := c.Value(expectedVersionKey{}).(int64)
+	return version, ok
+}
+
+func withExpectedVersion(c context.Context, version int64) context.Context {
+	return context.WithValue(c, expectedVersionKey{}, version)
+}
+
+// VersionedResource is implemented by the results of operations with @ETag that know their version, like aggregates
+// that implement SetAggregateVersion to keep the sequence-number of their last event
+type VersionedResource interface {
+	GetAggregateVersion() int64
+}
+
+func formatETag(version int64) string {
+	return fmt.Sprintf("\"%d\"", version)
+}
+
+// etagMatches tells whether an If-Match header matches the version of a resource: weak tags never match
+func etagMatches(ifMatch string, version int64) bool {
+	for _, tag := range strings.Split(ifMatch, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || tag == formatETag(version) {
+			return true
+		}
+	}
+	return false
+}
+`
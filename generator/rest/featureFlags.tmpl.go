@@ -0,0 +1,33 @@
+package rest
+
+const featureFlagsTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+
+package {{.PackageName}}
+
+import "context"
+
+// FeatureFlagNames lists all feature-flags that guard the rest-operations of this package
+var FeatureFlagNames = []string{
+{{- range .Names }}
+	"{{.}}",
+{{- end }}
+}
+
+// FeatureFlagProvider decides per request whether a feature is enabled
+type FeatureFlagProvider interface {
+	IsEnabled(c context.Context, rc request.Context, name string) bool
+}
+
+type allFeaturesEnabled struct{}
+
+func (allFeaturesEnabled) IsEnabled(c context.Context, rc request.Context, name string) bool {
+	return true
+}
+
+var featureFlagProvider FeatureFlagProvider = allFeaturesEnabled{}
+
+// SetFeatureFlagProvider replaces the default provider that enables all features
+func SetFeatureFlagProvider(provider FeatureFlagProvider) {
+	featureFlagProvider = provider
+}
+`
@@ -2,19 +2,34 @@ package rest
 
 import (
 	"fmt"
-	"log"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"text/template"
+	"time"
 	"unicode"
 
 	"github.com/MarcGrol/golangAnnotations/generator"
 	"github.com/MarcGrol/golangAnnotations/generator/annotation"
 	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/generator/recording/recordingAnnotation"
 	"github.com/MarcGrol/golangAnnotations/generator/rest/restAnnotation"
+	"github.com/MarcGrol/golangAnnotations/generator/tenant"
+	"github.com/MarcGrol/golangAnnotations/generator/tenant/tenantAnnotation"
 	"github.com/MarcGrol/golangAnnotations/model"
 )
 
+// defaultMaxBodyKB is the size in KB that request bodies are limited to when neither the operation nor its service
+// has a maxBodyKB
+const defaultMaxBodyKB = 1024
+
+// asyncJobsPath is the path below the path of a rest-service where the status of its asynchronous jobs is served
+const asyncJobsPath = "/jobs"
+
+// validVersion is the version of the api of a rest-service, like v1 or v2
+var validVersion = regexp.MustCompile(`^v[1-9][0-9]*$`)
+
 type Generator struct {
 }
 
@@ -22,109 +37,708 @@ func NewGenerator() generator.Generator {
 	return &Generator{}
 }
 
+func (eg *Generator) Name() string {
+	return "rest"
+}
+
+func (eg *Generator) GetTemplates() []generator.Template {
+	return []generator.Template{
+		{Name: "http-handlers", APIVersion: 1, Text: httpHandlersTemplate},
+		{Name: "feature-flags", APIVersion: 1, Text: featureFlagsTemplate},
+		{Name: "audit", APIVersion: 1, Text: auditTemplate},
+		{Name: "etag", APIVersion: 1, Text: etagTemplate},
+		{Name: "idempotency", APIVersion: 1, Text: idempotencyTemplate},
+		{Name: "async", APIVersion: 1, Text: asyncTemplate},
+		{Name: "request-body", APIVersion: 1, Text: requestBodyTemplate},
+		{Name: "request-body-legacy", APIVersion: 1, Text: requestBodyLegacyTemplate},
+		{Name: "tenant", APIVersion: 1, Text: tenantTemplate},
+		{Name: "http-test-helpers", APIVersion: 1, Text: testHelpersTemplate},
+		{Name: "test-service", APIVersion: 1, Text: testServiceTemplate},
+	}
+}
+
 func (eg *Generator) GetAnnotations() []annotation.AnnotationDescriptor {
-	return restAnnotation.Get()
+	return append(restAnnotation.Get(), tenantAnnotation.Get()...)
 }
 
-func (eg *Generator) Generate(inputDir string, parsedSource model.ParsedSources) error {
-	return generate(inputDir, parsedSource.Structs)
+func (eg *Generator) Generate(parsedSource model.ParsedSources, config generator.Config) ([]generator.OutputFile, error) {
+	return generate(config, parsedSource.Structs)
 }
 
 type generateContext struct {
 	targetDir   string
 	packageName string
 	service     model.Struct
+	config      generator.Config
+	// domainPackage and domainImportPath refer to the annotated package when generating into a separate package
+	domainPackage    string
+	domainImportPath string
+	// successorPath is the path of the next version of the service, when the service is an old version
+	successorPath string
+	// structs are all structs of the annotated package, that hold the fields that are audited
+	structs []model.Struct
 }
 
-func generate(inputDir string, structs []model.Struct) error {
+func generate(config generator.Config, structs []model.Struct) ([]generator.OutputFile, error) {
 
 	packageName, err := generationUtil.GetPackageNameForStructs(structs)
 	if packageName == "" || err != nil {
-		return err
+		return nil, err
 	}
-	targetDir, err := generationUtil.DetermineTargetPath(inputDir, packageName)
+	targetDir, err := generationUtil.DetermineTargetPath(config.InputDir, packageName)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	files := []generator.OutputFile{}
+	servicesPerPackage := map[string][]model.Struct{}
+	for _, s := range structs {
+		servicesPerPackage[GetRestServicePackage(s)] = append(servicesPerPackage[GetRestServicePackage(s)], s)
+	}
+	if HasFeatureFlags(servicesPerPackage[""]) {
+		file, err := generateFeatureFlags(targetDir, packageName, servicesPerPackage[""], config)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	if HasAuditedOperations(servicesPerPackage[""]) {
+		file, err := generateAudit(targetDir, packageName, config)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	if HasTenantScopedServices(servicesPerPackage[""]) {
+		file, err := generateTenant(targetDir, packageName, config)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	if HasETagOperations(servicesPerPackage[""]) {
+		file, err := generateETag(targetDir, packageName, config)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	if HasIdempotentOperations(servicesPerPackage[""]) {
+		file, err := generateIdempotency(targetDir, packageName, config)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	if HasAsyncOperations(servicesPerPackage[""]) {
+		file, err := generateAsync(targetDir, packageName, config)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	if HasRequestBodies(servicesPerPackage[""]) {
+		requestBodyFiles, err := generateRequestBody(targetDir, packageName, config)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, requestBodyFiles...)
+	}
+	for _, separatePackage := range getSeparatePackages(servicesPerPackage) {
+		if HasFeatureFlags(servicesPerPackage[separatePackage]) {
+			file, err := generateFeatureFlags(separatePackageDir(targetDir, separatePackage), separatePackage, servicesPerPackage[separatePackage], config)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, file)
+		}
+		if HasAuditedOperations(servicesPerPackage[separatePackage]) {
+			file, err := generateAudit(separatePackageDir(targetDir, separatePackage), separatePackage, config)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, file)
+		}
+		if HasTenantScopedServices(servicesPerPackage[separatePackage]) {
+			file, err := generateTenant(separatePackageDir(targetDir, separatePackage), separatePackage, config)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, file)
+		}
+		if HasETagOperations(servicesPerPackage[separatePackage]) {
+			file, err := generateETag(separatePackageDir(targetDir, separatePackage), separatePackage, config)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, file)
+		}
+		if HasIdempotentOperations(servicesPerPackage[separatePackage]) {
+			file, err := generateIdempotency(separatePackageDir(targetDir, separatePackage), separatePackage, config)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, file)
+		}
+		if HasAsyncOperations(servicesPerPackage[separatePackage]) {
+			file, err := generateAsync(separatePackageDir(targetDir, separatePackage), separatePackage, config)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, file)
+		}
+		if HasRequestBodies(servicesPerPackage[separatePackage]) {
+			requestBodyFiles, err := generateRequestBody(separatePackageDir(targetDir, separatePackage), separatePackage, config)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, requestBodyFiles...)
+		}
 	}
 
 	for _, service := range structs {
 		if IsRestService(service) {
+			err = validatePathParams(service)
+			if err != nil {
+				return nil, err
+			}
+			err = validateTimeouts(service)
+			if err != nil {
+				return nil, err
+			}
+			err = validateRequestBodies(service)
+			if err != nil {
+				return nil, err
+			}
+			err = validateVersion(service, structs)
+			if err != nil {
+				return nil, err
+			}
+			err = validateAudits(service, structs)
+			if err != nil {
+				return nil, err
+			}
+			err = validateTenants(service)
+			if err != nil {
+				return nil, err
+			}
+			err = validateETags(service)
+			if err != nil {
+				return nil, err
+			}
+			err = validateIdempotency(service)
+			if err != nil {
+				return nil, err
+			}
+			err = validateAsync(service)
+			if err != nil {
+				return nil, err
+			}
 			ctx := generateContext{
 				targetDir:   targetDir,
 				packageName: packageName,
 				service:     service,
+				config:      config,
+				structs:     structs,
 			}
-			err = generateHTTPService(ctx)
+			if successor, found := GetRestServiceSuccessor(service, structs); found {
+				ctx.successorPath = GetRestServicePath(successor)
+			}
+			if separatePackage := GetRestServicePackage(service); separatePackage != "" {
+				ctx, err = inSeparatePackage(ctx, separatePackage)
+				if err != nil {
+					return nil, err
+				}
+			}
+			file, err := generateHTTPService(ctx)
 			if err != nil {
-				return err
+				return nil, err
 			}
+			files = append(files, file)
 
 			if !IsRestServiceNoTest(service) {
-				err = generateHTTPTestHelpers(ctx)
+				file, err = generateHTTPTestHelpers(ctx)
 				if err != nil {
-					return err
+					return nil, err
 				}
-				err = generateHTTPTestService(ctx)
+				files = append(files, file)
+				file, err = generateHTTPTestService(ctx)
 				if err != nil {
-					return err
+					return nil, err
 				}
+				files = append(files, file)
+			}
+		}
+	}
+	return files, nil
+}
+
+func getSeparatePackages(servicesPerPackage map[string][]model.Struct) []string {
+	packageNames := []string{}
+	for packageName := range servicesPerPackage {
+		if packageName != "" {
+			packageNames = append(packageNames, packageName)
+		}
+	}
+	sort.Strings(packageNames)
+	return packageNames
+}
+
+// validatePathParams checks that every parameter in the path of a rest-operation is an argument of the operation
+func validatePathParams(service model.Struct) error {
+	for _, o := range service.Operations {
+		if !IsRestOperation(*o) {
+			continue
+		}
+		for _, pathParam := range getAllPathParams(*o) {
+			if !hasInputArg(*o, pathParam) {
+				return generator.OperationError(*o, service.Name, restAnnotation.TypeRestOperation, "Path parameter {%s} of operation %s.%s has no matching argument", pathParam, service.Name, o.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// validateAudits checks that an audited operation that takes the state before it has audited fields to take
+func validateAudits(service model.Struct, structs []model.Struct) error {
+	for _, o := range service.Operations {
+		if IsRestOperation(*o) && IsRestOperationAuditedBefore(*o) && len(GetAuditedFields(*o, structs)) == 0 {
+			return generator.OperationError(*o, service.Name, restAnnotation.TypeAudited, "Operation %s.%s audits the state before it, but its result has no fields with @Audited", service.Name, o.Name)
+		}
+	}
+	return nil
+}
+
+// validateTenants checks that every operation of a tenant-scoped service receives the tenant, so that it cannot
+// access the data of tenant-scoped aggregates without one
+func validateTenants(service model.Struct) error {
+	if !IsRestServiceTenantScoped(service) {
+		return nil
+	}
+	for _, o := range service.Operations {
+		if IsRestOperation(*o) && !hasTenantArg(*o) {
+			return generator.OperationError(*o, service.Name, restAnnotation.TypeRestOperation, "Operation %s.%s of tenant-scoped service %s has no argument '%s string' for the tenant", service.Name, o.Name, service.Name, tenant.ArgName)
+		}
+	}
+	return nil
+}
+
+// validateETags checks that an operation with @ETag has a result to tag, or changes the resource so that it checks
+// the version that the client has: with a method that returns the version, and a context to pass it to the operation
+func validateETags(service model.Struct) error {
+	for _, o := range service.Operations {
+		if IsRestOperation(*o) && HasETag(*o) && !HasOutput(*o) && !HasETagPrecondition(*o) {
+			return generator.OperationError(*o, service.Name, restAnnotation.TypeETag, "Operation %s.%s with @ETag has neither a result to tag nor a resource to change", service.Name, o.Name)
+		}
+		if IsRestOperation(*o) && HasETagPrecondition(*o) && !HasContext(*o) {
+			return generator.OperationError(*o, service.Name, restAnnotation.TypeETag, "Operation %s.%s with @ETag has no context.Context argument to pass the expected version to", service.Name, o.Name)
+		}
+		if IsRestOperation(*o) && HasETagPrecondition(*o) && !hasVersionMethod(service, *o) {
+			return generator.OperationError(*o, service.Name, restAnnotation.TypeETag, "Operation %s.%s with @ETag has no method %s.%sVersion that returns the int64 version of the resource", service.Name, o.Name, service.Name, o.Name)
+		}
+	}
+	return nil
+}
+
+// hasVersionMethod tells whether a service has the method <operation>Version, that returns the current version of
+// the resource of an operation with @ETag
+func hasVersionMethod(service model.Struct, o model.Operation) bool {
+	for _, method := range service.Operations {
+		if method.Name == o.Name+"Version" {
+			return len(method.OutputArgs) > 0 && method.OutputArgs[0].TypeName == "int64"
+		}
+	}
+	return false
+}
+
+// validateIdempotency checks that operations with @Idempotent are unsafe: the other methods are idempotent already
+func validateIdempotency(service model.Struct) error {
+	for _, o := range service.Operations {
+		if !IsRestOperation(*o) || !IsRestOperationIdempotent(*o) {
+			continue
+		}
+		if method := GetRestOperationMethod(*o); method != "POST" && method != "PATCH" {
+			return generator.OperationError(*o, service.Name, restAnnotation.TypeIdempotent, "Operation %s.%s with @Idempotent must be a POST or PATCH: %s is idempotent already", service.Name, o.Name, method)
+		}
+	}
+	return nil
+}
+
+// validateAsync checks that an asynchronous operation has a context to run its job with, and a json-result that
+// clients can poll: what is written in the response itself is gone when the job completes
+func validateAsync(service model.Struct) error {
+	for _, o := range service.Operations {
+		if !IsRestOperation(*o) || !IsRestOperationAsync(*o) {
+			continue
+		}
+		if !HasContext(*o) {
+			return generator.OperationError(*o, service.Name, restAnnotation.TypeAsync, "Operation %s.%s with @Async has no context.Context argument to run its job with", service.Name, o.Name)
+		}
+		if !IsRestOperationJSON(*o) && !IsRestOperationNoContent(*o) {
+			return generator.OperationError(*o, service.Name, restAnnotation.TypeAsync, "Operation %s.%s with @Async must have format JSON or no_content: clients poll its result as json", service.Name, o.Name)
+		}
+		for _, conflict := range []struct {
+			what  string
+			found bool
+		}{
+			{"meta-data", HasMetaOutput(*o)},
+			{"an after-handler", HasRestOperationAfter(*o)},
+			{"@Audited", IsRestOperationAudited(*o)},
+			{"@ETag", HasETag(*o)},
+		} {
+			if conflict.found {
+				return generator.OperationError(*o, service.Name, restAnnotation.TypeAsync, "Operation %s.%s with @Async cannot have %s: its job completes after the response", service.Name, o.Name, conflict.what)
 			}
 		}
 	}
 	return nil
 }
 
-func generateHTTPService(ctx generateContext) error {
-	err := generationUtil.Generate(generationUtil.Info{
+// validateTimeouts checks that the timeouts of a rest-service and its operations are positive durations, like "5s",
+// and that an operation with a timeout of its own passes a context to the service
+func validateTimeouts(service model.Struct) error {
+	annotations := annotation.NewRegistry(restAnnotation.Get())
+	if ann, ok := annotations.ResolveAnnotationByName(service.DocLines, restAnnotation.TypeRestService); ok {
+		if timeout, found := ann.Attributes[restAnnotation.ParamTimeout]; found {
+			if _, ok := parseTimeout(timeout); !ok {
+				return generator.StructError(service, restAnnotation.TypeRestService, "Timeout '%s' of service %s is no positive duration, like 5s", timeout, service.Name)
+			}
+		}
+	}
+	for _, o := range service.Operations {
+		ann, ok := annotations.ResolveAnnotationByName(o.DocLines, restAnnotation.TypeRestOperation)
+		if !ok {
+			continue
+		}
+		timeout, found := ann.Attributes[restAnnotation.ParamTimeout]
+		if !found {
+			continue
+		}
+		if _, ok := parseTimeout(timeout); !ok {
+			return generator.OperationError(*o, service.Name, restAnnotation.TypeRestOperation, "Timeout '%s' of operation %s.%s is no positive duration, like 5s", timeout, service.Name, o.Name)
+		}
+		if !HasContext(*o) {
+			return generator.OperationError(*o, service.Name, restAnnotation.TypeRestOperation, "Operation %s.%s has a timeout, but no context.Context argument to pass it to", service.Name, o.Name)
+		}
+	}
+	return nil
+}
+
+// validateRequestBodies checks that the size-limits of the request bodies of a rest-service and its operations
+// are positive numbers of KB and that an operation with a limit or media types of its own has a request body
+func validateRequestBodies(service model.Struct) error {
+	annotations := annotation.NewRegistry(restAnnotation.Get())
+	if ann, ok := annotations.ResolveAnnotationByName(service.DocLines, restAnnotation.TypeRestService); ok {
+		if maxBodyKB, found := ann.Attributes[restAnnotation.ParamMaxBodyKB]; found {
+			if _, ok := parseMaxBodyKB(maxBodyKB); !ok {
+				return generator.StructError(service, restAnnotation.TypeRestService, "MaxBodyKB '%s' of service %s is no positive number", maxBodyKB, service.Name)
+			}
+		}
+	}
+	for _, o := range service.Operations {
+		ann, ok := annotations.ResolveAnnotationByName(o.DocLines, restAnnotation.TypeRestOperation)
+		if !ok {
+			continue
+		}
+		if maxBodyKB, found := ann.Attributes[restAnnotation.ParamMaxBodyKB]; found {
+			if _, ok := parseMaxBodyKB(maxBodyKB); !ok {
+				return generator.OperationError(*o, service.Name, restAnnotation.TypeRestOperation, "MaxBodyKB '%s' of operation %s.%s is no positive number", maxBodyKB, service.Name, o.Name)
+			}
+		}
+		for _, name := range []string{restAnnotation.ParamMaxBodyKB, restAnnotation.ParamConsumes} {
+			if _, found := ann.Attributes[name]; found && !HasInput(*o) {
+				return generator.OperationError(*o, service.Name, restAnnotation.TypeRestOperation, "Operation %s.%s has %s, but reads no request body", service.Name, o.Name, name)
+			}
+		}
+	}
+	return nil
+}
+
+// validateVersion checks that the version of a rest-service is like v1 or v2 and that no other service has the
+// same version of its path
+func validateVersion(service model.Struct, structs []model.Struct) error {
+	version := GetRestServiceVersion(service)
+	if version == "" {
+		return nil
+	}
+	if !validVersion.MatchString(version) {
+		return generator.StructError(service, restAnnotation.TypeRestService, "Version '%s' of service %s is no version like v1 or v2", version, service.Name)
+	}
+	for _, other := range structs {
+		if other.Name != service.Name && IsRestService(other) && GetRestServiceVersion(other) == version && getRestServiceUnversionedPath(other) == getRestServiceUnversionedPath(service) {
+			return generator.StructError(service, restAnnotation.TypeRestService, "Services %s and %s both serve version %s of path '%s'", other.Name, service.Name, version, getRestServiceUnversionedPath(service))
+		}
+	}
+	return nil
+}
+
+func parseMaxBodyKB(maxBodyKB string) (int, bool) {
+	value, err := strconv.Atoi(maxBodyKB)
+	return value, err == nil && value > 0
+}
+
+func parseTimeout(timeout string) (time.Duration, bool) {
+	duration, err := time.ParseDuration(timeout)
+	return duration, err == nil && duration > 0
+}
+
+func hasInputArg(o model.Operation, name string) bool {
+	for _, arg := range o.InputArgs {
+		if arg.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// separatePackageDir returns the directory of a package next to the annotated package
+func separatePackageDir(targetDir string, packageName string) string {
+	return fmt.Sprintf("%s/../%s", targetDir, packageName)
+}
+
+// inSeparatePackage prepares ctx for generating the http-handling of its service into the sibling package
+// separatePackage, that imports the annotated package and can only call its exported operations
+func inSeparatePackage(ctx generateContext, separatePackage string) (generateContext, error) {
+	for _, o := range ctx.service.Operations {
+		if IsRestOperation(*o) && !generationUtil.IsExported(o.Name) {
+			return ctx, generator.OperationError(*o, ctx.service.Name, restAnnotation.TypeRestOperation, "Operation %s.%s must be exported to be called from package %s", ctx.service.Name, o.Name, separatePackage)
+		}
+	}
+	domainImportPath, err := generationUtil.GetImportPath(ctx.targetDir)
+	if err != nil {
+		return ctx, err
+	}
+	ctx.domainPackage = ctx.packageName
+	ctx.domainImportPath = domainImportPath
+	ctx.targetDir = separatePackageDir(ctx.targetDir, separatePackage)
+	ctx.packageName = separatePackage
+	ctx.service = generationUtil.QualifyStruct(ctx.service, ctx.domainPackage)
+	ctx.service.PackageName = separatePackage
+	return ctx, nil
+}
+
+// templateFuncs adds the functions that describe the annotated package when generating into a separate package
+func templateFuncs(ctx generateContext) template.FuncMap {
+	funcs := template.FuncMap{}
+	for name, f := range customTemplateFuncs {
+		funcs[name] = f
+	}
+	funcs["GetDomainPackage"] = func() string { return ctx.domainPackage }
+	funcs["GetDomainImportPath"] = func() string { return ctx.domainImportPath }
+	funcs["GetSuccessorPath"] = func() string { return ctx.successorPath }
+	funcs["GetAuditedFields"] = func(o model.Operation) string { return quotedList(GetAuditedFields(o, ctx.structs)) }
+	return funcs
+}
+
+func generateHTTPService(ctx generateContext) (generator.OutputFile, error) {
+	file, err := generationUtil.Generate(generationUtil.Info{
 		Src:            fmt.Sprintf("%s.%s", ctx.service.PackageName, ToFirstUpper(ctx.service.Name)),
+		TypeName:       ctx.service.Name,
 		TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/http%s.go", ctx.targetDir, ToFirstUpper(ctx.service.Name))),
 		TemplateName:   "http-handlers",
 		TemplateString: httpHandlersTemplate,
-		FuncMap:        customTemplateFuncs,
+		Config:         ctx.config,
+		FuncMap:        templateFuncs(ctx),
 		Data:           ctx.service,
 	})
 	if err != nil {
-		log.Fatalf("Error generating handlers for service %s: %s", ctx.service.Name, err)
-		return err
+		return generator.OutputFile{}, fmt.Errorf("Error generating handlers for service %s:%w", ctx.service.Name, err)
 	}
-	return nil
+	return file, nil
 }
 
-func generateHTTPTestHelpers(ctx generateContext) error {
-	err := generationUtil.Generate(generationUtil.Info{
+func generateFeatureFlags(targetDir string, packageName string, structs []model.Struct, config generator.Config) (generator.OutputFile, error) {
+	file, err := generationUtil.Generate(generationUtil.Info{
+		Src:            fmt.Sprintf("%s.%s", packageName, "FeatureFlags"),
+		TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/featureFlags.go", targetDir)),
+		TemplateName:   "feature-flags",
+		TemplateString: featureFlagsTemplate,
+		Config:         config,
+		FuncMap:        customTemplateFuncs,
+		Data: struct {
+			PackageName string
+			Names       []string
+		}{
+			PackageName: packageName,
+			Names:       GetFeatureFlagNames(structs),
+		},
+	})
+	if err != nil {
+		return generator.OutputFile{}, fmt.Errorf("Error generating feature-flags for package %s:%w", packageName, err)
+	}
+	return file, nil
+}
+
+func generateAudit(targetDir string, packageName string, config generator.Config) (generator.OutputFile, error) {
+	file, err := generationUtil.Generate(generationUtil.Info{
+		Src:            fmt.Sprintf("%s.%s", packageName, "Audit"),
+		TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/audit.go", targetDir)),
+		TemplateName:   "audit",
+		TemplateString: auditTemplate,
+		Config:         config,
+		FuncMap:        customTemplateFuncs,
+		Data: struct {
+			PackageName string
+		}{
+			PackageName: packageName,
+		},
+	})
+	if err != nil {
+		return generator.OutputFile{}, fmt.Errorf("Error generating audit-events for package %s:%w", packageName, err)
+	}
+	return file, nil
+}
+
+func generateTenant(targetDir string, packageName string, config generator.Config) (generator.OutputFile, error) {
+	file, err := generationUtil.Generate(generationUtil.Info{
+		Src:            fmt.Sprintf("%s.%s", packageName, "Tenant"),
+		TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/tenant.go", targetDir)),
+		TemplateName:   "tenant",
+		TemplateString: tenantTemplate,
+		Config:         config,
+		FuncMap:        customTemplateFuncs,
+		Data: struct {
+			PackageName string
+		}{
+			PackageName: packageName,
+		},
+	})
+	if err != nil {
+		return generator.OutputFile{}, fmt.Errorf("Error generating tenant-extraction for package %s:%w", packageName, err)
+	}
+	return file, nil
+}
+
+func generateETag(targetDir string, packageName string, config generator.Config) (generator.OutputFile, error) {
+	file, err := generationUtil.Generate(generationUtil.Info{
+		Src:            fmt.Sprintf("%s.%s", packageName, "ETag"),
+		TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/etag.go", targetDir)),
+		TemplateName:   "etag",
+		TemplateString: etagTemplate,
+		Config:         config,
+		FuncMap:        customTemplateFuncs,
+		Data: struct {
+			PackageName string
+		}{
+			PackageName: packageName,
+		},
+	})
+	if err != nil {
+		return generator.OutputFile{}, fmt.Errorf("Error generating etags for package %s:%w", packageName, err)
+	}
+	return file, nil
+}
+
+func generateIdempotency(targetDir string, packageName string, config generator.Config) (generator.OutputFile, error) {
+	file, err := generationUtil.Generate(generationUtil.Info{
+		Src:            fmt.Sprintf("%s.%s", packageName, "Idempotency"),
+		TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/idempotency.go", targetDir)),
+		TemplateName:   "idempotency",
+		TemplateString: idempotencyTemplate,
+		Config:         config,
+		FuncMap:        customTemplateFuncs,
+		Data: struct {
+			PackageName string
+		}{
+			PackageName: packageName,
+		},
+	})
+	if err != nil {
+		return generator.OutputFile{}, fmt.Errorf("Error generating idempotency-keys for package %s:%w", packageName, err)
+	}
+	return file, nil
+}
+
+func generateAsync(targetDir string, packageName string, config generator.Config) (generator.OutputFile, error) {
+	file, err := generationUtil.Generate(generationUtil.Info{
+		Src:            fmt.Sprintf("%s.%s", packageName, "Async"),
+		TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/async.go", targetDir)),
+		TemplateName:   "async",
+		TemplateString: asyncTemplate,
+		Config:         config,
+		FuncMap:        customTemplateFuncs,
+		Data: struct {
+			PackageName string
+		}{
+			PackageName: packageName,
+		},
+	})
+	if err != nil {
+		return generator.OutputFile{}, fmt.Errorf("Error generating async jobs for package %s:%w", packageName, err)
+	}
+	return file, nil
+}
+
+// generateRequestBody renders the recognition of too large request bodies twice: with http.MaxBytesError for go 1.19
+// and later, and without it for older versions of go
+func generateRequestBody(targetDir string, packageName string, config generator.Config) ([]generator.OutputFile, error) {
+	files := []generator.OutputFile{}
+	for _, variant := range []struct {
+		filename       string
+		templateName   string
+		templateString string
+	}{
+		{filename: "requestBody.go", templateName: "request-body", templateString: requestBodyTemplate},
+		{filename: "requestBodyLegacy.go", templateName: "request-body-legacy", templateString: requestBodyLegacyTemplate},
+	} {
+		file, err := generationUtil.Generate(generationUtil.Info{
+			Src:            fmt.Sprintf("%s.%s", packageName, "RequestBody"),
+			TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/%s", targetDir, variant.filename)),
+			TemplateName:   variant.templateName,
+			TemplateString: variant.templateString,
+			Config:         config,
+			FuncMap:        customTemplateFuncs,
+			Data: struct {
+				PackageName string
+			}{
+				PackageName: packageName,
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Error generating request-bodies for package %s:%w", packageName, err)
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+func generateHTTPTestHelpers(ctx generateContext) (generator.OutputFile, error) {
+	file, err := generationUtil.Generate(generationUtil.Info{
 		Src:            fmt.Sprintf("%s.%s", ctx.service.PackageName, ToFirstUpper(ctx.service.Name)),
+		TypeName:       ctx.service.Name,
 		TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/http%sHelpers_test.go", ctx.targetDir, ToFirstUpper(ctx.service.Name))),
 		TemplateName:   "http-test-helpers",
 		TemplateString: testHelpersTemplate,
-		FuncMap:        customTemplateFuncs,
+		Config:         ctx.config,
+		FuncMap:        templateFuncs(ctx),
 		Data:           ctx.service,
 	})
 	if err != nil {
-		log.Fatalf("Error generating helpers for service %s: %s", ctx.service.Name, err)
-		return err
+		return generator.OutputFile{}, fmt.Errorf("Error generating helpers for service %s:%w", ctx.service.Name, err)
 	}
-	return nil
+	return file, nil
 }
 
-func generateHTTPTestService(ctx generateContext) error {
+func generateHTTPTestService(ctx generateContext) (generator.OutputFile, error) {
 	// create this file within a subdirectoty
 	ctx.packageName = ctx.packageName + "TestLog"
 
 	ctx.service.PackageName = ctx.packageName
 	target := generationUtil.Prefixed(fmt.Sprintf("%s/%s/httpTest%s.go", ctx.targetDir, ctx.packageName, ToFirstUpper(ctx.service.Name)))
-	err := generationUtil.Generate(generationUtil.Info{
+	file, err := generationUtil.Generate(generationUtil.Info{
 		Src:            fmt.Sprintf("%s.%s", ctx.service.PackageName, ToFirstUpper(ctx.service.Name)),
+		TypeName:       ctx.service.Name,
 		TargetFilename: target,
-		TemplateName:   "testService",
+		TemplateName:   "test-service",
 		TemplateString: testServiceTemplate,
-		FuncMap:        customTemplateFuncs,
+		Config:         ctx.config,
+		FuncMap:        templateFuncs(ctx),
 		Data:           ctx.service,
 	})
 	if err != nil {
-		log.Fatalf("Error generating testHandler for service %s: %s", ctx.service.Name, err)
-		return err
+		return generator.OutputFile{}, fmt.Errorf("Error generating testHandler for service %s:%w", ctx.service.Name, err)
 	}
-	return nil
+	return file, nil
 }
 
 var customTemplateFuncs = template.FuncMap{
@@ -140,10 +754,15 @@ var customTemplateFuncs = template.FuncMap{
 	"GetRestOperationPath":                  GetRestOperationPath,
 	"GetRestOperationMethod":                GetRestOperationMethod,
 	"IsRestOperationTransactional":          IsRestOperationTransactional,
+	"HasRestOperationTimeout":               HasRestOperationTimeout,
+	"GetRestOperationTimeout":               GetRestOperationTimeout,
+	"GetRestOperationMaxBodyKB":             GetRestOperationMaxBodyKB,
+	"GetRestOperationConsumes":              GetRestOperationConsumes,
 	"IsRestOperationForm":                   IsRestOperationForm,
 	"IsRestOperationJSON":                   IsRestOperationJSON,
 	"IsRestOperationHTML":                   IsRestOperationHTML,
 	"IsRestOperationCSV":                    IsRestOperationCSV,
+	"IsRestOperationXLSX":                   IsRestOperationXLSX,
 	"IsRestOperationTXT":                    IsRestOperationTXT,
 	"IsRestOperationMD":                     IsRestOperationMD,
 	"IsRestOperationNoContent":              IsRestOperationNoContent,
@@ -151,6 +770,9 @@ var customTemplateFuncs = template.FuncMap{
 	"HasContentType":                        HasContentType,
 	"GetContentType":                        GetContentType,
 	"GetRestOperationFilename":              GetRestOperationFilename,
+	"HasFeatureFlag":                        HasFeatureFlag,
+	"GetFeatureFlagName":                    GetFeatureFlagName,
+	"GetFeatureFlagStatus":                  GetFeatureFlagStatus,
 	"GetRestOperationRolesString":           GetRestOperationRolesString,
 	"GetRestOperationProducesEvents":        GetRestOperationProducesEvents,
 	"GetRestOperationProducesEventsAsSlice": GetRestOperationProducesEventsAsSlice,
@@ -188,6 +810,28 @@ var customTemplateFuncs = template.FuncMap{
 	"BackTick":                              BackTick,
 	"ToFirstUpper":                          ToFirstUpper,
 	"Uncapitalized":                         Uncapitalized,
+	"GetDomainPackage":                      func() string { return "" },
+	"GetDomainImportPath":                   func() string { return "" },
+	"GetSuccessorPath":                      func() string { return "" },
+	"IsRestServiceRecorded":                 IsRestServiceRecorded,
+	"IsRestOperationAudited":                IsRestOperationAudited,
+	"IsRestServiceTenantScoped":             IsRestServiceTenantScoped,
+	"GetRestServiceTenantHeader":            GetRestServiceTenantHeader,
+	"IsTenantArg":                           tenant.IsTenantArg,
+	"IsRestOperationAuditedBefore":          IsRestOperationAuditedBefore,
+	"HasETag":                               HasETag,
+	"HasETagPrecondition":                   HasETagPrecondition,
+	"IsETagRequired":                        IsETagRequired,
+	"IsRestOperationIdempotent":             IsRestOperationIdempotent,
+	"IsIdempotencyKeyRequired":              IsIdempotencyKeyRequired,
+	"GetIdempotencyScope":                   GetIdempotencyScope,
+	"IsRestOperationAsync":                  IsRestOperationAsync,
+	"IsRestServiceAsync":                    IsRestServiceAsync,
+	"GetAsyncJobsPath":                      GetAsyncJobsPath,
+	"GetAsyncJobTenant":                     GetAsyncJobTenant,
+	"GetRestOperationAuditAction":           GetRestOperationAuditAction,
+	"GetAuditedValue":                       GetAuditedValue,
+	"GetAuditedFields":                      func(o model.Operation) string { return "" },
 }
 
 func BackTick() string {
@@ -218,7 +862,17 @@ func IsRestServiceUnprotected(s model.Struct) bool {
 	return ok && ann.Attributes[restAnnotation.ParamProtected] != "true"
 }
 
+// GetRestServicePath returns the path that the operations of a rest-service are below: a versioned service has its
+// version as prefix, like /v2/persons
 func GetRestServicePath(s model.Struct) string {
+	path := getRestServiceUnversionedPath(s)
+	if version := GetRestServiceVersion(s); version != "" {
+		return "/" + version + strings.TrimSuffix(path, "/")
+	}
+	return path
+}
+
+func getRestServiceUnversionedPath(s model.Struct) string {
 	annotations := annotation.NewRegistry(restAnnotation.Get())
 	if ann, ok := annotations.ResolveAnnotationByName(s.DocLines, restAnnotation.TypeRestService); ok {
 		return ann.Attributes[restAnnotation.ParamPath]
@@ -226,6 +880,51 @@ func GetRestServicePath(s model.Struct) string {
 	return ""
 }
 
+// GetRestServiceVersion returns the version of the api of a rest-service, like v2, or "" when it is not versioned
+func GetRestServiceVersion(s model.Struct) string {
+	annotations := annotation.NewRegistry(restAnnotation.Get())
+	if ann, ok := annotations.ResolveAnnotationByName(s.DocLines, restAnnotation.TypeRestService); ok {
+		return ann.Attributes[restAnnotation.ParamVersion]
+	}
+	return ""
+}
+
+// GetRestServiceSuccessor returns the service with the next version of the path of s, when s is an old version
+func GetRestServiceSuccessor(s model.Struct, structs []model.Struct) (model.Struct, bool) {
+	version, ok := parseVersion(GetRestServiceVersion(s))
+	if !ok {
+		return model.Struct{}, false
+	}
+	successor, successorVersion := model.Struct{}, 0
+	for _, other := range structs {
+		if !IsRestService(other) || getRestServiceUnversionedPath(other) != getRestServiceUnversionedPath(s) {
+			continue
+		}
+		if otherVersion, ok := parseVersion(GetRestServiceVersion(other)); ok && otherVersion > version && (successorVersion == 0 || otherVersion < successorVersion) {
+			successor, successorVersion = other, otherVersion
+		}
+	}
+	return successor, successorVersion > 0
+}
+
+func parseVersion(version string) (int, bool) {
+	if !validVersion.MatchString(version) {
+		return 0, false
+	}
+	number, err := strconv.Atoi(version[1:])
+	return number, err == nil
+}
+
+// GetRestServicePackage returns the name of the separate package that the http-handling of the service is generated
+// into, or "" when it is generated into the package of the service itself
+func GetRestServicePackage(s model.Struct) string {
+	annotations := annotation.NewRegistry(restAnnotation.Get())
+	if ann, ok := annotations.ResolveAnnotationByName(s.DocLines, restAnnotation.TypeRestService); ok {
+		return ann.Attributes[restAnnotation.ParamPackage]
+	}
+	return ""
+}
+
 func GetExtractRequestContextMethod(s model.Struct) string {
 	annotations := annotation.NewRegistry(restAnnotation.Get())
 	if ann, ok := annotations.ResolveAnnotationByName(s.DocLines, restAnnotation.TypeRestService); ok {
@@ -253,6 +952,13 @@ func DoesRestServiceRequireRoleValidation(s model.Struct) bool {
 	return !IsRestServiceNoValidation(s)
 }
 
+// IsRestServiceRecorded tells whether the responses of a rest-service can be recorded as fixtures for its clients
+func IsRestServiceRecorded(s model.Struct) bool {
+	annotations := annotation.NewRegistry(recordingAnnotation.Get())
+	_, ok := annotations.ResolveAnnotationByName(s.DocLines, recordingAnnotation.TypeRecording)
+	return ok
+}
+
 func IsRestServiceNoTest(s model.Struct) bool {
 	annotations := annotation.NewRegistry(restAnnotation.Get())
 	if ann, ok := annotations.ResolveAnnotationByName(s.DocLines, restAnnotation.TypeRestService); ok {
@@ -279,14 +985,11 @@ func isImportToBeIgnored(imp string) bool {
 func ExtractImports(s model.Struct) []string {
 	importsMap := map[string]bool{}
 	for _, o := range s.Operations {
-		for _, ia := range o.InputArgs {
-			if !isImportToBeIgnored(ia.PackageName) {
-				importsMap[ia.PackageName] = true
-			}
-		}
-		for _, oa := range o.OutputArgs {
-			if !isImportToBeIgnored(oa.PackageName) {
-				importsMap[oa.PackageName] = true
+		for _, arg := range append(append([]model.Field{}, o.InputArgs...), o.OutputArgs...) {
+			for _, importPath := range arg.ImportPaths() {
+				if !isImportToBeIgnored(importPath) {
+					importsMap[importPath] = true
+				}
 			}
 		}
 	}
@@ -298,6 +1001,7 @@ func mapToSlice(importsMap map[string]bool) []string {
 	for k := range importsMap {
 		importsList = append(importsList, k)
 	}
+	sort.Strings(importsList)
 	return importsList
 }
 
@@ -328,6 +1032,79 @@ func IsRestOperationGenerated(o model.Operation) bool {
 	return !IsRestOperationNoWrap(o)
 }
 
+// getRestOperationAttribute returns an attribute of the annotation of an operation, otherwise the one of its service
+func getRestOperationAttribute(s model.Struct, o model.Operation, name string) (string, bool) {
+	annotations := annotation.NewRegistry(restAnnotation.Get())
+	if ann, ok := annotations.ResolveAnnotationByName(o.DocLines, restAnnotation.TypeRestOperation); ok {
+		if value, found := ann.Attributes[name]; found {
+			return value, true
+		}
+	}
+	if ann, ok := annotations.ResolveAnnotationByName(s.DocLines, restAnnotation.TypeRestService); ok {
+		if value, found := ann.Attributes[name]; found {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// getRestOperationTimeout returns the timeout of an operation: its own, otherwise the one of its service
+func getRestOperationTimeout(s model.Struct, o model.Operation) (time.Duration, bool) {
+	timeout, found := getRestOperationAttribute(s, o, restAnnotation.ParamTimeout)
+	if !found {
+		return 0, false
+	}
+	return parseTimeout(timeout)
+}
+
+// HasRestOperationTimeout tells if the handler of an operation passes a context with a deadline to the service:
+// timeouts of the service are left out of operations without context.Context argument
+func HasRestOperationTimeout(s model.Struct, o model.Operation) bool {
+	_, found := getRestOperationTimeout(s, o)
+	return found && HasContext(o)
+}
+
+// GetRestOperationTimeout returns the timeout of an operation as go-expression, like 5 * time.Second
+func GetRestOperationTimeout(s model.Struct, o model.Operation) string {
+	timeout, _ := getRestOperationTimeout(s, o)
+	for _, unit := range []struct {
+		duration time.Duration
+		name     string
+	}{{time.Minute, "time.Minute"}, {time.Second, "time.Second"}, {time.Millisecond, "time.Millisecond"}} {
+		if timeout%unit.duration == 0 {
+			return fmt.Sprintf("%d * %s", timeout/unit.duration, unit.name)
+		}
+	}
+	return fmt.Sprintf("time.Duration(%d)", timeout)
+}
+
+// GetRestOperationMaxBodyKB returns the size in KB that the request body of an operation is limited to: its own
+// limit, otherwise the one of its service, otherwise defaultMaxBodyKB
+func GetRestOperationMaxBodyKB(s model.Struct, o model.Operation) int {
+	if value, found := getRestOperationAttribute(s, o, restAnnotation.ParamMaxBodyKB); found {
+		if maxBodyKB, ok := parseMaxBodyKB(value); ok {
+			return maxBodyKB
+		}
+	}
+	return defaultMaxBodyKB
+}
+
+// GetRestOperationConsumes returns the media types of the request body that an operation accepts, like
+// application/json: its own, otherwise the ones of its service. Without, any Content-Type is accepted.
+func GetRestOperationConsumes(s model.Struct, o model.Operation) []string {
+	value, found := getRestOperationAttribute(s, o, restAnnotation.ParamConsumes)
+	if !found {
+		return nil
+	}
+	mediaTypes := []string{}
+	for _, mediaType := range strings.Split(value, ",") {
+		if mediaType = strings.TrimSpace(mediaType); mediaType != "" {
+			mediaTypes = append(mediaTypes, mediaType)
+		}
+	}
+	return mediaTypes
+}
+
 func HasRestOperationAfter(o model.Operation) bool {
 	annotations := annotation.NewRegistry(restAnnotation.Get())
 	if ann, ok := annotations.ResolveAnnotationByName(o.DocLines, restAnnotation.TypeRestOperation); ok {
@@ -344,6 +1121,16 @@ func GetRestOperationPath(o model.Operation) string {
 	return ""
 }
 
+// GetRestOperationPathPattern returns the regular expression that matches the full paths of an operation of a
+// service, in which a path-param matches a single segment
+func GetRestOperationPathPattern(s model.Struct, o model.Operation) string {
+	parts := regexp.MustCompile(`\{\w+\}`).Split(GetRestServicePath(s)+GetRestOperationPath(o), -1)
+	for idx, part := range parts {
+		parts[idx] = regexp.QuoteMeta(part)
+	}
+	return "^" + strings.Join(parts, "[^/]+") + "$"
+}
+
 func HasAnyPathParam(o model.Operation) bool {
 	return len(getAllPathParams(o)) > 0
 }
@@ -394,6 +1181,10 @@ func IsRestOperationCSV(o model.Operation) bool {
 	return GetRestOperationFormat(o) == "CSV"
 }
 
+func IsRestOperationXLSX(o model.Operation) bool {
+	return GetRestOperationFormat(o) == "XLSX"
+}
+
 func IsRestOperationTXT(o model.Operation) bool {
 	return GetRestOperationFormat(o) == "TXT"
 }
@@ -422,6 +1213,8 @@ func GetContentType(operation model.Operation) string {
 		return "text/html; charset=UTF-8"
 	case "CSV":
 		return "text/csv; charset=UTF-8"
+	case "XLSX":
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
 	case "TXT":
 		return "text/plain; charset=UTF-8"
 	case "MD":
@@ -439,6 +1232,301 @@ func GetRestOperationFilename(o model.Operation) string {
 	return ""
 }
 
+func HasFeatureFlag(o model.Operation) bool {
+	annotations := annotation.NewRegistry(restAnnotation.Get())
+	_, ok := annotations.ResolveAnnotationByName(o.DocLines, restAnnotation.TypeFeatureFlag)
+	return ok
+}
+
+func GetFeatureFlagName(o model.Operation) string {
+	annotations := annotation.NewRegistry(restAnnotation.Get())
+	if ann, ok := annotations.ResolveAnnotationByName(o.DocLines, restAnnotation.TypeFeatureFlag); ok {
+		return ann.Attributes[restAnnotation.ParamName]
+	}
+	return ""
+}
+
+// GetFeatureFlagStatus returns the http-status returned when the feature-flag of the operation is off
+func GetFeatureFlagStatus(o model.Operation) string {
+	annotations := annotation.NewRegistry(restAnnotation.Get())
+	if ann, ok := annotations.ResolveAnnotationByName(o.DocLines, restAnnotation.TypeFeatureFlag); ok {
+		if ann.Attributes[restAnnotation.ParamWhenOff] == restAnnotation.WhenOffDisabled {
+			return "http.StatusServiceUnavailable"
+		}
+	}
+	return "http.StatusNotFound"
+}
+
+func HasFeatureFlags(structs []model.Struct) bool {
+	return len(GetFeatureFlagNames(structs)) > 0
+}
+
+func IsRestOperationAudited(o model.Operation) bool {
+	annotations := annotation.NewRegistry(restAnnotation.Get())
+	_, ok := annotations.ResolveAnnotationByName(o.DocLines, restAnnotation.TypeAudited)
+	return ok
+}
+
+// IsRestOperationAuditedBefore tells whether the service provides the state before the operation, for its
+// audit-event, with a method <operation>AuditBefore
+func IsRestOperationAuditedBefore(o model.Operation) bool {
+	annotations := annotation.NewRegistry(restAnnotation.Get())
+	if ann, ok := annotations.ResolveAnnotationByName(o.DocLines, restAnnotation.TypeAudited); ok {
+		return ann.Attributes[restAnnotation.ParamBefore] == "true"
+	}
+	return false
+}
+
+// GetRestOperationAuditAction returns what the audit-events of the operation say was done: its name by default
+func GetRestOperationAuditAction(o model.Operation) string {
+	annotations := annotation.NewRegistry(restAnnotation.Get())
+	if ann, ok := annotations.ResolveAnnotationByName(o.DocLines, restAnnotation.TypeAudited); ok {
+		if action := ann.Attributes[restAnnotation.ParamAction]; action != "" {
+			return action
+		}
+	}
+	return o.Name
+}
+
+// GetAuditedValue returns the variable of the handler with the state after the operation: its result, otherwise
+// its request body
+func GetAuditedValue(o model.Operation) string {
+	if HasOutput(o) {
+		return "result"
+	}
+	return GetInputArgName(o)
+}
+
+// GetAuditedFields returns the json-names of the fields with @Audited of the struct that the operation returns,
+// otherwise of its request body
+func GetAuditedFields(o model.Operation, structs []model.Struct) []string {
+	typeName := GetInputArgType(o)
+	if HasOutput(o) {
+		typeName = GetOutputArgType(o)
+	}
+	// the types are qualified when generating into a separate package
+	typeName = strings.TrimPrefix(typeName, "*")
+	typeName = typeName[strings.LastIndex(typeName, ".")+1:]
+
+	annotations := annotation.NewRegistry(restAnnotation.Get())
+	names := []string{}
+	for _, s := range structs {
+		if s.Name != typeName {
+			continue
+		}
+		for _, f := range s.Fields {
+			if _, ok := annotations.ResolveAnnotationByName(f.DocLines, restAnnotation.TypeAudited); !ok {
+				continue
+			}
+			name := strings.Split(f.GetTagMap()["json"], ",")[0]
+			if name == "" {
+				name = f.Name
+			}
+			if name != "-" {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+func IsRestServiceTenantScoped(s model.Struct) bool {
+	return IsRestService(s) && tenant.IsTenantScoped(s.DocLines)
+}
+
+// GetRestServiceTenantHeader returns the request-header that tells the tenant of a call to a tenant-scoped service
+func GetRestServiceTenantHeader(s model.Struct) string {
+	return tenant.GetHeader(s)
+}
+
+func HasTenantScopedServices(structs []model.Struct) bool {
+	for _, s := range structs {
+		if IsRestServiceTenantScoped(s) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasTenantArg(o model.Operation) bool {
+	for _, arg := range o.InputArgs {
+		if tenant.IsTenantArg(arg) {
+			return true
+		}
+	}
+	return false
+}
+
+func HasETag(o model.Operation) bool {
+	annotations := annotation.NewRegistry(restAnnotation.Get())
+	_, ok := annotations.ResolveAnnotationByName(o.DocLines, restAnnotation.TypeETag)
+	return ok
+}
+
+// HasETagPrecondition tells whether the handler checks the If-Match header against the version of the resource
+// before changing it, with a method <operation>Version of the service
+func HasETagPrecondition(o model.Operation) bool {
+	return HasETag(o) && GetRestOperationMethod(o) != "GET"
+}
+
+// IsETagRequired tells whether a resource can only be changed with an If-Match header
+func IsETagRequired(o model.Operation) bool {
+	annotations := annotation.NewRegistry(restAnnotation.Get())
+	if ann, ok := annotations.ResolveAnnotationByName(o.DocLines, restAnnotation.TypeETag); ok {
+		return ann.Attributes[restAnnotation.ParamRequired] == "true"
+	}
+	return false
+}
+
+func HasETagOperations(structs []model.Struct) bool {
+	for _, s := range structs {
+		if !IsRestService(s) {
+			continue
+		}
+		for _, o := range s.Operations {
+			if IsRestOperation(*o) && HasETag(*o) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func IsRestOperationIdempotent(o model.Operation) bool {
+	annotations := annotation.NewRegistry(restAnnotation.Get())
+	_, ok := annotations.ResolveAnnotationByName(o.DocLines, restAnnotation.TypeIdempotent)
+	return ok
+}
+
+// IsIdempotencyKeyRequired tells whether calls of an idempotent operation must have an Idempotency-Key header
+func IsIdempotencyKeyRequired(o model.Operation) bool {
+	annotations := annotation.NewRegistry(restAnnotation.Get())
+	if ann, ok := annotations.ResolveAnnotationByName(o.DocLines, restAnnotation.TypeIdempotent); ok {
+		return ann.Attributes[restAnnotation.ParamRequired] == "true"
+	}
+	return false
+}
+
+// GetIdempotencyScope returns the expression for the scope of the Idempotency-Keys of an operation: the keys of
+// tenant-scoped services are per tenant
+func GetIdempotencyScope(service model.Struct, o model.Operation) string {
+	scope := fmt.Sprintf("%q", service.Name+"."+o.Name)
+	if IsRestServiceTenantScoped(service) {
+		return scope + ` + "/" + ` + tenant.ArgName
+	}
+	return scope
+}
+
+func HasIdempotentOperations(structs []model.Struct) bool {
+	for _, s := range structs {
+		if !IsRestService(s) {
+			continue
+		}
+		for _, o := range s.Operations {
+			if IsRestOperation(*o) && IsRestOperationIdempotent(*o) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func IsRestOperationAsync(o model.Operation) bool {
+	annotations := annotation.NewRegistry(restAnnotation.Get())
+	_, ok := annotations.ResolveAnnotationByName(o.DocLines, restAnnotation.TypeAsync)
+	return ok
+}
+
+// IsRestServiceAsync tells whether a rest-service has asynchronous operations, and so serves the status of their jobs
+func IsRestServiceAsync(s model.Struct) bool {
+	for _, o := range s.Operations {
+		if IsRestOperation(*o) && IsRestOperationAsync(*o) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetAsyncJobsPath returns the path below which a rest-service serves the status of the jobs of its asynchronous
+// operations, like /api/jobs
+func GetAsyncJobsPath(s model.Struct) string {
+	return strings.TrimSuffix(GetRestServicePath(s), "/") + asyncJobsPath
+}
+
+// GetAsyncJobTenant returns the expression for the tenant of the jobs of a rest-service: only the tenant that started
+// a job can see it
+func GetAsyncJobTenant(s model.Struct) string {
+	if IsRestServiceTenantScoped(s) {
+		return tenant.ArgName
+	}
+	return `""`
+}
+
+func HasAsyncOperations(structs []model.Struct) bool {
+	for _, s := range structs {
+		if IsRestService(s) && IsRestServiceAsync(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasRequestBodies tells whether rest-services have operations that read their input from a limited request body
+func HasRequestBodies(structs []model.Struct) bool {
+	for _, s := range structs {
+		if !IsRestService(s) {
+			continue
+		}
+		for _, o := range s.Operations {
+			if IsRestOperation(*o) && HasInput(*o) && !HasUpload(*o) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func HasAuditedOperations(structs []model.Struct) bool {
+	for _, s := range structs {
+		if !IsRestService(s) {
+			continue
+		}
+		for _, o := range s.Operations {
+			if IsRestOperation(*o) && IsRestOperationAudited(*o) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func quotedList(values []string) string {
+	quoted := []string{}
+	for _, value := range values {
+		quoted = append(quoted, strconv.Quote(value))
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// GetFeatureFlagNames returns the sorted unique names of all feature-flags of the rest-operations
+func GetFeatureFlagNames(structs []model.Struct) []string {
+	names := []string{}
+	found := map[string]bool{}
+	for _, s := range structs {
+		if !IsRestService(s) {
+			continue
+		}
+		for _, o := range s.Operations {
+			if IsRestOperation(*o) && HasFeatureFlag(*o) && !found[GetFeatureFlagName(*o)] {
+				found[GetFeatureFlagName(*o)] = true
+				names = append(names, GetFeatureFlagName(*o))
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
 func GetRestOperationRolesString(o model.Operation) string {
 	roles := GetRestOperationRoles(o)
 	for i, r := range roles {
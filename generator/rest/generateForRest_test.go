@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"strings"
 	"testing"
 
+	"github.com/MarcGrol/golangAnnotations/generator"
 	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
 	"github.com/MarcGrol/golangAnnotations/model"
 	"github.com/stretchr/testify/assert"
@@ -18,6 +20,15 @@ func cleanup() {
 	os.Remove(generationUtil.Prefixed("./testData/httpClientForMyService.go"))
 	os.Remove(generationUtil.Prefixed("./testData/httpMyServiceHelpers_test.go"))
 	os.Remove(generationUtil.Prefixed("./testData/testDataTestLog/httpTestMyService.go"))
+	os.Remove(generationUtil.Prefixed("./testData/featureFlags.go"))
+	os.Remove(generationUtil.Prefixed("./testData/audit.go"))
+	os.Remove(generationUtil.Prefixed("./testData/tenant.go"))
+	os.Remove(generationUtil.Prefixed("./testData/etag.go"))
+	os.Remove(generationUtil.Prefixed("./testData/idempotency.go"))
+	os.Remove(generationUtil.Prefixed("./testData/async.go"))
+	os.Remove(generationUtil.Prefixed("./testData/requestBody.go"))
+	os.Remove(generationUtil.Prefixed("./testData/requestBodyLegacy.go"))
+	os.Remove(generationUtil.Prefixed("./testData/httpMyServiceV2.go"))
 }
 
 func TestGenerateForWeb(t *testing.T) {
@@ -47,7 +58,7 @@ func TestGenerateForWeb(t *testing.T) {
 			},
 		})
 	{
-		err := NewGenerator().Generate("testData", model.ParsedSources{Structs: s})
+		err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
 		assert.Nil(t, err)
 	}
 
@@ -81,6 +92,900 @@ func TestGenerateForWeb(t *testing.T) {
 
 }
 
+func TestGenerateForWebWithFeatureFlag(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			DocLines:    []string{"// @RestService( path = \"/api\")"},
+			PackageName: "testData",
+			Name:        "MyService",
+			Operations: []*model.Operation{
+				{
+					DocLines: []string{
+						"// @RestOperation(path = \"/checkout\", method = \"POST\" )",
+						"// @FeatureFlag( name = \"new-checkout\", whenOff = \"disabled\" )",
+					},
+					Name:          "checkout",
+					RelatedStruct: &model.Field{TypeName: "MyService"},
+					InputArgs:     []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "rc", TypeName: "request.Context"}},
+					OutputArgs:    []model.Field{{TypeName: "error"}},
+				},
+			},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Nil(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/httpMyService.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `if !featureFlagProvider.IsEnabled(c, rc, "new-checkout") {`)
+	assert.Contains(t, string(data), `http.Error(w, "Feature new-checkout is not enabled", http.StatusServiceUnavailable)`)
+
+	data, err = ioutil.ReadFile(generationUtil.Prefixed("./testData/featureFlags.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `var FeatureFlagNames = []string{
+	"new-checkout",
+}`)
+}
+
+func TestGenerateForWebWithAudit(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			DocLines:    []string{"// @RestService( path = \"/api\")"},
+			PackageName: "testData",
+			Name:        "MyService",
+			Operations: []*model.Operation{
+				{
+					DocLines: []string{
+						"// @RestOperation( path = \"/person/{uid}\", method = \"PUT\", format = \"JSON\" )",
+						"// @Audited( action = \"person.update\", before = \"true\" )",
+					},
+					Name:          "updatePerson",
+					RelatedStruct: &model.Field{TypeName: "MyService"},
+					InputArgs: []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "rc", TypeName: "request.Context"},
+						{Name: "uid", TypeName: "string"}, {Name: "person", TypeName: "Person"}},
+					OutputArgs: []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+				},
+			},
+		},
+		{
+			PackageName: "testData",
+			Name:        "Person",
+			Fields: []model.Field{
+				{Name: "UID", TypeName: "string", Tag: "`json:\"uid\"`"},
+				{Name: "Email", TypeName: "string", Tag: "`json:\"email,omitempty\"`", DocLines: []string{"// @Audited()"}},
+				{Name: "Role", TypeName: "string", DocLines: []string{"// @Audited()"}},
+			},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Nil(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/httpMyService.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "auditBefore, err := service.updatePersonAuditBefore(c, rc, uid, person)")
+	assert.Contains(t, string(data), `err = publishAuditEvent(c, rc, r, AuditEvent{
+			Service:   "MyService",
+			Operation: "updatePerson",
+			Action:    "person.update",
+			Before:    auditSnapshot(auditBefore, "email", "Role"),
+			After:     auditSnapshot(result, "email", "Role"),
+		})`)
+
+	data, err = ioutil.ReadFile(generationUtil.Prefixed("./testData/audit.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "func SetAuditActorProvider(provider AuditActorProvider) {")
+	assert.Contains(t, string(data), "err = bus.New().Publish(c, rc, &envlp)")
+}
+
+func TestAuditBeforeWithoutAuditedFields(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			DocLines:    []string{"// @RestService( path = \"/api\")"},
+			PackageName: "testData",
+			Name:        "MyService",
+			Operations: []*model.Operation{
+				{
+					DocLines: []string{
+						"// @RestOperation( path = \"/person/{uid}\", method = \"DELETE\" )",
+						"// @Audited( before = \"true\" )",
+					},
+					Name:          "deletePerson",
+					RelatedStruct: &model.Field{TypeName: "MyService"},
+					InputArgs:     []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}},
+					OutputArgs:    []model.Field{{TypeName: "error"}},
+				},
+			},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Operation MyService.deletePerson audits the state before it, but its result has no fields with @Audited")
+}
+
+func TestGenerateForWebWithTenant(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			DocLines: []string{
+				"// @RestService( path = \"/api\")",
+				"// @TenantScoped( header = \"X-Customer\" )",
+			},
+			PackageName: "testData",
+			Name:        "MyService",
+			Operations: []*model.Operation{
+				{
+					DocLines:      []string{"// @RestOperation( path = \"/person/{uid}\", method = \"GET\", format = \"JSON\" )"},
+					Name:          "getPerson",
+					RelatedStruct: &model.Field{TypeName: "MyService"},
+					InputArgs: []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "rc", TypeName: "request.Context"},
+						{Name: "tenantID", TypeName: "string"}, {Name: "uid", TypeName: "string"}},
+					OutputArgs: []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+				},
+			},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Nil(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/httpMyService.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `tenantID, err := tenantProvider.GetTenantID(c, rc, r, "X-Customer")`)
+	assert.Contains(t, string(data), `"Missing tenant in header X-Customer"`)
+	assert.NotContains(t, string(data), `tenantID := `)
+
+	data, err = ioutil.ReadFile(generationUtil.Prefixed("./testData/tenant.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "func SetTenantProvider(provider TenantProvider) {")
+}
+
+func TestTenantScopedOperationWithoutTenant(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			DocLines: []string{
+				"// @RestService( path = \"/api\")",
+				"// @TenantScoped()",
+			},
+			PackageName: "testData",
+			Name:        "MyService",
+			Operations: []*model.Operation{
+				{
+					DocLines:      []string{"// @RestOperation( path = \"/person/{uid}\", method = \"DELETE\" )"},
+					Name:          "deletePerson",
+					RelatedStruct: &model.Field{TypeName: "MyService"},
+					InputArgs:     []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}},
+					OutputArgs:    []model.Field{{TypeName: "error"}},
+				},
+			},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Operation MyService.deletePerson of tenant-scoped service MyService has no argument 'tenantID string' for the tenant")
+}
+
+func TestGenerateForWebWithETag(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			DocLines:    []string{"// @RestService( path = \"/api\")"},
+			PackageName: "testData",
+			Name:        "MyService",
+			Operations: []*model.Operation{
+				{
+					DocLines:      []string{"// @RestOperation( path = \"/person/{uid}\", method = \"GET\", format = \"JSON\" )", "// @ETag()"},
+					Name:          "getPerson",
+					RelatedStruct: &model.Field{TypeName: "MyService"},
+					InputArgs:     []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}},
+					OutputArgs:    []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+				},
+				{
+					DocLines: []string{
+						"// @RestOperation( path = \"/person/{uid}\", method = \"PUT\", format = \"JSON\" )",
+						"// @ETag( required = \"true\" )",
+					},
+					Name:          "updatePerson",
+					RelatedStruct: &model.Field{TypeName: "MyService"},
+					InputArgs:     []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}, {Name: "person", TypeName: "Person"}},
+					OutputArgs:    []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+				},
+				{
+					Name:          "updatePersonVersion",
+					RelatedStruct: &model.Field{TypeName: "MyService"},
+					InputArgs:     []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}, {Name: "person", TypeName: "Person"}},
+					OutputArgs:    []model.Field{{TypeName: "int64"}, {TypeName: "error"}},
+				},
+			},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Nil(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/httpMyService.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `http.Error(w, "Missing If-Match header for updatePerson", http.StatusPreconditionRequired)`)
+	assert.Contains(t, string(data), "version, err := service.updatePersonVersion(c, uid, person)")
+	assert.Contains(t, string(data), `http.Error(w, "Version of resource has changed", http.StatusPreconditionFailed)`)
+	assert.Contains(t, string(data), "c = withExpectedVersion(c, version)")
+	assert.Contains(t, string(data), "if errors.Is(err, ErrVersionChanged) {")
+	assert.Equal(t, 1, strings.Count(string(data), "withExpectedVersion("))
+	assert.NotContains(t, string(data), "service.getPersonVersion")
+	assert.Contains(t, string(data), `w.Header().Set("ETag", formatETag(versioned.GetAggregateVersion()))`)
+
+	data, err = ioutil.ReadFile(generationUtil.Prefixed("./testData/etag.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "type VersionedResource interface {")
+	assert.Contains(t, string(data), "func etagMatches(ifMatch string, version int64) bool {")
+	assert.Contains(t, string(data), "func ExpectedVersion(c context.Context) (int64, bool) {")
+}
+
+func TestETagWithoutResource(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			DocLines:    []string{"// @RestService( path = \"/api\")"},
+			PackageName: "testData",
+			Name:        "MyService",
+			Operations: []*model.Operation{
+				{
+					DocLines:      []string{"// @RestOperation( path = \"/ping\", method = \"GET\" )", "// @ETag()"},
+					Name:          "ping",
+					RelatedStruct: &model.Field{TypeName: "MyService"},
+					InputArgs:     []model.Field{{Name: "c", TypeName: "context.Context"}},
+					OutputArgs:    []model.Field{{TypeName: "error"}},
+				},
+			},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Operation MyService.ping with @ETag has neither a result to tag nor a resource to change")
+}
+
+func TestETagWithoutContext(t *testing.T) {
+	service := model.Struct{
+		DocLines: []string{"// @RestService( path = \"/api\")"},
+		Name:     "MyService",
+		Operations: []*model.Operation{
+			{
+				Filename:      "myService.go",
+				Line:          12,
+				DocLines:      []string{"// @RestOperation( path = \"/person/{uid}\", method = \"PUT\" )", "// @ETag()"},
+				Name:          "updatePerson",
+				RelatedStruct: &model.Field{TypeName: "MyService"},
+				InputArgs:     []model.Field{{Name: "uid", TypeName: "string"}},
+				OutputArgs:    []model.Field{{TypeName: "error"}},
+			},
+		},
+	}
+	err := validateETags(service)
+	assert.EqualError(t, err, "myService.go:11: Operation MyService.updatePerson with @ETag has no context.Context argument to pass the expected version to")
+}
+
+func TestETagWithoutVersionMethod(t *testing.T) {
+	service := model.Struct{
+		DocLines: []string{"// @RestService( path = \"/api\")"},
+		Name:     "MyService",
+		Operations: []*model.Operation{
+			{
+				Filename:      "myService.go",
+				Line:          12,
+				DocLines:      []string{"// @RestOperation( path = \"/person/{uid}\", method = \"PUT\" )", "// @ETag()"},
+				Name:          "updatePerson",
+				RelatedStruct: &model.Field{TypeName: "MyService"},
+				InputArgs:     []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}},
+				OutputArgs:    []model.Field{{TypeName: "error"}},
+			},
+		},
+	}
+	err := validateETags(service)
+	assert.EqualError(t, err, "myService.go:11: Operation MyService.updatePerson with @ETag has no method MyService.updatePersonVersion that returns the int64 version of the resource")
+
+	service.Operations = append(service.Operations, &model.Operation{
+		Name:          "updatePersonVersion",
+		RelatedStruct: &model.Field{TypeName: "MyService"},
+		InputArgs:     []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}},
+		OutputArgs:    []model.Field{{TypeName: "string"}, {TypeName: "error"}},
+	})
+	err = validateETags(service)
+	assert.EqualError(t, err, "myService.go:11: Operation MyService.updatePerson with @ETag has no method MyService.updatePersonVersion that returns the int64 version of the resource")
+
+	service.Operations[1].OutputArgs[0].TypeName = "int64"
+	err = validateETags(service)
+	assert.NoError(t, err)
+}
+
+func TestGenerateForWebWithIdempotency(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			DocLines:    []string{"// @RestService( path = \"/api\")"},
+			PackageName: "testData",
+			Name:        "MyService",
+			Operations: []*model.Operation{
+				{
+					DocLines: []string{
+						"// @RestOperation( path = \"/person\", method = \"POST\", format = \"JSON\" )",
+						"// @Idempotent( required = \"true\" )",
+					},
+					Name:          "createPerson",
+					RelatedStruct: &model.Field{TypeName: "MyService"},
+					InputArgs:     []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "person", TypeName: "Person"}},
+					OutputArgs:    []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+				},
+			},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Nil(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/httpMyService.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `http.Error(w, "Missing Idempotency-Key header for createPerson", http.StatusBadRequest)`)
+	assert.Contains(t, string(data), `recorder, answered, err := startIdempotentCall(c, rc, w, r, "MyService.createPerson", idempotencyKey)`)
+	assert.Contains(t, string(data), "defer recorder.finish(c, rc)")
+	// the idempotency-key reads the body that is limited already
+	assert.Less(t, strings.Index(string(data), "r.Body = http.MaxBytesReader(w, r.Body, 1024*1024)"), strings.Index(string(data), "startIdempotentCall("))
+	assert.Less(t, strings.Index(string(data), "startIdempotentCall("), strings.Index(string(data), "json.NewDecoder(r.Body).Decode(&person)"))
+	assert.Equal(t, 2, strings.Count(string(data), "if isRequestBodyTooLarge(err) {"))
+
+	data, err = ioutil.ReadFile(generationUtil.Prefixed("./testData/idempotency.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "type IdempotencyStore interface {")
+	assert.Contains(t, string(data), "func SetIdempotencyStore(store IdempotencyStore) {")
+}
+
+func TestConcurrentIdempotentCalls(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			DocLines:    []string{"// @RestService( path = \"/api\")"},
+			PackageName: "testData",
+			Name:        "MyService",
+			Operations: []*model.Operation{
+				{
+					DocLines:      []string{"// @RestOperation( path = \"/person\", method = \"POST\", format = \"JSON\" )", "// @Idempotent()"},
+					Name:          "createPerson",
+					RelatedStruct: &model.Field{TypeName: "MyService"},
+					InputArgs:     []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "person", TypeName: "Person"}},
+					OutputArgs:    []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+				},
+			},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Nil(t, err)
+
+	// the second call arrives while the first one is in progress, the third one after it
+	runGeneratedCode(t, []string{generationUtil.Prefixed("./testData/idempotency.go")}, `package testData
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestConcurrentCalls(t *testing.T) {
+	started := make(chan struct{})
+	proceed := make(chan struct{})
+	calls := 0
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder, answered, err := startIdempotentCall(r.Context(), nil, w, r, "MyService.createPerson", "key")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if answered {
+			return
+		}
+		defer recorder.finish(r.Context(), nil)
+		w = recorder
+		calls++
+		close(started)
+		<-proceed
+		w.WriteHeader(http.StatusCreated)
+	})
+	call := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/person", strings.NewReader("{}")))
+		return w
+	}
+
+	first := make(chan *httptest.ResponseRecorder)
+	go func() {
+		first <- call()
+	}()
+	<-started
+	second := call()
+	close(proceed)
+
+	if status := (<-first).Code; status != http.StatusCreated {
+		t.Errorf("First call answered %d", status)
+	}
+	if second.Code != http.StatusConflict {
+		t.Errorf("Concurrent call answered %d", second.Code)
+	}
+	third := call()
+	if third.Code != http.StatusCreated || third.Header().Get("Idempotent-Replayed") != "true" {
+		t.Errorf("Retry answered %d without replay", third.Code)
+	}
+	if calls != 1 {
+		t.Errorf("Operation called %d times", calls)
+	}
+}
+`)
+}
+
+func TestIdempotentSafeOperation(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			DocLines:    []string{"// @RestService( path = \"/api\")"},
+			PackageName: "testData",
+			Name:        "MyService",
+			Operations: []*model.Operation{
+				{
+					DocLines:      []string{"// @RestOperation( path = \"/person/{uid}\", method = \"PUT\" )", "// @Idempotent()"},
+					Name:          "updatePerson",
+					RelatedStruct: &model.Field{TypeName: "MyService"},
+					InputArgs:     []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}},
+					OutputArgs:    []model.Field{{TypeName: "error"}},
+				},
+			},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Operation MyService.updatePerson with @Idempotent must be a POST or PATCH: PUT is idempotent already")
+}
+
+func TestGenerateForWebWithAsync(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			DocLines:    []string{"// @RestService( path = \"/api\")"},
+			PackageName: "testData",
+			Name:        "MyService",
+			Operations: []*model.Operation{
+				{
+					DocLines: []string{
+						"// @RestOperation( path = \"/report\", method = \"POST\", format = \"JSON\" )",
+						"// @Async()",
+					},
+					Name:          "createReport",
+					RelatedStruct: &model.Field{TypeName: "MyService"},
+					InputArgs:     []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "report", TypeName: "Report"}},
+					OutputArgs:    []model.Field{{TypeName: "*Report"}, {TypeName: "error"}},
+				},
+			},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Nil(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/httpMyService.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `subRouter.HandleFunc("/jobs/{jobID}", asyncJobOfMyService(ts)).Methods("GET")`)
+	assert.Contains(t, string(data), `job, err := startAsyncJob(c, rc, "MyService.createReport", "")`)
+	assert.Contains(t, string(data), "go func(c context.Context) {\n\t\t\tdefer recoverAsyncJob(c, rc, job)\n")
+	assert.Contains(t, string(data), "}(detachedContext{c})")
+	assert.Contains(t, string(data), "completeAsyncJob(c, rc, job, result, err)")
+	assert.Contains(t, string(data), `writeAsyncJob(c, rc, w, "/api/jobs", job)`)
+
+	data, err = ioutil.ReadFile(generationUtil.Prefixed("./testData/async.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "type AsyncJobStore interface {")
+	assert.Contains(t, string(data), "func SetAsyncJobStore(store AsyncJobStore) {")
+
+	data, err = ioutil.ReadFile(generationUtil.Prefixed("./testData/httpMyServiceHelpers_test.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "if httpResp.Code == http.StatusAccepted {")
+}
+
+func TestPanickingAsyncJob(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			DocLines:    []string{"// @RestService( path = \"/api\")"},
+			PackageName: "testData",
+			Name:        "MyService",
+			Operations: []*model.Operation{
+				{
+					DocLines:      []string{"// @RestOperation( path = \"/report\", method = \"POST\", format = \"no_content\" )", "// @Async()"},
+					Name:          "createReport",
+					RelatedStruct: &model.Field{TypeName: "MyService"},
+					InputArgs:     []model.Field{{Name: "c", TypeName: "context.Context"}},
+					OutputArgs:    []model.Field{{TypeName: "error"}},
+				},
+			},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Nil(t, err)
+
+	// the job panics in its goroutine, like the generated handler runs it
+	runGeneratedCode(t, []string{generationUtil.Prefixed("./testData/async.go")}, `package testData
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"generated/envelope"
+	"generated/mylog"
+)
+
+type requestContext struct{}
+
+func (requestContext) GetSessionUID() string {
+	return ""
+}
+
+func (requestContext) GetEnvelopes() []*envelope.Envelope {
+	return nil
+}
+
+func TestPanickingJob(t *testing.T) {
+	c := context.Background()
+	rc := requestContext{}
+	job, err := startAsyncJob(c, rc, "MyService.createReport", "")
+	if err != nil {
+		t.Fatalf("Error starting job: %s", err)
+	}
+
+	done := make(chan struct{})
+	go func(c context.Context) {
+		defer close(done)
+		defer recoverAsyncJob(c, rc, job)
+		panic("boom")
+	}(detachedContext{c})
+	<-done
+
+	stored, err := asyncJobStore.Get(c, rc, job.ID)
+	if err != nil || stored == nil {
+		t.Fatalf("Error getting job: %v", err)
+	}
+	if stored.Status != AsyncJobFailed || stored.Completed == nil {
+		t.Errorf("Job has status %s after a panic", stored.Status)
+	}
+	if strings.Contains(stored.Error, "boom") {
+		t.Errorf("Job tells the panic to clients: %s", stored.Error)
+	}
+	if len(mylog.Errors) != 1 || !strings.HasPrefix(mylog.Errors[0], "Panic in job "+job.ID+" of MyService.createReport: boom") {
+		t.Errorf("Panic is not logged: %v", mylog.Errors)
+	}
+}
+`)
+}
+
+func TestAsyncWithoutContext(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			DocLines:    []string{"// @RestService( path = \"/api\")"},
+			PackageName: "testData",
+			Name:        "MyService",
+			Operations: []*model.Operation{
+				{
+					DocLines:      []string{"// @RestOperation( path = \"/report\", method = \"POST\", format = \"no_content\" )", "// @Async()"},
+					Name:          "createReport",
+					RelatedStruct: &model.Field{TypeName: "MyService"},
+					InputArgs:     []model.Field{{Name: "report", TypeName: "Report"}},
+					OutputArgs:    []model.Field{{TypeName: "error"}},
+				},
+			},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Operation MyService.createReport with @Async has no context.Context argument to run its job with")
+}
+
+func TestGenerateForWebWithRecording(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			DocLines:    []string{"// @RestService( path = \"/api\")", "// @Recording()"},
+			PackageName: "testData",
+			Name:        "MyService",
+			Operations: []*model.Operation{
+				{
+					DocLines:      []string{"// @RestOperation(path = \"/person/{uid}\", method = \"GET\", format = \"JSON\" )"},
+					Name:          "getPerson",
+					RelatedStruct: &model.Field{TypeName: "MyService"},
+					InputArgs:     []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}},
+					OutputArgs:    []model.Field{{TypeName: "error"}},
+				},
+			},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Nil(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/httpMyServiceHelpers_test.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `if os.Getenv("RECORD_FIXTURES") == "true" {`)
+	assert.Contains(t, string(data), "err = RecordMyServiceFixture(MyServiceFixturesDir, httpReq, requestPayload, httpResp.Code, httpResp.Header(), httpResp.Body.Bytes())")
+}
+
+func TestGenerateForWebWithTimeout(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			DocLines:    []string{"// @RestService( path = \"/api\", timeout = \"1m\" )"},
+			PackageName: "testData",
+			Name:        "MyService",
+			Operations: []*model.Operation{
+				{
+					DocLines:      []string{"// @RestOperation(path = \"/checkout\", method = \"POST\", timeout = \"1500ms\" )"},
+					Name:          "checkout",
+					RelatedStruct: &model.Field{TypeName: "MyService"},
+					InputArgs:     []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "rc", TypeName: "request.Context"}},
+					OutputArgs:    []model.Field{{TypeName: "error"}},
+				},
+				{
+					DocLines:      []string{"// @RestOperation(path = \"/status\", method = \"GET\" )"},
+					Name:          "status",
+					RelatedStruct: &model.Field{TypeName: "MyService"},
+					InputArgs:     []model.Field{{Name: "c", TypeName: "context.Context"}},
+					OutputArgs:    []model.Field{{TypeName: "error"}},
+				},
+				{
+					DocLines:      []string{"// @RestOperation(path = \"/ping\", method = \"GET\" )"},
+					Name:          "ping",
+					RelatedStruct: &model.Field{TypeName: "MyService"},
+					OutputArgs:    []model.Field{{TypeName: "error"}},
+				},
+			},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Nil(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/httpMyService.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "c, cancel := context.WithTimeout(c, 1500*time.Millisecond)")
+	assert.Contains(t, string(data), "c, cancel := context.WithTimeout(c, 1*time.Minute)")
+	assert.Contains(t, string(data), `http.Error(w, "Timeout of checkout exceeded", http.StatusGatewayTimeout)`)
+	assert.NotContains(t, string(data), "Timeout of ping exceeded")
+	assert.Contains(t, string(data), `"time"`)
+}
+
+func TestInvalidTimeout(t *testing.T) {
+	service := model.Struct{
+		DocLines: []string{`// @RestService( path = "/api" )`},
+		Name:     "MyService",
+		Operations: []*model.Operation{
+			{
+				Filename: "myService.go",
+				Line:     21,
+				DocLines: []string{`// @RestOperation( method = "GET", path = "/person", timeout = "5 seconds" )`},
+				Name:     "getPerson",
+				InputArgs: []model.Field{
+					{Name: "c", TypeName: "context.Context"},
+				},
+			},
+			{
+				Filename:  "myService.go",
+				Line:      26,
+				DocLines:  []string{`// @RestOperation( method = "GET", path = "/ping", timeout = "5s" )`},
+				Name:      "ping",
+				InputArgs: []model.Field{},
+			},
+		},
+	}
+	err := validateTimeouts(service)
+	assert.EqualError(t, err, "myService.go:20: Timeout '5 seconds' of operation MyService.getPerson is no positive duration, like 5s")
+
+	service.Operations = service.Operations[1:]
+	err = validateTimeouts(service)
+	assert.EqualError(t, err, "myService.go:25: Operation MyService.ping has a timeout, but no context.Context argument to pass it to")
+}
+
+func TestGenerateForWebWithBodyLimits(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			DocLines:    []string{"// @RestService( path = \"/api\", consumes = \"application/json\" )"},
+			PackageName: "testData",
+			Name:        "MyService",
+			Operations: []*model.Operation{
+				{
+					DocLines:      []string{"// @RestOperation(path = \"/person\", method = \"POST\", maxBodyKB = \"64\", consumes = \"application/json, text/plain\" )"},
+					Name:          "createPerson",
+					RelatedStruct: &model.Field{TypeName: "MyService"},
+					InputArgs:     []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "person", TypeName: "Person"}},
+					OutputArgs:    []model.Field{{TypeName: "error"}},
+				},
+			},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Nil(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/httpMyService.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/json" && mediaType != "text/plain" {`)
+	assert.Contains(t, string(data), `http.Error(w, "Unsupported Content-Type: expected application/json, text/plain", http.StatusUnsupportedMediaType)`)
+	assert.Contains(t, string(data), "if r.ContentLength > 64*1024 {")
+	assert.Contains(t, string(data), "r.Body = http.MaxBytesReader(w, r.Body, 64*1024)")
+	assert.Contains(t, string(data), "if isRequestBodyTooLarge(err) {")
+	assert.Contains(t, string(data), `http.Error(w, "Request body exceeds 64 KB", http.StatusRequestEntityTooLarge)`)
+	assert.Contains(t, string(data), `"mime"`)
+
+	data, err = ioutil.ReadFile(generationUtil.Prefixed("./testData/requestBody.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "//go:build go1.19\n")
+	assert.Contains(t, string(data), "var maxBytesErr *http.MaxBytesError\n\treturn errors.As(err, &maxBytesErr)")
+
+	data, err = ioutil.ReadFile(generationUtil.Prefixed("./testData/requestBodyLegacy.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "//go:build !go1.19\n")
+	assert.Contains(t, string(data), `return err != nil && err.Error() == "http: request body too large"`)
+}
+
+func TestInvalidBodyLimits(t *testing.T) {
+	service := model.Struct{
+		DocLines: []string{`// @RestService( path = "/api" )`},
+		Name:     "MyService",
+		Operations: []*model.Operation{
+			{
+				Filename:  "myService.go",
+				Line:      21,
+				DocLines:  []string{`// @RestOperation( method = "POST", path = "/person", maxBodyKB = "1MB" )`},
+				Name:      "createPerson",
+				InputArgs: []model.Field{{Name: "person", TypeName: "Person"}},
+			},
+			{
+				Filename:  "myService.go",
+				Line:      26,
+				DocLines:  []string{`// @RestOperation( method = "GET", path = "/person", consumes = "application/json" )`},
+				Name:      "getPerson",
+				InputArgs: []model.Field{},
+			},
+		},
+	}
+	err := validateRequestBodies(service)
+	assert.EqualError(t, err, "myService.go:20: MaxBodyKB '1MB' of operation MyService.createPerson is no positive number")
+
+	service.Operations = service.Operations[1:]
+	err = validateRequestBodies(service)
+	assert.EqualError(t, err, "myService.go:25: Operation MyService.getPerson has consumes, but reads no request body")
+}
+
+func TestGenerateForWebWithVersions(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	versioned := func(name string, version string) model.Struct {
+		return model.Struct{
+			DocLines:    []string{fmt.Sprintf("// @RestService( path = \"/persons\", version = \"%s\", notest = \"true\" )", version)},
+			PackageName: "testData",
+			Name:        name,
+			Operations: []*model.Operation{
+				{
+					DocLines:      []string{"// @RestOperation(path = \"/{uid}\", method = \"GET\" )"},
+					Name:          "getPerson",
+					RelatedStruct: &model.Field{TypeName: name},
+					InputArgs:     []model.Field{{Name: "uid", TypeName: "string"}},
+					OutputArgs:    []model.Field{{TypeName: "error"}},
+				},
+			},
+		}
+	}
+	s := []model.Struct{versioned("MyService", "v1"), versioned("MyServiceV2", "v2")}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Nil(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/httpMyService.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `subRouter := router.PathPrefix("/v1/persons").Subrouter()`)
+	assert.Contains(t, string(data), `w.Header().Set("Deprecation", "true")`)
+	assert.Contains(t, string(data), `w.Header().Set("Link", "</v2/persons>; rel=\"successor-version\"")`)
+
+	data, err = ioutil.ReadFile(generationUtil.Prefixed("./testData/httpMyServiceV2.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `subRouter := router.PathPrefix("/v2/persons").Subrouter()`)
+	assert.NotContains(t, string(data), "Deprecation")
+}
+
+func TestInvalidVersions(t *testing.T) {
+	service := model.Struct{
+		Filename: "myService.go",
+		Line:     11,
+		DocLines: []string{`// @RestService( path = "/persons", version = "2" )`},
+		Name:     "MyService",
+	}
+	err := validateVersion(service, []model.Struct{service})
+	assert.EqualError(t, err, "myService.go:10: Version '2' of service MyService is no version like v1 or v2")
+
+	service.DocLines = []string{`// @RestService( path = "/persons", version = "v2" )`}
+	other := model.Struct{Name: "OtherService", DocLines: service.DocLines}
+	err = validateVersion(service, []model.Struct{other, service})
+	assert.EqualError(t, err, "myService.go:10: Services OtherService and MyService both serve version v2 of path '/persons'")
+}
+
+func TestGetRestServiceSuccessor(t *testing.T) {
+	service := func(name string, annotation string) model.Struct {
+		return model.Struct{Name: name, DocLines: []string{annotation}}
+	}
+	v1 := service("V1", `// @RestService( path = "/persons", version = "v1" )`)
+	v3 := service("V3", `// @RestService( path = "/persons", version = "v3" )`)
+	v2 := service("V2", `// @RestService( path = "/persons", version = "v2" )`)
+	other := service("Other", `// @RestService( path = "/tours", version = "v2" )`)
+	structs := []model.Struct{v1, v3, v2, other}
+
+	successor, found := GetRestServiceSuccessor(v1, structs)
+	assert.True(t, found)
+	assert.Equal(t, "V2", successor.Name)
+	_, found = GetRestServiceSuccessor(v3, structs)
+	assert.False(t, found)
+	_, found = GetRestServiceSuccessor(other, structs)
+	assert.False(t, found)
+}
+
+func TestGetFeatureFlagStatus(t *testing.T) {
+	o := model.Operation{
+		DocLines: []string{`// @FeatureFlag( name = "beta" )`},
+	}
+	assert.True(t, HasFeatureFlag(o))
+	assert.Equal(t, "beta", GetFeatureFlagName(o))
+	assert.Equal(t, "http.StatusNotFound", GetFeatureFlagStatus(o))
+}
+
 func TestIsRestService(t *testing.T) {
 	s := model.Struct{
 		DocLines: []string{
@@ -89,12 +994,27 @@ func TestIsRestService(t *testing.T) {
 	assert.True(t, IsRestService(s))
 }
 
+func TestIsRestServiceRecorded(t *testing.T) {
+	assert.True(t, IsRestServiceRecorded(model.Struct{DocLines: []string{`// @RestService( path = "/api")`, `// @Recording()`}}))
+	assert.False(t, IsRestServiceRecorded(model.Struct{DocLines: []string{`// @RestService( path = "/api")`}}))
+}
+
 func TestGetRestServicePath(t *testing.T) {
 	s := model.Struct{
 		DocLines: []string{
 			`//@RestService( path = "/api")`},
 	}
 	assert.Equal(t, "/api", GetRestServicePath(s))
+
+	s.DocLines = []string{`//@RestService( path = "/api/", version = "v2")`}
+	assert.Equal(t, "/v2/api", GetRestServicePath(s))
+	assert.Equal(t, "v2", GetRestServiceVersion(s))
+}
+
+func TestGetRestOperationPathPattern(t *testing.T) {
+	s := model.Struct{DocLines: []string{`// @RestService( path = "/api" )`}}
+	assert.Equal(t, "^/api/person/[^/]+/tags/[^/]+$", GetRestOperationPathPattern(s, model.Operation{DocLines: []string{`// @RestOperation( method = "GET", path = "/person/{uid}/tags/{tag}" )`}}))
+	assert.Equal(t, `^/api/person\.csv$`, GetRestOperationPathPattern(s, model.Operation{DocLines: []string{`// @RestOperation( method = "GET", path = "/person.csv" )`}}))
 }
 
 func TestIsRestOperation(t *testing.T) {
@@ -191,3 +1111,103 @@ func createOper(method string) model.Operation {
 	}
 	return o
 }
+
+func TestIsRestOperationXLSX(t *testing.T) {
+	o := model.Operation{
+		DocLines: []string{`//@RestOperation( method = "GET", path = "/api/person", format = "XLSX", filename = "persons.xlsx" )`},
+	}
+	assert.True(t, IsRestOperationXLSX(o))
+	assert.False(t, IsRestOperationCSV(o))
+	assert.Equal(t, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", GetContentType(o))
+}
+
+func TestGenerateForWebInSeparatePackage(t *testing.T) {
+	defer os.RemoveAll("./testDatahttp")
+
+	s := []model.Struct{
+		{
+			DocLines:    []string{`// @RestService( path = "/api", package = "testDatahttp" )`},
+			PackageName: "testData",
+			Name:        "MyService",
+			Operations: []*model.Operation{
+				{
+					DocLines:      []string{`// @RestOperation( path = "/person", method = "POST" )`},
+					Name:          "CreatePerson",
+					RelatedStruct: &model.Field{TypeName: "MyService"},
+					InputArgs:     []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "person", TypeName: "Person"}},
+					OutputArgs:    []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+				},
+			},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Nil(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testDatahttp/httpMyService.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "package testDatahttp")
+	assert.Contains(t, string(data), `"github.com/MarcGrol/golangAnnotations/generator/rest/testData"`)
+	assert.Contains(t, string(data), "type MyService struct {\n\t*testData.MyService\n}")
+	assert.Contains(t, string(data), "var person testData.Person")
+	assert.Contains(t, string(data), "var result *testData.Person")
+	assert.Contains(t, string(data), "result, err = service.CreatePerson(c, person)")
+
+	_, err = os.Stat(generationUtil.Prefixed("./testDatahttp/httpMyServiceHelpers_test.go"))
+	assert.NoError(t, err)
+}
+
+func TestGenerateForWebInSeparatePackageUnexportedOperation(t *testing.T) {
+	s := []model.Struct{
+		{
+			DocLines:    []string{`// @RestService( path = "/api", package = "testDatahttp" )`},
+			PackageName: "testData",
+			Name:        "MyService",
+			Operations:  []*model.Operation{{DocLines: []string{`// @RestOperation( path = "/person", method = "GET" )`}, Name: "getPerson"}},
+		},
+	}
+
+	_, err := NewGenerator().Generate(model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.EqualError(t, err, "Operation MyService.getPerson must be exported to be called from package testDatahttp")
+}
+
+func TestPathParamWithoutArgument(t *testing.T) {
+	service := model.Struct{
+		Name: "MyService",
+		Operations: []*model.Operation{
+			{
+				Filename: "myService.go",
+				Line:     21,
+				DocLines: []string{`// @RestOperation( method = "GET", path = "/person/{id}" )`},
+				Name:     "getPerson",
+				InputArgs: []model.Field{
+					{Name: "uid", TypeName: "string"},
+				},
+			},
+		},
+	}
+	err := validatePathParams(service)
+	assert.EqualError(t, err, "myService.go:20: Path parameter {id} of operation MyService.getPerson has no matching argument")
+}
+
+func TestExtractImportsOfMaps(t *testing.T) {
+	service := model.Struct{
+		Name: "OrderService",
+		Operations: []*model.Operation{
+			{
+				Name: "getOrders",
+				InputArgs: []model.Field{
+					{Name: "c", TypeName: "context.Context", PackageName: "context"},
+				},
+				OutputArgs: []model.Field{
+					{
+						TypeName:     "map[ids.ID]*shop.Order",
+						PackageName:  "github.com/example/shop",
+						PackageNames: []string{"github.com/example/ids", "github.com/example/shop"},
+					},
+				},
+			},
+		},
+	}
+	assert.Equal(t, []string{"github.com/example/ids", "github.com/example/shop"}, ExtractImports(service))
+}
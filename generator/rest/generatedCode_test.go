@@ -0,0 +1,194 @@
+package rest
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"testing"
+)
+
+// stubPackages stand in for the packages that the generated code leaves to goimports
+var stubPackages = map[string]string{
+	"request": `package request
+
+import "generated/envelope"
+
+type Context interface {
+	GetSessionUID() string
+	GetEnvelopes() []*envelope.Envelope
+}
+`,
+	"envelope": `package envelope
+
+import "time"
+
+type Envelope struct {
+	UUID             string
+	IsRootEvent      bool
+	SequenceNumber   int64
+	SessionUID       string
+	Timestamp        time.Time
+	AggregateName    string
+	AggregateUID     string
+	EventTypeName    string
+	EventTypeVersion int
+	EventData        string
+}
+
+func (e Envelope) CreateRequestUID(id string) string {
+	return id
+}
+`,
+	"bus": `package bus
+
+import (
+	"context"
+
+	"generated/envelope"
+)
+
+type Bus struct{}
+
+func New() Bus {
+	return Bus{}
+}
+
+func (Bus) Publish(c context.Context, rc interface{}, envlp *envelope.Envelope) error {
+	return nil
+}
+`,
+	"errorh": `package errorh
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+func NewInternalErrorf(code int, format string, args ...interface{}) error {
+	return fmt.Errorf(format, args...)
+}
+
+func HandleHTTPError(c context.Context, rc interface{}, err error, w http.ResponseWriter, r *http.Request) {
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
+`,
+	"mytime": `package mytime
+
+import "time"
+
+func Now() time.Time {
+	return time.Now()
+}
+`,
+	"mux": `package mux
+
+import "net/http"
+
+func Vars(r *http.Request) map[string]string {
+	return map[string]string{}
+}
+`,
+	"mylog": `package mylog
+
+import (
+	"context"
+	"fmt"
+)
+
+// Errors are the messages that are logged as error
+var Errors []string
+
+type Logger struct{}
+
+func New() Logger {
+	return Logger{}
+}
+
+func (Logger) Debug(c context.Context, rc interface{}, format string, args ...interface{}) {}
+
+func (Logger) Warning(c context.Context, rc interface{}, format string, args ...interface{}) {}
+
+func (Logger) Error(c context.Context, rc interface{}, format string, args ...interface{}) {
+	Errors = append(Errors, fmt.Sprintf(format, args...))
+}
+`,
+}
+
+// runGeneratedCode compiles generated files of package testData, with the stub-packages, and runs the go-tests of
+// testCode against them
+func runGeneratedCode(t *testing.T, filenames []string, testCode string) {
+	if testing.Short() {
+		t.Skip("Compiling generated code is slow")
+	}
+	goTool, err := exec.LookPath(filepath.Join(runtime.GOROOT(), "bin", "go"))
+	if err != nil {
+		t.Skipf("No go-tool to compile generated code with: %s", err)
+	}
+
+	dir := t.TempDir()
+	writeGeneratedCode(t, filepath.Join(dir, "go.mod"), []byte("module generated\n\ngo 1.16\n"))
+	for name, src := range stubPackages {
+		writeGeneratedCode(t, filepath.Join(dir, name, name+".go"), []byte(src))
+	}
+	for _, filename := range filenames {
+		data, err := ioutil.ReadFile(filename)
+		if err != nil {
+			t.Fatalf("Error reading generated file %s: %s", filename, err)
+		}
+		writeGeneratedCode(t, filepath.Join(dir, "testData", filepath.Base(filename)), importStubPackages(t, filename, data))
+	}
+	writeGeneratedCode(t, filepath.Join(dir, "testData", "generated_test.go"), []byte(testCode))
+
+	cmd := exec.Command(goTool, "test", "-count=1", "./testData")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off", "GOPROXY=off")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("Error running generated code: %s\n%s", err, output)
+	}
+}
+
+// importStubPackages adds the imports of the stub-packages that a generated file uses, like goimports would
+func importStubPackages(t *testing.T, filename string, data []byte) []byte {
+	file, err := parser.ParseFile(token.NewFileSet(), filename, data, 0)
+	if err != nil {
+		t.Fatalf("Error parsing generated file %s: %s", filename, err)
+	}
+	used := map[string]bool{}
+	ast.Inspect(file, func(node ast.Node) bool {
+		if selector, ok := node.(*ast.SelectorExpr); ok {
+			if ident, ok := selector.X.(*ast.Ident); ok && stubPackages[ident.Name] != "" {
+				used[ident.Name] = true
+			}
+		}
+		return true
+	})
+	names := []string{}
+	for name := range used {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	imports := ""
+	for _, name := range names {
+		imports += "\t\"generated/" + name + "\"\n"
+	}
+	return bytes.Replace(data, []byte("import (\n"), []byte("import (\n"+imports), 1)
+}
+
+func writeGeneratedCode(t *testing.T, filename string, data []byte) {
+	err := os.MkdirAll(filepath.Dir(filename), 0777)
+	if err == nil {
+		err = ioutil.WriteFile(filename, data, 0666)
+	}
+	if err != nil {
+		t.Fatalf("Error writing %s: %s", filename, err)
+	}
+}
@@ -11,9 +11,20 @@ import (
 	"cloud.google.com/go/datastore"
 
 	"github.com/gorilla/mux"
+{{- if GetDomainImportPath}}
+
+	"{{GetDomainImportPath}}"
+{{- end}}
 )
 
 {{ $service := . }}
+{{- if GetDomainPackage}}
+
+// {{.Name}} adds http-handling to the business logic of {{GetDomainPackage}}.{{.Name}}
+type {{.Name}} struct {
+	*{{GetDomainPackage}}.{{.Name}}
+}
+{{- end}}
 
 // HTTPHandler registers endpoint in new router
 func (ts *{{.Name}}) HTTPHandler() http.Handler {
@@ -24,12 +35,25 @@ func (ts *{{.Name}}) HTTPHandler() http.Handler {
 // HTTPHandlerWithRouter registers endpoint in existing router
 func (ts *{{.Name}}) HTTPHandlerWithRouter(router *mux.Router) *mux.Router {
 	subRouter := router.PathPrefix("{{GetRestServicePath . }}").Subrouter()
+	{{with GetSuccessorPath -}}
+	subRouter.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// this version is superseded
+			w.Header().Set("Deprecation", "true")
+			w.Header().Set("Link", "<{{.}}>; rel=\"successor-version\"")
+			next.ServeHTTP(w, r)
+		})
+	})
+	{{end}}
 
 	{{range .Operations -}}
 		{{if IsRestOperation . -}}
 			subRouter.HandleFunc("{{GetRestOperationPath . }}", {{.Name}}(ts)).Methods("{{GetRestOperationMethod . }}")
 		{{end -}}
 	{{end -}}
+	{{if IsRestServiceAsync . -}}
+		subRouter.HandleFunc("/jobs/{jobID}", asyncJobOf{{.Name}}(ts)).Methods("GET")
+	{{end -}}
 
 	return router
 }
@@ -37,6 +61,25 @@ func (ts *{{.Name}}) HTTPHandlerWithRouter(router *mux.Router) *mux.Router {
 {{ $extractRequestContextMethod := GetExtractRequestContextMethod . }}
 {{ $requiresRoleValidation := DoesRestServiceRequireRoleValidation . }}
 
+{{if IsRestServiceAsync . -}}
+// asyncJobOf{{.Name}} answers the status of a job of an asynchronous operation of {{.Name}}
+func asyncJobOf{{.Name}}(service *{{.Name}}) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		c := ctx.New().CreateContext(r)
+		rc := {{ $extractRequestContextMethod }}(c, r)
+
+		{{if IsRestServiceTenantScoped . -}}
+		tenantID, err := tenantProvider.GetTenantID(c, rc, r, "{{GetRestServiceTenantHeader .}}")
+		if err != nil {
+			errorh.HandleHTTPError(c, rc, err, w, r)
+			return
+		}
+		{{end -}}
+		writeAsyncJobStatus(c, rc, w, r, "{{.Name}}", {{GetAsyncJobTenant .}})
+	}
+}
+{{end -}}
+
 {{range $idxOper, $oper := .Operations}}
 	{{if IsRestOperation $oper -}}
 		{{if IsRestOperationGenerated . -}}
@@ -49,9 +92,31 @@ func {{$oper.Name}}(service *{{$service.Name}}) http.HandlerFunc {
 		{{if NeedsContext $oper -}}
 			{{GetContextName $oper}} := ctx.New().CreateContext(r)
 		{{end -}}
+		{{if HasRestOperationTimeout $service $oper -}}
+			{{GetContextName $oper}}, cancel := context.WithTimeout({{GetContextName $oper}}, {{GetRestOperationTimeout $service $oper}})
+			defer cancel()
+		{{end -}}
 
 		rc := {{ $extractRequestContextMethod }}(c, r)
 
+		{{if IsRestServiceTenantScoped $service -}}
+		tenantID, err := tenantProvider.GetTenantID(c, rc, r, "{{GetRestServiceTenantHeader $service}}")
+		if err != nil {
+			errorh.HandleHTTPError(c, rc, err, w, r)
+			return
+		}
+		if tenantID == "" {
+			http.Error(w, "Missing tenant in header {{GetRestServiceTenantHeader $service}}", http.StatusBadRequest)
+			return
+		}
+		{{end -}}
+		{{if HasFeatureFlag $oper -}}
+		if !featureFlagProvider.IsEnabled(c, rc, "{{GetFeatureFlagName $oper}}") {
+			http.Error(w, "Feature {{GetFeatureFlagName $oper}} is not enabled", {{GetFeatureFlagStatus $oper}})
+			return
+		}
+		{{end -}}
+
 		{{if and ($requiresRoleValidation) (HasRequestContext $oper) -}}
 
 			err = validateRequestContext(c, rc, {{GetRestOperationRolesString $oper}})
@@ -62,6 +127,51 @@ func {{$oper.Name}}(service *{{$service.Name}}) http.HandlerFunc {
 
 		{{end -}}
 
+		{{if and (HasInput .) (not (HasUpload .)) -}}
+
+			{{with GetRestOperationConsumes $service . -}}
+			if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); {{range $idx, $mediaType := .}}{{if $idx}} && {{end}}mediaType != "{{$mediaType}}"{{end}} {
+				http.Error(w, "Unsupported Content-Type: expected {{range $idx, $mediaType := .}}{{if $idx}}, {{end}}{{$mediaType}}{{end}}", http.StatusUnsupportedMediaType)
+				return
+			}
+			{{end -}}
+
+			// limit the request body: a body without a Content-Length reaches the limit while it is read
+			if r.ContentLength > {{GetRestOperationMaxBodyKB $service .}}*1024 {
+				http.Error(w, "Request body exceeds {{GetRestOperationMaxBodyKB $service .}} KB", http.StatusRequestEntityTooLarge)
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, {{GetRestOperationMaxBodyKB $service .}}*1024)
+
+		{{end -}}
+		{{if IsRestOperationIdempotent . -}}
+			// retried calls with the same Idempotency-Key get the response of the first call
+			{{if IsIdempotencyKeyRequired . -}}
+			if r.Header.Get("Idempotency-Key") == "" {
+				http.Error(w, "Missing Idempotency-Key header for {{$oper.Name}}", http.StatusBadRequest)
+				return
+			}
+			{{end -}}
+			if idempotencyKey := r.Header.Get("Idempotency-Key"); idempotencyKey != "" {
+				recorder, answered, err := startIdempotentCall(c, rc, w, r, {{GetIdempotencyScope $service $oper}}, idempotencyKey)
+				if err != nil {
+					{{if and (HasInput .) (not (HasUpload .)) -}}
+					if isRequestBodyTooLarge(err) {
+						http.Error(w, "Request body exceeds {{GetRestOperationMaxBodyKB $service .}} KB", http.StatusRequestEntityTooLarge)
+						return
+					}
+					{{end -}}
+					errorh.HandleHTTPError(c, rc, err, w, r)
+					return
+				}
+				if answered {
+					return
+				}
+				defer recorder.finish(c, rc)
+				w = recorder
+			}
+
+		{{end -}}
 		{{if HasUpload . -}}
 
 			// Note: blobstore.ParseUpload must be called before parsing request POST-params
@@ -77,6 +187,10 @@ func {{$oper.Name}}(service *{{$service.Name}}) http.HandlerFunc {
 			var {{GetInputArgName . }} {{GetInputArgType . }}
 			err = json.NewDecoder(r.Body).Decode(&{{GetInputArgName . }})
 			if err != nil {
+				if isRequestBodyTooLarge(err) {
+					http.Error(w, "Request body exceeds {{GetRestOperationMaxBodyKB $service .}} KB", http.StatusRequestEntityTooLarge)
+					return
+				}
 				errorh.HandleHTTPError(c, rc, errorh.NewInvalidInputErrorf(1, "Error parsing request body: %s", err), w, r)
 				return
 			}
@@ -91,7 +205,7 @@ func {{$oper.Name}}(service *{{$service.Name}}) http.HandlerFunc {
 
 		{{range .InputArgs -}}
 
-			{{if not (IsCustomArg .) }}
+			{{if not (or (IsCustomArg .) (and (IsRestServiceTenantScoped $service) (IsTenantArg .))) }}
 				{{if IsIntArg . -}}
 					{{if IsInputArgMandatory $oper . -}}
 						{{.Name}}, fieldError := httpparser.ExtractNumber(r, "{{Uncapitalized .Name}}", true)
@@ -153,6 +267,74 @@ func {{$oper.Name}}(service *{{$service.Name}}) http.HandlerFunc {
 
 		{{end -}}
 
+		{{if HasETagPrecondition . -}}
+			// optimistic concurrency: only change the resource when it still has the version that the client has
+			{{if IsETagRequired . -}}
+			if r.Header.Get("If-Match") == "" {
+				http.Error(w, "Missing If-Match header for {{$oper.Name}}", http.StatusPreconditionRequired)
+				return
+			}
+			{{end -}}
+			if ifMatch := r.Header.Get("If-Match"); ifMatch != "" {
+				version, err := service.{{$oper.Name}}Version({{GetInputParamString . }})
+				if err != nil {
+					errorh.HandleHTTPError(c, rc, err, w, r)
+					return
+				}
+				if !etagMatches(ifMatch, version) {
+					http.Error(w, "Version of resource has changed", http.StatusPreconditionFailed)
+					return
+				}
+				// the operation changes the resource only when it still has this version
+				{{GetContextName $oper}} = withExpectedVersion({{GetContextName $oper}}, version)
+			}
+
+		{{end -}}
+		{{if IsRestOperationAuditedBefore . -}}
+			// take the audited fields before the business logic changes them
+			auditBefore, err := service.{{$oper.Name}}AuditBefore({{GetInputParamString . }})
+			if err != nil {
+				errorh.HandleHTTPError(c, rc, err, w, r)
+				return
+			}
+
+		{{end -}}
+		{{if IsRestOperationAsync . -}}
+		// long-running: the job runs after answering, clients poll its status at the location of the response
+		job, err := startAsyncJob(c, rc, "{{$service.Name}}.{{$oper.Name}}", {{GetAsyncJobTenant $service}})
+		if err != nil {
+			errorh.HandleHTTPError(c, rc, err, w, r)
+			return
+		}
+		go func({{GetContextName $oper}} context.Context) {
+			defer recoverAsyncJob({{GetContextName $oper}}, rc, job)
+			var err error
+			rc.Set(request.Transactional({{ IsRestOperationTransactional $service .}}))
+			{{range GetOutputArgsDeclaration . -}}
+				{{.}}
+			{{end -}}
+			{{if IsRestOperationTransactional $service . -}}
+			err = eventStore.RunInTransaction({{GetContextName $oper}}, rc, func(tx *datastore.Transaction) error {
+			{{end -}}
+			{{if HasOutput . -}}
+				result, err = service.{{$oper.Name}}({{GetInputParamString . }})
+			{{else -}}
+				err = service.{{$oper.Name}}({{GetInputParamString . }})
+			{{end -}}
+			{{if IsRestOperationTransactional $service . -}}
+				if err != nil {
+					mylog.New().Debug({{GetContextName $oper}}, rc, "Error calling {{$oper.Name}}: %s", err)
+					rc.Set(request.ClearEnvelopes())
+					return err
+				}
+				return nil
+			})
+			{{end -}}
+			completeAsyncJob({{GetContextName $oper}}, rc, job, {{if HasOutput .}}result{{else}}nil{{end}}, err)
+		}(detachedContext{ {{- GetContextName $oper}}})
+
+		writeAsyncJob(c, rc, w, "{{GetAsyncJobsPath $service}}", job)
+		{{else -}}
 		// call business logic
 		rc.Set(request.Transactional({{ IsRestOperationTransactional $service .}}))
 		{{range GetOutputArgsDeclaration . -}}
@@ -195,6 +377,18 @@ func {{$oper.Name}}(service *{{$service.Name}}) http.HandlerFunc {
 			}
 		{{end -}}
 		if err != nil {
+			{{if HasRestOperationTimeout $service $oper -}}
+			if errors.Is(err, context.DeadlineExceeded) {
+				http.Error(w, "Timeout of {{$oper.Name}} exceeded", http.StatusGatewayTimeout)
+				return
+			}
+			{{end -}}
+			{{if HasETagPrecondition . -}}
+			if errors.Is(err, ErrVersionChanged) {
+				http.Error(w, "Version of resource has changed", http.StatusPreconditionFailed)
+				return
+			}
+			{{end -}}
 			errorh.HandleHTTPError(c, rc, err, w, r)
 			return
 		}
@@ -209,6 +403,26 @@ func {{$oper.Name}}(service *{{$service.Name}}) http.HandlerFunc {
 			}
 		}
 
+		{{if IsRestOperationAudited . -}}
+			err = publishAuditEvent(c, rc, r, AuditEvent{
+				Service:   "{{$service.Name}}",
+				Operation: "{{$oper.Name}}",
+				Action:    "{{GetRestOperationAuditAction .}}",
+				{{with GetAuditedFields . -}}
+					{{if IsRestOperationAuditedBefore $oper -}}
+						Before:    auditSnapshot(auditBefore, {{.}}),
+					{{end -}}
+					{{with GetAuditedValue $oper -}}
+						After:     auditSnapshot({{.}}, {{GetAuditedFields $oper}}),
+					{{end -}}
+				{{end -}}
+			})
+			if err != nil {
+				errorh.HandleHTTPError(c, rc, err, w, r)
+				return
+			}
+
+		{{end -}}
 	   {{if HasRestOperationAfter . -}}
 			err = service.{{$oper.Name}}HandleAfter(c, rc, r.Method, r.URL, {{GetInputArgName . }}, result)
 			if err != nil {
@@ -217,6 +431,12 @@ func {{$oper.Name}}(service *{{$service.Name}}) http.HandlerFunc {
 			}
 		{{end -}}
 
+		{{if and (HasETag .) (HasOutput .) -}}
+			if versioned, ok := interface{}(result).(VersionedResource); ok {
+				w.Header().Set("ETag", formatETag(versioned.GetAggregateVersion()))
+			}
+
+		{{end -}}
 		// write OK response body
 		{{if HasContentType . -}}
 			w.Header().Set("Content-Type", "{{GetContentType .}}")
@@ -247,6 +467,9 @@ func {{$oper.Name}}(service *{{$service.Name}}) http.HandlerFunc {
 			{{else -}}
 				{{$oper.Name}}WriteCSV(w)
 			{{end -}}
+		{{else if IsRestOperationXLSX . -}}
+			w.Header().Set("Content-Disposition", "attachment;filename={{ GetRestOperationFilename .}}")
+			service.{{$oper.Name}}WriteXLSX(w, result)
 		{{else if IsRestOperationTXT . -}}
 			fmt.Fprint(w, result)
 		{{else if IsRestOperationMD . -}}
@@ -258,6 +481,7 @@ func {{$oper.Name}}(service *{{$service.Name}}) http.HandlerFunc {
 		{{else -}}
 			errorh.NewInternalErrorf(0, "Not implemented")
 		{{end -}}
+		{{end -}}
 	}
 }
 	{{else -}}
@@ -0,0 +1,155 @@
+package rest
+
+const idempotencyTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+
+package {{.PackageName}}
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// IdempotentResponse is the response of the first call with an Idempotency-Key, for the request with RequestHash. It
+// has no StatusCode yet while that call is in progress.
+type IdempotentResponse struct {
+	RequestHash string
+	StatusCode  int
+	Header      http.Header
+	Body        []byte
+	Created     time.Time
+}
+
+// IdempotencyStore keeps the responses of operations with @Idempotent by their Idempotency-Key. Begin atomically
+// claims an unknown key for a call of the request with requestHash, or returns the response that the key has:
+// concurrent calls with the same key cannot both claim it. Put stores the response of the call that claimed the key
+// and Release drops the claim of a failed call, so that it can be retried.
+type IdempotencyStore interface {
+	Begin(c context.Context, rc request.Context, key string, requestHash string) (existing *IdempotentResponse, claimed bool, err error)
+	Put(c context.Context, rc request.Context, key string, response IdempotentResponse) error
+	Release(c context.Context, rc request.Context, key string) error
+}
+
+// idempotencyTTL is how long the in-memory store keeps responses
+const idempotencyTTL = 24 * time.Hour
+
+type memoryIdempotencyStore struct {
+	sync.Mutex
+	responses map[string]IdempotentResponse
+}
+
+func (s *memoryIdempotencyStore) Begin(c context.Context, rc request.Context, key string, requestHash string) (*IdempotentResponse, bool, error) {
+	s.Lock()
+	defer s.Unlock()
+	response, found := s.responses[key]
+	if found && time.Since(response.Created) <= idempotencyTTL {
+		return &response, false, nil
+	}
+	s.responses[key] = IdempotentResponse{RequestHash: requestHash, Created: time.Now()}
+	return nil, true, nil
+}
+
+func (s *memoryIdempotencyStore) Put(c context.Context, rc request.Context, key string, response IdempotentResponse) error {
+	s.Lock()
+	defer s.Unlock()
+	s.responses[key] = response
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Release(c context.Context, rc request.Context, key string) error {
+	s.Lock()
+	defer s.Unlock()
+	delete(s.responses, key)
+	return nil
+}
+
+var idempotencyStore IdempotencyStore = &memoryIdempotencyStore{responses: map[string]IdempotentResponse{}}
+
+// SetIdempotencyStore replaces the in-memory store of the responses of idempotent operations, like with one that
+// all instances of the service share
+func SetIdempotencyStore(store IdempotencyStore) {
+	idempotencyStore = store
+}
+
+// startIdempotentCall answers with the response of an earlier call with the key, or returns the recorder of the
+// response of this call. A key that was used for another request is rejected, and so is a key of a call that is
+// still in progress.
+func startIdempotentCall(c context.Context, rc request.Context, w http.ResponseWriter, r *http.Request, scope string, key string) (*idempotencyRecorder, bool, error) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, false, err
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	hash := sha256.Sum256(append([]byte(r.Method+" "+r.URL.RequestURI()+"\n"), body...))
+	requestHash := hex.EncodeToString(hash[:])
+
+	storeKey := scope + ":" + key
+	stored, claimed, err := idempotencyStore.Begin(c, rc, storeKey, requestHash)
+	if err != nil {
+		return nil, false, err
+	}
+	if claimed {
+		return &idempotencyRecorder{ResponseWriter: w, key: storeKey, requestHash: requestHash, status: http.StatusOK}, false, nil
+	}
+	if stored.RequestHash != requestHash {
+		http.Error(w, "Idempotency-Key was used for another request", http.StatusUnprocessableEntity)
+		return nil, true, nil
+	}
+	if stored.StatusCode == 0 {
+		http.Error(w, "A call with this Idempotency-Key is in progress", http.StatusConflict)
+		return nil, true, nil
+	}
+	for name, values := range stored.Header {
+		w.Header()[name] = values
+	}
+	w.Header().Set("Idempotent-Replayed", "true")
+	w.WriteHeader(stored.StatusCode)
+	w.Write(stored.Body)
+	return nil, true, nil
+}
+
+// idempotencyRecorder keeps the response of a call with an Idempotency-Key while writing it
+type idempotencyRecorder struct {
+	http.ResponseWriter
+	key         string
+	requestHash string
+	status      int
+	body        bytes.Buffer
+}
+
+func (r *idempotencyRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *idempotencyRecorder) Write(data []byte) (int, error) {
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+// finish stores a successful response: after a failure the call can be retried
+func (r *idempotencyRecorder) finish(c context.Context, rc request.Context) {
+	if r.status < 200 || r.status >= 300 {
+		err := idempotencyStore.Release(c, rc, r.key)
+		if err != nil {
+			mylog.New().Warning(c, rc, "Error releasing Idempotency-Key %s: %s", r.key, err)
+		}
+		return
+	}
+	err := idempotencyStore.Put(c, rc, r.key, IdempotentResponse{
+		RequestHash: r.requestHash,
+		StatusCode:  r.status,
+		Header:      r.Header().Clone(),
+		Body:        r.body.Bytes(),
+		Created:     time.Now(),
+	})
+	if err != nil {
+		mylog.New().Warning(c, rc, "Error storing the response for Idempotency-Key %s: %s", r.key, err)
+	}
+}
+`
@@ -0,0 +1,36 @@
+package rest
+
+const requestBodyTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+
+//go:build go1.19
+// +build go1.19
+
+package {{.PackageName}}
+
+import (
+	"errors"
+	"net/http"
+)
+
+// isRequestBodyTooLarge tells whether reading a body that http.MaxBytesReader limits, failed because it exceeds the
+// limit
+func isRequestBodyTooLarge(err error) bool {
+	var maxBytesErr *http.MaxBytesError
+	return errors.As(err, &maxBytesErr)
+}
+`
+
+const requestBodyLegacyTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+
+//go:build !go1.19
+// +build !go1.19
+
+package {{.PackageName}}
+
+// isRequestBodyTooLarge tells whether reading a body that http.MaxBytesReader limits, failed because it exceeds the
+// limit. Before go 1.19 the error of http.MaxBytesReader has no type of its own, so only its message tells: go 1.19
+// keeps that message for http.MaxBytesError.
+func isRequestBodyTooLarge(err error) bool {
+	return err != nil && err.Error() == "http: request body too large"
+}
+`
@@ -5,6 +5,11 @@ import "github.com/MarcGrol/golangAnnotations/generator/annotation"
 const (
 	TypeRestOperation   = "RestOperation"
 	TypeRestService     = "RestService"
+	TypeFeatureFlag     = "FeatureFlag"
+	TypeAudited         = "Audited"
+	TypeETag            = "ETag"
+	TypeIdempotent      = "Idempotent"
+	TypeAsync           = "Async"
 	ParamCredentials    = "credentials"
 	ParamNoValidation   = "novalidation"
 	ParamProtected      = "protected"
@@ -20,19 +25,56 @@ const (
 	ParamOptional       = "optionalargs"
 	ParamRoles          = "roles"
 	ParamProducesEvents = "producesevents"
+	ParamName           = "name"
+	ParamWhenOff        = "whenoff"
+	ParamPackage        = "package"
+	ParamTimeout        = "timeout"
+	ParamMaxBodyKB      = "maxbodykb"
+	ParamConsumes       = "consumes"
+	ParamVersion        = "version"
+	ParamAction         = "action"
+	ParamBefore         = "before"
+	ParamRequired       = "required"
+	WhenOffNotFound     = "notfound"
+	WhenOffDisabled     = "disabled"
 )
 
 func Get() []annotation.AnnotationDescriptor {
 	return []annotation.AnnotationDescriptor{
 		{
 			Name:       TypeRestService,
-			ParamNames: []string{ParamCredentials, ParamNoValidation, ParamProtected, ParamNoTest, ParamPath},
+			ParamNames: []string{ParamCredentials, ParamNoValidation, ParamProtected, ParamNoTest, ParamPath, ParamPackage, ParamTimeout, ParamMaxBodyKB, ParamConsumes, ParamVersion},
 			Validator:  validateRestServiceAnnotation,
 		},
 		{
 			Name:       TypeRestOperation,
-			ParamNames: []string{ParamNoWrap, ParamAfter, ParamPath, ParamMethod, ParamTransactional, ParamForm, ParamFormat, ParamFilename, ParamOptional, ParamRoles, ParamProducesEvents},
+			ParamNames: []string{ParamNoWrap, ParamAfter, ParamPath, ParamMethod, ParamTransactional, ParamForm, ParamFormat, ParamFilename, ParamOptional, ParamRoles, ParamProducesEvents, ParamTimeout, ParamMaxBodyKB, ParamConsumes},
 			Validator:  validateRestOperationAnnotation,
+		},
+		{
+			Name:       TypeFeatureFlag,
+			ParamNames: []string{ParamName, ParamWhenOff},
+			Validator:  validateFeatureFlagAnnotation,
+		},
+		{
+			Name:       TypeAudited,
+			ParamNames: []string{ParamAction, ParamBefore},
+			Validator:  validateAuditedAnnotation,
+		},
+		{
+			Name:       TypeETag,
+			ParamNames: []string{ParamRequired},
+			Validator:  validateETagAnnotation,
+		},
+		{
+			Name:       TypeIdempotent,
+			ParamNames: []string{ParamRequired},
+			Validator:  validateIdempotentAnnotation,
+		},
+		{
+			Name:       TypeAsync,
+			ParamNames: []string{},
+			Validator:  validateAsyncAnnotation,
 		}}
 }
 
@@ -51,3 +93,43 @@ func validateRestServiceAnnotation(annot annotation.Annotation) bool {
 	}
 	return false
 }
+
+func validateFeatureFlagAnnotation(annot annotation.Annotation) bool {
+	if annot.Name == TypeFeatureFlag {
+		name, hasName := annot.Attributes[ParamName]
+		whenOff, hasWhenOff := annot.Attributes[ParamWhenOff]
+		return hasName && name != "" && (!hasWhenOff || whenOff == WhenOffNotFound || whenOff == WhenOffDisabled)
+	}
+	return false
+}
+
+func validateAuditedAnnotation(annot annotation.Annotation) bool {
+	if annot.Name == TypeAudited {
+		before, hasBefore := annot.Attributes[ParamBefore]
+		return !hasBefore || before == "true" || before == "false"
+	}
+	return false
+}
+
+func validateETagAnnotation(annot annotation.Annotation) bool {
+	if annot.Name == TypeETag {
+		required, hasRequired := annot.Attributes[ParamRequired]
+		return !hasRequired || required == "true" || required == "false"
+	}
+	return false
+}
+
+func validateIdempotentAnnotation(annot annotation.Annotation) bool {
+	if annot.Name == TypeIdempotent {
+		required, hasRequired := annot.Attributes[ParamRequired]
+		return !hasRequired || required == "true" || required == "false"
+	}
+	return false
+}
+
+func validateAsyncAnnotation(annot annotation.Annotation) bool {
+	if annot.Name == TypeAsync {
+		return true
+	}
+	return false
+}
@@ -88,3 +88,66 @@ func TestEmptyRestServiceAnnotation(t *testing.T) {
 
 	assert.NotEmpty(t, registry.ResolveAnnotations([]string{`// @RestService( Path = "")`}))
 }
+
+func TestCorrectFeatureFlagAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	ann, ok := registry.ResolveAnnotationByName([]string{`// @FeatureFlag( name = "new-checkout", whenOff = "disabled" )`}, "FeatureFlag")
+	assert.True(t, ok)
+	assert.Equal(t, "new-checkout", ann.Attributes["name"])
+	assert.Equal(t, "disabled", ann.Attributes["whenoff"])
+}
+
+func TestIncompleteFeatureFlagAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @FeatureFlag()`}))
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @FeatureFlag( name = "x", whenOff = "hidden" )`}))
+}
+
+func TestCorrectAuditedAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	ann, ok := registry.ResolveAnnotationByName([]string{`// @Audited( action = "person.update", before = "true" )`}, "Audited")
+	assert.True(t, ok)
+	assert.Equal(t, "person.update", ann.Attributes["action"])
+	assert.Equal(t, "true", ann.Attributes["before"])
+	assert.NotEmpty(t, registry.ResolveAnnotations([]string{`// @Audited()`}))
+}
+
+func TestCorrectETagAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	ann, ok := registry.ResolveAnnotationByName([]string{`// @ETag( required = "true" )`}, "ETag")
+	assert.True(t, ok)
+	assert.Equal(t, "true", ann.Attributes["required"])
+	assert.NotEmpty(t, registry.ResolveAnnotations([]string{`// @ETag()`}))
+}
+
+func TestInvalidETagAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @ETag( required = "yes" )`}))
+}
+
+func TestCorrectIdempotentAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	ann, ok := registry.ResolveAnnotationByName([]string{`// @Idempotent( required = "true" )`}, "Idempotent")
+	assert.True(t, ok)
+	assert.Equal(t, "true", ann.Attributes["required"])
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @Idempotent( required = "1" )`}))
+}
+
+func TestCorrectAsyncAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	_, ok := registry.ResolveAnnotationByName([]string{`// @Async()`}, "Async")
+	assert.True(t, ok)
+}
+
+func TestInvalidAuditedAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @Audited( before = "yes" )`}))
+}
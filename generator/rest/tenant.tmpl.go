@@ -0,0 +1,30 @@
+package rest
+
+const tenantTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+
+package {{.PackageName}}
+
+import (
+	"context"
+	"net/http"
+)
+
+// TenantProvider tells which tenant a call to a tenant-scoped rest-service is for: none when it is empty
+type TenantProvider interface {
+	GetTenantID(c context.Context, rc request.Context, r *http.Request, header string) (string, error)
+}
+
+type headerTenant struct{}
+
+func (headerTenant) GetTenantID(c context.Context, rc request.Context, r *http.Request, header string) (string, error) {
+	return r.Header.Get(header), nil
+}
+
+var tenantProvider TenantProvider = headerTenant{}
+
+// SetTenantProvider replaces the default provider that takes the tenant from the header of the service, for example
+// by one that takes it from the credentials of the caller
+func SetTenantProvider(provider TenantProvider) {
+	tenantProvider = provider
+}
+`
@@ -13,6 +13,10 @@ import (
 	"os"
 	"strings"
 	"testing"
+{{- if GetDomainImportPath}}
+
+	"{{GetDomainImportPath}}"
+{{- end}}
 )
 
 var (
@@ -35,6 +39,7 @@ func TestMain(m *testing.M) {
 	os.Exit(code)
 }
 
+{{ $service := . -}}
 {{ $serviceName := .Name -}}
 
 type testClient struct {
@@ -115,8 +120,8 @@ func (tcl *testClient) {{.Name}}(request {{.Name}}TestRequest) {{.Name}}TestResp
 
 	// compose http-request
 	var httpReq *http.Request
+	var requestPayload []byte
 	{
-		var requestPayload []byte
 		{{if HasUpload . -}}
 			{{.Name}}SetUpload(request.Body)
 			httpReq, err = http.NewRequest("{{GetRestOperationMethod . }}", request.URL, nil)
@@ -161,6 +166,16 @@ func (tcl *testClient) {{.Name}}(request {{.Name}}TestRequest) {{.Name}}TestResp
 
 		// record responsepart of testcase
 		tcl.testCase.WithResponse(httpResp.Code, httpResp.Header(), httpResp.Body.Bytes())
+		{{- if IsRestServiceRecorded $service}}
+
+		// record the response as fixture for the clients of the service
+		if os.Getenv("RECORD_FIXTURES") == "true" {
+			err = {{if GetDomainPackage}}{{GetDomainPackage}}.{{end}}Record{{$serviceName}}Fixture({{if GetDomainPackage}}{{GetDomainPackage}}.{{end}}{{$serviceName}}FixturesDir, httpReq, requestPayload, httpResp.Code, httpResp.Header(), httpResp.Body.Bytes())
+			if err != nil {
+				tcl.t.Fatalf("Error recording fixture: %s", err)
+			}
+		}
+		{{- end}}
 	}
 
 
@@ -190,6 +205,17 @@ func (tcl *testClient) {{.Name}}(request {{.Name}}TestRequest) {{.Name}}TestResp
 				       Body:       nil,
 			       }
 		        }
+				{{if IsRestOperationAsync . -}}
+
+				if httpResp.Code == http.StatusAccepted {
+					// the result is polled at the location of the job
+					return {{.Name}}TestResponse{
+						StatusCode: httpResp.Code,
+						HeaderMap:  httpResp.Result().Header,
+						GetCookie:  getCookie,
+					}
+				}
+				{{- end}}
 
 				if httpResp.Code != http.StatusOK {
 					// return type-strong error response
@@ -0,0 +1,354 @@
+package retrofit
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/generator/rest"
+	"github.com/MarcGrol/golangAnnotations/generator/retrofit/retrofitAnnotation"
+	"github.com/MarcGrol/golangAnnotations/generator/tenant"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+var pathParamPattern = regexp.MustCompile(`\{(\w+)\}`)
+
+// interfaceContext is the retrofit-interface of a rest-service
+type interfaceContext struct {
+	Package    string
+	Service    string
+	Interface  string
+	Imports    []string
+	Operations []clientOperation
+}
+
+type clientOperation struct {
+	Name        string
+	Description string
+	// Annotation is the retrofit-annotation with the method and path of the operation, like GET("api/person/{uid}")
+	Annotation string
+	Form       bool
+	Params     []clientParam
+	Result     string
+}
+
+type clientParam struct {
+	// Kind is the retrofit-annotation of the parameter, like Path or Query
+	Kind     string
+	Key      string
+	Name     string
+	Type     string
+	Optional bool
+}
+
+// modelsContext holds the classes and enums of the rest-services with the same language and package
+type modelsContext struct {
+	Package string
+	Classes []*jvmClass
+	Enums   []*jvmEnum
+}
+
+type classContext struct {
+	Package string
+	Imports []string
+	Class   *jvmClass
+}
+
+type enumContext struct {
+	Package string
+	Enum    *jvmEnum
+}
+
+// clientGroup holds the rest-services that share their models, as they have the same language and package
+type clientGroup struct {
+	language   string
+	pkg        string
+	types      *jvmTypes
+	interfaces []interfaceContext
+}
+
+type Generator struct {
+}
+
+func NewGenerator() generator.Generator {
+	return &Generator{}
+}
+
+func (eg *Generator) Name() string {
+	return "retrofit"
+}
+
+func (eg *Generator) GetTemplates() []generator.Template {
+	return []generator.Template{
+		{Name: "retrofit-java-interface", APIVersion: 1, Text: javaInterfaceTemplate},
+		{Name: "retrofit-java-class", APIVersion: 1, Text: javaClassTemplate},
+		{Name: "retrofit-java-enum", APIVersion: 1, Text: javaEnumTemplate},
+		{Name: "retrofit-kotlin-interface", APIVersion: 1, Text: kotlinInterfaceTemplate},
+		{Name: "retrofit-kotlin-models", APIVersion: 1, Text: kotlinModelsTemplate},
+	}
+}
+
+func (eg *Generator) GetAnnotations() []annotation.AnnotationDescriptor {
+	return retrofitAnnotation.Get()
+}
+
+func (eg *Generator) Generate(parsedSource model.ParsedSources, config generator.Config) ([]generator.OutputFile, error) {
+	return generate(config, parsedSource)
+}
+
+func generate(config generator.Config, parsedSources model.ParsedSources) ([]generator.OutputFile, error) {
+	packageName, err := generationUtil.GetPackageNameForStructs(parsedSources.Structs)
+	if packageName == "" || err != nil {
+		return nil, err
+	}
+	targetDir, err := generationUtil.DetermineTargetPath(config.InputDir, packageName)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := []*clientGroup{}
+	for _, service := range parsedSources.Structs {
+		ann, ok := annotation.NewRegistry(retrofitAnnotation.Get()).ResolveAnnotationByName(service.DocLines, retrofitAnnotation.TypeRetrofit)
+		if !ok {
+			continue
+		}
+		if !rest.IsRestService(service) {
+			return nil, generator.StructError(service, retrofitAnnotation.TypeRetrofit, "Struct %s with @Retrofit must be a @RestService", service.Name)
+		}
+		language := ann.Attributes[retrofitAnnotation.ParamLanguage]
+		if language == "" {
+			language = retrofitAnnotation.LanguageJava
+		}
+		group := findGroup(&groups, language, ann.Attributes[retrofitAnnotation.ParamPackage], parsedSources)
+		group.interfaces = append(group.interfaces, newInterface(service, group))
+	}
+
+	files := []generator.OutputFile{}
+	for _, group := range groups {
+		groupFiles, err := generateGroup(targetDir, packageName, group, config)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, groupFiles...)
+	}
+	return files, nil
+}
+
+func findGroup(groups *[]*clientGroup, language string, pkg string, parsedSources model.ParsedSources) *clientGroup {
+	for _, group := range *groups {
+		if group.language == language && group.pkg == pkg {
+			return group
+		}
+	}
+	types := newJVMTypes(java, parsedSources)
+	if language == retrofitAnnotation.LanguageKotlin {
+		types = newJVMTypes(kotlin, parsedSources)
+	}
+	group := &clientGroup{language: language, pkg: pkg, types: types}
+	*groups = append(*groups, group)
+	return group
+}
+
+// generateGroup writes the sources of a group below the input-dir in the directory-layout of their package, like
+// java/com/example/person: java requires the files of public classes to be named after the class, so they have no
+// prefix
+func generateGroup(targetDir string, packageName string, group *clientGroup, config generator.Config) ([]generator.OutputFile, error) {
+	dir := fmt.Sprintf("%s/%s/%s", targetDir, group.language, strings.Replace(group.pkg, ".", "/", -1))
+	files := []generator.OutputFile{}
+	render := func(src string, typeName string, filename string, templateName string, templateString string, data interface{}) error {
+		file, err := generationUtil.Generate(generationUtil.Info{
+			Src:            src,
+			TypeName:       typeName,
+			TargetFilename: fmt.Sprintf("%s/%s", dir, filename),
+			TemplateName:   templateName,
+			TemplateString: templateString,
+			Config:         config,
+			FuncMap:        customTemplateFuncs,
+			Data:           data,
+		})
+		if err != nil {
+			return fmt.Errorf("Error generating retrofit-client %s for package %s:%w", filename, packageName, err)
+		}
+		files = append(files, file)
+		return nil
+	}
+
+	if group.language == retrofitAnnotation.LanguageKotlin {
+		for _, api := range group.interfaces {
+			err := render(fmt.Sprintf("%s.%s", packageName, api.Service), api.Service, api.Interface+".kt", "retrofit-kotlin-interface", kotlinInterfaceTemplate, api)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if len(group.types.classes) > 0 || len(group.types.enums) > 0 {
+			data := modelsContext{Package: group.pkg, Classes: group.types.classes, Enums: group.types.enums}
+			err := render(fmt.Sprintf("%s.%s", packageName, "Models"), "", "Models.kt", "retrofit-kotlin-models", kotlinModelsTemplate, data)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return files, nil
+	}
+
+	for _, api := range group.interfaces {
+		err := render(fmt.Sprintf("%s.%s", packageName, api.Service), api.Service, api.Interface+".java", "retrofit-java-interface", javaInterfaceTemplate, api)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, class := range group.types.classes {
+		imports := []string{}
+		if len(class.Fields) > 0 {
+			imports = append(imports, "com.google.gson.annotations.SerializedName")
+		}
+		types := []string{}
+		for _, f := range class.Fields {
+			types = append(types, f.Type)
+		}
+		data := classContext{Package: group.pkg, Imports: append(imports, javaCollectionImports(types)...), Class: class}
+		err := render(fmt.Sprintf("%s.%s", packageName, class.Name), class.Name, class.Name+".java", "retrofit-java-class", javaClassTemplate, data)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, enum := range group.types.enums {
+		data := enumContext{Package: group.pkg, Enum: enum}
+		err := render(fmt.Sprintf("%s.%s", packageName, enum.Name), enum.Name, enum.Name+".java", "retrofit-java-enum", javaEnumTemplate, data)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// newInterface describes the rest-operations of a service as methods of a retrofit-interface. Operations with an
+// upload are left out: their form is read by the service itself.
+func newInterface(service model.Struct, group *clientGroup) interfaceContext {
+	api := interfaceContext{
+		Package:   group.pkg,
+		Service:   service.Name,
+		Interface: service.Name + "Api",
+	}
+	imports := map[string]bool{"retrofit2.Call": true}
+	types := []string{}
+	for _, o := range service.Operations {
+		if !rest.IsRestOperation(*o) || rest.HasUpload(*o) {
+			continue
+		}
+		op := newOperation(service, *o, group.types)
+		api.Operations = append(api.Operations, op)
+
+		imports["retrofit2.http."+strings.Split(op.Annotation, "(")[0]] = true
+		if op.Form {
+			imports["retrofit2.http.FormUrlEncoded"] = true
+		}
+		for _, param := range op.Params {
+			imports["retrofit2.http."+param.Kind] = true
+			types = append(types, param.Type)
+		}
+		if op.Result == responseBody {
+			imports["okhttp3.ResponseBody"] = true
+		}
+		types = append(types, op.Result)
+	}
+	if group.language == retrofitAnnotation.LanguageJava {
+		for _, name := range javaCollectionImports(types) {
+			imports[name] = true
+		}
+	}
+	for name := range imports {
+		api.Imports = append(api.Imports, name)
+	}
+	sort.Strings(api.Imports)
+	return api
+}
+
+// responseBody is the result of operations that do not answer with json
+const responseBody = "ResponseBody"
+
+func newOperation(service model.Struct, o model.Operation, types *jvmTypes) clientOperation {
+	op := clientOperation{
+		Name:        types.identifier(o.Name),
+		Description: generationUtil.Description(o.DocLines),
+	}
+	pathParams := map[string]bool{}
+	for _, name := range pathParamPattern.FindAllStringSubmatch(rest.GetRestOperationPath(o), -1) {
+		pathParams[name[1]] = true
+	}
+	hasBody := false
+	for _, arg := range o.InputArgs {
+		param := clientParam{Name: types.identifier(arg.Name), Type: types.of(arg.TypeName)}
+		switch {
+		case pathParams[arg.Name]:
+			param.Kind, param.Key = "Path", arg.Name
+		case rest.IsRestServiceTenantScoped(service) && tenant.IsTenantArg(arg):
+			param.Kind, param.Key = "Header", rest.GetRestServiceTenantHeader(service)
+		case rest.IsContextArg(arg) || rest.IsRequestContextArg(arg) || rest.IsUploadArg(arg):
+			continue
+		case rest.IsInputArg(arg):
+			param.Kind = "Body"
+			hasBody = true
+		case rest.IsRestOperationForm(o):
+			param.Kind, param.Key, param.Optional = "Field", rest.Uncapitalized(arg.Name), !rest.IsInputArgMandatory(o, arg)
+			op.Form = true
+		default:
+			param.Kind, param.Key, param.Optional = "Query", rest.Uncapitalized(arg.Name), !rest.IsInputArgMandatory(o, arg)
+		}
+		op.Params = append(op.Params, param)
+	}
+	if rest.IsRestOperationIdempotent(o) {
+		op.Params = append(op.Params, clientParam{Kind: "Header", Key: "Idempotency-Key", Name: "idempotencyKey", Type: types.of("string"), Optional: !rest.IsIdempotencyKeyRequired(o)})
+	}
+	if rest.HasETagPrecondition(o) {
+		op.Params = append(op.Params, clientParam{Kind: "Header", Key: "If-Match", Name: "ifMatch", Type: types.of("string"), Optional: !rest.IsETagRequired(o)})
+	}
+
+	method := rest.GetRestOperationMethod(o)
+	path := strings.TrimPrefix(rest.GetRestServicePath(service)+rest.GetRestOperationPath(o), "/")
+	if hasBody && method != "POST" && method != "PUT" && method != "PATCH" {
+		// retrofit only sends a body with these methods by default
+		op.Annotation = fmt.Sprintf("HTTP(method = %q, path = %q, hasBody = true)", method, path)
+	} else {
+		op.Annotation = fmt.Sprintf("%s(%q)", method, path)
+	}
+
+	switch {
+	case rest.IsRestOperationAsync(o):
+		op.Result = types.asyncJob()
+	case rest.IsRestOperationNoContent(o) || (rest.IsRestOperationJSON(o) && !rest.HasOutput(o)):
+		op.Result = types.language.void
+	case rest.IsRestOperationJSON(o):
+		op.Result = types.of(rest.GetOutputArgType(o))
+	default:
+		op.Result = responseBody
+	}
+	return op
+}
+
+// javaCollectionImports returns the imports of the collections that java-types use
+func javaCollectionImports(types []string) []string {
+	imports := []string{}
+	for _, collection := range []string{"List", "Map"} {
+		for _, t := range types {
+			if strings.Contains(t, collection+"<") {
+				imports = append(imports, "java.util."+collection)
+				break
+			}
+		}
+	}
+	return imports
+}
+
+var customTemplateFuncs = template.FuncMap{
+	"Comment": comment,
+}
+
+// comment returns text that cannot end or nest the block-comment that it is in
+func comment(text string) string {
+	return strings.Replace(strings.Replace(text, "*/", "* /", -1), "/*", "/ *", -1)
+}
@@ -0,0 +1,298 @@
+package retrofit
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func cleanup() {
+	os.RemoveAll("./testData/java")
+	os.RemoveAll("./testData/kotlin")
+}
+
+func TestGenerateJava(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @RestService( path = "/api" )`, `// @Retrofit( package = "com.example.person" )`},
+			Name:        "PersonService",
+			Operations: []*model.Operation{
+				{
+					DocLines:   []string{"// Returns a person", `// @RestOperation( method = "GET", path = "/person/{uid}", format = "JSON", optionalargs = "verbose" )`},
+					Name:       "getPerson",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}, {Name: "verbose", TypeName: "bool"}},
+					OutputArgs: []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+				},
+				{
+					DocLines:   []string{`// @RestOperation( method = "POST", path = "/person", format = "no_content" )`, `// @Idempotent()`},
+					Name:       "createPerson",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "person", TypeName: "Person"}},
+					OutputArgs: []model.Field{{TypeName: "error"}},
+				},
+				{
+					DocLines:   []string{`// @RestOperation( method = "DELETE", path = "/person/{uid}", format = "JSON" )`, `// @ETag( required = "true" )`},
+					Name:       "deletePerson",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}, {Name: "reason", TypeName: "Reason"}},
+					OutputArgs: []model.Field{{TypeName: "[]Person"}, {TypeName: "error"}},
+				},
+				{
+					DocLines:   []string{`// @RestOperation( method = "POST", path = "/person/{uid}/name", form = "true", format = "no_content" )`},
+					Name:       "rename",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}, {Name: "newName", TypeName: "string"}},
+					OutputArgs: []model.Field{{TypeName: "error"}},
+				},
+				{
+					DocLines:   []string{`// @RestOperation( method = "POST", path = "/report", format = "JSON" )`, `// @Async()`},
+					Name:       "createReport",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}},
+					OutputArgs: []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+				},
+				{
+					DocLines:   []string{`// @RestOperation( method = "GET", path = "/export", format = "CSV" )`},
+					Name:       "export",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}},
+					OutputArgs: []model.Field{{TypeName: "string"}, {TypeName: "error"}},
+				},
+				{
+					DocLines:   []string{`// @RestOperation( method = "POST", path = "/upload", format = "no_content" )`},
+					Name:       "upload",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "upload", TypeName: "Upload"}},
+					OutputArgs: []model.Field{{TypeName: "error"}},
+				},
+			},
+		},
+		{
+			PackageName: "testData",
+			Name:        "Person",
+		},
+		{
+			PackageName: "testData",
+			Name:        "Reason",
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile("./testData/java/com/example/person/PersonServiceApi.java")
+	assert.NoError(t, err)
+	api := string(data)
+	assert.Contains(t, api, "package com.example.person;")
+	assert.Contains(t, api, "import java.util.List;")
+	assert.Contains(t, api, "import okhttp3.ResponseBody;")
+	assert.Contains(t, api, "public interface PersonServiceApi {")
+	assert.Contains(t, api, "     * Returns a person\n     */\n    @GET(\"api/person/{uid}\")\n    Call<Person> getPerson(@Path(\"uid\") String uid, @Query(\"verbose\") Boolean verbose);")
+	assert.Contains(t, api, "    @POST(\"api/person\")\n    Call<Void> createPerson(@Body Person person, @Header(\"Idempotency-Key\") String idempotencyKey);")
+	assert.Contains(t, api, `    @HTTP(method = "DELETE", path = "api/person/{uid}", hasBody = true)`+"\n"+`    Call<List<Person>> deletePerson(@Path("uid") String uid, @Body Reason reason, @Header("If-Match") String ifMatch);`)
+	assert.Contains(t, api, "    @FormUrlEncoded\n    @POST(\"api/person/{uid}/name\")\n    Call<Void> rename(@Path(\"uid\") String uid, @Field(\"newName\") String newName);")
+	assert.Contains(t, api, "    Call<AsyncJob> createReport();")
+	assert.Contains(t, api, "    Call<ResponseBody> export();")
+	assert.NotContains(t, api, "upload")
+
+	data, err = ioutil.ReadFile("./testData/java/com/example/person/Reason.java")
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "public class Reason {\n}")
+
+	_, err = os.Stat("./testData/java/com/example/person/AsyncJob.java")
+	assert.NoError(t, err)
+}
+
+func TestGenerateJavaClasses(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @RestService( path = "/api" )`, `// @Retrofit( package = "com.example.person" )`},
+			Name:        "PersonService",
+			Operations: []*model.Operation{
+				{
+					DocLines:   []string{`// @RestOperation( method = "GET", path = "/person/{uid}", format = "JSON" )`},
+					Name:       "getPerson",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}},
+					OutputArgs: []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+				},
+			},
+		},
+		{
+			PackageName: "testData",
+			DocLines:    []string{"// Person is someone */ we know"},
+			Name:        "Person",
+			Fields: []model.Field{
+				{Name: "Age", TypeName: "int", Tag: "`json:\"age\"`"},
+				{Name: "Rank", TypeName: "int32", Tag: "`json:\"rank\"`"},
+				{Name: "Score", TypeName: "float64", Tag: "`json:\"score\"`"},
+				{Name: "Born", TypeName: "*time.Time", Tag: "`json:\"born\"`"},
+				{Name: "Parents", TypeName: "[]*Person", Tag: "`json:\"parents\"`"},
+				{Name: "Tags", TypeName: "map[string]int", Tag: "`json:\"tags\"`"},
+				{Name: "Color", TypeName: "ColorType", Tag: "`json:\"color,omitempty\"`"},
+				{Name: "Status", TypeName: "Status", Tag: "`json:\"status\"`"},
+				{Name: "Extra", TypeName: "interface{}", Tag: "`json:\"extra\"`"},
+				{Name: "Default", TypeName: "bool", Tag: "`json:\"default\"`"},
+				{Name: "secret", TypeName: "string"},
+				{Name: "Internal", TypeName: "string", Tag: "`json:\"-\"`"},
+			},
+		},
+	}
+	e := []model.Enum{
+		{
+			PackageName:  "testData",
+			DocLines:     []string{"// @JsonEnum( )"},
+			Name:         "ColorType",
+			EnumLiterals: []model.EnumLiteral{{Name: "ColorRed"}, {Name: "ColorGreen"}},
+		},
+		{
+			PackageName:  "testData",
+			Name:         "Status",
+			EnumLiterals: []model.EnumLiteral{{Name: "StatusActive"}},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s, Enums: e}, generator.Config{InputDir: "testData"})
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile("./testData/java/com/example/person/Person.java")
+	assert.NoError(t, err)
+	class := string(data)
+	assert.Contains(t, class, "import java.util.Map;")
+	assert.Contains(t, class, " * Person is someone * / we know\n */\npublic class Person {")
+	for field, javaType := range map[string]string{
+		"age":     "Long",
+		"rank":    "Integer",
+		"score":   "Double",
+		"born":    "String",
+		"parents": "List<Person>",
+		"tags":    "Map<String, Long>",
+		"color":   "ColorType",
+		"status":  "Long",
+		"extra":   "Object",
+	} {
+		assert.Contains(t, class, "    @SerializedName(\""+field+"\")\n    public "+javaType+" "+field+";")
+	}
+	// keywords of java get a trailing underscore
+	assert.Contains(t, class, "    @SerializedName(\"default\")\n    public Boolean default_;")
+	assert.NotContains(t, class, "secret")
+	assert.NotContains(t, class, "Internal")
+
+	data, err = ioutil.ReadFile("./testData/java/com/example/person/ColorType.java")
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "public enum ColorType {\n    @SerializedName(\"colorRed\")\n    COLOR_RED,")
+	_, err = os.Stat("./testData/java/com/example/person/Status.java")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestGenerateKotlin(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @RestService( path = "/api" )`, `// @Retrofit( package = "com.example.person", language = "kotlin" )`},
+			Name:        "PersonService",
+			Operations: []*model.Operation{
+				{
+					DocLines:   []string{`// @RestOperation( method = "GET", path = "/person/{uid}", format = "JSON", optionalargs = "verbose" )`},
+					Name:       "getPerson",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}, {Name: "verbose", TypeName: "bool"}},
+					OutputArgs: []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+				},
+				{
+					DocLines:   []string{`// @RestOperation( method = "POST", path = "/person", format = "no_content" )`, `// @Idempotent()`},
+					Name:       "createPerson",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "person", TypeName: "Person"}},
+					OutputArgs: []model.Field{{TypeName: "error"}},
+				},
+				{
+					DocLines:   []string{`// @RestOperation( method = "DELETE", path = "/person/{uid}", format = "JSON" )`, `// @ETag( required = "true" )`},
+					Name:       "deletePerson",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}, {Name: "reason", TypeName: "Reason"}},
+					OutputArgs: []model.Field{{TypeName: "[]Person"}, {TypeName: "error"}},
+				},
+				{
+					DocLines:   []string{`// @RestOperation( method = "POST", path = "/report", format = "JSON" )`, `// @Async()`},
+					Name:       "createReport",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}},
+					OutputArgs: []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+				},
+			},
+		},
+		{
+			PackageName: "testData",
+			Name:        "Person",
+			Fields: []model.Field{
+				{Name: "Name", TypeName: "string", Tag: "`json:\"name\"`"},
+				{Name: "Rank", TypeName: "int32", Tag: "`json:\"rank\"`"},
+				{Name: "Tags", TypeName: "map[string]int", Tag: "`json:\"tags\"`"},
+				{Name: "Color", TypeName: "ColorType", Tag: "`json:\"color\"`"},
+				{Name: "Extra", TypeName: "interface{}", Tag: "`json:\"extra\"`"},
+				{Name: "In", TypeName: "bool", Tag: "`json:\"in\"`"},
+				{Name: "Default", TypeName: "bool", Tag: "`json:\"default\"`"},
+			},
+		},
+		{
+			PackageName: "testData",
+			Name:        "Reason",
+		},
+	}
+	e := []model.Enum{
+		{
+			PackageName:  "testData",
+			DocLines:     []string{"// @JsonEnum( )"},
+			Name:         "ColorType",
+			EnumLiterals: []model.EnumLiteral{{Name: "ColorRed"}, {Name: "ColorGreen"}},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s, Enums: e}, generator.Config{InputDir: "testData"})
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile("./testData/kotlin/com/example/person/PersonServiceApi.kt")
+	assert.NoError(t, err)
+	api := string(data)
+	assert.Contains(t, api, "package com.example.person\n")
+	assert.NotContains(t, api, "java.util")
+	assert.Contains(t, api, "interface PersonServiceApi {")
+	assert.Contains(t, api, "    fun getPerson(@Path(\"uid\") uid: String, @Query(\"verbose\") verbose: Boolean?): Call<Person>")
+	assert.Contains(t, api, "    fun createPerson(@Body person: Person, @Header(\"Idempotency-Key\") idempotencyKey: String?): Call<Unit>")
+	assert.Contains(t, api, "    fun deletePerson(@Path(\"uid\") uid: String, @Body reason: Reason, @Header(\"If-Match\") ifMatch: String): Call<List<Person>>")
+
+	data, err = ioutil.ReadFile("./testData/kotlin/com/example/person/Models.kt")
+	assert.NoError(t, err)
+	models := string(data)
+	assert.Contains(t, models, "data class Person(\n    @SerializedName(\"name\") val name: String? = null,")
+	assert.Contains(t, models, "    @SerializedName(\"rank\") val rank: Int? = null,")
+	assert.Contains(t, models, "    @SerializedName(\"tags\") val tags: Map<String, Long>? = null,")
+	assert.Contains(t, models, "    @SerializedName(\"extra\") val extra: Any? = null,")
+	// keywords of kotlin are quoted, soft keywords like default are not
+	assert.Contains(t, models, "    @SerializedName(\"in\") val `in`: Boolean? = null,")
+	assert.Contains(t, models, "    @SerializedName(\"default\") val default: Boolean? = null")
+	assert.Contains(t, models, "\nclass Reason\n")
+	assert.Contains(t, models, "data class AsyncJob(")
+	assert.Contains(t, models, "enum class ColorType {\n    @SerializedName(\"colorRed\")\n    COLOR_RED,")
+}
+
+func TestGenerateForNoRestService(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @Retrofit( package = "com.example.person" )`},
+			Name:        "PersonService",
+		},
+	}
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Struct PersonService with @Retrofit must be a @RestService")
+}
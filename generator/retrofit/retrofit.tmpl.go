@@ -0,0 +1,119 @@
+package retrofit
+
+const javaInterfaceTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+
+package {{.Package}};
+{{range .Imports}}
+import {{.}};
+{{- end}}
+
+/**
+ * {{.Interface}} calls the rest-operations of {{.Service}}
+ */
+public interface {{.Interface}} {
+{{- range $idx, $op := .Operations}}
+{{if $idx}}
+{{end}}{{with .Description}}    /**
+     * {{Comment .}}
+     */
+{{end}}{{if .Form}}    @FormUrlEncoded
+{{end}}    @{{.Annotation}}
+    Call<{{.Result}}> {{.Name}}({{range $idx, $param := .Params}}{{if $idx}}, {{end}}@{{$param.Kind}}{{with $param.Key}}("{{.}}"){{end}} {{$param.Type}} {{$param.Name}}{{end}});
+{{- end}}
+}
+`
+
+const javaClassTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+
+package {{.Package}};
+{{- with .Imports}}
+{{range .}}
+import {{.}};
+{{- end}}
+{{- end}}
+{{- with .Class}}
+
+{{with .Description}}/**
+ * {{Comment .}}
+ */
+{{end}}public class {{.Name}} {
+{{- range .Fields}}
+    @SerializedName("{{.JSONName}}")
+    public {{.Type}} {{.Name}};
+{{- end}}
+}
+{{- end}}
+`
+
+const javaEnumTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+
+package {{.Package}};
+
+import com.google.gson.annotations.SerializedName;
+{{with .Enum}}
+{{with .Description}}/**
+ * {{Comment .}}
+ */
+{{end}}public enum {{.Name}} {
+{{- range .Literals}}
+    @SerializedName("{{.JSONName}}")
+    {{.Name}},
+{{- end}}
+}
+{{- end}}
+`
+
+const kotlinInterfaceTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+
+package {{.Package}}
+{{range .Imports}}
+import {{.}}
+{{- end}}
+
+/**
+ * {{.Interface}} calls the rest-operations of {{.Service}}
+ */
+interface {{.Interface}} {
+{{- range $idx, $op := .Operations}}
+{{if $idx}}
+{{end}}{{with .Description}}    /**
+     * {{Comment .}}
+     */
+{{end}}{{if .Form}}    @FormUrlEncoded
+{{end}}    @{{.Annotation}}
+    fun {{.Name}}({{range $idx, $param := .Params}}{{if $idx}}, {{end}}@{{$param.Kind}}{{with $param.Key}}("{{.}}"){{end}} {{$param.Name}}: {{$param.Type}}{{if $param.Optional}}?{{end}}{{end}}): Call<{{.Result}}>
+{{- end}}
+}
+`
+
+const kotlinModelsTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+
+package {{.Package}}
+
+import com.google.gson.annotations.SerializedName
+{{- range .Classes}}
+
+{{with .Description}}/**
+ * {{Comment .}}
+ */
+{{end}}{{if .Fields}}data class {{.Name}}(
+{{- range .Fields}}
+    @SerializedName("{{.JSONName}}") val {{.Name}}: {{.Type}}? = null,
+{{- end}}
+)
+{{- else}}class {{.Name}}
+{{- end}}
+{{- end}}
+{{- range .Enums}}
+
+{{with .Description}}/**
+ * {{Comment .}}
+ */
+{{end}}enum class {{.Name}} {
+{{- range .Literals}}
+    @SerializedName("{{.JSONName}}")
+    {{.Name}},
+{{- end}}
+}
+{{- end}}
+`
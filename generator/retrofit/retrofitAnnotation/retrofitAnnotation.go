@@ -0,0 +1,35 @@
+package retrofitAnnotation
+
+import (
+	"regexp"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+)
+
+const (
+	TypeRetrofit   = "Retrofit"
+	ParamPackage   = "package"
+	ParamLanguage  = "language"
+	LanguageJava   = "java"
+	LanguageKotlin = "kotlin"
+)
+
+var jvmPackagePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*(\.[a-z][a-z0-9_]*)*$`)
+
+func Get() []annotation.AnnotationDescriptor {
+	return []annotation.AnnotationDescriptor{
+		{
+			Name:       TypeRetrofit,
+			ParamNames: []string{ParamPackage, ParamLanguage},
+			Validator:  validateRetrofitAnnotation,
+		}}
+}
+
+func validateRetrofitAnnotation(annot annotation.Annotation) bool {
+	if annot.Name == TypeRetrofit {
+		language, hasLanguage := annot.Attributes[ParamLanguage]
+		return jvmPackagePattern.MatchString(annot.Attributes[ParamPackage]) &&
+			(!hasLanguage || language == LanguageJava || language == LanguageKotlin)
+	}
+	return false
+}
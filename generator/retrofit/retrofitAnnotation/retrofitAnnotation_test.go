@@ -0,0 +1,26 @@
+package retrofitAnnotation
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrectRetrofitAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	annotation, ok := registry.ResolveAnnotation(`// @Retrofit( package = "com.example.person", language = "kotlin" )`)
+	assert.True(t, ok)
+	assert.Equal(t, TypeRetrofit, annotation.Name)
+	assert.Equal(t, "com.example.person", annotation.Attributes[ParamPackage])
+	assert.Equal(t, LanguageKotlin, annotation.Attributes[ParamLanguage])
+}
+
+func TestInvalidRetrofitAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @Retrofit()`}))
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @Retrofit( package = "com.Example" )`}))
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @Retrofit( package = "com.example", language = "scala" )`}))
+}
@@ -0,0 +1,232 @@
+package retrofit
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/generator/jsonHelpers"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+// jvmClass is a struct of the parsed sources as class with the json-fields of the struct
+type jvmClass struct {
+	Name        string
+	Description string
+	Fields      []jvmField
+}
+
+type jvmField struct {
+	JSONName string
+	Name     string
+	Type     string
+}
+
+// jvmEnum is a @JsonEnum of the parsed sources, with its literals by their name in json
+type jvmEnum struct {
+	Name        string
+	Description string
+	Literals    []jvmLiteral
+}
+
+type jvmLiteral struct {
+	JSONName string
+	Name     string
+}
+
+type jvmLanguage struct {
+	primitives map[string]string
+	any        string
+	void       string
+	keywords   map[string]bool
+	// escape makes a keyword into an identifier
+	escape func(name string) string
+}
+
+var java = jvmLanguage{
+	primitives: map[string]string{
+		"string":        "String",
+		"bool":          "Boolean",
+		"int":           "Long",
+		"int8":          "Integer",
+		"int16":         "Integer",
+		"int32":         "Integer",
+		"int64":         "Long",
+		"uint":          "Long",
+		"uint8":         "Integer",
+		"uint16":        "Integer",
+		"uint32":        "Long",
+		"uint64":        "Long",
+		"float32":       "Float",
+		"float64":       "Double",
+		"[]byte":        "String",
+		"time.Time":     "String",
+		"mydate.MyDate": "String",
+	},
+	any:  "Object",
+	void: "Void",
+	keywords: toSet("abstract", "assert", "boolean", "break", "byte", "case", "catch", "char", "class", "const",
+		"continue", "default", "do", "double", "else", "enum", "extends", "false", "final", "finally", "float", "for",
+		"goto", "if", "implements", "import", "instanceof", "int", "interface", "long", "native", "new", "null",
+		"package", "private", "protected", "public", "return", "short", "static", "strictfp", "super", "switch",
+		"synchronized", "this", "throw", "throws", "transient", "true", "try", "void", "volatile", "while"),
+	escape: func(name string) string { return name + "_" },
+}
+
+var kotlin = jvmLanguage{
+	primitives: map[string]string{
+		"string":        "String",
+		"bool":          "Boolean",
+		"int":           "Long",
+		"int8":          "Int",
+		"int16":         "Int",
+		"int32":         "Int",
+		"int64":         "Long",
+		"uint":          "Long",
+		"uint8":         "Int",
+		"uint16":        "Int",
+		"uint32":        "Long",
+		"uint64":        "Long",
+		"float32":       "Float",
+		"float64":       "Double",
+		"[]byte":        "String",
+		"time.Time":     "String",
+		"mydate.MyDate": "String",
+	},
+	any:  "Any",
+	void: "Unit",
+	keywords: toSet("as", "break", "class", "continue", "do", "else", "false", "for", "fun", "if", "in", "interface",
+		"is", "null", "object", "package", "return", "super", "this", "throw", "true", "try", "typealias", "typeof",
+		"val", "var", "when", "while"),
+	escape: func(name string) string { return "`" + name + "`" },
+}
+
+func toSet(names ...string) map[string]bool {
+	set := map[string]bool{}
+	for _, name := range names {
+		set[name] = true
+	}
+	return set
+}
+
+// jvmTypes maps go-types onto the types of java or kotlin, the same way as they are marshalled to json, and
+// collects the classes and enums of the structs and enums that it meets
+type jvmTypes struct {
+	language      jvmLanguage
+	parsedSources model.ParsedSources
+	classes       []*jvmClass
+	enums         []*jvmEnum
+	known         map[string]bool
+}
+
+func newJVMTypes(language jvmLanguage, parsedSources model.ParsedSources) *jvmTypes {
+	return &jvmTypes{language: language, parsedSources: parsedSources, known: map[string]bool{}}
+}
+
+// of returns the jvm-type of a go-type. Times and dates are strings in the format of their json, types that are not
+// in the parsed sources are any value.
+func (t *jvmTypes) of(typeName string) string {
+	if primitive, found := t.language.primitives[typeName]; found {
+		return primitive
+	}
+	if strings.HasPrefix(typeName, "*") {
+		return t.of(typeName[1:])
+	}
+	if strings.HasPrefix(typeName, "[]") {
+		return fmt.Sprintf("List<%s>", t.of(typeName[2:]))
+	}
+	if field := (model.Field{TypeName: typeName}); field.IsMap() {
+		keyType, valueType := field.SplitMapTypeNames()
+		return fmt.Sprintf("Map<%s, %s>", t.of(keyType), t.of(valueType))
+	}
+	packageName, name := model.Field{TypeName: typeName}.SplitTypeName()
+	for _, e := range t.parsedSources.Enums {
+		if e.Name == name && (packageName == "" || e.PackageName == packageName) {
+			if !jsonHelpers.IsJSONEnum(e) {
+				// the literals of other enums are numbers
+				return t.language.primitives["int"]
+			}
+			if jsonHelpers.IsJSONEnumTolerant(e) {
+				// unknown names are accepted as well
+				return t.language.primitives["string"]
+			}
+			t.addEnum(e)
+			return e.Name
+		}
+	}
+	if s, found := generationUtil.FindStruct(t.parsedSources.Structs, typeName); found {
+		t.addClass(s)
+		return s.Name
+	}
+	for _, td := range t.parsedSources.Typedefs {
+		// structs and interfaces are typedefs as well, without a type
+		if td.Name == name && (packageName == "" || td.PackageName == packageName) && td.Type != "" {
+			return t.of(td.Type)
+		}
+	}
+	return t.language.any
+}
+
+func (t *jvmTypes) addEnum(e model.Enum) {
+	if t.known[e.Name] {
+		return
+	}
+	t.known[e.Name] = true
+	enum := &jvmEnum{Name: e.Name, Description: generationUtil.Description(e.DocLines)}
+	for idx, name := range jsonHelpers.GetJSONEnumNames(e) {
+		enum.Literals = append(enum.Literals, jvmLiteral{JSONName: name, Name: strings.ToUpper(generationUtil.SnakeCase(e.EnumLiterals[idx].Name))})
+	}
+	t.enums = append(t.enums, enum)
+}
+
+func (t *jvmTypes) addClass(s model.Struct) {
+	if t.known[s.Name] {
+		return
+	}
+	// registered before its fields, for structs that refer to themselves
+	t.known[s.Name] = true
+	class := &jvmClass{Name: s.Name, Description: generationUtil.Description(s.DocLines)}
+	t.classes = append(t.classes, class)
+	class.Fields = t.fieldsOf(s)
+}
+
+// asyncJob returns the class of the job that an @Async rest-operation answers with
+func (t *jvmTypes) asyncJob() string {
+	const name = "AsyncJob"
+	if !t.known[name] {
+		t.known[name] = true
+		str := t.language.primitives["string"]
+		t.classes = append(t.classes, &jvmClass{
+			Name:        name,
+			Description: "AsyncJob is the status of an asynchronous rest-operation, with its result once completed",
+			Fields: []jvmField{
+				{JSONName: "id", Name: "id", Type: str},
+				{JSONName: "operation", Name: "operation", Type: str},
+				{JSONName: "tenant", Name: "tenant", Type: str},
+				{JSONName: "status", Name: "status", Type: str},
+				{JSONName: "result", Name: "result", Type: t.language.any},
+				{JSONName: "error", Name: "error", Type: str},
+				{JSONName: "created", Name: "created", Type: str},
+				{JSONName: "completed", Name: "completed", Type: str},
+			},
+		})
+	}
+	return name
+}
+
+// fieldsOf returns the json-fields of a struct as the fields of its class
+func (t *jvmTypes) fieldsOf(s model.Struct) []jvmField {
+	fields := []jvmField{}
+	for _, f := range generationUtil.JSONFields(s, t.parsedSources.Structs) {
+		fields = append(fields, jvmField{JSONName: f.JSONName, Name: t.identifier(generationUtil.LowerCamelCase(f.Field.Name)), Type: t.of(f.Field.TypeName)})
+	}
+	return fields
+}
+
+// identifier returns a go-name that is a keyword in the language as identifier
+func (t *jvmTypes) identifier(name string) string {
+	if t.language.keywords[name] {
+		return t.language.escape(name)
+	}
+	return name
+}
@@ -0,0 +1,157 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// cronSchedule is a parsed cron-expression, with a bit for every minute, hour, day of the month, month and day of
+// the week that it fires at
+type cronSchedule struct {
+	Minutes  uint64
+	Hours    uint64
+	Days     uint64
+	Months   uint64
+	Weekdays uint64
+	// AnyDay and AnyWeekday tell that the day of the month or the day of the week is "*": cron fires at the days that
+	// match either of them when both are restricted
+	AnyDay     bool
+	AnyWeekday bool
+}
+
+type cronField struct {
+	name  string
+	min   int
+	max   int
+	names map[string]int
+}
+
+var (
+	minuteField  = cronField{name: "minute", min: 0, max: 59}
+	hourField    = cronField{name: "hour", min: 0, max: 23}
+	dayField     = cronField{name: "day of the month", min: 1, max: 31}
+	monthField   = cronField{name: "month", min: 1, max: 12, names: map[string]int{"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6, "JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12}}
+	weekdayField = cronField{name: "day of the week", min: 0, max: 7, names: map[string]int{"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6}}
+)
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// daysInMonth are the most days that a month can have, in leap-years
+var daysInMonth = [13]int{0, 31, 29, 31, 30, 31, 30, 31, 31, 30, 31, 30, 31}
+
+// parseCron parses a cron-expression with the fields minute, hour, day of the month, month and day of the week, or
+// one of the macros like @daily
+func parseCron(expression string) (cronSchedule, error) {
+	if macro, found := cronMacros[expression]; found {
+		expression = macro
+	}
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return cronSchedule{}, fmt.Errorf("cron-expression '%s' must have 5 fields: minute, hour, day of the month, month and day of the week", expression)
+	}
+	schedule := cronSchedule{AnyDay: fields[2] == "*", AnyWeekday: fields[4] == "*"}
+	var err error
+	for idx, target := range []struct {
+		field cronField
+		bits  *uint64
+	}{
+		{minuteField, &schedule.Minutes},
+		{hourField, &schedule.Hours},
+		{dayField, &schedule.Days},
+		{monthField, &schedule.Months},
+		{weekdayField, &schedule.Weekdays},
+	} {
+		*target.bits, err = target.field.parse(fields[idx])
+		if err != nil {
+			return cronSchedule{}, fmt.Errorf("cron-expression '%s' has an invalid %s: %v", expression, target.field.name, err)
+		}
+	}
+	// sunday is both 0 and 7
+	if schedule.Weekdays&(1<<7) != 0 {
+		schedule.Weekdays = schedule.Weekdays&^(1<<7) | 1
+	}
+	if !schedule.firesOnSomeDay() {
+		return cronSchedule{}, fmt.Errorf("cron-expression '%s' never fires: none of its months has its days", expression)
+	}
+	return schedule, nil
+}
+
+// firesOnSomeDay tells that a month of the schedule has one of its days of the month, like the 30th day in february
+// has not. A restricted day of the week fires at every month.
+func (s cronSchedule) firesOnSomeDay() bool {
+	if !s.AnyWeekday || s.AnyDay {
+		return true
+	}
+	for month := 1; month <= 12; month++ {
+		if s.Months&(1<<uint(month)) != 0 && s.Days&((1<<uint(daysInMonth[month]+1))-1) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// parse returns the bits of the values of a field, given as comma-separated list of values, ranges like 1-5 and
+// steps like */15 or 1-5/2
+func (f cronField) parse(field string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if idx := strings.Index(part, "/"); idx >= 0 {
+			var err error
+			step, err = strconv.Atoi(part[idx+1:])
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("step '%s' is no positive number", part[idx+1:])
+			}
+			part = part[:idx]
+		}
+		first, last := f.min, f.max
+		if part != "*" {
+			var err error
+			bounds := strings.SplitN(part, "-", 2)
+			first, err = f.value(bounds[0])
+			if err != nil {
+				return 0, err
+			}
+			last = first
+			if len(bounds) == 2 {
+				last, err = f.value(bounds[1])
+				if err != nil {
+					return 0, err
+				}
+			} else if step > 1 {
+				// 5/15 is 5-59/15
+				last = f.max
+			}
+			if first > last {
+				return 0, fmt.Errorf("range '%s' ends before it starts", part)
+			}
+		}
+		for value := first; value <= last; value += step {
+			bits |= 1 << uint(value)
+		}
+	}
+	return bits, nil
+}
+
+func (f cronField) value(text string) (int, error) {
+	if value, found := f.names[strings.ToUpper(text)]; found {
+		return value, nil
+	}
+	value, err := strconv.Atoi(text)
+	if err != nil {
+		return 0, fmt.Errorf("'%s' is no number", text)
+	}
+	if value < f.min || value > f.max {
+		return 0, fmt.Errorf("%d is not between %d and %d", value, f.min, f.max)
+	}
+	return value, nil
+}
@@ -0,0 +1,57 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCron(t *testing.T) {
+	schedule, err := parseCron("*/15 8-18 * * MON-FRI")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1<<0|1<<15|1<<30|1<<45), schedule.Minutes)
+	assert.Equal(t, uint64(0x7ff00), schedule.Hours)
+	assert.Equal(t, uint64(0xfffffffe), schedule.Days)
+	assert.Equal(t, uint64(0x1ffe), schedule.Months)
+	assert.Equal(t, uint64(0x3e), schedule.Weekdays)
+	assert.True(t, schedule.AnyDay)
+	assert.False(t, schedule.AnyWeekday)
+
+	schedule, err = parseCron("5/20 0 1,15 jan-mar/2 7")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1<<5|1<<25|1<<45), schedule.Minutes)
+	assert.Equal(t, uint64(1), schedule.Hours)
+	assert.Equal(t, uint64(1<<1|1<<15), schedule.Days)
+	assert.Equal(t, uint64(1<<1|1<<3), schedule.Months)
+	assert.Equal(t, uint64(1), schedule.Weekdays)
+	assert.False(t, schedule.AnyDay)
+	assert.False(t, schedule.AnyWeekday)
+
+	daily, err := parseCron("@daily")
+	assert.NoError(t, err)
+	midnight, err := parseCron("0 0 * * *")
+	assert.NoError(t, err)
+	assert.Equal(t, midnight, daily)
+
+	_, err = parseCron("0 0 29 2 *")
+	assert.NoError(t, err)
+}
+
+func TestParseInvalidCron(t *testing.T) {
+	for expression, message := range map[string]string{
+		"* * * *":       "must have 5 fields",
+		"60 * * * *":    "invalid minute: 60 is not between 0 and 59",
+		"* 18-8 * * *":  "invalid hour: range '18-8' ends before it starts",
+		"*/0 * * * *":   "invalid minute: step '0' is no positive number",
+		"* * 0 * *":     "invalid day of the month: 0 is not between 1 and 31",
+		"* * * FOO *":   "invalid month: 'FOO' is no number",
+		"0 0 30 2 *":    "never fires",
+		"@fortnightly":  "must have 5 fields",
+		"* * * * 1-8/2": "invalid day of the week: 8 is not between 0 and 7",
+	} {
+		_, err := parseCron(expression)
+		if assert.Error(t, err, expression) {
+			assert.Contains(t, err.Error(), message, expression)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"text/template"
+	"time"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/generator/scheduler/schedulerAnnotation"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+// defaultTimezone is the timezone of the cron-expressions without timezone: the one of the machine
+const defaultTimezone = "Local"
+
+type schedulerContext struct {
+	PackageName string
+	// Receivers are the structs with scheduled methods, that the scheduler needs an instance of
+	Receivers []receiver
+	Tasks     []task
+}
+
+type receiver struct {
+	Name     string
+	TypeName string
+}
+
+// task is an operation with @Scheduled
+type task struct {
+	Name     string
+	Cron     string
+	Timezone string
+	Timeout  string
+	Schedule cronSchedule
+	// Run is the go-expression of the operation, like orderService.ExpireOrders
+	Run string
+}
+
+type Generator struct {
+}
+
+func NewGenerator() generator.Generator {
+	return &Generator{}
+}
+
+func (eg *Generator) Name() string {
+	return "scheduler"
+}
+
+func (eg *Generator) GetTemplates() []generator.Template {
+	return []generator.Template{
+		{Name: "scheduler", APIVersion: 1, Text: schedulerTemplate},
+	}
+}
+
+func (eg *Generator) GetAnnotations() []annotation.AnnotationDescriptor {
+	return schedulerAnnotation.Get()
+}
+
+func (eg *Generator) Generate(parsedSource model.ParsedSources, config generator.Config) ([]generator.OutputFile, error) {
+	return generate(config, parsedSource.Structs, parsedSource.Operations)
+}
+
+func generate(config generator.Config, structs []model.Struct, operations []model.Operation) ([]generator.OutputFile, error) {
+	packageName, err := generationUtil.GetPackageNameForStructs(structs)
+	if err != nil {
+		return nil, err
+	}
+	if packageName == "" && len(operations) > 0 {
+		// a package can have scheduled functions only
+		packageName = operations[0].PackageName
+	}
+	if packageName == "" {
+		return nil, nil
+	}
+	targetDir, err := generationUtil.DetermineTargetPath(config.InputDir, packageName)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := schedulerContext{PackageName: packageName}
+	for _, s := range structs {
+		hasTasks := false
+		for _, o := range s.Operations {
+			t, ok, err := newTask(*o, s.Name)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				t.Run = fmt.Sprintf("%s.%s", generationUtil.LowerCamelCase(s.Name), o.Name)
+				ctx.Tasks = append(ctx.Tasks, t)
+				hasTasks = true
+			}
+		}
+		if hasTasks {
+			ctx.Receivers = append(ctx.Receivers, receiver{Name: generationUtil.LowerCamelCase(s.Name), TypeName: s.Name})
+		}
+	}
+	for _, o := range operations {
+		// methods are handled with their struct
+		if o.RelatedStruct != nil {
+			continue
+		}
+		t, ok, err := newTask(o, "")
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			t.Run = o.Name
+			ctx.Tasks = append(ctx.Tasks, t)
+		}
+	}
+	if len(ctx.Tasks) == 0 {
+		return nil, nil
+	}
+
+	file, err := generationUtil.Generate(generationUtil.Info{
+		Src:            packageName,
+		TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/scheduler.go", targetDir)),
+		TemplateName:   "scheduler",
+		TemplateString: schedulerTemplate,
+		Config:         config,
+		FuncMap:        customTemplateFuncs,
+		Data:           ctx,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Error generating scheduler for package %s:%w", packageName, err)
+	}
+	return []generator.OutputFile{file}, nil
+}
+
+var customTemplateFuncs = template.FuncMap{
+	"Quote": strconv.Quote,
+}
+
+// newTask returns the task of an operation with @Scheduled, which must have the signature
+// func(c context.Context) error. Owner is the struct of a method and empty for functions.
+func newTask(o model.Operation, owner string) (task, bool, error) {
+	ann, ok := annotation.NewRegistry(schedulerAnnotation.Get()).ResolveAnnotationByName(o.DocLines, schedulerAnnotation.TypeScheduled)
+	if !ok {
+		return task{}, false, nil
+	}
+	name := o.Name
+	if owner != "" {
+		name = owner + "." + o.Name
+	}
+	if len(o.InputArgs) != 1 || o.InputArgs[0].TypeName != "context.Context" || len(o.OutputArgs) != 1 || o.OutputArgs[0].TypeName != "error" {
+		return task{}, false, generator.OperationError(o, owner, schedulerAnnotation.TypeScheduled, "Scheduled operation %s must have the signature func(c context.Context) error", name)
+	}
+	t := task{
+		Name:     name,
+		Cron:     ann.Attributes[schedulerAnnotation.ParamCron],
+		Timezone: ann.Attributes[schedulerAnnotation.ParamTimezone],
+	}
+	schedule, err := parseCron(t.Cron)
+	if err != nil {
+		return task{}, false, generator.OperationError(o, owner, schedulerAnnotation.TypeScheduled, "Scheduled operation %s: %v", name, err)
+	}
+	t.Schedule = schedule
+	if t.Timezone == "" {
+		t.Timezone = defaultTimezone
+	}
+	if _, err := time.LoadLocation(t.Timezone); err != nil {
+		return task{}, false, generator.OperationError(o, owner, schedulerAnnotation.TypeScheduled, "Scheduled operation %s has unknown timezone '%s'", name, t.Timezone)
+	}
+	if timeout, found := ann.Attributes[schedulerAnnotation.ParamTimeout]; found {
+		// the annotation is only valid with a positive duration
+		d, _ := time.ParseDuration(timeout)
+		t.Timeout = generationUtil.DurationLiteral(d)
+	}
+	return t, true, nil
+}
@@ -0,0 +1,158 @@
+package scheduler
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func cleanup() {
+	os.Remove(generationUtil.Prefixed("./testData/scheduler.go"))
+	os.Remove("./testData")
+}
+
+func TestGenerateScheduler(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			Name:        "OrderService",
+			Operations: []*model.Operation{
+				{
+					DocLines:   []string{`// @Scheduled( cron = "*/15 8-18 * * MON-FRI", timezone = "UTC", timeout = "90s" )`},
+					Name:       "ExpireOrders",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}},
+					OutputArgs: []model.Field{{TypeName: "error"}},
+				},
+				{
+					Name:       "GetOrder",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}},
+					OutputArgs: []model.Field{{TypeName: "*Order"}, {TypeName: "error"}},
+				},
+			},
+		},
+		{
+			PackageName: "testData",
+			Name:        "Order",
+		},
+	}
+	o := []model.Operation{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @Scheduled( cron = "@daily" )`},
+			Name:        "purgeSessions",
+			InputArgs:   []model.Field{{Name: "c", TypeName: "context.Context"}},
+			OutputArgs:  []model.Field{{TypeName: "error"}},
+		},
+		{
+			PackageName:   "testData",
+			DocLines:      []string{`// @Scheduled( cron = "*/15 8-18 * * MON-FRI", timezone = "UTC", timeout = "90s" )`},
+			RelatedStruct: &model.Field{TypeName: "*OrderService"},
+			Name:          "ExpireOrders",
+			InputArgs:     []model.Field{{Name: "c", TypeName: "context.Context"}},
+			OutputArgs:    []model.Field{{TypeName: "error"}},
+		},
+	}
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s, Operations: o}, generator.Config{InputDir: "testData"})
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/scheduler.go"))
+	assert.NoError(t, err)
+	code := string(data)
+	assert.Contains(t, code, "func NewScheduler(orderService *OrderService, leader SchedulerLeader, metrics SchedulerMetrics) *Scheduler {\n")
+	assert.Contains(t, code, `			{
+				name:     "OrderService.ExpireOrders",
+				timezone: "UTC",
+				timeout:  90 * time.Second,
+				schedule: cronSchedule{
+					minutes:    0x200040008001,
+					hours:      0x7ff00,
+					days:       0xfffffffe,
+					months:     0x1ffe,
+					weekdays:   0x3e,
+					anyDay:     true,
+					anyWeekday: false,
+				},
+				run: orderService.ExpireOrders,
+			},`)
+	assert.Contains(t, code, `			{
+				name:     "purgeSessions",
+				timezone: "Local",
+				schedule: cronSchedule{
+					minutes:    0x1,
+					hours:      0x1,`)
+	assert.Contains(t, code, "\t\t\t\trun: purgeSessions,\n")
+	assert.Equal(t, 2, strings.Count(code, "\t\t\t\trun: "))
+	assert.NotContains(t, code, "GetOrder")
+}
+
+func TestGenerateSchedulerWithInvalidOperations(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	for message, operation := range map[string]model.Operation{
+		"Scheduled operation OrderService.ExpireOrders: cron-expression '0 25 * * *' has an invalid hour: 25 is not between 0 and 23": {
+			DocLines:   []string{`// @Scheduled( cron = "0 25 * * *" )`},
+			Name:       "ExpireOrders",
+			InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}},
+			OutputArgs: []model.Field{{TypeName: "error"}},
+		},
+		"Scheduled operation OrderService.ExpireOrders has unknown timezone 'Mars/Olympus'": {
+			DocLines:   []string{`// @Scheduled( cron = "@daily", timezone = "Mars/Olympus" )`},
+			Name:       "ExpireOrders",
+			InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}},
+			OutputArgs: []model.Field{{TypeName: "error"}},
+		},
+		"Scheduled operation OrderService.GetOrder must have the signature func(c context.Context) error": {
+			DocLines:   []string{`// @Scheduled( cron = "@hourly" )`},
+			Name:       "GetOrder",
+			InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}},
+			OutputArgs: []model.Field{{TypeName: "*Order"}, {TypeName: "error"}},
+		},
+	} {
+		operation := operation
+		s := []model.Struct{
+			{
+				PackageName: "testData",
+				Name:        "OrderService",
+				Operations:  []*model.Operation{&operation},
+			},
+		}
+		err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+		if assert.Error(t, err, message) {
+			assert.Contains(t, err.Error(), message)
+		}
+	}
+}
+
+func TestGenerateForNoScheduledOperations(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			Name:        "OrderService",
+			Operations: []*model.Operation{
+				{
+					Name:       "ExpireOrders",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}},
+					OutputArgs: []model.Field{{TypeName: "error"}},
+				},
+			},
+		},
+	}
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.NoError(t, err)
+
+	_, err = os.Stat(generationUtil.Prefixed("./testData/scheduler.go"))
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,178 @@
+package scheduler
+
+const schedulerTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+
+package {{.PackageName}}
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// SchedulerLeader elects the instance that runs the scheduled tasks, when the application runs as several instances
+type SchedulerLeader interface {
+	// IsLeader tells that this instance runs the task that is due: the other instances skip it
+	IsLeader(c context.Context, task string) bool
+}
+
+// SchedulerMetrics receives the outcomes of the scheduled tasks, for example to count them and measure their duration
+type SchedulerMetrics interface {
+	// Finished receives how long a run of a task took, with the error that it returned or panicked with
+	Finished(task string, duration time.Duration, err error)
+	// Skipped receives the runs of a task that another instance is the leader of
+	Skipped(task string)
+}
+
+// Scheduler runs the operations with @Scheduled of package {{.PackageName}} on their cron-schedule
+type Scheduler struct {
+	leader  SchedulerLeader
+	metrics SchedulerMetrics
+	tasks   []scheduledTask
+}
+
+type scheduledTask struct {
+	name     string
+	timezone string
+	timeout  time.Duration
+	schedule cronSchedule
+	run      func(c context.Context) error
+}
+
+// NewScheduler returns the scheduler of the tasks of package {{.PackageName}}. Leader and metrics are optional:
+// without leader, this instance runs every task.
+func NewScheduler({{range .Receivers}}{{.Name}} *{{.TypeName}}, {{end}}leader SchedulerLeader, metrics SchedulerMetrics) *Scheduler {
+	return &Scheduler{
+		leader:  leader,
+		metrics: metrics,
+		tasks: []scheduledTask{
+{{- range .Tasks}}
+			{
+				name:     {{Quote .Name}},
+				timezone: {{Quote .Timezone}},
+{{- if .Timeout}}
+				timeout:  {{.Timeout}},
+{{- end}}
+				schedule: cronSchedule{
+					minutes:    {{printf "%#x" .Schedule.Minutes}},
+					hours:      {{printf "%#x" .Schedule.Hours}},
+					days:       {{printf "%#x" .Schedule.Days}},
+					months:     {{printf "%#x" .Schedule.Months}},
+					weekdays:   {{printf "%#x" .Schedule.Weekdays}},
+					anyDay:     {{.Schedule.AnyDay}},
+					anyWeekday: {{.Schedule.AnyWeekday}},
+				},
+				run: {{.Run}},
+			},
+{{- end}}
+		},
+	}
+}
+
+// Run runs the tasks on their schedules until c is done. A task runs once at a time: a run that takes longer than
+// the interval of its schedule skips the runs that were due meanwhile.
+func (s *Scheduler) Run(c context.Context) error {
+	locations := make([]*time.Location, len(s.tasks))
+	for idx, task := range s.tasks {
+		location, err := time.LoadLocation(task.timezone)
+		if err != nil {
+			return fmt.Errorf("Error loading timezone %s of task %s: %w", task.timezone, task.name, err)
+		}
+		locations[idx] = location
+	}
+
+	var wg sync.WaitGroup
+	for idx := range s.tasks {
+		wg.Add(1)
+		go func(task scheduledTask, location *time.Location) {
+			defer wg.Done()
+			s.schedule(c, task, location)
+		}(s.tasks[idx], locations[idx])
+	}
+	wg.Wait()
+	return c.Err()
+}
+
+func (s *Scheduler) schedule(c context.Context, task scheduledTask, location *time.Location) {
+	for {
+		now := time.Now().In(location)
+		timer := time.NewTimer(task.schedule.next(now).Sub(now))
+		select {
+		case <-c.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if s.leader != nil && !s.leader.IsLeader(c, task.name) {
+			if s.metrics != nil {
+				s.metrics.Skipped(task.name)
+			}
+			continue
+		}
+		start := time.Now()
+		err := s.invoke(c, task)
+		if s.metrics != nil {
+			s.metrics.Finished(task.name, time.Since(start), err)
+		}
+	}
+}
+
+// invoke runs a task once, within its timeout
+func (s *Scheduler) invoke(c context.Context, task scheduledTask) (err error) {
+	if task.timeout > 0 {
+		var cancel context.CancelFunc
+		c, cancel = context.WithTimeout(c, task.timeout)
+		defer cancel()
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("Task %s panicked: %v", task.name, r)
+		}
+	}()
+	return task.run(c)
+}
+
+// cronSchedule is a parsed cron-expression, with a bit for every minute, hour, day of the month, month and day of
+// the week that it fires at
+type cronSchedule struct {
+	minutes    uint64
+	hours      uint64
+	days       uint64
+	months     uint64
+	weekdays   uint64
+	anyDay     bool
+	anyWeekday bool
+}
+
+// next returns the first minute after t that the schedule fires at
+func (s cronSchedule) next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	for {
+		switch {
+		case s.months&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.firesOn(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case s.hours&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case s.minutes&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+}
+
+// firesOn tells that the schedule fires on the day of t: cron fires at the days that match either the day of the
+// month or the day of the week when both are restricted
+func (s cronSchedule) firesOn(t time.Time) bool {
+	day := s.days&(1<<uint(t.Day())) != 0
+	weekday := s.weekdays&(1<<uint(t.Weekday())) != 0
+	if s.anyDay || s.anyWeekday {
+		return day && weekday
+	}
+	return day || weekday
+}
+`
@@ -0,0 +1,35 @@
+package schedulerAnnotation
+
+import (
+	"time"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+)
+
+const (
+	TypeScheduled = "Scheduled"
+	ParamCron     = "cron"
+	ParamTimezone = "timezone"
+	ParamTimeout  = "timeout"
+)
+
+func Get() []annotation.AnnotationDescriptor {
+	return []annotation.AnnotationDescriptor{
+		{
+			Name:       TypeScheduled,
+			ParamNames: []string{ParamCron, ParamTimezone, ParamTimeout},
+			Validator:  validateScheduledAnnotation,
+		}}
+}
+
+func validateScheduledAnnotation(annot annotation.Annotation) bool {
+	if annot.Name == TypeScheduled {
+		if timeout, found := annot.Attributes[ParamTimeout]; found {
+			if d, err := time.ParseDuration(timeout); err != nil || d <= 0 {
+				return false
+			}
+		}
+		return annot.Attributes[ParamCron] != ""
+	}
+	return false
+}
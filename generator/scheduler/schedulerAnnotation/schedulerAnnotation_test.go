@@ -0,0 +1,30 @@
+package schedulerAnnotation
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrectScheduledAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	annotation, ok := registry.ResolveAnnotation(`// @Scheduled( cron = "*/15 8-18 * * MON-FRI", timezone = "Europe/Amsterdam", timeout = "5m" )`)
+	assert.True(t, ok)
+	assert.Equal(t, TypeScheduled, annotation.Name)
+	assert.Equal(t, "*/15 8-18 * * MON-FRI", annotation.Attributes[ParamCron])
+	assert.Equal(t, "Europe/Amsterdam", annotation.Attributes[ParamTimezone])
+	assert.Equal(t, "5m", annotation.Attributes[ParamTimeout])
+
+	_, ok = registry.ResolveAnnotation(`// @Scheduled( cron = "@daily" )`)
+	assert.True(t, ok)
+}
+
+func TestInvalidScheduledAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @Scheduled( timezone = "UTC" )`}))
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @Scheduled( cron = "@daily", timeout = "5 minutes" )`}))
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @Scheduled( cron = "@daily", timeout = "-1s" )`}))
+}
@@ -0,0 +1,241 @@
+package search
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/generator/search/searchAnnotation"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+// maxMappingDepth protects against endless recursion of self-referencing structs
+const maxMappingDepth = 8
+
+type searchContext struct {
+	PackageName string
+	Document    model.Struct
+	Index       string
+	Aggregate   string
+	IDField     string
+	Mapping     string
+}
+
+type Generator struct {
+}
+
+func NewGenerator() generator.Generator {
+	return &Generator{}
+}
+
+func (eg *Generator) Name() string {
+	return "search"
+}
+
+func (eg *Generator) GetTemplates() []generator.Template {
+	return []generator.Template{
+		{Name: "searchable", APIVersion: 1, Text: searchableTemplate},
+	}
+}
+
+func (eg *Generator) GetAnnotations() []annotation.AnnotationDescriptor {
+	return searchAnnotation.Get()
+}
+
+func (eg *Generator) Generate(parsedSource model.ParsedSources, config generator.Config) ([]generator.OutputFile, error) {
+	return generate(config, parsedSource.Structs)
+}
+
+func generate(config generator.Config, structs []model.Struct) ([]generator.OutputFile, error) {
+	packageName, err := generationUtil.GetPackageNameForStructs(structs)
+	if packageName == "" || err != nil {
+		return nil, err
+	}
+	targetDir, err := generationUtil.DetermineTargetPath(config.InputDir, packageName)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []generator.OutputFile{}
+
+	for _, s := range structs {
+		if !IsSearchable(s) {
+			continue
+		}
+		idField, found := GetIDField(s)
+		if !found {
+			return nil, generator.StructError(s, searchAnnotation.TypeSearchable, "Searchable %s has no field UID or field annotated with @SearchField( id = \"true\" )", s.Name)
+		}
+		mapping, err := GetIndexMapping(s, structs)
+		if err != nil {
+			return nil, err
+		}
+
+		file, err := generationUtil.Generate(generationUtil.Info{
+			Src:            fmt.Sprintf("%s.%s", s.PackageName, s.Name),
+			TypeName:       s.Name,
+			TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/searchable%s.go", targetDir, s.Name)),
+			TemplateName:   "searchable",
+			TemplateString: searchableTemplate,
+			Config:         config,
+			FuncMap:        customTemplateFuncs,
+			Data: searchContext{
+				PackageName: packageName,
+				Document:    s,
+				Index:       GetIndexName(s),
+				Aggregate:   GetAggregateName(s),
+				IDField:     idField.Name,
+				Mapping:     mapping,
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Error generating searchable %s:%w", s.Name, err)
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+var customTemplateFuncs = template.FuncMap{}
+
+func IsSearchable(s model.Struct) bool {
+	annotations := annotation.NewRegistry(searchAnnotation.Get())
+	_, ok := annotations.ResolveAnnotationByName(s.DocLines, searchAnnotation.TypeSearchable)
+	return ok
+}
+
+func GetIndexName(s model.Struct) string {
+	annotations := annotation.NewRegistry(searchAnnotation.Get())
+	if ann, ok := annotations.ResolveAnnotationByName(s.DocLines, searchAnnotation.TypeSearchable); ok {
+		if index := ann.Attributes[searchAnnotation.ParamIndex]; index != "" {
+			return index
+		}
+	}
+	return generationUtil.SnakeCase(s.Name)
+}
+
+func GetAggregateName(s model.Struct) string {
+	annotations := annotation.NewRegistry(searchAnnotation.Get())
+	if ann, ok := annotations.ResolveAnnotationByName(s.DocLines, searchAnnotation.TypeSearchable); ok {
+		return ann.Attributes[searchAnnotation.ParamAggregate]
+	}
+	return ""
+}
+
+// GetIDField returns the field that identifies the document: explicitly annotated or else the field UID
+func GetIDField(s model.Struct) (model.Field, bool) {
+	annotations := annotation.NewRegistry(searchAnnotation.Get())
+	for _, f := range s.Fields {
+		if ann, ok := annotations.ResolveAnnotationByName(f.DocLines, searchAnnotation.TypeSearchField); ok && ann.Attributes[searchAnnotation.ParamID] == "true" {
+			return f, true
+		}
+	}
+	for _, f := range s.Fields {
+		if f.Name == "UID" && f.IsString() {
+			return f, true
+		}
+	}
+	return model.Field{}, false
+}
+
+// GetIndexMapping returns the elasticsearch index-mapping in json
+func GetIndexMapping(s model.Struct, structs []model.Struct) (string, error) {
+	mapping := map[string]interface{}{
+		"mappings": map[string]interface{}{
+			"properties": getProperties(s, structs, 0),
+		},
+	}
+	marshalled, err := json.MarshalIndent(mapping, "", "\t")
+	if err != nil {
+		return "", fmt.Errorf("Error marshalling index-mapping of %s:%w", s.Name, err)
+	}
+	return string(marshalled), nil
+}
+
+func getProperties(s model.Struct, structs []model.Struct, depth int) map[string]interface{} {
+	annotations := annotation.NewRegistry(searchAnnotation.Get())
+
+	properties := map[string]interface{}{}
+	for _, f := range s.Fields {
+		name, exported := GetDocumentFieldName(f)
+		if !exported {
+			continue
+		}
+		property := map[string]interface{}{}
+		if ann, ok := annotations.ResolveAnnotationByName(f.DocLines, searchAnnotation.TypeSearchField); ok {
+			if ann.Attributes[searchAnnotation.ParamSkip] == "true" {
+				continue
+			}
+			if fieldType := ann.Attributes[searchAnnotation.ParamType]; fieldType != "" {
+				property["type"] = fieldType
+			}
+			if analyzer := ann.Attributes[searchAnnotation.ParamAnalyzer]; analyzer != "" {
+				property["analyzer"] = analyzer
+			}
+		}
+		if _, explicit := property["type"]; !explicit {
+			elementType := strings.TrimPrefix(f.SliceElementTypeName(), "*")
+			if fieldType := searchType(elementType); fieldType != "" {
+				property["type"] = fieldType
+			} else if sub, found := findStruct(structs, elementType); found && depth < maxMappingDepth {
+				property["properties"] = getProperties(sub, structs, depth+1)
+			} else {
+				property["type"] = "object"
+				property["enabled"] = false
+			}
+		}
+		properties[name] = property
+	}
+	return properties
+}
+
+func searchType(typeName string) string {
+	switch typeName {
+	case "string":
+		return "text"
+	case "bool":
+		return "boolean"
+	case "int", "int64", "uint", "uint64":
+		return "long"
+	case "int32", "int16", "int8", "uint32", "uint16", "uint8":
+		return "integer"
+	case "float64":
+		return "double"
+	case "float32":
+		return "float"
+	case "time.Time", "mydate.MyDate", "mytime.MyTime":
+		return "date"
+	}
+	return ""
+}
+
+// GetDocumentFieldName returns the name of the field in the json-document, honoring the json-tag
+func GetDocumentFieldName(f model.Field) (string, bool) {
+	if f.Name == "" || !unicode.IsUpper([]rune(f.Name)[0]) {
+		return "", false
+	}
+	if jsonTag, found := f.GetTagMap()["json"]; found {
+		name := strings.Split(jsonTag, ",")[0]
+		if name == "-" {
+			return "", false
+		}
+		if name != "" {
+			return name, true
+		}
+	}
+	return f.Name, true
+}
+
+func findStruct(structs []model.Struct, name string) (model.Struct, bool) {
+	for _, s := range structs {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return model.Struct{}, false
+}
@@ -0,0 +1,85 @@
+package search
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func cleanup() {
+	os.Remove(generationUtil.Prefixed("./testData/searchablePersonDocument.go"))
+}
+
+func TestGenerateForSearch(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @Searchable( index = "persons", aggregate = "Person" )`},
+			Name:        "PersonDocument",
+			Fields: []model.Field{
+				{Name: "UID", TypeName: "string", Tag: "`json:\"uid\"`", DocLines: []string{`// @SearchField( type = "keyword" )`}},
+				{Name: "Name", TypeName: "string", Tag: "`json:\"name\"`", DocLines: []string{`// @SearchField( analyzer = "dutch" )`}},
+				{Name: "Age", TypeName: "int", Tag: "`json:\"age,omitempty\"`"},
+				{Name: "Address", TypeName: "*Address", Tag: "`json:\"address\"`"},
+				{Name: "Secret", TypeName: "string", Tag: "`json:\"-\"`"},
+				{Name: "Notes", TypeName: "[]string", DocLines: []string{`// @SearchField( skip = "true" )`}},
+			},
+		},
+		{
+			PackageName: "testData",
+			Name:        "Address",
+			Fields: []model.Field{
+				{Name: "City", TypeName: "string", Tag: "`json:\"city\"`"},
+			},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Nil(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/searchablePersonDocument.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `const PersonDocumentIndexName = "persons"`)
+	assert.Contains(t, string(data), `"uid": {
+				"type": "keyword"
+			}`)
+	assert.Contains(t, string(data), `"name": {
+				"analyzer": "dutch",
+				"type": "text"
+			}`)
+	assert.Contains(t, string(data), `"age": {
+				"type": "long"
+			}`)
+	assert.Contains(t, string(data), `"address": {
+				"properties": {
+					"city": {
+						"type": "text"
+					}
+				}
+			}`)
+	assert.NotContains(t, string(data), `"Secret"`)
+	assert.NotContains(t, string(data), `"Notes"`)
+	assert.Contains(t, string(data), `return fmt.Sprintf("%v", doc.UID)`)
+	assert.Contains(t, string(data), `bus.Subscribe("Person", "PersonDocumentIndexer", indexer.handleEvent)`)
+}
+
+func TestGenerateForSearchWithoutID(t *testing.T) {
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @Searchable( aggregate = "Person" )`},
+			Name:        "PersonDocument",
+			Fields:      []model.Field{{Name: "Name", TypeName: "string"}},
+		},
+	}
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Error(t, err)
+}
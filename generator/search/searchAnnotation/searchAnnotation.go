@@ -0,0 +1,43 @@
+package searchAnnotation
+
+import "github.com/MarcGrol/golangAnnotations/generator/annotation"
+
+const (
+	TypeSearchable  = "Searchable"
+	TypeSearchField = "SearchField"
+	ParamIndex      = "index"
+	ParamAggregate  = "aggregate"
+	ParamType       = "type"
+	ParamAnalyzer   = "analyzer"
+	ParamID         = "id"
+	ParamSkip       = "skip"
+)
+
+func Get() []annotation.AnnotationDescriptor {
+	return []annotation.AnnotationDescriptor{
+		{
+			Name:       TypeSearchable,
+			ParamNames: []string{ParamIndex, ParamAggregate},
+			Validator:  validateSearchableAnnotation,
+		},
+		{
+			Name:       TypeSearchField,
+			ParamNames: []string{ParamType, ParamAnalyzer, ParamID, ParamSkip},
+			Validator:  validateSearchFieldAnnotation,
+		}}
+}
+
+func validateSearchableAnnotation(annot annotation.Annotation) bool {
+	if annot.Name == TypeSearchable {
+		val, hasAggr := annot.Attributes[ParamAggregate]
+		return hasAggr && val != ""
+	}
+	return false
+}
+
+func validateSearchFieldAnnotation(annot annotation.Annotation) bool {
+	if annot.Name == TypeSearchField {
+		return true
+	}
+	return false
+}
@@ -0,0 +1,33 @@
+package searchAnnotation
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrectSearchableAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	annotation, ok := registry.ResolveAnnotation(`// @Searchable( index = "persons", aggregate = "Person" )`)
+	assert.True(t, ok)
+	assert.Equal(t, TypeSearchable, annotation.Name)
+	assert.Equal(t, "persons", annotation.Attributes[ParamIndex])
+	assert.Equal(t, "Person", annotation.Attributes[ParamAggregate])
+}
+
+func TestSearchableAnnotationWithoutAggregate(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	_, ok := registry.ResolveAnnotation(`// @Searchable( index = "persons" )`)
+	assert.False(t, ok)
+}
+
+func TestCorrectSearchFieldAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	annotation, ok := registry.ResolveAnnotation(`// @SearchField( type = "keyword" )`)
+	assert.True(t, ok)
+	assert.Equal(t, "keyword", annotation.Attributes[ParamType])
+}
@@ -0,0 +1,133 @@
+package search
+
+const searchableTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+
+package {{.PackageName}}
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/elastic/go-elasticsearch/v7"
+	"github.com/elastic/go-elasticsearch/v7/esapi"
+)
+
+{{ $doc := .Document.Name -}}
+
+// {{$doc}}IndexName is the name of the elasticsearch index that holds {{$doc}} documents
+const {{$doc}}IndexName = "{{.Index}}"
+
+// {{$doc}}IndexMapping is the elasticsearch index-mapping derived from {{$doc}}
+const {{$doc}}IndexMapping = ` + "`" + `{{.Mapping}}` + "`" + `
+
+func (doc {{$doc}}) SearchDocumentID() string {
+	return fmt.Sprintf("%v", doc.{{.IDField}})
+}
+
+func (doc {{$doc}}) MarshalSearchDocument() ([]byte, error) {
+	return json.Marshal(doc)
+}
+
+func Unmarshal{{$doc}}SearchDocument(data []byte) (*{{$doc}}, error) {
+	doc := {{$doc}}{}
+	err := json.Unmarshal(data, &doc)
+	if err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// {{$doc}}Projector converts the events of aggregate {{.Aggregate}} into the latest {{$doc}}:
+// a nil document without deletion means the event is not relevant for the index
+type {{$doc}}Projector interface {
+	Project{{$doc}}(c context.Context, rc request.Context, envlp envelope.Envelope) (doc *{{$doc}}, deleted bool, err error)
+}
+
+// {{$doc}}Indexer keeps index {{.Index}} up to date with the events of aggregate {{.Aggregate}}
+type {{$doc}}Indexer struct {
+	client    *elasticsearch.Client
+	projector {{$doc}}Projector
+}
+
+func New{{$doc}}Indexer(client *elasticsearch.Client, projector {{$doc}}Projector) *{{$doc}}Indexer {
+	return &{{$doc}}Indexer{
+		client:    client,
+		projector: projector,
+	}
+}
+
+func (indexer *{{$doc}}Indexer) SubscribeToEvents() {
+	bus.Subscribe("{{.Aggregate}}", "{{$doc}}Indexer", indexer.handleEvent)
+}
+
+// CreateIndex creates index {{.Index}} with the generated mapping
+func (indexer *{{$doc}}Indexer) CreateIndex(c context.Context) error {
+	res, err := esapi.IndicesCreateRequest{
+		Index: {{$doc}}IndexName,
+		Body:  strings.NewReader({{$doc}}IndexMapping),
+	}.Do(c, indexer.client)
+	if err != nil {
+		return fmt.Errorf("Error creating index %s: %s", {{$doc}}IndexName, err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return fmt.Errorf("Error creating index %s: %s", {{$doc}}IndexName, res.String())
+	}
+	return nil
+}
+
+func (indexer *{{$doc}}Indexer) Index(c context.Context, doc {{$doc}}) error {
+	data, err := doc.MarshalSearchDocument()
+	if err != nil {
+		return fmt.Errorf("Error marshalling {{$doc}} %s: %s", doc.SearchDocumentID(), err)
+	}
+	res, err := esapi.IndexRequest{
+		Index:      {{$doc}}IndexName,
+		DocumentID: doc.SearchDocumentID(),
+		Body:       bytes.NewReader(data),
+	}.Do(c, indexer.client)
+	if err != nil {
+		return fmt.Errorf("Error indexing {{$doc}} %s: %s", doc.SearchDocumentID(), err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return fmt.Errorf("Error indexing {{$doc}} %s: %s", doc.SearchDocumentID(), res.String())
+	}
+	return nil
+}
+
+func (indexer *{{$doc}}Indexer) Delete(c context.Context, documentID string) error {
+	res, err := esapi.DeleteRequest{
+		Index:      {{$doc}}IndexName,
+		DocumentID: documentID,
+	}.Do(c, indexer.client)
+	if err != nil {
+		return fmt.Errorf("Error deleting {{$doc}} %s: %s", documentID, err)
+	}
+	defer res.Body.Close()
+	if res.IsError() && res.StatusCode != 404 {
+		return fmt.Errorf("Error deleting {{$doc}} %s: %s", documentID, res.String())
+	}
+	return nil
+}
+
+func (indexer *{{$doc}}Indexer) handleEvent(c context.Context, rc request.Context, topic string, envlp envelope.Envelope) error {
+	doc, deleted, err := indexer.projector.Project{{$doc}}(c, rc, envlp)
+	if err != nil {
+		return fmt.Errorf("Error projecting %s onto {{$doc}}: %s", envlp.NiceName(), err)
+	}
+	if deleted {
+		if doc != nil {
+			return indexer.Delete(c, doc.SearchDocumentID())
+		}
+		return indexer.Delete(c, envlp.AggregateUID)
+	}
+	if doc == nil {
+		return nil
+	}
+	return indexer.Index(c, *doc)
+}
+`
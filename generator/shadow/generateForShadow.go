@@ -0,0 +1,120 @@
+package shadow
+
+import (
+	"fmt"
+	"strconv"
+	"text/template"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/generator/rest"
+	"github.com/MarcGrol/golangAnnotations/generator/shadow/shadowAnnotation"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+const defaultPercentage = "10"
+
+type shadowContext struct {
+	PackageName string
+	Name        string
+	Percentage  string
+	Writes      bool
+	Routes      []route
+}
+
+// route tells by method and path which operation a call is for
+type route struct {
+	Operation string
+	Method    string
+	Pattern   string
+}
+
+type Generator struct {
+}
+
+func NewGenerator() generator.Generator {
+	return &Generator{}
+}
+
+func (eg *Generator) Name() string {
+	return "shadow"
+}
+
+func (eg *Generator) GetTemplates() []generator.Template {
+	return []generator.Template{
+		{Name: "shadow", APIVersion: 1, Text: shadowTemplate},
+	}
+}
+
+func (eg *Generator) GetAnnotations() []annotation.AnnotationDescriptor {
+	return shadowAnnotation.Get()
+}
+
+func (eg *Generator) Generate(parsedSource model.ParsedSources, config generator.Config) ([]generator.OutputFile, error) {
+	return generate(config, parsedSource.Structs)
+}
+
+func generate(config generator.Config, structs []model.Struct) ([]generator.OutputFile, error) {
+	packageName, err := generationUtil.GetPackageNameForStructs(structs)
+	if packageName == "" || err != nil {
+		return nil, err
+	}
+	targetDir, err := generationUtil.DetermineTargetPath(config.InputDir, packageName)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []generator.OutputFile{}
+	for _, service := range structs {
+		ann, ok := annotation.NewRegistry(shadowAnnotation.Get()).ResolveAnnotationByName(service.DocLines, shadowAnnotation.TypeShadow)
+		if !ok {
+			continue
+		}
+		if !rest.IsRestService(service) {
+			return nil, generator.StructError(service, shadowAnnotation.TypeShadow, "Struct %s with @Shadow must be a @RestService", service.Name)
+		}
+		file, err := generationUtil.Generate(generationUtil.Info{
+			Src:            fmt.Sprintf("%s.%s", service.PackageName, service.Name),
+			TypeName:       service.Name,
+			TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/shadow%s.go", targetDir, rest.ToFirstUpper(service.Name))),
+			TemplateName:   "shadow",
+			TemplateString: shadowTemplate,
+			Config:         config,
+			FuncMap:        customTemplateFuncs,
+			Data:           newShadowContext(service, ann),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Error generating shadow for service %s:%w", service.Name, err)
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+var customTemplateFuncs = template.FuncMap{
+	"Quoted":         strconv.Quote,
+	"LowerCamelCase": generationUtil.LowerCamelCase,
+}
+
+func newShadowContext(service model.Struct, ann annotation.Annotation) shadowContext {
+	ctx := shadowContext{
+		PackageName: service.PackageName,
+		Name:        service.Name,
+		Percentage:  ann.Attributes[shadowAnnotation.ParamPercentage],
+		Writes:      ann.Attributes[shadowAnnotation.ParamWrites] == "true",
+	}
+	if ctx.Percentage == "" {
+		ctx.Percentage = defaultPercentage
+	}
+	for _, o := range service.Operations {
+		if rest.IsRestOperation(*o) {
+			ctx.Routes = append(ctx.Routes, route{
+				Operation: o.Name,
+				Method:    rest.GetRestOperationMethod(*o),
+				Pattern:   rest.GetRestOperationPathPattern(service, *o),
+			})
+		}
+	}
+	return ctx
+}
@@ -0,0 +1,91 @@
+package shadow
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func cleanup() {
+	os.Remove(generationUtil.Prefixed("./testData/shadowPersonService.go"))
+}
+
+func TestGenerateShadow(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @RestService( path = "/api" )`, `// @Shadow( percentage = "25" )`},
+			Name:        "PersonService",
+			Operations: []*model.Operation{
+				{
+					DocLines:   []string{`// @RestOperation( method = "GET", path = "/person/{uid}", format = "JSON" )`},
+					Name:       "getPerson",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}},
+					OutputArgs: []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+				},
+			},
+		},
+	}
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/shadowPersonService.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "const PersonServiceShadowPercentage = 25\n")
+	assert.Contains(t, string(data), `{"getPerson", "GET", regexp.MustCompile("^/api/person/[^/]+$")},`)
+	assert.Contains(t, string(data), "func NewPersonServiceShadow(primary http.RoundTripper, secondary *url.URL, percentage float64, metrics PersonServiceShadowMetrics) http.RoundTripper {")
+	assert.Contains(t, string(data), `if operation == "" || (r.Method != http.MethodGet && r.Method != http.MethodHead) || rand.Float64()*100 >= percentage {`)
+	assert.Contains(t, string(data), "func NewPersonServiceCanary(primary http.RoundTripper, canary *url.URL, percentage float64) http.RoundTripper {")
+}
+
+func TestGenerateShadowOfWrites(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @RestService( path = "/api" )`, `// @Shadow( writes = "true" )`},
+			Name:        "PersonService",
+			Operations: []*model.Operation{
+				{
+					DocLines:   []string{`// @RestOperation( method = "GET", path = "/person/{uid}", format = "JSON" )`},
+					Name:       "getPerson",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}},
+					OutputArgs: []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+				},
+			},
+		},
+	}
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/shadowPersonService.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "const PersonServiceShadowPercentage = 10\n")
+	assert.Contains(t, string(data), `if operation == "" || rand.Float64()*100 >= percentage {`)
+}
+
+func TestGenerateShadowForNoRestService(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @Shadow()`},
+			Name:        "PersonService",
+		},
+	}
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Struct PersonService with @Shadow must be a @RestService")
+}
@@ -0,0 +1,200 @@
+package shadow
+
+const shadowTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+
+package {{.PackageName}}
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+)
+
+{{$service := .Name -}}
+{{$prefix := LowerCamelCase .Name -}}
+// {{$service}}ShadowPercentage is the default percentage of the calls to {{$service}} that are mirrored
+const {{$service}}ShadowPercentage = {{.Percentage}}
+
+// {{$service}}ShadowMetrics receives the outcomes of the calls to {{$service}} that were mirrored to a secondary
+// implementation, for example to count them per operation
+type {{$service}}ShadowMetrics interface {
+	// Compared receives the differences between the responses of the primary and the secondary: none when they match
+	Compared(operation string, differences []string)
+	// Failed receives the error of a mirrored call that got no response
+	Failed(operation string, err error)
+}
+
+var {{$prefix}}ShadowRoutes = []struct {
+	operation string
+	method    string
+	path      *regexp.Regexp
+}{
+{{- range .Routes}}
+	{"{{.Operation}}", "{{.Method}}", regexp.MustCompile({{Quoted .Pattern}})},
+{{- end}}
+}
+
+func {{$prefix}}ShadowOperation(r *http.Request) string {
+	for _, route := range {{$prefix}}ShadowRoutes {
+		if route.method == r.Method && route.path.MatchString(r.URL.Path) {
+			return route.operation
+		}
+	}
+	return ""
+}
+
+type {{$prefix}}ShadowTransport func(r *http.Request) (*http.Response, error)
+
+func (t {{$prefix}}ShadowTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	return t(r)
+}
+
+// New{{$service}}Shadow returns a transport for clients of {{$service}} that sends calls to primary and mirrors the
+// percentage of them to secondary in the background. Metrics receives how the responses of secondary differ from
+// the ones of primary, that the clients get.
+{{- if not .Writes}} Only GET- and HEAD-calls are mirrored, to keep secondary from writing twice.{{end}}
+func New{{$service}}Shadow(primary http.RoundTripper, secondary *url.URL, percentage float64, metrics {{$service}}ShadowMetrics) http.RoundTripper {
+	return {{$prefix}}ShadowTransport(func(r *http.Request) (*http.Response, error) {
+		operation := {{$prefix}}ShadowOperation(r)
+		if operation == ""{{if not .Writes}} || (r.Method != http.MethodGet && r.Method != http.MethodHead){{end}} || rand.Float64()*100 >= percentage {
+			return primary.RoundTrip(r)
+		}
+
+		requestBody, err := {{$prefix}}ReadBody(&r.Body)
+		if err != nil {
+			return nil, err
+		}
+		mirrored := {{$prefix}}Redirect(r.Clone(context.Background()), secondary, requestBody)
+
+		resp, err := primary.RoundTrip(r)
+		if err != nil {
+			return nil, err
+		}
+		body, err := {{$prefix}}ReadBody(&resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		go func() {
+			secondaryResp, err := http.DefaultTransport.RoundTrip(mirrored)
+			if err != nil {
+				metrics.Failed(operation, err)
+				return
+			}
+			defer secondaryResp.Body.Close()
+			secondaryBody, err := ioutil.ReadAll(secondaryResp.Body)
+			if err != nil {
+				metrics.Failed(operation, err)
+				return
+			}
+			metrics.Compared(operation, Compare{{$service}}Responses(resp.StatusCode, body, secondaryResp.StatusCode, secondaryBody))
+		}()
+		return resp, nil
+	})
+}
+
+// New{{$service}}Canary returns a transport for clients of {{$service}} that sends the percentage of the calls to
+// canary instead of primary
+func New{{$service}}Canary(primary http.RoundTripper, canary *url.URL, percentage float64) http.RoundTripper {
+	return {{$prefix}}ShadowTransport(func(r *http.Request) (*http.Response, error) {
+		if rand.Float64()*100 >= percentage {
+			return primary.RoundTrip(r)
+		}
+		requestBody, err := {{$prefix}}ReadBody(&r.Body)
+		if err != nil {
+			return nil, err
+		}
+		return http.DefaultTransport.RoundTrip({{$prefix}}Redirect(r.Clone(r.Context()), canary, requestBody))
+	})
+}
+
+// Compare{{$service}}Responses returns the differences between two responses: json-bodies are compared by their
+// values, other bodies byte by byte
+func Compare{{$service}}Responses(status int, body []byte, otherStatus int, otherBody []byte) []string {
+	differences := []string{}
+	if status != otherStatus {
+		differences = append(differences, fmt.Sprintf("status: %d != %d", status, otherStatus))
+	}
+	var value, otherValue interface{}
+	if json.Unmarshal(body, &value) == nil && json.Unmarshal(otherBody, &otherValue) == nil {
+		return append(differences, {{$prefix}}CompareJSON("body", value, otherValue)...)
+	}
+	if !bytes.Equal(body, otherBody) {
+		differences = append(differences, "body differs")
+	}
+	return differences
+}
+
+func {{$prefix}}CompareJSON(path string, value interface{}, otherValue interface{}) []string {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		other, ok := otherValue.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := []string{}
+		for key := range v {
+			keys = append(keys, key)
+		}
+		for key := range other {
+			if _, found := v[key]; !found {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		differences := []string{}
+		for _, key := range keys {
+			differences = append(differences, {{$prefix}}CompareJSON(path+"."+key, v[key], other[key])...)
+		}
+		return differences
+	case []interface{}:
+		other, ok := otherValue.([]interface{})
+		if !ok || len(v) != len(other) {
+			break
+		}
+		differences := []string{}
+		for idx := range v {
+			differences = append(differences, {{$prefix}}CompareJSON(fmt.Sprintf("%s[%d]", path, idx), v[idx], other[idx])...)
+		}
+		return differences
+	}
+	if reflect.DeepEqual(value, otherValue) {
+		return nil
+	}
+	primary, _ := json.Marshal(value)
+	secondary, _ := json.Marshal(otherValue)
+	return []string{fmt.Sprintf("%s: %s != %s", path, primary, secondary)}
+}
+
+// {{$prefix}}Redirect points a copy of a request to another host
+func {{$prefix}}Redirect(r *http.Request, target *url.URL, body []byte) *http.Request {
+	r.URL.Scheme, r.URL.Host, r.Host = target.Scheme, target.Host, target.Host
+	r.RequestURI = ""
+	if body != nil {
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	return r
+}
+
+// {{$prefix}}ReadBody reads a body and replaces it by a copy, so that it can be read again
+func {{$prefix}}ReadBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	data, err := ioutil.ReadAll(*body)
+	if err != nil {
+		return nil, err
+	}
+	(*body).Close()
+	*body = ioutil.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+`
@@ -0,0 +1,35 @@
+package shadowAnnotation
+
+import (
+	"strconv"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+)
+
+const (
+	TypeShadow      = "Shadow"
+	ParamPercentage = "percentage"
+	ParamWrites     = "writes"
+)
+
+func Get() []annotation.AnnotationDescriptor {
+	return []annotation.AnnotationDescriptor{
+		{
+			Name:       TypeShadow,
+			ParamNames: []string{ParamPercentage, ParamWrites},
+			Validator:  validateShadowAnnotation,
+		}}
+}
+
+func validateShadowAnnotation(annot annotation.Annotation) bool {
+	if annot.Name == TypeShadow {
+		if percentage, found := annot.Attributes[ParamPercentage]; found {
+			if value, err := strconv.ParseFloat(percentage, 64); err != nil || value < 0 || value > 100 {
+				return false
+			}
+		}
+		writes, hasWrites := annot.Attributes[ParamWrites]
+		return !hasWrites || writes == "true" || writes == "false"
+	}
+	return false
+}
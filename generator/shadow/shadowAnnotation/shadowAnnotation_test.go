@@ -0,0 +1,26 @@
+package shadowAnnotation
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrectShadowAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	annotation, ok := registry.ResolveAnnotation(`// @Shadow( percentage = "12.5", writes = "true" )`)
+	assert.True(t, ok)
+	assert.Equal(t, TypeShadow, annotation.Name)
+	assert.Equal(t, "12.5", annotation.Attributes[ParamPercentage])
+	assert.Equal(t, "true", annotation.Attributes[ParamWrites])
+}
+
+func TestInvalidShadowAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @Shadow( percentage = "150" )`}))
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @Shadow( percentage = "some" )`}))
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @Shadow( writes = "yes" )`}))
+}
@@ -0,0 +1,120 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+// SourceError is an error in an annotated declaration. It reads like a compiler error: "<file>:<line>: <message>"
+type SourceError struct {
+	Filename string
+	Line     int
+	// Declaration is the name of the offending declaration, e.g. Service.getPerson
+	Declaration string
+	Message     string
+}
+
+func (e SourceError) Error() string {
+	if e.Filename == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Position(), e.Message)
+}
+
+// Position returns where the error is, as "<file>:<line>" or only the file when the line is unknown
+func (e SourceError) Position() string {
+	if e.Line == 0 {
+		return e.Filename
+	}
+	return fmt.Sprintf("%s:%d", e.Filename, e.Line)
+}
+
+// AsSourceError returns the source error that err is or wraps
+func AsSourceError(err error) (SourceError, bool) {
+	var sourceError SourceError
+	ok := errors.As(err, &sourceError)
+	return sourceError, ok
+}
+
+// StructError returns an error about struct s, positioned at its annotation with the given name when it has one
+func StructError(s model.Struct, annotationName string, format string, args ...interface{}) error {
+	return newSourceError(s.Filename, annotationLine(s.Line, s.DocLines, annotationName), s.Name, format, args...)
+}
+
+// FieldError returns an error about field f of struct s, positioned at its annotation with the given name when it has one
+func FieldError(s model.Struct, f model.Field, annotationName string, format string, args ...interface{}) error {
+	return newSourceError(s.Filename, annotationLine(f.Line, f.DocLines, annotationName), s.Name+"."+f.Name, format, args...)
+}
+
+// InterfaceError returns an error about interface i, positioned at its annotation with the given name when it has one
+func InterfaceError(i model.Interface, annotationName string, format string, args ...interface{}) error {
+	return newSourceError(i.Filename, annotationLine(i.Line, i.DocLines, annotationName), i.Name, format, args...)
+}
+
+// TypedefError returns an error about typedef t, positioned at its annotation with the given name when it has one
+func TypedefError(t model.Typedef, annotationName string, format string, args ...interface{}) error {
+	return newSourceError(t.Filename, annotationLine(t.Line, t.DocLines, annotationName), t.Name, format, args...)
+}
+
+// EnumError returns an error about enum e, positioned at its annotation with the given name when it has one
+func EnumError(e model.Enum, annotationName string, format string, args ...interface{}) error {
+	return newSourceError(e.Filename, annotationLine(e.Line, e.DocLines, annotationName), e.Name, format, args...)
+}
+
+// OperationError returns an error about operation o of the struct or interface named owner, positioned at its
+// annotation with the given name when it has one. Owner is empty for functions.
+func OperationError(o model.Operation, owner string, annotationName string, format string, args ...interface{}) error {
+	declaration := o.Name
+	if owner != "" {
+		declaration = owner + "." + o.Name
+	}
+	return newSourceError(o.Filename, annotationLine(o.Line, o.DocLines, annotationName), declaration, format, args...)
+}
+
+func newSourceError(filename string, line int, declaration string, format string, args ...interface{}) error {
+	return SourceError{
+		Filename:    filepath.ToSlash(filename),
+		Line:        line,
+		Declaration: declaration,
+		Message:     fmt.Sprintf(format, args...),
+	}
+}
+
+// annotationLine returns the line of the annotation in the doc-lines that directly precede a declaration at line,
+// or the line of the declaration itself when the annotation is not found
+func annotationLine(line int, docLines []string, annotationName string) int {
+	if line == 0 || annotationName == "" {
+		return line
+	}
+	for idx, docLine := range docLines {
+		if containsAnnotation(docLine, annotationName) {
+			return line - len(docLines) + idx
+		}
+	}
+	return line
+}
+
+func containsAnnotation(docLine string, annotationName string) bool {
+	marker := "@" + annotationName
+	for offset := strings.Index(docLine, marker); offset >= 0; {
+		end := offset + len(marker)
+		if end == len(docLine) || !isIdentifierRune(rune(docLine[end])) {
+			return true
+		}
+		next := strings.Index(docLine[end:], marker)
+		if next < 0 {
+			return false
+		}
+		offset = end + next
+	}
+	return false
+}
+
+func isIdentifierRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
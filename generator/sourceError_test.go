@@ -0,0 +1,45 @@
+package generator
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOperationErrorPointsAtAnnotation(t *testing.T) {
+	o := model.Operation{
+		Filename: "service.go",
+		Line:     12,
+		DocLines: []string{
+			"// getPerson returns a person",
+			"// @RestOperation( method = \"GET\", path = \"/person/{id}\" )",
+		},
+		Name: "getPerson",
+	}
+	err := OperationError(o, "Service", "RestOperation", "Path parameter {%s} has no matching argument", "id")
+	assert.EqualError(t, err, "service.go:11: Path parameter {id} has no matching argument")
+
+	sourceError, ok := AsSourceError(fmt.Errorf("Wrapped:%w", err))
+	assert.True(t, ok)
+	assert.Equal(t, "Service.getPerson", sourceError.Declaration)
+}
+
+func TestStructErrorWithoutAnnotationPointsAtDeclaration(t *testing.T) {
+	s := model.Struct{Filename: "person.go", Line: 7, DocLines: []string{"// @RestServiceX()"}, Name: "Person"}
+	assert.EqualError(t, StructError(s, "RestService", "Broken"), "person.go:7: Broken")
+}
+
+func TestSourceErrorWithoutPosition(t *testing.T) {
+	assert.EqualError(t, StructError(model.Struct{Name: "Person"}, "Entity", "Broken"), "Broken")
+}
+
+func TestReportLocatesSourceError(t *testing.T) {
+	report := NewReport()
+	task := Task{Generator: namedGenerator{name: "entity"}, Config: Config{InputDir: "."}}
+	s := model.Struct{Filename: "person.go", Line: 9, DocLines: []string{"// @Entity()"}, Name: "Person"}
+	report.AddTask(task, TaskResult{Err: TaskError{Generator: "entity", InputDir: ".", Err: StructError(s, "Entity", "No id")}})
+	assert.Equal(t, []Diagnostic{{Severity: SeverityError, Generator: "entity", InputDir: ".", Filename: "person.go", Line: 8,
+		Declaration: "Person", Message: "No id"}}, report.Diagnostics)
+}
@@ -0,0 +1,39 @@
+package stateMachine
+
+const mermaidTemplate = `<!-- Generated automatically by golangAnnotations: do not edit manually -->
+
+# State-machine {{.Name}}
+
+The {{.Field}} of {{.Aggregate}}.
+
+` + "```" + `mermaid
+stateDiagram-v2
+    [*] --> {{.Initial}}
+{{- range $state := .States}}
+{{- range .Edges}}
+    {{$state.Name}} --> {{.To}}: {{Join .Methods ", "}}
+{{- end}}
+{{- if .IsTerminal}}
+    {{.Name}} --> [*]
+{{- end}}
+{{- end}}
+` + "```" + `
+`
+
+const dotTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+
+// State-machine {{.Name}}: the {{.Field}} of {{.Aggregate}}
+digraph {{.Name}} {
+    rankdir=LR;
+    initial [shape=point];
+{{- range .States}}
+    {{.Name}}{{if .IsTerminal}} [shape=doublecircle]{{end}};
+{{- end}}
+    initial -> {{.Initial}};
+{{- range $state := .States}}
+{{- range .Edges}}
+    {{$state.Name}} -> {{.To}} [label="{{Join .Methods ", "}}"];
+{{- end}}
+{{- end}}
+}
+`
@@ -0,0 +1,334 @@
+package stateMachine
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/MarcGrol/golangAnnotations/generator/event"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/generator/stateMachine/stateMachineAnnotation"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+type stateMachinesContext struct {
+	PackageName string
+	Machines    []*machine
+}
+
+// machine is an enum whose literals are the states of a field of an aggregate, with the methods of the aggregate
+// that move it from one state to another
+type machine struct {
+	Name      string
+	Aggregate string
+	Field     string
+	Initial   string
+	Diagram   string
+	States    []*state
+	// Transitions are in the order of the methods of the aggregate
+	Transitions []transition
+	literals    map[string]bool
+}
+
+type state struct {
+	Name string
+	// Targets are the states that the state can move to, in the order of the literals
+	Targets []string
+	// Edges are the transitions from the state, in the order of their targets
+	Edges []edge
+}
+
+type edge struct {
+	To      string
+	Methods []string
+}
+
+// transition is a method of an aggregate with @Transition
+type transition struct {
+	Method string
+	From   []string
+	To     string
+	Event  string
+}
+
+// IsTerminal tells that the state cannot move to another state, after it was reached
+func (s state) IsTerminal() bool {
+	return len(s.Targets) == 0
+}
+
+// HasEvents tells that the package needs the envelope and request-context for the events of the transitions
+func (c stateMachinesContext) HasEvents() bool {
+	for _, m := range c.Machines {
+		for _, t := range m.Transitions {
+			if t.Event != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// EventAggregates are the aggregates that emit events when they move to another state
+func (c stateMachinesContext) EventAggregates() []string {
+	aggregates := []string{}
+	for _, m := range c.Machines {
+		for _, t := range m.Transitions {
+			if t.Event != "" && !contains(aggregates, m.Aggregate) {
+				aggregates = append(aggregates, m.Aggregate)
+			}
+		}
+	}
+	return aggregates
+}
+
+type Generator struct {
+}
+
+func NewGenerator() generator.Generator {
+	return &Generator{}
+}
+
+func (eg *Generator) Name() string {
+	return "state-machine"
+}
+
+func (eg *Generator) GetTemplates() []generator.Template {
+	return []generator.Template{
+		{Name: "state-machines", APIVersion: 1, Text: stateMachinesTemplate},
+		{Name: "state-machine-mermaid", APIVersion: 1, Text: mermaidTemplate},
+		{Name: "state-machine-dot", APIVersion: 1, Text: dotTemplate},
+	}
+}
+
+func (eg *Generator) GetAnnotations() []annotation.AnnotationDescriptor {
+	return stateMachineAnnotation.Get()
+}
+
+func (eg *Generator) Generate(parsedSource model.ParsedSources, config generator.Config) ([]generator.OutputFile, error) {
+	return generate(config, parsedSource)
+}
+
+func generate(config generator.Config, parsedSources model.ParsedSources) ([]generator.OutputFile, error) {
+	machines, err := getMachines(parsedSources)
+	if err != nil || len(machines) == 0 {
+		return nil, err
+	}
+
+	packageName, err := generationUtil.GetPackageNameForEnumsOrStructs(parsedSources.Enums, parsedSources.Structs)
+	if packageName == "" || err != nil {
+		return nil, err
+	}
+	targetDir, err := generationUtil.DetermineTargetPath(config.InputDir, packageName)
+	if err != nil {
+		return nil, err
+	}
+
+	err = addTransitions(machines, parsedSources)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := generationUtil.Generate(generationUtil.Info{
+		Src:            packageName,
+		TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/stateMachines.go", targetDir)),
+		TemplateName:   "state-machines",
+		TemplateString: stateMachinesTemplate,
+		Config:         config,
+		FuncMap:        customTemplateFuncs,
+		Data:           stateMachinesContext{PackageName: packageName, Machines: machines},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Error generating state-machines for package %s:%w", packageName, err)
+	}
+	files := []generator.OutputFile{file}
+
+	for _, m := range machines {
+		templateName, templateString, extension := "state-machine-mermaid", mermaidTemplate, "md"
+		if m.Diagram == stateMachineAnnotation.DiagramDot {
+			templateName, templateString, extension = "state-machine-dot", dotTemplate, "dot"
+		}
+		file, err := generationUtil.Generate(generationUtil.Info{
+			Src:            fmt.Sprintf("%s.%s", packageName, m.Name),
+			TypeName:       m.Name,
+			TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/stateMachine%s.%s", targetDir, m.Name, extension)),
+			TemplateName:   templateName,
+			TemplateString: templateString,
+			Config:         config,
+			FuncMap:        customTemplateFuncs,
+			Data:           m,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Error generating diagram of state-machine %s:%w", m.Name, err)
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// getMachines returns the enums with @StateMachine, with the field of their aggregate that holds the state
+func getMachines(parsedSources model.ParsedSources) ([]*machine, error) {
+	machines := []*machine{}
+	registry := annotation.NewRegistry(stateMachineAnnotation.Get())
+	for _, e := range parsedSources.Enums {
+		ann, ok := registry.ResolveAnnotationByName(e.DocLines, stateMachineAnnotation.TypeStateMachine)
+		if !ok {
+			continue
+		}
+		if len(e.EnumLiterals) == 0 {
+			return nil, generator.EnumError(e, stateMachineAnnotation.TypeStateMachine, "State-machine %s has no states", e.Name)
+		}
+		m := &machine{
+			Name:      e.Name,
+			Aggregate: ann.Attributes[stateMachineAnnotation.ParamAggregate],
+			Initial:   ann.Attributes[stateMachineAnnotation.ParamInitial],
+			Diagram:   ann.Attributes[stateMachineAnnotation.ParamDiagram],
+			literals:  map[string]bool{},
+		}
+		for _, literal := range e.EnumLiterals {
+			m.States = append(m.States, &state{Name: literal.Name})
+			m.literals[literal.Name] = true
+		}
+		if m.Initial == "" {
+			m.Initial = e.EnumLiterals[0].Name
+		}
+		if !m.literals[m.Initial] {
+			return nil, generator.EnumError(e, stateMachineAnnotation.TypeStateMachine, "Initial state %s of state-machine %s is not a literal of %s", m.Initial, e.Name, e.Name)
+		}
+
+		aggregate, found := findStruct(m.Aggregate, parsedSources)
+		if !found {
+			return nil, generator.EnumError(e, stateMachineAnnotation.TypeStateMachine, "Aggregate %s of state-machine %s is not a struct in package %s", m.Aggregate, e.Name, e.PackageName)
+		}
+		field, err := stateField(aggregate, e, ann.Attributes[stateMachineAnnotation.ParamField])
+		if err != nil {
+			return nil, err
+		}
+		m.Field = field
+		for _, other := range machines {
+			if other.Aggregate == m.Aggregate && other.Field == m.Field {
+				return nil, generator.EnumError(e, stateMachineAnnotation.TypeStateMachine, "Field %s.%s is the state of state-machine %s already", m.Aggregate, m.Field, other.Name)
+			}
+		}
+		machines = append(machines, m)
+	}
+	return machines, nil
+}
+
+// stateField returns the field of the aggregate that holds the state: the one with the given name, or else the only
+// field of the type of the enum
+func stateField(aggregate model.Struct, e model.Enum, name string) (string, error) {
+	fields := []string{}
+	for _, f := range aggregate.Fields {
+		if f.TypeName != e.Name || f.Name == "" {
+			continue
+		}
+		if f.Name == name {
+			return f.Name, nil
+		}
+		fields = append(fields, f.Name)
+	}
+	if name != "" {
+		return "", generator.EnumError(e, stateMachineAnnotation.TypeStateMachine, "Aggregate %s of state-machine %s has no field %s of type %s", aggregate.Name, e.Name, name, e.Name)
+	}
+	if len(fields) != 1 {
+		return "", generator.EnumError(e, stateMachineAnnotation.TypeStateMachine, "Aggregate %s of state-machine %s has %d fields of type %s: name the one that holds the state with '%s'", aggregate.Name, e.Name, len(fields), e.Name, stateMachineAnnotation.ParamField)
+	}
+	return fields[0], nil
+}
+
+// addTransitions adds the methods of the aggregates with @Transition to the state-machine of the state they move to
+func addTransitions(machines []*machine, parsedSources model.ParsedSources) error {
+	registry := annotation.NewRegistry(stateMachineAnnotation.Get())
+	for _, s := range parsedSources.Structs {
+		for _, o := range s.Operations {
+			ann, ok := registry.ResolveAnnotationByName(o.DocLines, stateMachineAnnotation.TypeTransition)
+			if !ok {
+				continue
+			}
+			t := transition{
+				Method: o.Name,
+				To:     ann.Attributes[stateMachineAnnotation.ParamTo],
+				Event:  ann.Attributes[stateMachineAnnotation.ParamEvent],
+			}
+			m := findMachine(machines, s.Name, t.To)
+			if m == nil {
+				return generator.OperationError(*o, s.Name, stateMachineAnnotation.TypeTransition, "Transition %s.%s moves to %s, which is not a state of a state-machine of aggregate %s", s.Name, o.Name, t.To, s.Name)
+			}
+			for _, from := range strings.Split(ann.Attributes[stateMachineAnnotation.ParamFrom], ",") {
+				from = strings.TrimSpace(from)
+				if !m.literals[from] {
+					return generator.OperationError(*o, s.Name, stateMachineAnnotation.TypeTransition, "Transition %s.%s moves from %s, which is not a state of state-machine %s", s.Name, o.Name, from, m.Name)
+				}
+				if !contains(t.From, from) {
+					t.From = append(t.From, from)
+				}
+			}
+			if t.Event != "" {
+				evt, found := findStruct(t.Event, parsedSources)
+				if !found || !event.IsEvent(evt) {
+					return generator.OperationError(*o, s.Name, stateMachineAnnotation.TypeTransition, "Transition %s.%s emits %s, which is not an @Event in package %s", s.Name, o.Name, t.Event, s.PackageName)
+				}
+				if event.GetAggregateName(evt) != m.Aggregate {
+					return generator.OperationError(*o, s.Name, stateMachineAnnotation.TypeTransition, "Transition %s.%s emits %s, which is an event of aggregate %s instead of %s", s.Name, o.Name, t.Event, event.GetAggregateName(evt), m.Aggregate)
+				}
+			}
+			m.Transitions = append(m.Transitions, t)
+		}
+	}
+	for _, m := range machines {
+		addEdges(m)
+	}
+	return nil
+}
+
+// addEdges adds the transitions to the states they move from, in the order of the literals of the enum
+func addEdges(m *machine) {
+	for _, from := range m.States {
+		for _, to := range m.States {
+			methods := []string{}
+			for _, t := range m.Transitions {
+				if t.To == to.Name && contains(t.From, from.Name) {
+					methods = append(methods, t.Method)
+				}
+			}
+			if len(methods) > 0 {
+				from.Targets = append(from.Targets, to.Name)
+				from.Edges = append(from.Edges, edge{To: to.Name, Methods: methods})
+			}
+		}
+	}
+}
+
+func findMachine(machines []*machine, aggregate string, to string) *machine {
+	for _, m := range machines {
+		if m.Aggregate == aggregate && m.literals[to] {
+			return m
+		}
+	}
+	return nil
+}
+
+func findStruct(name string, parsedSources model.ParsedSources) (model.Struct, bool) {
+	for _, s := range parsedSources.Structs {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return model.Struct{}, false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+var customTemplateFuncs = template.FuncMap{
+	"LowerCamelCase": generationUtil.LowerCamelCase,
+	"Join":           strings.Join,
+}
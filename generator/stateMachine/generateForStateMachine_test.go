@@ -0,0 +1,333 @@
+package stateMachine
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func cleanup() {
+	os.Remove(generationUtil.Prefixed("./testData/stateMachines.go"))
+	os.Remove(generationUtil.Prefixed("./testData/stateMachineOrderStatus.md"))
+	os.Remove(generationUtil.Prefixed("./testData/stateMachineOrderStatus.dot"))
+	os.Remove("./testData")
+}
+
+func TestGenerateStateMachine(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	e := []model.Enum{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @StateMachine( aggregate = "Order" )`},
+			Name:        "OrderStatus",
+			EnumLiterals: []model.EnumLiteral{
+				{Name: "OrderStatusDraft"},
+				{Name: "OrderStatusSubmitted"},
+				{Name: "OrderStatusApproved"},
+				{Name: "OrderStatusRejected"},
+			},
+		},
+	}
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			Name:        "Order",
+			Fields: []model.Field{
+				{Name: "UID", TypeName: "string"},
+				{Name: "Status", TypeName: "OrderStatus"},
+			},
+			Operations: []*model.Operation{
+				{
+					DocLines: []string{`// @Transition( from = "OrderStatusDraft, OrderStatusRejected", to = "OrderStatusSubmitted", event = "OrderSubmitted" )`},
+					Name:     "Submit",
+				},
+				{
+					DocLines: []string{`// @Transition( from = "OrderStatusSubmitted", to = "OrderStatusApproved", event = "OrderApproved" )`},
+					Name:     "Approve",
+				},
+				{
+					DocLines: []string{`// @Transition( from = "OrderStatusSubmitted", to = "OrderStatusRejected" )`},
+					Name:     "Reject",
+				},
+				{
+					Name: "GetUID",
+				},
+			},
+		},
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @Event( aggregate = "Order" )`},
+			Name:        "OrderSubmitted",
+		},
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @Event( aggregate = "Order" )`},
+			Name:        "OrderApproved",
+		},
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @Event( aggregate = "Invoice" )`},
+			Name:        "InvoicePaid",
+		},
+	}
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s, Enums: e}, generator.Config{InputDir: "testData"})
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/stateMachines.go"))
+	assert.NoError(t, err)
+	code := string(data)
+	assert.Contains(t, code, "\tInitialOrderStatus = OrderStatusDraft\n")
+	assert.Contains(t, code, "type OrderTransitionEmitter func(c context.Context, rc request.Context, envlp *envelope.Envelope) error\n")
+	assert.Contains(t, code, `var OrderStatusTransitions = map[OrderStatus][]OrderStatus{
+	OrderStatusDraft:     {OrderStatusSubmitted},
+	OrderStatusSubmitted: {OrderStatusApproved, OrderStatusRejected},
+	OrderStatusApproved:  {},
+	OrderStatusRejected:  {OrderStatusSubmitted},
+}`)
+	assert.Contains(t, code, "func ValidateOrderStatusTransition(transition string, from OrderStatus, to OrderStatus) error {\n")
+	assert.Contains(t, code, `func (a *Order) CanSubmit() error {
+	switch a.Status {
+	case OrderStatusDraft, OrderStatusRejected:
+		return nil
+	}
+	return &IllegalOrderStatusTransition{Transition: "Submit", From: a.Status, To: OrderStatusSubmitted}
+}`)
+	assert.Contains(t, code, `func (a *Order) TransitionApprove(c context.Context, rc request.Context, evt *OrderApproved, emit OrderTransitionEmitter) error {
+	err := a.CanApprove()
+	if err != nil {
+		return err
+	}
+	envlp, err := evt.Wrap(rc)
+	if err != nil {
+		return err
+	}
+	err = emit(c, rc, envlp)
+	if err != nil {
+		return err
+	}
+	a.Status = OrderStatusApproved
+	return nil
+}`)
+	assert.Contains(t, code, "func (a *Order) TransitionReject() error {\n")
+	assert.NotContains(t, code, "GetUID")
+
+	data, err = ioutil.ReadFile(generationUtil.Prefixed("./testData/stateMachineOrderStatus.md"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "```mermaid\nstateDiagram-v2\n"+
+		"    [*] --> OrderStatusDraft\n"+
+		"    OrderStatusDraft --> OrderStatusSubmitted: Submit\n"+
+		"    OrderStatusSubmitted --> OrderStatusApproved: Approve\n"+
+		"    OrderStatusSubmitted --> OrderStatusRejected: Reject\n"+
+		"    OrderStatusApproved --> [*]\n"+
+		"    OrderStatusRejected --> OrderStatusSubmitted: Submit\n"+
+		"```\n")
+}
+
+func TestGenerateStateMachineAsDot(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	e := []model.Enum{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @StateMachine( aggregate = "Order", field = "Status", initial = "OrderStatusSubmitted", diagram = "dot" )`},
+			Name:        "OrderStatus",
+			EnumLiterals: []model.EnumLiteral{
+				{Name: "OrderStatusDraft"},
+				{Name: "OrderStatusSubmitted"},
+				{Name: "OrderStatusApproved"},
+				{Name: "OrderStatusRejected"},
+			},
+		},
+	}
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			Name:        "Order",
+			Fields: []model.Field{
+				{Name: "UID", TypeName: "string"},
+				{Name: "Status", TypeName: "OrderStatus"},
+			},
+			Operations: []*model.Operation{
+				{
+					DocLines: []string{`// @Transition( from = "OrderStatusDraft, OrderStatusRejected", to = "OrderStatusSubmitted", event = "OrderSubmitted" )`},
+					Name:     "Submit",
+				},
+				{
+					DocLines: []string{`// @Transition( from = "OrderStatusSubmitted", to = "OrderStatusApproved", event = "OrderApproved" )`},
+					Name:     "Approve",
+				},
+				{
+					DocLines: []string{`// @Transition( from = "OrderStatusSubmitted", to = "OrderStatusRejected" )`},
+					Name:     "Reject",
+				},
+				{
+					Name: "GetUID",
+				},
+			},
+		},
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @Event( aggregate = "Order" )`},
+			Name:        "OrderSubmitted",
+		},
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @Event( aggregate = "Order" )`},
+			Name:        "OrderApproved",
+		},
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @Event( aggregate = "Invoice" )`},
+			Name:        "InvoicePaid",
+		},
+	}
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s, Enums: e}, generator.Config{InputDir: "testData"})
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/stateMachines.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "\tInitialOrderStatus = OrderStatusSubmitted\n")
+
+	_, err = os.Stat(generationUtil.Prefixed("./testData/stateMachineOrderStatus.md"))
+	assert.True(t, os.IsNotExist(err))
+	data, err = ioutil.ReadFile(generationUtil.Prefixed("./testData/stateMachineOrderStatus.dot"))
+	assert.NoError(t, err)
+	diagram := string(data)
+	assert.Contains(t, diagram, "digraph OrderStatus {\n")
+	assert.Contains(t, diagram, "    OrderStatusApproved [shape=doublecircle];\n")
+	assert.Contains(t, diagram, "    initial -> OrderStatusSubmitted;\n")
+	assert.Contains(t, diagram, "    OrderStatusSubmitted -> OrderStatusApproved [label=\"Approve\"];\n")
+}
+
+func TestGenerateStateMachineWithInvalidAnnotations(t *testing.T) {
+	for _, tc := range []struct {
+		stateMachine string
+		submit       string
+		message      string
+	}{
+		{`// @StateMachine( aggregate = "Invoice" )`, `// @Transition( from = "OrderStatusDraft", to = "OrderStatusSubmitted" )`, "Aggregate Invoice of state-machine OrderStatus is not a struct in package testData"},
+		{`// @StateMachine( aggregate = "Order", field = "State" )`, `// @Transition( from = "OrderStatusDraft", to = "OrderStatusSubmitted" )`, "Aggregate Order of state-machine OrderStatus has no field State of type OrderStatus"},
+		{`// @StateMachine( aggregate = "Order", initial = "OrderStatusNew" )`, `// @Transition( from = "OrderStatusDraft", to = "OrderStatusSubmitted" )`, "Initial state OrderStatusNew of state-machine OrderStatus is not a literal of OrderStatus"},
+		{`// @StateMachine( aggregate = "Order" )`, `// @Transition( from = "OrderStatusDraft", to = "OrderStatusSent" )`, "Transition Order.Submit moves to OrderStatusSent, which is not a state of a state-machine of aggregate Order"},
+		{`// @StateMachine( aggregate = "Order" )`, `// @Transition( from = "OrderStatusNew", to = "OrderStatusSubmitted" )`, "Transition Order.Submit moves from OrderStatusNew, which is not a state of state-machine OrderStatus"},
+		{`// @StateMachine( aggregate = "Order" )`, `// @Transition( from = "OrderStatusDraft", to = "OrderStatusSubmitted", event = "OrderSent" )`, "Transition Order.Submit emits OrderSent, which is not an @Event in package testData"},
+		{`// @StateMachine( aggregate = "Order" )`, `// @Transition( from = "OrderStatusDraft", to = "OrderStatusSubmitted", event = "InvoicePaid" )`, "Transition Order.Submit emits InvoicePaid, which is an event of aggregate Invoice instead of Order"},
+	} {
+		cleanup()
+		e := []model.Enum{
+			{
+				PackageName: "testData",
+				DocLines:    []string{tc.stateMachine},
+				Name:        "OrderStatus",
+				EnumLiterals: []model.EnumLiteral{
+					{Name: "OrderStatusDraft"},
+					{Name: "OrderStatusSubmitted"},
+					{Name: "OrderStatusApproved"},
+					{Name: "OrderStatusRejected"},
+				},
+			},
+		}
+		s := []model.Struct{
+			{
+				PackageName: "testData",
+				Name:        "Order",
+				Fields: []model.Field{
+					{Name: "UID", TypeName: "string"},
+					{Name: "Status", TypeName: "OrderStatus"},
+				},
+				Operations: []*model.Operation{
+					{
+						DocLines: []string{tc.submit},
+						Name:     "Submit",
+					},
+					{
+						DocLines: []string{`// @Transition( from = "OrderStatusSubmitted", to = "OrderStatusApproved", event = "OrderApproved" )`},
+						Name:     "Approve",
+					},
+					{
+						DocLines: []string{`// @Transition( from = "OrderStatusSubmitted", to = "OrderStatusRejected" )`},
+						Name:     "Reject",
+					},
+					{
+						Name: "GetUID",
+					},
+				},
+			},
+			{
+				PackageName: "testData",
+				DocLines:    []string{`// @Event( aggregate = "Order" )`},
+				Name:        "OrderSubmitted",
+			},
+			{
+				PackageName: "testData",
+				DocLines:    []string{`// @Event( aggregate = "Order" )`},
+				Name:        "OrderApproved",
+			},
+			{
+				PackageName: "testData",
+				DocLines:    []string{`// @Event( aggregate = "Invoice" )`},
+				Name:        "InvoicePaid",
+			},
+		}
+		err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s, Enums: e}, generator.Config{InputDir: "testData"})
+		if assert.Error(t, err, tc.message) {
+			assert.Contains(t, err.Error(), tc.message)
+		}
+	}
+	cleanup()
+}
+
+func TestGenerateForNoStateMachine(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	e := []model.Enum{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @JsonEnum()`},
+			Name:        "OrderStatus",
+			EnumLiterals: []model.EnumLiteral{
+				{Name: "OrderStatusDraft"},
+				{Name: "OrderStatusSubmitted"},
+			},
+		},
+	}
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			Name:        "Order",
+			Fields:      []model.Field{{Name: "Status", TypeName: "OrderStatus"}},
+		},
+	}
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s, Enums: e}, generator.Config{InputDir: "testData"})
+	assert.NoError(t, err)
+
+	_, err = os.Stat(generationUtil.Prefixed("./testData/stateMachines.go"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestGenerateForPackageWithOnlyInterfaces(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	i := []model.Interface{
+		{
+			PackageName: "testData",
+			Name:        "OrderService",
+			Methods:     []model.Operation{{Name: "Submit"}},
+		},
+	}
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Interfaces: i}, generator.Config{InputDir: "testData"})
+	assert.NoError(t, err)
+
+	_, err = os.Stat(generationUtil.Prefixed("./testData/stateMachines.go"))
+	assert.True(t, os.IsNotExist(err))
+}
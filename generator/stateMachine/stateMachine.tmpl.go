@@ -0,0 +1,106 @@
+package stateMachine
+
+const stateMachinesTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+
+package {{.PackageName}}
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+{{- range .Machines}}
+	// Initial{{.Name}} is the {{.Field}} of a new {{.Aggregate}}
+	Initial{{.Name}} = {{.Initial}}
+{{- end}}
+)
+{{range .EventAggregates}}
+// {{.}}TransitionEmitter emits the event of a transition of {{.}}, for instance by storing and publishing it
+type {{.}}TransitionEmitter func(c context.Context, rc request.Context, envlp *envelope.Envelope) error
+{{end}}
+{{- range $machine := .Machines}}
+// {{.Name}}Transitions are the states that every state of {{.Name}} can move to
+var {{.Name}}Transitions = map[{{.Name}}][]{{.Name}}{
+{{- range .States}}
+	{{.Name}}: { {{- Join .Targets ", " -}} },
+{{- end}}
+}
+
+var {{LowerCamelCase .Name}}StateNames = map[{{.Name}}]string{
+{{- range .States}}
+	{{.Name}}: "{{.Name}}",
+{{- end}}
+}
+
+// CanTransitionTo tells that state-machine {{.Name}} allows to move from s to next
+func (s {{.Name}}) CanTransitionTo(next {{.Name}}) bool {
+	for _, target := range {{.Name}}Transitions[s] {
+		if target == next {
+			return true
+		}
+	}
+	return false
+}
+
+// Illegal{{.Name}}Transition is the error of a transition of {{.Aggregate}} that its {{.Field}} does not allow
+type Illegal{{.Name}}Transition struct {
+	Transition string
+	From       {{.Name}}
+	To         {{.Name}}
+}
+
+func (e *Illegal{{.Name}}Transition) Error() string {
+	return fmt.Sprintf("Illegal transition %s of {{.Aggregate}} from %s to %s", e.Transition, {{LowerCamelCase .Name}}StateNames[e.From], {{LowerCamelCase .Name}}StateNames[e.To])
+}
+
+// Validate{{.Name}}Transition returns an *Illegal{{.Name}}Transition when state-machine {{.Name}} does not allow to move
+// from one state to the other
+func Validate{{.Name}}Transition(transition string, from {{.Name}}, to {{.Name}}) error {
+	if from.CanTransitionTo(to) {
+		return nil
+	}
+	return &Illegal{{.Name}}Transition{Transition: transition, From: from, To: to}
+}
+{{range .Transitions}}
+// Can{{.Method}} returns an *Illegal{{$machine.Name}}Transition when {{$machine.Aggregate}} cannot {{.Method}} in its current {{$machine.Field}}
+func (a *{{$machine.Aggregate}}) Can{{.Method}}() error {
+	switch a.{{$machine.Field}} {
+	case {{Join .From ", "}}:
+		return nil
+	}
+	return &Illegal{{$machine.Name}}Transition{Transition: "{{.Method}}", From: a.{{$machine.Field}}, To: {{.To}}}
+}
+{{if .Event}}
+// Transition{{.Method}} moves {{$machine.Aggregate}} to {{.To}} after it emitted evt, when its current {{$machine.Field}} allows
+// to {{.Method}}
+func (a *{{$machine.Aggregate}}) Transition{{.Method}}(c context.Context, rc request.Context, evt *{{.Event}}, emit {{$machine.Aggregate}}TransitionEmitter) error {
+	err := a.Can{{.Method}}()
+	if err != nil {
+		return err
+	}
+	envlp, err := evt.Wrap(rc)
+	if err != nil {
+		return err
+	}
+	err = emit(c, rc, envlp)
+	if err != nil {
+		return err
+	}
+	a.{{$machine.Field}} = {{.To}}
+	return nil
+}
+{{else}}
+// Transition{{.Method}} moves {{$machine.Aggregate}} to {{.To}}, when its current {{$machine.Field}} allows to {{.Method}}
+func (a *{{$machine.Aggregate}}) Transition{{.Method}}() error {
+	err := a.Can{{.Method}}()
+	if err != nil {
+		return err
+	}
+	a.{{$machine.Field}} = {{.To}}
+	return nil
+}
+{{end}}
+{{- end}}
+{{- end}}
+`
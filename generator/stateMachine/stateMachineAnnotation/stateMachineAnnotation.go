@@ -0,0 +1,51 @@
+package stateMachineAnnotation
+
+import (
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+)
+
+const (
+	TypeStateMachine = "StateMachine"
+	TypeTransition   = "Transition"
+	ParamAggregate   = "aggregate"
+	ParamField       = "field"
+	ParamInitial     = "initial"
+	ParamDiagram     = "diagram"
+	ParamFrom        = "from"
+	ParamTo          = "to"
+	ParamEvent       = "event"
+	DiagramMermaid   = "mermaid"
+	DiagramDot       = "dot"
+)
+
+func Get() []annotation.AnnotationDescriptor {
+	return []annotation.AnnotationDescriptor{
+		{
+			Name:       TypeStateMachine,
+			ParamNames: []string{ParamAggregate, ParamField, ParamInitial, ParamDiagram},
+			Validator:  validateStateMachineAnnotation,
+		},
+		{
+			Name:       TypeTransition,
+			ParamNames: []string{ParamFrom, ParamTo, ParamEvent},
+			Validator:  validateTransitionAnnotation,
+		}}
+}
+
+func validateStateMachineAnnotation(annot annotation.Annotation) bool {
+	if annot.Name == TypeStateMachine {
+		diagram, hasDiagram := annot.Attributes[ParamDiagram]
+		if hasDiagram && diagram != DiagramMermaid && diagram != DiagramDot {
+			return false
+		}
+		return annot.Attributes[ParamAggregate] != ""
+	}
+	return false
+}
+
+func validateTransitionAnnotation(annot annotation.Annotation) bool {
+	if annot.Name == TypeTransition {
+		return annot.Attributes[ParamFrom] != "" && annot.Attributes[ParamTo] != ""
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package stateMachineAnnotation
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrectStateMachineAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	annotation, ok := registry.ResolveAnnotation(`// @StateMachine( aggregate = "Order", field = "Status", initial = "OrderStatusDraft", diagram = "dot" )`)
+	assert.True(t, ok)
+	assert.Equal(t, TypeStateMachine, annotation.Name)
+	assert.Equal(t, "Order", annotation.Attributes[ParamAggregate])
+	assert.Equal(t, "Status", annotation.Attributes[ParamField])
+	assert.Equal(t, "OrderStatusDraft", annotation.Attributes[ParamInitial])
+	assert.Equal(t, DiagramDot, annotation.Attributes[ParamDiagram])
+
+	_, ok = registry.ResolveAnnotation(`// @StateMachine( aggregate = "Order" )`)
+	assert.True(t, ok)
+}
+
+func TestInvalidStateMachineAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @StateMachine( field = "Status" )`}))
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @StateMachine( aggregate = "Order", diagram = "svg" )`}))
+}
+
+func TestCorrectTransitionAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	annotation, ok := registry.ResolveAnnotation(`// @Transition( from = "OrderStatusDraft,OrderStatusRejected", to = "OrderStatusSubmitted", event = "OrderSubmitted" )`)
+	assert.True(t, ok)
+	assert.Equal(t, TypeTransition, annotation.Name)
+	assert.Equal(t, "OrderStatusDraft,OrderStatusRejected", annotation.Attributes[ParamFrom])
+	assert.Equal(t, "OrderStatusSubmitted", annotation.Attributes[ParamTo])
+	assert.Equal(t, "OrderSubmitted", annotation.Attributes[ParamEvent])
+}
+
+func TestInvalidTransitionAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @Transition( to = "OrderStatusSubmitted" )`}))
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @Transition( from = "OrderStatusDraft" )`}))
+}
@@ -0,0 +1,170 @@
+package stub
+
+import (
+	"fmt"
+	"text/template"
+	"time"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/MarcGrol/golangAnnotations/generator/example"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/generator/rest"
+	"github.com/MarcGrol/golangAnnotations/generator/stub/stubAnnotation"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+const defaultPort = "8080"
+
+type stubContext struct {
+	Service   string
+	Port      string
+	Latency   string
+	ErrorRate string
+	Endpoints []endpoint
+}
+
+// endpoint is the example-response of a rest-operation
+type endpoint struct {
+	Name        string
+	Method      string
+	Path        string
+	Status      string
+	ContentType string
+	Body        string
+}
+
+type Generator struct {
+}
+
+func NewGenerator() generator.Generator {
+	return &Generator{}
+}
+
+func (eg *Generator) Name() string {
+	return "stub"
+}
+
+func (eg *Generator) GetTemplates() []generator.Template {
+	return []generator.Template{
+		{Name: "stub-server", APIVersion: 1, Text: stubTemplate},
+	}
+}
+
+func (eg *Generator) GetAnnotations() []annotation.AnnotationDescriptor {
+	return stubAnnotation.Get()
+}
+
+func (eg *Generator) Generate(parsedSource model.ParsedSources, config generator.Config) ([]generator.OutputFile, error) {
+	return generate(config, parsedSource)
+}
+
+func generate(config generator.Config, parsedSources model.ParsedSources) ([]generator.OutputFile, error) {
+	packageName, err := generationUtil.GetPackageNameForStructs(parsedSources.Structs)
+	if packageName == "" || err != nil {
+		return nil, err
+	}
+	targetDir, err := generationUtil.DetermineTargetPath(config.InputDir, packageName)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []generator.OutputFile{}
+	for _, service := range parsedSources.Structs {
+		ann, ok := annotation.NewRegistry(stubAnnotation.Get()).ResolveAnnotationByName(service.DocLines, stubAnnotation.TypeStub)
+		if !ok {
+			continue
+		}
+		if !rest.IsRestService(service) {
+			return nil, generator.StructError(service, stubAnnotation.TypeStub, "Struct %s with @Stub must be a @RestService", service.Name)
+		}
+
+		// the stub is a command of its own, in a sub-directory of the package
+		stubPackage := ann.Attributes[stubAnnotation.ParamPackage]
+		if stubPackage == "" {
+			stubPackage = generationUtil.LowerCamelCase(service.Name) + "Stub"
+		}
+		file, err := generationUtil.Generate(generationUtil.Info{
+			Src:            fmt.Sprintf("%s.%s", service.PackageName, service.Name),
+			TypeName:       service.Name,
+			TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/%s/stub%s.go", targetDir, stubPackage, rest.ToFirstUpper(service.Name))),
+			TemplateName:   "stub-server",
+			TemplateString: stubTemplate,
+			Config:         config,
+			FuncMap:        customTemplateFuncs,
+			Data:           newStubContext(service, ann, parsedSources),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Error generating stub for service %s:%w", service.Name, err)
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+var customTemplateFuncs = template.FuncMap{
+	"GoStringLiteral": generationUtil.GoStringLiteral,
+}
+
+func newStubContext(service model.Struct, ann annotation.Annotation, parsedSources model.ParsedSources) stubContext {
+	ctx := stubContext{
+		Service:   service.Name,
+		Port:      ann.Attributes[stubAnnotation.ParamPort],
+		Latency:   "0",
+		ErrorRate: ann.Attributes[stubAnnotation.ParamErrorRate],
+	}
+	if ctx.Port == "" {
+		ctx.Port = defaultPort
+	}
+	if ctx.ErrorRate == "" {
+		ctx.ErrorRate = "0"
+	}
+	if latency, err := time.ParseDuration(ann.Attributes[stubAnnotation.ParamLatency]); err == nil {
+		ctx.Latency = durationLiteral(latency)
+	}
+	for _, o := range service.Operations {
+		if rest.IsRestOperation(*o) {
+			ctx.Endpoints = append(ctx.Endpoints, newEndpoint(service, *o, parsedSources))
+		}
+	}
+	return ctx
+}
+
+func newEndpoint(service model.Struct, o model.Operation, parsedSources model.ParsedSources) endpoint {
+	e := endpoint{
+		Name:        o.Name,
+		Method:      rest.GetRestOperationMethod(o),
+		Path:        rest.GetRestServicePath(service) + rest.GetRestOperationPath(o),
+		Status:      "http.StatusOK",
+		ContentType: rest.GetContentType(o),
+	}
+	if rest.IsRestOperationNoContent(o) {
+		e.Status = "http.StatusNoContent"
+	} else if rest.IsRestOperationJSON(o) && rest.HasOutput(o) {
+		e.Body = example.JSON(rest.GetOutputArgType(o), parsedSources)
+	}
+	return e
+}
+
+// durationLiteral returns the go-expression of a duration in its largest whole unit, like 200 * time.Millisecond
+func durationLiteral(d time.Duration) string {
+	if d == 0 {
+		return "0"
+	}
+	units := []struct {
+		unit time.Duration
+		name string
+	}{
+		{time.Hour, "time.Hour"},
+		{time.Minute, "time.Minute"},
+		{time.Second, "time.Second"},
+		{time.Millisecond, "time.Millisecond"},
+		{time.Microsecond, "time.Microsecond"},
+	}
+	for _, u := range units {
+		if d%u.unit == 0 {
+			return fmt.Sprintf("%d * %s", d/u.unit, u.name)
+		}
+	}
+	return fmt.Sprintf("%d", int64(d))
+}
@@ -0,0 +1,127 @@
+package stub
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func cleanup() {
+	os.RemoveAll("./testData/personServiceStub")
+	os.RemoveAll("./testData/personstub")
+}
+
+func TestGenerateStub(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @RestService( path = "/api" )`, `// @Stub( port = "9090", latency = "200ms", errorRate = "0.1" )`},
+			Name:        "PersonService",
+			Operations: []*model.Operation{
+				{
+					DocLines:   []string{`// @RestOperation( method = "GET", path = "/person/{uid}", format = "JSON" )`},
+					Name:       "getPerson",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}},
+					OutputArgs: []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+				},
+				{
+					DocLines:   []string{`// @RestOperation( method = "DELETE", path = "/person/{uid}", format = "no_content" )`},
+					Name:       "removePerson",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}},
+					OutputArgs: []model.Field{{TypeName: "error"}},
+				},
+			},
+		},
+		{
+			PackageName: "testData",
+			Name:        "Person",
+			Fields: []model.Field{
+				{Name: "Name", TypeName: "string", Tag: "`json:\"name\"`", DocLines: []string{`// @Example( value = "John Doe" )`}},
+				{Name: "Age", TypeName: "int", Tag: "`json:\"age\"`"},
+			},
+		},
+	}
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/personServiceStub/stubPersonService.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "package main\n")
+	assert.Contains(t, string(data), `port        = flag.Int("port", 9090, "port to listen on")`)
+	assert.Contains(t, string(data), `latency     = flag.Duration("latency", 200*time.Millisecond, "delay of every response")`)
+	assert.Contains(t, string(data), `errorRate   = flag.Float64("error-rate", 0.1, `)
+	assert.Contains(t, string(data), `router.HandleFunc("/api/person/{uid}", stub(http.StatusOK, "application/json", getPersonExample)).Methods("GET")`)
+	assert.Contains(t, string(data), `router.HandleFunc("/api/person/{uid}", stub(http.StatusNoContent, "", removePersonExample)).Methods("DELETE")`)
+	assert.Contains(t, string(data), "const getPersonExample = `{\n  \"name\": \"John Doe\",\n  \"age\": 1\n}`")
+	assert.Contains(t, string(data), "const removePersonExample = ``")
+}
+
+func TestGenerateStubInPackage(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @RestService( path = "/api" )`, `// @Stub( package = "personstub" )`},
+			Name:        "PersonService",
+			Operations: []*model.Operation{
+				{
+					DocLines:   []string{`// @RestOperation( method = "DELETE", path = "/person/{uid}", format = "no_content" )`},
+					Name:       "removePerson",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}},
+					OutputArgs: []model.Field{{TypeName: "error"}},
+				},
+			},
+		},
+	}
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/personstub/stubPersonService.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `flag.Int("port", 8080, `)
+	assert.Contains(t, string(data), `flag.Duration("latency", 0, `)
+	assert.Contains(t, string(data), `flag.Float64("error-rate", 0, `)
+}
+
+func TestGenerateStubForNoRestService(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @Stub()`},
+			Name:        "PersonService",
+			Operations: []*model.Operation{
+				{
+					DocLines:   []string{`// @RestOperation( method = "DELETE", path = "/person/{uid}", format = "no_content" )`},
+					Name:       "removePerson",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}},
+					OutputArgs: []model.Field{{TypeName: "error"}},
+				},
+			},
+		},
+	}
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Struct PersonService with @Stub must be a @RestService")
+}
+
+func TestDurationLiteral(t *testing.T) {
+	assert.Equal(t, "0", durationLiteral(0))
+	assert.Equal(t, "2 * time.Second", durationLiteral(2*time.Second))
+	assert.Equal(t, "1500 * time.Millisecond", durationLiteral(1500*time.Millisecond))
+	assert.Equal(t, "90 * time.Minute", durationLiteral(90*time.Minute))
+	assert.Equal(t, "15", durationLiteral(15))
+}
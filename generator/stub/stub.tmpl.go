@@ -0,0 +1,96 @@
+package stub
+
+const stubTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+
+// Stub of {{.Service}}: serves example-responses of all its operations, so that clients can be developed before the
+// service exists. The X-Stub-Status and X-Stub-Latency headers of a request override the status and latency of its
+// response.
+package main
+
+import (
+	"flag"
+	"log"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+var (
+	port        = flag.Int("port", {{.Port}}, "port to listen on")
+	latency     = flag.Duration("latency", {{.Latency}}, "delay of every response")
+	errorRate   = flag.Float64("error-rate", {{.ErrorRate}}, "fraction of requests, between 0 and 1, that fail")
+	errorStatus = flag.Int("error-status", http.StatusInternalServerError, "status of the requests that fail")
+)
+
+func main() {
+	flag.Parse()
+	log.Printf("Stub of {{.Service}} listening on port %d", *port)
+	log.Fatal(http.ListenAndServe(":"+strconv.Itoa(*port), stubHandler()))
+}
+
+func stubHandler() http.Handler {
+	router := mux.NewRouter()
+{{- range .Endpoints}}
+	router.HandleFunc("{{.Path}}", stub({{.Status}}, "{{.ContentType}}", {{.Name}}Example)).Methods("{{.Method}}")
+{{- end}}
+	return allowCORS(router)
+}
+{{range .Endpoints}}
+// {{.Name}}Example is the response of {{.Method}} {{.Path}}
+const {{.Name}}Example = {{GoStringLiteral .Body}}
+{{end}}
+// stub responds with body after the latency, or with an injected error for the fraction error-rate of the requests
+func stub(status int, contentType string, body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		delay := *latency
+		if value := r.Header.Get("X-Stub-Latency"); value != "" {
+			parsed, err := time.ParseDuration(value)
+			if err != nil {
+				http.Error(w, "Invalid X-Stub-Latency: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+			delay = parsed
+		}
+		time.Sleep(delay)
+
+		if value := r.Header.Get("X-Stub-Status"); value != "" {
+			forced, err := strconv.Atoi(value)
+			if err != nil {
+				http.Error(w, "Invalid X-Stub-Status: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+			if forced >= http.StatusBadRequest {
+				http.Error(w, "Injected error", forced)
+				return
+			}
+			status = forced
+		} else if rand.Float64() < *errorRate {
+			http.Error(w, "Injected error", *errorStatus)
+			return
+		}
+
+		if contentType != "" {
+			w.Header().Set("Content-Type", contentType)
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}
+}
+
+// allowCORS lets web-applications on other origins call the stub
+func allowCORS(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		if r.Method == http.MethodOptions {
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", "*")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+`
@@ -0,0 +1,47 @@
+package stubAnnotation
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+)
+
+const (
+	TypeStub       = "Stub"
+	ParamPackage   = "package"
+	ParamPort      = "port"
+	ParamLatency   = "latency"
+	ParamErrorRate = "errorrate"
+)
+
+func Get() []annotation.AnnotationDescriptor {
+	return []annotation.AnnotationDescriptor{
+		{
+			Name:       TypeStub,
+			ParamNames: []string{ParamPackage, ParamPort, ParamLatency, ParamErrorRate},
+			Validator:  validateStubAnnotation,
+		}}
+}
+
+func validateStubAnnotation(annot annotation.Annotation) bool {
+	if annot.Name == TypeStub {
+		if port, found := annot.Attributes[ParamPort]; found {
+			if number, err := strconv.Atoi(port); err != nil || number <= 0 {
+				return false
+			}
+		}
+		if latency, found := annot.Attributes[ParamLatency]; found {
+			if duration, err := time.ParseDuration(latency); err != nil || duration < 0 {
+				return false
+			}
+		}
+		if errorRate, found := annot.Attributes[ParamErrorRate]; found {
+			if rate, err := strconv.ParseFloat(errorRate, 64); err != nil || rate < 0 || rate > 1 {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,27 @@
+package stubAnnotation
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrectStubAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	annotation, ok := registry.ResolveAnnotation(`// @Stub( package = "personstub", port = "9090", latency = "200ms", errorRate = "0.1" )`)
+	assert.True(t, ok)
+	assert.Equal(t, TypeStub, annotation.Name)
+	assert.Equal(t, "personstub", annotation.Attributes[ParamPackage])
+	assert.Equal(t, "200ms", annotation.Attributes[ParamLatency])
+	assert.Equal(t, "0.1", annotation.Attributes[ParamErrorRate])
+}
+
+func TestInvalidStubAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @Stub( port = "http" )`}))
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @Stub( latency = "slow" )`}))
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @Stub( errorRate = "1.5" )`}))
+}
@@ -0,0 +1,256 @@
+package tags
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/MarcGrol/golangAnnotations/generator/entity"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/generator/tags/tagsAnnotation"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+// Generator does not create new files: it rewrites the struct-tags of @Tags-structs in their
+// own source-file, using the go/ast representation so that all other code is left untouched.
+type Generator struct {
+}
+
+func NewGenerator() generator.Generator {
+	return &Generator{}
+}
+
+func (eg *Generator) Name() string {
+	return "tags"
+}
+
+func (eg *Generator) GetAnnotations() []annotation.AnnotationDescriptor {
+	return tagsAnnotation.Get()
+}
+
+func (eg *Generator) Generate(parsedSource model.ParsedSources, config generator.Config) ([]generator.OutputFile, error) {
+	return generate(parsedSource.Structs)
+}
+
+// generate returns the rewritten versions of the source-files that contain tagged structs
+func generate(structs []model.Struct) ([]generator.OutputFile, error) {
+	structsPerFile := map[string]map[string]model.Struct{}
+	for _, s := range structs {
+		if !IsTagged(s) {
+			continue
+		}
+		if _, found := structsPerFile[s.Filename]; !found {
+			structsPerFile[s.Filename] = map[string]model.Struct{}
+		}
+		structsPerFile[s.Filename][s.Name] = s
+	}
+
+	filenames := make([]string, 0, len(structsPerFile))
+	for filename := range structsPerFile {
+		filenames = append(filenames, filename)
+	}
+	sort.Strings(filenames)
+
+	files := []generator.OutputFile{}
+	for _, filename := range filenames {
+		file, changed, err := rewriteFile(filename, structsPerFile[filename])
+		if err != nil {
+			return nil, err
+		}
+		if changed {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+func IsTagged(s model.Struct) bool {
+	annotations := annotation.NewRegistry(tagsAnnotation.Get())
+	_, ok := annotations.ResolveAnnotationByName(s.DocLines, tagsAnnotation.TypeTags)
+	return ok
+}
+
+// GetTagKeys returns the struct-tag keys that are maintained for the struct
+func GetTagKeys(s model.Struct) []string {
+	annotations := annotation.NewRegistry(tagsAnnotation.Get())
+	if ann, ok := annotations.ResolveAnnotationByName(s.DocLines, tagsAnnotation.TypeTags); ok {
+		if keys := ann.Attributes[tagsAnnotation.ParamKeys]; keys != "" {
+			result := []string{}
+			for _, key := range strings.Split(keys, ",") {
+				result = append(result, strings.TrimSpace(key))
+			}
+			return result
+		}
+	}
+	return []string{tagsAnnotation.KeyJSON}
+}
+
+func isOverwrite(s model.Struct) bool {
+	annotations := annotation.NewRegistry(tagsAnnotation.Get())
+	if ann, ok := annotations.ResolveAnnotationByName(s.DocLines, tagsAnnotation.TypeTags); ok {
+		return ann.Attributes[tagsAnnotation.ParamOverwrite] == "true"
+	}
+	return false
+}
+
+func rewriteFile(filename string, structs map[string]model.Struct) (generator.OutputFile, bool, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, nil, parser.ParseComments)
+	if err != nil {
+		return generator.OutputFile{}, false, fmt.Errorf("Error parsing file %s:%w", filename, err)
+	}
+
+	changed := false
+	ast.Inspect(file, func(node ast.Node) bool {
+		if err != nil {
+			return false
+		}
+		typeSpec, ok := node.(*ast.TypeSpec)
+		if !ok {
+			return true
+		}
+		structType, ok := typeSpec.Type.(*ast.StructType)
+		if !ok {
+			return true
+		}
+		s, found := structs[typeSpec.Name.Name]
+		if !found {
+			return true
+		}
+		var structChanged bool
+		structChanged, err = rewriteStruct(s, structType)
+		changed = changed || structChanged
+		return true
+	})
+	if err != nil {
+		return generator.OutputFile{}, false, err
+	}
+	if !changed {
+		return generator.OutputFile{}, false, nil
+	}
+
+	var buf bytes.Buffer
+	err = format.Node(&buf, fset, file)
+	if err != nil {
+		return generator.OutputFile{}, false, fmt.Errorf("Error formatting file %s:%w", filename, err)
+	}
+	return generator.OutputFile{
+		Filename: filename,
+		Src:      filename,
+		Fixed:    true,
+		Content:  buf.Bytes(),
+	}, true, nil
+}
+
+func rewriteStruct(s model.Struct, structType *ast.StructType) (bool, error) {
+	changed := false
+	for _, astField := range structType.Fields.List {
+		// embedded fields and multiple names sharing one tag are left alone
+		if len(astField.Names) != 1 || !astField.Names[0].IsExported() {
+			continue
+		}
+		field, found := findField(s, astField.Names[0].Name)
+		if !found {
+			continue
+		}
+
+		existing := ""
+		if astField.Tag != nil {
+			unquoted, err := strconv.Unquote(astField.Tag.Value)
+			if err != nil {
+				return false, generator.FieldError(s, field, "", "Invalid tag of field %s.%s:%s", s.Name, field.Name, err)
+			}
+			existing = unquoted
+		}
+		tag, err := parseStructTag(existing)
+		if err != nil {
+			return false, generator.FieldError(s, field, "", "Invalid tag of field %s.%s:%s", s.Name, field.Name, err)
+		}
+		for _, key := range GetTagKeys(s) {
+			value, explicit := GetTagValue(s, field, key)
+			if _, exists := tag.get(key); exists && !explicit && !isOverwrite(s) {
+				continue
+			}
+			tag.set(key, value)
+		}
+
+		rewritten := tag.String()
+		if rewritten == existing {
+			continue
+		}
+		if strings.Contains(rewritten, "`") {
+			rewritten = strconv.Quote(rewritten)
+		} else {
+			rewritten = "`" + rewritten + "`"
+		}
+		if astField.Tag == nil {
+			astField.Tag = &ast.BasicLit{Kind: token.STRING, ValuePos: astField.Type.End()}
+		}
+		astField.Tag.Value = rewritten
+		changed = true
+	}
+	return changed, nil
+}
+
+func findField(s model.Struct, name string) (model.Field, bool) {
+	for _, f := range s.Fields {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return model.Field{}, false
+}
+
+// GetTagValue returns the value of a tag-key for a field and whether it was explicitly specified using @Tag
+func GetTagValue(s model.Struct, f model.Field, key string) (string, bool) {
+	annotations := annotation.NewRegistry(tagsAnnotation.Get())
+	if ann, ok := annotations.ResolveAnnotationByName(f.DocLines, tagsAnnotation.TypeTag); ok {
+		if ann.Attributes[tagsAnnotation.ParamSkip] == "true" {
+			return "-", true
+		}
+		if value, found := ann.Attributes[key]; found {
+			return value, true
+		}
+	}
+
+	// the fields of an entity that its repository does not store, are no column
+	column, stored := entity.Column{Name: generationUtil.SnakeCase(f.Name)}, true
+	if entity.IsEntity(s) {
+		stored = false
+		for _, c := range entity.GetColumns(s) {
+			if c.Field.Name == f.Name {
+				column, stored = c, true
+			}
+		}
+	}
+
+	switch key {
+	case tagsAnnotation.KeyDB:
+		if !stored {
+			return "-", false
+		}
+		return column.Name, false
+	case tagsAnnotation.KeyGorm:
+		if !stored {
+			return "-", false
+		}
+		value := "column:" + column.Name
+		if column.IsID {
+			value += ";primaryKey"
+		}
+		if column.IsAuto {
+			value += ";autoIncrement"
+		}
+		return value, false
+	default:
+		return generationUtil.LowerCamelCase(f.Name), false
+	}
+}
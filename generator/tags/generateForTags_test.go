@@ -0,0 +1,100 @@
+package tags
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+const personSource = `package testData
+
+// @Entity()
+// @Tags( keys = "json,db,gorm" )
+type Person struct {
+	// @Id( auto = "true" )
+	ID        int64
+	FirstName string ` + "`json:\"first\"`" + `
+	// @Tag( json = "-" )
+	Password string
+	Roles    []string
+	internal string
+}
+
+type Other struct {
+	Name string
+}
+`
+
+func cleanup() {
+	os.Remove("./testData/person.go")
+}
+
+func TestGenerateForTags(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	os.MkdirAll("./testData", 0777)
+	err := ioutil.WriteFile("./testData/person.go", []byte(personSource), 0644)
+	assert.NoError(t, err)
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			Filename:    "./testData/person.go",
+			DocLines:    []string{`// @Entity()`, `// @Tags( keys = "json,db,gorm" )`},
+			Name:        "Person",
+			Fields: []model.Field{
+				{Name: "ID", TypeName: "int64", DocLines: []string{`// @Id( auto = "true" )`}},
+				{Name: "FirstName", TypeName: "string", Tag: "json:\"first\""},
+				{Name: "Password", TypeName: "string", DocLines: []string{`// @Tag( json = "-" )`}},
+				{Name: "Roles", TypeName: "[]string"},
+				{Name: "internal", TypeName: "string"},
+			},
+		},
+		{
+			PackageName: "testData",
+			Filename:    "./testData/person.go",
+			Name:        "Other",
+			Fields:      []model.Field{{Name: "Name", TypeName: "string"}},
+		},
+	}
+
+	err = generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Nil(t, err)
+
+	data, err := ioutil.ReadFile("./testData/person.go")
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "ID        int64  `json:\"id\" db:\"id\" gorm:\"column:id;primaryKey;autoIncrement\"`")
+	assert.Contains(t, string(data), "FirstName string `json:\"first\" db:\"first_name\" gorm:\"column:first_name\"`")
+	assert.Contains(t, string(data), "Password string   `json:\"-\" db:\"password\" gorm:\"column:password\"`")
+	// the repository of the entity does not store a slice
+	assert.Contains(t, string(data), "Roles    []string `json:\"roles\" db:\"-\" gorm:\"-\"`")
+	assert.Contains(t, string(data), "internal string\n")
+	assert.Contains(t, string(data), "Name string\n")
+
+	// a second pass leaves the file as is
+	err = generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Nil(t, err)
+	again, err := ioutil.ReadFile("./testData/person.go")
+	assert.NoError(t, err)
+	assert.Equal(t, string(data), string(again))
+}
+
+func TestParseStructTag(t *testing.T) {
+	tag, err := parseStructTag(`json:"name,omitempty" db:"na\"me"`)
+	assert.NoError(t, err)
+	value, found := tag.get("db")
+	assert.True(t, found)
+	assert.Equal(t, `na"me`, value)
+
+	tag.set("json", "other")
+	tag.set("gorm", "column:name")
+	assert.Equal(t, `json:"other" db:"na\"me" gorm:"column:name"`, tag.String())
+
+	_, err = parseStructTag(`json:name`)
+	assert.Error(t, err)
+}
@@ -0,0 +1,82 @@
+package tags
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type tagEntry struct {
+	key   string
+	value string
+}
+
+// structTag keeps the key-value pairs of a struct-tag in their original order
+type structTag []tagEntry
+
+// parseStructTag follows the conventions of reflect.StructTag: key:"value" pairs separated by spaces
+func parseStructTag(tag string) (structTag, error) {
+	entries := structTag{}
+	for tag != "" {
+		tag = strings.TrimLeft(tag, " ")
+		if tag == "" {
+			break
+		}
+
+		i := 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			return nil, fmt.Errorf("Invalid struct-tag syntax:%s", tag)
+		}
+		key := tag[:i]
+		tag = tag[i+1:]
+
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			return nil, fmt.Errorf("Unterminated value for struct-tag key %s", key)
+		}
+		value, err := strconv.Unquote(tag[:i+1])
+		if err != nil {
+			return nil, fmt.Errorf("Invalid value for struct-tag key %s:%w", key, err)
+		}
+		tag = tag[i+1:]
+
+		entries = append(entries, tagEntry{key: key, value: value})
+	}
+	return entries, nil
+}
+
+func (t structTag) get(key string) (string, bool) {
+	for _, e := range t {
+		if e.key == key {
+			return e.value, true
+		}
+	}
+	return "", false
+}
+
+func (t *structTag) set(key string, value string) {
+	for idx, e := range *t {
+		if e.key == key {
+			(*t)[idx].value = value
+			return
+		}
+	}
+	*t = append(*t, tagEntry{key: key, value: value})
+}
+
+func (t structTag) String() string {
+	parts := make([]string, 0, len(t))
+	for _, e := range t {
+		parts = append(parts, fmt.Sprintf("%s:%s", e.key, strconv.Quote(e.value)))
+	}
+	return strings.Join(parts, " ")
+}
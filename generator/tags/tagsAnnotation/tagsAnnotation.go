@@ -0,0 +1,58 @@
+package tagsAnnotation
+
+import (
+	"strings"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+)
+
+const (
+	TypeTags       = "Tags"
+	TypeTag        = "Tag"
+	ParamKeys      = "keys"
+	ParamOverwrite = "overwrite"
+	ParamSkip      = "skip"
+	KeyJSON        = "json"
+	KeyDB          = "db"
+	KeyGorm        = "gorm"
+)
+
+func Get() []annotation.AnnotationDescriptor {
+	return []annotation.AnnotationDescriptor{
+		{
+			Name:       TypeTags,
+			ParamNames: []string{ParamKeys, ParamOverwrite},
+			Validator:  validateTagsAnnotation,
+		},
+		{
+			// every other attribute of @Tag is taken as explicit value for the tag-key with the same name
+			Name:       TypeTag,
+			ParamNames: []string{ParamSkip, KeyJSON, KeyDB, KeyGorm},
+			Validator:  validateTagAnnotation,
+		}}
+}
+
+func validateTagsAnnotation(annot annotation.Annotation) bool {
+	if annot.Name == TypeTags {
+		keys, hasKeys := annot.Attributes[ParamKeys]
+		if !hasKeys {
+			return true
+		}
+		for _, key := range strings.Split(keys, ",") {
+			switch strings.TrimSpace(key) {
+			case KeyJSON, KeyDB, KeyGorm:
+			default:
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func validateTagAnnotation(annot annotation.Annotation) bool {
+	if annot.Name == TypeTag {
+		return true
+	}
+	return false
+}
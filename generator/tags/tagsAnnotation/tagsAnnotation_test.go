@@ -0,0 +1,33 @@
+package tagsAnnotation
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrectTagsAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	annotation, ok := registry.ResolveAnnotation(`// @Tags( keys = "json,db,gorm" )`)
+	assert.True(t, ok)
+	assert.Equal(t, TypeTags, annotation.Name)
+	assert.Equal(t, "json,db,gorm", annotation.Attributes[ParamKeys])
+}
+
+func TestTagsAnnotationWithUnknownKey(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	_, ok := registry.ResolveAnnotation(`// @Tags( keys = "json,yaml" )`)
+	assert.False(t, ok)
+}
+
+func TestCorrectTagAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	annotation, ok := registry.ResolveAnnotation(`// @Tag( json = "name,omitempty" )`)
+	assert.True(t, ok)
+	assert.Equal(t, TypeTag, annotation.Name)
+	assert.Equal(t, "name,omitempty", annotation.Attributes[KeyJSON])
+}
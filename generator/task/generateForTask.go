@@ -0,0 +1,230 @@
+package task
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/generator/rest"
+	"github.com/MarcGrol/golangAnnotations/generator/task/taskAnnotation"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+const (
+	defaultAttempts   = 5
+	defaultBackoff    = 10 * time.Second
+	defaultMaxBackoff = 5 * time.Minute
+)
+
+type tasksContext struct {
+	PackageName string
+	// Receivers are the structs with task-methods, that the worker needs an instance of
+	Receivers []receiver
+	Tasks     []task
+}
+
+type receiver struct {
+	Name     string
+	TypeName string
+}
+
+// task is an operation with @Task, that the worker calls with the arguments that it was enqueued with
+type task struct {
+	Name      string
+	Operation string
+	// Call is the go-expression of the operation, like mailService.SendWelcome
+	Call       string
+	Args       []argument
+	Attempts   int
+	Backoff    string
+	MaxBackoff string
+	Timeout    string
+}
+
+// argument is an argument of a task after its context, as field of the json of the task
+type argument struct {
+	Name     string
+	Field    string
+	JSONName string
+	TypeName string
+}
+
+type Generator struct {
+}
+
+func NewGenerator() generator.Generator {
+	return &Generator{}
+}
+
+func (eg *Generator) Name() string {
+	return "task"
+}
+
+func (eg *Generator) GetTemplates() []generator.Template {
+	return []generator.Template{
+		{Name: "tasks", APIVersion: 1, Text: tasksTemplate},
+		{Name: "task-queues", APIVersion: 1, Text: taskQueuesTemplate},
+	}
+}
+
+func (eg *Generator) GetAnnotations() []annotation.AnnotationDescriptor {
+	return taskAnnotation.Get()
+}
+
+func (eg *Generator) Generate(parsedSource model.ParsedSources, config generator.Config) ([]generator.OutputFile, error) {
+	return generate(config, parsedSource.Structs, parsedSource.Operations)
+}
+
+func generate(config generator.Config, structs []model.Struct, operations []model.Operation) ([]generator.OutputFile, error) {
+	packageName, err := generationUtil.GetPackageNameForStructs(structs)
+	if err != nil {
+		return nil, err
+	}
+	if packageName == "" && len(operations) > 0 {
+		// a package can have task-functions only
+		packageName = operations[0].PackageName
+	}
+	if packageName == "" {
+		return nil, nil
+	}
+	targetDir, err := generationUtil.DetermineTargetPath(config.InputDir, packageName)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := tasksContext{PackageName: packageName}
+	for _, s := range structs {
+		hasTasks := false
+		for _, o := range s.Operations {
+			t, ok, err := newTask(*o, s.Name, ctx.Tasks)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				t.Call = fmt.Sprintf("%s.%s", generationUtil.LowerCamelCase(s.Name), o.Name)
+				ctx.Tasks = append(ctx.Tasks, t)
+				hasTasks = true
+			}
+		}
+		if hasTasks {
+			ctx.Receivers = append(ctx.Receivers, receiver{Name: generationUtil.LowerCamelCase(s.Name), TypeName: s.Name})
+		}
+	}
+	for _, o := range operations {
+		// methods are handled with their struct
+		if o.RelatedStruct != nil {
+			continue
+		}
+		t, ok, err := newTask(o, "", ctx.Tasks)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			t.Call = o.Name
+			ctx.Tasks = append(ctx.Tasks, t)
+		}
+	}
+	if len(ctx.Tasks) == 0 {
+		return nil, nil
+	}
+
+	files := []generator.OutputFile{}
+	for _, f := range []struct {
+		templateName   string
+		templateString string
+		filename       string
+	}{
+		{"tasks", tasksTemplate, "tasks.go"},
+		{"task-queues", taskQueuesTemplate, "taskQueues.go"},
+	} {
+		file, err := generationUtil.Generate(generationUtil.Info{
+			Src:            packageName,
+			TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/%s", targetDir, f.filename)),
+			TemplateName:   f.templateName,
+			TemplateString: f.templateString,
+			Config:         config,
+			FuncMap:        customTemplateFuncs,
+			Data:           ctx,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Error generating %s for package %s:%w", f.templateName, packageName, err)
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+var customTemplateFuncs = template.FuncMap{
+	"Quote":          strconv.Quote,
+	"LowerCamelCase": generationUtil.LowerCamelCase,
+}
+
+// newTask returns the task of an operation with @Task, which must get a context and arguments that can be
+// marshalled to json, and return an error only. Owner is the struct of a method and empty for functions.
+func newTask(o model.Operation, owner string, others []task) (task, bool, error) {
+	ann, ok := annotation.NewRegistry(taskAnnotation.Get()).ResolveAnnotationByName(o.DocLines, taskAnnotation.TypeTask)
+	if !ok {
+		return task{}, false, nil
+	}
+	operation := o.Name
+	if owner != "" {
+		operation = owner + "." + o.Name
+	}
+	if len(o.InputArgs) == 0 || o.InputArgs[0].TypeName != "context.Context" || len(o.OutputArgs) != 1 || o.OutputArgs[0].TypeName != "error" {
+		return task{}, false, generator.OperationError(o, owner, taskAnnotation.TypeTask, "Task %s must get a context.Context as first argument and return an error only", operation)
+	}
+
+	t := task{
+		Name:       ann.Attributes[taskAnnotation.ParamName],
+		Operation:  operation,
+		Attempts:   defaultAttempts,
+		Backoff:    generationUtil.DurationLiteral(defaultBackoff),
+		MaxBackoff: generationUtil.DurationLiteral(defaultMaxBackoff),
+	}
+	if t.Name == "" {
+		t.Name = rest.ToFirstUpper(o.Name)
+	}
+	for _, other := range others {
+		if other.Name == t.Name {
+			return task{}, false, generator.OperationError(o, owner, taskAnnotation.TypeTask, "Task %s has the same name %s as task %s: give one of them another name", operation, t.Name, other.Operation)
+		}
+	}
+	for _, arg := range o.InputArgs[1:] {
+		if arg.Name == "" || arg.Name == "_" || strings.HasPrefix(arg.TypeName, "...") || arg.TypeName == "context.Context" {
+			return task{}, false, generator.OperationError(o, owner, taskAnnotation.TypeTask, "Argument %s of task %s cannot be enqueued: it must be named, not variadic and no context", arg.TypeName, operation)
+		}
+		t.Args = append(t.Args, argument{
+			Name:     arg.Name,
+			Field:    rest.ToFirstUpper(arg.Name),
+			JSONName: arg.Name,
+			TypeName: arg.TypeName,
+		})
+	}
+
+	// the annotation is only valid with a positive number of attempts and positive durations
+	if attempts, found := ann.Attributes[taskAnnotation.ParamAttempts]; found {
+		t.Attempts, _ = strconv.Atoi(attempts)
+	}
+	backoff, maxBackoff := defaultBackoff, defaultMaxBackoff
+	if value, found := ann.Attributes[taskAnnotation.ParamBackoff]; found {
+		backoff, _ = time.ParseDuration(value)
+		t.Backoff = generationUtil.DurationLiteral(backoff)
+	}
+	if value, found := ann.Attributes[taskAnnotation.ParamMaxBackoff]; found {
+		maxBackoff, _ = time.ParseDuration(value)
+		t.MaxBackoff = generationUtil.DurationLiteral(maxBackoff)
+	}
+	if maxBackoff < backoff {
+		return task{}, false, generator.OperationError(o, owner, taskAnnotation.TypeTask, "Task %s has a maxbackoff %s that is shorter than its backoff %s", operation, maxBackoff, backoff)
+	}
+	if value, found := ann.Attributes[taskAnnotation.ParamTimeout]; found {
+		timeout, _ := time.ParseDuration(value)
+		t.Timeout = generationUtil.DurationLiteral(timeout)
+	}
+	return t, true, nil
+}
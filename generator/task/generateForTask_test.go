@@ -0,0 +1,181 @@
+package task
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func cleanup() {
+	os.Remove(generationUtil.Prefixed("./testData/tasks.go"))
+	os.Remove(generationUtil.Prefixed("./testData/taskQueues.go"))
+	os.Remove("./testData")
+}
+
+func TestGenerateTasks(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			Name:        "MailService",
+			Operations: []*model.Operation{
+				{
+					DocLines:   []string{`// @Task( name = "WelcomeMail", attempts = "10", backoff = "30s", maxbackoff = "1h", timeout = "2m" )`},
+					Name:       "sendWelcome",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "email", TypeName: "string"}, {Name: "person", TypeName: "Person"}},
+					OutputArgs: []model.Field{{TypeName: "error"}},
+				},
+				{
+					Name:       "render",
+					InputArgs:  []model.Field{{Name: "person", TypeName: "Person"}},
+					OutputArgs: []model.Field{{TypeName: "string"}},
+				},
+			},
+		},
+		{
+			PackageName: "testData",
+			Name:        "Person",
+		},
+	}
+	o := []model.Operation{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @Task()`},
+			Name:        "purgeMailbox",
+			InputArgs:   []model.Field{{Name: "c", TypeName: "context.Context"}},
+			OutputArgs:  []model.Field{{TypeName: "error"}},
+		},
+	}
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s, Operations: o}, generator.Config{InputDir: "testData"})
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/tasks.go"))
+	assert.NoError(t, err)
+	code := string(data)
+	assert.Contains(t, code, "\tWelcomeMailTaskName  = \"testData.WelcomeMail\"\n")
+	assert.Contains(t, code, "\tPurgeMailboxTaskName = \"testData.PurgeMailbox\"\n")
+	assert.Contains(t, code, "type welcomeMailTaskArgs struct {\n\tEmail  string `json:\"email\"`\n\tPerson Person `json:\"person\"`\n}")
+	assert.Contains(t, code, `// EnqueueWelcomeMail enqueues a call of MailService.sendWelcome, that a TaskWorker makes
+func EnqueueWelcomeMail(c context.Context, email string, person Person) error {
+	return enqueueTask(c, WelcomeMailTaskName, welcomeMailTaskArgs{Email: email, Person: person})
+}`)
+	assert.Contains(t, code, "func EnqueuePurgeMailbox(c context.Context) error {\n\treturn enqueueTask(c, PurgeMailboxTaskName, purgeMailboxTaskArgs{})\n}")
+	assert.Contains(t, code, "func NewTaskWorker(mailService *MailService) *TaskWorker {\n")
+	assert.Contains(t, code, "\t\t\t\toptions: taskOptions{attempts: 10, backoff: 30 * time.Second, maxBackoff: 1 * time.Hour, timeout: 2 * time.Minute},\n")
+	assert.Contains(t, code, "\t\t\t\t\treturn mailService.sendWelcome(c, args.Email, args.Person)\n")
+	assert.Contains(t, code, "\t\t\t\toptions: taskOptions{attempts: 5, backoff: 10 * time.Second, maxBackoff: 5 * time.Minute},\n")
+	assert.Contains(t, code, "\t\t\t\t\treturn purgeMailbox(c)\n")
+	assert.NotContains(t, code, "render")
+
+	data, err = ioutil.ReadFile(generationUtil.Prefixed("./testData/taskQueues.go"))
+	assert.NoError(t, err)
+	queues := string(data)
+	assert.Contains(t, queues, "func NewMemoryTaskQueue() PullTaskQueue {\n")
+	assert.Contains(t, queues, "func NewCloudTasksQueue(queue string, url string, token func(c context.Context) (string, error)) TaskQueue {\n")
+	assert.Contains(t, queues, "func NewRedisTaskQueue(client RedisClient, key string) PullTaskQueue {\n")
+}
+
+func TestGenerateTasksWithDefaultName(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			Name:        "MailService",
+			Operations: []*model.Operation{
+				{
+					DocLines:   []string{`// @Task()`},
+					Name:       "sendWelcome",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "email", TypeName: "string"}, {Name: "person", TypeName: "Person"}},
+					OutputArgs: []model.Field{{TypeName: "error"}},
+				},
+			},
+		},
+	}
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/tasks.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "func EnqueueSendWelcome(c context.Context, email string, person Person) error {\n")
+}
+
+func TestGenerateTasksWithSameName(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			Name:        "MailService",
+			Operations: []*model.Operation{
+				{
+					DocLines:   []string{`// @Task( name = "PurgeMailbox" )`},
+					Name:       "sendWelcome",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}},
+					OutputArgs: []model.Field{{TypeName: "error"}},
+				},
+			},
+		},
+	}
+	o := []model.Operation{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @Task()`},
+			Name:        "purgeMailbox",
+			InputArgs:   []model.Field{{Name: "c", TypeName: "context.Context"}},
+			OutputArgs:  []model.Field{{TypeName: "error"}},
+		},
+	}
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s, Operations: o}, generator.Config{InputDir: "testData"})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "Task purgeMailbox has the same name PurgeMailbox as task MailService.sendWelcome")
+	}
+}
+
+func TestGenerateTasksWithInvalidOperations(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	for message, operation := range map[string]model.Operation{
+		"Task MailService.sendWelcome has a maxbackoff 30s that is shorter than its backoff 1m0s": {
+			DocLines:   []string{`// @Task( backoff = "1m", maxbackoff = "30s" )`},
+			Name:       "sendWelcome",
+			InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}},
+			OutputArgs: []model.Field{{TypeName: "error"}},
+		},
+		"Task MailService.render must get a context.Context as first argument and return an error only": {
+			DocLines:   []string{`// @Task()`},
+			Name:       "render",
+			InputArgs:  []model.Field{{Name: "person", TypeName: "Person"}},
+			OutputArgs: []model.Field{{TypeName: "string"}},
+		},
+		"Argument ...Person of task MailService.sendWelcome cannot be enqueued": {
+			DocLines:   []string{`// @Task()`},
+			Name:       "sendWelcome",
+			InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "persons", TypeName: "...Person"}},
+			OutputArgs: []model.Field{{TypeName: "error"}},
+		},
+	} {
+		operation := operation
+		s := []model.Struct{
+			{
+				PackageName: "testData",
+				Name:        "MailService",
+				Operations:  []*model.Operation{&operation},
+			},
+		}
+		err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+		if assert.Error(t, err, message) {
+			assert.Contains(t, err.Error(), message)
+		}
+	}
+}
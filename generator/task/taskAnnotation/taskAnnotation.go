@@ -0,0 +1,51 @@
+package taskAnnotation
+
+import (
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+)
+
+const (
+	TypeTask        = "Task"
+	ParamName       = "name"
+	ParamAttempts   = "attempts"
+	ParamBackoff    = "backoff"
+	ParamMaxBackoff = "maxbackoff"
+	ParamTimeout    = "timeout"
+)
+
+var namePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*$`)
+
+func Get() []annotation.AnnotationDescriptor {
+	return []annotation.AnnotationDescriptor{
+		{
+			Name:       TypeTask,
+			ParamNames: []string{ParamName, ParamAttempts, ParamBackoff, ParamMaxBackoff, ParamTimeout},
+			Validator:  validateTaskAnnotation,
+		}}
+}
+
+func validateTaskAnnotation(annot annotation.Annotation) bool {
+	if annot.Name == TypeTask {
+		if name, found := annot.Attributes[ParamName]; found && !namePattern.MatchString(name) {
+			return false
+		}
+		if attempts, found := annot.Attributes[ParamAttempts]; found {
+			if number, err := strconv.Atoi(attempts); err != nil || number < 1 {
+				return false
+			}
+		}
+		for _, param := range []string{ParamBackoff, ParamMaxBackoff, ParamTimeout} {
+			if value, found := annot.Attributes[param]; found {
+				if d, err := time.ParseDuration(value); err != nil || d <= 0 {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	return false
+}
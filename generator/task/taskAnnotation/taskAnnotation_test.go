@@ -0,0 +1,33 @@
+package taskAnnotation
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrectTaskAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	annotation, ok := registry.ResolveAnnotation(`// @Task( name = "WelcomeMail", attempts = "10", backoff = "30s", maxbackoff = "1h", timeout = "2m" )`)
+	assert.True(t, ok)
+	assert.Equal(t, TypeTask, annotation.Name)
+	assert.Equal(t, "WelcomeMail", annotation.Attributes[ParamName])
+	assert.Equal(t, "10", annotation.Attributes[ParamAttempts])
+	assert.Equal(t, "30s", annotation.Attributes[ParamBackoff])
+	assert.Equal(t, "1h", annotation.Attributes[ParamMaxBackoff])
+	assert.Equal(t, "2m", annotation.Attributes[ParamTimeout])
+
+	_, ok = registry.ResolveAnnotation(`// @Task()`)
+	assert.True(t, ok)
+}
+
+func TestInvalidTaskAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @Task( name = "welcome-mail" )`}))
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @Task( attempts = "0" )`}))
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @Task( backoff = "soon" )`}))
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @Task( timeout = "0s" )`}))
+}
@@ -0,0 +1,211 @@
+package task
+
+const taskQueuesTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+
+package {{.PackageName}}
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// memoryTaskQueueWait is how long a worker of the in-memory queue waits at most before it looks for due tasks again
+const memoryTaskQueueWait = time.Minute
+
+// memoryTaskQueue keeps the tasks in memory, for a single instance of the service and for tests
+type memoryTaskQueue struct {
+	sync.Mutex
+	tasks []QueuedTask
+	added chan struct{}
+}
+
+// NewMemoryTaskQueue returns a queue that keeps the tasks in memory: they are lost when the service stops
+func NewMemoryTaskQueue() PullTaskQueue {
+	return &memoryTaskQueue{added: make(chan struct{}, 1)}
+}
+
+func (q *memoryTaskQueue) Enqueue(c context.Context, task QueuedTask) error {
+	q.Lock()
+	q.tasks = append(q.tasks, task)
+	q.Unlock()
+	select {
+	case q.added <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (q *memoryTaskQueue) Dequeue(c context.Context) (QueuedTask, error) {
+	for {
+		task, wait, found := q.next(time.Now())
+		if found {
+			return task, nil
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-c.Done():
+			timer.Stop()
+			return QueuedTask{}, c.Err()
+		case <-q.added:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+// next removes the first task that is due, or else tells how long to wait for one
+func (q *memoryTaskQueue) next(now time.Time) (QueuedTask, time.Duration, bool) {
+	q.Lock()
+	defer q.Unlock()
+	wait := memoryTaskQueueWait
+	for idx, task := range q.tasks {
+		if !task.NotBefore.After(now) {
+			q.tasks = append(q.tasks[:idx], q.tasks[idx+1:]...)
+			return task, 0, true
+		}
+		if due := task.NotBefore.Sub(now); due < wait {
+			wait = due
+		}
+	}
+	return QueuedTask{}, wait, false
+}
+
+// cloudTasksAPI is the endpoint of the REST-API of Google Cloud Tasks
+const cloudTasksAPI = "https://cloudtasks.googleapis.com/v2/"
+
+// cloudTasksQueue creates a task of Google Cloud Tasks for every enqueued task, that Cloud Tasks pushes to a
+// TaskWorker
+type cloudTasksQueue struct {
+	queue  string
+	url    string
+	token  func(c context.Context) (string, error)
+	client *http.Client
+}
+
+// NewCloudTasksQueue returns a queue that enqueues the tasks in a queue of Google Cloud Tasks, like
+// "projects/my-project/locations/europe-west1/queues/my-queue", that pushes them to the TaskWorker served at url.
+// Token returns the OAuth2-token that authorizes the requests to Cloud Tasks.
+func NewCloudTasksQueue(queue string, url string, token func(c context.Context) (string, error)) TaskQueue {
+	return &cloudTasksQueue{queue: queue, url: url, token: token, client: http.DefaultClient}
+}
+
+func (q *cloudTasksQueue) Enqueue(c context.Context, task QueuedTask) error {
+	body, err := json.Marshal(task)
+	if err != nil {
+		return err
+	}
+	cloudTask := map[string]interface{}{
+		"httpRequest": map[string]interface{}{
+			"httpMethod": "POST",
+			"url":        q.url,
+			"headers":    map[string]string{"Content-Type": "application/json"},
+			// bytes are base64-encoded in json, like Cloud Tasks expects
+			"body": body,
+		},
+	}
+	if !task.NotBefore.IsZero() {
+		cloudTask["scheduleTime"] = task.NotBefore.UTC().Format(time.RFC3339Nano)
+	}
+	content, err := json.Marshal(map[string]interface{}{"task": cloudTask})
+	if err != nil {
+		return err
+	}
+	token, err := q.token(c)
+	if err != nil {
+		return fmt.Errorf("Error getting token for Cloud Tasks: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(c, http.MethodPost, cloudTasksAPI+q.queue+"/tasks", bytes.NewReader(content))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Authorization", "Bearer "+token)
+	request.Header.Set("Content-Type", "application/json")
+	response, err := q.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		answer, _ := ioutil.ReadAll(response.Body)
+		return fmt.Errorf("Error creating Cloud Task for task %s: http-status %d: %s", task.Name, response.StatusCode, answer)
+	}
+	return nil
+}
+
+// RedisClient is the part of a redis-client that the redis-queue uses, like an adapter of go-redis
+type RedisClient interface {
+	// ZAdd adds member to the sorted set at key, with score
+	ZAdd(c context.Context, key string, score float64, member string) error
+	// ZRangeByScore returns at most count members of the sorted set at key with a score of at most max, lowest first
+	ZRangeByScore(c context.Context, key string, max float64, count int64) ([]string, error)
+	// ZRem removes member from the sorted set at key and tells whether it was there
+	ZRem(c context.Context, key string, member string) (bool, error)
+}
+
+// redisTaskQueuePoll is how often workers of the redis-queue look for due tasks
+const redisTaskQueuePoll = time.Second
+
+// redisTaskQueue keeps the tasks in a sorted set of redis, scored by the millisecond that they are due
+type redisTaskQueue struct {
+	client RedisClient
+	key    string
+}
+
+// NewRedisTaskQueue returns a queue that keeps the tasks in the sorted set at key of redis, that all instances of
+// the service share
+func NewRedisTaskQueue(client RedisClient, key string) PullTaskQueue {
+	return &redisTaskQueue{client: client, key: key}
+}
+
+func (q *redisTaskQueue) Enqueue(c context.Context, task QueuedTask) error {
+	member, err := json.Marshal(task)
+	if err != nil {
+		return err
+	}
+	due := task.NotBefore
+	if due.IsZero() {
+		due = time.Now()
+	}
+	return q.client.ZAdd(c, q.key, redisTaskScore(due), string(member))
+}
+
+func (q *redisTaskQueue) Dequeue(c context.Context) (QueuedTask, error) {
+	for {
+		members, err := q.client.ZRangeByScore(c, q.key, redisTaskScore(time.Now()), 1)
+		if err != nil {
+			return QueuedTask{}, err
+		}
+		if len(members) > 0 {
+			// only the worker that removes the task calls it
+			removed, err := q.client.ZRem(c, q.key, members[0])
+			if err != nil {
+				return QueuedTask{}, err
+			}
+			if !removed {
+				continue
+			}
+			var task QueuedTask
+			err = json.Unmarshal([]byte(members[0]), &task)
+			return task, err
+		}
+		timer := time.NewTimer(redisTaskQueuePoll)
+		select {
+		case <-c.Done():
+			timer.Stop()
+			return QueuedTask{}, c.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func redisTaskScore(t time.Time) float64 {
+	return float64(t.UnixNano() / int64(time.Millisecond))
+}
+`
@@ -0,0 +1,209 @@
+package task
+
+const tasksTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+
+package {{.PackageName}}
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Names of the tasks of this package, that workers call them by
+const (
+{{- range .Tasks}}
+	{{.Name}}TaskName = "{{$.PackageName}}.{{.Name}}"
+{{- end}}
+)
+
+// QueuedTask is an enqueued call of a task, with its arguments as json
+type QueuedTask struct {
+	ID      string          ` + "`" + `json:"id"` + "`" + `
+	Name    string          ` + "`" + `json:"name"` + "`" + `
+	Payload json.RawMessage ` + "`" + `json:"payload"` + "`" + `
+	// Attempt counts the calls of the task, starting at 1
+	Attempt int ` + "`" + `json:"attempt"` + "`" + `
+	// NotBefore is when the task is due: at once when it is zero
+	NotBefore time.Time ` + "`" + `json:"notBefore"` + "`" + `
+}
+
+// TaskQueue keeps the enqueued tasks until a worker calls them
+type TaskQueue interface {
+	Enqueue(c context.Context, task QueuedTask) error
+}
+
+// PullTaskQueue is a task-queue that workers take the due tasks from, unlike queues that push them to the workers
+type PullTaskQueue interface {
+	TaskQueue
+	// Dequeue removes the next task that is due, waiting for it until c is done
+	Dequeue(c context.Context) (QueuedTask, error)
+}
+
+var taskQueue TaskQueue = NewMemoryTaskQueue()
+
+// SetTaskQueue replaces the in-memory queue of the tasks, like with one that all instances of the service share
+func SetTaskQueue(queue TaskQueue) {
+	taskQueue = queue
+}
+{{range .Tasks}}
+type {{LowerCamelCase .Name}}TaskArgs struct {
+{{- range .Args}}
+	{{.Field}} {{.TypeName}} ` + "`" + `json:"{{.JSONName}}"` + "`" + `
+{{- end}}
+}
+
+// Enqueue{{.Name}} enqueues a call of {{.Operation}}, that a TaskWorker makes
+func Enqueue{{.Name}}(c context.Context{{range .Args}}, {{.Name}} {{.TypeName}}{{end}}) error {
+	return enqueueTask(c, {{.Name}}TaskName, {{LowerCamelCase .Name}}TaskArgs{ {{- range $idx, $arg := .Args}}{{if $idx}}, {{end}}{{.Field}}: {{.Name}}{{end -}} })
+}
+{{end}}
+func enqueueTask(c context.Context, name string, args interface{}) error {
+	payload, err := json.Marshal(args)
+	if err != nil {
+		return fmt.Errorf("Error marshalling arguments of task %s: %w", name, err)
+	}
+	id := make([]byte, 16)
+	_, err = rand.Read(id)
+	if err != nil {
+		return fmt.Errorf("Error creating id of task %s: %w", name, err)
+	}
+	return taskQueue.Enqueue(c, QueuedTask{ID: hex.EncodeToString(id), Name: name, Payload: payload, Attempt: 1})
+}
+
+// taskOptions tell how often and how long a task is tried
+type taskOptions struct {
+	attempts   int
+	backoff    time.Duration
+	maxBackoff time.Duration
+	timeout    time.Duration
+}
+
+// backoffAfter returns how long a task waits after a failed attempt: the backoff doubles after every attempt, up to
+// the max-backoff
+func (o taskOptions) backoffAfter(attempt int) time.Duration {
+	backoff := o.backoff
+	for idx := 1; idx < attempt && backoff < o.maxBackoff; idx++ {
+		backoff *= 2
+	}
+	if backoff > o.maxBackoff {
+		return o.maxBackoff
+	}
+	return backoff
+}
+
+type taskHandler struct {
+	options taskOptions
+	call    func(c context.Context, payload json.RawMessage) error
+}
+
+// TaskWorker calls the tasks of package {{.PackageName}}
+type TaskWorker struct {
+	// Failed receives the tasks that failed their last attempt, or that could not be retried: they are dropped
+	// without it
+	Failed   func(c context.Context, task QueuedTask, err error)
+	handlers map[string]taskHandler
+}
+
+// NewTaskWorker returns a worker that calls the tasks of package {{.PackageName}}
+func NewTaskWorker({{range $idx, $receiver := .Receivers}}{{if $idx}}, {{end}}{{.Name}} *{{.TypeName}}{{end}}) *TaskWorker {
+	return &TaskWorker{
+		handlers: map[string]taskHandler{
+{{- range .Tasks}}
+			{{.Name}}TaskName: {
+				options: taskOptions{attempts: {{.Attempts}}, backoff: {{.Backoff}}, maxBackoff: {{.MaxBackoff}}{{if .Timeout}}, timeout: {{.Timeout}}{{end}}},
+				call: func(c context.Context, payload json.RawMessage) error {
+					var args {{LowerCamelCase .Name}}TaskArgs
+					err := json.Unmarshal(payload, &args)
+					if err != nil {
+						return fmt.Errorf("Error unmarshalling arguments of task %s: %w", {{.Name}}TaskName, err)
+					}
+					return {{.Call}}(c{{range .Args}}, args.{{.Field}}{{end}})
+				},
+			},
+{{- end}}
+		},
+	}
+}
+
+// Handle calls a task and enqueues its next attempt after its backoff, when it fails. An error is returned when the
+// task is unknown or its next attempt could not be enqueued.
+func (w *TaskWorker) Handle(c context.Context, task QueuedTask) error {
+	handler, found := w.handlers[task.Name]
+	if !found {
+		return fmt.Errorf("Unknown task %s", task.Name)
+	}
+	err := w.call(c, handler, task)
+	if err == nil {
+		return nil
+	}
+	if task.Attempt >= handler.options.attempts {
+		if w.Failed != nil {
+			w.Failed(c, task, err)
+		}
+		return nil
+	}
+	next := task
+	next.Attempt++
+	next.NotBefore = time.Now().Add(handler.options.backoffAfter(task.Attempt))
+	return taskQueue.Enqueue(c, next)
+}
+
+// call calls a task once, within its timeout
+func (w *TaskWorker) call(c context.Context, handler taskHandler, task QueuedTask) (err error) {
+	if handler.options.timeout > 0 {
+		var cancel context.CancelFunc
+		c, cancel = context.WithTimeout(c, handler.options.timeout)
+		defer cancel()
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("Task %s panicked: %v", task.Name, r)
+		}
+	}()
+	return handler.call(c, task.Payload)
+}
+
+// Run takes the due tasks from the pull-queue of SetTaskQueue and calls them one at a time, until c is done: run
+// several workers to call several tasks at a time
+func (w *TaskWorker) Run(c context.Context) error {
+	queue, ok := taskQueue.(PullTaskQueue)
+	if !ok {
+		return fmt.Errorf("Task-queue %T pushes its tasks: serve the worker over http instead", taskQueue)
+	}
+	for {
+		task, err := queue.Dequeue(c)
+		if err != nil {
+			if c.Err() != nil {
+				return c.Err()
+			}
+			return err
+		}
+		err = w.Handle(c, task)
+		if err != nil && w.Failed != nil {
+			w.Failed(c, task, err)
+		}
+	}
+}
+
+// ServeHTTP calls the task in the json-body of a request, that a push-queue like Cloud Tasks sends. It answers
+// with an error-status when the task could not be handled, so the queue retries the request itself.
+func (w *TaskWorker) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	var task QueuedTask
+	err := json.NewDecoder(r.Body).Decode(&task)
+	if err != nil {
+		http.Error(rw, fmt.Sprintf("Invalid task: %s", err), http.StatusBadRequest)
+		return
+	}
+	err = w.Handle(r.Context(), task)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
+`
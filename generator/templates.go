@@ -0,0 +1,116 @@
+package generator
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strconv"
+)
+
+// Template is a template of a generator that users can override. Its APIVersion is raised whenever the data or
+// functions that are available to the template change in an incompatible way.
+type Template struct {
+	Name       string
+	APIVersion int
+	Text       string
+}
+
+// TemplateProvider is implemented by generators whose output is based on overridable templates
+type TemplateProvider interface {
+	GetTemplates() []Template
+}
+
+var templateFilenamePattern = regexp.MustCompile(`^(.+)\.v(\d+)\.tmpl$`)
+
+// TemplateFilename returns the location of a template within a template-dir: <generator>/<template>.v<api-version>.tmpl
+func TemplateFilename(dir string, generatorName string, t Template) string {
+	return filepath.Join(dir, generatorName, fmt.Sprintf("%s.v%d.tmpl", t.Name, t.APIVersion))
+}
+
+// DefaultTemplateFiles returns the default templates of all generators as files within dir, ready to be customized
+func DefaultTemplateFiles(dir string, registry *Registry) []OutputFile {
+	files := []OutputFile{}
+	for _, g := range registry.All() {
+		provider, ok := g.(TemplateProvider)
+		if !ok {
+			continue
+		}
+		for _, t := range provider.GetTemplates() {
+			files = append(files, OutputFile{
+				Filename: TemplateFilename(dir, g.Name(), t),
+				Src:      g.Name(),
+				Content:  []byte(t.Text),
+			})
+		}
+	}
+	return files
+}
+
+// LoadTemplateOverrides reads the overriding templates in dir, per generator-name and template-name.
+// It fails on templates that are unknown or that were written for another api-version than the generator provides.
+func LoadTemplateOverrides(dir string, registry *Registry) (map[string]map[string]string, error) {
+	overrides := map[string]map[string]string{}
+	if dir == "" {
+		return overrides, nil
+	}
+	generatorDirs, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading template-dir %s:%w", dir, err)
+	}
+	for _, generatorDir := range generatorDirs {
+		if !generatorDir.IsDir() {
+			continue
+		}
+		name := generatorDir.Name()
+		g, found := registry.Get(name)
+		if !found {
+			return nil, fmt.Errorf("Template-dir %s contains templates for unknown generator %s", dir, name)
+		}
+		provider, ok := g.(TemplateProvider)
+		if !ok {
+			return nil, fmt.Errorf("Generator %s does not support templates", name)
+		}
+		templates, err := loadGeneratorTemplates(filepath.Join(dir, name), name, provider.GetTemplates())
+		if err != nil {
+			return nil, err
+		}
+		overrides[name] = templates
+	}
+	return overrides, nil
+}
+
+func loadGeneratorTemplates(dir string, generatorName string, defaults []Template) (map[string]string, error) {
+	versions := map[string]int{}
+	for _, t := range defaults {
+		versions[t.Name] = t.APIVersion
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading template-dir %s:%w", dir, err)
+	}
+	templates := map[string]string{}
+	for _, f := range files {
+		match := templateFilenamePattern.FindStringSubmatch(f.Name())
+		if f.IsDir() || match == nil {
+			continue
+		}
+		name := match[1]
+		version, _ := strconv.Atoi(match[2])
+		expected, found := versions[name]
+		if !found {
+			return nil, fmt.Errorf("Generator %s has no template %s (template %s)", generatorName, name, filepath.Join(dir, f.Name()))
+		}
+		if version != expected {
+			return nil, fmt.Errorf("Template %s targets api-version %d but generator %s provides api-version %d: compare with the output of -dump-templates",
+				filepath.Join(dir, f.Name()), version, generatorName, expected)
+		}
+		content, err := ioutil.ReadFile(filepath.Join(dir, f.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("Error reading template %s:%w", f.Name(), err)
+		}
+		templates[name] = string(content)
+	}
+	return templates, nil
+}
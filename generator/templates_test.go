@@ -0,0 +1,83 @@
+package generator
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type templatedGenerator struct {
+	namedGenerator
+}
+
+func (g templatedGenerator) GetTemplates() []Template {
+	return []Template{{Name: "handlers", APIVersion: 2, Text: "default"}}
+}
+
+func templateRegistry() *Registry {
+	return NewRegistry(templatedGenerator{namedGenerator{name: "rest"}}, namedGenerator{name: "ast"})
+}
+
+func writeTemplate(t *testing.T, filename string, content string) {
+	err := os.MkdirAll(filepath.Dir(filename), 0777)
+	assert.NoError(t, err)
+	err = ioutil.WriteFile(filename, []byte(content), 0644)
+	assert.NoError(t, err)
+}
+
+func TestDefaultTemplateFiles(t *testing.T) {
+	assert.Equal(t, []OutputFile{
+		{
+			Filename: filepath.Join("templates", "rest", "handlers.v2.tmpl"),
+			Src:      "rest",
+			Content:  []byte("default"),
+		},
+	}, DefaultTemplateFiles("templates", templateRegistry()))
+}
+
+func TestLoadTemplateOverrides(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplate(t, filepath.Join(dir, "rest", "handlers.v2.tmpl"), "custom")
+	writeTemplate(t, filepath.Join(dir, "rest", "README.md"), "ignored")
+
+	overrides, err := LoadTemplateOverrides(dir, templateRegistry())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]map[string]string{"rest": {"handlers": "custom"}}, overrides)
+}
+
+func TestLoadTemplateOverridesWithoutDir(t *testing.T) {
+	overrides, err := LoadTemplateOverrides("", templateRegistry())
+	assert.NoError(t, err)
+	assert.Empty(t, overrides)
+}
+
+func TestLoadTemplateOverridesRejectsOtherAPIVersion(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplate(t, filepath.Join(dir, "rest", "handlers.v1.tmpl"), "custom")
+
+	_, err := LoadTemplateOverrides(dir, templateRegistry())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "targets api-version 1 but generator rest provides api-version 2")
+}
+
+func TestLoadTemplateOverridesRejectsUnknownTemplates(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplate(t, filepath.Join(dir, "rest", "client.v2.tmpl"), "custom")
+	_, err := LoadTemplateOverrides(dir, templateRegistry())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Generator rest has no template client")
+
+	dir = t.TempDir()
+	writeTemplate(t, filepath.Join(dir, "soap", "client.v1.tmpl"), "custom")
+	_, err = LoadTemplateOverrides(dir, templateRegistry())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown generator soap")
+
+	dir = t.TempDir()
+	writeTemplate(t, filepath.Join(dir, "ast", "ast.v1.tmpl"), "custom")
+	_, err = LoadTemplateOverrides(dir, templateRegistry())
+	assert.EqualError(t, err, "Generator ast does not support templates")
+}
@@ -0,0 +1,48 @@
+// Package tenant describes the declarations with @TenantScoped, for the generators of the rest-services, events and
+// repositories that keep the data of tenants apart
+package tenant
+
+import (
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/MarcGrol/golangAnnotations/generator/tenant/tenantAnnotation"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+const (
+	// DefaultHeader is the request-header that tells the tenant of a call to a tenant-scoped rest-service
+	DefaultHeader = "X-Tenant-ID"
+	// DefaultField is the field of a tenant-scoped event that holds its tenant
+	DefaultField = "TenantID"
+	// ArgName is the argument of the operations of a tenant-scoped rest-service that receives the tenant
+	ArgName = "tenantID"
+)
+
+// IsTenantScoped tells whether the declaration with the doc-lines has @TenantScoped
+func IsTenantScoped(docLines []string) bool {
+	_, ok := annotation.NewRegistry(tenantAnnotation.Get()).ResolveAnnotationByName(docLines, tenantAnnotation.TypeTenantScoped)
+	return ok
+}
+
+// GetHeader returns the request-header with the tenant of a tenant-scoped rest-service
+func GetHeader(s model.Struct) string {
+	return getAttribute(s.DocLines, tenantAnnotation.ParamHeader, DefaultHeader)
+}
+
+// GetField returns the field with the tenant of a tenant-scoped event
+func GetField(s model.Struct) string {
+	return getAttribute(s.DocLines, tenantAnnotation.ParamField, DefaultField)
+}
+
+// IsTenantArg tells whether an argument of an operation receives the tenant
+func IsTenantArg(arg model.Field) bool {
+	return arg.Name == ArgName && arg.TypeName == "string"
+}
+
+func getAttribute(docLines []string, name string, defaultValue string) string {
+	if ann, ok := annotation.NewRegistry(tenantAnnotation.Get()).ResolveAnnotationByName(docLines, tenantAnnotation.TypeTenantScoped); ok {
+		if value := ann.Attributes[name]; value != "" {
+			return value
+		}
+	}
+	return defaultValue
+}
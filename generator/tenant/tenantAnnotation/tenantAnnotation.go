@@ -0,0 +1,27 @@
+package tenantAnnotation
+
+import "github.com/MarcGrol/golangAnnotations/generator/annotation"
+
+const (
+	TypeTenantScoped = "TenantScoped"
+	ParamHeader      = "header"
+	ParamField       = "field"
+)
+
+func Get() []annotation.AnnotationDescriptor {
+	return []annotation.AnnotationDescriptor{
+		{
+			Name:       TypeTenantScoped,
+			ParamNames: []string{ParamHeader, ParamField},
+			Validator:  validateTenantScopedAnnotation,
+		}}
+}
+
+func validateTenantScopedAnnotation(annot annotation.Annotation) bool {
+	if annot.Name == TypeTenantScoped {
+		header, hasHeader := annot.Attributes[ParamHeader]
+		field, hasField := annot.Attributes[ParamField]
+		return (!hasHeader || header != "") && (!hasField || field != "")
+	}
+	return false
+}
@@ -0,0 +1,26 @@
+package tenantAnnotation
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrectTenantScopedAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	annotation, ok := registry.ResolveAnnotation(`// @TenantScoped( header = "X-Customer", field = "CustomerUID" )`)
+	assert.True(t, ok)
+	assert.Equal(t, TypeTenantScoped, annotation.Name)
+	assert.Equal(t, "X-Customer", annotation.Attributes[ParamHeader])
+	assert.Equal(t, "CustomerUID", annotation.Attributes[ParamField])
+	assert.NotEmpty(t, registry.ResolveAnnotations([]string{`// @TenantScoped()`}))
+}
+
+func TestInvalidTenantScopedAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @TenantScoped( header = "" )`}))
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @TenantScoped( field = "" )`}))
+}
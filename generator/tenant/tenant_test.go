@@ -0,0 +1,28 @@
+package tenant
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaults(t *testing.T) {
+	s := model.Struct{DocLines: []string{`// @TenantScoped()`}}
+	assert.True(t, IsTenantScoped(s.DocLines))
+	assert.Equal(t, "X-Tenant-ID", GetHeader(s))
+	assert.Equal(t, "TenantID", GetField(s))
+}
+
+func TestAttributes(t *testing.T) {
+	s := model.Struct{DocLines: []string{`// @TenantScoped( header = "X-Customer", field = "CustomerUID" )`}}
+	assert.Equal(t, "X-Customer", GetHeader(s))
+	assert.Equal(t, "CustomerUID", GetField(s))
+	assert.False(t, IsTenantScoped([]string{`// @Event( aggregate = "Person" )`}))
+}
+
+func TestIsTenantArg(t *testing.T) {
+	assert.True(t, IsTenantArg(model.Field{Name: "tenantID", TypeName: "string"}))
+	assert.False(t, IsTenantArg(model.Field{Name: "tenantID", TypeName: "int"}))
+	assert.False(t, IsTenantArg(model.Field{Name: "uid", TypeName: "string"}))
+}
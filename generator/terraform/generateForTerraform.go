@@ -0,0 +1,510 @@
+package terraform
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/generator/jsonHelpers"
+	"github.com/MarcGrol/golangAnnotations/generator/pii"
+	"github.com/MarcGrol/golangAnnotations/generator/rest"
+	"github.com/MarcGrol/golangAnnotations/generator/tenant"
+	"github.com/MarcGrol/golangAnnotations/generator/terraform/terraformAnnotation"
+	"github.com/MarcGrol/golangAnnotations/generator/validation/validationAnnotation"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+var pathParamPattern = regexp.MustCompile(`\{(\w+)\}`)
+
+// reservedNames are the names that terraform gives a meaning of its own in the block of a resource
+var reservedNames = map[string]bool{
+	"connection": true, "count": true, "depends_on": true, "for_each": true, "lifecycle": true, "provider": true,
+	"provisioner": true,
+}
+
+// providerContext holds the resources of a terraform-provider, which is a plugin of its own
+type providerContext struct {
+	Provider  string
+	Package   string
+	Address   string
+	Tenant    bool
+	Resources []resourceContext
+}
+
+// resourceContext is a terraform-resource with the attributes of a struct, managed by the operations of a service
+type resourceContext struct {
+	Name        string
+	TypeName    string
+	GoName      string
+	Service     string
+	Description string
+	Attributes  []attribute
+	// Skipped are the json-fields of the struct that have no attribute
+	Skipped []string
+	// PlanModifiers are the packages of the plan-modifiers that the attributes use
+	PlanModifiers []string
+	Create        request
+	Read          request
+	Update        *request
+	Delete        request
+}
+
+type attribute struct {
+	Name        string
+	JSONName    string
+	Field       string
+	Description string
+	Type        tfType
+	// Settable attributes are sent when the resource is created or updated
+	Settable           bool
+	Required           bool
+	Optional           bool
+	Computed           bool
+	Sensitive          bool
+	RequiresReplace    bool
+	UseStateForUnknown bool
+	validated          bool
+	created            bool
+	updated            bool
+}
+
+// request is a call of a rest-operation, with the go-expressions of its parameters in the json of the resource
+type request struct {
+	Operation string
+	Method    string
+	Path      string
+	Query     []parameter
+	Headers   []parameter
+	Body      bool
+	Result    bool
+}
+
+type parameter struct {
+	Key   string
+	Value string
+}
+
+const defaultAddressFormat = "localhost/providers/%s"
+
+type Generator struct {
+}
+
+func NewGenerator() generator.Generator {
+	return &Generator{}
+}
+
+func (eg *Generator) Name() string {
+	return "terraform"
+}
+
+func (eg *Generator) GetTemplates() []generator.Template {
+	return []generator.Template{
+		{Name: "terraform-provider", APIVersion: 1, Text: providerTemplate},
+		{Name: "terraform-resource", APIVersion: 1, Text: resourceTemplate},
+	}
+}
+
+func (eg *Generator) GetAnnotations() []annotation.AnnotationDescriptor {
+	return terraformAnnotation.Get()
+}
+
+func (eg *Generator) Generate(parsedSource model.ParsedSources, config generator.Config) ([]generator.OutputFile, error) {
+	return generate(config, parsedSource)
+}
+
+func generate(config generator.Config, parsedSources model.ParsedSources) ([]generator.OutputFile, error) {
+	packageName, err := generationUtil.GetPackageNameForStructs(parsedSources.Structs)
+	if packageName == "" || err != nil {
+		return nil, err
+	}
+	targetDir, err := generationUtil.DetermineTargetPath(config.InputDir, packageName)
+	if err != nil {
+		return nil, err
+	}
+
+	providers := []*providerContext{}
+	registry := annotation.NewRegistry(terraformAnnotation.Get())
+	for _, service := range parsedSources.Structs {
+		// a service can manage several resources
+		for _, ann := range registry.ResolveAnnotations(service.DocLines) {
+			if ann.Name != terraformAnnotation.TypeTerraformResource {
+				continue
+			}
+			if !rest.IsRestService(service) {
+				return nil, generator.StructError(service, terraformAnnotation.TypeTerraformResource, "Struct %s with @TerraformResource must be a @RestService", service.Name)
+			}
+			provider := findProvider(&providers, ann)
+			resource, err := newResource(service, ann, parsedSources)
+			if err != nil {
+				return nil, err
+			}
+			for _, other := range provider.Resources {
+				if other.Name == resource.Name {
+					return nil, generator.StructError(service, terraformAnnotation.TypeTerraformResource, "Resource %s of provider %s is managed by %s as well", resource.TypeName, provider.Provider, other.Service)
+				}
+			}
+			provider.Resources = append(provider.Resources, resource)
+			provider.Tenant = provider.Tenant || rest.IsRestServiceTenantScoped(service)
+		}
+	}
+
+	files := []generator.OutputFile{}
+	for _, provider := range providers {
+		if provider.Package == "" {
+			// terraform finds a provider by the name of its binary, which go names after the directory
+			provider.Package = "terraform-provider-" + provider.Provider
+		}
+		if provider.Address == "" {
+			provider.Address = fmt.Sprintf(defaultAddressFormat, provider.Provider)
+		}
+		// the provider is a plugin of its own, in a sub-directory of the package
+		file, err := generationUtil.Generate(generationUtil.Info{
+			Src:            packageName,
+			TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/%s/provider.go", targetDir, provider.Package)),
+			TemplateName:   "terraform-provider",
+			TemplateString: providerTemplate,
+			Config:         config,
+			FuncMap:        customTemplateFuncs,
+			Data:           provider,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Error generating terraform-provider %s for package %s:%w", provider.Provider, packageName, err)
+		}
+		files = append(files, file)
+
+		for _, resource := range provider.Resources {
+			file, err := generationUtil.Generate(generationUtil.Info{
+				Src:            fmt.Sprintf("%s.%s", packageName, resource.Service),
+				TypeName:       resource.Service,
+				TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/%s/resource%s.go", targetDir, provider.Package, rest.ToFirstUpper(resource.GoName))),
+				TemplateName:   "terraform-resource",
+				TemplateString: resourceTemplate,
+				Config:         config,
+				FuncMap:        customTemplateFuncs,
+				Data:           resource,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("Error generating terraform-resource %s for service %s:%w", resource.TypeName, resource.Service, err)
+			}
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+func findProvider(providers *[]*providerContext, ann annotation.Annotation) *providerContext {
+	name := ann.Attributes[terraformAnnotation.ParamProvider]
+	var provider *providerContext
+	for _, p := range *providers {
+		if p.Provider == name {
+			provider = p
+		}
+	}
+	if provider == nil {
+		provider = &providerContext{Provider: name}
+		*providers = append(*providers, provider)
+	}
+	// the package and address can be given by any of the resources of the provider
+	if provider.Package == "" {
+		provider.Package = ann.Attributes[terraformAnnotation.ParamPackage]
+	}
+	if provider.Address == "" {
+		provider.Address = ann.Attributes[terraformAnnotation.ParamAddress]
+	}
+	return provider
+}
+
+func newResource(service model.Struct, ann annotation.Annotation, parsedSources model.ParsedSources) (resourceContext, error) {
+	name := ann.Attributes[terraformAnnotation.ParamName]
+	resource := resourceContext{
+		Name:     name,
+		TypeName: ann.Attributes[terraformAnnotation.ParamProvider] + "_" + name,
+		GoName:   goName(name),
+		Service:  service.Name,
+	}
+	fail := func(format string, args ...interface{}) (resourceContext, error) {
+		return resourceContext{}, generator.StructError(service, terraformAnnotation.TypeTerraformResource, "Resource %s of %s: %s", resource.TypeName, service.Name, fmt.Sprintf(format, args...))
+	}
+
+	operations := map[string]model.Operation{}
+	for _, param := range []string{terraformAnnotation.ParamCreate, terraformAnnotation.ParamRead, terraformAnnotation.ParamUpdate, terraformAnnotation.ParamDelete} {
+		operationName := ann.Attributes[param]
+		if operationName == "" {
+			continue
+		}
+		o, found := findOperation(service, operationName)
+		if !found {
+			return fail("%s-operation %s is not a @RestOperation of the service", param, operationName)
+		}
+		if err := checkOperation(o); err != "" {
+			return fail("%s-operation %s %s", param, operationName, err)
+		}
+		operations[param] = o
+	}
+	read := operations[terraformAnnotation.ParamRead]
+	if !rest.IsRestOperationJSON(read) || !rest.HasOutput(read) {
+		return fail("read-operation %s must return the json of the resource", read.Name)
+	}
+	if create := operations[terraformAnnotation.ParamCreate]; !rest.IsRestOperationJSON(create) || !rest.HasOutput(create) {
+		return fail("create-operation %s must return the json of the resource", create.Name)
+	}
+	s, found := generationUtil.FindStruct(parsedSources.Structs, rest.GetOutputArgType(read))
+	if !found {
+		return fail("read-operation %s must return a struct", read.Name)
+	}
+	resource.Description = generationUtil.Description(s.DocLines)
+
+	id := ann.Attributes[terraformAnnotation.ParamID]
+	if id == "" {
+		// the last path-parameter of the read-operation identifies the resource
+		matches := pathParamPattern.FindAllStringSubmatch(rest.GetRestOperationPath(read), -1)
+		if len(matches) == 0 {
+			return fail("read-operation %s has no path-parameter that identifies the resource", read.Name)
+		}
+		id = matches[len(matches)-1][1]
+	}
+	if err := resource.addAttributes(s, id, parsedSources); err != "" {
+		return fail("%s", err)
+	}
+	if resource.attributeFor(id, id) == nil {
+		return fail("id %s is not a string-field of %s", id, s.Name)
+	}
+
+	for _, attr := range resource.sentBy(operations[terraformAnnotation.ParamCreate], id, parsedSources) {
+		attr.created = true
+	}
+	if update, found := operations[terraformAnnotation.ParamUpdate]; found {
+		for _, attr := range resource.sentBy(update, id, parsedSources) {
+			// the id only identifies the resource to update
+			if attr.Name != "id" {
+				attr.updated = true
+			}
+		}
+	}
+	resource.completeAttributes()
+
+	var err string
+	if resource.Create, err = resource.newRequest(service, operations[terraformAnnotation.ParamCreate], id); err != "" {
+		return fail("%s", err)
+	}
+	if resource.Read, err = resource.newRequest(service, read, id); err != "" {
+		return fail("%s", err)
+	}
+	if o, found := operations[terraformAnnotation.ParamUpdate]; found {
+		update, err := resource.newRequest(service, o, id)
+		if err != "" {
+			return fail("%s", err)
+		}
+		resource.Update = &update
+	}
+	if resource.Delete, err = resource.newRequest(service, operations[terraformAnnotation.ParamDelete], id); err != "" {
+		return fail("%s", err)
+	}
+	if resource.Read.Body || resource.Delete.Body {
+		return fail("read- and delete-operations cannot have a body")
+	}
+	return resource, nil
+}
+
+func findOperation(service model.Struct, name string) (model.Operation, bool) {
+	for _, o := range service.Operations {
+		if o.Name == name && rest.IsRestOperation(*o) {
+			return *o, true
+		}
+	}
+	return model.Operation{}, false
+}
+
+// checkOperation returns why a rest-operation cannot be called by the provider, if it cannot
+func checkOperation(o model.Operation) string {
+	switch {
+	case rest.IsRestOperationAsync(o):
+		return "is @Async"
+	case rest.IsRestOperationForm(o) || rest.HasUpload(o):
+		return "reads a form"
+	case rest.IsETagRequired(o):
+		return "requires an @ETag"
+	case !rest.IsRestOperationJSON(o) && !rest.IsRestOperationNoContent(o):
+		return "must answer with json or no content"
+	}
+	return ""
+}
+
+// addAttributes adds the attributes of the json-fields of the struct, with the id as attribute id
+func (r *resourceContext) addAttributes(s model.Struct, id string, parsedSources model.ParsedSources) string {
+	validations := annotation.NewRegistry(validationAnnotation.Get())
+	for _, f := range generationUtil.JSONFields(s, parsedSources.Structs) {
+		t, ok := typeOf(f.Field.TypeName, parsedSources)
+		if !ok {
+			r.Skipped = append(r.Skipped, f.JSONName)
+			continue
+		}
+		attr := attribute{
+			Name:        generationUtil.SnakeCase(f.JSONName),
+			JSONName:    f.JSONName,
+			Field:       f.Field.Name,
+			Description: generationUtil.Description(f.Field.DocLines),
+			Type:        t,
+			Sensitive:   pii.GetCategory(f.Field) != "" || jsonHelpers.IsEncryptedField(f.Field),
+		}
+		if isNamed(f, id) {
+			if t.Attribute != "String" {
+				return fmt.Sprintf("id %s is not a string-field of %s", id, s.Name)
+			}
+			attr.Name = "id"
+		} else if attr.Name == "id" || reservedNames[attr.Name] {
+			return fmt.Sprintf("field %s of %s is named %s, which is reserved for terraform", f.Field.Name, s.Name, attr.Name)
+		}
+		if ann, ok := validations.ResolveAnnotationByName(f.Field.DocLines, validationAnnotation.TypeValidate); ok {
+			attr.validated = ann.Attributes[validationAnnotation.ParamRequired] == "true"
+		}
+		r.Attributes = append(r.Attributes, attr)
+	}
+	return ""
+}
+
+// sentBy returns the attributes that an operation sends: the fields of its body and its parameters
+func (r *resourceContext) sentBy(o model.Operation, id string, parsedSources model.ParsedSources) []*attribute {
+	sent := map[string]bool{}
+	for _, arg := range o.InputArgs {
+		if rest.IsInputArg(arg) {
+			if s, found := generationUtil.FindStruct(parsedSources.Structs, arg.TypeName); found {
+				for _, f := range generationUtil.JSONFields(s, parsedSources.Structs) {
+					sent[f.JSONName] = true
+				}
+			}
+		} else if attr := r.attributeFor(arg.Name, id); attr != nil {
+			sent[attr.JSONName] = true
+		}
+	}
+	attributes := []*attribute{}
+	for idx := range r.Attributes {
+		if sent[r.Attributes[idx].JSONName] {
+			attributes = append(attributes, &r.Attributes[idx])
+		}
+	}
+	return attributes
+}
+
+// completeAttributes sets how terraform handles the attributes: attributes that are not sent are computed by the
+// service, the resource is replaced when an attribute changes that cannot be updated, and the id is kept once known
+func (r *resourceContext) completeAttributes() {
+	modifiers := map[string]bool{}
+	for idx := range r.Attributes {
+		attr := &r.Attributes[idx]
+		isID := attr.Name == "id"
+		attr.Settable = attr.created || attr.updated
+		attr.Required = attr.validated && attr.created && !isID
+		attr.Optional = attr.Settable && !attr.Required
+		attr.Computed = !attr.Settable || isID
+		attr.RequiresReplace = attr.Settable && (!attr.updated || isID)
+		attr.UseStateForUnknown = isID
+		if attr.RequiresReplace || attr.UseStateForUnknown {
+			modifiers[attr.Type.PlanModifier()] = true
+		}
+	}
+	for modifier := range modifiers {
+		r.PlanModifiers = append(r.PlanModifiers, modifier)
+	}
+	sort.Strings(r.PlanModifiers)
+}
+
+// HasCollections tells whether the resource has attributes that are lists or maps
+func (r resourceContext) HasCollections() bool {
+	for _, attr := range r.Attributes {
+		if attr.Type.IsCollection() {
+			return true
+		}
+	}
+	return false
+}
+
+// attributeFor returns the attribute that a parameter of an operation is taken from
+func (r *resourceContext) attributeFor(name string, id string) *attribute {
+	for idx := range r.Attributes {
+		attr := &r.Attributes[idx]
+		if attr.Name == "id" && strings.EqualFold(name, id) {
+			return attr
+		}
+		if attr.Name != "id" && (strings.EqualFold(name, attr.JSONName) || strings.EqualFold(name, attr.Field)) {
+			return attr
+		}
+	}
+	return nil
+}
+
+func (r *resourceContext) newRequest(service model.Struct, o model.Operation, id string) (request, string) {
+	req := request{
+		Operation: o.Name,
+		Method:    rest.GetRestOperationMethod(o),
+		Result:    rest.IsRestOperationJSON(o) && rest.HasOutput(o),
+	}
+	path := rest.GetRestServicePath(service) + rest.GetRestOperationPath(o)
+	pathParams := map[string]string{}
+	for _, arg := range o.InputArgs {
+		attr := r.attributeFor(arg.Name, id)
+		value := ""
+		if attr != nil && !attr.Type.IsCollection() {
+			value = "values." + attr.Field
+		}
+		switch {
+		case strings.Contains(path, "{"+arg.Name+"}"):
+			if value == "" {
+				return req, fmt.Sprintf("path-parameter %s of %s is not an attribute", arg.Name, o.Name)
+			}
+			pathParams[arg.Name] = "pathParameter(" + value + ")"
+		case rest.IsRestServiceTenantScoped(service) && tenant.IsTenantArg(arg):
+			req.Headers = append(req.Headers, parameter{Key: rest.GetRestServiceTenantHeader(service), Value: "r.client.tenant"})
+		case rest.IsContextArg(arg) || rest.IsRequestContextArg(arg):
+			continue
+		case rest.IsInputArg(arg):
+			req.Body = true
+		case value != "":
+			req.Query = append(req.Query, parameter{Key: rest.Uncapitalized(arg.Name), Value: "parameter(" + value + ")"})
+		case rest.IsInputArgMandatory(o, arg):
+			return req, fmt.Sprintf("parameter %s of %s is not an attribute", arg.Name, o.Name)
+		}
+	}
+	if rest.IsRestOperationIdempotent(o) {
+		req.Headers = append(req.Headers, parameter{Key: "Idempotency-Key", Value: "idempotencyKey()"})
+	}
+	req.Path = pathExpression(path, pathParams)
+	return req, ""
+}
+
+// pathExpression returns the go-expression of a path, with the path-parameters filled in
+func pathExpression(path string, pathParams map[string]string) string {
+	parts := []string{}
+	last := 0
+	for _, match := range pathParamPattern.FindAllStringSubmatchIndex(path, -1) {
+		value, found := pathParams[path[match[2]:match[3]]]
+		if !found {
+			continue
+		}
+		if match[0] > last {
+			parts = append(parts, strconv.Quote(path[last:match[0]]))
+		}
+		parts = append(parts, value)
+		last = match[1]
+	}
+	if last < len(path) || len(parts) == 0 {
+		parts = append(parts, strconv.Quote(path[last:]))
+	}
+	return strings.Join(parts, " + ")
+}
+
+func isNamed(f generationUtil.JSONField, name string) bool {
+	return strings.EqualFold(f.JSONName, name) || strings.EqualFold(f.Field.Name, name)
+}
+
+var customTemplateFuncs = template.FuncMap{
+	"Quote":        strconv.Quote,
+	"ToUpper":      strings.ToUpper,
+	"ToFirstUpper": rest.ToFirstUpper,
+}
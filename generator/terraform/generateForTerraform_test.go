@@ -0,0 +1,348 @@
+package terraform
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func cleanup() {
+	os.RemoveAll("./testData/terraform-provider-example")
+	os.RemoveAll("./testData/provider")
+}
+
+func TestGenerateProvider(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @RestService( path = "/api" )`, `// @TenantScoped()`, `// @TerraformResource( provider = "example", name = "person", create = "createPerson", read = "getPerson", update = "updatePerson", delete = "deletePerson" )`},
+			Name:        "PersonService",
+			Operations: []*model.Operation{
+				{
+					DocLines:   []string{`// @RestOperation( method = "POST", path = "/person", format = "JSON" )`, `// @Idempotent()`},
+					Name:       "createPerson",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "tenantID", TypeName: "string"}, {Name: "person", TypeName: "NewPerson"}},
+					OutputArgs: []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+				},
+				{
+					DocLines:   []string{`// @RestOperation( method = "GET", path = "/person/{uid}", format = "JSON", optionalargs = "verbose" )`},
+					Name:       "getPerson",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "tenantID", TypeName: "string"}, {Name: "uid", TypeName: "string"}, {Name: "verbose", TypeName: "bool"}},
+					OutputArgs: []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+				},
+				{
+					DocLines:   []string{`// @RestOperation( method = "PUT", path = "/person/{uid}", format = "no_content" )`},
+					Name:       "updatePerson",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "tenantID", TypeName: "string"}, {Name: "uid", TypeName: "string"}, {Name: "update", TypeName: "PersonUpdate"}},
+					OutputArgs: []model.Field{{TypeName: "error"}},
+				},
+				{
+					DocLines:   []string{`// @RestOperation( method = "DELETE", path = "/person/{uid}", format = "no_content" )`},
+					Name:       "deletePerson",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "tenantID", TypeName: "string"}, {Name: "uid", TypeName: "string"}},
+					OutputArgs: []model.Field{{TypeName: "error"}},
+				},
+				{
+					DocLines:   []string{`// @RestOperation( method = "DELETE", path = "/person/{uid}/versioned", format = "no_content" )`, `// @ETag( required = "true" )`},
+					Name:       "deleteVersioned",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}},
+					OutputArgs: []model.Field{{TypeName: "error"}},
+				},
+				{
+					DocLines:   []string{`// @RestOperation( method = "GET", path = "/person/{uid}/name", format = "JSON" )`},
+					Name:       "getName",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}},
+					OutputArgs: []model.Field{{TypeName: "string"}, {TypeName: "error"}},
+				},
+				{
+					DocLines:   []string{`// @RestOperation( method = "GET", path = "/group/{group}/person/{uid}", format = "JSON" )`},
+					Name:       "getGroupMember",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "group", TypeName: "string"}, {Name: "uid", TypeName: "string"}},
+					OutputArgs: []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+				},
+			},
+		},
+		{
+			PackageName: "testData",
+			DocLines:    []string{"// Person is someone we know"},
+			Name:        "Person",
+			Fields: []model.Field{
+				{Name: "UID", TypeName: "string", Tag: "`json:\"uid\"`"},
+				{Name: "Name", TypeName: "string", Tag: "`json:\"name\"`", DocLines: []string{"// Name is the full name", `// @Validate( required = "true" )`}},
+				{Name: "Email", TypeName: "string", Tag: "`json:\"email,omitempty\"`", DocLines: []string{`// @PII( category = "contact" )`}},
+				{Name: "BirthYear", TypeName: "int", Tag: "`json:\"birthYear\"`"},
+				{Name: "Color", TypeName: "ColorType", Tag: "`json:\"color\"`"},
+				{Name: "Tags", TypeName: "[]string", Tag: "`json:\"tags\"`"},
+				{Name: "Labels", TypeName: "map[string]string", Tag: "`json:\"labels\"`"},
+				{Name: "Created", TypeName: "time.Time", Tag: "`json:\"created\"`"},
+				{Name: "Address", TypeName: "*Address", Tag: "`json:\"address\"`"},
+				{Name: "secret", TypeName: "string"},
+			},
+		},
+		{
+			PackageName: "testData",
+			Name:        "NewPerson",
+			Fields: []model.Field{
+				{Name: "Name", TypeName: "string", Tag: "`json:\"name\"`"},
+				{Name: "Email", TypeName: "string", Tag: "`json:\"email\"`"},
+				{Name: "BirthYear", TypeName: "int", Tag: "`json:\"birthYear\"`"},
+				{Name: "Color", TypeName: "ColorType", Tag: "`json:\"color\"`"},
+				{Name: "Tags", TypeName: "[]string", Tag: "`json:\"tags\"`"},
+				{Name: "Labels", TypeName: "map[string]string", Tag: "`json:\"labels\"`"},
+			},
+		},
+		{
+			PackageName: "testData",
+			Name:        "PersonUpdate",
+			Fields: []model.Field{
+				{Name: "Name", TypeName: "string", Tag: "`json:\"name\"`"},
+				{Name: "Tags", TypeName: "[]string", Tag: "`json:\"tags\"`"},
+				{Name: "Labels", TypeName: "map[string]string", Tag: "`json:\"labels\"`"},
+			},
+		},
+		{
+			PackageName: "testData",
+			Name:        "Address",
+			Fields:      []model.Field{{Name: "City", TypeName: "string", Tag: "`json:\"city\"`"}},
+		},
+	}
+	e := []model.Enum{
+		{
+			PackageName:  "testData",
+			DocLines:     []string{"// @JsonEnum()"},
+			Name:         "ColorType",
+			EnumLiterals: []model.EnumLiteral{{Name: "ColorRed"}, {Name: "ColorGreen"}},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s, Enums: e}, generator.Config{InputDir: "testData"})
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/terraform-provider-example/provider.go"))
+	assert.NoError(t, err)
+	provider := string(data)
+	assert.Contains(t, provider, "package main\n")
+	assert.Contains(t, provider, `Address: "localhost/providers/example",`)
+	assert.Contains(t, provider, "type exampleProvider struct {")
+	assert.Contains(t, provider, "\tTenant   types.String `tfsdk:\"tenant\"`\n")
+	assert.Contains(t, provider, `endpoint: os.Getenv("EXAMPLE_ENDPOINT"),`)
+	assert.Contains(t, provider, "\treturn []func() resource.Resource{\n\t\tnewPersonResource,\n\t}")
+
+	data, err = ioutil.ReadFile(generationUtil.Prefixed("./testData/terraform-provider-example/resourcePerson.go"))
+	assert.NoError(t, err)
+	resource := string(data)
+	assert.Contains(t, resource, "\t\"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier\"\n")
+	assert.Contains(t, resource, "\t\"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier\"\n")
+	assert.NotContains(t, resource, "listplanmodifier")
+	assert.Contains(t, resource, "// personResourceModel is the terraform-state of example_person. Fields without attribute: address.\n")
+	assert.Contains(t, resource, "\tUID       types.String `tfsdk:\"id\"`\n")
+	assert.Contains(t, resource, "\tBirthYear types.Int64  `tfsdk:\"birth_year\"`\n")
+	assert.Contains(t, resource, "\tTags      types.List   `tfsdk:\"tags\"`\n")
+	assert.Contains(t, resource, "\tLabels    types.Map    `tfsdk:\"labels\"`\n")
+	assert.Contains(t, resource, "\tBirthYear *int64            `json:\"birthYear,omitempty\"`\n")
+	assert.Contains(t, resource, "\tTags      []string          `json:\"tags,omitempty\"`\n")
+	assert.Contains(t, resource, "\tLabels    map[string]string `json:\"labels,omitempty\"`\n")
+	assert.NotContains(t, resource, "secret")
+	assert.Contains(t, resource, `Description: "Person is someone we know",`)
+
+	assert.Contains(t, resource, `			"id": schema.StringAttribute{
+				Description: "",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},`)
+	assert.Contains(t, resource, `			"name": schema.StringAttribute{
+				Description: "Name is the full name",
+				Required:    true,
+			},`)
+	assert.Contains(t, resource, `			"email": schema.StringAttribute{
+				Description: "",
+				Optional:    true,
+				Sensitive:   true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},`)
+	assert.Contains(t, resource, `			"tags": schema.ListAttribute{
+				Description: "",
+				ElementType: types.StringType,
+				Optional:    true,
+			},`)
+	assert.Contains(t, resource, `			"created": schema.StringAttribute{
+				Description: "",
+				Computed:    true,
+			},`)
+
+	assert.Contains(t, resource, `	err := r.client.do(ctx, "POST", "/api/person", map[string]string{}, map[string]string{"X-Tenant-ID": r.client.tenant, "Idempotency-Key": idempotencyKey()}, values, &result)`)
+	assert.Contains(t, resource, `	err := r.client.do(ctx, "GET", "/api/person/"+pathParameter(values.UID), map[string]string{}, map[string]string{"X-Tenant-ID": r.client.tenant}, nil, &result)`)
+	assert.Contains(t, resource, `	err := r.client.do(ctx, "PUT", "/api/person/"+pathParameter(values.UID), map[string]string{}, map[string]string{"X-Tenant-ID": r.client.tenant}, values, nil)
+	if err != nil {
+		return personJSON{}, err
+	}
+	return r.read(ctx, values)`)
+	assert.Contains(t, resource, `	return r.client.do(ctx, "DELETE", "/api/person/"+pathParameter(values.UID), map[string]string{}, map[string]string{"X-Tenant-ID": r.client.tenant}, nil, nil)`)
+
+	assert.Contains(t, resource, "\t\tvalues.BirthYear = m.BirthYear.ValueInt64Pointer()\n")
+	assert.Contains(t, resource, "\t\tdiags.Append(m.Tags.ElementsAs(ctx, &values.Tags, false)...)\n")
+	assert.Contains(t, resource, "\tm.BirthYear = types.Int64PointerValue(values.BirthYear)\n")
+	assert.Contains(t, resource, "\tvar d diag.Diagnostics\n")
+	assert.Contains(t, resource, "\tm.Labels, d = types.MapValueFrom(ctx, types.StringType, values.Labels)\n")
+}
+
+func TestGenerateProviderWithoutUpdate(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @RestService( path = "/api" )`, `// @TerraformResource( provider = "example", name = "person", create = "createPerson", read = "getPerson", delete = "deletePerson", package = "provider", address = "registry.terraform.io/acme/example" )`},
+			Name:        "PersonService",
+			Operations: []*model.Operation{
+				{
+					DocLines:   []string{`// @RestOperation( method = "POST", path = "/person", format = "JSON" )`},
+					Name:       "createPerson",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "person", TypeName: "Person"}},
+					OutputArgs: []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+				},
+				{
+					DocLines:   []string{`// @RestOperation( method = "GET", path = "/person/{uid}", format = "JSON" )`},
+					Name:       "getPerson",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}},
+					OutputArgs: []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+				},
+				{
+					DocLines:   []string{`// @RestOperation( method = "DELETE", path = "/person/{uid}", format = "no_content" )`},
+					Name:       "deletePerson",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}},
+					OutputArgs: []model.Field{{TypeName: "error"}},
+				},
+			},
+		},
+		{
+			PackageName: "testData",
+			Name:        "Person",
+			Fields: []model.Field{
+				{Name: "UID", TypeName: "string", Tag: "`json:\"uid\"`"},
+				{Name: "Tags", TypeName: "[]string", Tag: "`json:\"tags\"`"},
+			},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/provider/provider.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `Address: "registry.terraform.io/acme/example",`)
+
+	data, err = ioutil.ReadFile(generationUtil.Prefixed("./testData/provider/resourcePerson.go"))
+	assert.NoError(t, err)
+	resource := string(data)
+	assert.Contains(t, resource, `			"tags": schema.ListAttribute{
+				Description: "",
+				ElementType: types.StringType,
+				Optional:    true,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+			},`)
+	assert.Contains(t, resource, "func (r *personResource) update(ctx context.Context, values personJSON) (personJSON, error) {\n\treturn r.read(ctx, values)\n}")
+}
+
+func TestGenerateProviderWithInvalidOperations(t *testing.T) {
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			Name:        "PersonService",
+			Operations: []*model.Operation{
+				{
+					DocLines:   []string{`// @RestOperation( method = "POST", path = "/person", format = "JSON" )`},
+					Name:       "createPerson",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "person", TypeName: "Person"}},
+					OutputArgs: []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+				},
+				{
+					DocLines:   []string{`// @RestOperation( method = "GET", path = "/person/{uid}", format = "JSON" )`},
+					Name:       "getPerson",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}},
+					OutputArgs: []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+				},
+				{
+					DocLines:   []string{`// @RestOperation( method = "DELETE", path = "/person/{uid}", format = "no_content" )`},
+					Name:       "deletePerson",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}},
+					OutputArgs: []model.Field{{TypeName: "error"}},
+				},
+				{
+					DocLines:   []string{`// @RestOperation( method = "DELETE", path = "/person/{uid}/versioned", format = "no_content" )`, `// @ETag( required = "true" )`},
+					Name:       "deleteVersioned",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}},
+					OutputArgs: []model.Field{{TypeName: "error"}},
+				},
+				{
+					DocLines:   []string{`// @RestOperation( method = "GET", path = "/person/{uid}/name", format = "JSON" )`},
+					Name:       "getName",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "uid", TypeName: "string"}},
+					OutputArgs: []model.Field{{TypeName: "string"}, {TypeName: "error"}},
+				},
+				{
+					DocLines:   []string{`// @RestOperation( method = "GET", path = "/group/{group}/person/{uid}", format = "JSON" )`},
+					Name:       "getGroupMember",
+					InputArgs:  []model.Field{{Name: "c", TypeName: "context.Context"}, {Name: "group", TypeName: "string"}, {Name: "uid", TypeName: "string"}},
+					OutputArgs: []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+				},
+			},
+		},
+		{
+			PackageName: "testData",
+			Name:        "Person",
+			Fields: []model.Field{
+				{Name: "UID", TypeName: "string", Tag: "`json:\"uid\"`"},
+				{Name: "BirthYear", TypeName: "int", Tag: "`json:\"birthYear\"`"},
+			},
+		},
+	}
+
+	for resource, message := range map[string]string{
+		`// @TerraformResource( provider = "example", name = "person", create = "createPerson", read = "findPerson", delete = "deletePerson" )`:                  "read-operation findPerson is not a @RestOperation of the service",
+		`// @TerraformResource( provider = "example", name = "person", create = "createPerson", read = "getName", delete = "deletePerson" )`:                     "read-operation getName must return a struct",
+		`// @TerraformResource( provider = "example", name = "person", create = "deletePerson", read = "getPerson", delete = "deletePerson" )`:                   "create-operation deletePerson must return the json of the resource",
+		`// @TerraformResource( provider = "example", name = "person", create = "createPerson", read = "getPerson", delete = "deleteVersioned" )`:                "delete-operation deleteVersioned requires an @ETag",
+		`// @TerraformResource( provider = "example", name = "person", create = "createPerson", read = "getGroupMember", delete = "deletePerson" )`:              "path-parameter group of getGroupMember is not an attribute",
+		`// @TerraformResource( provider = "example", name = "person", create = "createPerson", read = "getPerson", delete = "deletePerson", id = "birthYear" )`: "id birthYear is not a string-field of Person",
+	} {
+		cleanup()
+		s[0].DocLines = []string{`// @RestService( path = "/api" )`, resource}
+		err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+		if assert.Error(t, err, resource) {
+			assert.Contains(t, err.Error(), message)
+		}
+	}
+	cleanup()
+}
+
+func TestGenerateForNoRestService(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @TerraformResource( provider = "example", name = "person", create = "createPerson", read = "getPerson", delete = "deletePerson" )`},
+			Name:        "PersonService",
+		},
+	}
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Struct PersonService with @TerraformResource must be a @RestService")
+}
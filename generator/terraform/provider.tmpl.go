@@ -0,0 +1,256 @@
+package terraform
+
+const providerTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+
+// Terraform-provider {{.Provider}}: manages the resources of the rest-services at its endpoint
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func main() {
+	var debug bool
+	flag.BoolVar(&debug, "debug", false, "start the provider for debuggers like delve")
+	flag.Parse()
+
+	err := providerserver.Serve(context.Background(), newProvider, providerserver.ServeOpts{
+		Address: {{Quote .Address}},
+		Debug:   debug,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+var _ provider.Provider = &{{.Provider}}Provider{}
+
+type {{.Provider}}Provider struct {
+}
+
+func newProvider() provider.Provider {
+	return &{{.Provider}}Provider{}
+}
+
+// {{.Provider}}ProviderModel is the configuration of the provider
+type {{.Provider}}ProviderModel struct {
+	Endpoint types.String ` + "`" + `tfsdk:"endpoint"` + "`" + `
+	Headers  types.Map    ` + "`" + `tfsdk:"headers"` + "`" + `
+{{- if .Tenant}}
+	Tenant   types.String ` + "`" + `tfsdk:"tenant"` + "`" + `
+{{- end}}
+}
+
+func (p *{{.Provider}}Provider) Metadata(_ context.Context, _ provider.MetadataRequest, resp *provider.MetadataResponse) {
+	resp.TypeName = "{{.Provider}}"
+}
+
+func (p *{{.Provider}}Provider) Schema(_ context.Context, _ provider.SchemaRequest, resp *provider.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"endpoint": schema.StringAttribute{
+				Description: "The url that the paths of the rest-services are relative to. Defaults to the environment-variable {{ToUpper .Provider}}_ENDPOINT.",
+				Optional:    true,
+			},
+			"headers": schema.MapAttribute{
+				Description: "The headers that are sent with every request, like the ones that authenticate it.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Sensitive:   true,
+			},
+{{- if .Tenant}}
+			"tenant": schema.StringAttribute{
+				Description: "The tenant that the resources belong to. Defaults to the environment-variable {{ToUpper .Provider}}_TENANT.",
+				Optional:    true,
+			},
+{{- end}}
+		},
+	}
+}
+
+func (p *{{.Provider}}Provider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	var config {{.Provider}}ProviderModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client := &apiClient{
+		endpoint: os.Getenv("{{ToUpper .Provider}}_ENDPOINT"),
+		headers:  map[string]string{},
+{{- if .Tenant}}
+		tenant:   os.Getenv("{{ToUpper .Provider}}_TENANT"),
+{{- end}}
+		http:     http.DefaultClient,
+	}
+	if !config.Endpoint.IsNull() {
+		client.endpoint = config.Endpoint.ValueString()
+	}
+	if client.endpoint == "" {
+		resp.Diagnostics.AddError("Missing endpoint", "The endpoint of the provider is neither configured nor in {{ToUpper .Provider}}_ENDPOINT")
+		return
+	}
+	client.endpoint = strings.TrimSuffix(client.endpoint, "/")
+	resp.Diagnostics.Append(config.Headers.ElementsAs(ctx, &client.headers, false)...)
+{{- if .Tenant}}
+	if !config.Tenant.IsNull() {
+		client.tenant = config.Tenant.ValueString()
+	}
+{{- end}}
+	resp.DataSourceData = client
+	resp.ResourceData = client
+}
+
+func (p *{{.Provider}}Provider) Resources(_ context.Context) []func() resource.Resource {
+	return []func() resource.Resource{
+{{- range .Resources}}
+		new{{ToFirstUpper .GoName}}Resource,
+{{- end}}
+	}
+}
+
+func (p *{{.Provider}}Provider) DataSources(_ context.Context) []func() datasource.DataSource {
+	return nil
+}
+
+// apiClient sends the requests of the resources to the endpoint of the provider
+type apiClient struct {
+	endpoint string
+	headers  map[string]string
+{{- if .Tenant}}
+	tenant   string
+{{- end}}
+	http     *http.Client
+}
+
+// apiError is the answer of a rest-operation that failed
+type apiError struct {
+	Method string
+	Path   string
+	Status int
+	Body   string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("%s %s answered with http-status %d: %s", e.Method, e.Path, e.Status, e.Body)
+}
+
+func isNotFound(err error) bool {
+	var apiErr *apiError
+	return errors.As(err, &apiErr) && apiErr.Status == http.StatusNotFound
+}
+
+// do sends a request with the json of body, when given, and decodes the json of the response into result, when
+// given. Parameters and headers without value are left out.
+func (c *apiClient) do(ctx context.Context, method string, path string, query map[string]string, headers map[string]string, body interface{}, result interface{}) error {
+	var content io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		content = bytes.NewReader(data)
+	}
+	values := url.Values{}
+	for key, value := range query {
+		if value != "" {
+			values.Set(key, value)
+		}
+	}
+	target := c.endpoint + path
+	if len(values) > 0 {
+		target += "?" + values.Encode()
+	}
+	request, err := http.NewRequestWithContext(ctx, method, target, content)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Accept", "application/json")
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	for key, value := range c.headers {
+		request.Header.Set(key, value)
+	}
+	for key, value := range headers {
+		if value != "" {
+			request.Header.Set(key, value)
+		}
+	}
+
+	response, err := c.http.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return &apiError{Method: method, Path: path, Status: response.StatusCode, Body: string(data)}
+	}
+	if result == nil || len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, result)
+}
+
+// parameter formats a value of the json of a resource the way the services parse their parameters
+func parameter(value interface{}) string {
+	switch v := value.(type) {
+	case *string:
+		if v != nil {
+			return *v
+		}
+	case *bool:
+		if v != nil {
+			return strconv.FormatBool(*v)
+		}
+	case *int64:
+		if v != nil {
+			return strconv.FormatInt(*v, 10)
+		}
+	case *float64:
+		if v != nil {
+			return strconv.FormatFloat(*v, 'f', -1, 64)
+		}
+	}
+	return ""
+}
+
+// pathParameter formats a value of the json of a resource as segment of a path
+func pathParameter(value interface{}) string {
+	return url.PathEscape(parameter(value))
+}
+
+// idempotencyKey returns a new key for a request that the service handles once only
+func idempotencyKey() string {
+	key := make([]byte, 16)
+	if _, err := rand.Read(key); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(key)
+}
+`
@@ -0,0 +1,245 @@
+package terraform
+
+const resourceTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+{{- range .PlanModifiers}}
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/{{.}}"
+{{- end}}
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource                = &{{.GoName}}Resource{}
+	_ resource.ResourceWithConfigure   = &{{.GoName}}Resource{}
+	_ resource.ResourceWithImportState = &{{.GoName}}Resource{}
+)
+
+// {{.GoName}}Resource manages {{.TypeName}} with the rest-operations of {{.Service}}
+type {{.GoName}}Resource struct {
+	client *apiClient
+}
+
+func new{{ToFirstUpper .GoName}}Resource() resource.Resource {
+	return &{{.GoName}}Resource{}
+}
+
+// {{.GoName}}ResourceModel is the terraform-state of {{.TypeName}}
+{{- with .Skipped}}. Fields without attribute: {{range $idx, $name := .}}{{if $idx}}, {{end}}{{$name}}{{end}}.{{end}}
+type {{.GoName}}ResourceModel struct {
+{{- range .Attributes}}
+	{{.Field}} {{.Type.ModelType}} ` + "`" + `tfsdk:"{{.Name}}"` + "`" + `
+{{- end}}
+}
+
+// {{.GoName}}JSON is {{.TypeName}} in the requests and responses of {{.Service}}
+type {{.GoName}}JSON struct {
+{{- range .Attributes}}
+	{{.Field}} {{if not .Type.IsCollection}}*{{end}}{{.Type.JSON}} ` + "`" + `json:"{{.JSONName}},omitempty"` + "`" + `
+{{- end}}
+}
+
+func (r *{{.GoName}}Resource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_{{.Name}}"
+}
+
+func (r *{{.GoName}}Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: {{Quote .Description}},
+		Attributes: map[string]schema.Attribute{
+{{- range .Attributes}}
+			"{{.Name}}": schema.{{.Type.Attribute}}Attribute{
+				Description: {{Quote .Description}},
+{{- if .Type.IsCollection}}
+				ElementType: {{.Type.ElemType}},
+{{- end}}
+{{- if .Required}}
+				Required:    true,
+{{- end}}
+{{- if .Optional}}
+				Optional:    true,
+{{- end}}
+{{- if .Computed}}
+				Computed:    true,
+{{- end}}
+{{- if .Sensitive}}
+				Sensitive:   true,
+{{- end}}
+{{- if or .UseStateForUnknown .RequiresReplace}}
+				PlanModifiers: []planmodifier.{{.Type.Attribute}}{
+{{- if .UseStateForUnknown}}
+					{{.Type.PlanModifier}}.UseStateForUnknown(),
+{{- end}}
+{{- if .RequiresReplace}}
+					{{.Type.PlanModifier}}.RequiresReplace(),
+{{- end}}
+				},
+{{- end}}
+			},
+{{- end}}
+		},
+	}
+}
+
+func (r *{{.GoName}}Resource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*apiClient)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data", fmt.Sprintf("Expected *apiClient, got %T", req.ProviderData))
+		return
+	}
+	r.client = client
+}
+
+func (r *{{.GoName}}Resource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan {{.GoName}}ResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	result, err := r.create(ctx, plan.toJSON(ctx, &resp.Diagnostics))
+	if err != nil {
+		resp.Diagnostics.AddError("Error creating {{.TypeName}}", err.Error())
+		return
+	}
+	plan.fromJSON(ctx, result, &resp.Diagnostics)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *{{.GoName}}Resource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state {{.GoName}}ResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	result, err := r.read(ctx, state.toJSON(ctx, &resp.Diagnostics))
+	if isNotFound(err) {
+		// removed outside of terraform
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading {{.TypeName}}", err.Error())
+		return
+	}
+	state.fromJSON(ctx, result, &resp.Diagnostics)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *{{.GoName}}Resource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan {{.GoName}}ResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	result, err := r.update(ctx, plan.toJSON(ctx, &resp.Diagnostics))
+	if err != nil {
+		resp.Diagnostics.AddError("Error updating {{.TypeName}}", err.Error())
+		return
+	}
+	plan.fromJSON(ctx, result, &resp.Diagnostics)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *{{.GoName}}Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state {{.GoName}}ResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	err := r.delete(ctx, state.toJSON(ctx, &resp.Diagnostics))
+	if err != nil && !isNotFound(err) {
+		resp.Diagnostics.AddError("Error deleting {{.TypeName}}", err.Error())
+	}
+}
+
+func (r *{{.GoName}}Resource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// create calls {{.Create.Operation}} of {{.Service}}
+func (r *{{.GoName}}Resource) create(ctx context.Context, values {{.GoName}}JSON) ({{.GoName}}JSON, error) {
+	var result {{.GoName}}JSON
+	err := {{template "request" .Create}}, &result)
+	return result, err
+}
+
+// read calls {{.Read.Operation}} of {{.Service}}
+func (r *{{.GoName}}Resource) read(ctx context.Context, values {{.GoName}}JSON) ({{.GoName}}JSON, error) {
+	var result {{.GoName}}JSON
+	err := {{template "request" .Read}}, &result)
+	return result, err
+}
+{{with .Update}}
+// update calls {{.Operation}} of {{$.Service}}
+func (r *{{$.GoName}}Resource) update(ctx context.Context, values {{$.GoName}}JSON) ({{$.GoName}}JSON, error) {
+{{- if .Result}}
+	var result {{$.GoName}}JSON
+	err := {{template "request" .}}, &result)
+	return result, err
+{{- else}}
+	err := {{template "request" .}}, nil)
+	if err != nil {
+		return {{$.GoName}}JSON{}, err
+	}
+	return r.read(ctx, values)
+{{- end}}
+}
+{{- else}}
+// update only reads the resource: the attributes that can be set replace it when they change
+func (r *{{.GoName}}Resource) update(ctx context.Context, values {{.GoName}}JSON) ({{.GoName}}JSON, error) {
+	return r.read(ctx, values)
+}
+{{- end}}
+
+// delete calls {{.Delete.Operation}} of {{.Service}}
+func (r *{{.GoName}}Resource) delete(ctx context.Context, values {{.GoName}}JSON) error {
+	return {{template "request" .Delete}}, nil)
+}
+
+// toJSON returns the known attributes of the resource as json
+func (m *{{.GoName}}ResourceModel) toJSON(ctx context.Context, diags *diag.Diagnostics) {{.GoName}}JSON {
+	values := {{.GoName}}JSON{}
+{{- range .Attributes}}
+	if !m.{{.Field}}.IsUnknown() {
+{{- if .Type.IsCollection}}
+		diags.Append(m.{{.Field}}.ElementsAs(ctx, &values.{{.Field}}, false)...)
+{{- else}}
+		values.{{.Field}} = m.{{.Field}}.Value{{.Type.Attribute}}Pointer()
+{{- end}}
+	}
+{{- end}}
+	return values
+}
+
+// fromJSON sets the attributes of the resource to the json that the service answered with
+func (m *{{.GoName}}ResourceModel) fromJSON(ctx context.Context, values {{.GoName}}JSON, diags *diag.Diagnostics) {
+{{- if .HasCollections}}
+	var d diag.Diagnostics
+{{- end}}
+{{- range .Attributes}}
+{{- if .Type.IsCollection}}
+	m.{{.Field}}, d = types.{{.Type.Attribute}}ValueFrom(ctx, {{.Type.ElemType}}, values.{{.Field}})
+	diags.Append(d...)
+{{- else}}
+	m.{{.Field}} = types.{{.Type.Attribute}}PointerValue(values.{{.Field}})
+{{- end}}
+{{- end}}
+}
+{{define "request"}}r.client.do(ctx, "{{.Method}}", {{.Path}}, map[string]string{
+{{- range $idx, $param := .Query}}{{if $idx}}, {{end}}"{{$param.Key}}": {{$param.Value}}{{end -}}
+}, map[string]string{
+{{- range $idx, $param := .Headers}}{{if $idx}}, {{end}}"{{$param.Key}}": {{$param.Value}}{{end -}}
+}, {{if .Body}}values{{else}}nil{{end}}{{end}}`
@@ -0,0 +1,46 @@
+package terraformAnnotation
+
+import (
+	"regexp"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+)
+
+const (
+	TypeTerraformResource = "TerraformResource"
+	ParamProvider         = "provider"
+	ParamName             = "name"
+	ParamCreate           = "create"
+	ParamRead             = "read"
+	ParamUpdate           = "update"
+	ParamDelete           = "delete"
+	ParamID               = "id"
+	ParamPackage          = "package"
+	ParamAddress          = "address"
+)
+
+var (
+	providerPattern = regexp.MustCompile(`^[a-z][a-z0-9]*$`)
+	namePattern     = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+)
+
+func Get() []annotation.AnnotationDescriptor {
+	return []annotation.AnnotationDescriptor{
+		{
+			Name:       TypeTerraformResource,
+			ParamNames: []string{ParamProvider, ParamName, ParamCreate, ParamRead, ParamUpdate, ParamDelete, ParamID, ParamPackage, ParamAddress},
+			Validator:  validateTerraformResourceAnnotation,
+		}}
+}
+
+func validateTerraformResourceAnnotation(annot annotation.Annotation) bool {
+	if annot.Name == TypeTerraformResource {
+		for _, param := range []string{ParamCreate, ParamRead, ParamDelete} {
+			if annot.Attributes[param] == "" {
+				return false
+			}
+		}
+		return providerPattern.MatchString(annot.Attributes[ParamProvider]) && namePattern.MatchString(annot.Attributes[ParamName])
+	}
+	return false
+}
@@ -0,0 +1,32 @@
+package terraformAnnotation
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrectTerraformResourceAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	annotation, ok := registry.ResolveAnnotation(`// @TerraformResource( provider = "example", name = "person", create = "createPerson", read = "getPerson", update = "updatePerson", delete = "deletePerson", id = "uid" )`)
+	assert.True(t, ok)
+	assert.Equal(t, TypeTerraformResource, annotation.Name)
+	assert.Equal(t, "example", annotation.Attributes[ParamProvider])
+	assert.Equal(t, "person", annotation.Attributes[ParamName])
+	assert.Equal(t, "updatePerson", annotation.Attributes[ParamUpdate])
+	assert.Equal(t, "uid", annotation.Attributes[ParamID])
+
+	_, ok = registry.ResolveAnnotation(`// @TerraformResource( provider = "example", name = "person_tag", create = "addTag", read = "getTag", delete = "removeTag" )`)
+	assert.True(t, ok)
+}
+
+func TestInvalidTerraformResourceAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @TerraformResource( name = "person", create = "createPerson", read = "getPerson", delete = "deletePerson" )`}))
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @TerraformResource( provider = "my_example", name = "person", create = "createPerson", read = "getPerson", delete = "deletePerson" )`}))
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @TerraformResource( provider = "example", name = "Person", create = "createPerson", read = "getPerson", delete = "deletePerson" )`}))
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @TerraformResource( provider = "example", name = "person", create = "createPerson", read = "getPerson" )`}))
+}
@@ -0,0 +1,116 @@
+package terraform
+
+import (
+	"strings"
+
+	"github.com/MarcGrol/golangAnnotations/generator/jsonHelpers"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+// tfType is the kind of terraform-attribute of a go-type, with the go-type of its value in the json of a resource
+type tfType struct {
+	// Attribute is the kind of attribute in the schema, like String for a schema.StringAttribute
+	Attribute string
+	// Elem is the kind of the elements of a List or Map
+	Elem string
+	JSON string
+}
+
+var scalarTypes = map[string]tfType{
+	"string":        {Attribute: "String", JSON: "string"},
+	"bool":          {Attribute: "Bool", JSON: "bool"},
+	"int":           {Attribute: "Int64", JSON: "int64"},
+	"int8":          {Attribute: "Int64", JSON: "int64"},
+	"int16":         {Attribute: "Int64", JSON: "int64"},
+	"int32":         {Attribute: "Int64", JSON: "int64"},
+	"int64":         {Attribute: "Int64", JSON: "int64"},
+	"uint":          {Attribute: "Int64", JSON: "int64"},
+	"uint8":         {Attribute: "Int64", JSON: "int64"},
+	"uint16":        {Attribute: "Int64", JSON: "int64"},
+	"uint32":        {Attribute: "Int64", JSON: "int64"},
+	"uint64":        {Attribute: "Int64", JSON: "int64"},
+	"float32":       {Attribute: "Float64", JSON: "float64"},
+	"float64":       {Attribute: "Float64", JSON: "float64"},
+	"[]byte":        {Attribute: "String", JSON: "string"},
+	"time.Time":     {Attribute: "String", JSON: "string"},
+	"mydate.MyDate": {Attribute: "String", JSON: "string"},
+}
+
+func (t tfType) IsCollection() bool {
+	return t.Elem != ""
+}
+
+// PlanModifier is the package with the plan-modifiers of the kind of attribute
+func (t tfType) PlanModifier() string {
+	return strings.ToLower(t.Attribute) + "planmodifier"
+}
+
+// ModelType is the go-type of the attribute in the terraform-state
+func (t tfType) ModelType() string {
+	return "types." + t.Attribute
+}
+
+// ElemType is the attr.Type of the elements of a List or Map
+func (t tfType) ElemType() string {
+	return "types." + t.Elem + "Type"
+}
+
+// typeOf returns the terraform-type of a go-type, the same way as it is marshalled to json. Structs, and slices and
+// maps of them, have no attribute: false is returned for them.
+func typeOf(typeName string, parsedSources model.ParsedSources) (tfType, bool) {
+	typeName = strings.TrimPrefix(typeName, "*")
+	if scalar, ok := scalarOf(typeName, parsedSources); ok {
+		return scalar, true
+	}
+	if strings.HasPrefix(typeName, "[]") {
+		if elem, ok := scalarOf(strings.TrimPrefix(typeName[2:], "*"), parsedSources); ok {
+			return tfType{Attribute: "List", Elem: elem.Attribute, JSON: "[]" + elem.JSON}, true
+		}
+		return tfType{}, false
+	}
+	if field := (model.Field{TypeName: typeName}); field.IsMap() {
+		keyType, valueType := field.SplitMapTypeNames()
+		key, ok := scalarOf(keyType, parsedSources)
+		if !ok || key.Attribute != "String" {
+			return tfType{}, false
+		}
+		if elem, ok := scalarOf(strings.TrimPrefix(valueType, "*"), parsedSources); ok {
+			return tfType{Attribute: "Map", Elem: elem.Attribute, JSON: "map[string]" + elem.JSON}, true
+		}
+	}
+	return tfType{}, false
+}
+
+func scalarOf(typeName string, parsedSources model.ParsedSources) (tfType, bool) {
+	if scalar, found := scalarTypes[typeName]; found {
+		return scalar, true
+	}
+	packageName, name := model.Field{TypeName: typeName}.SplitTypeName()
+	for _, e := range parsedSources.Enums {
+		if e.Name == name && (packageName == "" || e.PackageName == packageName) {
+			if jsonHelpers.IsJSONEnum(e) {
+				return scalarTypes["string"], true
+			}
+			// the literals of other enums are numbers
+			return scalarTypes["int"], true
+		}
+	}
+	for _, td := range parsedSources.Typedefs {
+		// structs and interfaces are typedefs as well, without a type
+		if td.Name == name && (packageName == "" || td.PackageName == packageName) && td.Type != "" {
+			return scalarOf(td.Type, parsedSources)
+		}
+	}
+	return tfType{}, false
+}
+
+// goName converts a terraform-name like "person_tag" into the go-identifier "personTag"
+func goName(name string) string {
+	parts := strings.Split(name, "_")
+	for idx := 1; idx < len(parts); idx++ {
+		if parts[idx] != "" {
+			parts[idx] = strings.ToUpper(parts[idx][:1]) + parts[idx][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
@@ -0,0 +1,266 @@
+package testFactory
+
+import (
+	"fmt"
+	"strconv"
+	"text/template"
+	"unicode"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/MarcGrol/golangAnnotations/generator/example"
+	"github.com/MarcGrol/golangAnnotations/generator/example/exampleAnnotation"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/generator/testFactory/testFactoryAnnotation"
+	"github.com/MarcGrol/golangAnnotations/generator/validation/validationAnnotation"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+type testFactoryContext struct {
+	PackageName string
+	Factories   []factory
+}
+
+type factory struct {
+	Struct model.Struct
+	Fields []factoryField
+}
+
+type factoryField struct {
+	Field     model.Field
+	FakeValue string
+}
+
+type Generator struct {
+}
+
+func NewGenerator() generator.Generator {
+	return &Generator{}
+}
+
+func (eg *Generator) Name() string {
+	return "test-factory"
+}
+
+func (eg *Generator) GetTemplates() []generator.Template {
+	return []generator.Template{
+		{Name: "test-factories", APIVersion: 1, Text: testFactoryTemplate},
+	}
+}
+
+func (eg *Generator) GetAnnotations() []annotation.AnnotationDescriptor {
+	annotations := append(testFactoryAnnotation.Get(), validationAnnotation.Get()...)
+	return append(annotations, exampleAnnotation.Get()...)
+}
+
+func (eg *Generator) Generate(parsedSource model.ParsedSources, config generator.Config) ([]generator.OutputFile, error) {
+	return generate(config, parsedSource.Structs, parsedSource.Enums)
+}
+
+func generate(config generator.Config, structs []model.Struct, enums []model.Enum) ([]generator.OutputFile, error) {
+	packageName, err := generationUtil.GetPackageNameForStructs(structs)
+	if packageName == "" || err != nil {
+		return nil, err
+	}
+	targetDir, err := generationUtil.DetermineTargetPath(config.InputDir, packageName)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []generator.OutputFile{}
+
+	factories := make([]factory, 0)
+	for _, s := range structs {
+		if !IsTestFactory(s) {
+			continue
+		}
+		factories = append(factories, factory{
+			Struct: s,
+			Fields: getFactoryFields(s, structs, enums),
+		})
+	}
+	if len(factories) == 0 {
+		return files, nil
+	}
+
+	file, err := generationUtil.Generate(generationUtil.Info{
+		Src:            packageName,
+		TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/testFactories.go", targetDir)),
+		TemplateName:   "test-factories",
+		TemplateString: testFactoryTemplate,
+		Config:         config,
+		FuncMap:        customTemplateFuncs,
+		Data: testFactoryContext{
+			PackageName: packageName,
+			Factories:   factories,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Error generating test-factories for package %s:%w", packageName, err)
+	}
+	files = append(files, file)
+	return files, nil
+}
+
+var customTemplateFuncs = template.FuncMap{}
+
+func IsTestFactory(s model.Struct) bool {
+	annotations := annotation.NewRegistry(testFactoryAnnotation.Get())
+	_, ok := annotations.ResolveAnnotationByName(s.DocLines, testFactoryAnnotation.TypeTestFactory)
+	return ok
+}
+
+func getFactoryFields(s model.Struct, structs []model.Struct, enums []model.Enum) []factoryField {
+	fields := make([]factoryField, 0, len(s.Fields))
+	for _, f := range s.Fields {
+		if f.Name == "" || !unicode.IsUpper([]rune(f.Name)[0]) {
+			continue
+		}
+		fields = append(fields, factoryField{
+			Field:     f,
+			FakeValue: GetFakeValue(f, structs, enums),
+		})
+	}
+	return fields
+}
+
+// GetFakeValue returns the go-expression that provides a plausible value for a field: the literal of
+// its @Example annotation or a value that uses the sequence-number "seq" to stay unique across
+// invocations. Pointers and slices, also nested ones like [][]*string or *[]Thing, get a value of their
+// element. Returns "" when the zero-value must be kept.
+func GetFakeValue(f model.Field, structs []model.Struct, enums []model.Enum) string {
+	if f.IsPointer() {
+		typeName := f.DereferencedTypeName()
+		value := GetFakeValue(model.Field{Name: f.Name, TypeName: typeName, DocLines: f.DocLines}, structs, enums)
+		if value == "" {
+			return ""
+		}
+		if isFactoryStruct(typeName, structs) {
+			return fmt.Sprintf("New%sForTest()", typeName)
+		}
+		return fmt.Sprintf("func() *%s { v := %s; return &v }()", typeName, value)
+	}
+	if f.IsSlice() {
+		elementType := f.SliceElementTypeName()
+		element := GetFakeValue(model.Field{Name: f.Name, TypeName: elementType, DocLines: f.DocLines}, structs, enums)
+		if element == "" {
+			return ""
+		}
+		return fmt.Sprintf("[]%s{%s}", elementType, element)
+	}
+	return fakeValueForType(f, structs, enums)
+}
+
+func fakeValueForType(f model.Field, structs []model.Struct, enums []model.Enum) string {
+	typeName := f.DereferencedTypeName()
+
+	if literal, ok := example.GetExample(f.DocLines); ok {
+		if value, ok := exampleLiteral(typeName, literal); ok {
+			return value
+		}
+	}
+
+	annotations := annotation.NewRegistry(validationAnnotation.Get())
+	ann, validated := annotations.ResolveAnnotationByName(f.DocLines, validationAnnotation.TypeValidate)
+
+	switch typeName {
+	case "string":
+		if validated {
+			if value, ok := fakeFormattedString(ann.Attributes[validationAnnotation.ParamFormat]); ok {
+				return value
+			}
+		}
+		return fmt.Sprintf("fmt.Sprintf(\"%s-%%d\", seq)", generationUtil.LowerCamelCase(f.Name))
+	case "int", "int32", "int64":
+		return fmt.Sprintf("%s(%s)", typeName, fakeNumber(ann, validated, false))
+	case "float32", "float64":
+		return fmt.Sprintf("%s(%s)", typeName, fakeNumber(ann, validated, true))
+	case "bool":
+		return "true"
+	case "time.Time":
+		return "time.Date(2020, time.January, 1, 12, 0, 0, 0, time.UTC).Add(time.Duration(seq) * time.Hour)"
+	}
+
+	for _, e := range enums {
+		if e.Name == typeName && len(e.EnumLiterals) > 0 {
+			return e.EnumLiterals[0].Name
+		}
+	}
+	if isFactoryStruct(typeName, structs) {
+		return fmt.Sprintf("*New%sForTest()", typeName)
+	}
+	return ""
+}
+
+// exampleLiteral converts the literal of an @Example annotation into a go-expression for primitive types
+func exampleLiteral(typeName string, literal string) (string, bool) {
+	switch typeName {
+	case "string":
+		return strconv.Quote(literal), true
+	case "int", "int32", "int64", "float32", "float64":
+		if _, err := strconv.ParseFloat(literal, 64); err == nil {
+			return fmt.Sprintf("%s(%s)", typeName, literal), true
+		}
+	case "bool":
+		if value, err := strconv.ParseBool(literal); err == nil {
+			return strconv.FormatBool(value), true
+		}
+	}
+	return "", false
+}
+
+func fakeFormattedString(format string) (string, bool) {
+	switch format {
+	case validationAnnotation.FormatEmail:
+		return "fmt.Sprintf(\"user%d@example.com\", seq)", true
+	case validationAnnotation.FormatUUID:
+		return "fmt.Sprintf(\"00000000-0000-4000-8000-%012d\", seq)", true
+	case validationAnnotation.FormatURL:
+		return "fmt.Sprintf(\"https://example.com/%d\", seq)", true
+	case validationAnnotation.FormatPhone:
+		return "fmt.Sprintf(\"+3120%07d\", seq)", true
+	case validationAnnotation.FormatDate:
+		return "time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, int(seq)).Format(\"2006-01-02\")", true
+	}
+	return "", false
+}
+
+// fakeNumber keeps the sequence-number within the range demanded by @Validate(min, max)
+func fakeNumber(ann annotation.Annotation, validated bool, fraction bool) string {
+	if !validated {
+		return "seq"
+	}
+	min, hasMin := parseNumber(ann.Attributes[validationAnnotation.ParamMin])
+	max, hasMax := parseNumber(ann.Attributes[validationAnnotation.ParamMax])
+	switch {
+	case hasMin && hasMax && max > min:
+		if fraction {
+			return fmt.Sprintf("%v + float64(seq%%%d)*%v", min, 100, (max-min)/100)
+		}
+		return fmt.Sprintf("%d + seq%%%d", int64(min), int64(max-min)+1)
+	case hasMin && hasMax:
+		return fmt.Sprintf("%v", min)
+	case hasMin:
+		return fmt.Sprintf("%v + seq", min)
+	case hasMax:
+		return fmt.Sprintf("%v - seq", max)
+	}
+	return "seq"
+}
+
+func parseNumber(value string) (float64, bool) {
+	if value == "" {
+		return 0, false
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	return number, err == nil
+}
+
+func isFactoryStruct(typeName string, structs []model.Struct) bool {
+	for _, s := range structs {
+		if s.Name == typeName && IsTestFactory(s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package testFactory
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func cleanup() {
+	os.Remove(generationUtil.Prefixed("./testData/testFactories.go"))
+}
+
+func TestGenerateForTestFactory(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			DocLines:    []string{"// @TestFactory()"},
+			PackageName: "testData",
+			Name:        "Customer",
+			Fields: []model.Field{
+				{Name: "UID", TypeName: "string", DocLines: []string{`// @Validate( format = "uuid" )`}},
+				{Name: "Email", TypeName: "string", DocLines: []string{`// @Validate( format = "email" )`}},
+				{Name: "Name", TypeName: "string"},
+				{Name: "Age", TypeName: "int", DocLines: []string{`// @Validate( min = "18", max = "99" )`}},
+				{Name: "Status", TypeName: "Status"},
+				{Name: "Address", TypeName: "*Address"},
+				{Name: "Tags", TypeName: "[]string"},
+				{Name: "CreatedAt", TypeName: "time.Time"},
+				{Name: "internal", TypeName: "string"},
+			},
+		},
+		{
+			DocLines:    []string{"// @TestFactory()"},
+			PackageName: "testData",
+			Name:        "Address",
+			Fields: []model.Field{
+				{Name: "Street", TypeName: "string"},
+			},
+		},
+	}
+	enums := []model.Enum{
+		{
+			PackageName:  "testData",
+			Name:         "Status",
+			EnumLiterals: []model.EnumLiteral{{Name: "StatusActive"}, {Name: "StatusBlocked"}},
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s, Enums: enums}, generator.Config{InputDir: "testData"})
+	assert.Nil(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/testFactories.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "func NewCustomerForTest(options ...CustomerForTestOption) *Customer {")
+	assert.Contains(t, string(data), `UID:       fmt.Sprintf("00000000-0000-4000-8000-%012d", seq),`)
+	assert.Contains(t, string(data), `Email:     fmt.Sprintf("user%d@example.com", seq),`)
+	assert.Contains(t, string(data), `Name:      fmt.Sprintf("name-%d", seq),`)
+	assert.Contains(t, string(data), `Age:       int(18 + seq%82),`)
+	assert.Contains(t, string(data), `Status:    StatusActive,`)
+	assert.Contains(t, string(data), `Address:   NewAddressForTest(),`)
+	assert.Contains(t, string(data), `Tags:      []string{fmt.Sprintf("tags-%d", seq)},`)
+	assert.Contains(t, string(data), "func WithCustomerEmail(value string) CustomerForTestOption {")
+	assert.Contains(t, string(data), "func NewAddressForTest(options ...AddressForTestOption) *Address {")
+	assert.NotContains(t, string(data), "internal")
+}
+
+func TestGetFakeValueKeepsZeroValueForUnknownTypes(t *testing.T) {
+	assert.Equal(t, "", GetFakeValue(model.Field{Name: "Payload", TypeName: "json.RawMessage"}, nil, nil))
+}
+
+func TestGetFakeValueForPointerToPrimitive(t *testing.T) {
+	assert.Equal(t, "func() *bool { v := true; return &v }()", GetFakeValue(model.Field{Name: "Active", TypeName: "*bool"}, nil, nil))
+}
+
+func TestGetFakeValueUsesExample(t *testing.T) {
+	docLines := []string{`// @Example( value = "john@example.com" )`, `// @Validate( format = "email" )`}
+	assert.Equal(t, `"john@example.com"`, GetFakeValue(model.Field{Name: "Email", TypeName: "string", DocLines: docLines}, nil, nil))
+	assert.Equal(t, "int64(42)", GetFakeValue(model.Field{Name: "Age", TypeName: "int64", DocLines: []string{`// @Example( value = "42" )`}}, nil, nil))
+	assert.Equal(t, "int(seq)", GetFakeValue(model.Field{Name: "Age", TypeName: "int", DocLines: []string{`// @Example( value = "old" )`}}, nil, nil))
+}
+
+func TestGetFakeValueForNestedSlicesAndPointers(t *testing.T) {
+	assert.Equal(t, `[][]string{[]string{fmt.Sprintf("tags-%d", seq)}}`,
+		GetFakeValue(model.Field{Name: "Tags", TypeName: "[][]string"}, nil, nil))
+	assert.Equal(t, `[]*string{func() *string { v := fmt.Sprintf("tags-%d", seq); return &v }()}`,
+		GetFakeValue(model.Field{Name: "Tags", TypeName: "[]*string"}, nil, nil))
+	assert.Equal(t, `func() *[]bool { v := []bool{true}; return &v }()`,
+		GetFakeValue(model.Field{Name: "Flags", TypeName: "*[]bool"}, nil, nil))
+	assert.Equal(t, "", GetFakeValue(model.Field{Name: "Things", TypeName: "[][]*pkg.Thing"}, nil, nil))
+}
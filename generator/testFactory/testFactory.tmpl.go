@@ -0,0 +1,51 @@
+package testFactory
+
+const testFactoryTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+
+package {{.PackageName}}
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+var testFactorySequence int64
+
+func nextTestFactorySequence() int64 {
+	return atomic.AddInt64(&testFactorySequence, 1)
+}
+
+{{range $factory := .Factories -}}
+{{$name := $factory.Struct.Name -}}
+// {{$name}}ForTestOption overrides the fake value of a single field of a {{$name}} created by New{{$name}}ForTest
+type {{$name}}ForTestOption func(*{{$name}})
+
+// New{{$name}}ForTest creates a {{$name}} filled with plausible fake values; use the options to override specific fields
+func New{{$name}}ForTest(options ...{{$name}}ForTestOption) *{{$name}} {
+	seq := nextTestFactorySequence()
+	_ = seq
+
+	instance := &{{$name}}{
+	{{range $factory.Fields -}}
+	{{if .FakeValue -}}
+		{{.Field.Name}}: {{.FakeValue}},
+	{{end -}}
+	{{end -}}
+	}
+	for _, option := range options {
+		option(instance)
+	}
+	return instance
+}
+
+{{range $factory.Fields -}}
+func With{{$name}}{{.Field.Name}}(value {{.Field.TypeName}}) {{$name}}ForTestOption {
+	return func(s *{{$name}}) {
+		s.{{.Field.Name}} = value
+	}
+}
+
+{{end -}}
+{{end -}}
+`
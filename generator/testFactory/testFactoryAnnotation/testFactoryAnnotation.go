@@ -0,0 +1,23 @@
+package testFactoryAnnotation
+
+import "github.com/MarcGrol/golangAnnotations/generator/annotation"
+
+const (
+	TypeTestFactory = "TestFactory"
+)
+
+func Get() []annotation.AnnotationDescriptor {
+	return []annotation.AnnotationDescriptor{
+		{
+			Name:       TypeTestFactory,
+			ParamNames: []string{},
+			Validator:  validateTestFactoryAnnotation,
+		}}
+}
+
+func validateTestFactoryAnnotation(annot annotation.Annotation) bool {
+	if annot.Name == TypeTestFactory {
+		return true
+	}
+	return false
+}
@@ -0,0 +1,22 @@
+package testFactoryAnnotation
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrectTestFactoryAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	annotation, ok := registry.ResolveAnnotation(`// @TestFactory()`)
+	assert.True(t, ok)
+	assert.Equal(t, TypeTestFactory, annotation.Name)
+}
+
+func TestEmptyTestFactoryAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	assert.Empty(t, registry.ResolveAnnotations([]string{``}))
+}
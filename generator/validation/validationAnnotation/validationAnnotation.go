@@ -0,0 +1,50 @@
+package validationAnnotation
+
+import (
+	"strconv"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+)
+
+const (
+	TypeValidate  = "Validate"
+	ParamFormat   = "format"
+	ParamMin      = "min"
+	ParamMax      = "max"
+	ParamRequired = "required"
+	FormatEmail   = "email"
+	FormatUUID    = "uuid"
+	FormatURL     = "url"
+	FormatPhone   = "phone"
+	FormatDate    = "date"
+)
+
+func Get() []annotation.AnnotationDescriptor {
+	return []annotation.AnnotationDescriptor{
+		{
+			Name:       TypeValidate,
+			ParamNames: []string{ParamFormat, ParamMin, ParamMax, ParamRequired},
+			Validator:  validateValidateAnnotation,
+		}}
+}
+
+func validateValidateAnnotation(annot annotation.Annotation) bool {
+	if annot.Name == TypeValidate {
+		if format, hasFormat := annot.Attributes[ParamFormat]; hasFormat {
+			switch format {
+			case FormatEmail, FormatUUID, FormatURL, FormatPhone, FormatDate:
+			default:
+				return false
+			}
+		}
+		for _, param := range []string{ParamMin, ParamMax} {
+			if value, found := annot.Attributes[param]; found {
+				if _, err := strconv.ParseFloat(value, 64); err != nil {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,31 @@
+package validationAnnotation
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrectValidateAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	annotation, ok := registry.ResolveAnnotation(`// @Validate( format = "email", required = "true" )`)
+	assert.True(t, ok)
+	assert.Equal(t, TypeValidate, annotation.Name)
+	assert.Equal(t, "email", annotation.Attributes[ParamFormat])
+}
+
+func TestValidateAnnotationWithUnknownFormat(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	_, ok := registry.ResolveAnnotation(`// @Validate( format = "iban" )`)
+	assert.False(t, ok)
+}
+
+func TestValidateAnnotationWithInvalidRange(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	_, ok := registry.ResolveAnnotation(`// @Validate( min = "ten" )`)
+	assert.False(t, ok)
+}
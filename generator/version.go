@@ -0,0 +1,49 @@
+package generator
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// versionLinePrefix starts the line of the header that tells which version of golangAnnotations generated a file
+const versionLinePrefix = "golangAnnotations version: "
+
+// headerScanLines is how far into a file the version-line of its header is searched, leaving room for a license
+const headerScanLines = 40
+
+// ReadGeneratedVersion returns the version of golangAnnotations that generated the file, according to its
+// header, or "" when the file does not exist or its header does not mention a version
+func ReadGeneratedVersion(filename string) (string, error) {
+	file, err := os.Open(filename)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("Error reading file %s:%w", filename, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for lineCount := 0; lineCount < headerScanLines && scanner.Scan(); lineCount++ {
+		line := scanner.Text()
+		if idx := strings.Index(line, versionLinePrefix); idx >= 0 {
+			fields := strings.Fields(line[idx+len(versionLinePrefix):])
+			if len(fields) > 0 {
+				return fields[0], nil
+			}
+		}
+	}
+	return "", scanner.Err()
+}
+
+// MajorVersion returns the part of a version that changes on incompatible changes: the major version, or for
+// versions below 1 the minor version as well, like semantic versioning does
+func MajorVersion(version string) string {
+	parts := strings.SplitN(strings.TrimPrefix(version, "v"), ".", 3)
+	if parts[0] == "0" && len(parts) > 1 {
+		return parts[0] + "." + strings.SplitN(parts[1], "-", 2)[0]
+	}
+	return strings.SplitN(parts[0], "-", 2)[0]
+}
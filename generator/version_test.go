@@ -0,0 +1,46 @@
+package generator
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadGeneratedVersion(t *testing.T) {
+	dir := t.TempDir()
+	for _, tc := range []struct {
+		filename string
+		header   Header
+		expected string
+	}{
+		{filename: "gen_a.go", header: Header{Version: "v1.2.3"}, expected: "v1.2.3"},
+		{filename: "gen_a.md", header: Header{Version: "0.8", License: "Copyright"}, expected: "0.8"},
+		{filename: "gen_b.go", header: Header{WithSource: true}, expected: ""},
+	} {
+		t.Run(tc.filename, func(t *testing.T) {
+			filename := filepath.Join(dir, tc.filename)
+			syntax := commentSyntaxes[filepath.Ext(filename)]
+			content := syntax.comment(DefaultMarker) + "\n\npackage a\n"
+			files := ApplyHeader([]OutputFile{{Filename: filename, Content: []byte(content)}}, "rest", Config{Header: tc.header})
+			assert.NoError(t, ioutil.WriteFile(filename, files[0].Content, 0644))
+
+			version, err := ReadGeneratedVersion(filename)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, version)
+		})
+	}
+
+	version, err := ReadGeneratedVersion(filepath.Join(dir, "gen_missing.go"))
+	assert.NoError(t, err)
+	assert.Equal(t, "", version)
+}
+
+func TestMajorVersion(t *testing.T) {
+	assert.Equal(t, "1", MajorVersion("v1.2.3"))
+	assert.Equal(t, "2", MajorVersion("2.0.0-rc1"))
+	assert.Equal(t, "0.8", MajorVersion("0.8"))
+	assert.Equal(t, "0.9", MajorVersion("v0.9.1-3-gabcdef"))
+	assert.Equal(t, "1", MajorVersion("v1-5-gabcdef"))
+}
@@ -0,0 +1,269 @@
+package warehouse
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/MarcGrol/golangAnnotations/generator/event"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/generator/jsonHelpers"
+	"github.com/MarcGrol/golangAnnotations/generator/warehouse/warehouseAnnotation"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+// Column describes how a field of an event is stored in the warehouse-table
+type Column struct {
+	Name     string
+	Type     string
+	Field    model.Field
+	Required bool
+	Repeated bool
+}
+
+type warehouseContext struct {
+	PackageName string
+	Aggregates  []string
+	Events      []warehouseEvent
+}
+
+type warehouseEvent struct {
+	Event   model.Struct
+	Table   string
+	Columns []Column
+}
+
+// metadataColumns are taken from the envelope and are part of every warehouse-table
+var metadataColumns = []Column{
+	{Name: "event_uuid", Type: "STRING", Required: true},
+	{Name: "event_type_name", Type: "STRING", Required: true},
+	{Name: "aggregate_name", Type: "STRING", Required: true},
+	{Name: "aggregate_uid", Type: "STRING", Required: true},
+	{Name: "timestamp", Type: "TIMESTAMP", Required: true},
+}
+
+type Generator struct {
+}
+
+func NewGenerator() generator.Generator {
+	return &Generator{}
+}
+
+func (eg *Generator) Name() string {
+	return "warehouse"
+}
+
+// GetDependencies returns the generators of the code that the generated code refers to: the event-names of the events
+func (eg *Generator) GetDependencies() []string {
+	return []string{"event"}
+}
+
+func (eg *Generator) GetTemplates() []generator.Template {
+	return []generator.Template{
+		{Name: "warehouse", APIVersion: 1, Text: warehouseTemplate},
+	}
+}
+
+func (eg *Generator) GetAnnotations() []annotation.AnnotationDescriptor {
+	return warehouseAnnotation.Get()
+}
+
+func (eg *Generator) Generate(parsedSource model.ParsedSources, config generator.Config) ([]generator.OutputFile, error) {
+	return generate(config, parsedSource.Structs)
+}
+
+func generate(config generator.Config, structs []model.Struct) ([]generator.OutputFile, error) {
+	packageName, err := generationUtil.GetPackageNameForStructs(structs)
+	if packageName == "" || err != nil {
+		return nil, err
+	}
+	targetDir, err := generationUtil.DetermineTargetPath(config.InputDir, packageName)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []generator.OutputFile{}
+
+	events := []warehouseEvent{}
+	aggregates := map[string]bool{}
+	for _, s := range structs {
+		if !IsWarehouseEvent(s) {
+			continue
+		}
+		if !event.IsEvent(s) {
+			return nil, generator.StructError(s, warehouseAnnotation.TypeWarehouse, "Warehouse-struct %s must be annotated with @Event", s.Name)
+		}
+		events = append(events, warehouseEvent{
+			Event:   s,
+			Table:   GetTableName(s),
+			Columns: GetColumns(s),
+		})
+		aggregates[event.GetAggregateName(s)] = true
+	}
+	if len(events) == 0 {
+		return files, nil
+	}
+
+	aggregateNames := make([]string, 0, len(aggregates))
+	for name := range aggregates {
+		aggregateNames = append(aggregateNames, name)
+	}
+	sort.Strings(aggregateNames)
+
+	file, err := generationUtil.Generate(generationUtil.Info{
+		Src:            fmt.Sprintf("%s.%s", packageName, "events"),
+		TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/warehouse.go", targetDir)),
+		TemplateName:   "warehouse",
+		TemplateString: warehouseTemplate,
+		Config:         config,
+		FuncMap:        customTemplateFuncs,
+		Data: warehouseContext{
+			PackageName: packageName,
+			Aggregates:  aggregateNames,
+			Events:      events,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Error generating warehouse for package %s:%w", packageName, err)
+	}
+	files = append(files, file)
+	return files, nil
+}
+
+var customTemplateFuncs = template.FuncMap{
+	"MetadataColumns": func() []Column { return metadataColumns },
+	"FieldType":       FieldType,
+	"Value":           Value,
+	"DDL":             DDL,
+	"UsesDateSlices":  usesDateSlices,
+}
+
+func IsWarehouseEvent(s model.Struct) bool {
+	annotations := annotation.NewRegistry(warehouseAnnotation.Get())
+	_, ok := annotations.ResolveAnnotationByName(s.DocLines, warehouseAnnotation.TypeWarehouse)
+	return ok
+}
+
+func GetTableName(s model.Struct) string {
+	annotations := annotation.NewRegistry(warehouseAnnotation.Get())
+	if ann, ok := annotations.ResolveAnnotationByName(s.DocLines, warehouseAnnotation.TypeWarehouse); ok {
+		if table := ann.Attributes[warehouseAnnotation.ParamTable]; table != "" {
+			return table
+		}
+	}
+	return generationUtil.SnakeCase(s.Name)
+}
+
+// GetColumns returns the payload-columns of an event: sensitive and encrypted fields never end up in the warehouse
+func GetColumns(s model.Struct) []Column {
+	columns := []Column{}
+	for _, f := range s.Fields {
+		if f.Name == "" || !unicode.IsUpper([]rune(f.Name)[0]) {
+			continue
+		}
+		if event.IsSensitiveField(f) || event.IsDeepSensitiveField(f) || event.IsCustomSensitiveField(f) || jsonHelpers.IsEncryptedField(f) {
+			continue
+		}
+		column := Column{
+			Name:  generationUtil.SnakeCase(f.Name),
+			Field: f,
+		}
+		if f.IsPrimitiveSlice() || f.IsDateSlice() {
+			column.Type = columnType(f.SliceElementTypeName())
+			column.Repeated = true
+		} else {
+			column.Type = columnType(f.DereferencedTypeName())
+			column.Required = !f.IsPointer() && column.Type != "JSON"
+		}
+		columns = append(columns, column)
+	}
+	return columns
+}
+
+func columnType(typeName string) string {
+	switch typeName {
+	case "string":
+		return "STRING"
+	case "bool":
+		return "BOOLEAN"
+	case "int", "int32", "int64":
+		return "INTEGER"
+	case "float32", "float64":
+		return "FLOAT"
+	case "time.Time":
+		return "TIMESTAMP"
+	case "mydate.MyDate":
+		return "DATE"
+	}
+	// everything else is stored as json-encoded string
+	return "JSON"
+}
+
+// FieldType returns the bigquery-constant for the type of a column
+func FieldType(c Column) string {
+	switch c.Type {
+	case "BOOLEAN":
+		return "bigquery.BooleanFieldType"
+	case "INTEGER":
+		return "bigquery.IntegerFieldType"
+	case "FLOAT":
+		return "bigquery.FloatFieldType"
+	case "TIMESTAMP":
+		return "bigquery.TimestampFieldType"
+	case "DATE":
+		return "bigquery.DateFieldType"
+	}
+	return "bigquery.StringFieldType"
+}
+
+// Value returns the go-expression that provides the bigquery-value of a column of evt
+func Value(c Column) string {
+	value := fmt.Sprintf("evt.%s", c.Field.Name)
+	switch {
+	case c.Type == "JSON":
+		return fmt.Sprintf("warehouseJSON(%s)", value)
+	case c.Type == "DATE" && c.Repeated:
+		return fmt.Sprintf("warehouseDates(%s)", value)
+	case c.Type == "DATE":
+		if c.Field.IsPointer() {
+			return fmt.Sprintf("warehouseOptional(%s != nil, func() bigquery.Value { return %s.String() })", value, value)
+		}
+		return fmt.Sprintf("%s.String()", value)
+	case c.Field.IsPointer():
+		return fmt.Sprintf("warehouseOptional(%s != nil, func() bigquery.Value { return *%s })", value, value)
+	}
+	return value
+}
+
+func usesDateSlices(data warehouseContext) bool {
+	for _, e := range data.Events {
+		for _, c := range e.Columns {
+			if c.Type == "DATE" && c.Repeated {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// DDL returns the generic create-table statement (bigquery standard-sql) of an event
+func DDL(e warehouseEvent) string {
+	lines := []string{}
+	for _, c := range append(append([]Column{}, metadataColumns...), e.Columns...) {
+		sqlType := c.Type
+		if sqlType == "JSON" {
+			sqlType = "STRING"
+		}
+		if c.Repeated {
+			sqlType = fmt.Sprintf("ARRAY<%s>", sqlType)
+		} else if c.Required {
+			sqlType += " NOT NULL"
+		}
+		lines = append(lines, fmt.Sprintf("  %s %s", c.Name, sqlType))
+	}
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (\n%s\n)\nPARTITION BY DATE(timestamp)", e.Table, strings.Join(lines, ",\n"))
+}
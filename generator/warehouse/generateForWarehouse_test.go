@@ -0,0 +1,75 @@
+package warehouse
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func cleanup() {
+	os.Remove(generationUtil.Prefixed("./testData/warehouse.go"))
+}
+
+func TestGenerateForWarehouse(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @Event( aggregate = "Person" )`, `// @Warehouse()`},
+			Name:        "PersonCreated",
+			Fields: []model.Field{
+				{Name: "PersonUID", TypeName: "string"},
+				{Name: "Age", TypeName: "*int"},
+				{Name: "Tags", TypeName: "[]string"},
+				{Name: "BirthDate", TypeName: "mydate.MyDate"},
+				{Name: "Address", TypeName: "Address"},
+				{Name: "Email", TypeName: "string", Tag: "`sensitive:\"true\"`"},
+				{Name: "Iban", TypeName: "string", DocLines: []string{`// @Encrypted()`}},
+			},
+		},
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @Event( aggregate = "Person" )`},
+			Name:        "PersonDeleted",
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Nil(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/warehouse.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `PersonCreatedWarehouseTable = "person_created"`)
+	assert.Contains(t, string(data), `{Name: "person_uid", Type: bigquery.StringFieldType, Required: true, Repeated: false},`)
+	assert.Contains(t, string(data), `{Name: "tags", Type: bigquery.StringFieldType, Required: false, Repeated: true},`)
+	assert.Contains(t, string(data), `"age":             warehouseOptional(evt.Age != nil, func() bigquery.Value { return *evt.Age }),`)
+	assert.Contains(t, string(data), `"birth_date":      evt.BirthDate.String(),`)
+	assert.Contains(t, string(data), `"address":         warehouseJSON(evt.Address),`)
+	assert.Contains(t, string(data), `  tags ARRAY<STRING>,`)
+	assert.Contains(t, string(data), `bus.Subscribe("Person", "WarehouseSink", sink.handleEvent)`)
+	assert.Contains(t, string(data), `case PersonCreatedEventName:`)
+	assert.NotContains(t, string(data), `email`)
+	assert.NotContains(t, string(data), `iban`)
+	assert.NotContains(t, string(data), `PersonDeleted`)
+	assert.NotContains(t, string(data), `warehouseDates`)
+}
+
+func TestGenerateForWarehouseWithoutEvent(t *testing.T) {
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines:    []string{`// @Warehouse()`},
+			Name:        "Person",
+		},
+	}
+
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,142 @@
+package warehouse
+
+const warehouseTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+
+package {{.PackageName}}
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"cloud.google.com/go/bigquery"
+)
+
+const (
+{{range .Events -}}
+	// {{.Event.Name}}WarehouseTable is the name of the warehouse-table that holds {{.Event.Name}} events
+	{{.Event.Name}}WarehouseTable = "{{.Table}}"
+{{end -}}
+)
+
+{{range .Events -}}
+{{ $event := .Event.Name -}}
+
+// {{$event}}WarehouseDDL creates the warehouse-table of {{$event}} without the bigquery-client
+const {{$event}}WarehouseDDL = ` + "`" + `{{DDL .}}` + "`" + `
+
+// {{$event}}WarehouseSchema is the bigquery-schema of the table that holds {{$event}} events
+var {{$event}}WarehouseSchema = bigquery.Schema{
+{{- range MetadataColumns }}
+	{Name: "{{.Name}}", Type: {{FieldType .}}, Required: {{.Required}}},
+{{- end }}
+{{- range .Columns }}
+	{Name: "{{.Name}}", Type: {{FieldType .}}, Required: {{.Required}}, Repeated: {{.Repeated}}},
+{{- end }}
+}
+
+// {{$event}}WarehouseRow is a single {{$event}} ready to be streamed into the warehouse
+type {{$event}}WarehouseRow struct {
+	Envelope envelope.Envelope
+	Event    {{$event}}
+}
+
+// Save implements bigquery.ValueSaver: the event-uuid is used as insert-id to dedup retries
+func (row {{$event}}WarehouseRow) Save() (map[string]bigquery.Value, string, error) {
+	evt := row.Event
+	return map[string]bigquery.Value{
+		"event_uuid":      row.Envelope.UUID,
+		"event_type_name": row.Envelope.EventTypeName,
+		"aggregate_name":  row.Envelope.AggregateName,
+		"aggregate_uid":   row.Envelope.AggregateUID,
+		"timestamp":       row.Envelope.Timestamp,
+{{- range .Columns }}
+		"{{.Name}}": {{Value .}},
+{{- end }}
+	}, row.Envelope.UUID, nil
+}
+
+{{end -}}
+
+// WarehouseSink streams all warehouse-events of this package into bigquery
+type WarehouseSink struct {
+	dataset *bigquery.Dataset
+}
+
+func NewWarehouseSink(dataset *bigquery.Dataset) *WarehouseSink {
+	return &WarehouseSink{
+		dataset: dataset,
+	}
+}
+
+// CreateTables creates the day-partitioned warehouse-tables
+func (sink *WarehouseSink) CreateTables(c context.Context) error {
+	for table, schema := range map[string]bigquery.Schema{
+{{- range .Events }}
+		{{.Event.Name}}WarehouseTable: {{.Event.Name}}WarehouseSchema,
+{{- end }}
+	} {
+		err := sink.dataset.Table(table).Create(c, &bigquery.TableMetadata{
+			Schema:           schema,
+			TimePartitioning: &bigquery.TimePartitioning{Field: "timestamp"},
+		})
+		if err != nil {
+			return fmt.Errorf("Error creating warehouse-table %s: %s", table, err)
+		}
+	}
+	return nil
+}
+
+func (sink *WarehouseSink) SubscribeToEvents() {
+{{- range .Aggregates }}
+	bus.Subscribe("{{.}}", "WarehouseSink", sink.handleEvent)
+{{- end }}
+}
+
+func (sink *WarehouseSink) handleEvent(c context.Context, rc request.Context, topic string, envlp envelope.Envelope) error {
+	switch envlp.EventTypeName {
+{{- range .Events }}
+	case {{.Event.Name}}EventName:
+		evt, err := UnWrap{{.Event.Name}}(&envlp)
+		if err != nil {
+			return err
+		}
+		return sink.put(c, {{.Event.Name}}WarehouseTable, {{.Event.Name}}WarehouseRow{Envelope: envlp, Event: *evt})
+{{- end }}
+	}
+	return nil
+}
+
+func (sink *WarehouseSink) put(c context.Context, table string, row bigquery.ValueSaver) error {
+	err := sink.dataset.Table(table).Inserter().Put(c, row)
+	if err != nil {
+		return fmt.Errorf("Error streaming into warehouse-table %s: %s", table, err)
+	}
+	return nil
+}
+
+func warehouseOptional(present bool, value func() bigquery.Value) bigquery.Value {
+	if !present {
+		return nil
+	}
+	return value()
+}
+
+{{if UsesDateSlices . -}}
+func warehouseDates(dates []mydate.MyDate) []string {
+	values := make([]string, 0, len(dates))
+	for _, d := range dates {
+		values = append(values, d.String())
+	}
+	return values
+}
+
+{{end -}}
+func warehouseJSON(value interface{}) bigquery.Value {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	return string(data)
+}
+`
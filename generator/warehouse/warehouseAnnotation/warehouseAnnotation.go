@@ -0,0 +1,24 @@
+package warehouseAnnotation
+
+import "github.com/MarcGrol/golangAnnotations/generator/annotation"
+
+const (
+	TypeWarehouse = "Warehouse"
+	ParamTable    = "table"
+)
+
+func Get() []annotation.AnnotationDescriptor {
+	return []annotation.AnnotationDescriptor{
+		{
+			Name:       TypeWarehouse,
+			ParamNames: []string{ParamTable},
+			Validator:  validateWarehouseAnnotation,
+		}}
+}
+
+func validateWarehouseAnnotation(annot annotation.Annotation) bool {
+	if annot.Name == TypeWarehouse {
+		return true
+	}
+	return false
+}
@@ -0,0 +1,24 @@
+package warehouseAnnotation
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrectWarehouseAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	annotation, ok := registry.ResolveAnnotation(`// @Warehouse( table = "person_created" )`)
+	assert.True(t, ok)
+	assert.Equal(t, TypeWarehouse, annotation.Name)
+	assert.Equal(t, "person_created", annotation.Attributes[ParamTable])
+}
+
+func TestOtherAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	_, ok := registry.ResolveAnnotation(`// @Event( aggregate = "Person" )`)
+	assert.False(t, ok)
+}
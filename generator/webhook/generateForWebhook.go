@@ -0,0 +1,137 @@
+package webhook
+
+import (
+	"fmt"
+	"strconv"
+	"text/template"
+	"time"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/MarcGrol/golangAnnotations/generator/event"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/generator/webhook/webhookAnnotation"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+const (
+	defaultAttempts = 3
+	defaultBackoff  = time.Second
+)
+
+type webhooksContext struct {
+	PackageName string
+	Events      []publishableEvent
+}
+
+// publishableEvent is an event with @WebhookPublishable, that subscribers subscribe to by its event-type
+type publishableEvent struct {
+	Name      string
+	EventType string
+	Attempts  int
+	Backoff   string
+}
+
+type Generator struct {
+}
+
+func NewGenerator() generator.Generator {
+	return &Generator{}
+}
+
+func (eg *Generator) Name() string {
+	return "webhook"
+}
+
+func (eg *Generator) GetTemplates() []generator.Template {
+	return []generator.Template{
+		{Name: "webhooks", APIVersion: 1, Text: webhooksTemplate},
+	}
+}
+
+func (eg *Generator) GetAnnotations() []annotation.AnnotationDescriptor {
+	return webhookAnnotation.Get()
+}
+
+func (eg *Generator) Generate(parsedSource model.ParsedSources, config generator.Config) ([]generator.OutputFile, error) {
+	return generate(config, parsedSource.Structs)
+}
+
+func generate(config generator.Config, structs []model.Struct) ([]generator.OutputFile, error) {
+	packageName, err := generationUtil.GetPackageNameForStructs(structs)
+	if packageName == "" || err != nil {
+		return nil, err
+	}
+	targetDir, err := generationUtil.DetermineTargetPath(config.InputDir, packageName)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := webhooksContext{PackageName: packageName}
+	for _, s := range structs {
+		e, ok, err := newPublishableEvent(s, ctx.Events)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			ctx.Events = append(ctx.Events, e)
+		}
+	}
+	if len(ctx.Events) == 0 {
+		return nil, nil
+	}
+
+	file, err := generationUtil.Generate(generationUtil.Info{
+		Src:            packageName,
+		TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/webhooks.go", targetDir)),
+		TemplateName:   "webhooks",
+		TemplateString: webhooksTemplate,
+		Config:         config,
+		FuncMap:        customTemplateFuncs,
+		Data:           ctx,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Error generating webhooks for package %s:%w", packageName, err)
+	}
+	return []generator.OutputFile{file}, nil
+}
+
+var customTemplateFuncs = template.FuncMap{
+	"Quote": strconv.Quote,
+}
+
+// newPublishableEvent returns the publishable event of a struct with @WebhookPublishable, which must be an @Event.
+// Its event-type is the name of the event, unless name gives another one.
+func newPublishableEvent(s model.Struct, others []publishableEvent) (publishableEvent, bool, error) {
+	ann, ok := annotation.NewRegistry(webhookAnnotation.Get()).ResolveAnnotationByName(s.DocLines, webhookAnnotation.TypeWebhookPublishable)
+	if !ok {
+		return publishableEvent{}, false, nil
+	}
+	if !event.IsEvent(s) {
+		return publishableEvent{}, false, generator.StructError(s, webhookAnnotation.TypeWebhookPublishable, "Struct %s with @WebhookPublishable must be an @Event", s.Name)
+	}
+	e := publishableEvent{
+		Name:      s.Name,
+		EventType: ann.Attributes[webhookAnnotation.ParamName],
+		Attempts:  defaultAttempts,
+		Backoff:   generationUtil.DurationLiteral(defaultBackoff),
+	}
+	if e.EventType == "" {
+		e.EventType = s.Name
+	}
+	for _, other := range others {
+		if other.EventType == e.EventType {
+			return publishableEvent{}, false, generator.StructError(s, webhookAnnotation.TypeWebhookPublishable, "Event %s has the same event-type %s as event %s: give one of them another name", s.Name, e.EventType, other.Name)
+		}
+	}
+
+	// the annotation is only valid with a positive number of attempts and a positive backoff
+	if attempts, found := ann.Attributes[webhookAnnotation.ParamAttempts]; found {
+		e.Attempts, _ = strconv.Atoi(attempts)
+	}
+	if value, found := ann.Attributes[webhookAnnotation.ParamBackoff]; found {
+		backoff, _ := time.ParseDuration(value)
+		e.Backoff = generationUtil.DurationLiteral(backoff)
+	}
+	return e, true, nil
+}
@@ -0,0 +1,126 @@
+package webhook
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func cleanup() {
+	os.Remove(generationUtil.Prefixed("./testData/webhooks.go"))
+	os.Remove("./testData")
+}
+
+func TestGenerateForWebhook(t *testing.T) {
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines: []string{
+				`// @Event( aggregate = "Order" )`,
+				`// @WebhookPublishable( name = "order.shipped", attempts = "5", backoff = "2s" )`,
+			},
+			Name: "OrderShipped",
+		},
+		{
+			PackageName: "testData",
+			DocLines: []string{
+				`// @Event( aggregate = "Order" )`,
+				`// @WebhookPublishable()`,
+			},
+			Name: "OrderCancelled",
+		},
+		{
+			PackageName: "testData",
+			DocLines: []string{
+				`// @Event( aggregate = "Order" )`,
+			},
+			Name: "OrderCreated",
+		},
+	}
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Nil(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/webhooks.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `OrderShippedWebhookEventType   = "order.shipped"`)
+	assert.Contains(t, string(data), `OrderCancelledWebhookEventType = "OrderCancelled"`)
+	assert.Contains(t, string(data), "func PublishWebhookOrderShipped(c context.Context, evt OrderShipped) error {")
+	assert.Contains(t, string(data), "webhookOptions{attempts: 5, backoff: 2 * time.Second}")
+	assert.Contains(t, string(data), "webhookOptions{attempts: 3, backoff: 1 * time.Second}")
+	assert.Contains(t, string(data), "case *OrderCancelled:")
+	assert.Contains(t, string(data), "func VerifyWebhookSignature(secret string, header string, body []byte, tolerance time.Duration) error {")
+	assert.Contains(t, string(data), `router.HandleFunc("/webhooks", createWebhookSubscription).Methods("POST")`)
+	assert.Contains(t, string(data), "type WebhookDeliveryLog interface {")
+	assert.NotContains(t, string(data), "OrderCreated")
+}
+
+func TestGenerateForNoWebhook(t *testing.T) {
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines: []string{
+				`// @Event( aggregate = "Order" )`,
+			},
+			Name: "OrderShipped",
+		},
+	}
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	assert.Nil(t, err)
+
+	_, err = os.Stat(generationUtil.Prefixed("./testData/webhooks.go"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestGenerateForWebhookWithoutEvent(t *testing.T) {
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines: []string{
+				`// @WebhookPublishable( name = "order.shipped" )`,
+			},
+			Name: "OrderShipped",
+		},
+	}
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "Struct OrderShipped with @WebhookPublishable must be an @Event")
+	}
+}
+
+func TestGenerateForWebhooksWithSameEventType(t *testing.T) {
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			DocLines: []string{
+				`// @Event( aggregate = "Order" )`,
+				`// @WebhookPublishable( name = "order.shipped" )`,
+			},
+			Name: "OrderShipped",
+		},
+		{
+			PackageName: "testData",
+			DocLines: []string{
+				`// @Event( aggregate = "Order" )`,
+				`// @WebhookPublishable( name = "order.shipped" )`,
+			},
+			Name: "OrderCancelled",
+		},
+	}
+	err := generator.Generate(NewGenerator(), model.ParsedSources{Structs: s}, generator.Config{InputDir: "testData"})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "Event OrderCancelled has the same event-type order.shipped as event OrderShipped")
+	}
+}
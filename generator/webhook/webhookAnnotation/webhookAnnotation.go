@@ -0,0 +1,48 @@
+package webhookAnnotation
+
+import (
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+)
+
+const (
+	TypeWebhookPublishable = "WebhookPublishable"
+	ParamName              = "name"
+	ParamAttempts          = "attempts"
+	ParamBackoff           = "backoff"
+)
+
+// namePattern allows event-types like order.shipped, that subscribers subscribe to
+var namePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9._-]*$`)
+
+func Get() []annotation.AnnotationDescriptor {
+	return []annotation.AnnotationDescriptor{
+		{
+			Name:       TypeWebhookPublishable,
+			ParamNames: []string{ParamName, ParamAttempts, ParamBackoff},
+			Validator:  validateWebhookPublishableAnnotation,
+		}}
+}
+
+func validateWebhookPublishableAnnotation(annot annotation.Annotation) bool {
+	if annot.Name == TypeWebhookPublishable {
+		if name, found := annot.Attributes[ParamName]; found && !namePattern.MatchString(name) {
+			return false
+		}
+		if attempts, found := annot.Attributes[ParamAttempts]; found {
+			if number, err := strconv.Atoi(attempts); err != nil || number < 1 {
+				return false
+			}
+		}
+		if backoff, found := annot.Attributes[ParamBackoff]; found {
+			if d, err := time.ParseDuration(backoff); err != nil || d <= 0 {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
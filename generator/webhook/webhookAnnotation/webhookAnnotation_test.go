@@ -0,0 +1,30 @@
+package webhookAnnotation
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrectWebhookPublishableAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	annotation, ok := registry.ResolveAnnotation(`// @WebhookPublishable( name = "order.shipped", attempts = "5", backoff = "2s" )`)
+	assert.True(t, ok)
+	assert.Equal(t, TypeWebhookPublishable, annotation.Name)
+	assert.Equal(t, "order.shipped", annotation.Attributes[ParamName])
+	assert.Equal(t, "5", annotation.Attributes[ParamAttempts])
+	assert.Equal(t, "2s", annotation.Attributes[ParamBackoff])
+
+	_, ok = registry.ResolveAnnotation(`// @WebhookPublishable()`)
+	assert.True(t, ok)
+}
+
+func TestInvalidWebhookPublishableAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @WebhookPublishable( name = "order shipped" )`}))
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @WebhookPublishable( attempts = "0" )`}))
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @WebhookPublishable( backoff = "-1s" )`}))
+}
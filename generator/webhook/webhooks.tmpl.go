@@ -0,0 +1,513 @@
+package webhook
+
+const webhooksTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+
+package {{.PackageName}}
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// Event-types of the events of this package that can be subscribed to
+const (
+{{- range .Events}}
+	{{.Name}}WebhookEventType = {{Quote .EventType}}
+{{- end}}
+)
+
+// WebhookEventTypes are all event-types that can be subscribed to
+var WebhookEventTypes = []string{
+{{- range .Events}}
+	{{.Name}}WebhookEventType,
+{{- end}}
+}
+
+const (
+	// WebhookSignatureHeader signs a delivery as t=<unix-time>,v1=<hex hmac-sha256 of "<unix-time>.<body>">
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	// WebhookEventTypeHeader is the event-type of a delivery
+	WebhookEventTypeHeader = "X-Webhook-Event"
+	// WebhookDeliveryHeader is the id of a delivery, which is the same for all its attempts
+	WebhookDeliveryHeader = "X-Webhook-Delivery"
+)
+
+// WebhookSubscription subscribes a url of a third party to events
+type WebhookSubscription struct {
+	ID         string    ` + "`" + `json:"id"` + "`" + `
+	URL        string    ` + "`" + `json:"url"` + "`" + `
+	EventTypes []string  ` + "`" + `json:"eventTypes"` + "`" + `
+	Secret     string    ` + "`" + `json:"secret,omitempty"` + "`" + `
+	CreatedAt  time.Time ` + "`" + `json:"createdAt"` + "`" + `
+}
+
+func (s WebhookSubscription) subscribesTo(eventType string) bool {
+	for _, t := range s.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookPayload is the json-body of a delivery
+type WebhookPayload struct {
+	ID        string          ` + "`" + `json:"id"` + "`" + `
+	EventType string          ` + "`" + `json:"eventType"` + "`" + `
+	Timestamp time.Time       ` + "`" + `json:"timestamp"` + "`" + `
+	Data      json.RawMessage ` + "`" + `json:"data"` + "`" + `
+}
+
+// WebhookDelivery is an attempt to deliver an event to a subscription
+type WebhookDelivery struct {
+	ID             string        ` + "`" + `json:"id"` + "`" + `
+	SubscriptionID string        ` + "`" + `json:"subscriptionId"` + "`" + `
+	EventType      string        ` + "`" + `json:"eventType"` + "`" + `
+	URL            string        ` + "`" + `json:"url"` + "`" + `
+	Attempt        int           ` + "`" + `json:"attempt"` + "`" + `
+	Status         int           ` + "`" + `json:"status,omitempty"` + "`" + `
+	Error          string        ` + "`" + `json:"error,omitempty"` + "`" + `
+	Started        time.Time     ` + "`" + `json:"started"` + "`" + `
+	Duration       time.Duration ` + "`" + `json:"duration"` + "`" + `
+}
+
+// WebhookSubscriptionStore keeps the subscriptions
+type WebhookSubscriptionStore interface {
+	Put(c context.Context, subscription WebhookSubscription) error
+	Get(c context.Context, id string) (WebhookSubscription, bool, error)
+	Delete(c context.Context, id string) error
+	List(c context.Context) ([]WebhookSubscription, error)
+}
+
+// WebhookDeliveryLog keeps the attempts of the deliveries, so that subscribers can see why they missed events
+type WebhookDeliveryLog interface {
+	Record(c context.Context, delivery WebhookDelivery) error
+	List(c context.Context, subscriptionID string) ([]WebhookDelivery, error)
+}
+
+var (
+	webhookSubscriptionStore WebhookSubscriptionStore = NewMemoryWebhookSubscriptionStore()
+	webhookDeliveryLog       WebhookDeliveryLog       = NewMemoryWebhookDeliveryLog(100)
+	webhookClient                                     = &http.Client{Timeout: 10 * time.Second}
+)
+
+// SetWebhookSubscriptionStore replaces the in-memory store of the subscriptions, like with one in a database
+func SetWebhookSubscriptionStore(store WebhookSubscriptionStore) {
+	webhookSubscriptionStore = store
+}
+
+// SetWebhookDeliveryLog replaces the in-memory log of the deliveries
+func SetWebhookDeliveryLog(log WebhookDeliveryLog) {
+	webhookDeliveryLog = log
+}
+
+// SetWebhookHTTPClient replaces the client that delivers the events, which times out after 10 seconds
+func SetWebhookHTTPClient(client *http.Client) {
+	webhookClient = client
+}
+
+// SignWebhookPayload returns the value of the signature-header of a body that is sent at timestamp
+func SignWebhookPayload(secret string, timestamp time.Time, body []byte) string {
+	unix := strconv.FormatInt(timestamp.Unix(), 10)
+	return fmt.Sprintf("t=%s,v1=%s", unix, webhookSignature(secret, unix, body))
+}
+
+func webhookSignature(secret string, unix string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unix + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookSignature checks the signature-header of a delivery that a subscriber received: it fails when the
+// body was not signed with the secret of the subscription, or was signed longer than tolerance ago
+func VerifyWebhookSignature(secret string, header string, body []byte, tolerance time.Duration) error {
+	var unix, signature string
+	for _, part := range strings.Split(header, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "t":
+			unix = kv[1]
+		case "v1":
+			signature = kv[1]
+		}
+	}
+	seconds, err := strconv.ParseInt(unix, 10, 64)
+	if err != nil || signature == "" {
+		return fmt.Errorf("Invalid webhook-signature '%s'", header)
+	}
+	if !hmac.Equal([]byte(signature), []byte(webhookSignature(secret, unix, body))) {
+		return fmt.Errorf("Webhook-signature does not match the body")
+	}
+	age := time.Since(time.Unix(seconds, 0))
+	if tolerance > 0 && (age > tolerance || age < -tolerance) {
+		return fmt.Errorf("Webhook-signature was made %s ago, which is not within %s", age, tolerance)
+	}
+	return nil
+}
+
+// webhookOptions tell how often and how long a delivery is tried
+type webhookOptions struct {
+	attempts int
+	backoff  time.Duration
+}
+{{range .Events}}
+// PublishWebhook{{.Name}} delivers event {{.Name}} to the subscriptions of event-type {{Quote .EventType}}
+func PublishWebhook{{.Name}}(c context.Context, evt {{.Name}}) error {
+	return publishWebhook(c, {{.Name}}WebhookEventType, webhookOptions{attempts: {{.Attempts}}, backoff: {{.Backoff}}}, evt)
+}
+{{end}}
+// PublishWebhookEvent delivers an event of package {{.PackageName}}, given as value or pointer, to its subscriptions:
+// events that are not publishable are ignored
+func PublishWebhookEvent(c context.Context, evt interface{}) error {
+	switch e := evt.(type) {
+{{- range .Events}}
+	case {{.Name}}:
+		return PublishWebhook{{.Name}}(c, e)
+	case *{{.Name}}:
+		return PublishWebhook{{.Name}}(c, *e)
+{{- end}}
+	}
+	return nil
+}
+
+// WebhookPublishError tells which subscriptions an event could not be delivered to: the other ones received it
+type WebhookPublishError struct {
+	EventType string
+	Errors    []error
+}
+
+func (err *WebhookPublishError) Error() string {
+	messages := []string{}
+	for _, e := range err.Errors {
+		messages = append(messages, e.Error())
+	}
+	return fmt.Sprintf("Error delivering webhook-event %s: %s", err.EventType, strings.Join(messages, "; "))
+}
+
+// publishWebhook delivers an event to all its subscriptions, one after the other. A delivery is tried again after
+// its backoff, which doubles after every attempt, so run it in the background when the caller should not wait.
+func publishWebhook(c context.Context, eventType string, options webhookOptions, evt interface{}) error {
+	subscriptions, err := webhookSubscriptionStore.List(c)
+	if err != nil {
+		return fmt.Errorf("Error listing webhook-subscriptions: %w", err)
+	}
+	data, err := json.Marshal(evt)
+	if err != nil {
+		return fmt.Errorf("Error marshalling webhook-event %s: %w", eventType, err)
+	}
+	errs := []error{}
+	for _, subscription := range subscriptions {
+		if !subscription.subscribesTo(eventType) {
+			continue
+		}
+		id, err := newWebhookID()
+		if err != nil {
+			return err
+		}
+		body, err := json.Marshal(WebhookPayload{ID: id, EventType: eventType, Timestamp: time.Now(), Data: data})
+		if err != nil {
+			return fmt.Errorf("Error marshalling webhook-payload %s: %w", eventType, err)
+		}
+		err = deliverWebhook(c, subscription, eventType, id, body, options)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("subscription %s: %w", subscription.ID, err))
+		}
+	}
+	if len(errs) > 0 {
+		return &WebhookPublishError{EventType: eventType, Errors: errs}
+	}
+	return nil
+}
+
+// deliverWebhook posts a payload to a subscription until it is accepted or its attempts are used
+func deliverWebhook(c context.Context, subscription WebhookSubscription, eventType string, id string, body []byte, options webhookOptions) error {
+	backoff := options.backoff
+	var err error
+	for attempt := 1; attempt <= options.attempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-c.Done():
+				return c.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+		delivery := WebhookDelivery{ID: id, SubscriptionID: subscription.ID, EventType: eventType, URL: subscription.URL, Attempt: attempt, Started: time.Now()}
+		delivery.Status, err = postWebhook(c, subscription, eventType, id, body)
+		delivery.Duration = time.Since(delivery.Started)
+		if err != nil {
+			delivery.Error = err.Error()
+		}
+		if logErr := webhookDeliveryLog.Record(c, delivery); logErr != nil {
+			return fmt.Errorf("Error logging webhook-delivery %s: %w", id, logErr)
+		}
+		if err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("Delivery %s failed %d attempts: %w", id, options.attempts, err)
+}
+
+func postWebhook(c context.Context, subscription WebhookSubscription, eventType string, id string, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(c, http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventTypeHeader, eventType)
+	req.Header.Set(WebhookDeliveryHeader, id)
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(subscription.Secret, time.Now(), body))
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return resp.StatusCode, fmt.Errorf("%s answered with status %d", subscription.URL, resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+func newWebhookID() (string, error) {
+	id := make([]byte, 16)
+	_, err := rand.Read(id)
+	if err != nil {
+		return "", fmt.Errorf("Error creating webhook-id: %w", err)
+	}
+	return hex.EncodeToString(id), nil
+}
+
+// WebhookSubscriptionHandlers registers the endpoints that third parties subscribe with in existing router: protect
+// them like the other endpoints of the service, and use a sub-router for another path than /webhooks
+//
+//	GET    /webhooks/event-types          lists the event-types
+//	POST   /webhooks                      subscribes {"url": ..., "eventTypes": [...]} and returns its secret once
+//	GET    /webhooks                      lists the subscriptions
+//	GET    /webhooks/{id}                 returns a subscription
+//	DELETE /webhooks/{id}                 unsubscribes
+//	GET    /webhooks/{id}/deliveries      lists the last deliveries of a subscription
+func WebhookSubscriptionHandlers(router *mux.Router) *mux.Router {
+	router.HandleFunc("/webhooks/event-types", listWebhookEventTypes).Methods("GET")
+	router.HandleFunc("/webhooks", createWebhookSubscription).Methods("POST")
+	router.HandleFunc("/webhooks", listWebhookSubscriptions).Methods("GET")
+	router.HandleFunc("/webhooks/{id}", getWebhookSubscription).Methods("GET")
+	router.HandleFunc("/webhooks/{id}", deleteWebhookSubscription).Methods("DELETE")
+	router.HandleFunc("/webhooks/{id}/deliveries", listWebhookDeliveries).Methods("GET")
+	return router
+}
+
+func listWebhookEventTypes(w http.ResponseWriter, r *http.Request) {
+	writeWebhookJSON(w, http.StatusOK, WebhookEventTypes)
+}
+
+func createWebhookSubscription(w http.ResponseWriter, r *http.Request) {
+	var subscription WebhookSubscription
+	err := json.NewDecoder(r.Body).Decode(&subscription)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid subscription: %s", err), http.StatusBadRequest)
+		return
+	}
+	err = validateWebhookSubscription(subscription)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	subscription.ID, err = newWebhookID()
+	if err == nil {
+		subscription.Secret, err = newWebhookID()
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	subscription.CreatedAt = time.Now()
+	err = webhookSubscriptionStore.Put(r.Context(), subscription)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error storing subscription: %s", err), http.StatusInternalServerError)
+		return
+	}
+	writeWebhookJSON(w, http.StatusCreated, subscription)
+}
+
+func validateWebhookSubscription(subscription WebhookSubscription) error {
+	u, err := url.Parse(subscription.URL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("Invalid url '%s': it must be an absolute http(s)-url", subscription.URL)
+	}
+	if len(subscription.EventTypes) == 0 {
+		return fmt.Errorf("A subscription needs at least one event-type")
+	}
+	for _, eventType := range subscription.EventTypes {
+		known := false
+		for _, t := range WebhookEventTypes {
+			known = known || t == eventType
+		}
+		if !known {
+			return fmt.Errorf("Unknown event-type '%s'", eventType)
+		}
+	}
+	return nil
+}
+
+func listWebhookSubscriptions(w http.ResponseWriter, r *http.Request) {
+	subscriptions, err := webhookSubscriptionStore.List(r.Context())
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error listing subscriptions: %s", err), http.StatusInternalServerError)
+		return
+	}
+	for idx := range subscriptions {
+		subscriptions[idx].Secret = ""
+	}
+	writeWebhookJSON(w, http.StatusOK, subscriptions)
+}
+
+func getWebhookSubscription(w http.ResponseWriter, r *http.Request) {
+	subscription, found := findWebhookSubscription(w, r)
+	if found {
+		subscription.Secret = ""
+		writeWebhookJSON(w, http.StatusOK, subscription)
+	}
+}
+
+func deleteWebhookSubscription(w http.ResponseWriter, r *http.Request) {
+	subscription, found := findWebhookSubscription(w, r)
+	if !found {
+		return
+	}
+	err := webhookSubscriptionStore.Delete(r.Context(), subscription.ID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error deleting subscription: %s", err), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func listWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	subscription, found := findWebhookSubscription(w, r)
+	if !found {
+		return
+	}
+	deliveries, err := webhookDeliveryLog.List(r.Context(), subscription.ID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error listing deliveries: %s", err), http.StatusInternalServerError)
+		return
+	}
+	writeWebhookJSON(w, http.StatusOK, deliveries)
+}
+
+// findWebhookSubscription returns the subscription of the id in the path, or answers with an error-status
+func findWebhookSubscription(w http.ResponseWriter, r *http.Request) (WebhookSubscription, bool) {
+	id := mux.Vars(r)["id"]
+	subscription, found, err := webhookSubscriptionStore.Get(r.Context(), id)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error getting subscription: %s", err), http.StatusInternalServerError)
+		return WebhookSubscription{}, false
+	}
+	if !found {
+		http.Error(w, fmt.Sprintf("Subscription %s not found", id), http.StatusNotFound)
+		return WebhookSubscription{}, false
+	}
+	return subscription, true
+}
+
+func writeWebhookJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+type memoryWebhookSubscriptionStore struct {
+	sync.Mutex
+	subscriptions map[string]WebhookSubscription
+}
+
+// NewMemoryWebhookSubscriptionStore returns a store that keeps the subscriptions in memory, so that they are lost
+// at a restart and not shared between instances
+func NewMemoryWebhookSubscriptionStore() WebhookSubscriptionStore {
+	return &memoryWebhookSubscriptionStore{subscriptions: map[string]WebhookSubscription{}}
+}
+
+func (s *memoryWebhookSubscriptionStore) Put(c context.Context, subscription WebhookSubscription) error {
+	s.Lock()
+	defer s.Unlock()
+	s.subscriptions[subscription.ID] = subscription
+	return nil
+}
+
+func (s *memoryWebhookSubscriptionStore) Get(c context.Context, id string) (WebhookSubscription, bool, error) {
+	s.Lock()
+	defer s.Unlock()
+	subscription, found := s.subscriptions[id]
+	return subscription, found, nil
+}
+
+func (s *memoryWebhookSubscriptionStore) Delete(c context.Context, id string) error {
+	s.Lock()
+	defer s.Unlock()
+	delete(s.subscriptions, id)
+	return nil
+}
+
+func (s *memoryWebhookSubscriptionStore) List(c context.Context) ([]WebhookSubscription, error) {
+	s.Lock()
+	defer s.Unlock()
+	subscriptions := []WebhookSubscription{}
+	for _, subscription := range s.subscriptions {
+		subscriptions = append(subscriptions, subscription)
+	}
+	sort.Slice(subscriptions, func(i, j int) bool {
+		return subscriptions[i].CreatedAt.Before(subscriptions[j].CreatedAt)
+	})
+	return subscriptions, nil
+}
+
+type memoryWebhookDeliveryLog struct {
+	sync.Mutex
+	max        int
+	deliveries map[string][]WebhookDelivery
+}
+
+// NewMemoryWebhookDeliveryLog returns a log that keeps the last max deliveries of every subscription in memory
+func NewMemoryWebhookDeliveryLog(max int) WebhookDeliveryLog {
+	return &memoryWebhookDeliveryLog{max: max, deliveries: map[string][]WebhookDelivery{}}
+}
+
+func (l *memoryWebhookDeliveryLog) Record(c context.Context, delivery WebhookDelivery) error {
+	l.Lock()
+	defer l.Unlock()
+	deliveries := append(l.deliveries[delivery.SubscriptionID], delivery)
+	if len(deliveries) > l.max {
+		deliveries = deliveries[len(deliveries)-l.max:]
+	}
+	l.deliveries[delivery.SubscriptionID] = deliveries
+	return nil
+}
+
+func (l *memoryWebhookDeliveryLog) List(c context.Context, subscriptionID string) ([]WebhookDelivery, error) {
+	l.Lock()
+	defer l.Unlock()
+	return append([]WebhookDelivery{}, l.deliveries[subscriptionID]...), nil
+}
+`
@@ -0,0 +1,94 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+// Generate runs a single generator and writes the files it rendered to the configured location
+func Generate(g Generator, parsedSources model.ParsedSources, config Config) error {
+	return GenerateContext(context.Background(), g, parsedSources, config)
+}
+
+// GenerateContext is Generate that stops when c is cancelled
+func GenerateContext(c context.Context, g Generator, parsedSources model.ParsedSources, config Config) error {
+	files, err := RenderContext(c, g, parsedSources, config)
+	if err != nil {
+		return err
+	}
+	changed, err := WriteChanged(files)
+	for _, filename := range changed {
+		config.Logger.Verbosef("golangAnnotations: Generated file '%s'", filename)
+	}
+	return err
+}
+
+// Render runs a single generator and returns the files it rendered at their configured location, without writing them
+func Render(g Generator, parsedSources model.ParsedSources, config Config) ([]OutputFile, error) {
+	return RenderContext(context.Background(), g, parsedSources, config)
+}
+
+// RenderContext is Render that stops when c is cancelled
+func RenderContext(c context.Context, g Generator, parsedSources model.ParsedSources, config Config) ([]OutputFile, error) {
+	err := c.Err()
+	if err != nil {
+		return nil, err
+	}
+	config.Logger.Debugf("Running generator %s for %s", g.Name(), config.InputDir)
+	var files []OutputFile
+	if cg, ok := g.(ContextGenerator); ok {
+		files, err = cg.GenerateContext(c, parsedSources, config)
+	} else {
+		files, err = g.Generate(parsedSources, config)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for idx, f := range files {
+		files[idx], err = Relocate(f, g.Name(), config)
+		if err != nil {
+			return nil, err
+		}
+	}
+	files, err = ApplyBuildTags(files, g, parsedSources, config)
+	if err != nil {
+		return nil, err
+	}
+	return FormatGoFiles(ApplyHeader(files, g.Name(), config))
+}
+
+// Write stores the rendered files on disk, creating directories when needed
+func Write(files []OutputFile) error {
+	_, err := WriteChanged(files)
+	return err
+}
+
+// WriteChanged stores the rendered files that differ from the files on disk and returns their names. Unchanged
+// files are not touched, so that their modification time does not trigger rebuilds.
+func WriteChanged(files []OutputFile) ([]string, error) {
+	changed := []string{}
+	for _, f := range files {
+		upToDate, err := IsUpToDate(f)
+		if err != nil {
+			return changed, err
+		}
+		if upToDate {
+			continue
+		}
+		err = os.MkdirAll(filepath.Dir(f.Filename), 0777)
+		if err != nil {
+			return changed, err
+		}
+		err = ioutil.WriteFile(f.Filename, f.Content, 0644)
+		if err != nil {
+			return changed, fmt.Errorf("Error writing file %s:%w", f.Filename, err)
+		}
+		changed = append(changed, f.Filename)
+	}
+	return changed, nil
+}
@@ -0,0 +1,48 @@
+package generator
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteChangedSkipsUnchangedFiles(t *testing.T) {
+	dir := t.TempDir()
+	unchanged := filepath.Join(dir, "gen_unchanged.go")
+	changed := filepath.Join(dir, "gen_changed.go")
+	created := filepath.Join(dir, "sub", "gen_new.go")
+	assert.NoError(t, ioutil.WriteFile(unchanged, []byte("package x\n"), 0644))
+	assert.NoError(t, ioutil.WriteFile(changed, []byte("package x\n"), 0644))
+	past := time.Now().Add(-time.Hour).Truncate(time.Second)
+	assert.NoError(t, os.Chtimes(unchanged, past, past))
+
+	written, err := WriteChanged([]OutputFile{
+		{Filename: unchanged, Content: []byte("package x\n")},
+		{Filename: changed, Content: []byte("package y\n")},
+		{Filename: created, Content: []byte("package z\n")},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{changed, created}, written)
+
+	info, err := os.Stat(unchanged)
+	assert.NoError(t, err)
+	assert.True(t, info.ModTime().Equal(past))
+
+	data, err := ioutil.ReadFile(created)
+	assert.NoError(t, err)
+	assert.Equal(t, "package z\n", string(data))
+}
+
+func TestRenderContextStopsWhenCancelled(t *testing.T) {
+	c, cancel := context.WithCancel(context.Background())
+	cancel()
+	files, err := RenderContext(c, fileGenerator{namedGenerator: namedGenerator{name: "a"}}, model.ParsedSources{}, Config{})
+	assert.Equal(t, context.Canceled, err)
+	assert.Empty(t, files)
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path"
 
+	"github.com/MarcGrol/golangAnnotations/generator"
 	"github.com/MarcGrol/golangAnnotations/generator/rest"
 	"github.com/MarcGrol/golangAnnotations/model"
 )
@@ -21,8 +22,7 @@ func main() {
 	}
 
 	if triggerRestGenerator {
-		generator := rest.NewGenerator()
-		err = generator.Generate(outputDir, parsedSources)
+		err = generator.Generate(rest.NewGenerator(), parsedSources, generator.Config{InputDir: outputDir})
 		if err != nil {
 			log.Printf("Error triggering rest-generator: %s", err)
 			os.Exit(-2)
@@ -2,4 +2,8 @@ module github.com/MarcGrol/golangAnnotations
 
 go 1.16
 
-require github.com/stretchr/testify v1.7.0
+require (
+	github.com/Masterminds/sprig/v3 v3.2.3
+	github.com/stretchr/testify v1.7.0
+	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c
+)
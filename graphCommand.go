@@ -0,0 +1,198 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/MarcGrol/golangAnnotations/generator/event/eventAnnotation"
+	"github.com/MarcGrol/golangAnnotations/generator/eventService"
+	"github.com/MarcGrol/golangAnnotations/generator/eventService/eventServiceAnnotation"
+	"github.com/MarcGrol/golangAnnotations/generator/rest/restAnnotation"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+// Kinds of nodes in the event-graph
+const (
+	serviceNode   = "service"
+	eventNode     = "event"
+	aggregateNode = "aggregate"
+)
+
+type graphNode struct {
+	id          string
+	kind        string
+	packageName string
+	name        string
+}
+
+type graphEdge struct {
+	from  string
+	to    string
+	label string
+}
+
+// eventGraph tells which services publish and handle which events, and which aggregates the events apply to
+type eventGraph struct {
+	nodes []graphNode
+	edges []graphEdge
+	index map[string]bool
+}
+
+func (g *eventGraph) addNode(kind string, packageName string, name string) string {
+	id := kind + ":" + packageName + "." + name
+	if g.index == nil {
+		g.index = map[string]bool{}
+	}
+	if !g.index[id] {
+		g.index[id] = true
+		g.nodes = append(g.nodes, graphNode{id: id, kind: kind, packageName: packageName, name: name})
+	}
+	return id
+}
+
+func (g *eventGraph) addEdge(from string, to string, label string) {
+	edge := graphEdge{from: from, to: to, label: label}
+	for _, e := range g.edges {
+		if e == edge {
+			return
+		}
+	}
+	g.edges = append(g.edges, edge)
+}
+
+// runGraph prints the event-graph of all input-dirs
+func runGraph() {
+	inputDirs, err := applyProjectConfig()
+	if err != nil {
+		fail(exitUsage, "Error in config: %s", err)
+	}
+
+	c, stop := interruptibleContext()
+	defer stop()
+
+	graph := buildEventGraph(parseInputDirs(c, inputDirs))
+	err = graph.write(os.Stdout, *graphFormat)
+	if err != nil {
+		fail(exitUsage, "Error writing graph: %s", err)
+	}
+}
+
+// buildEventGraph derives the graph from the @Event-, @EventService-, @EventOperation- and @RestOperation-annotations:
+// operations publish the events of their producesevents-attribute and event-operations handle the event they receive
+func buildEventGraph(parsedSources []model.ParsedSources) *eventGraph {
+	eventAnnotations := annotation.NewRegistry(eventAnnotation.Get())
+	eventServiceAnnotations := annotation.NewRegistry(eventServiceAnnotation.Get())
+	restAnnotations := annotation.NewRegistry(restAnnotation.Get())
+
+	graph := &eventGraph{}
+	for _, parsed := range parsedSources {
+		for _, s := range parsed.Structs {
+			if ann, ok := eventAnnotations.ResolveAnnotationByName(s.DocLines, eventAnnotation.TypeEvent); ok {
+				evt := graph.addNode(eventNode, s.PackageName, s.Name)
+				aggregate := graph.addNode(aggregateNode, s.PackageName, ann.Attributes[eventAnnotation.ParamAggregate])
+				graph.addEdge(evt, aggregate, "applies to")
+			}
+			if _, ok := eventServiceAnnotations.ResolveAnnotationByName(s.DocLines, eventServiceAnnotation.TypeEventService); ok {
+				graph.addNode(serviceNode, s.PackageName, s.Name)
+			}
+		}
+	}
+	for _, parsed := range parsedSources {
+		for _, o := range parsed.Operations {
+			if o.RelatedStruct == nil {
+				continue
+			}
+			serviceName := strings.TrimPrefix(o.RelatedStruct.TypeName, "*")
+			if ann, ok := eventServiceAnnotations.ResolveAnnotationByName(o.DocLines, eventServiceAnnotation.TypeEventOperation); ok {
+				service := graph.addNode(serviceNode, o.PackageName, serviceName)
+				if eventType := eventService.GetInputArgType(o); eventType != "" {
+					evt := graph.addNode(eventNode, qualifier(eventService.GetInputArgPackage(o), o.PackageName), eventType)
+					graph.addEdge(evt, service, "handled by")
+				}
+				graph.addPublished(service, o.PackageName, ann.Attributes[eventServiceAnnotation.ParamProducesEvents])
+			}
+			if ann, ok := restAnnotations.ResolveAnnotationByName(o.DocLines, restAnnotation.TypeRestOperation); ok {
+				if produced := ann.Attributes[restAnnotation.ParamProducesEvents]; produced != "" {
+					service := graph.addNode(serviceNode, o.PackageName, serviceName)
+					graph.addPublished(service, o.PackageName, produced)
+				}
+			}
+		}
+	}
+	return graph
+}
+
+// addPublished adds the events of a comma-separated producesevents-attribute, like "TourCreated,events.TourDeleted"
+func (g *eventGraph) addPublished(service string, packageName string, produced string) {
+	for _, name := range strings.Split(produced, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		eventPackage := ""
+		if idx := strings.LastIndex(name, "."); idx >= 0 {
+			eventPackage, name = name[:idx], name[idx+1:]
+		}
+		g.addEdge(service, g.addNode(eventNode, qualifier(eventPackage, packageName), name), "publishes")
+	}
+}
+
+// qualifier returns the package of a type: the package it refers to, otherwise the package it is used in
+func qualifier(typePackage string, packageName string) string {
+	if typePackage != "" {
+		return typePackage
+	}
+	return packageName
+}
+
+// label returns the name of a node, qualified by its package when the graph spans packages
+func (g *eventGraph) label(node graphNode) string {
+	for _, other := range g.nodes {
+		if other.packageName != node.packageName {
+			return node.packageName + "." + node.name
+		}
+	}
+	return node.name
+}
+
+func (g *eventGraph) write(w io.Writer, format string) error {
+	switch format {
+	case "dot":
+		g.writeDot(w)
+		return nil
+	case "mermaid":
+		g.writeMermaid(w)
+		return nil
+	}
+	return fmt.Errorf("Unknown format %s: expected dot or mermaid", format)
+}
+
+func (g *eventGraph) writeDot(w io.Writer) {
+	shapes := map[string]string{serviceNode: "box", eventNode: "ellipse", aggregateNode: "hexagon"}
+	fmt.Fprintf(w, "digraph events {\n")
+	fmt.Fprintf(w, "\trankdir=LR;\n")
+	for _, node := range g.nodes {
+		fmt.Fprintf(w, "\t%q [label=%q, shape=%s];\n", node.id, g.label(node), shapes[node.kind])
+	}
+	for _, edge := range g.edges {
+		fmt.Fprintf(w, "\t%q -> %q [label=%q];\n", edge.from, edge.to, edge.label)
+	}
+	fmt.Fprintf(w, "}\n")
+}
+
+func (g *eventGraph) writeMermaid(w io.Writer) {
+	shapes := map[string][2]string{serviceNode: {"[", "]"}, eventNode: {"([", "])"}, aggregateNode: {"{{", "}}"}}
+	ids := map[string]string{}
+	fmt.Fprintf(w, "flowchart LR\n")
+	for idx, node := range g.nodes {
+		ids[node.id] = fmt.Sprintf("n%d", idx)
+		shape := shapes[node.kind]
+		fmt.Fprintf(w, "\t%s%s\"%s\"%s\n", ids[node.id], shape[0], g.label(node), shape[1])
+	}
+	for _, edge := range g.edges {
+		fmt.Fprintf(w, "\t%s -->|%s| %s\n", ids[edge.from], edge.label, ids[edge.to])
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func graphSources() []model.ParsedSources {
+	return []model.ParsedSources{
+		{
+			Structs: []model.Struct{
+				{PackageName: "events", Name: "TourCreated", DocLines: []string{`// @Event( aggregate = "Tour" )`}},
+			},
+		},
+		{
+			Structs: []model.Struct{
+				{PackageName: "tour", Name: "TourService", DocLines: []string{`// @RestService( path = "/api" )`}},
+				{PackageName: "tour", Name: "Notifier", DocLines: []string{`// @EventService( self = "notifier" )`}},
+			},
+			Operations: []model.Operation{
+				{
+					PackageName:   "tour",
+					Name:          "createTour",
+					DocLines:      []string{`// @RestOperation( method = "POST", path = "/tour", producesevents = "events.TourCreated" )`},
+					RelatedStruct: &model.Field{TypeName: "*TourService"},
+				},
+				{
+					PackageName:   "tour",
+					Name:          "onTourCreated",
+					DocLines:      []string{`// @EventOperation( topic = "tour" )`},
+					RelatedStruct: &model.Field{TypeName: "*Notifier"},
+					InputArgs: []model.Field{
+						{Name: "c", TypeName: "context.Context"},
+						{Name: "evt", TypeName: "events.TourCreated"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestEventGraphDot(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, buildEventGraph(graphSources()).write(&buf, "dot"))
+	assert.Equal(t, `digraph events {
+	rankdir=LR;
+	"event:events.TourCreated" [label="events.TourCreated", shape=ellipse];
+	"aggregate:events.Tour" [label="events.Tour", shape=hexagon];
+	"service:tour.Notifier" [label="tour.Notifier", shape=box];
+	"service:tour.TourService" [label="tour.TourService", shape=box];
+	"event:events.TourCreated" -> "aggregate:events.Tour" [label="applies to"];
+	"service:tour.TourService" -> "event:events.TourCreated" [label="publishes"];
+	"event:events.TourCreated" -> "service:tour.Notifier" [label="handled by"];
+}
+`, buf.String())
+}
+
+func TestEventGraphMermaid(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, buildEventGraph(graphSources()[:1]).write(&buf, "mermaid"))
+	assert.Equal(t, "flowchart LR\n"+
+		"\tn0([\"TourCreated\"])\n"+
+		"\tn1{{\"Tour\"}}\n"+
+		"\tn0 -->|applies to| n1\n", buf.String())
+
+	assert.Error(t, buildEventGraph(nil).write(&buf, "svg"))
+}
@@ -0,0 +1,147 @@
+// Package lint finds the errors in annotations that generators would silently ignore or only report when generating
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+// KnownAnnotationNames returns the names of the annotations of all registered generators
+func KnownAnnotationNames(registry *generator.Registry) map[string]bool {
+	names := map[string]bool{}
+	for _, g := range registry.All() {
+		for _, descriptor := range g.GetAnnotations() {
+			names[descriptor.Name] = true
+		}
+	}
+	return names
+}
+
+func allDescriptors(registry *generator.Registry) []annotation.AnnotationDescriptor {
+	descriptors := []annotation.AnnotationDescriptor{}
+	for _, g := range registry.All() {
+		descriptors = append(descriptors, g.GetAnnotations()...)
+	}
+	return descriptors
+}
+
+var annotationNamePattern = regexp.MustCompile(`^//\s*@(\w+)`)
+
+// AnnotationNames returns the names of the annotations in doc-lines
+func AnnotationNames(docLines []string) []string {
+	names := []string{}
+	for _, docLine := range docLines {
+		if name, ok := annotationName(docLine); ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func annotationName(docLine string) (string, bool) {
+	match := annotationNamePattern.FindStringSubmatch(strings.TrimSpace(docLine))
+	if match == nil {
+		return "", false
+	}
+	return match[1], true
+}
+
+// UnknownAnnotations returns an error for every annotation in the parsed sources that no generator knows, which
+// usually is a typo that makes the annotation silently ignored
+func UnknownAnnotations(parsed model.ParsedSources, known map[string]bool) []error {
+	return checkAnnotations(parsed, func(name string, docLine string) string {
+		if !known[name] {
+			return fmt.Sprintf("Unknown annotation @%s", name)
+		}
+		return ""
+	})
+}
+
+// MalformedAnnotations returns an error for every known annotation that does not parse or that its generator
+// rejects, like a missing closing parenthesis or a required attribute that is left out: generators silently
+// ignore these as well
+func MalformedAnnotations(parsed model.ParsedSources, registry *generator.Registry) []error {
+	known := KnownAnnotationNames(registry)
+	annotations := annotation.NewRegistry(allDescriptors(registry))
+	return checkAnnotations(parsed, func(name string, docLine string) string {
+		if _, ok := annotations.ResolveAnnotation(strings.TrimSpace(docLine)); known[name] && !ok {
+			return fmt.Sprintf("Annotation @%s is malformed or has invalid attributes", name)
+		}
+		return ""
+	})
+}
+
+// UnresolvedReceivers returns an error for every annotated method with a receiver that is no struct of the parsed
+// sources, like a struct that is declared in a generated file: generators that handle the methods of structs
+// silently drop its annotations
+func UnresolvedReceivers(parsed model.ParsedSources) []error {
+	errs := []error{}
+	for _, o := range parsed.UnresolvedOperations() {
+		names := AnnotationNames(o.DocLines)
+		if len(names) == 0 {
+			continue
+		}
+		owner := o.RelatedStruct.DereferencedTypeName()
+		errs = append(errs, generator.OperationError(o, owner, names[0],
+			"Receiver %s of annotated method %s is no struct of package %s in the parsed sources, so generators do not see its annotations",
+			owner, o.Name, o.PackageName))
+	}
+	return errs
+}
+
+// checkAnnotations returns an error for every annotation of the parsed sources that check returns a message for.
+// Structs are typedefs in the model as well, so the errors are reported once.
+func checkAnnotations(parsed model.ParsedSources, check func(name string, docLine string) string) []error {
+	errs := []error{}
+	reported := map[string]bool{}
+	checkDocLines := func(docLines []string, sourceError func(name string, message string) error) {
+		for _, docLine := range docLines {
+			name, ok := annotationName(docLine)
+			if !ok {
+				continue
+			}
+			if message := check(name, docLine); message != "" {
+				err := sourceError(name, message)
+				if !reported[err.Error()] {
+					reported[err.Error()] = true
+					errs = append(errs, err)
+				}
+			}
+		}
+	}
+	for _, s := range parsed.Structs {
+		checkDocLines(s.DocLines, func(name string, message string) error { return generator.StructError(s, name, "%s", message) })
+		for _, f := range s.Fields {
+			checkDocLines(f.DocLines, func(name string, message string) error { return generator.FieldError(s, f, name, "%s", message) })
+		}
+	}
+	for _, o := range parsed.Operations {
+		owner := ""
+		if o.RelatedStruct != nil {
+			owner = strings.TrimPrefix(o.RelatedStruct.TypeName, "*")
+		}
+		checkDocLines(o.DocLines, func(name string, message string) error {
+			return generator.OperationError(o, owner, name, "%s", message)
+		})
+	}
+	for _, i := range parsed.Interfaces {
+		checkDocLines(i.DocLines, func(name string, message string) error { return generator.InterfaceError(i, name, "%s", message) })
+		for _, m := range i.Methods {
+			checkDocLines(m.DocLines, func(name string, message string) error {
+				return generator.OperationError(m, i.Name, name, "%s", message)
+			})
+		}
+	}
+	for _, t := range parsed.Typedefs {
+		checkDocLines(t.DocLines, func(name string, message string) error { return generator.TypedefError(t, name, "%s", message) })
+	}
+	for _, e := range parsed.Enums {
+		checkDocLines(e.DocLines, func(name string, message string) error { return generator.EnumError(e, name, "%s", message) })
+	}
+	return errs
+}
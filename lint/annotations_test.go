@@ -0,0 +1,106 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator/builtin"
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnnotationNames(t *testing.T) {
+	assert.Equal(t, []string{"RestService", "Event"}, AnnotationNames([]string{
+		`// @RestService( path = "/api" )`,
+		`// mail support@example.com`,
+		`//@Event()`,
+	}))
+}
+
+func TestUnknownAnnotations(t *testing.T) {
+	parsed := model.ParsedSources{
+		Structs: []model.Struct{
+			{
+				Filename: "tour.go",
+				Line:     12,
+				Name:     "TourService",
+				DocLines: []string{`// @RestService( path = "/api" )`, `// @RestServce()`},
+			},
+		},
+		Operations: []model.Operation{
+			{
+				Filename:      "tour.go",
+				Line:          20,
+				Name:          "getTour",
+				DocLines:      []string{`// @RestOperation( path = "/tour" )`},
+				RelatedStruct: &model.Field{TypeName: "*TourService"},
+			},
+		},
+	}
+	errs := UnknownAnnotations(parsed, map[string]bool{"RestService": true, "RestOperation": true})
+	assert.Len(t, errs, 1)
+	assert.EqualError(t, errs[0], "tour.go:11: Unknown annotation @RestServce")
+}
+
+func TestMalformedAnnotations(t *testing.T) {
+	parsed := model.ParsedSources{
+		Structs: []model.Struct{
+			{
+				Filename: "tour.go",
+				Line:     12,
+				Name:     "TourCreated",
+				DocLines: []string{`// @Event( aggregate = "Tour"`, `// @Unknown( x`},
+			},
+		},
+		Typedefs: []model.Typedef{
+			{Filename: "tour.go", Line: 12, Name: "TourCreated", DocLines: []string{`// @Event( aggregate = "Tour"`, `// @Unknown( x`}},
+		},
+		Operations: []model.Operation{
+			{
+				Filename:      "tour.go",
+				Line:          20,
+				Name:          "getTour",
+				DocLines:      []string{`// @RestOperation( method = "GET", path = "/tour" )`},
+				RelatedStruct: &model.Field{TypeName: "*TourService"},
+			},
+		},
+	}
+	errs := MalformedAnnotations(parsed, builtin.NewRegistry())
+	assert.Len(t, errs, 1)
+	assert.EqualError(t, errs[0], "tour.go:10: Annotation @Event is malformed or has invalid attributes")
+}
+
+func TestUnresolvedReceivers(t *testing.T) {
+	parsed := model.ParsedSources{
+		Structs: []model.Struct{
+			{PackageName: "tour", Filename: "tour.go", Line: 12, Name: "TourService"},
+		},
+		Operations: []model.Operation{
+			{
+				PackageName:   "tour",
+				Filename:      "tour.go",
+				Line:          20,
+				Name:          "getTour",
+				DocLines:      []string{`// @RestOperation( method = "GET", path = "/tour" )`},
+				RelatedStruct: &model.Field{TypeName: "*TourService"},
+			},
+			{
+				PackageName:   "tour",
+				Filename:      "tour.go",
+				Line:          30,
+				Name:          "getStop",
+				DocLines:      []string{`// @RestOperation( method = "GET", path = "/stop" )`},
+				RelatedStruct: &model.Field{TypeName: "*StopService"},
+			},
+			{
+				PackageName:   "tour",
+				Filename:      "tour.go",
+				Line:          40,
+				Name:          "String",
+				RelatedStruct: &model.Field{TypeName: "Status"},
+			},
+		},
+	}
+	errs := UnresolvedReceivers(parsed)
+	assert.Len(t, errs, 1)
+	assert.EqualError(t, errs[0], "tour.go:29: Receiver StopService of annotated method getStop is no struct of package tour in the parsed sources, so generators do not see its annotations")
+}
@@ -0,0 +1,166 @@
+package lint
+
+import (
+	"go/ast"
+	"go/token"
+	"path/filepath"
+	"strings"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/parser"
+)
+
+// Finding is an error in the annotations of a parsed file, between its positions in the file-set. Annotations that
+// generators ignore are warnings, the errors that generators report are errors.
+type Finding struct {
+	Pos      token.Pos
+	End      token.Pos
+	Severity string
+	Message  string
+	Fixes    []Fix
+}
+
+// CheckFiles checks the annotations of the files of a package for the generators in registry, for tools that parse
+// the sources themselves, like go vet. It finds:
+//   - annotations that no generator knows
+//   - known annotations that are malformed or have invalid attributes
+//   - known annotations outside the doc-comment of a declaration, where no generator sees them
+//   - annotated methods of a receiver that is no struct of the package, like one in a generated file
+//   - the errors that the generators find in the annotated declarations
+//
+// Unknown and malformed annotations come with a fix when it is clear what was meant. Generated files are skipped.
+func CheckFiles(fileSet *token.FileSet, files []*ast.File, registry *generator.Registry) []Finding {
+	sources := []*ast.File{}
+	for _, file := range files {
+		if !strings.HasPrefix(filepath.Base(fileSet.Position(file.Pos()).Filename), generator.GenfilePrefix) {
+			sources = append(sources, file)
+		}
+	}
+	if len(sources) == 0 {
+		return nil
+	}
+
+	known := KnownAnnotationNames(registry)
+	findings := []Finding{}
+	for _, file := range sources {
+		findings = append(findings, misplacedAnnotations(file, known)...)
+	}
+
+	descriptors := allDescriptors(registry)
+	report := func(errs []error, severity string) {
+		for _, err := range errs {
+			sourceError, ok := generator.AsSourceError(err)
+			if !ok {
+				continue
+			}
+			finding := Finding{Severity: severity, Message: sourceError.Message}
+			if comment := annotationComment(fileSet, sources, sourceError); comment != nil {
+				finding.Pos, finding.End = comment.Pos(), comment.End()
+				title, text, fixable := fixAnnotation(comment.Text, descriptors, known)
+				if fixable && severity == generator.SeverityWarning {
+					finding.Fixes = []Fix{{Title: title, Pos: comment.Pos(), End: comment.End(), NewText: text}}
+				}
+			} else {
+				finding.Pos = position(fileSet, sources, sourceError)
+				finding.End = finding.Pos
+			}
+			findings = append(findings, finding)
+		}
+	}
+
+	parsed := parser.New().ParseFiles(fileSet, sources)
+	report(UnknownAnnotations(parsed, known), generator.SeverityWarning)
+	report(MalformedAnnotations(parsed, registry), generator.SeverityWarning)
+	report(UnresolvedReceivers(parsed), generator.SeverityWarning)
+	inputDir := filepath.Dir(fileSet.Position(sources[0].Pos()).Filename)
+	for _, g := range registry.All() {
+		_, err := generator.Render(g, parsed, generator.Config{InputDir: inputDir})
+		report(sourceErrors(err), generator.SeverityError)
+	}
+	return findings
+}
+
+// misplacedAnnotations finds the known annotations in comments that are not the doc-comment of a declaration,
+// like an annotation separated from its struct by an empty line
+func misplacedAnnotations(file *ast.File, known map[string]bool) []Finding {
+	attached := map[*ast.CommentGroup]bool{}
+	ast.Inspect(file, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.GenDecl:
+			attached[n.Doc] = true
+		case *ast.FuncDecl:
+			attached[n.Doc] = true
+		case *ast.TypeSpec:
+			attached[n.Doc], attached[n.Comment] = true, true
+		case *ast.ValueSpec:
+			attached[n.Doc], attached[n.Comment] = true, true
+		case *ast.Field:
+			attached[n.Doc], attached[n.Comment] = true, true
+		}
+		return true
+	})
+	findings := []Finding{}
+	for _, group := range file.Comments {
+		if attached[group] {
+			continue
+		}
+		for _, comment := range group.List {
+			if name, ok := annotationName(comment.Text); ok && known[name] {
+				findings = append(findings, Finding{
+					Pos:      comment.Pos(),
+					End:      comment.End(),
+					Severity: generator.SeverityWarning,
+					Message:  "Annotation @" + name + " is not in the doc-comment of a declaration, so no generator sees it",
+				})
+			}
+		}
+	}
+	return findings
+}
+
+// sourceErrors returns the errors in annotated sources that err is or combines: other errors, like a generator
+// that cannot determine the import path of the package, are left to generation
+func sourceErrors(err error) []error {
+	if err == nil {
+		return nil
+	}
+	if errs, ok := err.(generator.Errors); ok {
+		found := []error{}
+		for _, err := range errs {
+			found = append(found, sourceErrors(err)...)
+		}
+		return found
+	}
+	if _, ok := generator.AsSourceError(err); ok {
+		return []error{err}
+	}
+	return nil
+}
+
+// annotationComment returns the annotation-comment that a source error is positioned at
+func annotationComment(fileSet *token.FileSet, files []*ast.File, sourceError generator.SourceError) *ast.Comment {
+	for _, file := range files {
+		if filepath.ToSlash(fileSet.Position(file.Pos()).Filename) != sourceError.Filename {
+			continue
+		}
+		for _, group := range file.Comments {
+			for _, comment := range group.List {
+				if _, ok := annotationName(comment.Text); ok && fileSet.Position(comment.Pos()).Line == sourceError.Line {
+					return comment
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// position returns the start of the line of a source error, or the package clause when it cannot be located
+func position(fileSet *token.FileSet, files []*ast.File, sourceError generator.SourceError) token.Pos {
+	for _, file := range files {
+		tokenFile := fileSet.File(file.Pos())
+		if filepath.ToSlash(tokenFile.Name()) == sourceError.Filename && sourceError.Line > 0 && sourceError.Line <= tokenFile.LineCount() {
+			return tokenFile.LineStart(sourceError.Line)
+		}
+	}
+	return files[0].Package
+}
@@ -0,0 +1,61 @@
+package lint
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator/builtin"
+	"github.com/stretchr/testify/assert"
+)
+
+const lintedSource = `package tour
+
+import "context"
+
+// @RestService( path = "/api" )
+type TourService struct{}
+
+// @RestOperation( method = "GET", path = "/tour/{uid}" )
+func (ts *TourService) getTour(c context.Context, id string) (*Tour, error) {
+	return nil, nil
+}
+
+// @RestOperaton( method = "POST", path = "/tour" )
+func (ts *TourService) createTour(c context.Context) error {
+	return nil
+}
+
+// @JsonStruct()
+
+type Tour struct{}
+`
+
+func TestCheckFiles(t *testing.T) {
+	fileSet := token.NewFileSet()
+	file, err := parser.ParseFile(fileSet, "tour.go", lintedSource, parser.ParseComments)
+	assert.NoError(t, err)
+	generated, err := parser.ParseFile(fileSet, "gen_tour.go", "package tour\n\n// @Unknown()\ntype Generated struct{}\n", parser.ParseComments)
+	assert.NoError(t, err)
+
+	messages := map[int]string{}
+	fixes := map[int][]Fix{}
+	for _, finding := range CheckFiles(fileSet, []*ast.File{file, generated}, builtin.NewRegistry()) {
+		position := fileSet.Position(finding.Pos)
+		assert.Equal(t, "tour.go", position.Filename)
+		messages[position.Line] = finding.Severity + ": " + finding.Message
+		fixes[position.Line] = finding.Fixes
+	}
+	assert.Equal(t, map[int]string{
+		8:  "error: Path parameter {uid} of operation TourService.getTour has no matching argument",
+		13: "warning: Unknown annotation @RestOperaton",
+		18: "warning: Annotation @JsonStruct is not in the doc-comment of a declaration, so no generator sees it",
+	}, messages)
+
+	assert.Empty(t, fixes[8])
+	assert.Len(t, fixes[13], 1)
+	assert.Equal(t, "Replace @RestOperaton by @RestOperation", fixes[13][0].Title)
+	assert.Equal(t, `// @RestOperation( method = "POST", path = "/tour" )`, fixes[13][0].NewText)
+	assert.Equal(t, `// @RestOperaton( method = "POST", path = "/tour" )`, lintedSource[fileSet.Position(fixes[13][0].Pos).Offset:fileSet.Position(fixes[13][0].End).Offset])
+}
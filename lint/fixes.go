@@ -0,0 +1,188 @@
+package lint
+
+import (
+	"fmt"
+	"go/token"
+	"sort"
+	"strings"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/MarcGrol/golangAnnotations/generator/rest/restAnnotation"
+)
+
+// Fix is an edit that resolves a finding: it replaces the text between Pos and End by NewText
+type Fix struct {
+	Title   string
+	Pos     token.Pos
+	End     token.Pos
+	NewText string
+}
+
+// suggestedValues are the values of the attributes that fixes add, to be edited afterwards: other attributes
+// get placeholderValue
+var suggestedValues = map[string]string{
+	restAnnotation.ParamMethod: "GET",
+	restAnnotation.ParamPath:   "/",
+}
+
+const placeholderValue = "TODO"
+
+// fixAnnotation returns the title and the corrected text of an annotation-comment that no generator accepts: an
+// unknown name is replaced by the known name it resembles, an annotation without closing parenthesis is closed
+// and the attributes that the descriptor requires are added
+func fixAnnotation(text string, descriptors []annotation.AnnotationDescriptor, known map[string]bool) (string, string, bool) {
+	name, ok := annotationName(text)
+	if !ok {
+		return "", "", false
+	}
+	if !known[name] {
+		similar, found := similarName(name, known)
+		if !found {
+			return "", "", false
+		}
+		return fmt.Sprintf("Replace @%s by @%s", name, similar), strings.Replace(text, "@"+name, "@"+similar, 1), true
+	}
+
+	fixed := text
+	ann, err := annotation.Parse(text)
+	if err != nil {
+		fixed = closeAnnotation(text)
+		ann, err = annotation.Parse(fixed)
+		if err != nil {
+			return "", "", false
+		}
+	}
+	for _, descriptor := range descriptors {
+		if descriptor.Name != name {
+			continue
+		}
+		if descriptor.Validator(ann) {
+			return fmt.Sprintf("Close the annotation @%s", name), fixed, fixed != text
+		}
+		if missing, found := missingAttributes(ann, descriptor); found {
+			attributes := formatAttributes(missing)
+			return fmt.Sprintf("Add %s to @%s", attributes, name), addAttributes(fixed, ann, attributes), true
+		}
+	}
+	return "", "", false
+}
+
+func closeAnnotation(text string) string {
+	text = strings.TrimRight(text, " \t")
+	if strings.HasSuffix(text, "(") {
+		return text + ")"
+	}
+	if !strings.Contains(text, "(") {
+		return text + "()"
+	}
+	return text + " )"
+}
+
+// missingAttributes returns the smallest set of attributes of the descriptor, with suggested values, that makes the
+// annotation valid
+func missingAttributes(ann annotation.Annotation, descriptor annotation.AnnotationDescriptor) ([]string, bool) {
+	candidates := []string{}
+	for _, name := range descriptor.ParamNames {
+		if _, found := ann.Attributes[name]; !found {
+			candidates = append(candidates, name)
+		}
+	}
+	valid := func(names ...string) bool {
+		attributes := map[string]string{}
+		for name, value := range ann.Attributes {
+			attributes[name] = value
+		}
+		for _, name := range names {
+			attributes[name] = suggestedValue(name)
+		}
+		return descriptor.Validator(annotation.Annotation{Name: ann.Name, Attributes: attributes})
+	}
+	for _, name := range candidates {
+		if valid(name) {
+			return []string{name}, true
+		}
+	}
+	for idx, first := range candidates {
+		for _, second := range candidates[idx+1:] {
+			if valid(first, second) {
+				return []string{first, second}, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// formatAttributes returns the attributes with their suggested values, like method = "GET", path = "/"
+func formatAttributes(names []string) string {
+	attributes := []string{}
+	for _, name := range names {
+		attributes = append(attributes, fmt.Sprintf("%s = %q", name, suggestedValue(name)))
+	}
+	return strings.Join(attributes, ", ")
+}
+
+// addAttributes adds formatted attributes before the closing parenthesis of an annotation
+func addAttributes(text string, ann annotation.Annotation, attributes string) string {
+	closing := strings.LastIndex(text, ")")
+	before := strings.TrimRight(text[:closing], " \t")
+	separator := " "
+	if len(ann.Attributes) > 0 {
+		separator = ", "
+	}
+	return before + separator + attributes + " " + text[closing:]
+}
+
+func suggestedValue(name string) string {
+	if value, found := suggestedValues[name]; found {
+		return value
+	}
+	return placeholderValue
+}
+
+// similarName returns the known name that differs the least from name, when it is a likely typo of it
+func similarName(name string, known map[string]bool) (string, bool) {
+	const maxDistance = 2
+	names := []string{}
+	for candidate := range known {
+		names = append(names, candidate)
+	}
+	sort.Strings(names)
+	similar, best := "", maxDistance+1
+	for _, candidate := range names {
+		if d := distance(strings.ToLower(name), strings.ToLower(candidate)); d < best {
+			similar, best = candidate, d
+		}
+	}
+	return similar, similar != ""
+}
+
+// distance is the number of inserted, removed or replaced characters that turn a into b
+func distance(a string, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = smallest(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
+
+func smallest(values ...int) int {
+	min := values[0]
+	for _, value := range values[1:] {
+		if value < min {
+			min = value
+		}
+	}
+	return min
+}
@@ -0,0 +1,39 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator/builtin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFixAnnotation(t *testing.T) {
+	registry := builtin.NewRegistry()
+	descriptors := allDescriptors(registry)
+	known := KnownAnnotationNames(registry)
+
+	for text, expected := range map[string][2]string{
+		`// @RestOperaton( method = "GET" )`:      {"Replace @RestOperaton by @RestOperation", `// @RestOperation( method = "GET" )`},
+		`// @Event( aggregate = "Tour"`:           {"Close the annotation @Event", `// @Event( aggregate = "Tour" )`},
+		`// @RestOperation( path = "/tour" )`:     {`Add method = "GET" to @RestOperation`, `// @RestOperation( path = "/tour", method = "GET" )`},
+		`// @RestOperation()`:                     {`Add method = "GET" to @RestOperation`, `// @RestOperation( method = "GET" )`},
+		`// @RestOperation( path = "/tour"`:       {`Add method = "GET" to @RestOperation`, `// @RestOperation( path = "/tour", method = "GET" )`},
+		`// @FeatureFlag( whenoff = "notfound" )`: {`Add name = "TODO" to @FeatureFlag`, `// @FeatureFlag( whenoff = "notfound", name = "TODO" )`},
+	} {
+		title, fixed, ok := fixAnnotation(text, descriptors, known)
+		assert.True(t, ok, text)
+		assert.Equal(t, expected[0], title, text)
+		assert.Equal(t, expected[1], fixed, text)
+	}
+
+	for _, text := range []string{`// @Whatever()`, `// @RestOperation( method = "GET" )`, `// no annotation`} {
+		_, _, ok := fixAnnotation(text, descriptors, known)
+		assert.False(t, ok, text)
+	}
+}
+
+func TestDistance(t *testing.T) {
+	assert.Equal(t, 0, distance("event", "event"))
+	assert.Equal(t, 1, distance("restoperaton", "restoperation"))
+	assert.Equal(t, 3, distance("kitten", "sitting"))
+}
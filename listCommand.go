@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/MarcGrol/golangAnnotations/generator/event/eventAnnotation"
+	"github.com/MarcGrol/golangAnnotations/generator/eventService/eventServiceAnnotation"
+	"github.com/MarcGrol/golangAnnotations/generator/rest/restAnnotation"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+// Kinds of the elements that list shows
+const (
+	kindRestService  = "rest-service"
+	kindEventService = "event-service"
+	kindOperation    = "operation"
+	kindEvent        = "event"
+	kindAggregate    = "aggregate"
+	kindEnum         = "enum"
+)
+
+// listItem is an annotated element of the sources with its key attributes
+type listItem struct {
+	Kind       string            `json:"kind"`
+	Name       string            `json:"name"`
+	Package    string            `json:"package"`
+	Filename   string            `json:"filename,omitempty"`
+	Line       int               `json:"line,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// runList prints the annotated services, operations, events, aggregates and enums of all input-dirs
+func runList() {
+	inputDirs, err := applyProjectConfig()
+	if err != nil {
+		fail(exitUsage, "Error in config: %s", err)
+	}
+
+	c, stop := interruptibleContext()
+	defer stop()
+
+	items := listAnnotated(parseInputDirs(c, inputDirs))
+	err = printList(os.Stdout, items, *listFormat)
+	if err != nil {
+		fail(exitUsage, "Error listing: %s", err)
+	}
+}
+
+// listAnnotated returns the annotated elements of the parsed sources, ordered by kind
+func listAnnotated(parsedSources []model.ParsedSources) []listItem {
+	restAnnotations := annotation.NewRegistry(restAnnotation.Get())
+	eventServiceAnnotations := annotation.NewRegistry(eventServiceAnnotation.Get())
+	eventAnnotations := annotation.NewRegistry(eventAnnotation.Get())
+
+	services := []listItem{}
+	operations := []listItem{}
+	events := []listItem{}
+	aggregates := []listItem{}
+	enums := []listItem{}
+	aggregateEvents := map[string]int{}
+	for _, parsed := range parsedSources {
+		for _, s := range parsed.Structs {
+			item := listItem{Name: s.Name, Package: s.PackageName, Filename: s.Filename, Line: s.Line}
+			if ann, ok := restAnnotations.ResolveAnnotationByName(s.DocLines, restAnnotation.TypeRestService); ok {
+				item.Kind, item.Attributes = kindRestService, ann.Attributes
+				services = append(services, item)
+			}
+			if ann, ok := eventServiceAnnotations.ResolveAnnotationByName(s.DocLines, eventServiceAnnotation.TypeEventService); ok {
+				item.Kind, item.Attributes = kindEventService, ann.Attributes
+				services = append(services, item)
+			}
+			if ann, ok := eventAnnotations.ResolveAnnotationByName(s.DocLines, eventAnnotation.TypeEvent); ok {
+				item.Kind, item.Attributes = kindEvent, ann.Attributes
+				events = append(events, item)
+				aggregate := s.PackageName + "." + ann.Attributes[eventAnnotation.ParamAggregate]
+				if aggregateEvents[aggregate] == 0 {
+					aggregates = append(aggregates, listItem{Kind: kindAggregate, Name: ann.Attributes[eventAnnotation.ParamAggregate], Package: s.PackageName})
+				}
+				aggregateEvents[aggregate]++
+			}
+		}
+		for _, o := range parsed.Operations {
+			name := o.Name
+			if o.RelatedStruct != nil {
+				name = strings.TrimPrefix(o.RelatedStruct.TypeName, "*") + "." + o.Name
+			}
+			item := listItem{Kind: kindOperation, Name: name, Package: o.PackageName, Filename: o.Filename, Line: o.Line}
+			if ann, ok := restAnnotations.ResolveAnnotationByName(o.DocLines, restAnnotation.TypeRestOperation); ok {
+				item.Attributes = ann.Attributes
+				operations = append(operations, item)
+			} else if ann, ok := eventServiceAnnotations.ResolveAnnotationByName(o.DocLines, eventServiceAnnotation.TypeEventOperation); ok {
+				item.Attributes = ann.Attributes
+				operations = append(operations, item)
+			}
+		}
+		for _, e := range parsed.Enums {
+			literals := []string{}
+			for _, literal := range e.EnumLiterals {
+				literals = append(literals, literal.Name)
+			}
+			enums = append(enums, listItem{Kind: kindEnum, Name: e.Name, Package: e.PackageName, Filename: e.Filename, Line: e.Line,
+				Attributes: map[string]string{"literals": strings.Join(literals, ",")}})
+		}
+	}
+	for idx, aggregate := range aggregates {
+		aggregates[idx].Attributes = map[string]string{"events": fmt.Sprint(aggregateEvents[aggregate.Package+"."+aggregate.Name])}
+	}
+
+	items := []listItem{}
+	for _, kind := range [][]listItem{services, operations, events, aggregates, enums} {
+		items = append(items, kind...)
+	}
+	return items
+}
+
+func printList(w io.Writer, items []listItem, format string) error {
+	switch format {
+	case "json":
+		data, err := json.MarshalIndent(items, "", "\t")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%s\n", data)
+		return nil
+	case "table":
+		tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+		fmt.Fprintf(tw, "Kind\tName\tPackage\tAttributes\tLocation\n")
+		for _, item := range items {
+			location := ""
+			if item.Filename != "" {
+				location = fmt.Sprintf("%s:%d", item.Filename, item.Line)
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", item.Kind, item.Name, item.Package, formatAttributes(item.Attributes), location)
+		}
+		return tw.Flush()
+	}
+	return fmt.Errorf("Unknown format %s: expected table or json", format)
+}
+
+// formatAttributes returns the attributes sorted by name
+func formatAttributes(attributes map[string]string) string {
+	names := []string{}
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	formatted := []string{}
+	for _, name := range names {
+		formatted = append(formatted, name+"="+attributes[name])
+	}
+	return strings.Join(formatted, " ")
+}
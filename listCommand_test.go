@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListAnnotated(t *testing.T) {
+	items := listAnnotated([]model.ParsedSources{{
+		Structs: []model.Struct{
+			{PackageName: "tour", Filename: "tour.go", Line: 3, Name: "TourCreated", DocLines: []string{`// @Event( aggregate = "Tour" )`}},
+			{PackageName: "tour", Filename: "tour.go", Line: 8, Name: "TourDeleted", DocLines: []string{`// @Event( aggregate = "Tour" )`}},
+			{PackageName: "tour", Filename: "service.go", Line: 5, Name: "TourService", DocLines: []string{`// @RestService( path = "/api" )`}},
+			{PackageName: "tour", Name: "Tour"},
+		},
+		Operations: []model.Operation{
+			{PackageName: "tour", Filename: "service.go", Line: 9, Name: "getTour", DocLines: []string{`// @RestOperation( method = "GET", path = "/tour/{uid}" )`},
+				RelatedStruct: &model.Field{TypeName: "*TourService"}},
+			{PackageName: "tour", Name: "helper"},
+		},
+		Enums: []model.Enum{
+			{PackageName: "tour", Filename: "status.go", Line: 4, Name: "Status", EnumLiterals: []model.EnumLiteral{{Name: "StatusOpen"}, {Name: "StatusClosed"}}},
+		},
+	}})
+	assert.Equal(t, []listItem{
+		{Kind: kindRestService, Name: "TourService", Package: "tour", Filename: "service.go", Line: 5, Attributes: map[string]string{"path": "/api"}},
+		{Kind: kindOperation, Name: "TourService.getTour", Package: "tour", Filename: "service.go", Line: 9, Attributes: map[string]string{"method": "GET", "path": "/tour/{uid}"}},
+		{Kind: kindEvent, Name: "TourCreated", Package: "tour", Filename: "tour.go", Line: 3, Attributes: map[string]string{"aggregate": "Tour"}},
+		{Kind: kindEvent, Name: "TourDeleted", Package: "tour", Filename: "tour.go", Line: 8, Attributes: map[string]string{"aggregate": "Tour"}},
+		{Kind: kindAggregate, Name: "Tour", Package: "tour", Attributes: map[string]string{"events": "2"}},
+		{Kind: kindEnum, Name: "Status", Package: "tour", Filename: "status.go", Line: 4, Attributes: map[string]string{"literals": "StatusOpen,StatusClosed"}},
+	}, items)
+}
+
+func TestPrintList(t *testing.T) {
+	items := []listItem{{Kind: kindRestService, Name: "TourService", Package: "tour", Filename: "service.go", Line: 5, Attributes: map[string]string{"path": "/api", "credentials": "true"}}}
+
+	var buf bytes.Buffer
+	assert.NoError(t, printList(&buf, items, "table"))
+	assert.Equal(t, ""+
+		"Kind          Name         Package  Attributes                  Location\n"+
+		"rest-service  TourService  tour     credentials=true path=/api  service.go:5\n", buf.String())
+
+	buf.Reset()
+	assert.NoError(t, printList(&buf, items, "json"))
+	assert.Contains(t, buf.String(), `"kind": "rest-service"`)
+
+	assert.Error(t, printList(&buf, items, "xml"))
+}
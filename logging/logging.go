@@ -0,0 +1,117 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Level is the amount of output of a Logger
+type Level int
+
+const (
+	// Quiet only reports errors
+	Quiet Level = iota
+	// Normal also reports warnings and a summary of what was generated
+	Normal
+	// Verbose also reports every file that is written or removed
+	Verbose
+	// Debug also reports the details of parsing and generating
+	Debug
+)
+
+var levelNames = []string{"quiet", "normal", "verbose", "debug"}
+
+func (level Level) String() string {
+	if level < Quiet || int(level) >= len(levelNames) {
+		return fmt.Sprintf("level(%d)", level)
+	}
+	return levelNames[level]
+}
+
+// ParseLevel returns the level with the given name
+func ParseLevel(name string) (Level, error) {
+	for idx, levelName := range levelNames {
+		if strings.EqualFold(name, levelName) {
+			return Level(idx), nil
+		}
+	}
+	return Normal, fmt.Errorf("Unknown log-level %s: choose from %s", name, strings.Join(levelNames, ", "))
+}
+
+// Logger writes the messages up to its level as lines to its output. It can be used from multiple goroutines.
+// A nil Logger behaves like Default, so that library callers only need to pass one to change the output.
+type Logger struct {
+	out   io.Writer
+	level Level
+	mutex sync.Mutex
+}
+
+// New returns a logger that writes the messages up to level to out
+func New(out io.Writer, level Level) *Logger {
+	return &Logger{out: out, level: level}
+}
+
+// Default returns a logger that writes errors, warnings and summaries to stderr
+func Default() *Logger {
+	return New(os.Stderr, Normal)
+}
+
+// Discard returns a logger that writes nothing
+func Discard() *Logger {
+	return New(ioutil.Discard, Quiet)
+}
+
+// Level returns the level up to which messages are written
+func (l *Logger) Level() Level {
+	if l == nil {
+		return Normal
+	}
+	return l.level
+}
+
+// Enabled tells if messages of the given level are written, to avoid composing expensive messages needlessly
+func (l *Logger) Enabled(level Level) bool {
+	return level <= l.Level()
+}
+
+// Errorf reports an error: errors are written at every level
+func (l *Logger) Errorf(format string, args ...interface{}) {
+	l.logf(Quiet, format, args...)
+}
+
+// Warningf reports a problem that does not stop generation
+func (l *Logger) Warningf(format string, args ...interface{}) {
+	l.logf(Normal, "warning: "+format, args...)
+}
+
+// Infof reports the outcome of a run
+func (l *Logger) Infof(format string, args ...interface{}) {
+	l.logf(Normal, format, args...)
+}
+
+// Verbosef reports the individual steps of a run
+func (l *Logger) Verbosef(format string, args ...interface{}) {
+	l.logf(Verbose, format, args...)
+}
+
+// Debugf reports details that help to find out why generation does not do what is expected
+func (l *Logger) Debugf(format string, args ...interface{}) {
+	l.logf(Debug, format, args...)
+}
+
+func (l *Logger) logf(level Level, format string, args ...interface{}) {
+	if !l.Enabled(level) {
+		return
+	}
+	if l == nil {
+		fmt.Fprintln(os.Stderr, fmt.Sprintf(format, args...))
+		return
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	fmt.Fprintln(l.out, fmt.Sprintf(format, args...))
+}
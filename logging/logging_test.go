@@ -0,0 +1,46 @@
+package logging
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevels(t *testing.T) {
+	for _, tc := range []struct {
+		level    Level
+		expected string
+	}{
+		{Quiet, "error\n"},
+		{Normal, "error\nwarning: warning\ninfo\n"},
+		{Verbose, "error\nwarning: warning\ninfo\nverbose\n"},
+		{Debug, "error\nwarning: warning\ninfo\nverbose\ndebug\n"},
+	} {
+		t.Run(tc.level.String(), func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := New(&buf, tc.level)
+			logger.Errorf("error")
+			logger.Warningf("warning")
+			logger.Infof("info")
+			logger.Verbosef("verbose")
+			logger.Debugf("debug")
+			assert.Equal(t, tc.expected, buf.String())
+		})
+	}
+}
+
+func TestNilLoggerIsNormal(t *testing.T) {
+	var logger *Logger
+	assert.True(t, logger.Enabled(Normal))
+	assert.False(t, logger.Enabled(Verbose))
+}
+
+func TestParseLevel(t *testing.T) {
+	level, err := ParseLevel("Verbose")
+	assert.NoError(t, err)
+	assert.Equal(t, Verbose, level)
+
+	_, err = ParseLevel("loud")
+	assert.EqualError(t, err, "Unknown log-level loud: choose from quiet, normal, verbose, debug")
+}
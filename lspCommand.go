@@ -0,0 +1,374 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/builtin"
+	"github.com/MarcGrol/golangAnnotations/lint"
+)
+
+// Severities of diagnostics in the Language Server Protocol
+const (
+	lspError   = 1
+	lspWarning = 2
+)
+
+// Error-codes of JSON-RPC
+const (
+	rpcParseError     = -32700
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+)
+
+type rpcMessage struct {
+	ID     json.RawMessage `json:"id,omitempty"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type lspPosition struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+type lspRange struct {
+	Start lspPosition `json:"start"`
+	End   lspPosition `json:"end"`
+}
+
+type lspDiagnostic struct {
+	Range    lspRange `json:"range"`
+	Severity int      `json:"severity"`
+	Source   string   `json:"source"`
+	Message  string   `json:"message"`
+}
+
+type lspTextEdit struct {
+	Range   lspRange `json:"range"`
+	NewText string   `json:"newText"`
+}
+
+type lspCodeAction struct {
+	Title       string          `json:"title"`
+	Kind        string          `json:"kind"`
+	Diagnostics []lspDiagnostic `json:"diagnostics"`
+	Edit        struct {
+		Changes map[string][]lspTextEdit `json:"changes"`
+	} `json:"edit"`
+}
+
+type textDocumentParams struct {
+	TextDocument struct {
+		URI  string `json:"uri"`
+		Text string `json:"text"`
+	} `json:"textDocument"`
+	ContentChanges []struct {
+		Text string `json:"text"`
+	} `json:"contentChanges"`
+	Range lspRange `json:"range"`
+}
+
+// document is a file that is open in the editor: its content replaces the file on disk when linting
+type document struct {
+	uri     string
+	content []byte
+}
+
+// languageServer publishes the findings of lint.CheckFiles as diagnostics of the Language Server Protocol and
+// offers their fixes as code actions. Editors run it over stdio, next to gopls.
+type languageServer struct {
+	registry  *generator.Registry
+	out       io.Writer
+	documents map[string]document
+}
+
+// runLSP serves the Language Server Protocol on stdin and stdout until the editor exits
+func runLSP() {
+	registry := builtin.NewRegistry()
+	err := registerPlugins(registry, *plugins)
+	if err != nil {
+		fail(exitUsage, "Error registering plugins: %s", err)
+	}
+	err = newLanguageServer(registry, os.Stdout).serve(os.Stdin)
+	if err != nil {
+		fail(exitFailure, "Error serving language server: %s", err)
+	}
+}
+
+func newLanguageServer(registry *generator.Registry, out io.Writer) *languageServer {
+	return &languageServer{registry: registry, out: out, documents: map[string]document{}}
+}
+
+func (s *languageServer) serve(in io.Reader) error {
+	reader := bufio.NewReader(in)
+	for {
+		data, err := readRPC(reader)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		message := rpcMessage{}
+		err = json.Unmarshal(data, &message)
+		if err != nil {
+			s.respond(nil, nil, &rpcError{Code: rpcParseError, Message: err.Error()})
+			continue
+		}
+		if message.Method == "exit" {
+			return nil
+		}
+		s.handle(message)
+	}
+}
+
+func (s *languageServer) handle(message rpcMessage) {
+	params := textDocumentParams{}
+	if len(message.Params) > 0 {
+		err := json.Unmarshal(message.Params, &params)
+		if err != nil {
+			s.respond(message.ID, nil, &rpcError{Code: rpcInvalidParams, Message: err.Error()})
+			return
+		}
+	}
+	uri := params.TextDocument.URI
+
+	switch message.Method {
+	case "initialize":
+		s.respond(message.ID, map[string]interface{}{
+			"capabilities": map[string]interface{}{
+				"textDocumentSync":   map[string]interface{}{"openClose": true, "change": 1, "save": true},
+				"codeActionProvider": true,
+			},
+			"serverInfo": map[string]string{"name": "golangAnnotations", "version": toolVersion()},
+		}, nil)
+	case "shutdown":
+		s.respond(message.ID, nil, nil)
+	case "textDocument/didOpen":
+		s.documents[uriFilename(uri)] = document{uri: uri, content: []byte(params.TextDocument.Text)}
+		s.publish(uriFilename(uri))
+	case "textDocument/didChange":
+		if len(params.ContentChanges) > 0 {
+			// full synchronization: the last change is the whole document
+			s.documents[uriFilename(uri)] = document{uri: uri, content: []byte(params.ContentChanges[len(params.ContentChanges)-1].Text)}
+		}
+		s.publish(uriFilename(uri))
+	case "textDocument/didSave":
+		s.publish(uriFilename(uri))
+	case "textDocument/didClose":
+		delete(s.documents, uriFilename(uri))
+		s.notify("textDocument/publishDiagnostics", map[string]interface{}{"uri": uri, "diagnostics": []lspDiagnostic{}})
+		s.publish(uriFilename(uri))
+	case "textDocument/codeAction":
+		actions := []lspCodeAction{}
+		_, fixes := s.diagnose(uriFilename(uri))
+		for _, action := range fixes[uriFilename(uri)] {
+			if overlaps(action.Diagnostics[0].Range, params.Range) {
+				actions = append(actions, action)
+			}
+		}
+		s.respond(message.ID, actions, nil)
+	default:
+		if message.ID != nil {
+			s.respond(message.ID, nil, &rpcError{Code: rpcMethodNotFound, Message: "Unknown method " + message.Method})
+		}
+	}
+}
+
+// publish sends the diagnostics of all open documents in the package of filename: an edit of one file can solve
+// or cause errors in another
+func (s *languageServer) publish(filename string) {
+	diagnostics, _ := s.diagnose(filename)
+	for name, doc := range s.documents {
+		if filepath.Dir(name) != filepath.Dir(filename) {
+			continue
+		}
+		found := diagnostics[name]
+		if found == nil {
+			found = []lspDiagnostic{}
+		}
+		s.notify("textDocument/publishDiagnostics", map[string]interface{}{"uri": doc.uri, "diagnostics": found})
+	}
+}
+
+// diagnose lints the package of filename, with the open documents instead of their files on disk, and returns the
+// diagnostics and code actions per file
+func (s *languageServer) diagnose(filename string) (map[string][]lspDiagnostic, map[string][]lspCodeAction) {
+	diagnostics := map[string][]lspDiagnostic{}
+	actions := map[string][]lspCodeAction{}
+
+	fileSet := token.NewFileSet()
+	contents := map[string][]byte{}
+	files := []*ast.File{}
+	packageName := ""
+	parse := func(name string) *ast.File {
+		content, err := s.content(name)
+		if err != nil {
+			logger.Warningf("Error reading %s: %s", name, err)
+			return nil
+		}
+		// incomplete edits still have annotations worth checking
+		file, _ := goparser.ParseFile(fileSet, name, content, goparser.ParseComments)
+		if file != nil {
+			contents[name] = content
+		}
+		return file
+	}
+	if file := parse(filename); file != nil {
+		packageName = file.Name.Name
+		files = append(files, file)
+	}
+	infos, err := ioutil.ReadDir(filepath.Dir(filename))
+	if err != nil {
+		logger.Warningf("Error reading %s: %s", filepath.Dir(filename), err)
+	}
+	for _, info := range infos {
+		name := filepath.Join(filepath.Dir(filename), info.Name())
+		if info.IsDir() || !strings.HasSuffix(name, ".go") || name == filename {
+			continue
+		}
+		if file := parse(name); file != nil && file.Name.Name == packageName {
+			files = append(files, file)
+		}
+	}
+
+	for _, finding := range lint.CheckFiles(fileSet, files, s.registry) {
+		name := fileSet.Position(finding.Pos).Filename
+		diagnostic := lspDiagnostic{
+			Range:    lspRangeOf(fileSet, contents[name], finding.Pos, finding.End),
+			Severity: lspWarning,
+			Source:   "golangAnnotations",
+			Message:  finding.Message,
+		}
+		if finding.Severity == generator.SeverityError {
+			diagnostic.Severity = lspError
+		}
+		diagnostics[name] = append(diagnostics[name], diagnostic)
+		for _, fix := range finding.Fixes {
+			action := lspCodeAction{Title: fix.Title, Kind: "quickfix", Diagnostics: []lspDiagnostic{diagnostic}}
+			action.Edit.Changes = map[string][]lspTextEdit{
+				s.uri(name): {{Range: lspRangeOf(fileSet, contents[name], fix.Pos, fix.End), NewText: fix.NewText}},
+			}
+			actions[name] = append(actions[name], action)
+		}
+	}
+	return diagnostics, actions
+}
+
+func (s *languageServer) content(filename string) ([]byte, error) {
+	if doc, found := s.documents[filename]; found {
+		return doc.content, nil
+	}
+	return ioutil.ReadFile(filename)
+}
+
+// uri returns the uri of an open document as the editor knows it, otherwise the uri of the file
+func (s *languageServer) uri(filename string) string {
+	if doc, found := s.documents[filename]; found {
+		return doc.uri
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(filename)}).String()
+}
+
+func uriFilename(uri string) string {
+	parsed, err := url.Parse(uri)
+	if err != nil || parsed.Scheme != "file" {
+		return uri
+	}
+	return filepath.FromSlash(parsed.Path)
+}
+
+// lspRangeOf converts positions in the file-set to the zero-based lines and utf-16 characters of the protocol
+func lspRangeOf(fileSet *token.FileSet, content []byte, pos token.Pos, end token.Pos) lspRange {
+	toPosition := func(p token.Pos) lspPosition {
+		position := fileSet.Position(p)
+		lineStart := position.Offset - (position.Column - 1)
+		if lineStart < 0 || position.Offset > len(content) {
+			return lspPosition{Line: position.Line - 1, Character: position.Column - 1}
+		}
+		return lspPosition{Line: position.Line - 1, Character: len(utf16.Encode([]rune(string(content[lineStart:position.Offset]))))}
+	}
+	if !end.IsValid() {
+		end = pos
+	}
+	return lspRange{Start: toPosition(pos), End: toPosition(end)}
+}
+
+func overlaps(a lspRange, b lspRange) bool {
+	before := func(x lspPosition, y lspPosition) bool {
+		return x.Line < y.Line || (x.Line == y.Line && x.Character < y.Character)
+	}
+	return !before(a.End, b.Start) && !before(b.End, a.Start)
+}
+
+func (s *languageServer) respond(id json.RawMessage, result interface{}, rpcErr *rpcError) {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	response := map[string]interface{}{"jsonrpc": "2.0", "id": id}
+	if rpcErr != nil {
+		response["error"] = rpcErr
+	} else {
+		response["result"] = result
+	}
+	s.write(response)
+}
+
+func (s *languageServer) notify(method string, params interface{}) {
+	s.write(map[string]interface{}{"jsonrpc": "2.0", "method": method, "params": params})
+}
+
+func (s *languageServer) write(message interface{}) {
+	data, err := json.Marshal(message)
+	if err != nil {
+		logger.Errorf("Error encoding message: %s", err)
+		return
+	}
+	fmt.Fprintf(s.out, "Content-Length: %d\r\n\r\n%s", len(data), data)
+}
+
+// readRPC reads the content of a message that is framed by a Content-Length header
+func readRPC(reader *bufio.Reader) ([]byte, error) {
+	length := -1
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			break
+		}
+		if value := strings.TrimPrefix(line, "Content-Length:"); value != line {
+			length, err = strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return nil, fmt.Errorf("Invalid header '%s':%w", line, err)
+			}
+		}
+	}
+	if length < 0 {
+		return nil, fmt.Errorf("Missing Content-Length header")
+	}
+	data := make([]byte, length)
+	_, err := io.ReadFull(reader, data)
+	return data, err
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"io/ioutil"
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator/builtin"
+	"github.com/stretchr/testify/assert"
+)
+
+const lspSource = `package tour
+
+// @RestService( path = "/api" )
+type TourService struct{}
+
+// @RestOperaton( method = "POST", path = "/tour" )
+func (ts *TourService) createTour() error {
+	return nil
+}
+`
+
+func frame(t *testing.T, messages ...interface{}) *bytes.Buffer {
+	var buf bytes.Buffer
+	for _, message := range messages {
+		data, err := json.Marshal(message)
+		assert.NoError(t, err)
+		fmt.Fprintf(&buf, "Content-Length: %d\r\n\r\n%s", len(data), data)
+	}
+	return &buf
+}
+
+func responses(t *testing.T, out *bytes.Buffer) []map[string]interface{} {
+	reader := bufio.NewReader(out)
+	messages := []map[string]interface{}{}
+	for {
+		data, err := readRPC(reader)
+		if err != nil {
+			return messages
+		}
+		message := map[string]interface{}{}
+		assert.NoError(t, json.Unmarshal(data, &message))
+		messages = append(messages, message)
+	}
+}
+
+func TestLanguageServer(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "tour.go")
+	assert.NoError(t, ioutil.WriteFile(filename, []byte("package tour\n"), 0644))
+	uri := (&url.URL{Scheme: "file", Path: filepath.ToSlash(filename)}).String()
+
+	in := frame(t,
+		map[string]interface{}{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": map[string]interface{}{}},
+		map[string]interface{}{"jsonrpc": "2.0", "method": "initialized", "params": map[string]interface{}{}},
+		map[string]interface{}{"jsonrpc": "2.0", "method": "textDocument/didOpen", "params": map[string]interface{}{
+			"textDocument": map[string]interface{}{"uri": uri, "languageId": "go", "version": 1, "text": lspSource},
+		}},
+		map[string]interface{}{"jsonrpc": "2.0", "id": 2, "method": "textDocument/codeAction", "params": map[string]interface{}{
+			"textDocument": map[string]interface{}{"uri": uri},
+			"range":        map[string]interface{}{"start": map[string]int{"line": 5, "character": 3}, "end": map[string]int{"line": 5, "character": 3}},
+		}},
+		map[string]interface{}{"jsonrpc": "2.0", "id": 3, "method": "textDocument/hover", "params": map[string]interface{}{}},
+		map[string]interface{}{"jsonrpc": "2.0", "id": 4, "method": "shutdown"},
+		map[string]interface{}{"jsonrpc": "2.0", "method": "exit"},
+	)
+	var out bytes.Buffer
+	assert.NoError(t, newLanguageServer(builtin.NewRegistry(), &out).serve(in))
+
+	messages := responses(t, &out)
+	assert.Len(t, messages, 5)
+	assert.Contains(t, messages[0]["result"], "capabilities")
+
+	assert.Equal(t, "textDocument/publishDiagnostics", messages[1]["method"])
+	params := messages[1]["params"].(map[string]interface{})
+	assert.Equal(t, uri, params["uri"])
+	assert.Equal(t, []interface{}{map[string]interface{}{
+		"range": map[string]interface{}{
+			"start": map[string]interface{}{"line": 5.0, "character": 0.0},
+			"end":   map[string]interface{}{"line": 5.0, "character": 51.0},
+		},
+		"severity": 2.0,
+		"source":   "golangAnnotations",
+		"message":  "Unknown annotation @RestOperaton",
+	}}, params["diagnostics"])
+
+	actions := messages[2]["result"].([]interface{})
+	assert.Len(t, actions, 1)
+	action := actions[0].(map[string]interface{})
+	assert.Equal(t, "Replace @RestOperaton by @RestOperation", action["title"])
+	edits := action["edit"].(map[string]interface{})["changes"].(map[string]interface{})[uri].([]interface{})
+	assert.Equal(t, `// @RestOperation( method = "POST", path = "/tour" )`, edits[0].(map[string]interface{})["newText"])
+
+	assert.Equal(t, float64(rpcMethodNotFound), messages[3]["error"].(map[string]interface{})["code"])
+	assert.Contains(t, messages[4], "result")
+}
+
+func TestLspRangeCountsUtf16(t *testing.T) {
+	content := []byte("package tour\n\n// é𝄞 @Event()\n")
+	fileSet := token.NewFileSet()
+	file := fileSet.AddFile("tour.go", -1, len(content))
+	file.SetLinesForContent(content)
+	pos := file.Pos(bytes.Index(content, []byte("@Event")))
+	assert.Equal(t, lspRange{
+		Start: lspPosition{Line: 2, Character: 7},
+		End:   lspPosition{Line: 2, Character: 7},
+	}, lspRangeOf(fileSet, content, pos, token.NoPos))
+}
+
+func TestOverlaps(t *testing.T) {
+	diagnostic := lspRange{Start: lspPosition{Line: 5, Character: 0}, End: lspPosition{Line: 5, Character: 51}}
+	assert.True(t, overlaps(diagnostic, lspRange{Start: lspPosition{Line: 5, Character: 51}, End: lspPosition{Line: 5, Character: 51}}))
+	assert.True(t, overlaps(diagnostic, lspRange{Start: lspPosition{Line: 4, Character: 0}, End: lspPosition{Line: 6, Character: 0}}))
+	assert.False(t, overlaps(diagnostic, lspRange{Start: lspPosition{Line: 6, Character: 0}, End: lspPosition{Line: 6, Character: 0}}))
+}
+
+func TestReadRPCWithoutContentLength(t *testing.T) {
+	_, err := readRPC(bufio.NewReader(bytes.NewBufferString("Content-Type: application/json\r\n\r\n{}")))
+	assert.Error(t, err)
+}
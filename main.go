@@ -1,88 +1,796 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
-	"log"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/MarcGrol/golangAnnotations/generator"
-	"github.com/MarcGrol/golangAnnotations/generator/ast"
-	"github.com/MarcGrol/golangAnnotations/generator/event"
-	"github.com/MarcGrol/golangAnnotations/generator/eventService"
-	"github.com/MarcGrol/golangAnnotations/generator/jsonHelpers"
-	"github.com/MarcGrol/golangAnnotations/generator/repository"
+	"github.com/MarcGrol/golangAnnotations/generator/builtin"
+	"github.com/MarcGrol/golangAnnotations/generator/plugin"
 	"github.com/MarcGrol/golangAnnotations/generator/rest"
+	"github.com/MarcGrol/golangAnnotations/logging"
 	"github.com/MarcGrol/golangAnnotations/model"
 	"github.com/MarcGrol/golangAnnotations/parser"
 )
 
 const (
-	version = "0.8"
+	// baseVersion is the version of golangAnnotations when neither the build nor the module tells otherwise
+	baseVersion = "0.8"
 
 	excludeMatchPattern = "^" + generator.GenfilePrefix + ".*.go$"
+
+	// exit-codes of the cli
+	exitOK = 0
+	// exitFailure tells that generation failed, that lint found errors or that verify found stale files
+	exitFailure = 1
+	// exitUsage tells that the command, flags or config are invalid
+	exitUsage = 2
+)
+
+var (
+	// version is set when building with -ldflags "-X main.version=$(git describe --tags)", see make install
+	version string
+
+	inputDir      *string
+	plugins       *string
+	templateDir   *string
+	dumpTemplates *string
+	outputDir     *string
+	dryRun        *bool
+	verify        *bool
+	// commandName is the subcommand that is run, with the arguments that are no flags
+	commandName string
+	commandArgs []string
+
+	headerLicenseFile *string
+	headerMarker      *string
+	headerVersion     *bool
+	headerSource      *bool
+
+	configFile *string
+	profile    *string
+	reportFile *string
+
+	// runReport collects the outcome of the run for the -report flag
+	runStart  time.Time
+	runReport = generator.NewReport()
+
+	only *string
+	jobs *int
+	skip *string
+
+	quiet     *bool
+	verbose   *bool
+	debugging *bool
+	logger    = logging.Default()
+
+	// projectDir is the directory of the project config when its inputs are generated: the output-dir then
+	// keeps the layout of the inputs below this directory
+	projectDir string
+
+	outputSubdirs    = generatorSettings{}
+	filenamePatterns = generatorSettings{}
+	buildTags        = generatorSettings{}
+
+	modelFormat *string
+	modelOutput *string
+	filters     = modelFilters{}
+	listFormat  *string
+	graphFormat *string
+
+	topologyFormat   *string
+	topologyProvider *string
+	compatBaseline   *string
+
+	listen        *string
+	watchInterval *time.Duration
 )
 
-var inputDir *string
+// generatorSettings is a repeatable flag of the form <generator>=<value>
+type generatorSettings map[string]string
+
+func (s generatorSettings) String() string {
+	settings := []string{}
+	for name, value := range s {
+		settings = append(settings, name+"="+value)
+	}
+	return strings.Join(settings, ",")
+}
+
+func (s generatorSettings) Set(setting string) error {
+	parts := strings.SplitN(setting, "=", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return fmt.Errorf("Expected <generator>=<value>, got '%s'", setting)
+	}
+	s[parts[0]] = parts[1]
+	return nil
+}
 
 func main() {
-	processArgs()
+	runStart = time.Now()
+	cmd := processArgs()
+	err := startProfiling()
+	if err != nil {
+		fail(exitUsage, "%s", err)
+	}
+	cmd.run()
+	exit(exitOK)
+}
 
-	parsedSources, err := parser.New().ParseSourceDir(*inputDir, "^.*.go$", excludeMatchPattern)
+// prepare applies the project config and registers the generators: it returns the directories to generate for
+func prepare() ([]string, *generator.Registry) {
+	inputDirs, err := applyProjectConfig()
 	if err != nil {
-		log.Printf("Error parsing golang sources in %s: %s", *inputDir, err)
-		os.Exit(1)
+		fail(exitUsage, "Error in config: %s", err)
 	}
 
-	runAllGenerators(*inputDir, parsedSources)
+	registry := builtin.NewRegistry()
+	err = registerPlugins(registry, *plugins)
+	if err != nil {
+		fail(exitUsage, "Error registering plugins: %s", err)
+	}
+	return inputDirs, registry
+}
 
-	os.Exit(0)
+// parseInputDirs parses the sources of every input-dir
+func parseInputDirs(c context.Context, inputDirs []string) []model.ParsedSources {
+	parsedSources, err := parseSources(c, inputDirs)
+	if err != nil {
+		fail(exitFailure, "%s", err)
+	}
+	return parsedSources
 }
 
-func runAllGenerators(inputDir string, parsedSources model.ParsedSources) {
-	for name, g := range map[string]generator.Generator{
-		"ast":           ast.NewGenerator("ast.json"),
-		"event":         event.NewGenerator(),
-		"event-service": eventService.NewGenerator(),
-		"json-helpers":  jsonHelpers.NewGenerator(),
-		"rest":          rest.NewGenerator(),
-		"repository":    repository.NewGenerator(),
-	} {
-		err := g.Generate(inputDir, parsedSources)
+// parseSources parses the go-sources of every input-dir concurrently
+func parseSources(c context.Context, inputDirs []string) ([]model.ParsedSources, error) {
+	parsedSources := make([]model.ParsedSources, len(inputDirs))
+	err := generator.RunConcurrently(c, len(inputDirs), *jobs, func(idx int) error {
+		var err error
+		parsedSources[idx], err = parser.NewWithOptions(parser.Options{Logger: logger, Timer: timings.add}).ParseSourceDir(inputDirs[idx], "^.*.go$", excludeMatchPattern)
 		if err != nil {
-			log.Printf("Error generating module %s: %s", name, err)
-			os.Exit(-1)
+			return fmt.Errorf("Error parsing golang sources in %s: %w", inputDirs[idx], err)
+		}
+		return nil
+	})
+	return parsedSources, err
+}
+
+// renderInputDirs runs the selected generators for every input-dir, without writing anything: it fails when a
+// generator fails or when generated files collide. The results are in the order of the tasks: per input-dir, the
+// generators in the order of the returned list.
+func renderInputDirs(c context.Context, registry *generator.Registry, inputDirs []string, parsedSources []model.ParsedSources) ([]generator.Generator, []generator.Task, []generator.TaskResult) {
+	generators, tasks, err := newTasks(registry, inputDirs, parsedSources)
+	if err != nil {
+		fail(exitUsage, "%s", err)
+	}
+	results, err := generator.RenderAll(c, tasks, *jobs)
+	for idx, result := range results {
+		runReport.AddTask(tasks[idx], result)
+		timings.add("render "+tasks[idx].Generator.Name(), result.Duration)
+	}
+	if err != nil {
+		// like compiler-errors, so that editors can jump to the annotation in error
+		logger.Errorf("%s", err)
+		exit(exitFailure)
+	}
+	err = generator.DetectCollisions(tasks, results)
+	if err != nil {
+		fail(exitFailure, "%s", err)
+	}
+	return generators, tasks, results
+}
+
+// newTasks combines every input-dir with every selected generator, configured by the flags
+func newTasks(registry *generator.Registry, inputDirs []string, parsedSources []model.ParsedSources) ([]generator.Generator, []generator.Task, error) {
+	templateOverrides, err := generator.LoadTemplateOverrides(*templateDir, registry)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error loading templates: %w", err)
+	}
+
+	err = checkGeneratorNames(registry, outputSubdirs, filenamePatterns, buildTags)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error in flags: %w", err)
+	}
+
+	generators, err := registry.Select(splitNames(*only), splitNames(*skip))
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error selecting generators: %w", err)
+	}
+
+	header, err := getHeader()
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error reading header: %w", err)
+	}
+
+	tasks := []generator.Task{}
+	for idx, dir := range inputDirs {
+		for _, g := range generators {
+			tasks = append(tasks, generator.Task{
+				Generator:     g,
+				ParsedSources: parsedSources[idx],
+				Config: generator.Config{
+					InputDir:          dir,
+					OutputDir:         generatorOutputDir(g.Name(), dir),
+					FilenamePattern:   filenamePatterns[g.Name()],
+					BuildTags:         buildTags[g.Name()],
+					TemplateOverrides: templateOverrides[g.Name()],
+					Header:            header,
+					Logger:            logger,
+				},
+			})
 		}
 	}
+	return generators, tasks, nil
 }
 
-func printUsage() {
-	fmt.Fprintf(os.Stderr, "\nUsage:\n")
-	fmt.Fprintf(os.Stderr, " %s [flags]\n", os.Args[0])
-	flag.PrintDefaults()
-	fmt.Fprintf(os.Stderr, "\n")
-	os.Exit(1)
+// applyProjectConfig completes the flags with the settings of golangannotations.yaml and returns the directories
+// to generate for: flags that are set explicitly take precedence over the config
+func applyProjectConfig() ([]string, error) {
+	var config *projectConfig
+	var err error
+	if *configFile != "" {
+		config, err = loadProjectConfig(*configFile)
+	} else {
+		config, err = findProjectConfig(".")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		if *profile != "" {
+			return nil, fmt.Errorf("Profile %s requires a %s", *profile, projectConfigFilename)
+		}
+		return []string{*inputDir}, nil
+	}
+	selected, err := config.withProfile(*profile)
+	if err != nil {
+		return nil, err
+	}
+
+	explicit := map[string]bool{}
+	flag.Visit(func(f *flag.Flag) {
+		explicit[f.Name] = true
+	})
+	setDefault := func(name string, value *string, configValue string) {
+		if !explicit[name] && configValue != "" {
+			*value = configValue
+		}
+	}
+	setDefault("plugins", plugins, strings.Join(selected.Plugins, ","))
+	setDefault("template-dir", templateDir, selected.path(selected.TemplateDir))
+	setDefault("output-dir", outputDir, selected.path(selected.OutputDir))
+	setDefault("header-license-file", headerLicenseFile, selected.path(selected.Header.LicenseFile))
+	setDefault("header-marker", headerMarker, selected.Header.Marker)
+	if !explicit["header-version"] && selected.Header.Version != nil {
+		*headerVersion = *selected.Header.Version
+	}
+	*headerSource = *headerSource || selected.Header.Source
+	for name, subdir := range selected.OutputSubdirs {
+		if _, found := outputSubdirs[name]; !found {
+			outputSubdirs[name] = subdir
+		}
+	}
+	for name, pattern := range selected.FilenamePatterns {
+		if _, found := filenamePatterns[name]; !found {
+			filenamePatterns[name] = pattern
+		}
+	}
+	for name, tags := range selected.BuildTags {
+		if _, found := buildTags[name]; !found {
+			buildTags[name] = tags
+		}
+	}
+	setDefault("only", only, strings.Join(selected.Generators, ","))
+	setDefault("skip", skip, strings.Join(selected.Skip, ","))
+
+	if explicit["input-dir"] {
+		return []string{*inputDir}, nil
+	}
+	inputDirs, err := selected.inputDirs(".")
+	if err != nil {
+		return nil, err
+	}
+	if len(inputDirs) > 1 || inputDirs[0] != "." {
+		projectDir = relativeToWorkDir(selected.dir)
+	}
+	return inputDirs, nil
+}
+
+// splitNames returns the names in a comma-separated list
+func splitNames(names string) []string {
+	split := []string{}
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		if name != "" {
+			split = append(split, name)
+		}
+	}
+	return split
+}
+
+// registerPlugins adds the external generators from the comma-separated list of plugin-names
+func registerPlugins(registry *generator.Registry, names string) error {
+	for _, name := range splitNames(names) {
+		g, err := plugin.Lookup(name)
+		if err != nil {
+			return err
+		}
+		err = registry.Register(g)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkGeneratorNames(registry *generator.Registry, settings ...generatorSettings) error {
+	for _, setting := range settings {
+		for name := range setting {
+			if _, found := registry.Get(name); !found {
+				return fmt.Errorf("Unknown generator %s", name)
+			}
+		}
+	}
+	return nil
+}
+
+// getHeader composes the header of the generated files from the header-flags
+func getHeader() (generator.Header, error) {
+	header := generator.Header{
+		Marker:     *headerMarker,
+		WithSource: *headerSource,
+	}
+	if *headerVersion {
+		header.Version = toolVersion()
+	}
+	if *headerLicenseFile != "" {
+		license, err := ioutil.ReadFile(*headerLicenseFile)
+		if err != nil {
+			return header, err
+		}
+		header.License = string(license)
+	}
+	return header, nil
+}
+
+// runSummary collects the outcome of generating for all input-dirs
+type runSummary struct {
+	staleFilenames   []string
+	changedFilenames []string
+	fileCount        int
+	// otherVersions lists the files on disk that another major version of golangAnnotations generated, per version
+	otherVersions map[string][]string
+}
+
+// checkVersions notes the files on disk that were generated by another major version of golangAnnotations
+func (summary *runSummary) checkVersions(files []generator.OutputFile) error {
+	current := generator.MajorVersion(toolVersion())
+	for _, f := range files {
+		generatedVersion, err := generator.ReadGeneratedVersion(f.Filename)
+		if err != nil {
+			return err
+		}
+		if generatedVersion == "" || generator.MajorVersion(generatedVersion) == current {
+			continue
+		}
+		if summary.otherVersions == nil {
+			summary.otherVersions = map[string][]string{}
+		}
+		summary.otherVersions[generatedVersion] = append(summary.otherVersions[generatedVersion], f.Filename)
+	}
+	return nil
+}
+
+// warnAboutVersions tells which files were generated by another major version, to coordinate an upgrade
+func (summary *runSummary) warnAboutVersions() {
+	generatedVersions := []string{}
+	for generatedVersion := range summary.otherVersions {
+		generatedVersions = append(generatedVersions, generatedVersion)
+	}
+	sort.Strings(generatedVersions)
+	for _, generatedVersion := range generatedVersions {
+		filenames := summary.otherVersions[generatedVersion]
+		message := fmt.Sprintf("%d generated files were generated by golangAnnotations %s instead of %s", len(filenames),
+			generatedVersion, toolVersion())
+		logger.Warningf("%s", message)
+		for _, filename := range filenames {
+			logger.Verbosef("\t%s", filename)
+		}
+		runReport.AddDiagnostic(generator.Diagnostic{Severity: generator.SeverityWarning, Message: message})
+	}
+}
+
+// processRenderedFiles writes, verifies or diffs the files that the generators rendered for inputDir
+func processRenderedFiles(inputDir string, tasks []generator.Task, results []generator.TaskResult, summary *runSummary) {
+	generated := []generator.ManifestEntry{}
+	for idx, result := range results {
+		files := result.Files
+		summary.fileCount += len(files)
+		generated = append(generated, generator.ManifestEntries(tasks[idx].Generator.Name(), files)...)
+		start := time.Now()
+		var err error
+		switch {
+		case commandName == cleanCommand:
+		case commandName == verifyCommand:
+			var stale []string
+			stale, err = getStaleFilenames(files)
+			summary.staleFilenames = append(summary.staleFilenames, stale...)
+			if err == nil {
+				err = summary.checkVersions(files)
+			}
+			reportFiles(tasks[idx].Generator.Name(), files, stale, generator.FileStale)
+		case commandName == diffCommand:
+			var changed []string
+			changed, err = printDiffs(files)
+			reportFiles(tasks[idx].Generator.Name(), files, changed, generator.FileStale)
+			warnChangedOldVersions(tasks[idx].Generator.Name(), tasks[idx].ParsedSources.Structs, files, changed)
+		default:
+			var changed []string
+			changed, err = generator.WriteChanged(files)
+			summary.changedFilenames = append(summary.changedFilenames, changed...)
+			logWrittenFiles(files, changed)
+			reportFiles(tasks[idx].Generator.Name(), files, changed, generator.FileWritten)
+		}
+		timings.since(writePhase(), start)
+		if err != nil {
+			fail(exitFailure, "Error generating for %s: %s", inputDir, err)
+		}
+		logger.Verbosef("golangAnnotations: Generator %s rendered %d files for %s in %s", tasks[idx].Generator.Name(),
+			len(files), inputDir, result.Duration.Round(time.Millisecond))
+	}
+	switch {
+	case commandName == cleanCommand:
+		removeStaleFiles(inputDir, cleanedGenerators(tasks), generated)
+	case commandName == generateCommand:
+		err := generator.WriteManifest(inputDir, generated)
+		if err != nil {
+			fail(exitFailure, "Error writing manifest: %s", err)
+		}
+	}
+}
+
+// writePhase is the phase of the timings in which rendered files are compared to or written to disk
+func writePhase() string {
+	switch commandName {
+	case verifyCommand, diffCommand:
+		return commandName
+	}
+	return "write"
+}
+
+// logWrittenFiles tells which source every written file is based on
+func logWrittenFiles(files []generator.OutputFile, changedFilenames []string) {
+	changed := map[string]bool{}
+	for _, filename := range changedFilenames {
+		changed[filename] = true
+	}
+	for _, f := range files {
+		if changed[f.Filename] {
+			logger.Verbosef("golangAnnotations: Generated file '%s' based on source '%s'", f.Filename, f.Src)
+		}
+	}
+}
+
+// reportFiles adds the files to the run-report: the changed ones with the given status, the others as unchanged
+func reportFiles(generatorName string, files []generator.OutputFile, changedFilenames []string, status string) {
+	changed := map[string]bool{}
+	for _, filename := range changedFilenames {
+		changed[filename] = true
+	}
+	for _, f := range files {
+		if changed[f.Filename] {
+			runReport.AddFile(generatorName, f, status)
+		} else {
+			runReport.AddFile(generatorName, f, generator.FileUnchanged)
+		}
+	}
+}
+
+// report tells which files changed, or fails when verification found generated files that are not up to date
+func (summary *runSummary) report(inputDirs []string) {
+	summary.warnAboutVersions()
+	if len(summary.staleFilenames) > 0 {
+		for _, filename := range summary.staleFilenames {
+			logger.Errorf("Generated file %s is not up to date", filename)
+		}
+		command := "golangAnnotations generate"
+		if projectDir == "" {
+			command += " -input-dir " + inputDirs[0]
+		}
+		fail(exitFailure, "%d generated files are not up to date: regenerate with '%s'", len(summary.staleFilenames), command)
+	}
+	if commandName == generateCommand {
+		printChangeSummary(summary.changedFilenames, summary.fileCount)
+	}
+}
+
+// cleanedGenerators returns the generators whose stale files are removed: the selected ones when -only or -skip
+// limits the generators, and nil for all of them otherwise
+func cleanedGenerators(tasks []generator.Task) []string {
+	if *only == "" && *skip == "" {
+		return nil
+	}
+	names := []string{}
+	for _, t := range tasks {
+		names = append(names, t.Generator.Name())
+	}
+	return names
+}
+
+// removeStaleFiles removes the files of the manifest that the generators no longer generate, without writing the
+// others
+func removeStaleFiles(inputDir string, generators []string, generated []generator.ManifestEntry) {
+	removed, err := generator.RemoveStaleFiles(inputDir, generators, generated)
+	for _, entry := range removed {
+		logger.Verbosef("golangAnnotations: Removed stale file '%s'", entry.Filename)
+		runReport.AddFile(entry.Generator, generator.OutputFile{Filename: entry.Filename}, generator.FileRemoved)
+	}
+	if err != nil {
+		fail(exitFailure, "Error cleaning %s: %s", inputDir, err)
+	}
+	logger.Infof("golangAnnotations: %d stale files removed", len(removed))
+}
+
+// printChangeSummary lists the files that were actually written: the others were already up to date
+func printChangeSummary(changedFilenames []string, fileCount int) {
+	logger.Infof("golangAnnotations: %d of %d generated files changed", len(changedFilenames), fileCount)
+	for _, filename := range changedFilenames {
+		logger.Infof("\t%s", filename)
+	}
+}
+
+// getStaleFilenames returns the files on disk that differ from their freshly rendered content
+func getStaleFilenames(files []generator.OutputFile) ([]string, error) {
+	staleFilenames := []string{}
+	for _, f := range files {
+		upToDate, err := generator.IsUpToDate(f)
+		if err != nil {
+			return nil, err
+		}
+		if !upToDate {
+			staleFilenames = append(staleFilenames, f.Filename)
+		}
+	}
+	return staleFilenames, nil
+}
+
+// printDiffs shows what regeneration would change on disk, without writing anything, and returns the files that would change
+func printDiffs(files []generator.OutputFile) ([]string, error) {
+	changed := []string{}
+	for _, f := range files {
+		diff, err := generator.Diff(f)
+		if err != nil {
+			return changed, err
+		}
+		if diff != "" {
+			fmt.Fprint(os.Stdout, diff)
+			changed = append(changed, f.Filename)
+		}
+	}
+	return changed, nil
+}
+
+// warnChangedOldVersions warns when a diff changes the generated files of a rest-service that a next version
+// supersedes: the clients of the old version expect it to stay as it is
+func warnChangedOldVersions(generatorName string, structs []model.Struct, files []generator.OutputFile, changedFilenames []string) {
+	changed := map[string]bool{}
+	for _, filename := range changedFilenames {
+		changed[filename] = true
+	}
+	changedPerService := map[string]int{}
+	for _, f := range files {
+		if changed[f.Filename] && f.TypeName != "" {
+			changedPerService[f.TypeName]++
+		}
+	}
+	for _, s := range structs {
+		if changedPerService[s.Name] == 0 || !rest.IsRestService(s) {
+			continue
+		}
+		if successor, found := rest.GetRestServiceSuccessor(s, structs); found {
+			message := fmt.Sprintf("Generator %s changes %d files of version %s of %s, which version %s of %s supersedes: keep old versions compatible for their clients",
+				generatorName, changedPerService[s.Name], rest.GetRestServiceVersion(s), s.Name, rest.GetRestServiceVersion(successor), successor.Name)
+			logger.Warningf("%s", message)
+			runReport.AddDiagnostic(generator.Diagnostic{Severity: generator.SeverityWarning, Generator: generatorName, Message: message})
+		}
+	}
+}
+
+// fail logs the error, adds it to the run-report and exits
+func fail(code int, format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	logger.Errorf("%s", message)
+	runReport.AddDiagnostic(generator.Diagnostic{Severity: generator.SeverityError, Message: message})
+	exit(code)
+}
+
+// exit completes the profiles, prints the timings and writes the run-report when requested and exits
+func exit(code int) {
+	err := stopProfiling()
+	if err != nil {
+		logger.Errorf("%s", err)
+		if code == 0 {
+			code = 1
+		}
+	}
+	if showTimings != nil && *showTimings {
+		timings.print(os.Stderr, time.Since(runStart))
+	}
+	if *reportFile != "" {
+		runReport.Finish(runStart)
+		err := runReport.Write(*reportFile)
+		if err != nil {
+			logger.Errorf("Error writing report: %s", err)
+			if code == 0 {
+				code = 1
+			}
+		}
+	}
+	os.Exit(code)
+}
+
+// generatorOutputDir returns the output-root combined with the subdirectory of the generator, or "" to write next to the sources
+func generatorOutputDir(name string, inputDir string) string {
+	subdir := outputSubdirs[name]
+	if *outputDir == "" && subdir == "" {
+		return ""
+	}
+	root := *outputDir
+	if root == "" {
+		root = inputDir
+	} else if projectDir != "" {
+		// keep the inputs of the project apart below the output-dir
+		relativeDir, err := filepath.Rel(projectDir, inputDir)
+		if err == nil {
+			root = filepath.Join(root, relativeDir)
+		}
+	}
+	return filepath.Join(root, subdir)
 }
 
 func printVersion() {
-	fmt.Fprintf(os.Stderr, "\nVersion: %s\n", version)
-	os.Exit(1)
+	fmt.Fprintf(os.Stdout, "golangAnnotations %s\n", toolVersion())
+}
+
+// toolVersion returns the version set at build-time, otherwise the version of the installed module
+func toolVersion() string {
+	if version != "" {
+		return version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	return baseVersion
 }
 
-func processArgs() {
+// processArgs parses the subcommand and the flags: without a subcommand, generate is run
+func processArgs() command {
 	inputDir = flag.String("input-dir", "", "Directory to be examined")
+	plugins = flag.String("plugins", "", "Comma-separated names of external generators: plugin x is run as executable golangAnnotations-gen-x")
+	templateDir = flag.String("template-dir", "", "Directory with templates that override the default templates: <generator>/<template>.v<api-version>.tmpl")
+	dumpTemplates = flag.String("dump-templates", "", "Write the default templates of all generators into this directory and exit")
+	outputDir = flag.String("output-dir", "", "Root directory for the generated files instead of next to the sources")
+	flag.Var(outputSubdirs, "output-subdir", "Subdirectory of one generator below the output-dir: <generator>=<dir> (repeatable)")
+	flag.Var(filenamePatterns, "filename-pattern", "Filename-pattern of one generator, e.g. 'rest=gen_{{.Struct.Name | snake}}_http.go' (repeatable)")
+	flag.Var(buildTags, "build-tags", "Build-constraint for all go-files of one generator, e.g. 'rest=!appengine' (repeatable)")
+	dryRun = flag.Bool("dry-run", false, "Print unified diffs of the changes that generation would make, without writing any file")
+	verify = flag.Bool("verify", false, "Fail when generated files on disk differ from a fresh generation, without writing any file")
+	headerLicenseFile = flag.String("header-license-file", "", "File with license text to put at the top of every generated file")
+	headerMarker = flag.String("header-marker", "", "Replaces the 'do not edit'-line of generated files, e.g. 'Code generated by golangAnnotations. DO NOT EDIT.'")
+	headerVersion = flag.Bool("header-version", true, "Mention the version of golangAnnotations in the header of generated files, so that -verify can warn about files of another major version")
+	headerSource = flag.Bool("header-source", false, "Mention the generator and source in the header of generated files")
+	only = flag.String("only", "", "Comma-separated names of the only generators to run")
+	skip = flag.String("skip", "", "Comma-separated names of generators not to run")
+	jobs = flag.Int("jobs", runtime.NumCPU(), "Maximum number of packages to parse and generators to run at the same time")
+	reportFile = flag.String("report", "", "Write a json-report of the run to this file, or to stdout for '-'")
+	configFile = flag.String("config", "", "Project config to use instead of the "+projectConfigFilename+" in the working dir or one of its parents")
+	profile = flag.String("profile", "", "Profile of the project config to apply")
+	quiet = flag.Bool("quiet", false, "Only report errors")
+	verbose = flag.Bool("verbose", false, "Also report every file that is written or removed and how long every generator took")
+	debugging = flag.Bool("debug", false, "Also report the files that are parsed and the generators that are run")
+	modelFormat = flag.String("model-format", "json", "Format of the model that parse writes: json or yaml")
+	modelOutput = flag.String("model-output", "", "File that parse writes the model to instead of stdout")
+	flag.Var(filters, "filter", "Only the declarations that parse writes of a package or with an annotation: package=<name> or annotation=<name> (repeatable)")
+	listFormat = flag.String("list-format", "table", "Format of the elements that list prints: table or json")
+	graphFormat = flag.String("graph-format", "dot", "Format of the graph that graph prints: dot or mermaid")
+	topologyFormat = flag.String("topology-format", "terraform", "Format of the messaging-topology that topology prints: terraform or json")
+	topologyProvider = flag.String("topology-provider", "pubsub", "Messaging system that topology provisions: pubsub or kafka")
+	compatBaseline = flag.String("compat-baseline", "", "Model that compat compares the sources with, as written by parse, like the model of the last release")
+	listen = flag.String("listen", "localhost:8080", "Address that serve listens on")
+	watchInterval = flag.Duration("watch-interval", time.Second, "How often serve checks the sources for changes")
+	cpuProfile = flag.String("cpuprofile", "", "Write a cpu-profile to this file, for go tool pprof")
+	memProfile = flag.String("memprofile", "", "Write a memory-profile to this file on exit, for go tool pprof")
+	traceFile = flag.String("trace", "", "Write an execution-trace to this file, for go tool trace")
+	showTimings = flag.Bool("timings", false, "Print how long parsing, visiting, rendering per generator and writing took on exit")
 	help := flag.Bool("help", false, "Usage information")
 	version := flag.Bool("version", false, "Version information")
+	flag.CommandLine.Usage = func() {
+		printUsage(os.Stderr)
+	}
 
-	flag.Parse()
-
-	if help != nil && *help == true {
-		printUsage()
+	args := os.Args[1:]
+	cmd, _ := lookupCommand(generateCommand)
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		var found bool
+		cmd, found = lookupCommand(args[0])
+		if !found {
+			fail(exitUsage, "Unknown command %s: run 'golangAnnotations help' for the commands", args[0])
+		}
+		args = args[1:]
 	}
-	if version != nil && *version == true {
+	// arguments of the command may precede and follow the flags
+	for {
+		flag.CommandLine.Parse(args)
+		if flag.NArg() == 0 {
+			break
+		}
+		commandArgs = append(commandArgs, flag.Arg(0))
+		args = flag.Args()[1:]
+	}
+	commandName = cmd.name
+
+	switch {
+	case *help:
+		printUsage(os.Stdout)
+		os.Exit(exitOK)
+	case *version:
 		printVersion()
+		os.Exit(exitOK)
+	case cmd.name == helpCommand || cmd.name == versionCommand:
+		return cmd
 	}
-	if inputDir == nil || *inputDir == "" {
-		printUsage()
+
+	level, err := getLogLevel()
+	if err != nil {
+		fail(exitUsage, "Error in flags: %s", err)
+	}
+	logger = logging.New(os.Stderr, level)
+
+	// the flags that preceded the subcommands
+	if cmd.name == generateCommand && *dryRun {
+		commandName = diffCommand
+	}
+	if cmd.name == generateCommand && *verify {
+		commandName = verifyCommand
+	}
+	if cmd.name == newCommand || cmd.name == lspCommand {
+		return cmd
+	}
+	if (inputDir == nil || *inputDir == "") && *dumpTemplates == "" && !hasProjectConfig() {
+		fail(exitUsage, "Missing -input-dir and no %s found: run 'golangAnnotations help %s' for the flags", projectConfigFilename, cmd.name)
+	}
+	return cmd
+}
+
+// getLogLevel returns the level that the -quiet, -verbose and -debug flags select
+func getLogLevel() (logging.Level, error) {
+	switch {
+	case *quiet && (*verbose || *debugging):
+		return logging.Normal, fmt.Errorf("Flag -quiet cannot be combined with -verbose or -debug")
+	case *quiet:
+		return logging.Quiet, nil
+	case *debugging:
+		return logging.Debug, nil
+	case *verbose:
+		return logging.Verbose, nil
+	}
+	return logging.Normal, nil
+}
+
+// hasProjectConfig tells if the input-dirs can be taken from a project config instead of the flags
+func hasProjectConfig() bool {
+	if *configFile != "" {
+		return true
 	}
+	config, err := findProjectConfig(".")
+	return err != nil || config != nil
 }
@@ -2,6 +2,7 @@ package model
 
 import (
 	"fmt"
+	"path/filepath"
 	"regexp"
 	"strings"
 )
@@ -18,6 +19,24 @@ func (f Field) SplitTypeName() (string, string) {
 	return "", ""
 }
 
+// ImportPaths returns the import-paths of the packages that the type of the field refers to
+func (f Field) ImportPaths() []string {
+	if len(f.PackageNames) > 0 {
+		return f.PackageNames
+	}
+	if f.PackageName != "" {
+		return []string{f.PackageName}
+	}
+	return nil
+}
+
+// EmbeddedName returns the name by which the struct refers to an embedded field: its type without package and
+// pointer, like Closer for io.Closer
+func (f Field) EmbeddedName() string {
+	_, name := f.SplitTypeName()
+	return name
+}
+
 func (f Field) EmptyInstance() string {
 	if f.IsPointer() {
 		return fmt.Sprintf("&%s{}", f.DereferencedTypeName())
@@ -126,3 +145,96 @@ func (f Field) GetTagMap() map[string]string {
 	}
 	return tagMap
 }
+
+// EmbeddedInterfaces returns the interfaces that s embeds, e.g. for generating methods that forward to them
+func (s Struct) EmbeddedInterfaces() []Field {
+	interfaces := []Field{}
+	for _, f := range s.Fields {
+		if f.IsEmbedded && f.IsInterface {
+			interfaces = append(interfaces, f)
+		}
+	}
+	return interfaces
+}
+
+// MethodsByName returns the operations that have s as receiver by their name, e.g. for templates that check if a
+// struct has a method: {{if index .MethodsByName "Validate"}}
+func (s Struct) MethodsByName() map[string]*Operation {
+	methods := make(map[string]*Operation, len(s.Operations))
+	for _, o := range s.Operations {
+		methods[o.Name] = o
+	}
+	return methods
+}
+
+// EmbedOperations adds every operation with a receiver to the Operations of its struct: the struct with the name
+// of the receiver in the same package, which is the package-name in the same directory. When the directory has no
+// such struct, like in a model of sources with relative and absolute filenames, it is the only struct of that name
+// in a package of that name. The structs refer to the operations in p.Operations. An operation that a struct has
+// already, by name, is replaced instead of added, so that embedding again, like after combining models, adds no
+// duplicates.
+func (p ParsedSources) EmbedOperations() {
+	receivers := p.receivers()
+	for idx := range p.Operations {
+		o := &p.Operations[idx]
+		if s, found := receivers.find(*o); found {
+			s.embedOperation(o)
+		}
+	}
+}
+
+// UnresolvedOperations returns the operations with a receiver that is no struct of the model, like a struct in a
+// file that was not parsed: generators that handle the methods of structs do not see these
+func (p ParsedSources) UnresolvedOperations() []Operation {
+	receivers := p.receivers()
+	unresolved := []Operation{}
+	for _, o := range p.Operations {
+		if _, found := receivers.find(o); o.RelatedStruct != nil && !found {
+			unresolved = append(unresolved, o)
+		}
+	}
+	return unresolved
+}
+
+// receiverIndex finds the struct of a method by its package-qualified name
+type receiverIndex struct {
+	byDir     map[string]*Struct
+	byPackage map[string][]*Struct
+}
+
+func (p ParsedSources) receivers() receiverIndex {
+	receivers := receiverIndex{
+		byDir:     make(map[string]*Struct, len(p.Structs)),
+		byPackage: make(map[string][]*Struct, len(p.Structs)),
+	}
+	for idx := range p.Structs {
+		s := &p.Structs[idx]
+		receivers.byDir[filepath.Dir(s.Filename)+":"+s.PackageName+"."+s.Name] = s
+		receivers.byPackage[s.PackageName+"."+s.Name] = append(receivers.byPackage[s.PackageName+"."+s.Name], s)
+	}
+	return receivers
+}
+
+func (r receiverIndex) find(o Operation) (*Struct, bool) {
+	if o.RelatedStruct == nil {
+		return nil, false
+	}
+	name := o.PackageName + "." + o.RelatedStruct.DereferencedTypeName()
+	if s, found := r.byDir[filepath.Dir(o.Filename)+":"+name]; found {
+		return s, true
+	}
+	if candidates := r.byPackage[name]; len(candidates) == 1 {
+		return candidates[0], true
+	}
+	return nil, false
+}
+
+func (s *Struct) embedOperation(o *Operation) {
+	for idx, existing := range s.Operations {
+		if existing.Name == o.Name {
+			s.Operations[idx] = o
+			return
+		}
+	}
+	s.Operations = append(s.Operations, o)
+}
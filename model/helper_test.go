@@ -0,0 +1,81 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmbedOperations(t *testing.T) {
+	parsed := ParsedSources{
+		Structs: []Struct{
+			{PackageName: "tour", Filename: "a/tour.go", Name: "Tour"},
+			{PackageName: "tour", Filename: "b/tour.go", Name: "Tour"},
+		},
+		Operations: []Operation{
+			{PackageName: "tour", Filename: "a/start.go", Name: "Start", RelatedStruct: &Field{TypeName: "*Tour"}},
+			{PackageName: "tour", Filename: "b/stop.go", Name: "Stop", RelatedStruct: &Field{TypeName: "Tour"}},
+			{PackageName: "tour_test", Filename: "a/tour_test.go", Name: "Helper", RelatedStruct: &Field{TypeName: "*Tour"}},
+			{PackageName: "tour", Filename: "a/start.go", Name: "NewTour"},
+		},
+	}
+	parsed.EmbedOperations()
+	parsed.EmbedOperations()
+
+	assert.Len(t, parsed.Structs[0].Operations, 1)
+	assert.True(t, parsed.Structs[0].Operations[0] == &parsed.Operations[0])
+	assert.Len(t, parsed.Structs[1].Operations, 1)
+	assert.True(t, parsed.Structs[1].Operations[0] == &parsed.Operations[1])
+
+	methods := parsed.Structs[0].MethodsByName()
+	assert.Equal(t, "Start", methods["Start"].Name)
+	assert.Nil(t, methods["Stop"])
+}
+
+func TestEmbedOperationsByPackageQualifiedName(t *testing.T) {
+	parsed := ParsedSources{
+		Structs: []Struct{
+			{PackageName: "tour", Filename: "/src/tour/tour.go", Name: "Tour"},
+		},
+		Operations: []Operation{
+			{PackageName: "tour", Filename: "tour/start.go", Name: "Start", RelatedStruct: &Field{TypeName: "*Tour"}},
+			{PackageName: "stop", Filename: "stop/stop.go", Name: "Stop", RelatedStruct: &Field{TypeName: "*Tour"}},
+		},
+	}
+	parsed.EmbedOperations()
+
+	assert.Equal(t, []*Operation{&parsed.Operations[0]}, parsed.Structs[0].Operations)
+	unresolved := parsed.UnresolvedOperations()
+	assert.Len(t, unresolved, 1)
+	assert.Equal(t, "Stop", unresolved[0].Name)
+}
+
+func TestFieldHelpersOfNestedTypes(t *testing.T) {
+	matrix := Field{TypeName: "[][]*pkg.Thing"}
+	assert.True(t, matrix.IsSlice())
+	assert.False(t, matrix.IsPointer())
+	assert.Equal(t, "[]*pkg.Thing", matrix.SliceElementTypeName())
+	assert.Equal(t, "[][]*pkg.Thing{}", matrix.EmptyInstance())
+
+	list := Field{TypeName: "*[]Thing"}
+	assert.True(t, list.IsPointer())
+	assert.False(t, list.IsSlice())
+	assert.Equal(t, "[]Thing", list.DereferencedTypeName())
+	assert.Equal(t, "&[]Thing{}", list.EmptyInstance())
+}
+
+func TestEmbeddedInterfaces(t *testing.T) {
+	s := Struct{
+		Name: "Store",
+		Fields: []Field{
+			{PackageName: "io", TypeName: "io.Closer", IsInterface: true, IsEmbedded: true},
+			{TypeName: "*Base", IsEmbedded: true},
+			{Name: "Repo", TypeName: "Repository", IsInterface: true},
+		},
+	}
+	interfaces := s.EmbeddedInterfaces()
+	assert.Len(t, interfaces, 1)
+	assert.Equal(t, "io", interfaces[0].PackageName)
+	assert.Equal(t, "Closer", interfaces[0].EmbeddedName())
+	assert.Equal(t, "Base", s.Fields[1].EmbeddedName())
+}
@@ -15,6 +15,7 @@ type ParsedSources struct {
 type Operation struct {
 	PackageName   string   `json:"packageName,omitempty"`
 	Filename      string   `json:"filename,omitempty"`
+	Line          int      `json:"line,omitempty"`
 	DocLines      []string `json:"docLines,omitempty"`
 	RelatedStruct *Field   `json:"relatedStruct,omitempty"` // optional
 	Name          string   `json:"name"`
@@ -27,6 +28,7 @@ type Operation struct {
 type Struct struct {
 	PackageName  string       `json:"packageName"`
 	Filename     string       `json:"filename"`
+	Line         int          `json:"line,omitempty"`
 	DocLines     []string     `json:"docLines,omitempty"`
 	Name         string       `json:"name"`
 	Fields       []Field      `json:"fields,omitempty"`
@@ -38,6 +40,7 @@ type Struct struct {
 type Interface struct {
 	PackageName  string      `json:"packageName"`
 	Filename     string      `json:"filename"`
+	Line         int         `json:"line,omitempty"`
 	DocLines     []string    `json:"docLines,omitempty"`
 	Name         string      `json:"name"`
 	Methods      []Operation `json:"methods,omitempty"`
@@ -47,17 +50,22 @@ type Interface struct {
 // @JsonStruct()
 type Field struct {
 	PackageName  string   `json:"packageName,omitempty"`
+	PackageNames []string `json:"packageNames,omitempty"` // when the type refers to more packages, like map[mypkg.ID]*other.Order
 	DocLines     []string `json:"docLines,omitempty"`
 	Name         string   `json:"name,omitempty"`
 	TypeName     string   `json:"typeName,omitempty"`
 	Tag          string   `json:"tag,omitempty"`
 	CommentLines []string `json:"commentLines,omitempty"`
+	Line         int      `json:"line,omitempty"`
+	IsInterface  bool     `json:"isInterface,omitempty"` // like error, io.Reader or an interface of the parsed sources
+	IsEmbedded   bool     `json:"isEmbedded,omitempty"`  // a field without name, like io.Closer in struct { io.Closer }
 }
 
 // @JsonStruct()
 type Typedef struct {
 	PackageName string   `json:"packageName"`
 	Filename    string   `json:"filename"`
+	Line        int      `json:"line,omitempty"`
 	DocLines    []string `json:"docLines,omitempty"`
 	Name        string   `json:"name"`
 	Type        string   `json:"type,omitempty"`
@@ -67,6 +75,7 @@ type Typedef struct {
 type Enum struct {
 	PackageName  string        `json:"packageName"`
 	Filename     string        `json:"filename"`
+	Line         int           `json:"line,omitempty"`
 	DocLines     []string      `json:"docLines,omitempty"`
 	Name         string        `json:"name,omitempty"`
 	EnumLiterals []EnumLiteral `json:"enumLiterals,omitempty"`
@@ -75,6 +84,9 @@ type Enum struct {
 
 // @JsonStruct()
 type EnumLiteral struct {
-	Name  string `json:"name"`
-	Value string `json:"value,omitempty"`
+	DocLines     []string `json:"docLines,omitempty"`
+	Name         string   `json:"name"`
+	Value        string   `json:"value,omitempty"`
+	CommentLines []string `json:"commentLines,omitempty"`
+	Ordinal      int      `json:"ordinal"` // position in the const-block, which is the value of iota
 }
@@ -15,14 +15,14 @@ func Parse(filename string) (ParsedSources, error) {
 	if filename != "" {
 		fp, err := os.Open(filename)
 		if err != nil {
-			return ParsedSources{}, fmt.Errorf("Error opening file %s: %s", filename, err)
+			return ParsedSources{}, fmt.Errorf("Error opening file %s: %w", filename, err)
 		}
 		reader = bufio.NewReader(fp)
 	}
 
 	err := json.NewDecoder(reader).Decode(&parsedSources)
 	if err != nil {
-		return ParsedSources{}, fmt.Errorf("Error decoding parsed-sources from stdin: %s", err)
+		return ParsedSources{}, fmt.Errorf("Error decoding parsed-sources from stdin: %w", err)
 	}
 	return parsedSources, nil
 }
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+)
+
+// Kinds of skeletons that the new command creates
+const (
+	restServiceSkeleton = "rest-service"
+	aggregateSkeleton   = "aggregate"
+)
+
+type skeletonData struct {
+	PackageName string
+	// WithGenerate adds the go:generate comment, for directories that have none yet
+	WithGenerate bool
+	// Name is the service or aggregate, Resource the struct that a rest-service serves
+	Name     string
+	Resource string
+	Receiver string
+	Path     string
+}
+
+// skeletons are the templates per kind, with the name of the file they are created in
+var skeletons = map[string]struct {
+	filename string
+	template string
+}{
+	restServiceSkeleton: {filename: "{{.Name | lowerFirst}}.go", template: restServiceSkeletonTemplate},
+	aggregateSkeleton:   {filename: "{{.Name | lowerFirst}}Events.go", template: aggregateSkeletonTemplate},
+}
+
+const restServiceSkeletonTemplate = `package {{.PackageName}}
+
+import "context"
+{{if .WithGenerate}}
+//go:generate golangAnnotations -input-dir .
+{{end}}
+// @JsonStruct()
+type {{.Resource}} struct {
+	UID  string ` + "`" + `json:"uid"` + "`" + `
+	Name string ` + "`" + `json:"name"` + "`" + `
+}
+
+// {{.Name}} serves {{.Resource}}: the generated gen_http{{.Name}}.go registers its operations on a router
+// @RestService( path = "/api" )
+type {{.Name}} struct {
+}
+
+// @RestOperation( method = "GET", path = "{{.Path}}/{uid}", format = "JSON" )
+func ({{.Receiver}} *{{.Name}}) get{{.Resource}}(c context.Context, uid string) (*{{.Resource}}, error) {
+	return &{{.Resource}}{UID: uid}, nil
+}
+
+// @RestOperation( method = "POST", path = "{{.Path}}", format = "JSON" )
+func ({{.Receiver}} *{{.Name}}) create{{.Resource}}(c context.Context, {{.Resource | lowerFirst}} {{.Resource}}) (*{{.Resource}}, error) {
+	return &{{.Resource | lowerFirst}}, nil
+}
+
+// @RestOperation( method = "DELETE", path = "{{.Path}}/{uid}", format = "JSON" )
+func ({{.Receiver}} *{{.Name}}) delete{{.Resource}}(c context.Context, uid string) error {
+	return nil
+}
+`
+
+const aggregateSkeletonTemplate = `package {{.PackageName}}
+{{if .WithGenerate}}
+//go:generate golangAnnotations -input-dir .
+{{end}}
+// The events of aggregate {{.Name}}: the generated {{.Name}}Aggregate interface requires an Apply-method for each of them
+
+// @Event( aggregate = "{{.Name}}", isrootevent = "true" )
+type {{.Name}}Created struct {
+	{{.Name}}UID string ` + "`" + `json:"{{.Name | lowerFirst}}UID"` + "`" + `
+	Name string ` + "`" + `json:"name"` + "`" + `
+}
+
+// @Event( aggregate = "{{.Name}}" )
+type {{.Name}}Updated struct {
+	{{.Name}}UID string ` + "`" + `json:"{{.Name | lowerFirst}}UID"` + "`" + `
+	Name string ` + "`" + `json:"name"` + "`" + `
+}
+
+// @Event( aggregate = "{{.Name}}" )
+type {{.Name}}Deleted struct {
+	{{.Name}}UID string ` + "`" + `json:"{{.Name | lowerFirst}}UID"` + "`" + `
+}
+`
+
+// runNew creates the skeleton that the command arguments ask for
+func runNew() {
+	if len(commandArgs) != 2 {
+		fail(exitUsage, "Expected new <%s> <Name>", strings.Join(skeletonKinds(), "|"))
+	}
+	dir := *inputDir
+	if dir == "" {
+		dir = "."
+	}
+	filename, err := createSkeleton(dir, commandArgs[0], commandArgs[1])
+	if err != nil {
+		fail(exitFailure, "Error creating %s %s: %s", commandArgs[0], commandArgs[1], err)
+	}
+	logger.Infof("golangAnnotations: Created %s: run 'golangAnnotations generate -input-dir %s' to generate its code", filename, dir)
+}
+
+func skeletonKinds() []string {
+	kinds := []string{}
+	for kind := range skeletons {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// createSkeleton writes the annotated skeleton of kind for name in dir and returns the name of the file. Existing
+// files are never overwritten.
+func createSkeleton(dir string, kind string, name string) (string, error) {
+	skeleton, found := skeletons[kind]
+	if !found {
+		return "", fmt.Errorf("Unknown kind %s: expected one of %s", kind, strings.Join(skeletonKinds(), ", "))
+	}
+	if !isIdentifier(name) {
+		return "", fmt.Errorf("Name %s is no go identifier", name)
+	}
+	name = upperFirst(name)
+	packageName, withGenerate, err := inspectPackage(dir)
+	if err != nil {
+		return "", err
+	}
+	data := skeletonData{
+		PackageName:  packageName,
+		WithGenerate: withGenerate,
+		Name:         name,
+	}
+	if kind == restServiceSkeleton {
+		data.Resource = strings.TrimSuffix(name, "Service")
+		if data.Resource == "" {
+			data.Resource = name
+		}
+		if !strings.HasSuffix(name, "Service") {
+			data.Name = name + "Service"
+		}
+		data.Receiver = strings.ToLower(data.Name[:1]) + "s"
+		data.Path = "/" + strings.ToLower(data.Resource)
+	}
+
+	funcs := template.FuncMap{"lowerFirst": lowerFirst}
+	var filename bytes.Buffer
+	err = template.Must(template.New("filename").Funcs(funcs).Parse(skeleton.filename)).Execute(&filename, data)
+	if err != nil {
+		return "", err
+	}
+	var content bytes.Buffer
+	err = template.Must(template.New(kind).Funcs(funcs).Parse(skeleton.template)).Execute(&content, data)
+	if err != nil {
+		return "", err
+	}
+	formatted, err := generator.FormatGoSource(filename.String(), content.Bytes())
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, filename.String())
+	if _, err := os.Stat(path); err == nil {
+		return "", fmt.Errorf("File %s already exists", path)
+	}
+	err = os.MkdirAll(dir, 0777)
+	if err != nil {
+		return "", err
+	}
+	return path, ioutil.WriteFile(path, formatted, 0644)
+}
+
+// inspectPackage returns the package of the sources in dir, or one named after dir when it has none, and whether dir
+// still needs a go:generate comment for golangAnnotations
+func inspectPackage(dir string) (string, bool, error) {
+	filenames, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return "", false, err
+	}
+	packageName := ""
+	withGenerate := true
+	for _, filename := range filenames {
+		base := filepath.Base(filename)
+		if strings.HasSuffix(base, "_test.go") || strings.HasPrefix(base, generator.GenfilePrefix) {
+			continue
+		}
+		data, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return "", false, err
+		}
+		if strings.Contains(string(data), "go:generate golangAnnotations") {
+			withGenerate = false
+		}
+		if packageName == "" {
+			file, err := parser.ParseFile(token.NewFileSet(), filename, data, parser.PackageClauseOnly)
+			if err != nil {
+				return "", false, err
+			}
+			packageName = file.Name.Name
+		}
+	}
+	if packageName != "" {
+		return packageName, withGenerate, nil
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false, err
+	}
+	packageName = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, filepath.Base(absDir))
+	if packageName == "" || !unicode.IsLetter(rune(packageName[0])) {
+		return "", false, fmt.Errorf("Cannot derive a package name from directory %s", dir)
+	}
+	return packageName, withGenerate, nil
+}
+
+func isIdentifier(name string) bool {
+	if name == "" || token.Lookup(name).IsKeyword() {
+		return false
+	}
+	for idx, r := range name {
+		if !unicode.IsLetter(r) && r != '_' && (idx == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return true
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
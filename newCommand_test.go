@@ -0,0 +1,66 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/generator/event"
+	"github.com/MarcGrol/golangAnnotations/generator/rest"
+	"github.com/MarcGrol/golangAnnotations/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateRestServiceSkeleton(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "tour-app")
+	filename, err := createSkeleton(dir, restServiceSkeleton, "tour")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "tourService.go"), filename)
+
+	data, err := ioutil.ReadFile(filename)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "package tourapp\n")
+	assert.Contains(t, string(data), "//go:generate golangAnnotations -input-dir .\n")
+	assert.Contains(t, string(data), "// @RestService( path = \"/api\" )\ntype TourService struct {\n}")
+	assert.Contains(t, string(data), "func (ts *TourService) getTour(c context.Context, uid string) (*Tour, error) {")
+
+	parsedSources, err := parser.New().ParseSourceDir(dir, "^.*.go$", excludeMatchPattern)
+	assert.NoError(t, err)
+	files, err := generator.Render(rest.NewGenerator(), parsedSources, generator.Config{InputDir: dir})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, files)
+
+	_, err = createSkeleton(dir, restServiceSkeleton, "TourService")
+	assert.EqualError(t, err, "File "+filename+" already exists")
+}
+
+func TestCreateAggregateSkeletonInExistingPackage(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "doc.go"), []byte("package tour\n\n//go:generate golangAnnotations -input-dir .\n"), 0644))
+
+	filename, err := createSkeleton(dir, aggregateSkeleton, "Tour")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "tourEvents.go"), filename)
+
+	data, err := ioutil.ReadFile(filename)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "package tour\n")
+	assert.NotContains(t, string(data), "go:generate")
+	assert.Contains(t, string(data), "// @Event( aggregate = \"Tour\", isrootevent = \"true\" )\ntype TourCreated struct {")
+
+	parsedSources, err := parser.New().ParseSourceDir(dir, "^.*.go$", excludeMatchPattern)
+	assert.NoError(t, err)
+	assert.Len(t, parsedSources.Structs, 3)
+	files, err := generator.Render(event.NewGenerator(), parsedSources, generator.Config{InputDir: dir})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, files)
+}
+
+func TestCreateSkeletonRejectsInvalidArguments(t *testing.T) {
+	_, err := createSkeleton(t.TempDir(), "repository", "Tour")
+	assert.EqualError(t, err, "Unknown kind repository: expected one of aggregate, rest-service")
+
+	_, err = createSkeleton(t.TempDir(), aggregateSkeleton, "my-tour")
+	assert.EqualError(t, err, "Name my-tour is no go identifier")
+}
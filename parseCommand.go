@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/MarcGrol/golangAnnotations/lint"
+	"github.com/MarcGrol/golangAnnotations/model"
+	"gopkg.in/yaml.v3"
+)
+
+// Kinds of -filter of the parse command
+const (
+	packageFilter    = "package"
+	annotationFilter = "annotation"
+)
+
+// modelFilters is the repeatable -filter flag of the form <kind>=<value>: the model keeps the declarations that
+// match one of the values of every kind
+type modelFilters map[string][]string
+
+func (f modelFilters) String() string {
+	filters := []string{}
+	for kind, values := range f {
+		for _, value := range values {
+			filters = append(filters, kind+"="+value)
+		}
+	}
+	return strings.Join(filters, ",")
+}
+
+func (f modelFilters) Set(filter string) error {
+	parts := strings.SplitN(filter, "=", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return fmt.Errorf("Expected <kind>=<value>, got '%s'", filter)
+	}
+	switch parts[0] {
+	case packageFilter, annotationFilter:
+	default:
+		return fmt.Errorf("Unknown filter %s: expected %s or %s", parts[0], packageFilter, annotationFilter)
+	}
+	f[parts[0]] = append(f[parts[0]], strings.TrimPrefix(parts[1], "@"))
+	return nil
+}
+
+// runParse writes the model of the sources of all input-dirs
+func runParse() {
+	inputDirs, err := applyProjectConfig()
+	if err != nil {
+		fail(exitUsage, "Error in config: %s", err)
+	}
+
+	c, stop := interruptibleContext()
+	defer stop()
+
+	merged := mergeParsed(parseInputDirs(c, inputDirs))
+	data, err := marshalModel(filters.apply(merged), *modelFormat)
+	if err != nil {
+		fail(exitUsage, "Error writing model: %s", err)
+	}
+	if *modelOutput == "" || *modelOutput == "-" {
+		os.Stdout.Write(data)
+		return
+	}
+	err = ioutil.WriteFile(*modelOutput, data, 0644)
+	if err != nil {
+		fail(exitFailure, "Error writing model to %s: %s", *modelOutput, err)
+	}
+}
+
+// mergeParsed combines the declarations of all input-dirs into a single model
+func mergeParsed(parsedSources []model.ParsedSources) model.ParsedSources {
+	merged := model.ParsedSources{}
+	for _, parsed := range parsedSources {
+		merged.Structs = append(merged.Structs, parsed.Structs...)
+		merged.Operations = append(merged.Operations, parsed.Operations...)
+		merged.Interfaces = append(merged.Interfaces, parsed.Interfaces...)
+		merged.Typedefs = append(merged.Typedefs, parsed.Typedefs...)
+		merged.Enums = append(merged.Enums, parsed.Enums...)
+	}
+	// refer to the operations of the merged model
+	merged.EmbedOperations()
+	return merged
+}
+
+// apply returns the declarations of parsed that match the filters. A struct or interface also matches the
+// annotation-filter when one of its fields or methods has the annotation.
+func (f modelFilters) apply(parsed model.ParsedSources) model.ParsedSources {
+	filtered := model.ParsedSources{}
+	for _, s := range parsed.Structs {
+		docLines := [][]string{s.DocLines}
+		for _, field := range s.Fields {
+			docLines = append(docLines, field.DocLines)
+		}
+		for _, o := range s.Operations {
+			docLines = append(docLines, o.DocLines)
+		}
+		if f.matches(s.PackageName, docLines...) {
+			filtered.Structs = append(filtered.Structs, s)
+		}
+	}
+	for _, o := range parsed.Operations {
+		if f.matches(o.PackageName, o.DocLines) {
+			filtered.Operations = append(filtered.Operations, o)
+		}
+	}
+	for _, i := range parsed.Interfaces {
+		docLines := [][]string{i.DocLines}
+		for _, m := range i.Methods {
+			docLines = append(docLines, m.DocLines)
+		}
+		if f.matches(i.PackageName, docLines...) {
+			filtered.Interfaces = append(filtered.Interfaces, i)
+		}
+	}
+	for _, t := range parsed.Typedefs {
+		if f.matches(t.PackageName, t.DocLines) {
+			filtered.Typedefs = append(filtered.Typedefs, t)
+		}
+	}
+	for _, e := range parsed.Enums {
+		if f.matches(e.PackageName, e.DocLines) {
+			filtered.Enums = append(filtered.Enums, e)
+		}
+	}
+	return filtered
+}
+
+func (f modelFilters) matches(packageName string, docLines ...[]string) bool {
+	if packages, found := f[packageFilter]; found && !contains(packages, packageName) {
+		return false
+	}
+	annotations, found := f[annotationFilter]
+	if !found {
+		return true
+	}
+	for _, lines := range docLines {
+		for _, name := range lint.AnnotationNames(lines) {
+			if contains(annotations, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// marshalModel returns the model as json or yaml. The yaml has the same keys, in the same order, as the json.
+func marshalModel(parsed model.ParsedSources, format string) ([]byte, error) {
+	data, err := json.MarshalIndent(parsed, "", "\t")
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case "json":
+		return append(data, '\n'), nil
+	case "yaml":
+		// json is yaml: decoding it keeps the order of the keys
+		var node yaml.Node
+		err = yaml.Unmarshal(data, &node)
+		if err != nil {
+			return nil, err
+		}
+		resetStyle(&node)
+		var buf bytes.Buffer
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		err = encoder.Encode(&node)
+		if err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	return nil, fmt.Errorf("Unknown format %s: expected json or yaml", format)
+}
+
+// resetStyle replaces the flow-style of decoded json by the block-style of yaml
+func resetStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetStyle(child)
+	}
+}
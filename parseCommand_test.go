@@ -0,0 +1,62 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func filterSources() model.ParsedSources {
+	return model.ParsedSources{
+		Structs: []model.Struct{
+			{PackageName: "tour", Name: "TourService", DocLines: []string{`// @RestService( path = "/api" )`}},
+			{PackageName: "tour", Name: "Tour", Fields: []model.Field{{Name: "ID", DocLines: []string{`// @Id()`}}}},
+			{PackageName: "other", Name: "OtherService", DocLines: []string{`// @RestService( path = "/other" )`}},
+		},
+		Enums: []model.Enum{{PackageName: "tour", Name: "Status"}},
+	}
+}
+
+func TestModelFiltersSet(t *testing.T) {
+	filters := modelFilters{}
+	assert.NoError(t, filters.Set("package=tour"))
+	assert.NoError(t, filters.Set("annotation=@RestService"))
+	assert.NoError(t, filters.Set("annotation=Id"))
+	assert.Equal(t, modelFilters{"package": {"tour"}, "annotation": {"RestService", "Id"}}, filters)
+
+	assert.Error(t, filters.Set("kind=tour"))
+	assert.Error(t, filters.Set("package"))
+}
+
+func TestModelFiltersApply(t *testing.T) {
+	assert.Equal(t, filterSources(), modelFilters{}.apply(filterSources()))
+
+	filtered := modelFilters{"package": {"tour"}}.apply(filterSources())
+	assert.Len(t, filtered.Structs, 2)
+	assert.Len(t, filtered.Enums, 1)
+
+	filtered = modelFilters{"annotation": {"RestService"}}.apply(filterSources())
+	assert.Equal(t, "TourService", filtered.Structs[0].Name)
+	assert.Equal(t, "OtherService", filtered.Structs[1].Name)
+	assert.Empty(t, filtered.Enums)
+
+	filtered = modelFilters{"package": {"tour"}, "annotation": {"RestService", "Id"}}.apply(filterSources())
+	assert.Len(t, filtered.Structs, 2)
+	assert.Equal(t, "Tour", filtered.Structs[1].Name)
+}
+
+func TestMarshalModel(t *testing.T) {
+	parsed := model.ParsedSources{Enums: []model.Enum{{PackageName: "tour", Filename: "tour.go", Name: "Status"}}}
+
+	data, err := marshalModel(parsed, "yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, "enums:\n- packageName: tour\n  filename: tour.go\n  name: Status\n", string(data))
+
+	data, err = marshalModel(parsed, "json")
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"packageName": "tour"`)
+
+	_, err = marshalModel(parsed, "xml")
+	assert.Error(t, err)
+}
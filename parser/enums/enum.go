@@ -5,8 +5,9 @@ type ColorType int
 
 const (
 	Red ColorType = iota
+	// Green is the color of grass
 	Green
-	Blue
+	Blue // the color of the sky
 )
 
 // @Enum()
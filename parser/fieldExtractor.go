@@ -1,38 +1,44 @@
 package parser
 
 import (
-	"fmt"
 	"go/ast"
-	"log"
-	"reflect"
-	"strings"
 
 	"github.com/MarcGrol/golangAnnotations/model"
 )
 
 func extractFieldList(fieldList *ast.FieldList, imports map[string]string) []model.Field {
-	mFields := make([]model.Field, 0)
-	if fieldList != nil {
-		for _, field := range fieldList.List {
-			mFields = append(mFields, extractFields(field, imports)...)
-		}
+	if fieldList == nil || len(fieldList.List) == 0 {
+		return nil
+	}
+	mFields := make([]model.Field, 0, fieldList.NumFields())
+	for _, field := range fieldList.List {
+		mFields = appendFields(mFields, field, imports)
 	}
 	return mFields
 }
 
-func extractFields(field *ast.Field, imports map[string]string) []model.Field {
-	mFields := make([]model.Field, 0)
-	if field != nil {
-		if mField := extractField(field, imports); mField != nil {
-			if len(field.Names) == 0 {
-				mFields = append(mFields, *mField)
-			} else {
-				// A single field can refer to multiple: example: x,y int -> x int, y int
-				for _, name := range field.Names {
-					mField.Name = name.Name
-					mFields = append(mFields, *mField)
-				}
-			}
+// extractStructFields extracts the fields of a struct: fields without name are embedded, like io.Closer in
+// struct { io.Closer }
+func extractStructFields(fieldList *ast.FieldList, imports map[string]string) []model.Field {
+	mFields := extractFieldList(fieldList, imports)
+	for idx := range mFields {
+		mFields[idx].IsEmbedded = mFields[idx].Name == ""
+	}
+	return mFields
+}
+
+// appendFields appends the fields that a single field declares: example: x,y int -> x int, y int
+func appendFields(mFields []model.Field, field *ast.Field, imports map[string]string) []model.Field {
+	if field == nil {
+		return mFields
+	}
+	if mField := extractField(field, imports); mField != nil {
+		if len(field.Names) == 0 {
+			return append(mFields, *mField)
+		}
+		for _, name := range field.Names {
+			mField.Name = name.Name
+			mFields = append(mFields, *mField)
 		}
 	}
 	return mFields
@@ -42,169 +48,206 @@ func extractField(field *ast.Field, imports map[string]string) *model.Field {
 	if fieldType := processExpression(field.Type, imports); fieldType != nil {
 		return &model.Field{
 			PackageName:  fieldType.PackageName,
+			PackageNames: fieldType.PackageNames,
 			DocLines:     extractComments(field.Doc),
 			Name:         fieldType.Name,
 			TypeName:     fieldType.TypeName,
 			Tag:          extractTag(field.Tag),
 			CommentLines: extractComments(field.Comment),
+			IsInterface:  isInterfaceType(field.Type, imports),
 		}
 	}
 	return nil
 }
 
-func processExpression(expr ast.Expr, imports map[string]string) *Expression {
-
-	if mExpr := processEllipsis(expr, imports); mExpr != nil {
-		return mExpr
-	}
-	if mExpr := processArrayType(expr, imports); mExpr != nil {
-		return mExpr
-	}
-	if mExpr := processStarExpr(expr, imports); mExpr != nil {
-		return mExpr
-	}
-	if mExpr := processIdent(expr, imports); mExpr != nil {
-		return mExpr
-	}
-	if mExpr := processSelectorExpr(expr, imports); mExpr != nil {
-		return mExpr
-	}
-	if mExpr := processMapType(expr, imports); mExpr != nil {
-		return mExpr
-	}
-	if mExpr := processFuncType(expr, imports); mExpr != nil {
-		return mExpr
-	}
-	if mExpr := processInterfaceType(expr, imports); mExpr != nil {
-		return mExpr
-	}
-
-	log.Printf("*** Could not understand expression %+v", reflect.TypeOf(expr))
-	return nil
+// wellKnownInterfaces are the interfaces of the standard library that fields commonly have, by import-path and name:
+// interfaces of the parsed sources are recognized after visiting them
+var wellKnownInterfaces = map[string]bool{
+	"context.Context":            true,
+	"database/sql/driver.Valuer": true,
+	"encoding/json.Marshaler":    true,
+	"encoding/json.Unmarshaler":  true,
+	"fmt.Stringer":               true,
+	"hash.Hash":                  true,
+	"io.Closer":                  true,
+	"io.ReadCloser":              true,
+	"io.ReadWriter":              true,
+	"io.ReadWriteCloser":         true,
+	"io.Reader":                  true,
+	"io.WriteCloser":             true,
+	"io.Writer":                  true,
+	"net.Conn":                   true,
+	"net/http.Handler":           true,
+	"net/http.ResponseWriter":    true,
+	"sort.Interface":             true,
 }
 
-func processEllipsis(expr ast.Expr, imports map[string]string) *Expression {
-	if ellipsisType, ok := expr.(*ast.Ellipsis); ok {
-		mExpr := &Expression{
-			TypeName: "...",
-		}
-		if ellipsisType.Elt != nil {
-			if elt := processExpression(ellipsisType.Elt, imports); elt != nil {
-				mExpr.PackageName = elt.PackageName
-				mExpr.TypeName = fmt.Sprintf("...%s", elt.TypeName)
-			}
+func isInterfaceType(expr ast.Expr, imports map[string]string) bool {
+	switch t := expr.(type) {
+	case *ast.InterfaceType:
+		return true
+	case *ast.Ident:
+		return t.Name == "error" || t.Name == "any"
+	case *ast.SelectorExpr:
+		if ident, ok := t.X.(*ast.Ident); ok {
+			return wellKnownInterfaces[imports[ident.Name]+"."+t.Sel.Name]
 		}
-		return mExpr
 	}
-	return nil
+	return false
 }
 
-func processArrayType(fieldType ast.Expr, imports map[string]string) *Expression {
-	if arrayType, ok := fieldType.(*ast.ArrayType); ok {
-		if elt := processExpression(arrayType.Elt, imports); elt != nil {
-			typeName := fmt.Sprintf("[]%s", elt.TypeName)
-			return &Expression{
-				PackageName: elt.PackageName,
-				TypeName:    typeName,
-			}
-		}
+func processExpression(expr ast.Expr, imports map[string]string) *Expression {
+	w := typeNameWriter{buf: make([]byte, 0, 32), imports: imports}
+	packageName, ok := w.write(expr)
+	if !ok {
+		return nil
 	}
-	return nil
-}
-
-func processStarExpr(fieldType ast.Expr, imports map[string]string) *Expression {
-	if starExpr, ok := fieldType.(*ast.StarExpr); ok {
-		if x := processExpression(starExpr.X, imports); x != nil {
-			typeName := fmt.Sprintf("*%s", x.TypeName)
-			return &Expression{
-				PackageName: x.PackageName,
-				TypeName:    typeName,
-			}
-		}
+	mExpr := &Expression{
+		PackageName: packageName,
+		TypeName:    string(w.buf),
 	}
-	return nil
-}
-
-func processIdent(fieldType ast.Expr, imports map[string]string) *Expression {
-	if ident, ok := fieldType.(*ast.Ident); ok {
-		return &Expression{
-			TypeName: ident.Name,
-		}
+	if len(w.packageNames) > 1 {
+		mExpr.PackageNames = w.packageNames
 	}
-	return nil
+	return mExpr
 }
 
-func processSelectorExpr(fieldType ast.Expr, imports map[string]string) *Expression {
-	if selectorExpr, ok := fieldType.(*ast.SelectorExpr); ok {
-		if ident, ok := selectorExpr.X.(*ast.Ident); ok {
-			typeName := fmt.Sprintf("%s.%s", ident.Name, selectorExpr.Sel.Name)
-			return &Expression{
-				PackageName: imports[ident.Name],
-				TypeName:    typeName,
-			}
-		}
-	}
-	return nil
+// typeNameWriter writes the type-name of an expression into a single buffer, instead of formatting a string for
+// every part of a composite type, and collects the import-paths of the packages that the type refers to
+type typeNameWriter struct {
+	buf          []byte
+	imports      map[string]string
+	packageNames []string
 }
 
-func processMapType(fieldType ast.Expr, imports map[string]string) *Expression {
-	if mapType, ok := fieldType.(*ast.MapType); ok {
-		if key := processExpression(mapType.Key, imports); key != nil {
-			if value := processExpression(mapType.Value, imports); value != nil {
-				typeName := fmt.Sprintf("map[%s]%s", key.TypeName, value.TypeName)
-				return &Expression{
-					TypeName: typeName,
-				}
-			}
-		}
+// write appends the type-name of expr and returns the import-path of its package: nothing is appended for
+// expressions that are not understood
+func (w *typeNameWriter) write(expr ast.Expr) (string, bool) {
+	start, packageCount := len(w.buf), len(w.packageNames)
+	packageName, ok := w.writeExpr(expr)
+	if !ok {
+		w.buf, w.packageNames = w.buf[:start], w.packageNames[:packageCount]
 	}
-	return nil
+	return packageName, ok
 }
 
-func processFuncType(fieldType ast.Expr, imports map[string]string) *Expression {
-	if funcType, ok := fieldType.(*ast.FuncType); ok {
-		params := make([]string, 0)
-		for _, param := range funcType.Params.List {
-			if paramField := extractField(param, imports); paramField != nil {
-				formattedParam := paramField.TypeName
-				if paramField.Name != "" {
-					formattedParam = fmt.Sprintf("%s %s", paramField.Name, paramField.TypeName)
-				}
-				params = append(params, formattedParam)
-			}
+func (w *typeNameWriter) writeExpr(expr ast.Expr) (string, bool) {
+	switch t := expr.(type) {
+	case *ast.Ellipsis:
+		w.buf = append(w.buf, "..."...)
+		if t.Elt == nil {
+			return "", true
 		}
-		results := make([]string, 0)
-		if funcType.Results != nil {
-			for _, result := range funcType.Results.List {
-				if resultType := processExpression(result.Type, imports); resultType != nil {
-					results = append(results, resultType.TypeName)
-				}
-			}
+		packageName, _ := w.write(t.Elt)
+		return packageName, true
+	case *ast.ArrayType:
+		w.buf = append(w.buf, '[')
+		switch length := t.Len.(type) {
+		case nil:
+		case *ast.BasicLit:
+			w.buf = append(w.buf, length.Value...)
+		case *ast.Ident:
+			w.buf = append(w.buf, length.Name...)
+		default:
+			return "", false
+		}
+		w.buf = append(w.buf, ']')
+		return w.write(t.Elt)
+	case *ast.StarExpr:
+		w.buf = append(w.buf, '*')
+		return w.write(t.X)
+	case *ast.Ident:
+		w.buf = append(w.buf, t.Name...)
+		if dotImport := w.imports[dotImportKey]; dotImport != "" && ast.IsExported(t.Name) {
+			// types of the package itself are resolved after visiting all of it
+			w.addPackageName(dotImport)
+			return dotImport, true
+		}
+		return "", true
+	case *ast.SelectorExpr:
+		ident, ok := t.X.(*ast.Ident)
+		if !ok {
+			return "", false
 		}
-		typeName := fmt.Sprintf("(%s)%s", strings.Join(params, ","), strings.Join(results, ","))
-		return &Expression{
-			TypeName: typeName,
+		w.buf = append(w.buf, ident.Name...)
+		w.buf = append(w.buf, '.')
+		w.buf = append(w.buf, t.Sel.Name...)
+		packageName := w.imports[ident.Name]
+		w.addPackageName(packageName)
+		return packageName, true
+	case *ast.MapType:
+		// the package of the value, otherwise that of the key: like map[mypkg.ID]*Order
+		w.buf = append(w.buf, "map["...)
+		keyPackageName, ok := w.write(t.Key)
+		if !ok {
+			return "", false
+		}
+		w.buf = append(w.buf, ']')
+		valuePackageName, ok := w.write(t.Value)
+		if valuePackageName == "" {
+			return keyPackageName, ok
+		}
+		return valuePackageName, ok
+	case *ast.FuncType:
+		w.buf = append(w.buf, '(')
+		w.writeList(t.Params, false)
+		w.buf = append(w.buf, ')')
+		w.writeList(t.Results, false)
+		return "", true
+	case *ast.InterfaceType:
+		w.buf = append(w.buf, "interface{"...)
+		w.writeList(t.Methods, true)
+		w.buf = append(w.buf, '}')
+		return "", true
+	}
+	return "", false
+}
+
+func (w *typeNameWriter) addPackageName(packageName string) {
+	if packageName == "" {
+		return
+	}
+	for _, existing := range w.packageNames {
+		if existing == packageName {
+			return
 		}
 	}
-	return nil
+	w.packageNames = append(w.packageNames, packageName)
 }
 
-func processInterfaceType(fieldType ast.Expr, imports map[string]string) *Expression {
-	if interfaceType, ok := fieldType.(*ast.InterfaceType); ok {
-		methods := make([]string, 0)
-		for _, method := range extractFieldList(interfaceType.Methods, imports) {
-			methods = append(methods, fmt.Sprintf("%s%s", method.Name, method.TypeName))
+// writeList appends the comma-separated types of a field-list, skipping those that are not understood. Parameters
+// and results are written once per declaration, like x,y int -> int; methods of an interface are written once per
+// name and prefixed with it.
+func (w *typeNameWriter) writeList(fieldList *ast.FieldList, withNames bool) {
+	if fieldList == nil {
+		return
+	}
+	first := true
+	for _, field := range fieldList.List {
+		names := []*ast.Ident{nil}
+		if withNames && len(field.Names) > 0 {
+			names = field.Names
 		}
-		typeName := fmt.Sprintf("interface{%s}", strings.Join(methods, ","))
-		return &Expression{
-			TypeName: typeName,
+		for _, name := range names {
+			start := len(w.buf)
+			if !first {
+				w.buf = append(w.buf, ',')
+			}
+			if name != nil {
+				w.buf = append(w.buf, name.Name...)
+			}
+			if _, ok := w.write(field.Type); !ok {
+				w.buf = w.buf[:start]
+				continue
+			}
+			first = false
 		}
 	}
-	return nil
 }
 
 type Expression struct {
-	PackageName string
-	Name        string
-	TypeName    string
+	PackageName  string
+	PackageNames []string
+	Name         string
+	TypeName     string
 }
@@ -0,0 +1,226 @@
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+// structCorpus returns the field-lists of many structs with the kinds of fields that annotated sources have
+func structCorpus(tb testing.TB) []*ast.FieldList {
+	src := strings.Builder{}
+	src.WriteString("package corpus\n\nimport \"time\"\n")
+	for idx := 0; idx < 500; idx++ {
+		fmt.Fprintf(&src, `
+// Struct%[1]d is documented
+type Struct%[1]d struct {
+	// UID identifies it
+	UID       string `+"`json:\"uid\"`"+`
+	X, Y      int
+	Created   time.Time
+	Parent    *Struct%[1]d
+	Names     []string
+	Stops     map[string][]*time.Time // by name
+	Callback  func(ctx string, count int) (bool, error)
+	Listener  interface{ Notify(event string) error }
+	Arguments []interface{}
+}
+`, idx)
+	}
+	file, err := parser.ParseFile(token.NewFileSet(), "corpus.go", src.String(), parser.ParseComments)
+	assert.NoError(tb, err)
+	fieldLists := []*ast.FieldList{}
+	ast.Inspect(file, func(node ast.Node) bool {
+		if structType, ok := node.(*ast.StructType); ok {
+			fieldLists = append(fieldLists, structType.Fields)
+		}
+		return true
+	})
+	return fieldLists
+}
+
+func TestExtractFieldList(t *testing.T) {
+	imports := map[string]string{"time": "time"}
+	fields := extractFieldList(structCorpus(t)[0], imports)
+
+	typeNames := []string{}
+	for _, field := range fields {
+		typeNames = append(typeNames, field.Name+" "+field.TypeName)
+	}
+	assert.Equal(t, []string{
+		"UID string",
+		"X int",
+		"Y int",
+		"Created time.Time",
+		"Parent *Struct0",
+		"Names []string",
+		"Stops map[string][]*time.Time",
+		"Callback (string,int)bool,error",
+		"Listener interface{Notify(string)error}",
+		"Arguments []interface{}",
+	}, typeNames)
+	assert.Equal(t, "time", fields[3].PackageName)
+	assert.Equal(t, []string{"// UID identifies it"}, fields[0].DocLines)
+	assert.Equal(t, []string{"// by name"}, fields[6].CommentLines)
+	assert.Nil(t, fields[1].DocLines)
+	assert.Nil(t, extractFieldList(nil, imports))
+}
+
+func BenchmarkExtractFieldList(b *testing.B) {
+	imports := map[string]string{"time": "time"}
+	fieldLists := structCorpus(b)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for _, fieldList := range fieldLists {
+			extractFieldList(fieldList, imports)
+		}
+	}
+}
+
+func TestExtractQualifiedMapTypes(t *testing.T) {
+	src := `package orders
+
+import (
+	"github.com/example/ids"
+	"github.com/example/shop"
+)
+
+type Orders struct {
+	ByID     map[ids.ID]*Order
+	Shops    map[string]*shop.Shop
+	Mixed    map[ids.ID][]*shop.Order
+	Nested   map[ids.ID]map[string]shop.Order
+	Callback func(id ids.ID) *shop.Order
+}
+`
+	file, err := parser.ParseFile(token.NewFileSet(), "orders.go", src, parser.ParseComments)
+	assert.NoError(t, err)
+	imports := map[string]string{"ids": "github.com/example/ids", "shop": "github.com/example/shop"}
+	fieldList := file.Decls[1].(*ast.GenDecl).Specs[0].(*ast.TypeSpec).Type.(*ast.StructType).Fields
+	fields := extractFieldList(fieldList, imports)
+
+	assert.Equal(t, "map[ids.ID]*Order", fields[0].TypeName)
+	assert.Equal(t, "github.com/example/ids", fields[0].PackageName)
+	assert.Nil(t, fields[0].PackageNames)
+	assert.Equal(t, []string{"github.com/example/ids"}, fields[0].ImportPaths())
+
+	assert.Equal(t, "map[string]*shop.Shop", fields[1].TypeName)
+	assert.Equal(t, "github.com/example/shop", fields[1].PackageName)
+
+	for _, f := range fields[2:] {
+		assert.Equal(t, []string{"github.com/example/ids", "github.com/example/shop"}, f.ImportPaths(), f.Name)
+	}
+	assert.Equal(t, "map[ids.ID][]*shop.Order", fields[2].TypeName)
+	assert.Equal(t, "github.com/example/shop", fields[2].PackageName)
+	assert.Equal(t, "map[ids.ID]map[string]shop.Order", fields[3].TypeName)
+	assert.Equal(t, "(ids.ID)*shop.Order", fields[4].TypeName)
+}
+
+func TestExtractNestedSliceAndPointerTypes(t *testing.T) {
+	src := `package shapes
+
+import "github.com/example/pkg"
+
+type Shapes struct {
+	Matrix      [][]string
+	Things      [][]*pkg.Thing
+	Deeper      [][][]*pkg.Thing
+	ThingList   *[]Thing
+	PointerList *[]*pkg.Thing
+	Pointers    []*[]string
+	Fixed       [3]string
+	Grid        [size][size]int
+}
+`
+	file, err := parser.ParseFile(token.NewFileSet(), "shapes.go", src, parser.ParseComments)
+	assert.NoError(t, err)
+	fieldList := file.Decls[1].(*ast.GenDecl).Specs[0].(*ast.TypeSpec).Type.(*ast.StructType).Fields
+	fields := extractFieldList(fieldList, map[string]string{"pkg": "github.com/example/pkg"})
+
+	typeNames := map[string]string{}
+	packageNames := map[string]string{}
+	for _, f := range fields {
+		typeNames[f.Name] = f.TypeName
+		packageNames[f.Name] = f.PackageName
+	}
+	assert.Equal(t, map[string]string{
+		"Matrix":      "[][]string",
+		"Things":      "[][]*pkg.Thing",
+		"Deeper":      "[][][]*pkg.Thing",
+		"ThingList":   "*[]Thing",
+		"PointerList": "*[]*pkg.Thing",
+		"Pointers":    "[]*[]string",
+		"Fixed":       "[3]string",
+		"Grid":        "[size][size]int",
+	}, typeNames)
+	assert.Equal(t, "github.com/example/pkg", packageNames["Things"])
+	assert.Equal(t, "github.com/example/pkg", packageNames["Deeper"])
+	assert.Equal(t, "github.com/example/pkg", packageNames["PointerList"])
+	assert.Equal(t, "", packageNames["Matrix"])
+}
+
+func TestInterfaceFields(t *testing.T) {
+	dir := t.TempDir()
+	writeSource(t, filepath.Join(dir, "store.go"), `package store
+
+import (
+	"io"
+	"net/http"
+)
+
+type Repository interface {
+	Get(uid string) (Order, error)
+}
+
+type Order struct{}
+
+type Store struct {
+	Repo     Repository
+	Body     io.Reader
+	Handler  http.Handler
+	Err      error
+	Anything interface{}
+	Order    Order
+	Orders   []Repository
+	Client   *http.Client
+}
+
+func (s *Store) Save(repo Repository, order Order) error {
+	return nil
+}
+`)
+	parsedSources, err := New().ParseSourceDir(dir, "*.go", "gen_*.go")
+	assert.NoError(t, err)
+
+	interfaceFields := func(fields []model.Field) []string {
+		names := []string{}
+		for _, f := range fields {
+			if f.IsInterface {
+				names = append(names, f.Name)
+			}
+		}
+		return names
+	}
+	store := parsedSources.Structs[1]
+	assert.Equal(t, "Store", store.Name)
+	assert.Equal(t, []string{"Repo", "Body", "Handler", "Err", "Anything"}, interfaceFields(store.Fields))
+	assert.Equal(t, "io", store.Fields[1].PackageName)
+
+	save := parsedSources.Operations[0]
+	assert.Equal(t, []string{"repo"}, interfaceFields(save.InputArgs))
+	assert.True(t, save.OutputArgs[0].IsInterface)
+	assert.True(t, store.Operations[0].InputArgs[0].IsInterface)
+
+	get := parsedSources.Interfaces[0].Methods[0]
+	assert.False(t, get.OutputArgs[0].IsInterface)
+	assert.True(t, get.OutputArgs[1].IsInterface)
+}
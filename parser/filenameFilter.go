@@ -0,0 +1,66 @@
+package parser
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// filenameFilter matches the names of the files in a source-dir, by a regular expression or a glob
+type filenameFilter struct {
+	regex   *regexp.Regexp
+	glob    string
+	negated bool
+}
+
+// compiledFilters caches the filters by pattern, because the same patterns are used for every parsed directory
+var compiledFilters sync.Map
+
+// compileFilter compiles a pattern for filenames. A pattern is a glob when it starts with "!", which inverts the
+// match, or has a "*" that does not repeat a "." or a group, like "*.go", "gen_*.go" or "!*_test.go". Other
+// patterns are regular expressions, like "^.*.go$".
+func compileFilter(pattern string) (*filenameFilter, error) {
+	if filter, found := compiledFilters.Load(pattern); found {
+		return filter.(*filenameFilter), nil
+	}
+	filter := &filenameFilter{}
+	if isGlob(pattern) {
+		filter.glob = strings.TrimPrefix(pattern, "!")
+		filter.negated = filter.glob != pattern
+		// Match only reports a malformed pattern when it gets to the malformed part
+		_, err := filepath.Match(filter.glob, filter.glob)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid filename-glob '%s':%w", pattern, err)
+		}
+	} else {
+		regex, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid filename-regex '%s':%w", pattern, err)
+		}
+		filter.regex = regex
+	}
+	compiledFilters.Store(pattern, filter)
+	return filter, nil
+}
+
+func isGlob(pattern string) bool {
+	if strings.HasPrefix(pattern, "!") {
+		return true
+	}
+	for idx, c := range pattern {
+		if c == '*' && (idx == 0 || !strings.ContainsRune(".])", rune(pattern[idx-1]))) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *filenameFilter) match(filename string) bool {
+	if f.regex != nil {
+		return f.regex.MatchString(filename)
+	}
+	matched, _ := filepath.Match(f.glob, filename)
+	return matched != f.negated
+}
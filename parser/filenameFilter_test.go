@@ -0,0 +1,62 @@
+package parser
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilenameFilters(t *testing.T) {
+	for _, tc := range []struct {
+		pattern  string
+		filename string
+		match    bool
+	}{
+		{"^.*.go$", "tour.go", true},
+		{"^.*.go$", "tour.txt", false},
+		{"gen_.*", "gen_tour.go", true},
+		{"gen_.*", "tour.go", false},
+		{"*.go", "tour.go", true},
+		{"*.go", "tour.txt", false},
+		{"gen_*.go", "gen_tour.go", true},
+		{"gen_*.go", "tour.go", false},
+		{"!*_test.go", "tour.go", true},
+		{"!*_test.go", "tour_test.go", false},
+	} {
+		filter, err := compileFilter(tc.pattern)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.match, filter.match(tc.filename), "%s matches %s", tc.pattern, tc.filename)
+	}
+}
+
+func TestCompileFilterIsCached(t *testing.T) {
+	first, err := compileFilter("^cached.*.go$")
+	assert.NoError(t, err)
+	second, err := compileFilter("^cached.*.go$")
+	assert.NoError(t, err)
+	assert.True(t, first == second)
+}
+
+func TestInvalidFilenameFilters(t *testing.T) {
+	_, err := compileFilter("^(.*.go$")
+	assert.EqualError(t, err, "Invalid filename-regex '^(.*.go$':error parsing regexp: missing closing ): `^(.*.go$`")
+
+	_, err = compileFilter("[*.go")
+	assert.EqualError(t, err, "Invalid filename-glob '[*.go':syntax error in pattern")
+
+	_, err = New().ParseSourceDir("structs", "^.*.go$", "^(gen_")
+	assert.Error(t, err)
+}
+
+func TestParseSourceDirWithGlobs(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "tour.go"), []byte("package tour\n\ntype Tour struct{}\n"), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "tour_test.go"), []byte("package tour\n\ntype TourTest struct{}\n"), 0644))
+
+	parsedSources, err := New().ParseSourceDir(dir, "!*_test.go", "gen_*.go")
+	assert.NoError(t, err)
+	assert.Len(t, parsedSources.Structs, 1)
+	assert.Equal(t, "Tour", parsedSources.Structs[0].Name)
+}
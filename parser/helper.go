@@ -1,12 +1,28 @@
 package parser
 
-import "go/ast"
+import (
+	"go/ast"
+	"strings"
+)
 
 func extractComments(commentGroup *ast.CommentGroup) []string {
-	lines := make([]string, 0)
-	if commentGroup != nil {
-		for _, comment := range commentGroup.List {
-			lines = append(lines, comment.Text)
+	if commentGroup == nil {
+		return nil
+	}
+	lines := make([]string, 0, len(commentGroup.List))
+	for _, comment := range commentGroup.List {
+		lines = append(lines, comment.Text)
+	}
+	return lines
+}
+
+// extractDocAndTrailingAnnotations returns the doc-lines, followed by the annotations in the comment on the same
+// line, like of a method of an interface: Get() // @CacheMethod()
+func extractDocAndTrailingAnnotations(doc *ast.CommentGroup, comment *ast.CommentGroup) []string {
+	lines := extractComments(doc)
+	for _, line := range extractComments(comment) {
+		if strings.HasPrefix(strings.TrimSpace(strings.TrimLeft(line, "/")), "@") {
+			lines = append(lines, line)
 		}
 	}
 	return lines
@@ -0,0 +1,130 @@
+package parser
+
+import (
+	"go/ast"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+// dotImportKey is the key of the dot-import of a file in its imports: types without package refer to it when
+// the package does not declare them itself
+const dotImportKey = "."
+
+var (
+	// majorVersionSegment is the last segment of a module of major version 2 or higher, like github.com/org/y/v2
+	majorVersionSegment = regexp.MustCompile(`^v[0-9]+$`)
+	// versionSuffix is the version of gopkg.in-imports, like gopkg.in/yaml.v3
+	versionSuffix = regexp.MustCompile(`\.v[0-9]+$`)
+)
+
+// addImport records an import of the file that is visited by the name that its file refers to the package by:
+// the explicit alias, otherwise the name that the import-path implies
+func (v *astVisitor) addImport(importSpec *ast.ImportSpec) {
+	importPath := strings.Trim(importSpec.Path.Value, "\"`")
+	name := importName(importPath)
+	if importSpec.Name != nil {
+		name = importSpec.Name.Name
+	}
+	switch name {
+	case "_":
+		return
+	case dotImportKey:
+		if v.DotImports == nil {
+			v.DotImports = map[string]bool{}
+		}
+		v.DotImports[importPath] = true
+		if _, found := v.Imports[dotImportKey]; found {
+			// with multiple dot-imports, the package of a type cannot be told without type-checking
+			v.Logger.Warningf("%s:%d: multiple dot-imports, so types without package are not resolved to %s",
+				v.CurrentFilename, v.line(importSpec.Pos()), importPath)
+			v.Imports[dotImportKey] = ""
+			return
+		}
+	default:
+		if existing, found := v.Imports[name]; found && existing != importPath {
+			v.Logger.Warningf("%s:%d: imports %s and %s are both known as %s: give one of them an alias, so that types resolve to the right package",
+				v.CurrentFilename, v.line(importSpec.Pos()), existing, importPath, name)
+		}
+	}
+	v.Imports[name] = importPath
+}
+
+// importName returns the name of the package of an import-path without alias: its last segment without the
+// major version of the module or gopkg.in, and without a go-prefix or -go suffix
+func importName(importPath string) string {
+	segments := strings.Split(importPath, "/")
+	name := segments[len(segments)-1]
+	if len(segments) > 1 && majorVersionSegment.MatchString(name) {
+		name = segments[len(segments)-2]
+	}
+	name = versionSuffix.ReplaceAllString(name, "")
+	name = strings.TrimSuffix(strings.TrimPrefix(name, "go-"), "-go")
+	return strings.Replace(path.Base(name), "-", "_", -1)
+}
+
+// resolveDotImports removes the package from the fields of types that the package declares itself, which were
+// taken for types of the dot-imported package
+func resolveDotImports(visitor *astVisitor) {
+	if len(visitor.DotImports) == 0 {
+		return
+	}
+	declared := map[string]bool{}
+	for _, s := range visitor.Structs {
+		declared[s.PackageName+"."+s.Name] = true
+	}
+	for _, e := range visitor.Enums {
+		declared[e.PackageName+"."+e.Name] = true
+	}
+	for _, t := range visitor.Typedefs {
+		declared[t.PackageName+"."+t.Name] = true
+	}
+	for _, i := range visitor.Interfaces {
+		declared[i.PackageName+"."+i.Name] = true
+	}
+	resolve := func(packageName string, fields []model.Field) {
+		for idx := range fields {
+			f := &fields[idx]
+			if visitor.DotImports[f.PackageName] && len(f.PackageNames) == 0 && declared[packageName+"."+elementTypeName(f.TypeName)] {
+				f.PackageName = ""
+			}
+		}
+	}
+	for _, s := range visitor.Structs {
+		resolve(s.PackageName, s.Fields)
+	}
+	for _, o := range visitor.Operations {
+		if o.RelatedStruct != nil {
+			// receivers are of the package itself
+			o.RelatedStruct.PackageName = ""
+		}
+		resolve(o.PackageName, o.InputArgs)
+		resolve(o.PackageName, o.OutputArgs)
+	}
+	for _, i := range visitor.Interfaces {
+		for _, m := range i.Methods {
+			resolve(i.PackageName, m.InputArgs)
+			resolve(i.PackageName, m.OutputArgs)
+		}
+	}
+}
+
+// elementTypeName returns the type of the elements of pointers, slices and arrays, like Thing of []*Thing
+func elementTypeName(typeName string) string {
+	for {
+		switch {
+		case strings.HasPrefix(typeName, "*"):
+			typeName = typeName[1:]
+		case strings.HasPrefix(typeName, "["):
+			end := strings.Index(typeName, "]")
+			if end < 0 {
+				return typeName
+			}
+			typeName = typeName[end+1:]
+		default:
+			return typeName
+		}
+	}
+}
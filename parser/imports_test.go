@@ -0,0 +1,104 @@
+package parser
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportName(t *testing.T) {
+	for importPath, name := range map[string]string{
+		"io":                              "io",
+		"net/http":                        "http",
+		"github.com/org/model":            "model",
+		"github.com/org/model/v2":         "model",
+		"gopkg.in/yaml.v3":                "yaml",
+		"github.com/mattn/go-sqlite3":     "sqlite3",
+		"github.com/census/opencensus-go": "opencensus",
+		"github.com/org/json-iterator":    "json_iterator",
+	} {
+		assert.Equal(t, name, importName(importPath), importPath)
+	}
+}
+
+func TestAliasedAndDotImports(t *testing.T) {
+	dir := t.TempDir()
+	writeSource(t, filepath.Join(dir, "order.go"), `package order
+
+import (
+	m "github.com/org/model/v2"
+	. "github.com/org/money"
+	_ "github.com/lib/pq"
+)
+
+type Order struct {
+	Customer m.Customer
+	Price    Amount
+	Lines    []*Line
+	Status   Status
+	Count    int
+}
+
+type Line struct{}
+
+type Status int
+`)
+	writeSource(t, filepath.Join(dir, "invoice.go"), `package order
+
+import "github.com/org/invoice/v3"
+
+type Invoice struct {
+	Original invoice.Invoice
+	Amount   Amount
+}
+`)
+	parsedSources, err := New().ParseSourceDir(dir, "*.go", "gen_*.go")
+	assert.NoError(t, err)
+
+	packageNames := map[string]string{}
+	for _, s := range parsedSources.Structs {
+		for _, f := range s.Fields {
+			packageNames[s.Name+"."+f.Name] = f.PackageName
+		}
+	}
+	assert.Equal(t, map[string]string{
+		"Order.Customer":   "github.com/org/model/v2",
+		"Order.Price":      "github.com/org/money",
+		"Order.Lines":      "",
+		"Order.Status":     "",
+		"Order.Count":      "",
+		"Invoice.Original": "github.com/org/invoice/v3",
+		// the dot-import is of the other file
+		"Invoice.Amount": "",
+	}, packageNames)
+}
+
+func TestWarnAboutAmbiguousImports(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "store.go")
+	writeSource(t, filename, `package store
+
+import (
+	"github.com/org/model"
+	"github.com/other/model/v2"
+	. "github.com/org/money"
+	. "github.com/org/units"
+)
+
+type Store struct {
+	Customer model.Customer
+	Price    Amount
+}
+`)
+	var buf bytes.Buffer
+	parsedSources, err := NewWithLogger(logging.New(&buf, logging.Normal)).ParseSourceDir(dir, "*.go", "gen_*.go")
+	assert.NoError(t, err)
+	assert.Equal(t, "warning: "+filename+":5: imports github.com/org/model and github.com/other/model/v2 are both known as model: give one of them an alias, so that types resolve to the right package\n"+
+		"warning: "+filename+":7: multiple dot-imports, so types without package are not resolved to github.com/org/units\n", buf.String())
+
+	assert.Len(t, parsedSources.Structs, 1)
+	assert.Equal(t, "", parsedSources.Structs[0].Fields[1].PackageName)
+}
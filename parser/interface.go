@@ -1,7 +1,21 @@
 package parser
 
-import "github.com/MarcGrol/golangAnnotations/model"
+import (
+	"go/ast"
+	"go/token"
+
+	"github.com/MarcGrol/golangAnnotations/model"
+)
 
 type Parser interface {
-	ParseSourceDir(dirName string, includeRegex string, excludeRegex string) (model.ParsedSources, error)
+	// ParseSourceDir parses the files in dirName that match include and not exclude: both are regular expressions,
+	// like "^.*.go$", or globs, like "*.go" and "!*_test.go"
+	ParseSourceDir(dirName string, include string, exclude string) (model.ParsedSources, error)
+	// ForEachParsedPackage parses the directories of rootDir and below one at a time and passes the model of every
+	// package to fn as soon as it is parsed, so that tools that handle one package at a time need not keep the
+	// model of all packages in memory. Directories that go ignores, like vendor and testdata, are skipped. It stops
+	// at the first error, including those that fn returns.
+	ForEachParsedPackage(rootDir string, include string, exclude string, fn func(ParsedPackage) error) error
+	// ParseFiles builds the model of files that are parsed already, like the files of a package that go vet analyzes
+	ParseFiles(fileSet *token.FileSet, files []*ast.File) model.ParsedSources
 }
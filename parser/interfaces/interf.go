@@ -10,5 +10,6 @@ type Doer interface {
 	// docline for interface method doit
 	doit(c context.Context, req Req) (Resp, error)
 	// docline for interface method dontDoit
-	dontDoit()
+	dontDoit()     // @Annotation( key = "value" )
+	undocumented() // comment of undocumented
 }
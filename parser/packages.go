@@ -0,0 +1,94 @@
+package parser
+
+import (
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+// ParsedPackage is the model of a single package in a directory
+type ParsedPackage struct {
+	Dir           string
+	Name          string
+	ParsedSources model.ParsedSources
+}
+
+func (p *myParser) ForEachParsedPackage(rootDir string, include string, exclude string, fn func(ParsedPackage) error) error {
+	includeFilter, err := compileFilter(include)
+	if err != nil {
+		return err
+	}
+	excludeFilter, err := compileFilter(exclude)
+	if err != nil {
+		return err
+	}
+	return filepath.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		name := info.Name()
+		if path != rootDir && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "testdata" || name == "vendor") {
+			return filepath.SkipDir
+		}
+		if !hasSourceFiles(path, includeFilter, excludeFilter) {
+			return nil
+		}
+		return p.forEachPackageInDir(path, include, exclude, fn)
+	})
+}
+
+// forEachPackageInDir parses a single directory, with a file-set of its own so that nothing of it is retained
+// after its packages are passed to fn
+func (p *myParser) forEachPackageInDir(dirName string, include string, exclude string, fn func(ParsedPackage) error) error {
+	fileSet := token.NewFileSet()
+	start := time.Now()
+	packages, err := parseDir(fileSet, dirName, include, exclude)
+	p.time("parse", start)
+	if err != nil {
+		p.options.Logger.Debugf("error parsing dir %s: %s", dirName, err.Error())
+		return err
+	}
+	for _, packageName := range sortedPackageNames(packages) {
+		start = time.Now()
+		v := &astVisitor{
+			FileSet: fileSet,
+			Logger:  p.options.Logger,
+			Imports: map[string]string{},
+		}
+		parsePackage(packages[packageName], v)
+		parsedSources := v.parsedSources()
+		p.time("visit", start)
+
+		err = fn(ParsedPackage{Dir: dirName, Name: packageName, ParsedSources: parsedSources})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hasSourceFiles tells if a directory has files that the filters select, before parsing anything of it
+func hasSourceFiles(dirName string, include *filenameFilter, exclude *filenameFilter) bool {
+	dir, err := os.Open(dirName)
+	if err != nil {
+		return false
+	}
+	defer dir.Close()
+	names, err := dir.Readdirnames(-1)
+	if err != nil {
+		return false
+	}
+	for _, name := range names {
+		if include.match(name) && !exclude.match(name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package parser
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeSource(t *testing.T, filename string, src string) {
+	assert.NoError(t, os.MkdirAll(filepath.Dir(filename), 0755))
+	assert.NoError(t, ioutil.WriteFile(filename, []byte(src), 0644))
+}
+
+func TestForEachParsedPackage(t *testing.T) {
+	root := t.TempDir()
+	writeSource(t, filepath.Join(root, "tour", "tour.go"), "package tour\n\ntype Tour struct{}\n\nfunc (t *Tour) Start() {}\n")
+	writeSource(t, filepath.Join(root, "tour", "tour_test.go"), "package tour_test\n\ntype TourTest struct{}\n")
+	writeSource(t, filepath.Join(root, "tour", "gen_tour.go"), "package tour\n\ntype Generated struct{}\n")
+	writeSource(t, filepath.Join(root, "person", "person.go"), "package person\n\ntype Person struct{}\n")
+	writeSource(t, filepath.Join(root, "person", "testdata", "data.go"), "package data\n\ntype Data struct{}\n")
+	writeSource(t, filepath.Join(root, "vendor", "lib", "lib.go"), "package lib\n\ntype Lib struct{}\n")
+	writeSource(t, filepath.Join(root, "docs", "README.md"), "not go\n")
+
+	visited := []string{}
+	err := New().ForEachParsedPackage(root, "*.go", "gen_*.go", func(pkg ParsedPackage) error {
+		rel, err := filepath.Rel(root, pkg.Dir)
+		assert.NoError(t, err)
+		names := []string{}
+		for _, s := range pkg.ParsedSources.Structs {
+			names = append(names, fmt.Sprintf("%s(%d)", s.Name, len(s.Operations)))
+		}
+		visited = append(visited, fmt.Sprintf("%s %s %v", filepath.ToSlash(rel), pkg.Name, names))
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"person person [Person(0)]",
+		"tour tour [Tour(1)]",
+		"tour tour_test [TourTest(0)]",
+	}, visited)
+}
+
+func TestForEachParsedPackageStopsAtError(t *testing.T) {
+	root := t.TempDir()
+	writeSource(t, filepath.Join(root, "a", "a.go"), "package a\n")
+	writeSource(t, filepath.Join(root, "b", "b.go"), "package b\n")
+
+	stop := fmt.Errorf("stop")
+	count := 0
+	err := New().ForEachParsedPackage(root, "*.go", "gen_*.go", func(pkg ParsedPackage) error {
+		count++
+		return stop
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, 1, count)
+
+	err = New().ForEachParsedPackage(root, "[*.go", "gen_*.go", func(pkg ParsedPackage) error {
+		return nil
+	})
+	assert.EqualError(t, err, "Invalid filename-glob '[*.go':syntax error in pattern")
+}
@@ -1,61 +1,104 @@
 package parser
 
 import (
+	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/token"
-	"log"
+	"io"
 	"os"
-	"path/filepath"
-	"regexp"
 	"sort"
 	"strings"
+	"time"
 
+	"github.com/MarcGrol/golangAnnotations/logging"
 	"github.com/MarcGrol/golangAnnotations/model"
 )
 
-var debugAstOfSources = false
+// Options configure a parser
+type Options struct {
+	// Logger receives the messages of parsing: nil reports to stderr
+	Logger *logging.Logger
+	// AstDump receives the go-ast of every parsed file, which helps when supporting new constructs in the parser
+	AstDump io.Writer
+	// Timer receives how long the phases of parsing a directory took: "parse" for reading the go-files and "visit"
+	// for building the model of their ast
+	Timer func(phase string, duration time.Duration)
+}
 
 type myParser struct {
+	options Options
 }
 
 func New() Parser {
-	return &myParser{}
+	return NewWithOptions(Options{})
 }
 
-func (p *myParser) ParseSourceDir(dirName string, includeRegex string, excludeRegex string) (model.ParsedSources, error) {
-	if debugAstOfSources {
-		dumpFilesInDir(dirName)
-	}
-	packages, err := parseDir(dirName, includeRegex, excludeRegex)
+// NewWithLogger returns a parser that reports to logger instead of to stderr
+func NewWithLogger(logger *logging.Logger) Parser {
+	return NewWithOptions(Options{Logger: logger})
+}
+
+// NewWithOptions returns a parser configured by options
+func NewWithOptions(options Options) Parser {
+	return &myParser{options: options}
+}
+
+func (p *myParser) ParseSourceDir(dirName string, include string, exclude string) (model.ParsedSources, error) {
+	fileSet := token.NewFileSet()
+	start := time.Now()
+	packages, err := parseDir(fileSet, dirName, include, exclude)
+	p.time("parse", start)
 	if err != nil {
-		log.Printf("error parsing dir %s: %s", dirName, err.Error())
+		p.options.Logger.Debugf("error parsing dir %s: %s", dirName, err.Error())
 		return model.ParsedSources{}, err
 	}
+	if p.options.AstDump != nil {
+		err = dumpPackages(p.options.AstDump, fileSet, packages)
+		if err != nil {
+			return model.ParsedSources{}, err
+		}
+	}
 
+	start = time.Now()
 	v := &astVisitor{
+		FileSet: fileSet,
+		Logger:  p.options.Logger,
 		Imports: map[string]string{},
 	}
-	for _, aPackage := range packages {
-		parsePackage(aPackage, v)
+	for _, packageName := range sortedPackageNames(packages) {
+		parsePackage(packages[packageName], v)
 	}
 
-	embedOperationsInStructs(v)
+	parsedSources := v.parsedSources()
+	p.time("visit", start)
+	return parsedSources, nil
+}
 
-	embedTypedefDocLinesInEnum(v)
+func (p *myParser) time(phase string, start time.Time) {
+	if p.options.Timer != nil {
+		p.options.Timer(phase, time.Since(start))
+	}
+}
 
-	return model.ParsedSources{
-		Structs:    v.Structs,
-		Operations: v.Operations,
-		Interfaces: v.Interfaces,
-		Typedefs:   v.Typedefs,
-		Enums:      v.Enums,
-	}, nil
+func (p *myParser) ParseFiles(fileSet *token.FileSet, files []*ast.File) model.ParsedSources {
+	fileMap := map[string]*ast.File{}
+	for _, file := range files {
+		fileMap[fileSet.Position(file.Pos()).Filename] = file
+	}
+	v := &astVisitor{
+		FileSet: fileSet,
+		Logger:  p.options.Logger,
+		Imports: map[string]string{},
+	}
+	parsePackage(&ast.Package{Files: fileMap}, v)
+	return v.parsedSources()
 }
 
 func parsePackage(aPackage *ast.Package, v *astVisitor) {
 	for _, fileEntry := range sortedFileEntries(aPackage.Files) {
 		v.CurrentFilename = fileEntry.key
+		v.Logger.Debugf("Parsing %s", fileEntry.key)
 
 		appEngineOnly := true
 		for _, commentGroup := range fileEntry.file.Comments {
@@ -68,42 +111,27 @@ func parsePackage(aPackage *ast.Package, v *astVisitor) {
 			}
 		}
 		if appEngineOnly {
-			ast.Walk(v, &fileEntry.file)
+			ast.Walk(v, fileEntry.file)
 		}
 	}
 }
 
 func parseSourceFile(srcFilename string) (model.ParsedSources, error) {
-	if debugAstOfSources {
-		dumpFile(srcFilename)
-	}
-
 	v, err := doParseFile(srcFilename)
 	if err != nil {
-		log.Printf("error parsing src %s: %s", srcFilename, err.Error())
 		return model.ParsedSources{}, err
 	}
-
-	embedOperationsInStructs(v)
-	embedTypedefDocLinesInEnum(v)
-
-	return model.ParsedSources{
-		Structs:    v.Structs,
-		Operations: v.Operations,
-		Interfaces: v.Interfaces,
-		Typedefs:   v.Typedefs,
-		Enums:      v.Enums,
-	}, nil
+	return v.parsedSources(), nil
 }
 
 func doParseFile(srcFilename string) (*astVisitor, error) {
 	fileSet := token.NewFileSet()
 	file, err := parser.ParseFile(fileSet, srcFilename, nil, parser.ParseComments)
 	if err != nil {
-		log.Printf("error parsing src-file %s: %s", srcFilename, err.Error())
 		return nil, err
 	}
 	v := &astVisitor{
+		FileSet: fileSet,
 		Imports: map[string]string{},
 	}
 	v.CurrentFilename = srcFilename
@@ -112,9 +140,10 @@ func doParseFile(srcFilename string) (*astVisitor, error) {
 	return v, nil
 }
 
+// fileEntry refers to a parsed file instead of copying it, so the visitor walks the ast that go/parser created
 type fileEntry struct {
 	key  string
-	file ast.File
+	file *ast.File
 }
 
 type fileEntries []fileEntry
@@ -137,7 +166,7 @@ func sortedFileEntries(fileMap map[string]*ast.File) fileEntries {
 		if file != nil {
 			fileEntries = append(fileEntries, fileEntry{
 				key:  key,
-				file: *file,
+				file: file,
 			})
 		}
 	}
@@ -145,20 +174,50 @@ func sortedFileEntries(fileMap map[string]*ast.File) fileEntries {
 	return fileEntries
 }
 
-func embedOperationsInStructs(visitor *astVisitor) {
-	mStructMap := make(map[string]*model.Struct)
-	for idx := range visitor.Structs {
-		mStructMap[(&visitor.Structs[idx]).Name] = &visitor.Structs[idx]
-	}
-	for idx := range visitor.Operations {
-		mOperation := visitor.Operations[idx]
-		if mOperation.RelatedStruct != nil {
-			if mStruct, ok := mStructMap[mOperation.RelatedStruct.DereferencedTypeName()]; ok {
-				mStruct.Operations = append(mStruct.Operations, &mOperation)
+// sortedPackageNames makes the order of the parsed sources independent of map-iteration when a directory
+// contains multiple packages, like an external test-package
+func sortedPackageNames(packages map[string]*ast.Package) []string {
+	names := make([]string, 0, len(packages))
+	for name := range packages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// markInterfaceFields marks the fields, arguments and results that have an interface of the visited package as type
+func markInterfaceFields(visitor *astVisitor) {
+	if len(visitor.Interfaces) == 0 {
+		return
+	}
+	interfaces := map[string]bool{}
+	for _, i := range visitor.Interfaces {
+		interfaces[i.PackageName+"."+i.Name] = true
+	}
+	mark := func(packageName string, fields []model.Field) {
+		for idx := range fields {
+			if fields[idx].PackageName == "" && interfaces[packageName+"."+fields[idx].TypeName] {
+				fields[idx].IsInterface = true
 			}
 		}
 	}
+	for _, s := range visitor.Structs {
+		mark(s.PackageName, s.Fields)
+	}
+	for _, o := range visitor.Operations {
+		mark(o.PackageName, o.InputArgs)
+		mark(o.PackageName, o.OutputArgs)
+	}
+	for _, i := range visitor.Interfaces {
+		for _, m := range i.Methods {
+			mark(i.PackageName, m.InputArgs)
+			mark(i.PackageName, m.OutputArgs)
+		}
+	}
+}
 
+func embedOperationsInStructs(visitor *astVisitor) {
+	model.ParsedSources{Structs: visitor.Structs, Operations: visitor.Operations}.EmbedOperations()
 }
 
 func embedTypedefDocLinesInEnum(visitor *astVisitor) {
@@ -172,59 +231,60 @@ func embedTypedefDocLinesInEnum(visitor *astVisitor) {
 	}
 }
 
-func parseDir(dirName string, includeRegex string, excludeRegex string) (map[string]*ast.Package, error) {
-	var includePattern = regexp.MustCompile(includeRegex)
-	var excludePattern = regexp.MustCompile(excludeRegex)
+func parseDir(fileSet *token.FileSet, dirName string, includePattern string, excludePattern string) (map[string]*ast.Package, error) {
+	include, err := compileFilter(includePattern)
+	if err != nil {
+		return nil, err
+	}
+	exclude, err := compileFilter(excludePattern)
+	if err != nil {
+		return nil, err
+	}
 
-	fileSet := token.NewFileSet()
 	packageMap, err := parser.ParseDir(fileSet, dirName, func(fi os.FileInfo) bool {
-		if excludePattern.MatchString(fi.Name()) {
+		if exclude.match(fi.Name()) {
 			return false
 		}
-		return includePattern.MatchString(fi.Name())
+		return include.match(fi.Name())
 	}, parser.ParseComments)
 	if err != nil {
-		log.Printf("error parsing dir %s: %s", dirName, err.Error())
 		return packageMap, err
 	}
 
 	return packageMap, nil
 }
 
-func dumpFile(srcFilename string) {
+func dumpFile(w io.Writer, srcFilename string) error {
 	fileSet := token.NewFileSet()
 	file, err := parser.ParseFile(fileSet, srcFilename, nil, parser.ParseComments)
 	if err != nil {
-		log.Printf("error parsing src %s: %s", srcFilename, err.Error())
-		return
+		return fmt.Errorf("Error parsing src %s:%w", srcFilename, err)
 	}
-	ast.Print(fileSet, file)
+	return ast.Fprint(w, fileSet, file, ast.NotNilFilter)
 }
 
-func dumpFilesInDir(dirName string) {
-	fileSet := token.NewFileSet()
-	packageMap, err := parser.ParseDir(
-		fileSet,
-		dirName,
-		nil,
-		parser.ParseComments)
-	if err != nil {
-		log.Printf("error parsing dir %s: %s", dirName, err.Error())
-	}
-	for _, aPackage := range packageMap {
-		for _, file := range aPackage.Files {
-			ast.Print(fileSet, file)
+func dumpPackages(w io.Writer, fileSet *token.FileSet, packages map[string]*ast.Package) error {
+	for _, packageName := range sortedPackageNames(packages) {
+		for _, fileEntry := range sortedFileEntries(packages[packageName].Files) {
+			err := ast.Fprint(w, fileSet, fileEntry.file, ast.NotNilFilter)
+			if err != nil {
+				return fmt.Errorf("Error dumping ast of %s:%w", fileEntry.key, err)
+			}
 		}
 	}
+	return nil
 }
 
 // =====================================================================================================================
 
 type astVisitor struct {
+	FileSet         *token.FileSet
+	Logger          *logging.Logger
 	CurrentFilename string
 	PackageName     string
 	Filename        string
 	Imports         map[string]string
+	DotImports      map[string]bool
 	Structs         []model.Struct
 	Operations      []model.Operation
 	Interfaces      []model.Interface
@@ -232,12 +292,35 @@ type astVisitor struct {
 	Enums           []model.Enum
 }
 
+// parsedSources completes the visited declarations into a model: types of the package are no longer taken for types
+// of a dot-import, fields of interfaces of the package are marked, operations with a receiver are embedded in their
+// struct and enums get the doc-lines of their typedef
+func (v *astVisitor) parsedSources() model.ParsedSources {
+	resolveDotImports(v)
+
+	markInterfaceFields(v)
+
+	embedOperationsInStructs(v)
+
+	embedTypedefDocLinesInEnum(v)
+
+	return model.ParsedSources{
+		Structs:    v.Structs,
+		Operations: v.Operations,
+		Interfaces: v.Interfaces,
+		Typedefs:   v.Typedefs,
+		Enums:      v.Enums,
+	}
+}
+
 func (v *astVisitor) Visit(node ast.Node) ast.Visitor {
 	if node != nil {
 
 		// package-name is in isolated node
 		if packageName, ok := extractPackageName(node); ok {
 			v.PackageName = packageName
+			// imports are per file
+			v.Imports = map[string]string{}
 		}
 
 		// extract all imports into a map
@@ -257,13 +340,7 @@ func (v *astVisitor) extractGenDeclImports(node ast.Node) {
 	if genDecl, ok := node.(*ast.GenDecl); ok {
 		for _, spec := range genDecl.Specs {
 			if importSpec, ok := spec.(*ast.ImportSpec); ok {
-				quotedImport := importSpec.Path.Value
-				unquotedImport := strings.Trim(quotedImport, "\"")
-				init, last := filepath.Split(unquotedImport)
-				if init == "" {
-					last = init
-				}
-				v.Imports[last] = unquotedImport
+				v.addImport(importSpec)
 			}
 		}
 	}
@@ -273,6 +350,10 @@ func (v *astVisitor) parseAsStruct(node ast.Node) {
 	if mStruct := extractGenDeclForStruct(node, v.Imports); mStruct != nil {
 		mStruct.PackageName = v.PackageName
 		mStruct.Filename = v.CurrentFilename
+		mStruct.Line = v.typeLine(node)
+		if structType, ok := firstTypeSpec(node).Type.(*ast.StructType); ok {
+			v.setFieldLines(mStruct.Fields, structType.Fields)
+		}
 		v.Structs = append(v.Structs, *mStruct)
 	}
 }
@@ -281,15 +362,17 @@ func (v *astVisitor) parseAsTypedef(node ast.Node) {
 	if mTypedef := extractGenDeclForTypedef(node); mTypedef != nil {
 		mTypedef.PackageName = v.PackageName
 		mTypedef.Filename = v.CurrentFilename
+		mTypedef.Line = v.typeLine(node)
 		v.Typedefs = append(v.Typedefs, *mTypedef)
 	}
 }
 
 func (v *astVisitor) parseAsEnum(node ast.Node) {
-	if mEnum := extractGenDeclForEnum(node); mEnum != nil {
+	for _, mEnum := range extractGenDeclForEnums(node) {
 		mEnum.PackageName = v.PackageName
 		mEnum.Filename = v.CurrentFilename
-		v.Enums = append(v.Enums, *mEnum)
+		mEnum.Line = v.line(node.Pos())
+		v.Enums = append(v.Enums, mEnum)
 	}
 }
 
@@ -298,6 +381,14 @@ func (v *astVisitor) parseAsInterFace(node ast.Node) {
 	if mInterface := extractInterface(node, v.Imports); mInterface != nil {
 		mInterface.PackageName = v.PackageName
 		mInterface.Filename = v.CurrentFilename
+		mInterface.Line = v.typeLine(node)
+		if interfaceType, ok := firstTypeSpec(node).Type.(*ast.InterfaceType); ok {
+			v.setMethodLines(mInterface.Methods, interfaceType.Methods)
+		}
+		for idx := range mInterface.Methods {
+			mInterface.Methods[idx].PackageName = v.PackageName
+			mInterface.Methods[idx].Filename = v.CurrentFilename
+		}
 		v.Interfaces = append(v.Interfaces, *mInterface)
 	}
 }
@@ -307,10 +398,76 @@ func (v *astVisitor) parseAsOperation(node ast.Node) {
 	if mOperation := extractOperation(node, v.Imports); mOperation != nil {
 		mOperation.PackageName = v.PackageName
 		mOperation.Filename = v.CurrentFilename
+		mOperation.Line = v.line(node.(*ast.FuncDecl).Name.Pos())
+		v.setFieldLines(mOperation.InputArgs, node.(*ast.FuncDecl).Type.Params)
 		v.Operations = append(v.Operations, *mOperation)
 	}
 }
 
+// line returns the line of a position in the file that is being visited
+func (v *astVisitor) line(pos token.Pos) int {
+	if v.FileSet == nil || !pos.IsValid() {
+		return 0
+	}
+	return v.FileSet.Position(pos).Line
+}
+
+// typeLine returns the line of the name of the type that a declaration declares
+func (v *astVisitor) typeLine(node ast.Node) int {
+	if typeSpec := firstTypeSpec(node); typeSpec.Name != nil {
+		return v.line(typeSpec.Name.Pos())
+	}
+	return v.line(node.Pos())
+}
+
+// setFieldLines gives the fields that were extracted from fieldList the line of their declaration
+func (v *astVisitor) setFieldLines(fields []model.Field, fieldList *ast.FieldList) {
+	if fieldList == nil {
+		return
+	}
+	idx := 0
+	for _, field := range fieldList.List {
+		if processExpression(field.Type, v.Imports) == nil {
+			// not extracted into fields
+			v.Logger.Warningf("%s:%d: could not understand type %T", v.CurrentFilename, v.line(field.Pos()), field.Type)
+			continue
+		}
+		count := len(field.Names)
+		if count == 0 {
+			count = 1
+		}
+		for n := 0; n < count && idx < len(fields); n++ {
+			fields[idx].Line = v.line(field.Pos())
+			if n < len(field.Names) {
+				fields[idx].Line = v.line(field.Names[n].Pos())
+			}
+			idx++
+		}
+	}
+}
+
+// setMethodLines gives the methods that were extracted from the fieldList of an interface the line of their declaration
+func (v *astVisitor) setMethodLines(methods []model.Operation, fieldList *ast.FieldList) {
+	idx := 0
+	for _, field := range fieldList.List {
+		if _, ok := field.Type.(*ast.FuncType); !ok || len(field.Names) == 0 || idx >= len(methods) {
+			continue
+		}
+		methods[idx].Line = v.line(field.Names[0].Pos())
+		v.setFieldLines(methods[idx].InputArgs, field.Type.(*ast.FuncType).Params)
+		idx++
+	}
+}
+
+func firstTypeSpec(node ast.Node) *ast.TypeSpec {
+	if genDecl, ok := node.(*ast.GenDecl); ok && len(genDecl.Specs) > 0 {
+		if typeSpec, ok := genDecl.Specs[0].(*ast.TypeSpec); ok {
+			return typeSpec
+		}
+	}
+	return &ast.TypeSpec{}
+}
+
 // =====================================================================================================================
 
 func extractPackageName(node ast.Node) (string, bool) {
@@ -343,7 +500,7 @@ func extractSpecsForStruct(specs []ast.Spec, imports map[string]string) *model.S
 			if structType, ok := typeSpec.Type.(*ast.StructType); ok {
 				return &model.Struct{
 					Name:   typeSpec.Name.Name,
-					Fields: extractFieldList(structType.Fields, imports),
+					Fields: extractStructFields(structType.Fields, imports),
 				}
 			}
 		}
@@ -381,55 +538,71 @@ func extractSpecsForTypedef(specs []ast.Spec) *model.Typedef {
 
 // ------------------------------------------------------- ENUM --------------------------------------------------------
 
-func extractGenDeclForEnum(node ast.Node) *model.Enum {
-	if genDecl, ok := node.(*ast.GenDecl); ok {
+func extractGenDeclForEnums(node ast.Node) []model.Enum {
+	if genDecl, ok := node.(*ast.GenDecl); ok && genDecl.Tok == token.CONST {
 		// Continue parsing to see if it is an enum
 		// Docs live in the related typedef
-		return extractSpecsForEnum(genDecl.Specs)
+		return extractSpecsForEnums(genDecl.Specs)
 	}
 	return nil
 }
 
-func extractSpecsForEnum(specs []ast.Spec) *model.Enum {
-	if typeName, ok := extractEnumTypeName(specs); ok {
-		mEnum := model.Enum{
-			Name:         typeName,
-			EnumLiterals: []model.EnumLiteral{},
+// extractSpecsForEnums returns an enum per type of the typed specs of a const-block, in order of appearance. A
+// spec without type and value repeats the previous spec, like Green and Blue in:
+//
+//	const (
+//		Red Color = iota
+//		Green
+//		Blue
+//	)
+//
+// Other specs without type are no enum-literals.
+func extractSpecsForEnums(specs []ast.Spec) []model.Enum {
+	mEnums := []model.Enum{}
+	enumIndex := map[string]int{}
+	typeName := ""
+	for ordinal, spec := range specs {
+		valueSpec, ok := spec.(*ast.ValueSpec)
+		if !ok {
+			continue
 		}
-		for _, spec := range specs {
-			if valueSpec, ok := spec.(*ast.ValueSpec); ok {
-				enumLiteral := model.EnumLiteral{
-					Name: valueSpec.Names[0].Name,
-				}
-				for _, value := range valueSpec.Values {
-					if basicLit, ok := value.(*ast.BasicLit); ok {
-						enumLiteral.Value = strings.Trim(basicLit.Value, "\"")
-						break
-					}
-				}
-				mEnum.EnumLiterals = append(mEnum.EnumLiterals, enumLiteral)
+		if valueSpec.Type != nil {
+			typeName = ""
+			if ident, ok := valueSpec.Type.(*ast.Ident); ok {
+				typeName = ident.Name
 			}
+		} else if len(valueSpec.Values) > 0 {
+			// untyped, like Max = 10
+			typeName = ""
 		}
-		return &mEnum
-	}
-	return nil
-}
-
-func extractEnumTypeName(specs []ast.Spec) (string, bool) {
-	for _, spec := range specs {
-		if valueSpec, ok := spec.(*ast.ValueSpec); ok {
-			if valueSpec.Type != nil {
-				for _, name := range valueSpec.Names {
-					if ident, ok := valueSpec.Type.(*ast.Ident); ok {
-						if name.Obj.Kind == ast.Con {
-							return ident.Name, true
-						}
-					}
-				}
+		if typeName == "" {
+			continue
+		}
+		idx, found := enumIndex[typeName]
+		if !found {
+			idx = len(mEnums)
+			enumIndex[typeName] = idx
+			mEnums = append(mEnums, model.Enum{Name: typeName, EnumLiterals: []model.EnumLiteral{}})
+		}
+		if valueSpec.Names[0].Name == "_" {
+			// skips a value, like the zero-value in _ ColorType = iota
+			continue
+		}
+		enumLiteral := model.EnumLiteral{
+			DocLines:     extractComments(valueSpec.Doc),
+			Name:         valueSpec.Names[0].Name,
+			CommentLines: extractComments(valueSpec.Comment),
+			Ordinal:      ordinal,
+		}
+		for _, value := range valueSpec.Values {
+			if basicLit, ok := value.(*ast.BasicLit); ok {
+				enumLiteral.Value = strings.Trim(basicLit.Value, "\"")
+				break
 			}
 		}
+		mEnums[idx].EnumLiterals = append(mEnums[idx].EnumLiterals, enumLiteral)
 	}
-	return "", false
+	return mEnums
 }
 
 // ----------------------------------------------------- INTERFACE -----------------------------------------------------
@@ -466,10 +639,11 @@ func extractInterfaceMethods(fieldList *ast.FieldList, imports map[string]string
 		if len(field.Names) > 0 {
 			if funcType, ok := field.Type.(*ast.FuncType); ok {
 				methods = append(methods, model.Operation{
-					DocLines:   extractComments(field.Doc),
-					Name:       field.Names[0].Name,
-					InputArgs:  extractFieldList(funcType.Params, imports),
-					OutputArgs: extractFieldList(funcType.Results, imports),
+					DocLines:     extractDocAndTrailingAnnotations(field.Doc, field.Comment),
+					Name:         field.Names[0].Name,
+					InputArgs:    extractFieldList(funcType.Params, imports),
+					OutputArgs:   extractFieldList(funcType.Results, imports),
+					CommentLines: extractComments(field.Comment),
 				})
 			}
 		}
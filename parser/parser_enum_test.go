@@ -1,13 +1,19 @@
 package parser
 
 import (
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"io/ioutil"
+	"path/filepath"
 	"testing"
 
+	"github.com/MarcGrol/golangAnnotations/model"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestParseEnumsInFile(t *testing.T) {
-	dumpFile("enums/enum.go")
+	assert.NoError(t, dumpFile(ioutil.Discard, "enums/enum.go"))
 	parsedSources, err := parseSourceFile("enums/enum.go")
 	assert.Equal(t, nil, err)
 
@@ -35,6 +41,12 @@ func TestParseEnumsInFile(t *testing.T) {
 		assert.Equal(t, "Red", parsedSources.Enums[0].EnumLiterals[0].Name)
 		assert.Equal(t, "Green", parsedSources.Enums[0].EnumLiterals[1].Name)
 		assert.Equal(t, "Blue", parsedSources.Enums[0].EnumLiterals[2].Name)
+		assert.Equal(t, []string{"// Green is the color of grass"}, parsedSources.Enums[0].EnumLiterals[1].DocLines)
+		assert.Equal(t, []string{"// the color of the sky"}, parsedSources.Enums[0].EnumLiterals[2].CommentLines)
+		assert.Nil(t, parsedSources.Enums[0].EnumLiterals[0].DocLines)
+		for ordinal, literal := range parsedSources.Enums[0].EnumLiterals {
+			assert.Equal(t, ordinal, literal.Ordinal)
+		}
 		assert.Equal(t, "enums/enum.go", parsedSources.Enums[0].Filename)
 		assert.Equal(t, "enums", parsedSources.Enums[0].PackageName)
 
@@ -48,3 +60,82 @@ func TestParseEnumsInFile(t *testing.T) {
 		assert.Equal(t, "enums", parsedSources.Enums[1].PackageName)
 	}
 }
+
+func TestParseEnumsWithoutObjects(t *testing.T) {
+	fileSet := token.NewFileSet()
+	file, err := goparser.ParseFile(fileSet, "status.go", `package status
+
+type Status int
+
+const (
+	_ Status = iota
+	Open
+	Closed
+)
+
+var (
+	Default Status = Open
+)
+
+const _ = "unused"
+`, goparser.ParseComments)
+	assert.NoError(t, err)
+	// like files parsed without object-resolution
+	ast.Inspect(file, func(node ast.Node) bool {
+		if ident, ok := node.(*ast.Ident); ok {
+			ident.Obj = nil
+		}
+		return true
+	})
+
+	parsedSources := New().ParseFiles(fileSet, []*ast.File{file})
+	assert.Len(t, parsedSources.Enums, 1)
+	assert.Equal(t, "Status", parsedSources.Enums[0].Name)
+	assert.Len(t, parsedSources.Enums[0].EnumLiterals, 2)
+	assert.Equal(t, "Open", parsedSources.Enums[0].EnumLiterals[0].Name)
+	assert.Equal(t, 1, parsedSources.Enums[0].EnumLiterals[0].Ordinal)
+	assert.Equal(t, "Closed", parsedSources.Enums[0].EnumLiterals[1].Name)
+	assert.Equal(t, 2, parsedSources.Enums[0].EnumLiterals[1].Ordinal)
+}
+
+func TestParseEnumsInMixedConstBlocks(t *testing.T) {
+	dir := t.TempDir()
+	writeSource(t, filepath.Join(dir, "pill.go"), `package pill
+
+type Pill int
+
+type Shape string
+
+const (
+	Placebo Pill = iota
+	Aspirin
+	Ibuprofen
+	Round Shape = "round"
+	Oval  Shape = "oval"
+	MaxDose = 3
+	Unit    = "mg"
+	Paracetamol Pill = 10
+)
+
+const Timeout = 5
+`)
+	parsedSources, err := New().ParseSourceDir(dir, "*.go", "gen_*.go")
+	assert.NoError(t, err)
+	assert.Len(t, parsedSources.Enums, 2)
+
+	literals := func(mEnum model.Enum) []string {
+		names := []string{}
+		for _, literal := range mEnum.EnumLiterals {
+			names = append(names, literal.Name)
+		}
+		return names
+	}
+	assert.Equal(t, "Pill", parsedSources.Enums[0].Name)
+	assert.Equal(t, []string{"Placebo", "Aspirin", "Ibuprofen", "Paracetamol"}, literals(parsedSources.Enums[0]))
+	assert.Equal(t, 7, parsedSources.Enums[0].EnumLiterals[3].Ordinal)
+	assert.Equal(t, "10", parsedSources.Enums[0].EnumLiterals[3].Value)
+
+	assert.Equal(t, "Shape", parsedSources.Enums[1].Name)
+	assert.Equal(t, []string{"Round", "Oval"}, literals(parsedSources.Enums[1]))
+	assert.Equal(t, "oval", parsedSources.Enums[1].EnumLiterals[1].Value)
+}
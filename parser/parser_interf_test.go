@@ -20,14 +20,14 @@ func TestInterfacesInDir(t *testing.T) {
 		assert.Equal(t, "Doer", i.Name)
 
 		{
-			assert.Len(t, i.Methods, 2)
+			assert.Len(t, i.Methods, 3)
 			{
 				m := i.Methods[0]
 				assert.Equal(t, []string{"// docline for interface method doit"}, m.DocLines)
 				assert.Equal(t, "doit", m.Name)
 				assert.Nil(t, m.RelatedStruct)
 				assert.Equal(t, 2, len(m.InputArgs))
-				assertField(t, model.Field{Name: "c", TypeName: "context.Context"}, m.InputArgs[0])
+				assertField(t, model.Field{PackageName: "context", Name: "c", TypeName: "context.Context"}, m.InputArgs[0])
 				assertField(t, model.Field{Name: "req", TypeName: "Req"}, m.InputArgs[1])
 
 				assert.Equal(t, 2, len(m.OutputArgs))
@@ -36,12 +36,34 @@ func TestInterfacesInDir(t *testing.T) {
 			}
 			{
 				m := i.Methods[1]
-				assert.Equal(t, []string{"// docline for interface method dontDoit"}, m.DocLines)
+				assert.Equal(t, []string{"// docline for interface method dontDoit", `// @Annotation( key = "value" )`}, m.DocLines)
+				assert.Equal(t, []string{`// @Annotation( key = "value" )`}, m.CommentLines)
 				assert.Equal(t, "dontDoit", m.Name)
 				assert.Nil(t, m.RelatedStruct)
 				assert.Equal(t, 0, len(m.InputArgs))
 				assert.Equal(t, 0, len(m.OutputArgs))
 			}
+			{
+				m := i.Methods[2]
+				assert.Nil(t, m.DocLines)
+				assert.Equal(t, []string{"// comment of undocumented"}, m.CommentLines)
+				assert.Equal(t, "undocumented", m.Name)
+			}
 		}
 	}
 }
+
+func TestInterfaceLines(t *testing.T) {
+	parsedSources, err := New().ParseSourceDir("./interfaces", "^.*.go$", generator.GenfileExcludeRegex)
+	assert.Equal(t, nil, err)
+
+	i := parsedSources.Interfaces[0]
+	assert.Equal(t, 9, i.Line)
+	assert.Equal(t, 11, i.Methods[0].Line)
+	assert.Equal(t, "interfaces/interf.go", i.Methods[0].Filename)
+	assert.Equal(t, 11, i.Methods[0].InputArgs[1].Line)
+	assert.Equal(t, 13, i.Methods[1].Line)
+
+	assert.Equal(t, "Req", parsedSources.Structs[0].Name)
+	assert.Equal(t, 5, parsedSources.Structs[0].Line)
+}
@@ -1,6 +1,8 @@
 package parser
 
 import (
+	"bytes"
+	"path/filepath"
 	"testing"
 
 	"github.com/MarcGrol/golangAnnotations/generator"
@@ -9,9 +11,10 @@ import (
 )
 
 func TestStructOperationsInDir(t *testing.T) {
-	dumpFilesInDir("./operations")
-	parsedSources, err := New().ParseSourceDir("./operations", "^.*.go$", generator.GenfileExcludeRegex)
+	var astDump bytes.Buffer
+	parsedSources, err := NewWithOptions(Options{AstDump: &astDump}).ParseSourceDir("./operations", "^.*.go$", generator.GenfileExcludeRegex)
 	assert.Equal(t, nil, err)
+	assert.Contains(t, astDump.String(), "*ast.FuncDecl")
 	assert.Equal(t, 4, len(parsedSources.Operations))
 
 	{
@@ -90,3 +93,30 @@ func TestStructOperationsInDir(t *testing.T) {
 		assertField(t, model.Field{TypeName: "error"}, o.OutputArgs[1])
 	}
 }
+
+func TestStructOperationsAcrossFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeSource(t, filepath.Join(dir, "service.go"), "package service\n\ntype Service struct{}\n")
+	writeSource(t, filepath.Join(dir, "get.go"), "package service\n\nfunc (s *Service) Get() {}\n")
+	writeSource(t, filepath.Join(dir, "put.go"), "package service\n\nfunc (s Service) Put() {}\n")
+	writeSource(t, filepath.Join(dir, "service_test.go"), "package service_test\n\ntype Service struct{}\n\nfunc (s *Service) Helper() {}\n")
+
+	parsedSources, err := New().ParseSourceDir(dir, "^.*.go$", generator.GenfileExcludeRegex)
+	assert.NoError(t, err)
+	assert.Len(t, parsedSources.Structs, 2)
+
+	s := parsedSources.Structs[0]
+	assert.Equal(t, "service", s.PackageName)
+	assert.Len(t, s.Operations, 2)
+	assert.True(t, s.MethodsByName()["Get"] == &parsedSources.Operations[0])
+	assert.True(t, s.MethodsByName()["Put"] == &parsedSources.Operations[1])
+	assert.Equal(t, []string{"Helper"}, operationNames(parsedSources.Structs[1].Operations))
+}
+
+func operationNames(operations []*model.Operation) []string {
+	names := []string{}
+	for _, o := range operations {
+		names = append(names, o.Name)
+	}
+	return names
+}
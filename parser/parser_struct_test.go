@@ -1,8 +1,12 @@
 package parser
 
 import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
 	"testing"
 
+	"github.com/MarcGrol/golangAnnotations/logging"
 	"github.com/MarcGrol/golangAnnotations/model"
 	"github.com/stretchr/testify/assert"
 )
@@ -135,3 +139,56 @@ func assertStringSlice(t *testing.T, expected []string, actual []string) {
 		}
 	}
 }
+
+func TestWarnAboutFieldWithUnknownType(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "channels.go")
+	src := "package channels\n\ntype Pipe struct {\n\tIn   chan int\n\tName string\n}\n"
+	assert.NoError(t, ioutil.WriteFile(filename, []byte(src), 0644))
+
+	var buf bytes.Buffer
+	parsedSources, err := NewWithLogger(logging.New(&buf, logging.Normal)).ParseSourceDir(dir, "^.*.go$", "^$")
+	assert.NoError(t, err)
+	assert.Equal(t, "warning: "+filename+":4: could not understand type *ast.ChanType\n", buf.String())
+
+	assert.Len(t, parsedSources.Structs, 1)
+	assert.Len(t, parsedSources.Structs[0].Fields, 1)
+	assert.Equal(t, "Name", parsedSources.Structs[0].Fields[0].Name)
+	assert.Equal(t, 5, parsedSources.Structs[0].Fields[0].Line)
+}
+
+func TestEmbeddedFields(t *testing.T) {
+	dir := t.TempDir()
+	writeSource(t, filepath.Join(dir, "store.go"), `package store
+
+import "io"
+
+type Repository interface {
+	Get(uid string) error
+}
+
+type Base struct{}
+
+type Store struct {
+	io.Closer
+	Repository
+	*Base
+	Name string
+}
+`)
+	parsedSources, err := New().ParseSourceDir(dir, "*.go", "gen_*.go")
+	assert.NoError(t, err)
+	assert.Len(t, parsedSources.Structs, 2)
+
+	fields := parsedSources.Structs[1].Fields
+	assert.Len(t, fields, 4)
+	assertField(t, model.Field{PackageName: "io", TypeName: "io.Closer", IsInterface: true, IsEmbedded: true}, fields[0])
+	assertField(t, model.Field{TypeName: "Repository", IsInterface: true, IsEmbedded: true}, fields[1])
+	assertField(t, model.Field{TypeName: "*Base", IsEmbedded: true}, fields[2])
+	assertField(t, model.Field{Name: "Name", TypeName: "string"}, fields[3])
+	for idx, embedded := range []bool{true, true, true, false} {
+		assert.Equal(t, embedded, fields[idx].IsEmbedded)
+	}
+
+	assert.Len(t, parsedSources.Structs[1].EmbeddedInterfaces(), 2)
+}
@@ -0,0 +1,109 @@
+package parser
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const benchmarkFileCount = 200
+
+// writeLargePackage writes a package of many files with annotated structs, enums and operations
+func writeLargePackage(tb testing.TB) string {
+	dir := tb.TempDir()
+	for idx := 0; idx < benchmarkFileCount; idx++ {
+		src := fmt.Sprintf(`package large
+
+// @Event( aggregate = "Tour" )
+type Event%[1]d struct {
+	UID   string
+	Names []string
+	Stops map[string]int
+}
+
+type Status%[1]d int
+
+const (
+	Started%[1]d Status%[1]d = iota
+	Stopped%[1]d
+)
+
+// @RestOperation( method = "GET", path = "/event%[1]d/{uid}" )
+func (e *Event%[1]d) Get(uid string) (*Event%[1]d, error) {
+	return e, nil
+}
+`, idx)
+		err := ioutil.WriteFile(filepath.Join(dir, fmt.Sprintf("event%03d.go", idx)), []byte(src), 0644)
+		assert.NoError(tb, err)
+	}
+	return dir
+}
+
+func TestSortedFileEntriesReferToParsedFiles(t *testing.T) {
+	fileSet := token.NewFileSet()
+	packages, err := parser.ParseDir(fileSet, writeLargePackage(t), nil, parser.ParseComments)
+	assert.NoError(t, err)
+	files := packages["large"].Files
+
+	entries := sortedFileEntries(files)
+	assert.Len(t, entries, benchmarkFileCount)
+	for idx, entry := range entries {
+		assert.True(t, files[entry.key] == entry.file)
+		if idx > 0 {
+			assert.True(t, entries[idx-1].key < entry.key)
+		}
+	}
+}
+
+func BenchmarkSortedFileEntries(b *testing.B) {
+	fileSet := token.NewFileSet()
+	packages, err := parser.ParseDir(fileSet, writeLargePackage(b), nil, parser.ParseComments)
+	assert.NoError(b, err)
+	files := packages["large"].Files
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		sortedFileEntries(files)
+	}
+}
+
+func BenchmarkParsePackage(b *testing.B) {
+	fileSet := token.NewFileSet()
+	packages, err := parser.ParseDir(fileSet, writeLargePackage(b), nil, parser.ParseComments)
+	assert.NoError(b, err)
+	aPackage := packages["large"]
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		parsePackage(aPackage, &astVisitor{FileSet: fileSet, Imports: map[string]string{}})
+	}
+}
+
+func BenchmarkParseSourceDir(b *testing.B) {
+	dir := writeLargePackage(b)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		_, err := New().ParseSourceDir(dir, "^.*.go$", "^$")
+		assert.NoError(b, err)
+	}
+}
+
+func TestTimerReceivesPhases(t *testing.T) {
+	phases := []string{}
+	p := NewWithOptions(Options{Timer: func(phase string, duration time.Duration) {
+		phases = append(phases, phase)
+	}})
+	_, err := p.ParseSourceDir("structs", "^.*.go$", "^$")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"parse", "visit"}, phases)
+}
@@ -21,8 +21,7 @@ func main() {
 		os.Exit(1)
 	}
 
-	jsonAstGenerator := ast.NewGenerator(outputFile)
-	err = jsonAstGenerator.Generate(inputDir, parsedSources)
+	err = generator.Generate(ast.NewGenerator(outputFile), parsedSources, generator.Config{InputDir: inputDir})
 	if err != nil {
 		log.Printf("Error generating json-ast: %s", err)
 		os.Exit(-1)
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+var (
+	cpuProfile  *string
+	memProfile  *string
+	traceFile   *string
+	showTimings *bool
+
+	// profileFiles are the open cpu-profile and trace, which are completed on exit
+	profileFiles []*os.File
+
+	timings = newPhaseTimings()
+)
+
+// phaseTimings adds up how long the phases of a run took, like parsing and rendering per generator: phases of
+// input-dirs that run concurrently add up to more than the duration of the run
+type phaseTimings struct {
+	mutex     sync.Mutex
+	phases    []string
+	durations map[string]time.Duration
+	counts    map[string]int
+}
+
+func newPhaseTimings() *phaseTimings {
+	return &phaseTimings{durations: map[string]time.Duration{}, counts: map[string]int{}}
+}
+
+func (t *phaseTimings) add(phase string, duration time.Duration) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if _, found := t.durations[phase]; !found {
+		t.phases = append(t.phases, phase)
+	}
+	t.durations[phase] += duration
+	t.counts[phase]++
+}
+
+// since adds the duration since start to phase
+func (t *phaseTimings) since(phase string, start time.Time) {
+	t.add(phase, time.Since(start))
+}
+
+// print writes the phases in the order they were first timed, followed by the duration of the whole run
+func (t *phaseTimings) print(w io.Writer, total time.Duration) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "Phase\tCount\tDuration\n")
+	for _, phase := range t.phases {
+		fmt.Fprintf(tw, "%s\t%d\t%s\n", phase, t.counts[phase], t.durations[phase].Round(time.Millisecond))
+	}
+	fmt.Fprintf(tw, "total\t\t%s\n", total.Round(time.Millisecond))
+	tw.Flush()
+}
+
+// startProfiling starts the cpu-profile and the execution-trace that the flags ask for
+func startProfiling() error {
+	if *cpuProfile != "" {
+		f, err := os.Create(*cpuProfile)
+		if err != nil {
+			return fmt.Errorf("Error creating cpu-profile:%w", err)
+		}
+		profileFiles = append(profileFiles, f)
+		err = pprof.StartCPUProfile(f)
+		if err != nil {
+			return fmt.Errorf("Error starting cpu-profile:%w", err)
+		}
+	}
+	if *traceFile != "" {
+		f, err := os.Create(*traceFile)
+		if err != nil {
+			return fmt.Errorf("Error creating trace:%w", err)
+		}
+		profileFiles = append(profileFiles, f)
+		err = trace.Start(f)
+		if err != nil {
+			return fmt.Errorf("Error starting trace:%w", err)
+		}
+	}
+	return nil
+}
+
+// stopProfiling completes the cpu-profile and the trace and writes the memory-profile
+func stopProfiling() error {
+	if cpuProfile == nil {
+		// exiting before the flags were defined
+		return nil
+	}
+	if *cpuProfile != "" {
+		pprof.StopCPUProfile()
+	}
+	if *traceFile != "" {
+		trace.Stop()
+	}
+	for _, f := range profileFiles {
+		err := f.Close()
+		if err != nil {
+			return fmt.Errorf("Error closing %s:%w", f.Name(), err)
+		}
+	}
+	profileFiles = nil
+	if *memProfile != "" {
+		f, err := os.Create(*memProfile)
+		if err != nil {
+			return fmt.Errorf("Error creating memory-profile:%w", err)
+		}
+		defer f.Close()
+		// up-to-date statistics of what is still in use
+		runtime.GC()
+		err = pprof.WriteHeapProfile(f)
+		if err != nil {
+			return fmt.Errorf("Error writing memory-profile:%w", err)
+		}
+	}
+	return nil
+}
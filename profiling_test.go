@@ -0,0 +1,24 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPhaseTimings(t *testing.T) {
+	timings := newPhaseTimings()
+	timings.add("parse", 2*time.Millisecond)
+	timings.add("render rest", 10*time.Millisecond)
+	timings.add("parse", 3*time.Millisecond)
+
+	var buf bytes.Buffer
+	timings.print(&buf, 20*time.Millisecond)
+	assert.Equal(t, `Phase        Count  Duration
+parse        2      5ms
+render rest  1      10ms
+total               20ms
+`, buf.String())
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"gopkg.in/yaml.v3"
+)
+
+const projectConfigFilename = "golangannotations.yaml"
+
+// projectConfig is the content of golangannotations.yaml: the paths in it are relative to the directory of the file
+type projectConfig struct {
+	// Input lists the directories to generate for when running from the directory of the config,
+	// where "dir/..." also includes all directories below dir
+	Input []string `yaml:"input"`
+	// Generators are the only generators to run, except the ones in Skip
+	Generators       []string          `yaml:"generators"`
+	Skip             []string          `yaml:"skip"`
+	Plugins          []string          `yaml:"plugins"`
+	TemplateDir      string            `yaml:"template-dir"`
+	OutputDir        string            `yaml:"output-dir"`
+	OutputSubdirs    map[string]string `yaml:"output-subdirs"`
+	FilenamePatterns map[string]string `yaml:"filename-patterns"`
+	BuildTags        map[string]string `yaml:"build-tags"`
+	Header           struct {
+		LicenseFile string `yaml:"license-file"`
+		Marker      string `yaml:"marker"`
+		Version     *bool  `yaml:"version"`
+		Source      bool   `yaml:"source"`
+	} `yaml:"header"`
+	// Profiles are named variants of the config, selected with -profile: their settings replace those of the config
+	Profiles map[string]projectConfig `yaml:"profiles"`
+
+	dir string
+}
+
+// findProjectConfig looks for golangannotations.yaml in dir and its parents and returns nil when there is none
+func findProjectConfig(dir string) (*projectConfig, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		filename := filepath.Join(dir, projectConfigFilename)
+		if _, err := os.Stat(filename); err == nil {
+			return loadProjectConfig(filename)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+func loadProjectConfig(filename string) (*projectConfig, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading config %s:%w", filename, err)
+	}
+	config := &projectConfig{}
+	decoder := yaml.NewDecoder(strings.NewReader(string(data)))
+	decoder.KnownFields(true)
+	err = decoder.Decode(config)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing config %s:%w", filename, err)
+	}
+	config.dir, err = filepath.Abs(filepath.Dir(filename))
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// withProfile returns the config with the settings of the named profile applied
+func (config projectConfig) withProfile(name string) (projectConfig, error) {
+	if name == "" {
+		return config, nil
+	}
+	profile, found := config.Profiles[name]
+	if !found {
+		return config, fmt.Errorf("Unknown profile %s in %s", name, filepath.Join(config.dir, projectConfigFilename))
+	}
+	if len(profile.Input) > 0 {
+		config.Input = profile.Input
+	}
+	if len(profile.Generators) > 0 {
+		config.Generators = profile.Generators
+	}
+	if len(profile.Skip) > 0 {
+		config.Skip = profile.Skip
+	}
+	if len(profile.Plugins) > 0 {
+		config.Plugins = profile.Plugins
+	}
+	if profile.TemplateDir != "" {
+		config.TemplateDir = profile.TemplateDir
+	}
+	if profile.OutputDir != "" {
+		config.OutputDir = profile.OutputDir
+	}
+	config.OutputSubdirs = mergeSettings(config.OutputSubdirs, profile.OutputSubdirs)
+	config.FilenamePatterns = mergeSettings(config.FilenamePatterns, profile.FilenamePatterns)
+	config.BuildTags = mergeSettings(config.BuildTags, profile.BuildTags)
+	if profile.Header.LicenseFile != "" {
+		config.Header.LicenseFile = profile.Header.LicenseFile
+	}
+	if profile.Header.Marker != "" {
+		config.Header.Marker = profile.Header.Marker
+	}
+	if profile.Header.Version != nil {
+		config.Header.Version = profile.Header.Version
+	}
+	config.Header.Source = config.Header.Source || profile.Header.Source
+	return config, nil
+}
+
+func mergeSettings(settings map[string]string, overrides map[string]string) map[string]string {
+	merged := map[string]string{}
+	for name, value := range settings {
+		merged[name] = value
+	}
+	for name, value := range overrides {
+		merged[name] = value
+	}
+	return merged
+}
+
+// path makes a path of the config usable from the working dir
+func (config projectConfig) path(p string) string {
+	if p == "" || filepath.IsAbs(p) {
+		return p
+	}
+	return relativeToWorkDir(filepath.Join(config.dir, p))
+}
+
+// inputDirs returns the directories to generate for when running from workDir: all inputs of the config when
+// running from the directory of the config, otherwise workDir itself when it is one of the inputs
+func (config projectConfig) inputDirs(workDir string) ([]string, error) {
+	workDir, err := filepath.Abs(workDir)
+	if err != nil {
+		return nil, err
+	}
+	if len(config.Input) == 0 {
+		return []string{"."}, nil
+	}
+	dirs := []string{}
+	for _, pattern := range config.Input {
+		matched, err := expandInputPattern(filepath.Join(config.dir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		dirs = append(dirs, matched...)
+	}
+	if workDir == config.dir {
+		for idx, dir := range dirs {
+			dirs[idx] = relativeToWorkDir(dir)
+		}
+		return dirs, nil
+	}
+	for _, dir := range dirs {
+		if dir == workDir {
+			return []string{"."}, nil
+		}
+	}
+	return nil, fmt.Errorf("Directory %s is not an input of %s", workDir, filepath.Join(config.dir, projectConfigFilename))
+}
+
+// expandInputPattern returns dir itself, or for "dir/..." all directories below dir that contain go-sources
+func expandInputPattern(pattern string) ([]string, error) {
+	if filepath.Base(pattern) != "..." {
+		return []string{filepath.Clean(pattern)}, nil
+	}
+	root := filepath.Dir(pattern)
+	dirs := map[string]bool{}
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			name := info.Name()
+			if path != root && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "testdata" || name == "vendor") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasSuffix(info.Name(), ".go") && !strings.HasPrefix(info.Name(), generator.GenfilePrefix) {
+			dirs[filepath.Dir(path)] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Error expanding input %s:%w", pattern, err)
+	}
+	sorted := []string{}
+	for dir := range dirs {
+		sorted = append(sorted, dir)
+	}
+	sort.Strings(sorted)
+	return sorted, nil
+}
+
+func relativeToWorkDir(path string) string {
+	workDir, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	relative, err := filepath.Rel(workDir, path)
+	if err != nil {
+		return path
+	}
+	return relative
+}
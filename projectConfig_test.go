@@ -0,0 +1,101 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeProjectFile(t *testing.T, filename string, content string) {
+	assert.NoError(t, os.MkdirAll(filepath.Dir(filename), 0777))
+	assert.NoError(t, ioutil.WriteFile(filename, []byte(content), 0644))
+}
+
+func TestLoadProjectConfigWithProfile(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, projectConfigFilename)
+	writeProjectFile(t, filename, `
+generators: [rest, event]
+output-subdirs:
+  docs: documentation
+header:
+  marker: "Code generated by golangAnnotations. DO NOT EDIT."
+profiles:
+  docs:
+    generators: [docs]
+    output-subdirs:
+      rest: http
+    build-tags:
+      docs: "!appengine"
+    header:
+      version: false
+`)
+	config, err := loadProjectConfig(filename)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"rest", "event"}, config.Generators)
+
+	selected, err := config.withProfile("docs")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"docs"}, selected.Generators)
+	assert.Equal(t, map[string]string{"docs": "documentation", "rest": "http"}, selected.OutputSubdirs)
+	assert.Equal(t, map[string]string{"docs": "!appengine"}, selected.BuildTags)
+	assert.Equal(t, "Code generated by golangAnnotations. DO NOT EDIT.", selected.Header.Marker)
+	assert.Nil(t, config.Header.Version)
+	assert.False(t, *selected.Header.Version)
+
+	_, err = config.withProfile("unknown")
+	assert.Error(t, err)
+}
+
+func TestLoadProjectConfigWithUnknownSetting(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), projectConfigFilename)
+	writeProjectFile(t, filename, "generator: [rest]\n")
+	_, err := loadProjectConfig(filename)
+	assert.Error(t, err)
+}
+
+func TestFindProjectConfigInParent(t *testing.T) {
+	dir := t.TempDir()
+	writeProjectFile(t, filepath.Join(dir, projectConfigFilename), "generators: [rest]\n")
+	sub := filepath.Join(dir, "a", "b")
+	assert.NoError(t, os.MkdirAll(sub, 0777))
+
+	config, err := findProjectConfig(sub)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"rest"}, config.Generators)
+}
+
+func TestInputDirs(t *testing.T) {
+	dir := t.TempDir()
+	writeProjectFile(t, filepath.Join(dir, projectConfigFilename), "input: [\"tools\", \"services/...\"]\n")
+	writeProjectFile(t, filepath.Join(dir, "tools", "tool.go"), "package tools\n")
+	writeProjectFile(t, filepath.Join(dir, "services", "a", "a.go"), "package a\n")
+	writeProjectFile(t, filepath.Join(dir, "services", "a", "testdata", "fixture.go"), "package fixture\n")
+	writeProjectFile(t, filepath.Join(dir, "services", "b", "gen_b.go"), "package b\n")
+	writeProjectFile(t, filepath.Join(dir, "services", "c", "d", "d.go"), "package d\n")
+	config, err := findProjectConfig(dir)
+	assert.NoError(t, err)
+
+	dirs, err := config.inputDirs(dir)
+	assert.NoError(t, err)
+	for idx, inputDir := range dirs {
+		dirs[idx], _ = filepath.Rel(dir, absolute(t, inputDir))
+	}
+	assert.Equal(t, []string{"tools", filepath.Join("services", "a"), filepath.Join("services", "c", "d")}, dirs)
+
+	dirs, err = config.inputDirs(filepath.Join(dir, "services", "a"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"."}, dirs)
+
+	_, err = config.inputDirs(filepath.Join(dir, "services", "b"))
+	assert.Error(t, err)
+}
+
+func absolute(t *testing.T, path string) string {
+	abs, err := filepath.Abs(path)
+	assert.NoError(t, err)
+	return abs
+}
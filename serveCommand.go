@@ -0,0 +1,380 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/MarcGrol/golangAnnotations/lint"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+// snapshot is the outcome of parsing and rendering the input-dirs once: it is replaced as a whole when a source
+// changes, so that every request sees a consistent model
+type snapshot struct {
+	version   int
+	parsed    []model.ParsedSources
+	files     map[string]generator.OutputFile
+	report    *generator.Report
+	createdAt time.Time
+}
+
+// introspectionServer exposes the latest snapshot of the input-dirs over http
+type introspectionServer struct {
+	inputDirs []string
+	build     func(c context.Context) *snapshot
+
+	mutex       sync.RWMutex
+	current     *snapshot
+	fingerprint string
+}
+
+// runServe parses and renders the input-dirs and serves the outcome until interrupted, rebuilding it whenever a
+// source changes
+func runServe() {
+	inputDirs, registry := prepare()
+
+	_, _, err := newTasks(registry, nil, nil)
+	if err != nil {
+		fail(exitUsage, "%s", err)
+	}
+
+	c, stop := interruptibleContext()
+	defer stop()
+
+	server := newIntrospectionServer(inputDirs, func(c context.Context) *snapshot {
+		return buildSnapshot(c, registry, inputDirs)
+	})
+	server.refresh(c)
+	go server.watch(c, *watchInterval)
+
+	httpServer := &http.Server{Addr: *listen, Handler: server.handler()}
+	go func() {
+		<-c.Done()
+		httpServer.Shutdown(context.Background())
+	}()
+	logger.Infof("golangAnnotations: serving %d input-dirs on http://%s", len(inputDirs), *listen)
+	err = httpServer.ListenAndServe()
+	if err != nil && err != http.ErrServerClosed {
+		fail(exitFailure, "Error serving on %s: %s", *listen, err)
+	}
+}
+
+func newIntrospectionServer(inputDirs []string, build func(c context.Context) *snapshot) *introspectionServer {
+	return &introspectionServer{inputDirs: inputDirs, build: build, current: &snapshot{}}
+}
+
+// buildSnapshot parses and renders the input-dirs without writing anything: errors end up in the report of the
+// snapshot instead of stopping the server
+func buildSnapshot(c context.Context, registry *generator.Registry, inputDirs []string) *snapshot {
+	start := time.Now()
+	result := &snapshot{files: map[string]generator.OutputFile{}, report: generator.NewReport(), createdAt: start}
+	defer result.report.Finish(start)
+
+	parsedSources, err := parseSources(c, inputDirs)
+	if err != nil {
+		result.report.AddDiagnostic(generator.NewDiagnostic(generator.SeverityError, err))
+		return result
+	}
+	result.parsed = parsedSources
+	for _, parsed := range parsedSources {
+		errs := lint.UnknownAnnotations(parsed, lint.KnownAnnotationNames(registry))
+		errs = append(errs, lint.MalformedAnnotations(parsed, registry)...)
+		for _, err := range append(errs, lint.UnresolvedReceivers(parsed)...) {
+			result.report.AddDiagnostic(generator.NewDiagnostic(generator.SeverityWarning, err))
+		}
+	}
+
+	_, tasks, err := newTasks(registry, inputDirs, parsedSources)
+	if err != nil {
+		result.report.AddDiagnostic(generator.NewDiagnostic(generator.SeverityError, err))
+		return result
+	}
+	results, _ := generator.RenderAll(c, tasks, *jobs)
+	for idx, taskResult := range results {
+		result.report.AddTask(tasks[idx], taskResult)
+		for _, f := range taskResult.Files {
+			status := generator.FileStale
+			if upToDate, err := generator.IsUpToDate(f); err == nil && upToDate {
+				status = generator.FileUnchanged
+			}
+			result.report.AddFile(tasks[idx].Generator.Name(), f, status)
+			result.files[fileKey(f.Filename)] = f
+		}
+	}
+	err = generator.DetectCollisions(tasks, results)
+	if errs, ok := err.(generator.Errors); ok {
+		for _, err := range errs {
+			result.report.AddDiagnostic(generator.NewDiagnostic(generator.SeverityError, err))
+		}
+	}
+	return result
+}
+
+// refresh rebuilds the snapshot when the sources changed since the last build
+func (s *introspectionServer) refresh(c context.Context) bool {
+	fingerprint := sourcesFingerprint(s.inputDirs)
+	s.mutex.RLock()
+	unchanged := s.current.version > 0 && fingerprint == s.fingerprint
+	s.mutex.RUnlock()
+	if unchanged {
+		return false
+	}
+
+	next := s.build(c)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	next.version = s.current.version + 1
+	s.current = next
+	s.fingerprint = fingerprint
+	logger.Verbosef("golangAnnotations: model version %d with %d diagnostics", next.version, len(next.report.Diagnostics))
+	return true
+}
+
+func (s *introspectionServer) watch(c context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.Done():
+			return
+		case <-ticker.C:
+			s.refresh(c)
+		}
+	}
+}
+
+func (s *introspectionServer) snapshot() *snapshot {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.current
+}
+
+// sourcesFingerprint identifies the state of the go-sources of the input-dirs by their names, sizes and
+// modification times. Generated files are left out, so that generating does not trigger a rebuild.
+func sourcesFingerprint(inputDirs []string) string {
+	entries := []string{}
+	for _, dir := range inputDirs {
+		infos, err := ioutil.ReadDir(dir)
+		if err != nil {
+			entries = append(entries, dir+":"+err.Error())
+			continue
+		}
+		for _, info := range infos {
+			name := info.Name()
+			if info.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasPrefix(name, generator.GenfilePrefix) {
+				continue
+			}
+			entries = append(entries, fmt.Sprintf("%s:%d:%d", filepath.Join(dir, name), info.Size(), info.ModTime().UnixNano()))
+		}
+	}
+	return strings.Join(entries, "\n")
+}
+
+func (s *introspectionServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.serveIndex)
+	mux.HandleFunc("/model", s.serveModel)
+	mux.HandleFunc("/list", s.serveList)
+	mux.HandleFunc("/graph", s.serveGraph)
+	mux.HandleFunc("/report", s.serveReport)
+	mux.HandleFunc("/files/", s.serveFile)
+	mux.HandleFunc("/events", s.serveEvents)
+	return mux
+}
+
+// serveModel writes the model as json, or as yaml with ?format=yaml. The query-parameters package and annotation
+// filter the model like the -filter flag of parse.
+func (s *introspectionServer) serveModel(w http.ResponseWriter, r *http.Request) {
+	filters := modelFilters{}
+	for _, kind := range []string{packageFilter, annotationFilter} {
+		for _, value := range r.URL.Query()[kind] {
+			err := filters.Set(kind + "=" + value)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+	}
+	format := queryValue(r, "format", "json")
+	data, err := marshalModel(filters.apply(mergeParsed(s.snapshot().parsed)), format)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", map[string]string{"json": "application/json", "yaml": "application/yaml"}[format])
+	w.Write(data)
+}
+
+func (s *introspectionServer) serveList(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, listAnnotated(s.snapshot().parsed))
+}
+
+// serveGraph writes the event-graph as dot, or as mermaid with ?format=mermaid
+func (s *introspectionServer) serveGraph(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	err := buildEventGraph(s.snapshot().parsed).write(w, queryValue(r, "format", "dot"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	}
+}
+
+// serveReport writes the report of the last build: the diagnostics of the annotations and the files the
+// generators render, with whether the files on disk are up to date
+func (s *introspectionServer) serveReport(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, s.snapshot().report)
+}
+
+// serveFile writes a file as the generators render it, like the documentation of the rest-services
+func (s *introspectionServer) serveFile(w http.ResponseWriter, r *http.Request) {
+	f, found := s.snapshot().files[strings.TrimPrefix(r.URL.Path, "/files/")]
+	if !found {
+		http.NotFound(w, r)
+		return
+	}
+	contentType := "text/plain; charset=utf-8"
+	if strings.HasSuffix(f.Filename, ".json") {
+		contentType = "application/json"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Write(f.Content)
+}
+
+// serveEvents streams a reload-event whenever the snapshot is rebuilt, so that browsers can refresh the page
+func (s *introspectionServer) serveEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	version := s.snapshot().version
+	fmt.Fprintf(w, "retry: 1000\n\n")
+	flusher.Flush()
+
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			if current := s.snapshot().version; current != version {
+				version = current
+				fmt.Fprintf(w, "event: reload\ndata: %d\n\n", version)
+				flusher.Flush()
+			}
+		}
+	}
+}
+
+func (s *introspectionServer) serveIndex(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	current := s.snapshot()
+	files := []generator.ReportFile{}
+	if current.report != nil {
+		files = append(files, current.report.Files...)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Filename < files[j].Filename
+	})
+	for idx := range files {
+		files[idx].Filename = fileKey(files[idx].Filename)
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	err := indexTemplate.Execute(w, map[string]interface{}{
+		"Version":   current.version,
+		"CreatedAt": current.createdAt.Format(time.RFC3339),
+		"InputDirs": s.inputDirs,
+		"Items":     listAnnotated(current.parsed),
+		"Report":    current.report,
+		"Files":     files,
+	})
+	if err != nil {
+		logger.Errorf("Error rendering index: %s", err)
+	}
+}
+
+// fileKey is the path of a generated file below /files/
+func fileKey(filename string) string {
+	return strings.TrimPrefix(filepath.ToSlash(filepath.Clean(filename)), "/")
+}
+
+func queryValue(r *http.Request, name string, defaultValue string) string {
+	if value := r.URL.Query().Get(name); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func writeJSON(w http.ResponseWriter, value interface{}) {
+	data, err := json.MarshalIndent(value, "", "\t")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(append(data, '\n'))
+}
+
+var indexTemplate = template.Must(template.New("index").Funcs(template.FuncMap{
+	"attributes": formatAttributes,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>golangAnnotations</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+td, th { text-align: left; padding: 0.2em 1em 0.2em 0; vertical-align: top; }
+.error { color: #b00020; }
+.warning { color: #a05a00; }
+</style>
+</head>
+<body>
+<h1>golangAnnotations</h1>
+<p>Model version {{.Version}} of {{.CreatedAt}} for {{range $idx, $dir := .InputDirs}}{{if $idx}}, {{end}}{{$dir}}{{end}}</p>
+<p>
+<a href="/model">model (json)</a> | <a href="/model?format=yaml">model (yaml)</a> | <a href="/list">annotated elements</a> |
+<a href="/report">report</a> | <a href="/graph">event-graph (dot)</a> | <a href="/graph?format=mermaid">event-graph (mermaid)</a>
+</p>
+{{with .Report}}
+<h2>Diagnostics</h2>
+{{if .Diagnostics}}
+<table>
+{{range .Diagnostics}}<tr class="{{.Severity}}"><td>{{.Severity}}</td><td>{{if .Filename}}{{.Filename}}:{{.Line}}{{end}}</td><td>{{.Message}}</td></tr>
+{{end}}</table>
+{{else}}
+<p>No errors or warnings in the annotations.</p>
+{{end}}
+{{end}}
+<h2>Annotated elements</h2>
+<table>
+<tr><th>Kind</th><th>Name</th><th>Package</th><th>Attributes</th></tr>
+{{range .Items}}<tr><td>{{.Kind}}</td><td>{{.Name}}</td><td>{{.Package}}</td><td>{{attributes .Attributes}}</td></tr>
+{{end}}</table>
+<h2>Generated files</h2>
+<table>
+<tr><th>File</th><th>Generator</th><th>Status</th></tr>
+{{range .Files}}<tr><td><a href="/files/{{.Filename}}">{{.Filename}}</a></td><td>{{.Generator}}</td><td>{{.Status}}</td></tr>
+{{end}}</table>
+<script>
+new EventSource("/events").addEventListener("reload", function() { location.reload(); });
+</script>
+</body>
+</html>
+`))
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/MarcGrol/golangAnnotations/generator"
+	"github.com/stretchr/testify/assert"
+)
+
+func testServer(dir string) (*introspectionServer, *int) {
+	builds := 0
+	server := newIntrospectionServer([]string{dir}, func(c context.Context) *snapshot {
+		builds++
+		report := generator.NewReport()
+		docs := generator.OutputFile{Filename: "docs/gen_apiTourService.md", Content: []byte("# TourService\n")}
+		report.AddFile("docs", docs, generator.FileStale)
+		report.AddDiagnostic(generator.Diagnostic{Severity: generator.SeverityWarning, Filename: "tour.go", Line: 3, Message: "Unknown annotation @Rest"})
+		return &snapshot{
+			parsed:    graphSources(),
+			files:     map[string]generator.OutputFile{fileKey(docs.Filename): docs},
+			report:    report,
+			createdAt: time.Now(),
+		}
+	})
+	return server, &builds
+}
+
+func get(t *testing.T, server *introspectionServer, url string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	server.handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, url, nil))
+	return recorder
+}
+
+func TestServeRefreshesOnlyWhenSourcesChange(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "tour.go")
+	assert.NoError(t, ioutil.WriteFile(filename, []byte("package tour\n"), 0644))
+	server, builds := testServer(dir)
+
+	assert.True(t, server.refresh(context.Background()))
+	assert.False(t, server.refresh(context.Background()))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, generator.GenfilePrefix+"tour.go"), []byte("package tour\n"), 0644))
+	assert.False(t, server.refresh(context.Background()))
+	assert.NoError(t, ioutil.WriteFile(filename, []byte("package tour\n\ntype Tour struct{}\n"), 0644))
+	assert.True(t, server.refresh(context.Background()))
+
+	assert.Equal(t, 2, *builds)
+	assert.Equal(t, 2, server.snapshot().version)
+}
+
+func TestServeModelListAndGraph(t *testing.T) {
+	server, _ := testServer(t.TempDir())
+	server.refresh(context.Background())
+
+	response := get(t, server, "/model?format=yaml&package=tour&annotation=@EventService")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "application/yaml", response.Header().Get("Content-Type"))
+	assert.Contains(t, response.Body.String(), "name: Notifier")
+	assert.NotContains(t, response.Body.String(), "TourService")
+
+	assert.Equal(t, http.StatusBadRequest, get(t, server, "/model?format=xml").Code)
+
+	response = get(t, server, "/list")
+	assert.Equal(t, "application/json", response.Header().Get("Content-Type"))
+	assert.Contains(t, response.Body.String(), `"kind": "event-service"`)
+
+	response = get(t, server, "/graph?format=mermaid")
+	assert.Contains(t, response.Body.String(), "flowchart LR\n")
+	assert.Equal(t, http.StatusBadRequest, get(t, server, "/graph?format=svg").Code)
+}
+
+func TestServeReportAndFiles(t *testing.T) {
+	server, _ := testServer(t.TempDir())
+	server.refresh(context.Background())
+
+	response := get(t, server, "/report")
+	assert.Contains(t, response.Body.String(), `"message": "Unknown annotation @Rest"`)
+
+	response = get(t, server, "/files/docs/gen_apiTourService.md")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "# TourService\n", response.Body.String())
+	assert.Equal(t, http.StatusNotFound, get(t, server, "/files/gen_unknown.go").Code)
+
+	response = get(t, server, "/")
+	assert.Equal(t, "text/html; charset=utf-8", response.Header().Get("Content-Type"))
+	assert.Contains(t, response.Body.String(), "Model version 1 of")
+	assert.Contains(t, response.Body.String(), `<a href="/files/docs/gen_apiTourService.md">docs/gen_apiTourService.md</a>`)
+	assert.Contains(t, response.Body.String(), "tour.go:3")
+	assert.Contains(t, response.Body.String(), `new EventSource("/events")`)
+	assert.Equal(t, http.StatusNotFound, get(t, server, "/unknown").Code)
+}
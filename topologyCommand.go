@@ -0,0 +1,209 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/MarcGrol/golangAnnotations/generator/annotation"
+	"github.com/MarcGrol/golangAnnotations/generator/event/eventAnnotation"
+	"github.com/MarcGrol/golangAnnotations/generator/eventService"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+// Messaging systems that topology provisions
+const (
+	pubsubProvider = "pubsub"
+	kafkaProvider  = "kafka"
+)
+
+type topologyTopic struct {
+	Name       string   `json:"name"`
+	Aggregates []string `json:"aggregates,omitempty"`
+	Events     []string `json:"events,omitempty"`
+}
+
+type topologySubscription struct {
+	Name       string   `json:"name"`
+	Topic      string   `json:"topic"`
+	Subscriber string   `json:"subscriber"`
+	Events     []string `json:"events,omitempty"`
+}
+
+// messagingTopology holds the topics that events are published on and the subscriptions of the event-services to
+// them, as the infrastructure must provide them
+type messagingTopology struct {
+	Provider      string                 `json:"provider"`
+	Topics        []topologyTopic        `json:"topics"`
+	Subscriptions []topologySubscription `json:"subscriptions"`
+}
+
+// runTopology prints the messaging-topology of all input-dirs
+func runTopology() {
+	inputDirs, err := applyProjectConfig()
+	if err != nil {
+		fail(exitUsage, "Error in config: %s", err)
+	}
+
+	c, stop := interruptibleContext()
+	defer stop()
+
+	topology := buildTopology(parseInputDirs(c, inputDirs))
+	topology.Provider = *topologyProvider
+	err = topology.write(os.Stdout, *topologyFormat)
+	if err != nil {
+		fail(exitUsage, "Error writing topology: %s", err)
+	}
+}
+
+// buildTopology derives the topics from the aggregates of the @Event-annotations and the subscriptions from the
+// @EventService- and @EventOperation-annotations: every event-service subscribes once to every topic it handles
+func buildTopology(parsedSources []model.ParsedSources) *messagingTopology {
+	eventAnnotations := annotation.NewRegistry(eventAnnotation.Get())
+
+	topics := map[string]*topologyTopic{}
+	topic := func(name string) *topologyTopic {
+		if topics[name] == nil {
+			topics[name] = &topologyTopic{Name: name}
+		}
+		return topics[name]
+	}
+	subscriptions := map[string]*topologySubscription{}
+	for _, parsed := range parsedSources {
+		for _, s := range parsed.Structs {
+			if ann, ok := eventAnnotations.ResolveAnnotationByName(s.DocLines, eventAnnotation.TypeEvent); ok {
+				aggregate := ann.Attributes[eventAnnotation.ParamAggregate]
+				t := topic(aggregateTopic(aggregate))
+				t.Aggregates = appendUnique(t.Aggregates, aggregate)
+				t.Events = appendUnique(t.Events, s.Name)
+			}
+		}
+		for _, s := range parsed.Structs {
+			if !eventService.IsEventService(s) {
+				continue
+			}
+			subscriber := eventService.GetEventServiceSelfName(s)
+			if subscriber == "" {
+				subscriber = strings.ToLower(s.Name)
+			}
+			for _, o := range parsed.Operations {
+				if o.RelatedStruct == nil || strings.TrimPrefix(o.RelatedStruct.TypeName, "*") != s.Name || !eventService.IsEventOperation(o) {
+					continue
+				}
+				name := eventService.GetEventOperationTopic(o)
+				topic(name)
+				id := subscriber + "-" + name
+				if subscriptions[id] == nil {
+					subscriptions[id] = &topologySubscription{Name: id, Topic: name, Subscriber: subscriber}
+				}
+				if eventType := eventService.GetInputArgType(o); eventType != "" {
+					subscriptions[id].Events = appendUnique(subscriptions[id].Events, eventType)
+				}
+			}
+		}
+	}
+
+	topology := &messagingTopology{Topics: []topologyTopic{}, Subscriptions: []topologySubscription{}}
+	for _, t := range topics {
+		sort.Strings(t.Events)
+		topology.Topics = append(topology.Topics, *t)
+	}
+	sort.Slice(topology.Topics, func(i, j int) bool { return topology.Topics[i].Name < topology.Topics[j].Name })
+	for _, s := range subscriptions {
+		sort.Strings(s.Events)
+		topology.Subscriptions = append(topology.Subscriptions, *s)
+	}
+	sort.Slice(topology.Subscriptions, func(i, j int) bool { return topology.Subscriptions[i].Name < topology.Subscriptions[j].Name })
+	return topology
+}
+
+// aggregateTopic returns the topic that the events of an aggregate are published on, like "tour" for Tour
+func aggregateTopic(aggregate string) string {
+	return strings.ToLower(aggregate)
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+func (t *messagingTopology) write(w io.Writer, format string) error {
+	if t.Provider != pubsubProvider && t.Provider != kafkaProvider {
+		return fmt.Errorf("Unknown provider %s: expected %s or %s", t.Provider, pubsubProvider, kafkaProvider)
+	}
+	switch format {
+	case "terraform":
+		if t.Provider == kafkaProvider {
+			t.writeKafkaTerraform(w)
+		} else {
+			t.writePubSubTerraform(w)
+		}
+		return nil
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(t)
+	}
+	return fmt.Errorf("Unknown format %s: expected terraform or json", format)
+}
+
+// writePubSubTerraform writes a google_pubsub_topic per topic and a google_pubsub_subscription per subscription
+func (t *messagingTopology) writePubSubTerraform(w io.Writer) {
+	fmt.Fprintf(w, "# Generated automatically by golangAnnotations: do not edit manually\n")
+	for _, topic := range t.Topics {
+		fmt.Fprintf(w, "\nresource \"google_pubsub_topic\" %q {\n", terraformName(topic.Name))
+		fmt.Fprintf(w, "  name = %q\n", topic.Name)
+		fmt.Fprintf(w, "}\n")
+	}
+	for _, s := range t.Subscriptions {
+		fmt.Fprintf(w, "\nresource \"google_pubsub_subscription\" %q {\n", terraformName(s.Name))
+		fmt.Fprintf(w, "  name  = %q\n", s.Name)
+		fmt.Fprintf(w, "  topic = google_pubsub_topic.%s.name\n", terraformName(s.Topic))
+		fmt.Fprintf(w, "}\n")
+	}
+}
+
+// writeKafkaTerraform writes a kafka_topic per topic: kafka has no subscriptions, the subscribers are consumer
+// groups, which are listed per topic for the acls that allow them to read
+func (t *messagingTopology) writeKafkaTerraform(w io.Writer) {
+	fmt.Fprintf(w, "# Generated automatically by golangAnnotations: do not edit manually\n")
+	fmt.Fprintf(w, "\nvariable \"kafka_partitions\" {\n  default = 1\n}\n")
+	fmt.Fprintf(w, "\nvariable \"kafka_replication_factor\" {\n  default = 1\n}\n")
+	for _, topic := range t.Topics {
+		fmt.Fprintf(w, "\nresource \"kafka_topic\" %q {\n", terraformName(topic.Name))
+		fmt.Fprintf(w, "  name               = %q\n", topic.Name)
+		fmt.Fprintf(w, "  partitions         = var.kafka_partitions\n")
+		fmt.Fprintf(w, "  replication_factor = var.kafka_replication_factor\n")
+		fmt.Fprintf(w, "}\n")
+	}
+	fmt.Fprintf(w, "\nlocals {\n  consumer_groups = {\n")
+	for _, topic := range t.Topics {
+		groups := []string{}
+		for _, s := range t.Subscriptions {
+			if s.Topic == topic.Name {
+				groups = append(groups, fmt.Sprintf("%q", s.Subscriber))
+			}
+		}
+		fmt.Fprintf(w, "    %s = [%s]\n", terraformName(topic.Name), strings.Join(groups, ", "))
+	}
+	fmt.Fprintf(w, "  }\n}\n")
+}
+
+var invalidTerraformChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// terraformName returns the name of a resource in terraform for a topic or subscription, like "notifier_tour"
+func terraformName(name string) string {
+	name = invalidTerraformChars.ReplaceAllString(name, "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+	return name
+}
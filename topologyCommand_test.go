@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopologyPubSubTerraform(t *testing.T) {
+	topology := buildTopology(graphSources())
+	topology.Provider = pubsubProvider
+
+	var buf bytes.Buffer
+	assert.NoError(t, topology.write(&buf, "terraform"))
+	assert.Equal(t, `# Generated automatically by golangAnnotations: do not edit manually
+
+resource "google_pubsub_topic" "tour" {
+  name = "tour"
+}
+
+resource "google_pubsub_subscription" "notifier_tour" {
+  name  = "notifier-tour"
+  topic = google_pubsub_topic.tour.name
+}
+`, buf.String())
+}
+
+func TestTopologyKafkaTerraform(t *testing.T) {
+	topology := buildTopology(graphSources())
+	topology.Provider = kafkaProvider
+
+	var buf bytes.Buffer
+	assert.NoError(t, topology.write(&buf, "terraform"))
+	assert.Contains(t, buf.String(), `resource "kafka_topic" "tour" {
+  name               = "tour"
+  partitions         = var.kafka_partitions
+  replication_factor = var.kafka_replication_factor
+}
+`)
+	assert.Contains(t, buf.String(), `  consumer_groups = {
+    tour = ["notifier"]
+  }
+`)
+}
+
+func TestTopologyJSON(t *testing.T) {
+	topology := buildTopology(graphSources())
+	topology.Provider = pubsubProvider
+
+	var buf bytes.Buffer
+	assert.NoError(t, topology.write(&buf, "json"))
+	assert.JSONEq(t, `{
+		"provider": "pubsub",
+		"topics": [{"name": "tour", "aggregates": ["Tour"], "events": ["TourCreated"]}],
+		"subscriptions": [{"name": "notifier-tour", "topic": "tour", "subscriber": "notifier", "events": ["TourCreated"]}]
+	}`, buf.String())
+}
+
+func TestTopologyUnknownFormatOrProvider(t *testing.T) {
+	topology := buildTopology(graphSources())
+	topology.Provider = pubsubProvider
+	assert.EqualError(t, topology.write(&bytes.Buffer{}, "yaml"), "Unknown format yaml: expected terraform or json")
+
+	topology.Provider = "sqs"
+	assert.EqualError(t, topology.write(&bytes.Buffer{}, "json"), "Unknown provider sqs: expected pubsub or kafka")
+}